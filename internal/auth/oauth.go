@@ -14,7 +14,7 @@ const (
 	authURL     = "https://www.strava.com/oauth/authorize"
 	tokenURL    = "https://www.strava.com/oauth/token"
 	redirectURI = "http://localhost:8089/callback"
-	scopes      = "activity:read_all"
+	scopes      = "activity:read_all,activity:write,profile:read_all"
 )
 
 // StravaOAuthConfig returns an OAuth2 config for Strava
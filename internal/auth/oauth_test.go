@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -111,4 +112,8 @@ func TestStravaOAuthConfig(t *testing.T) {
 	if config.RedirectURL != "http://localhost:8089/callback" {
 		t.Errorf("unexpected redirect URL: %q", config.RedirectURL)
 	}
+
+	if len(config.Scopes) != 1 || !strings.Contains(config.Scopes[0], "activity:write") {
+		t.Errorf("expected the activity:write scope, got %v", config.Scopes)
+	}
 }
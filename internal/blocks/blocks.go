@@ -0,0 +1,417 @@
+// Package blocks splits training history into base, build, peak, taper and
+// recovery phases. Each week is labelled from its moving time against the
+// trailing weeks and the share of it spent in hard sessions, then runs of the
+// same label become blocks. Moving time rather than distance carries the
+// load, so weeks mixing runs and rides still compare.
+package blocks
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/workouts"
+)
+
+// Training phases
+const (
+	PhaseBase     = "base"
+	PhaseBuild    = "build"
+	PhasePeak     = "peak"
+	PhaseTaper    = "taper"
+	PhaseRecovery = "recovery"
+)
+
+// Phases lists every phase in the order a season usually runs through them
+var Phases = []string{PhaseBase, PhaseBuild, PhasePeak, PhaseTaper, PhaseRecovery}
+
+// baselineWeeks is how many trailing weeks a week's volume is compared with
+const baselineWeeks = 4
+
+// peakWindow is how many weeks, including the week itself, a peak week must
+// be among the biggest of
+const peakWindow = 8
+
+// raceLookahead is how many weeks before a race a drop in volume counts as a
+// taper rather than recovery
+const raceLookahead = 2
+
+// Volume as a fraction of the trailing baseline. A taper cuts volume by 15%
+// or more; a cut of 30% without a race ahead is a recovery week; growing 8%
+// is building.
+const (
+	taperRatio    = 0.85
+	recoveryRatio = 0.70
+	buildRatio    = 1.08
+)
+
+// Share of moving time in tempo, interval and race sessions. Peak weeks are
+// close to the biggest and at least a fifth hard; a build week that isn't
+// growing is still building if an eighth of it is hard.
+const (
+	peakIntensity  = 0.20
+	peakVolume     = 0.90
+	buildIntensity = 0.12
+)
+
+// minEfficiencySessions is how many aerobic sessions each half of a block
+// needs before their efficiency is compared
+const minEfficiencySessions = 2
+
+// Session is one activity as it counts toward a block
+type Session struct {
+	ID        int64
+	Name      string
+	Type      string
+	Start     time.Time
+	Seconds   float64
+	Meters    float64
+	Speed     float64 // average m/s
+	Heartrate float64 // average bpm
+	Category  string  // workout category, empty when unclassified
+}
+
+// Hard reports whether the session was a tempo, interval or race effort
+func (s Session) Hard() bool {
+	switch s.Category {
+	case workouts.CategoryTempo, workouts.CategoryIntervals, workouts.CategoryRace:
+		return true
+	}
+	return false
+}
+
+// aerobic reports whether the session's heart rate says something about
+// aerobic fitness
+func (s Session) aerobic() bool {
+	switch s.Category {
+	case workouts.CategoryEasy, workouts.CategoryLong:
+		return s.Speed > 0 && s.Heartrate > 0
+	}
+	return false
+}
+
+// Week is a Monday-to-Sunday week of training
+type Week struct {
+	Start       time.Time
+	Days        int  // days of the week covered, fewer than 7 for the current week
+	InProgress  bool // the week hadn't ended when the history was read
+	Seconds     float64
+	Meters      float64
+	HardSeconds float64
+	Sessions    []Session
+}
+
+// Intensity is the share of the week's moving time in hard sessions
+func (w Week) Intensity() float64 {
+	if w.Seconds == 0 {
+		return 0
+	}
+	return w.HardSeconds / w.Seconds
+}
+
+// Race reports whether the week had a race
+func (w Week) Race() bool {
+	return slices.ContainsFunc(w.Sessions, func(s Session) bool { return s.Category == workouts.CategoryRace })
+}
+
+// Block is a run of consecutive weeks in the same phase
+type Block struct {
+	Phase string
+	Weeks []Week
+}
+
+// Start is the Monday the block began
+func (b Block) Start() time.Time {
+	return b.Weeks[0].Start
+}
+
+// End is the last day of the block
+func (b Block) End() time.Time {
+	last := b.Weeks[len(b.Weeks)-1]
+	return last.Start.AddDate(0, 0, max(last.Days, 1)-1)
+}
+
+// Totals sums the block's weeks
+func (b Block) Totals() (seconds, meters, hardSeconds float64, sessions int) {
+	for _, w := range b.Weeks {
+		seconds += w.Seconds
+		meters += w.Meters
+		hardSeconds += w.HardSeconds
+		sessions += len(w.Sessions)
+	}
+	return seconds, meters, hardSeconds, sessions
+}
+
+// Intensity is the share of the block's moving time in hard sessions
+func (b Block) Intensity() float64 {
+	seconds, _, hard, _ := b.Totals()
+	if seconds == 0 {
+		return 0
+	}
+	return hard / seconds
+}
+
+// WeeklySeconds is the block's average weekly moving time over its
+// completed weeks
+func (b Block) WeeklySeconds() float64 {
+	seconds, _, weeks := b.weekly()
+	return seconds / weeks
+}
+
+// WeeklyMeters is the block's average weekly distance over its completed
+// weeks
+func (b Block) WeeklyMeters() float64 {
+	_, meters, weeks := b.weekly()
+	return meters / weeks
+}
+
+// weekly sums the completed weeks of the block. A block that is only a week
+// in progress counts it as the fraction of a week it covers.
+func (b Block) weekly() (seconds, meters, weeks float64) {
+	for _, w := range b.Weeks {
+		if !w.InProgress {
+			seconds += w.Seconds
+			meters += w.Meters
+			weeks++
+		}
+	}
+	if weeks == 0 {
+		w := b.Weeks[len(b.Weeks)-1]
+		return w.Seconds, w.Meters, float64(max(w.Days, 1)) / 7
+	}
+	return seconds, meters, weeks
+}
+
+// KeySessions picks the block's races, its longest session and its longest
+// hard session, at most n of them, in date order
+func (b Block) KeySessions(n int) []Session {
+	var all []Session
+	for _, w := range b.Weeks {
+		all = append(all, w.Sessions...)
+	}
+
+	var picked []Session
+	add := func(s Session) {
+		if len(picked) < n && !slices.ContainsFunc(picked, func(p Session) bool { return p.ID == s.ID }) {
+			picked = append(picked, s)
+		}
+	}
+	for _, s := range all {
+		if s.Category == workouts.CategoryRace {
+			add(s)
+		}
+	}
+	longest := func(keep func(Session) bool) {
+		var best Session
+		for _, s := range all {
+			if keep(s) && s.Seconds > best.Seconds {
+				best = s
+			}
+		}
+		if best.ID != 0 {
+			add(best)
+		}
+	}
+	longest(func(Session) bool { return true })
+	longest(Session.Hard)
+
+	slices.SortFunc(picked, func(a, b Session) int { return a.Start.Compare(b.Start) })
+	return picked
+}
+
+// EfficiencyChange compares aerobic efficiency, meters per heartbeat on easy
+// and long sessions, between the first and second half of the block. It uses
+// the activity type with the most aerobic sessions and reports the percent
+// change, positive when the athlete went further per beat.
+func (b Block) EfficiencyChange() (activityType string, change float64, ok bool) {
+	byType := make(map[string][]Session)
+	for _, w := range b.Weeks {
+		for _, s := range w.Sessions {
+			if s.aerobic() {
+				byType[s.Type] = append(byType[s.Type], s)
+			}
+		}
+	}
+	for t, sessions := range byType {
+		if len(sessions) > len(byType[activityType]) || (len(sessions) == len(byType[activityType]) && t < activityType) {
+			activityType = t
+		}
+	}
+	sessions := byType[activityType]
+	if len(sessions) < 2*minEfficiencySessions {
+		return "", 0, false
+	}
+
+	half := len(sessions) / 2
+	first, second := efficiency(sessions[:half]), efficiency(sessions[half:])
+	if first == 0 {
+		return "", 0, false
+	}
+	return activityType, (second - first) / first * 100, true
+}
+
+// efficiency is the time-weighted meters per heartbeat over sessions
+func efficiency(sessions []Session) float64 {
+	var sum, weight float64
+	for _, s := range sessions {
+		sum += s.Speed * 60 / s.Heartrate * s.Seconds
+		weight += s.Seconds
+	}
+	if weight == 0 {
+		return 0
+	}
+	return sum / weight
+}
+
+// WeekStart is the Monday on or before t
+func WeekStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// Weeks buckets sessions into every week from the one containing from to the
+// one containing to, oldest first, keeping weeks with no training. The last
+// week only covers the days up to to, and is in progress unless to is the
+// last second of it.
+func Weeks(sessions []Session, from, to time.Time) []Week {
+	first, last := WeekStart(from), WeekStart(to)
+	var weeks []Week
+	for start := first; !start.After(last); start = start.AddDate(0, 0, 7) {
+		weeks = append(weeks, Week{Start: start, Days: 7})
+	}
+	if len(weeks) == 0 {
+		return nil
+	}
+	weeks[len(weeks)-1].Days = int(to.Sub(last).Hours()/24) + 1
+	weeks[len(weeks)-1].InProgress = to.Before(last.AddDate(0, 0, 7).Add(-time.Second))
+
+	sorted := slices.Clone(sessions)
+	slices.SortFunc(sorted, func(a, b Session) int { return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.ID, b.ID)) })
+	for _, s := range sorted {
+		if s.Start.Before(first) || s.Start.After(to) {
+			continue
+		}
+		i := int(WeekStart(s.Start.In(first.Location())).Sub(first).Hours()/24) / 7
+		if i < 0 || i >= len(weeks) {
+			continue
+		}
+		w := &weeks[i]
+		w.Seconds += s.Seconds
+		w.Meters += s.Meters
+		if s.Hard() {
+			w.HardSeconds += s.Seconds
+		}
+		w.Sessions = append(w.Sessions, s)
+	}
+	return weeks
+}
+
+// Segment labels each week with a phase and groups runs of the same phase
+// into blocks, oldest first
+func Segment(weeks []Week) []Block {
+	labels := make([]string, len(weeks))
+	for i := range weeks {
+		labels[i] = phase(weeks, labels[:i], i)
+	}
+	smooth(labels)
+
+	var out []Block
+	for i, w := range weeks {
+		if len(out) > 0 && out[len(out)-1].Phase == labels[i] {
+			out[len(out)-1].Weeks = append(out[len(out)-1].Weeks, w)
+			continue
+		}
+		out = append(out, Block{Phase: labels[i], Weeks: []Week{w}})
+	}
+	return out
+}
+
+// phase labels week i from its volume against the trailing weeks, its
+// intensity and whether a race is coming up. labels holds the phases of the
+// weeks before it. A week still in progress is too early to judge, so it
+// stays in the previous week's phase unless it has a race.
+func phase(weeks []Week, labels []string, i int) string {
+	w := weeks[i]
+	if i > 0 && w.InProgress && !w.Race() {
+		return labels[i-1]
+	}
+	volume := w.Seconds
+	baseline := baselineVolume(weeks, labels, i)
+
+	ratio := 1.0
+	switch {
+	case baseline > 0:
+		ratio = volume / baseline
+	case volume > 0:
+		ratio = math.Inf(1)
+	}
+
+	if w.Race() {
+		if ratio < taperRatio {
+			return PhaseTaper
+		}
+		return PhasePeak
+	}
+	raceAhead := false
+	for _, next := range weeks[i+1 : min(len(weeks), i+1+raceLookahead)] {
+		raceAhead = raceAhead || next.Race()
+	}
+	if raceAhead && ratio < taperRatio {
+		return PhaseTaper
+	}
+	if volume == 0 || ratio < recoveryRatio {
+		return PhaseRecovery
+	}
+
+	biggest := volume
+	for _, p := range weeks[max(0, i-peakWindow+1):i] {
+		biggest = max(biggest, p.Seconds)
+	}
+	if w.Intensity() >= peakIntensity && volume >= peakVolume*biggest {
+		return PhasePeak
+	}
+	if ratio >= buildRatio || w.Intensity() >= buildIntensity {
+		return PhaseBuild
+	}
+	return PhaseBase
+}
+
+// baselineVolume is the average moving time of the trailing weeks before
+// week i, passing over recovery and taper weeks so that training resuming
+// after one isn't taken for a build. Before any training it is the week's own
+// volume.
+func baselineVolume(weeks []Week, labels []string, i int) float64 {
+	var sum float64
+	var n int
+	for j := i - 1; j >= 0 && j >= i-2*baselineWeeks && n < baselineWeeks; j-- {
+		if labels[j] == PhaseRecovery || labels[j] == PhaseTaper {
+			continue
+		}
+		sum += weeks[j].Seconds
+		n++
+	}
+	if n == 0 {
+		return weeks[i].Seconds
+	}
+	return sum / float64(n)
+}
+
+// smooth folds a lone base, build or peak week between two weeks of another
+// phase into its neighbors; a single recovery or taper week is deliberate
+func smooth(labels []string) {
+	for i := 1; i < len(labels)-1; i++ {
+		prev, next := labels[i-1], labels[i+1]
+		if prev != next || labels[i] == prev {
+			continue
+		}
+		switch labels[i] {
+		case PhaseBase, PhaseBuild, PhasePeak:
+			if prev != PhaseRecovery && prev != PhaseTaper {
+				labels[i] = prev
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package blocks
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/workouts"
+)
+
+// season is the Monday the synthetic training starts
+var season = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+// plan builds sessions for consecutive weeks from season. Each week has a
+// hard session holding its hard share of the hours and easy runs for the
+// rest; race weeks end with a race instead.
+func plan(weeks []struct {
+	hours float64
+	hard  float64
+	race  bool
+}) []Session {
+	var out []Session
+	id := int64(1)
+	for i, w := range weeks {
+		monday := season.AddDate(0, 0, 7*i)
+		add := func(day int, seconds float64, category string, speed float64) {
+			out = append(out, Session{
+				ID:        id,
+				Type:      "Run",
+				Start:     monday.AddDate(0, 0, day).Add(7 * time.Hour),
+				Seconds:   seconds,
+				Meters:    seconds * speed,
+				Speed:     speed,
+				Heartrate: 145,
+				Category:  category,
+			})
+			id++
+		}
+		total := w.hours * 3600
+		hard := total * w.hard
+		if w.race {
+			add(6, hard, workouts.CategoryRace, 4.5)
+		} else if hard > 0 {
+			add(2, hard, workouts.CategoryIntervals, 4.2)
+		}
+		for day := range 4 {
+			add(day*2%7, (total-hard)/4, workouts.CategoryEasy, 3.0)
+		}
+	}
+	return out
+}
+
+func TestSegmentSeason(t *testing.T) {
+	sessions := plan([]struct {
+		hours float64
+		hard  float64
+		race  bool
+	}{
+		{5, 0, false}, {5, 0, false}, {5, 0, false}, {5, 0, false},
+		{5.5, 0.1, false}, {6, 0.1, false}, {6.6, 0.1, false}, {7.2, 0.1, false},
+		{7.2, 0.25, false}, {7.2, 0.25, false},
+		{5, 0.15, false}, {3, 0.3, true},
+		{2, 0, false},
+	})
+	weeks := Weeks(sessions, season, endOfWeek(12))
+	if len(weeks) != 13 {
+		t.Fatalf("expected 13 weeks, got %d", len(weeks))
+	}
+
+	got := Segment(weeks)
+	want := []struct {
+		phase string
+		weeks int
+	}{
+		{PhaseBase, 4}, {PhaseBuild, 4}, {PhasePeak, 2}, {PhaseTaper, 2}, {PhaseRecovery, 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d blocks, got %d: %v", len(want), len(got), phases(got))
+	}
+	for i, w := range want {
+		if got[i].Phase != w.phase || len(got[i].Weeks) != w.weeks {
+			t.Errorf("block %d: expected %d weeks of %s, got %v", i, w.weeks, w.phase, phases(got))
+		}
+	}
+
+	peak := got[2]
+	if math.Abs(peak.WeeklySeconds()-7.2*3600) > 1 || math.Abs(peak.Intensity()-0.25) > 0.001 {
+		t.Errorf("unexpected peak totals: %.0fs/week at %.2f", peak.WeeklySeconds(), peak.Intensity())
+	}
+	if got[3].End().Format(time.DateOnly) != "2026-05-24" {
+		t.Errorf("expected the taper to end on race day, got %s", got[3].End())
+	}
+}
+
+// endOfWeek is the last second of week i of the season
+func endOfWeek(i int) time.Time {
+	return season.AddDate(0, 0, 7*(i+1)).Add(-time.Second)
+}
+
+func phases(blocks []Block) []string {
+	var out []string
+	for _, b := range blocks {
+		out = append(out, b.Phase)
+	}
+	return out
+}
+
+func TestSegmentSmoothing(t *testing.T) {
+	// One bigger week in the middle of base training isn't a build
+	sessions := plan([]struct {
+		hours float64
+		hard  float64
+		race  bool
+	}{
+		{5, 0, false}, {5, 0, false}, {5, 0, false}, {6, 0, false}, {5, 0, false}, {5, 0, false},
+	})
+	got := Segment(Weeks(sessions, season, endOfWeek(5)))
+	if len(got) != 1 || got[0].Phase != PhaseBase {
+		t.Errorf("expected a single base block, got %v", phases(got))
+	}
+
+	// A single easy week is kept
+	sessions = plan([]struct {
+		hours float64
+		hard  float64
+		race  bool
+	}{
+		{5, 0, false}, {5, 0, false}, {5, 0, false}, {2, 0, false}, {5, 0, false}, {5, 0, false},
+	})
+	got = Segment(Weeks(sessions, season, endOfWeek(5)))
+	if len(got) != 3 || got[1].Phase != PhaseRecovery {
+		t.Errorf("expected base, recovery, base, got %v", phases(got))
+	}
+}
+
+func TestWeeks(t *testing.T) {
+	sessions := []Session{
+		{ID: 1, Start: season.Add(8 * time.Hour), Seconds: 3600},
+		{ID: 2, Start: season.AddDate(0, 0, 20), Seconds: 1800, Category: workouts.CategoryTempo},
+	}
+	// From a Wednesday to the following Wednesday fortnight
+	weeks := Weeks(sessions, season.AddDate(0, 0, 2), season.AddDate(0, 0, 16))
+	if len(weeks) != 3 {
+		t.Fatalf("expected 3 weeks, got %d", len(weeks))
+	}
+	if !weeks[0].Start.Equal(season) || weeks[0].Seconds != 3600 {
+		t.Errorf("unexpected first week: %+v", weeks[0])
+	}
+	if len(weeks[1].Sessions) != 0 {
+		t.Errorf("expected an empty second week, got %+v", weeks[1])
+	}
+	// The last week covers Monday to Wednesday; the session after it is dropped
+	if weeks[2].Days != 3 || !weeks[2].InProgress || weeks[2].Seconds != 0 {
+		t.Errorf("expected a partial last week, got %+v", weeks[2])
+	}
+
+	if got := WeekStart(time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)); !got.Equal(season) {
+		t.Errorf("expected Sunday to belong to the week of %s, got %s", season, got)
+	}
+}
+
+func TestPartialWeek(t *testing.T) {
+	// Three days into a normal week isn't a recovery week
+	sessions := plan([]struct {
+		hours float64
+		hard  float64
+		race  bool
+	}{
+		{5, 0, false}, {5, 0, false}, {5, 0, false},
+	})
+	var partial []Session
+	for _, s := range sessions {
+		if s.Start.Before(season.AddDate(0, 0, 17)) {
+			partial = append(partial, s)
+		}
+	}
+	got := Segment(Weeks(partial, season, season.AddDate(0, 0, 16)))
+	if len(got) != 1 || got[0].Phase != PhaseBase {
+		t.Errorf("expected the partial week to stay in base, got %v", phases(got))
+	}
+}
+
+func TestKeySessions(t *testing.T) {
+	b := Block{Weeks: []Week{{Sessions: []Session{
+		{ID: 1, Start: season, Seconds: 3600, Category: workouts.CategoryEasy},
+		{ID: 2, Start: season.AddDate(0, 0, 1), Seconds: 2400, Category: workouts.CategoryIntervals},
+		{ID: 3, Start: season.AddDate(0, 0, 2), Seconds: 7200, Category: workouts.CategoryLong},
+		{ID: 4, Start: season.AddDate(0, 0, 3), Seconds: 3000, Category: workouts.CategoryTempo},
+		{ID: 5, Start: season.AddDate(0, 0, 6), Seconds: 1200, Category: workouts.CategoryRace},
+	}}}}
+
+	got := b.KeySessions(3)
+	var ids []int64
+	for _, s := range got {
+		ids = append(ids, s.ID)
+	}
+	// The race, the long run and the tempo, the longest hard session
+	if len(ids) != 3 || ids[0] != 3 || ids[1] != 4 || ids[2] != 5 {
+		t.Errorf("expected sessions 3, 4 and 5, got %v", ids)
+	}
+}
+
+func TestEfficiencyChange(t *testing.T) {
+	var sessions []Session
+	for i := range 6 {
+		sessions = append(sessions, Session{
+			ID:        int64(i + 1),
+			Type:      "Run",
+			Start:     season.AddDate(0, 0, i*3),
+			Seconds:   3600,
+			Speed:     3.0 + 0.05*float64(i),
+			Heartrate: 145,
+			Category:  workouts.CategoryEasy,
+		})
+	}
+	// The runs are compared, not the odd ride
+	sessions = append(sessions, Session{ID: 7, Type: "Ride", Start: season, Seconds: 3600, Speed: 8, Heartrate: 130, Category: workouts.CategoryEasy})
+
+	b := Block{Weeks: Weeks(sessions, season, season.AddDate(0, 0, 20))}
+	activityType, change, ok := b.EfficiencyChange()
+	if !ok || activityType != "Run" {
+		t.Fatalf("expected a run efficiency change, got %q (%v)", activityType, ok)
+	}
+	// 3.05 m/s on average in the first half, 3.20 in the second
+	if math.Abs(change-(3.20-3.05)/3.05*100) > 0.01 {
+		t.Errorf("unexpected change %.2f%%", change)
+	}
+
+	b = Block{Weeks: Weeks(sessions[:3], season, season.AddDate(0, 0, 20))}
+	if _, _, ok := b.EfficiencyChange(); ok {
+		t.Error("expected too few sessions to compare")
+	}
+}
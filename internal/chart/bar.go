@@ -0,0 +1,83 @@
+package chart
+
+// BarChart is a vertical bar chart with one bar per label
+type BarChart struct {
+	Title  string
+	YLabel string
+	Labels []string
+	Values []float64
+}
+
+func (b BarChart) draw(c canvas, w, h float64) {
+	drawTitle(c, w, b.Title)
+
+	maxVal := 0.0
+	for _, v := range b.Values {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+	ticks := niceTicks(0, maxVal, 4)
+	top := ticks[len(ticks)-1]
+
+	plot := newPlotArea(w, h)
+	plot.drawYAxis(c, ticks, 0, top, b.YLabel)
+
+	n := len(b.Values)
+	if n == 0 {
+		c.text(w/2, h/2, "No data", labelSize, anchorMiddle, colorAxis)
+		return
+	}
+
+	slot := plot.w / float64(n)
+	barW := slot * 0.7
+	stride := labelStride(n, plot.w)
+	for i, v := range b.Values {
+		x := plot.x + slot*float64(i) + (slot-barW)/2
+		barH := v / top * plot.h
+		c.rect(x, plot.y+plot.h-barH, barW, barH, colorPrimary)
+
+		if n <= 12 && v > 0 {
+			c.text(x+barW/2, plot.y+plot.h-barH-4, formatNumber(v), labelSize-1, anchorMiddle, colorText)
+		}
+		if i%stride == 0 && i < len(b.Labels) {
+			c.text(x+barW/2, plot.y+plot.h+16, b.Labels[i], labelSize, anchorMiddle, colorText)
+		}
+	}
+}
+
+// plotArea is the inner rectangle of an axis chart
+type plotArea struct {
+	x, y, w, h float64
+}
+
+func newPlotArea(w, h float64) plotArea {
+	return plotArea{
+		x: marginLeft,
+		y: marginTop,
+		w: w - marginLeft - marginRight,
+		h: h - marginTop - marginBottom,
+	}
+}
+
+// yPos maps a value in [lo, hi] to a canvas y coordinate
+func (p plotArea) yPos(v, lo, hi float64) float64 {
+	return p.y + p.h - (v-lo)/(hi-lo)*p.h
+}
+
+// drawYAxis draws gridlines, tick labels, the axes and an optional axis label
+func (p plotArea) drawYAxis(c canvas, ticks []float64, lo, hi float64, label string) {
+	for _, t := range ticks {
+		if t < lo || t > hi {
+			continue
+		}
+		y := p.yPos(t, lo, hi)
+		c.line(p.x, y, p.x+p.w, y, 1, colorGrid)
+		c.text(p.x-6, y+4, formatNumber(t), labelSize, anchorEnd, colorText)
+	}
+	c.line(p.x, p.y, p.x, p.y+p.h, 1, colorAxis)
+	c.line(p.x, p.y+p.h, p.x+p.w, p.y+p.h, 1, colorAxis)
+	if label != "" {
+		c.text(p.x, p.y-10, label, labelSize, anchorStart, colorAxis)
+	}
+}
@@ -0,0 +1,175 @@
+// Package chart renders small, dependency-free charts as SVG or PNG for
+// returning to MCP clients as image content.
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+)
+
+// Format is an output image format
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// Default canvas size in pixels
+const (
+	Width  = 640
+	Height = 360
+)
+
+// Chart is anything that can draw itself onto a canvas
+type Chart interface {
+	draw(c canvas, w, h float64)
+}
+
+// ParseFormat validates a user-supplied format, defaulting to PNG
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatPNG:
+		return FormatPNG, nil
+	case FormatSVG:
+		return FormatSVG, nil
+	}
+	return "", fmt.Errorf("unsupported chart format %q (expected png or svg)", s)
+}
+
+// Render draws the chart and returns the encoded image with its MIME type
+func Render(ch Chart, format Format) ([]byte, string, error) {
+	switch format {
+	case FormatSVG:
+		c := newSVGCanvas(Width, Height)
+		ch.draw(c, Width, Height)
+		return c.bytes(), "image/svg+xml", nil
+	case FormatPNG, "":
+		c := newRasterCanvas(Width, Height)
+		ch.draw(c, Width, Height)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, c.img); err != nil {
+			return nil, "", fmt.Errorf("encoding PNG: %w", err)
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	return nil, "", fmt.Errorf("unsupported chart format %q", format)
+}
+
+// canvas is the small set of drawing primitives the charts need.
+// Coordinates are in pixels with the origin at the top left. Angles are in
+// radians, clockwise from 12 o'clock.
+type canvas interface {
+	rect(x, y, w, h float64, fill color.RGBA)
+	line(x1, y1, x2, y2, width float64, stroke color.RGBA)
+	wedge(cx, cy, rOuter, rInner, a0, a1 float64, fill color.RGBA)
+	text(x, y float64, s string, size float64, anchor textAnchor, fill color.RGBA)
+}
+
+type textAnchor string
+
+const (
+	anchorStart  textAnchor = "start"
+	anchorMiddle textAnchor = "middle"
+	anchorEnd    textAnchor = "end"
+)
+
+// Palette
+var (
+	colorBackground = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	colorAxis       = color.RGBA{0x99, 0x99, 0x99, 0xFF}
+	colorGrid       = color.RGBA{0xE6, 0xE6, 0xE6, 0xFF}
+	colorText       = color.RGBA{0x33, 0x33, 0x33, 0xFF}
+	colorPrimary    = color.RGBA{0xFC, 0x4C, 0x02, 0xFF} // Strava orange
+
+	seriesColors = []color.RGBA{
+		colorPrimary,
+		{0x1F, 0x77, 0xB4, 0xFF},
+		{0x2C, 0xA0, 0x2C, 0xFF},
+		{0x94, 0x67, 0xBD, 0xFF},
+		{0x8C, 0x56, 0x4B, 0xFF},
+	}
+
+	// zoneColors follow the conventional Z1 (easy) to Z5 (max) progression
+	zoneColors = []color.RGBA{
+		{0x9E, 0x9E, 0x9E, 0xFF},
+		{0x42, 0xA5, 0xF5, 0xFF},
+		{0x66, 0xBB, 0x6A, 0xFF},
+		{0xFF, 0xA7, 0x26, 0xFF},
+		{0xEF, 0x53, 0x50, 0xFF},
+		{0xAB, 0x47, 0xBC, 0xFF},
+		{0x6D, 0x4C, 0x41, 0xFF},
+	}
+)
+
+// Layout margins shared by the axis charts
+const (
+	marginLeft   = 64
+	marginRight  = 20
+	marginTop    = 44
+	marginBottom = 48
+	titleSize    = 16
+	labelSize    = 11
+)
+
+func drawTitle(c canvas, w float64, title string) {
+	c.rect(0, 0, w, Height, colorBackground)
+	if title != "" {
+		c.text(w/2, 26, title, titleSize, anchorMiddle, colorText)
+	}
+}
+
+// niceTicks returns evenly spaced, human-friendly tick values covering [lo, hi]
+func niceTicks(lo, hi float64, target int) []float64 {
+	if hi <= lo {
+		hi = lo + 1
+	}
+	raw := (hi - lo) / float64(target)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if raw <= m*mag {
+			step = m * mag
+			break
+		}
+	}
+
+	start := math.Floor(lo/step) * step
+	var ticks []float64
+	for i := 0; ; i++ {
+		v := start + float64(i)*step
+		ticks = append(ticks, v)
+		if v >= hi-step*1e-9 {
+			return ticks
+		}
+	}
+}
+
+// formatNumber prints v with no more precision than it needs
+func formatNumber(v float64) string {
+	switch {
+	case math.Abs(v) >= 100 || v == math.Trunc(v):
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	case math.Abs(v) >= 10:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	default:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+}
+
+// labelStride returns how many x labels to skip so that they don't overlap
+func labelStride(n int, width float64) int {
+	const minLabelWidth = 56
+	if n == 0 {
+		return 1
+	}
+	stride := int(math.Ceil(float64(n) * minLabelWidth / width))
+	if stride < 1 {
+		stride = 1
+	}
+	return stride
+}
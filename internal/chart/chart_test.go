@@ -0,0 +1,177 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+)
+
+func testCharts() map[string]Chart {
+	return map[string]Chart{
+		"bar": BarChart{
+			Title:  "Weekly distance",
+			YLabel: "km",
+			Labels: []string{"2024-W01", "2024-W02", "2024-W03"},
+			Values: []float64{21.3, 35, 28.4},
+		},
+		"donut": DonutChart{
+			Title:      "Heart rate zones",
+			ZoneColors: true,
+			Slices:     []Slice{{"Z1", 20}, {"Z2", 50}, {"Z3", 15}, {"Z4", 10}, {"Z5", 5}},
+		},
+		"line": LineChart{
+			Title:   "Weekly pace",
+			XLabels: []string{"W1", "W2", "W3", "W4"},
+			Series:  []Series{{Name: "pace", Values: []float64{330, math.NaN(), 318, 310}}},
+			InvertY: true,
+			Divider: 2,
+		},
+		"map": RouteMap{
+			Title:         "Morning Run",
+			Tracks:        []Track{{Points: testTrack(), Values: []float64{0, 300, 320, math.NaN(), 290}}},
+			ShowMarkers:   true,
+			GradientLabel: "pace",
+		},
+		"empty":     BarChart{Title: "Nothing"},
+		"empty map": RouteMap{Title: "Nothing"},
+	}
+}
+
+func testTrack() []geo.Point {
+	return []geo.Point{
+		{Lat: 37.7749, Lng: -122.4194},
+		{Lat: 37.7760, Lng: -122.4170},
+		{Lat: 37.7790, Lng: -122.4160},
+		{Lat: 37.7800, Lng: -122.4190},
+		{Lat: 37.7770, Lng: -122.4200},
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	for name, ch := range testCharts() {
+		data, mimeType, err := Render(ch, FormatPNG)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if mimeType != "image/png" {
+			t.Errorf("%s: expected image/png, got %s", name, mimeType)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: output is not a valid PNG: %v", name, err)
+		}
+		if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+			t.Errorf("%s: expected %dx%d, got %v", name, Width, Height, b)
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	for name, ch := range testCharts() {
+		data, mimeType, err := Render(ch, FormatSVG)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if mimeType != "image/svg+xml" {
+			t.Errorf("%s: expected image/svg+xml, got %s", name, mimeType)
+		}
+		dec := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			_, err := dec.Token()
+			if err != nil {
+				if err.Error() != "EOF" {
+					t.Fatalf("%s: output is not well-formed XML: %v", name, err)
+				}
+				break
+			}
+		}
+	}
+}
+
+func TestRenderSVGEscapesText(t *testing.T) {
+	data, _, err := Render(BarChart{Title: "Run & <Ride>"}, FormatSVG)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "Run &amp; &lt;Ride&gt;") {
+		t.Error("expected title to be XML-escaped")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatPNG, false},
+		{"png", FormatPNG, false},
+		{"svg", FormatSVG, false},
+		{"jpeg", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestNiceTicks(t *testing.T) {
+	ticks := niceTicks(0, 42.1, 4)
+	if ticks[0] != 0 || ticks[len(ticks)-1] < 42.1 {
+		t.Errorf("ticks %v do not cover [0, 42.1]", ticks)
+	}
+	step := ticks[1] - ticks[0]
+	if step != 10 && step != 20 {
+		t.Errorf("expected a round step, got %v", step)
+	}
+}
+
+func TestRouteMapProjection(t *testing.T) {
+	m := RouteMap{Tracks: []Track{{Points: testTrack()}}}
+	proj, ok := m.projection(0, 0, 200, 100)
+	if !ok {
+		t.Fatal("expected a projection")
+	}
+
+	// Every point lands inside the box, and north is up
+	for _, p := range testTrack() {
+		x, y := proj.point(p)
+		if x < -0.01 || x > 200.01 || y < -0.01 || y > 100.01 {
+			t.Errorf("point %v projected outside the box: (%.1f, %.1f)", p, x, y)
+		}
+	}
+	_, ySouth := proj.point(geo.Point{Lat: 37.7749, Lng: -122.418})
+	_, yNorth := proj.point(geo.Point{Lat: 37.7800, Lng: -122.418})
+	if yNorth >= ySouth {
+		t.Errorf("expected north above south, got north y=%.1f south y=%.1f", yNorth, ySouth)
+	}
+
+	if _, ok := (RouteMap{}).projection(0, 0, 200, 100); ok {
+		t.Error("expected no projection without points")
+	}
+}
+
+func TestRouteMapGradientRange(t *testing.T) {
+	values := make([]float64, 0, 101)
+	for i := 0; i <= 100; i++ {
+		values = append(values, float64(i))
+	}
+	values[0] = -1000 // outliers are clipped by the percentiles
+	values[100] = 1000
+
+	lo, hi, ok := RouteMap{Tracks: []Track{{Values: values}}}.gradientRange()
+	if !ok || lo != 5 || hi != 95 {
+		t.Errorf("gradientRange() = %v, %v, %v; want 5, 95, true", lo, hi, ok)
+	}
+
+	if _, _, ok := (RouteMap{Tracks: []Track{{Values: []float64{math.NaN()}}}}).gradientRange(); ok {
+		t.Error("expected no gradient for all-NaN values")
+	}
+}
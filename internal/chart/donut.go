@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+)
+
+// Slice is one segment of a donut chart
+type Slice struct {
+	Label string
+	Value float64
+}
+
+// DonutChart shows each slice's share of the total. Zone charts pass
+// ZoneColors so that Z1..Z5 keep their conventional colors.
+type DonutChart struct {
+	Title      string
+	Slices     []Slice
+	ZoneColors bool
+}
+
+func (d DonutChart) draw(c canvas, w, h float64) {
+	drawTitle(c, w, d.Title)
+
+	total := 0.0
+	for _, s := range d.Slices {
+		if s.Value > 0 {
+			total += s.Value
+		}
+	}
+	if total == 0 {
+		c.text(w/2, h/2, "No data", labelSize, anchorMiddle, colorAxis)
+		return
+	}
+
+	cx, cy := w*0.35, (h+marginTop)/2
+	rOuter := math.Min(w*0.3, (h-marginTop-16)/2)
+	rInner := rOuter * 0.55
+
+	palette := seriesColors
+	if d.ZoneColors {
+		palette = zoneColors
+	}
+
+	a := 0.0
+	legendX, legendY := w*0.68, cy-float64(len(d.Slices))*12
+	for i, s := range d.Slices {
+		col := palette[i%len(palette)]
+		if s.Value > 0 {
+			sweep := s.Value / total * 2 * math.Pi
+			c.wedge(cx, cy, rOuter, rInner, a, a+sweep, col)
+			a += sweep
+		}
+
+		ly := legendY + float64(i)*24
+		c.rect(legendX, ly-10, 12, 12, col)
+		c.text(legendX+20, ly, fmt.Sprintf("%s  %.0f%%", s.Label, math.Max(s.Value, 0)/total*100), labelSize+1, anchorStart, colorText)
+	}
+}
@@ -0,0 +1,119 @@
+package chart
+
+import "math"
+
+// Series is one line on a LineChart. NaN values leave a gap.
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// LineChart plots one or more series against shared x labels
+type LineChart struct {
+	Title   string
+	YLabel  string
+	XLabels []string
+	Series  []Series
+	// InvertY puts lower values at the top, e.g. for pace where less time per km is better
+	InvertY bool
+	// YFormat formats tick labels; defaults to plain numbers
+	YFormat func(float64) string
+	// Divider draws a vertical marker before this x index (0 for none)
+	Divider int
+}
+
+func (l LineChart) draw(c canvas, w, h float64) {
+	drawTitle(c, w, l.Title)
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	n := len(l.XLabels)
+	for _, s := range l.Series {
+		if len(s.Values) > n {
+			n = len(s.Values)
+		}
+		for _, v := range s.Values {
+			if math.IsNaN(v) {
+				continue
+			}
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+
+	plot := newPlotArea(w, h)
+	if math.IsInf(lo, 1) || n == 0 {
+		plot.drawYAxis(c, nil, 0, 1, l.YLabel)
+		c.text(w/2, h/2, "No data", labelSize, anchorMiddle, colorAxis)
+		return
+	}
+
+	// Pad the range so flat lines don't sit on the axis
+	pad := (hi - lo) * 0.1
+	if pad == 0 {
+		pad = math.Max(math.Abs(hi)*0.1, 1)
+	}
+	ticks := niceTicks(lo-pad, hi+pad, 4)
+	lo, hi = ticks[0], ticks[len(ticks)-1]
+
+	format := l.YFormat
+	if format == nil {
+		format = formatNumber
+	}
+
+	yPos := func(v float64) float64 {
+		if l.InvertY {
+			return plot.y + (v-lo)/(hi-lo)*plot.h
+		}
+		return plot.yPos(v, lo, hi)
+	}
+	for _, t := range ticks {
+		y := yPos(t)
+		c.line(plot.x, y, plot.x+plot.w, y, 1, colorGrid)
+		c.text(plot.x-6, y+4, format(t), labelSize, anchorEnd, colorText)
+	}
+	c.line(plot.x, plot.y, plot.x, plot.y+plot.h, 1, colorAxis)
+	c.line(plot.x, plot.y+plot.h, plot.x+plot.w, plot.y+plot.h, 1, colorAxis)
+	if l.YLabel != "" {
+		c.text(plot.x, plot.y-10, l.YLabel, labelSize, anchorStart, colorAxis)
+	}
+
+	xPos := func(i int) float64 {
+		if n == 1 {
+			return plot.x + plot.w/2
+		}
+		return plot.x + plot.w*float64(i)/float64(n-1)
+	}
+
+	stride := labelStride(n, plot.w)
+	for i := 0; i < len(l.XLabels); i += stride {
+		c.text(xPos(i), plot.y+plot.h+16, l.XLabels[i], labelSize, anchorMiddle, colorText)
+	}
+
+	if l.Divider > 0 && l.Divider < n {
+		x := (xPos(l.Divider-1) + xPos(l.Divider)) / 2
+		c.line(x, plot.y, x, plot.y+plot.h, 1, colorAxis)
+	}
+
+	for si, s := range l.Series {
+		col := seriesColors[si%len(seriesColors)]
+		prev := -1
+		for i, v := range s.Values {
+			if math.IsNaN(v) {
+				prev = -1
+				continue
+			}
+			if prev >= 0 {
+				c.line(xPos(prev), yPos(s.Values[prev]), xPos(i), yPos(v), 2, col)
+			}
+			c.rect(xPos(i)-2.5, yPos(v)-2.5, 5, 5, col)
+			prev = i
+		}
+
+		if len(l.Series) > 1 {
+			lx := plot.x + plot.w - 120
+			ly := plot.y + 4 + float64(si)*16
+			c.rect(lx, ly-8, 10, 10, col)
+			c.text(lx+16, ly+1, s.Name, labelSize, anchorStart, colorText)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+)
+
+// Track is one route drawn on a RouteMap. Values, when present, are aligned
+// with Points and color each segment along the gradient; NaN leaves a segment
+// in the neutral color.
+type Track struct {
+	Points []geo.Point
+	Values []float64
+}
+
+// RouteMap draws one or more GPS tracks in Web Mercator without any base
+// map tiles. North is up; a scale bar gives the sense of distance.
+type RouteMap struct {
+	Title  string
+	Tracks []Track
+	// ShowMarkers draws a green start and red finish dot on every track
+	ShowMarkers bool
+	// GradientLabel names the colored metric in the legend, e.g. "pace"
+	GradientLabel string
+	// ValueFormat formats the legend's range labels; defaults to plain numbers
+	ValueFormat func(float64) string
+}
+
+var (
+	colorMapBackground = color.RGBA{0xF5, 0xF4, 0xF0, 0xFF}
+	colorMapNeutral    = color.RGBA{0x9E, 0x9E, 0x9E, 0xFF}
+	colorStart         = color.RGBA{0x2E, 0x9E, 0x44, 0xFF}
+	colorFinish        = color.RGBA{0xD3, 0x2F, 0x2F, 0xFF}
+
+	// gradientStops run from cool (low) to hot (high)
+	gradientStops = []color.RGBA{
+		{0x31, 0x6A, 0xC8, 0xFF},
+		{0x2C, 0xB0, 0x5B, 0xFF},
+		{0xF2, 0xC1, 0x1D, 0xFF},
+		{0xE8, 0x3B, 0x2B, 0xFF},
+	}
+)
+
+const (
+	mapPadding      = 16
+	mapLegendHeight = 30
+)
+
+func (m RouteMap) draw(c canvas, w, h float64) {
+	drawTitle(c, w, m.Title)
+
+	lo, hi, gradient := m.gradientRange()
+	left, top, right, bottom := float64(mapPadding), float64(marginTop), w-mapPadding, h-mapLegendHeight
+	c.rect(left, top, right-left, bottom-top, colorMapBackground)
+
+	proj, ok := m.projection(left+mapPadding, top+mapPadding, right-mapPadding, bottom-mapPadding)
+	if !ok {
+		c.text(w/2, (top+bottom)/2, "No GPS data", labelSize, anchorMiddle, colorAxis)
+		return
+	}
+
+	for _, t := range m.Tracks {
+		for i := 1; i < len(t.Points); i++ {
+			x1, y1 := proj.point(t.Points[i-1])
+			x2, y2 := proj.point(t.Points[i])
+			col := colorPrimary
+			if gradient {
+				col = colorMapNeutral
+				if i < len(t.Values) && !math.IsNaN(t.Values[i]) {
+					col = gradientColor(t.Values[i], lo, hi)
+				}
+			}
+			c.line(x1, y1, x2, y2, 2.5, col)
+		}
+	}
+
+	if m.ShowMarkers {
+		for _, t := range m.Tracks {
+			if len(t.Points) == 0 {
+				continue
+			}
+			drawMarker(c, proj, t.Points[len(t.Points)-1], colorFinish)
+			drawMarker(c, proj, t.Points[0], colorStart)
+		}
+	}
+
+	legendY := h - mapLegendHeight/2 + 4
+	proj.drawScaleBar(c, left, legendY)
+	if gradient {
+		m.drawLegend(c, right, legendY, lo, hi)
+	}
+}
+
+// gradientRange returns the 5th-95th percentile of all track values so a few
+// outliers (GPS spikes, stops) don't wash out the gradient
+func (m RouteMap) gradientRange() (float64, float64, bool) {
+	var values []float64
+	for _, t := range m.Tracks {
+		for _, v := range t.Values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				values = append(values, v)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return 0, 0, false
+	}
+	sort.Float64s(values)
+	lo := values[int(float64(len(values)-1)*0.05)]
+	hi := values[int(float64(len(values)-1)*0.95)]
+	return lo, hi, true
+}
+
+func gradientColor(v, lo, hi float64) color.RGBA {
+	t := 0.5
+	if hi > lo {
+		t = math.Max(0, math.Min(1, (v-lo)/(hi-lo)))
+	}
+	return gradientAt(t)
+}
+
+// gradientAt interpolates gradientStops at t in [0, 1]
+func gradientAt(t float64) color.RGBA {
+	pos := t * float64(len(gradientStops)-1)
+	i := int(pos)
+	if i >= len(gradientStops)-1 {
+		return gradientStops[len(gradientStops)-1]
+	}
+	f := pos - float64(i)
+	a, b := gradientStops[i], gradientStops[i+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*f))
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xFF}
+}
+
+func (m RouteMap) drawLegend(c canvas, right, y, lo, hi float64) {
+	const barWidth, steps = 120.0, 24
+	format := m.ValueFormat
+	if format == nil {
+		format = formatNumber
+	}
+
+	loLabel, hiLabel := format(lo), format(hi)
+	if m.GradientLabel != "" {
+		loLabel = m.GradientLabel + "  " + loLabel
+	}
+
+	x := right - barWidth - 44
+	c.text(x-6, y, loLabel, labelSize, anchorEnd, colorText)
+	for i := 0; i < steps; i++ {
+		c.rect(x+float64(i)*barWidth/steps, y-9, barWidth/steps+0.5, 10, gradientAt(float64(i)/(steps-1)))
+	}
+	c.text(x+barWidth+6, y, hiLabel, labelSize, anchorStart, colorText)
+}
+
+func drawMarker(c canvas, proj mercator, p geo.Point, fill color.RGBA) {
+	x, y := proj.point(p)
+	c.wedge(x, y, 6, 0, 0, 2*math.Pi, colorBackground)
+	c.wedge(x, y, 4.5, 0, 0, 2*math.Pi, fill)
+}
+
+// mercator maps coordinates onto the canvas, fitted to the tracks' bounds
+// with a uniform scale so shapes aren't distorted
+type mercator struct {
+	scale     float64 // pixels per projected unit
+	originX   float64 // projected x at canvas x = offsetX
+	originY   float64 // projected y at canvas y = offsetY
+	offsetX   float64
+	offsetY   float64
+	centerLat float64 // radians
+}
+
+func project(p geo.Point) (float64, float64) {
+	lat := math.Max(-85, math.Min(85, p.Lat)) * math.Pi / 180
+	return p.Lng * math.Pi / 180, math.Log(math.Tan(math.Pi/4 + lat/2))
+}
+
+func (m RouteMap) projection(left, top, right, bottom float64) (mercator, bool) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, t := range m.Tracks {
+		for _, p := range t.Points {
+			x, y := project(p)
+			minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+			minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+		}
+	}
+	if math.IsInf(minX, 1) {
+		return mercator{}, false
+	}
+
+	// Treat single points and straight lines as a small area so the scale stays finite
+	const minExtent = 1e-5
+	spanX := math.Max(maxX-minX, minExtent)
+	spanY := math.Max(maxY-minY, minExtent)
+	width, height := right-left, bottom-top
+	scale := math.Min(width/spanX, height/spanY)
+
+	centerY := (minY + maxY) / 2
+	return mercator{
+		scale:     scale,
+		originX:   (minX+maxX)/2 - width/2/scale,
+		originY:   centerY + height/2/scale,
+		offsetX:   left,
+		offsetY:   top,
+		centerLat: 2*math.Atan(math.Exp(centerY)) - math.Pi/2,
+	}, true
+}
+
+func (p mercator) point(pt geo.Point) (float64, float64) {
+	x, y := project(pt)
+	return p.offsetX + (x-p.originX)*p.scale, p.offsetY + (p.originY-y)*p.scale
+}
+
+// drawScaleBar draws a bar of a round ground distance, about 100px long
+func (p mercator) drawScaleBar(c canvas, left, y float64) {
+	metersPerPixel := geo.EarthRadius * math.Cos(p.centerLat) / p.scale
+	if metersPerPixel <= 0 || math.IsNaN(metersPerPixel) {
+		return
+	}
+
+	target := metersPerPixel * 100
+	mag := math.Pow(10, math.Floor(math.Log10(target)))
+	meters := mag
+	for _, f := range []float64{1, 2, 5, 10} {
+		if f*mag <= target {
+			meters = f * mag
+		}
+	}
+	length := meters / metersPerPixel
+
+	label := fmt.Sprintf("%.0f m", meters)
+	if meters >= 1000 {
+		label = formatNumber(meters/1000) + " km"
+	}
+
+	x := left + 4
+	c.line(x, y-4, x+length, y-4, 2, colorText)
+	c.line(x, y-9, x, y, 1.5, colorText)
+	c.line(x+length, y-9, x+length, y, 1.5, colorText)
+	c.text(x+length+6, y, label, labelSize, anchorStart, colorText)
+}
@@ -0,0 +1,71 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// svgCanvas accumulates SVG elements
+type svgCanvas struct {
+	sb strings.Builder
+}
+
+func newSVGCanvas(w, h int) *svgCanvas {
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`, w, h, w, h)
+	c.sb.WriteString("\n")
+	return c
+}
+
+func (c *svgCanvas) bytes() []byte {
+	return []byte(c.sb.String() + "</svg>\n")
+}
+
+func hex(col color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", col.R, col.G, col.B)
+}
+
+func (c *svgCanvas) rect(x, y, w, h float64, fill color.RGBA) {
+	fmt.Fprintf(&c.sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, w, h, hex(fill))
+}
+
+func (c *svgCanvas) line(x1, y1, x2, y2, width float64, stroke color.RGBA) {
+	fmt.Fprintf(&c.sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f" stroke-linecap="round"/>`+"\n",
+		x1, y1, x2, y2, hex(stroke), width)
+}
+
+func (c *svgCanvas) wedge(cx, cy, rOuter, rInner, a0, a1 float64, fill color.RGBA) {
+	// A single arc can't describe a full circle, so split it in two
+	if a1-a0 >= 2*math.Pi-1e-9 {
+		mid := a0 + math.Pi
+		c.wedge(cx, cy, rOuter, rInner, a0, mid, fill)
+		c.wedge(cx, cy, rOuter, rInner, mid, a1, fill)
+		return
+	}
+
+	large := 0
+	if a1-a0 > math.Pi {
+		large = 1
+	}
+	ox0, oy0 := polar(cx, cy, rOuter, a0)
+	ox1, oy1 := polar(cx, cy, rOuter, a1)
+	ix1, iy1 := polar(cx, cy, rInner, a1)
+	ix0, iy0 := polar(cx, cy, rInner, a0)
+
+	fmt.Fprintf(&c.sb, `<path d="M%.2f %.2f A%.2f %.2f 0 %d 1 %.2f %.2f L%.2f %.2f A%.2f %.2f 0 %d 0 %.2f %.2f Z" fill="%s"/>`+"\n",
+		ox0, oy0, rOuter, rOuter, large, ox1, oy1,
+		ix1, iy1, rInner, rInner, large, ix0, iy0, hex(fill))
+}
+
+func (c *svgCanvas) text(x, y float64, s string, size float64, anchor textAnchor, fill color.RGBA) {
+	fmt.Fprintf(&c.sb, `<text x="%.1f" y="%.1f" font-size="%.0f" text-anchor="%s" fill="%s">%s</text>`+"\n",
+		x, y, size, anchor, hex(fill), html.EscapeString(s))
+}
+
+// polar converts a clockwise-from-north angle to canvas coordinates
+func polar(cx, cy, r, a float64) (float64, float64) {
+	return cx + r*math.Sin(a), cy - r*math.Cos(a)
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/explore"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportType      string
+	exportStartDate string
+	exportEndDate   string
+	exportLimit     int
+	exportOutput    string
+	exportHeatmap   bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export data from the local database",
+}
+
+var exportGeoJSONCmd = &cobra.Command{
+	Use:   "geojson",
+	Short: "Export activity routes as a GeoJSON FeatureCollection",
+	Long: `Decode the stored Strava summary polylines and write them as a GeoJSON
+FeatureCollection, one LineString feature per activity. Activities without
+GPS data are skipped. No Strava API calls are made.
+
+Examples:
+  strava-mcp export geojson --type Run --start-date 2024-01-01 -o runs.geojson
+  strava-mcp export geojson --limit 0 > all.geojson`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportGeoJSON(cmd.Context())
+	},
+}
+
+var exportTilesCmd = &cobra.Command{
+	Use:   "tiles",
+	Short: "Export explorer tiles and the GPS heatmap as GeoJSON",
+	Long: `Aggregate the stored GPS streams (or summary polylines where no streams are
+synced yet) into zoom-14 explorer tiles and write them as a GeoJSON
+FeatureCollection of polygons. Each feature has a "kind" property: "tile"
+for a visited tile, "max_square" for the largest fully visited square, and
+with --heatmap, "heat" for each ~250 m heatmap cell with its activity count.
+No Strava API calls are made.
+
+Examples:
+  strava-mcp export tiles -o tiles.geojson
+  strava-mcp export tiles --type Ride --heatmap > rides.geojson`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportTiles(cmd.Context())
+	},
+}
+
+func init() {
+	exportGeoJSONCmd.Flags().StringVar(&exportType, "type", "", "filter by activity type (e.g. Run, Ride)")
+	exportGeoJSONCmd.Flags().StringVar(&exportStartDate, "start-date", "", "include activities on or after this date (YYYY-MM-DD)")
+	exportGeoJSONCmd.Flags().StringVar(&exportEndDate, "end-date", "", "include activities on or before this date (YYYY-MM-DD)")
+	exportGeoJSONCmd.Flags().IntVar(&exportLimit, "limit", 0, "maximum number of activities to export (0 for all)")
+	exportGeoJSONCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file (default stdout)")
+
+	exportTilesCmd.Flags().StringVar(&exportType, "type", "", "filter by activity type (e.g. Run, Ride)")
+	exportTilesCmd.Flags().StringVar(&exportStartDate, "start-date", "", "include activities on or after this date (YYYY-MM-DD)")
+	exportTilesCmd.Flags().StringVar(&exportEndDate, "end-date", "", "include activities on or before this date (YYYY-MM-DD)")
+	exportTilesCmd.Flags().BoolVar(&exportHeatmap, "heatmap", false, "include heatmap cells")
+	exportTilesCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file (default stdout)")
+
+	exportCmd.AddCommand(exportGeoJSONCmd)
+	exportCmd.AddCommand(exportTilesCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func exportGeoJSON(ctx context.Context) error {
+	log := logging.Logger
+
+	start, end, err := parseExportDates(exportStartDate, exportEndDate)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := openDatabase(ctx, dbPath)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	limit := int64(exportLimit)
+	if limit <= 0 {
+		limit = -1 // SQLite treats a negative LIMIT as unbounded
+	}
+
+	hasType := exportType != ""
+	activities, err := db.New(sqlDB).SearchActivitiesWithRoute(ctx, db.SearchActivitiesWithRouteParams{
+		Column1:     sql.NullString{String: exportType, Valid: hasType},
+		Type:        sql.NullString{String: exportType, Valid: hasType},
+		Column3:     start,
+		StartDate:   start,
+		Column5:     end,
+		StartDate_2: end,
+		Limit:       limit,
+	})
+	if err != nil {
+		return fmt.Errorf("querying activities: %w", err)
+	}
+
+	routes := make([]geo.Route, 0, len(activities))
+	for _, a := range activities {
+		route, err := geo.RouteFromActivity(a)
+		if err != nil {
+			log.Warn().Err(err).Int64("activity_id", a.ID).Msg("skipping activity")
+			continue
+		}
+		routes = append(routes, route)
+	}
+
+	if err := writeGeoJSON(exportOutput, geo.NewFeatureCollection(routes)); err != nil {
+		return err
+	}
+
+	log.Info().Int("routes", len(routes)).Str("output", exportOutput).Msg("export complete")
+	return nil
+}
+
+func exportTiles(ctx context.Context) error {
+	log := logging.Logger
+
+	start, end, err := parseExportDates(exportStartDate, exportEndDate)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := openDatabase(ctx, dbPath)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	coverage, err := explore.Build(ctx, db.New(sqlDB), explore.Filter{
+		Type:      exportType,
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeGeoJSON(exportOutput, coverage.FeatureCollection(exportHeatmap)); err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("activities", coverage.Activities).
+		Int("tiles", len(coverage.Tiles)).
+		Str("output", exportOutput).
+		Msg("export complete")
+	return nil
+}
+
+// writeGeoJSON writes v as indented JSON to path, or stdout when path is empty
+func writeGeoJSON(path string, v any) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("writing GeoJSON: %w", err)
+	}
+	return nil
+}
+
+// openDatabase opens the SQLite database for a one-off command and applies
+// pending migrations. Unlike Run it does not take the exclusive lock.
+func openDatabase(ctx context.Context, path string) (*sql.DB, error) {
+	sqlDB, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	if err := configureSQLite(sqlDB); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("configuring SQLite: %w", err)
+	}
+
+	if err := runMigrations(ctx, sqlDB); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	return sqlDB, nil
+}
+
+// parseExportDates parses YYYY-MM-DD bounds; the end date is inclusive
+func parseExportDates(startDate, endDate string) (sql.NullTime, sql.NullTime, error) {
+	var start, end sql.NullTime
+
+	if startDate != "" {
+		t, err := time.Parse(time.DateOnly, startDate)
+		if err != nil {
+			return start, end, fmt.Errorf("parsing start date: %w", err)
+		}
+		start = sql.NullTime{Time: t, Valid: true}
+	}
+
+	if endDate != "" {
+		t, err := time.Parse(time.DateOnly, endDate)
+		if err != nil {
+			return start, end, fmt.Errorf("parsing end date: %w", err)
+		}
+		end = sql.NullTime{Time: t.Add(24*time.Hour - time.Second), Valid: true}
+	}
+
+	return start, end, nil
+}
@@ -17,6 +17,8 @@ var (
 	tokenRefreshInterval time.Duration
 	noSync               bool
 	forceReauth          bool
+	zonesFile            string
+	syncSocialLists      bool
 )
 
 var rootCmd = &cobra.Command{
@@ -51,6 +53,8 @@ Use --force-reauth to re-enter credentials and re-authenticate.
 			TokenRefreshInterval: tokenRefreshInterval,
 			NoSync:               noSync,
 			ForceReauth:          forceReauth,
+			ZonesFile:            zonesFile,
+			SyncSocialLists:      syncSocialLists,
 		}
 
 		return Run(rtCfg)
@@ -72,6 +76,12 @@ func init() {
 
 	// Force re-authentication
 	rootCmd.PersistentFlags().BoolVar(&forceReauth, "force-reauth", false, "force OAuth re-authentication, clearing existing tokens")
+
+	// Zone boundaries for computing zones locally from streams
+	rootCmd.PersistentFlags().StringVar(&zonesFile, "zones-file", "", "JSON file of heart rate, power and pace zone settings (overrides Strava profile zones)")
+
+	// Who gave kudos and comments, at one or two API requests per activity
+	rootCmd.PersistentFlags().BoolVar(&syncSocialLists, "sync-social-lists", false, "also sync who gave kudos and the comments on each activity (uses more API requests)")
 }
 
 // Execute runs the root command
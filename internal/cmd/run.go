@@ -15,6 +15,7 @@ import (
 	"github.com/joshdurbin/strava-mcp/internal/auth"
 	"github.com/joshdurbin/strava-mcp/internal/db"
 	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/ratelimit"
 	"github.com/joshdurbin/strava-mcp/internal/server"
 	"github.com/joshdurbin/strava-mcp/internal/strava"
 	"github.com/joshdurbin/strava-mcp/internal/workers"
@@ -33,6 +34,8 @@ type RuntimeConfig struct {
 	TokenRefreshInterval time.Duration
 	NoSync               bool
 	ForceReauth          bool
+	ZonesFile            string
+	SyncSocialLists      bool
 }
 
 // Run is the main entry point for the unified run mode
@@ -79,26 +82,25 @@ func Run(cfg *RuntimeConfig) error {
 	}
 
 	// Run SQL migrations using goose
-	gooseProvider, err := goose.NewProvider(goose.DialectSQLite3, sqlDB, os.DirFS("sql/migrations"))
-	if err != nil {
-		return fmt.Errorf("creating goose provider: %w", err)
-	}
-
-	results, err := gooseProvider.Up(ctx)
-	if err != nil {
-		return fmt.Errorf("running migrations: %w", err)
-	}
-
-	for _, r := range results {
-		log.Debug().Int64("version", r.Source.Version).Str("path", r.Source.Path).Msg("migration applied")
+	if err := runMigrations(ctx, sqlDB); err != nil {
+		return err
 	}
-	log.Debug().Int("applied", len(results)).Msg("database migrations completed")
 
 	// Create queries and storage
 	queries := db.New(sqlDB)
 
+	// Apply zone boundaries from --zones-file (or clear previous ones)
+	if err := workers.LoadZoneSettings(ctx, queries, cfg.ZonesFile); err != nil {
+		return err
+	}
+
 	// Log database statistics
 	workers.LogDatabaseStats(ctx, queries)
+	workers.DetectRoutes(ctx, queries)
+	workers.ComputeLocalZones(ctx, queries)
+	workers.AnalyzeThresholds(ctx, queries)
+	workers.ClassifyWorkouts(ctx, queries)
+	workers.CheckDataQuality(ctx, queries)
 
 	// Start background workers with errgroup for graceful shutdown
 	g, gCtx := errgroup.WithContext(ctx)
@@ -115,14 +117,32 @@ func Run(cfg *RuntimeConfig) error {
 		// Use default retry config (rate limiting is handled by waiting for window resets)
 		retryConfig := strava.DefaultRetryConfig()
 
+		// Every client below draws on one rate limit budget, carried over
+		// from the last run, so workers and tool calls cannot jointly
+		// overrun a window
+		governor := ratelimit.New(ctx, queries)
+		strava.SetGovernor(governor)
+		g.Go(func() error {
+			governor.Run(gCtx)
+			return nil
+		})
+
 		// Perform initial sync
 		if err := workers.SyncOnce(ctx, queries, accessToken, retryConfig); err != nil {
 			log.Warn().Err(err).Msg("initial sync failed")
 			// Continue anyway - background worker will retry
 		}
 
+		// Pick up the athlete's zone boundaries from their Strava profile
+		workers.SyncAthleteZones(ctx, queries, accessToken, retryConfig)
+
 		// Log database statistics after initial sync
 		workers.LogDatabaseStats(ctx, queries)
+		workers.DetectRoutes(ctx, queries)
+		workers.ComputeLocalZones(ctx, queries)
+		workers.AnalyzeThresholds(ctx, queries)
+		workers.ClassifyWorkouts(ctx, queries)
+		workers.CheckDataQuality(ctx, queries)
 
 		log.Info().Msg("starting background workers")
 
@@ -136,27 +156,66 @@ func Run(cfg *RuntimeConfig) error {
 			return nil
 		})
 
-		// Activity sync worker
-		activitySyncer := workers.NewActivitySyncer(
+		// Sync scheduler (queues activity pages, streams, laps, zones and
+		// optionally kudos and comments in the sync_jobs table and runs them
+		// within the rate limits)
+		syncScheduler := workers.NewSyncScheduler(
+			queries,
+			storage,
+			cfg.SyncInterval,
+			retryConfig,
+			cfg.SyncSocialLists,
+		)
+		g.Go(func() error {
+			syncScheduler.Run(gCtx)
+			return nil
+		})
+
+		// Social sync worker (refreshes kudos and comment counts)
+		socialSyncer := workers.NewSocialSyncer(
 			queries,
 			storage,
 			cfg.SyncInterval,
 			retryConfig,
 		)
 		g.Go(func() error {
-			activitySyncer.Run(gCtx)
+			socialSyncer.Run(gCtx)
 			return nil
 		})
 
-		// Zone sync worker (syncs heart rate and power zone data)
-		zoneSyncer := workers.NewZoneSyncer(
+		// Club sync worker (stores clubs and their activity feeds)
+		clubSyncer := workers.NewClubSyncer(
 			queries,
 			storage,
-			cfg.SyncInterval, // Use same interval as activity sync
+			cfg.SyncInterval,
 			retryConfig,
 		)
 		g.Go(func() error {
-			zoneSyncer.Run(gCtx)
+			clubSyncer.Run(gCtx)
+			return nil
+		})
+
+		// Route library sync worker (saved routes and starred segments)
+		librarySyncer := workers.NewLibrarySyncer(
+			queries,
+			storage,
+			workers.LibrarySyncInterval,
+			retryConfig,
+		)
+		g.Go(func() error {
+			librarySyncer.Run(gCtx)
+			return nil
+		})
+
+		// Sync integrity worker (cross-checks athlete stats and repairs gaps)
+		integrityChecker := workers.NewIntegrityChecker(
+			queries,
+			storage,
+			workers.IntegrityCheckInterval,
+			retryConfig,
+		)
+		g.Go(func() error {
+			integrityChecker.Run(gCtx)
 			return nil
 		})
 	} else {
@@ -165,6 +224,18 @@ func Run(cfg *RuntimeConfig) error {
 
 	// Start MCP server
 	srv := server.New(queries)
+	if !cfg.NoSync {
+		// Tools that change activities on Strava get a client with a fresh
+		// token for each change
+		storage := auth.NewStorage(queries)
+		srv.WithStrava(func() (server.StravaClient, error) {
+			accessToken, err := storage.GetValidAccessToken()
+			if err != nil {
+				return nil, err
+			}
+			return strava.NewClientWithRetryConfig(accessToken, strava.DefaultRetryConfig()).WithPriority(strava.PriorityInteractive), nil
+		})
+	}
 
 	var serverErr error
 	if cfg.MCPPort > 0 {
@@ -332,6 +403,27 @@ func runHTTPServer(ctx context.Context, mcpServer *mcp.Server, port int) error {
 	}
 }
 
+// runMigrations applies any pending goose migrations from sql/migrations
+func runMigrations(ctx context.Context, sqlDB *sql.DB) error {
+	log := logging.Logger
+
+	gooseProvider, err := goose.NewProvider(goose.DialectSQLite3, sqlDB, os.DirFS("sql/migrations"))
+	if err != nil {
+		return fmt.Errorf("creating goose provider: %w", err)
+	}
+
+	results, err := gooseProvider.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	for _, r := range results {
+		log.Debug().Int64("version", r.Source.Version).Str("path", r.Source.Path).Msg("migration applied")
+	}
+	log.Debug().Int("applied", len(results)).Msg("database migrations completed")
+	return nil
+}
+
 // configureSQLite sets up SQLite for concurrent access
 func configureSQLite(sqlDB *sql.DB) error {
 	log := logging.Logger
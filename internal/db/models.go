@@ -6,28 +6,127 @@ package db
 
 import (
 	"database/sql"
+	"time"
 )
 
 type Activity struct {
-	ID                 int64           `json:"id"`
-	Name               string          `json:"name"`
+	ID                  int64           `json:"id"`
+	Name                string          `json:"name"`
+	Distance            sql.NullFloat64 `json:"distance"`
+	MovingTime          sql.NullInt64   `json:"moving_time"`
+	ElapsedTime         sql.NullInt64   `json:"elapsed_time"`
+	TotalElevationGain  sql.NullFloat64 `json:"total_elevation_gain"`
+	Type                sql.NullString  `json:"type"`
+	SportType           sql.NullString  `json:"sport_type"`
+	StartDate           sql.NullTime    `json:"start_date"`
+	StartDateLocal      sql.NullTime    `json:"start_date_local"`
+	Timezone            sql.NullString  `json:"timezone"`
+	AverageSpeed        sql.NullFloat64 `json:"average_speed"`
+	MaxSpeed            sql.NullFloat64 `json:"max_speed"`
+	AverageCadence      sql.NullFloat64 `json:"average_cadence"`
+	AverageHeartrate    sql.NullFloat64 `json:"average_heartrate"`
+	MaxHeartrate        sql.NullFloat64 `json:"max_heartrate"`
+	Calories            sql.NullFloat64 `json:"calories"`
+	CreatedAt           sql.NullTime    `json:"created_at"`
+	UpdatedAt           sql.NullTime    `json:"updated_at"`
+	SummaryPolyline     sql.NullString  `json:"summary_polyline"`
+	StartLat            sql.NullFloat64 `json:"start_lat"`
+	StartLng            sql.NullFloat64 `json:"start_lng"`
+	EndLat              sql.NullFloat64 `json:"end_lat"`
+	EndLng              sql.NullFloat64 `json:"end_lng"`
+	WorkoutType         sql.NullInt64   `json:"workout_type"`
+	WorkoutCategory     sql.NullString  `json:"workout_category"`
+	WorkoutConfidence   sql.NullFloat64 `json:"workout_confidence"`
+	WorkoutClassifiedAt sql.NullTime    `json:"workout_classified_at"`
+}
+
+type ActivityComment struct {
+	ID          int64        `json:"id"`
+	ActivityID  int64        `json:"activity_id"`
+	AthleteName string       `json:"athlete_name"`
+	Text        string       `json:"text"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+}
+
+type ActivityFlag struct {
+	ID                int64         `json:"id"`
+	ActivityID        int64         `json:"activity_id"`
+	Code              string        `json:"code"`
+	Severity          string        `json:"severity"`
+	Detail            string        `json:"detail"`
+	RelatedActivityID sql.NullInt64 `json:"related_activity_id"`
+	DetectedAt        sql.NullTime  `json:"detected_at"`
+}
+
+type ActivityKudo struct {
+	ActivityID  int64  `json:"activity_id"`
+	Position    int64  `json:"position"`
+	AthleteName string `json:"athlete_name"`
+}
+
+type ActivityLap struct {
+	ActivityID         int64           `json:"activity_id"`
+	LapIndex           int64           `json:"lap_index"`
+	Name               sql.NullString  `json:"name"`
 	Distance           sql.NullFloat64 `json:"distance"`
 	MovingTime         sql.NullInt64   `json:"moving_time"`
 	ElapsedTime        sql.NullInt64   `json:"elapsed_time"`
-	TotalElevationGain sql.NullFloat64 `json:"total_elevation_gain"`
-	Type               sql.NullString  `json:"type"`
-	SportType          sql.NullString  `json:"sport_type"`
 	StartDate          sql.NullTime    `json:"start_date"`
-	StartDateLocal     sql.NullTime    `json:"start_date_local"`
-	Timezone           sql.NullString  `json:"timezone"`
+	TotalElevationGain sql.NullFloat64 `json:"total_elevation_gain"`
 	AverageSpeed       sql.NullFloat64 `json:"average_speed"`
 	MaxSpeed           sql.NullFloat64 `json:"max_speed"`
-	AverageCadence     sql.NullFloat64 `json:"average_cadence"`
 	AverageHeartrate   sql.NullFloat64 `json:"average_heartrate"`
 	MaxHeartrate       sql.NullFloat64 `json:"max_heartrate"`
-	Calories           sql.NullFloat64 `json:"calories"`
-	CreatedAt          sql.NullTime    `json:"created_at"`
-	UpdatedAt          sql.NullTime    `json:"updated_at"`
+	AverageCadence     sql.NullFloat64 `json:"average_cadence"`
+	AverageWatts       sql.NullFloat64 `json:"average_watts"`
+}
+
+type ActivityOverride struct {
+	ActivityID           int64           `json:"activity_id"`
+	Name                 sql.NullString  `json:"name"`
+	Type                 sql.NullString  `json:"type"`
+	Distance             sql.NullFloat64 `json:"distance"`
+	Counted              sql.NullInt64   `json:"counted"`
+	Reason               sql.NullString  `json:"reason"`
+	OriginalName         sql.NullString  `json:"original_name"`
+	OriginalType         sql.NullString  `json:"original_type"`
+	OriginalSportType    sql.NullString  `json:"original_sport_type"`
+	OriginalDistance     sql.NullFloat64 `json:"original_distance"`
+	OriginalAverageSpeed sql.NullFloat64 `json:"original_average_speed"`
+	CreatedAt            sql.NullTime    `json:"created_at"`
+	UpdatedAt            sql.NullTime    `json:"updated_at"`
+}
+
+type ActivityQualityCheck struct {
+	ActivityID int64        `json:"activity_id"`
+	CheckedAt  sql.NullTime `json:"checked_at"`
+}
+
+type ActivitySocial struct {
+	ActivityID        int64         `json:"activity_id"`
+	KudosCount        int64         `json:"kudos_count"`
+	CommentCount      int64         `json:"comment_count"`
+	AthleteCount      int64         `json:"athlete_count"`
+	ListsKudosCount   sql.NullInt64 `json:"lists_kudos_count"`
+	ListsCommentCount sql.NullInt64 `json:"lists_comment_count"`
+	ListsSyncedAt     sql.NullTime  `json:"lists_synced_at"`
+	UpdatedAt         sql.NullTime  `json:"updated_at"`
+}
+
+type ActivityStream struct {
+	ActivityID    int64          `json:"activity_id"`
+	PointCount    int64          `json:"point_count"`
+	TimeData      sql.NullString `json:"time_data"`
+	DistanceData  sql.NullString `json:"distance_data"`
+	LatlngData    sql.NullString `json:"latlng_data"`
+	AltitudeData  sql.NullString `json:"altitude_data"`
+	VelocityData  sql.NullString `json:"velocity_data"`
+	HeartrateData sql.NullString `json:"heartrate_data"`
+	CadenceData   sql.NullString `json:"cadence_data"`
+	WattsData     sql.NullString `json:"watts_data"`
+	GradeData     sql.NullString `json:"grade_data"`
+	MovingData    sql.NullString `json:"moving_data"`
+	FetchedAt     sql.NullTime   `json:"fetched_at"`
 }
 
 type ActivityZone struct {
@@ -36,6 +135,7 @@ type ActivityZone struct {
 	ZoneType    string       `json:"zone_type"`
 	SensorBased int64        `json:"sensor_based"`
 	CreatedAt   sql.NullTime `json:"created_at"`
+	Source      string       `json:"source"`
 }
 
 type AuthConfig struct {
@@ -49,6 +149,206 @@ type AuthConfig struct {
 	UpdatedAt    sql.NullTime   `json:"updated_at"`
 }
 
+type Club struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	SportType   sql.NullString `json:"sport_type"`
+	City        sql.NullString `json:"city"`
+	State       sql.NullString `json:"state"`
+	Country     sql.NullString `json:"country"`
+	Private     int64          `json:"private"`
+	MemberCount int64          `json:"member_count"`
+	Url         sql.NullString `json:"url"`
+	SyncedAt    sql.NullTime   `json:"synced_at"`
+}
+
+type ClubActivity struct {
+	ID                 int64          `json:"id"`
+	ClubID             int64          `json:"club_id"`
+	Fingerprint        string         `json:"fingerprint"`
+	AthleteName        string         `json:"athlete_name"`
+	Name               string         `json:"name"`
+	Type               sql.NullString `json:"type"`
+	SportType          sql.NullString `json:"sport_type"`
+	Distance           float64        `json:"distance"`
+	MovingTime         int64          `json:"moving_time"`
+	ElapsedTime        int64          `json:"elapsed_time"`
+	TotalElevationGain float64        `json:"total_elevation_gain"`
+	FirstSeenAt        time.Time      `json:"first_seen_at"`
+	Backfill           int64          `json:"backfill"`
+}
+
+type ExcludedActivity struct {
+	ActivityID int64 `json:"activity_id"`
+}
+
+type Race struct {
+	ID           int64          `json:"id"`
+	Name         string         `json:"name"`
+	RaceDate     time.Time      `json:"race_date"`
+	Distance     float64        `json:"distance"`
+	ActivityType string         `json:"activity_type"`
+	Priority     string         `json:"priority"`
+	GoalTime     sql.NullInt64  `json:"goal_time"`
+	Notes        sql.NullString `json:"notes"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+}
+
+type RateLimitBudget struct {
+	ID          int64     `json:"id"`
+	WindowStart time.Time `json:"window_start"`
+	WindowUsage int64     `json:"window_usage"`
+	WindowLimit int64     `json:"window_limit"`
+	DayStart    time.Time `json:"day_start"`
+	DailyUsage  int64     `json:"daily_usage"`
+	DailyLimit  int64     `json:"daily_limit"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RouteEffort struct {
+	ActivityID    int64   `json:"activity_id"`
+	RouteID       int64   `json:"route_id"`
+	ShapeDistance float64 `json:"shape_distance"`
+}
+
+type RoutesDetected struct {
+	ID                       int64          `json:"id"`
+	Name                     string         `json:"name"`
+	ActivityType             sql.NullString `json:"activity_type"`
+	RepresentativeActivityID int64          `json:"representative_activity_id"`
+	Polyline                 string         `json:"polyline"`
+	Distance                 float64        `json:"distance"`
+	StartLat                 float64        `json:"start_lat"`
+	StartLng                 float64        `json:"start_lng"`
+	EndLat                   float64        `json:"end_lat"`
+	EndLng                   float64        `json:"end_lng"`
+	CreatedAt                sql.NullTime   `json:"created_at"`
+}
+
+type SavedRoute struct {
+	ID                  int64          `json:"id"`
+	Name                string         `json:"name"`
+	Description         sql.NullString `json:"description"`
+	Type                sql.NullString `json:"type"`
+	SubType             sql.NullString `json:"sub_type"`
+	Distance            float64        `json:"distance"`
+	ElevationGain       float64        `json:"elevation_gain"`
+	EstimatedMovingTime int64          `json:"estimated_moving_time"`
+	Polyline            sql.NullString `json:"polyline"`
+	Private             int64          `json:"private"`
+	Starred             int64          `json:"starred"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	Gpx                 sql.NullString `json:"gpx"`
+	GpxUpdatedAt        sql.NullTime   `json:"gpx_updated_at"`
+	SyncedAt            time.Time      `json:"synced_at"`
+}
+
+type StarredSegment struct {
+	ID            int64           `json:"id"`
+	Name          string          `json:"name"`
+	ActivityType  sql.NullString  `json:"activity_type"`
+	Distance      float64         `json:"distance"`
+	AverageGrade  float64         `json:"average_grade"`
+	MaximumGrade  float64         `json:"maximum_grade"`
+	ElevationHigh float64         `json:"elevation_high"`
+	ElevationLow  float64         `json:"elevation_low"`
+	ClimbCategory int64           `json:"climb_category"`
+	City          sql.NullString  `json:"city"`
+	State         sql.NullString  `json:"state"`
+	Country       sql.NullString  `json:"country"`
+	Private       int64           `json:"private"`
+	StartLat      sql.NullFloat64 `json:"start_lat"`
+	StartLng      sql.NullFloat64 `json:"start_lng"`
+	EndLat        sql.NullFloat64 `json:"end_lat"`
+	EndLng        sql.NullFloat64 `json:"end_lng"`
+	PrElapsedTime sql.NullInt64   `json:"pr_elapsed_time"`
+	PrDate        sql.NullString  `json:"pr_date"`
+	PrActivityID  sql.NullInt64   `json:"pr_activity_id"`
+	EffortCount   int64           `json:"effort_count"`
+	SyncedAt      time.Time       `json:"synced_at"`
+}
+
+type SyncFailure struct {
+	ActivityID    int64          `json:"activity_id"`
+	ActivityName  sql.NullString `json:"activity_name"`
+	StartDate     sql.NullTime   `json:"start_date"`
+	Error         string         `json:"error"`
+	Attempts      int64          `json:"attempts"`
+	FirstFailedAt time.Time      `json:"first_failed_at"`
+	LastFailedAt  time.Time      `json:"last_failed_at"`
+}
+
+type SyncIntegrity struct {
+	Sport            string        `json:"sport"`
+	Period           string        `json:"period"`
+	WindowStart      sql.NullTime  `json:"window_start"`
+	StravaCount      int64         `json:"strava_count"`
+	LocalCount       int64         `json:"local_count"`
+	StravaDistance   float64       `json:"strava_distance"`
+	LocalDistance    float64       `json:"local_distance"`
+	StravaMovingTime int64         `json:"strava_moving_time"`
+	LocalMovingTime  int64         `json:"local_moving_time"`
+	RepairedMissing  sql.NullInt64 `json:"repaired_missing"`
+	CheckedAt        time.Time     `json:"checked_at"`
+}
+
+type SyncJob struct {
+	ID          int64          `json:"id"`
+	Kind        string         `json:"kind"`
+	Target      int64          `json:"target"`
+	AfterTime   sql.NullTime   `json:"after_time"`
+	Priority    int64          `json:"priority"`
+	Status      string         `json:"status"`
+	Attempts    int64          `json:"attempts"`
+	MaxAttempts int64          `json:"max_attempts"`
+	LastError   sql.NullString `json:"last_error"`
+	RunAfter    time.Time      `json:"run_after"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	FinishedAt  sql.NullTime   `json:"finished_at"`
+	BeforeTime  sql.NullTime   `json:"before_time"`
+}
+
+type SyncRepair struct {
+	ID          int64          `json:"id"`
+	Reason      string         `json:"reason"`
+	WindowStart sql.NullTime   `json:"window_start"`
+	WindowEnd   sql.NullTime   `json:"window_end"`
+	Fetched     int64          `json:"fetched"`
+	Added       int64          `json:"added"`
+	Failed      int64          `json:"failed"`
+	Error       sql.NullString `json:"error"`
+	RepairedAt  time.Time      `json:"repaired_at"`
+}
+
+type SyncState struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ThresholdEffort struct {
+	ActivityID     int64           `json:"activity_id"`
+	Sport          string          `json:"sport"`
+	StartDate      sql.NullTime    `json:"start_date"`
+	Lthr           sql.NullFloat64 `json:"lthr"`
+	Ftp            sql.NullFloat64 `json:"ftp"`
+	ThresholdSpeed sql.NullFloat64 `json:"threshold_speed"`
+	AnalyzedAt     sql.NullTime    `json:"analyzed_at"`
+}
+
+type ThresholdHistory struct {
+	ID            int64           `json:"id"`
+	Metric        string          `json:"metric"`
+	Sport         string          `json:"sport"`
+	Value         float64         `json:"value"`
+	PreviousValue sql.NullFloat64 `json:"previous_value"`
+	ActivityID    int64           `json:"activity_id"`
+	DetectedOn    time.Time       `json:"detected_on"`
+	CreatedAt     sql.NullTime    `json:"created_at"`
+}
+
 type ZoneBucket struct {
 	ID             int64 `json:"id"`
 	ActivityZoneID int64 `json:"activity_zone_id"`
@@ -57,3 +357,10 @@ type ZoneBucket struct {
 	MaxValue       int64 `json:"max_value"`
 	TimeSeconds    int64 `json:"time_seconds"`
 }
+
+type ZoneSetting struct {
+	ZoneType   string       `json:"zone_type"`
+	Boundaries string       `json:"boundaries"`
+	Source     string       `json:"source"`
+	UpdatedAt  sql.NullTime `json:"updated_at"`
+}
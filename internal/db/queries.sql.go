@@ -8,10 +8,173 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
+const addSyncRepairProgress = `-- name: AddSyncRepairProgress :exec
+UPDATE sync_repairs
+SET fetched = fetched + ?, added = added + ?, failed = failed + ?
+WHERE id = (
+    SELECT id FROM sync_repairs
+    WHERE window_start IS ? AND window_end = ?
+    ORDER BY id DESC
+    LIMIT 1
+)
+`
+
+type AddSyncRepairProgressParams struct {
+	Fetched     int64        `json:"fetched"`
+	Added       int64        `json:"added"`
+	Failed      int64        `json:"failed"`
+	WindowStart sql.NullTime `json:"window_start"`
+	WindowEnd   sql.NullTime `json:"window_end"`
+}
+
+func (q *Queries) AddSyncRepairProgress(ctx context.Context, arg AddSyncRepairProgressParams) error {
+	_, err := q.db.ExecContext(ctx, addSyncRepairProgress,
+		arg.Fetched,
+		arg.Added,
+		arg.Failed,
+		arg.WindowStart,
+		arg.WindowEnd,
+	)
+	return err
+}
+
+const applyActivityOverride = `-- name: ApplyActivityOverride :exec
+UPDATE activities SET
+    name = COALESCE(o.name, activities.name),
+    type = COALESCE(o.type, activities.type),
+    sport_type = COALESCE(o.type, activities.sport_type),
+    distance = COALESCE(o.distance, activities.distance),
+    average_speed = CASE WHEN o.distance IS NOT NULL AND activities.moving_time > 0
+                         THEN o.distance / activities.moving_time ELSE activities.average_speed END,
+    updated_at = CURRENT_TIMESTAMP
+FROM activity_overrides o
+WHERE o.activity_id = activities.id AND activities.id = ?
+`
+
+func (q *Queries) ApplyActivityOverride(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, applyActivityOverride, id)
+	return err
+}
+
+const claimSyncJob = `-- name: ClaimSyncJob :one
+UPDATE sync_jobs SET status = 'running', updated_at = ?
+WHERE id = (
+    SELECT id FROM sync_jobs
+    WHERE status = 'pending' AND run_after <= ?
+    ORDER BY priority DESC, run_after, id
+    LIMIT 1
+)
+RETURNING id, kind, target, after_time, priority, status, attempts, max_attempts, last_error, run_after, created_at, updated_at, finished_at, before_time
+`
+
+type ClaimSyncJobParams struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	RunAfter  time.Time `json:"run_after"`
+}
+
+func (q *Queries) ClaimSyncJob(ctx context.Context, arg ClaimSyncJobParams) (SyncJob, error) {
+	row := q.db.QueryRowContext(ctx, claimSyncJob, arg.UpdatedAt, arg.RunAfter)
+	var i SyncJob
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Target,
+		&i.AfterTime,
+		&i.Priority,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.LastError,
+		&i.RunAfter,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinishedAt,
+		&i.BeforeTime,
+	)
+	return i, err
+}
+
+const clearActivityCorrections = `-- name: ClearActivityCorrections :exec
+UPDATE activity_overrides SET
+    name = NULL, type = NULL, distance = NULL,
+    original_name = NULL, original_type = NULL, original_sport_type = NULL,
+    original_distance = NULL, original_average_speed = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE activity_id = ?
+`
+
+func (q *Queries) ClearActivityCorrections(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, clearActivityCorrections, activityID)
+	return err
+}
+
+const clearSyncFailure = `-- name: ClearSyncFailure :exec
+DELETE FROM sync_failures WHERE activity_id = ?
+`
+
+func (q *Queries) ClearSyncFailure(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, clearSyncFailure, activityID)
+	return err
+}
+
+const completeSyncJob = `-- name: CompleteSyncJob :exec
+UPDATE sync_jobs
+SET status = 'done', last_error = NULL, updated_at = ?, finished_at = ?
+WHERE id = ?
+`
+
+type CompleteSyncJobParams struct {
+	UpdatedAt  time.Time    `json:"updated_at"`
+	FinishedAt sql.NullTime `json:"finished_at"`
+	ID         int64        `json:"id"`
+}
+
+func (q *Queries) CompleteSyncJob(ctx context.Context, arg CompleteSyncJobParams) error {
+	_, err := q.db.ExecContext(ctx, completeSyncJob, arg.UpdatedAt, arg.FinishedAt, arg.ID)
+	return err
+}
+
+const countActiveDeltaPages = `-- name: CountActiveDeltaPages :one
+SELECT COUNT(*) FROM sync_jobs
+WHERE kind = 'list_page' AND after_time IS NOT NULL AND before_time IS NULL AND status IN ('pending', 'running')
+`
+
+func (q *Queries) CountActiveDeltaPages(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveDeltaPages)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActiveFullSyncPages = `-- name: CountActiveFullSyncPages :one
+SELECT COUNT(*) FROM sync_jobs
+WHERE kind = 'list_page' AND after_time IS NULL AND before_time IS NULL AND status IN ('pending', 'running')
+`
+
+func (q *Queries) CountActiveFullSyncPages(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveFullSyncPages)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActiveSyncJobs = `-- name: CountActiveSyncJobs :one
+SELECT COUNT(*) FROM sync_jobs WHERE kind = ? AND status IN ('pending', 'running')
+`
+
+func (q *Queries) CountActiveSyncJobs(ctx context.Context, kind string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveSyncJobs, kind)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countActivities = `-- name: CountActivities :one
 SELECT COUNT(*) FROM activities
+WHERE id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
 func (q *Queries) CountActivities(ctx context.Context) (int64, error) {
@@ -23,6 +186,7 @@ func (q *Queries) CountActivities(ctx context.Context) (int64, error) {
 
 const countActivitiesByType = `-- name: CountActivitiesByType :one
 SELECT COUNT(*) FROM activities WHERE type = ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
 func (q *Queries) CountActivitiesByType(ctx context.Context, type_ sql.NullString) (int64, error) {
@@ -34,6 +198,7 @@ func (q *Queries) CountActivitiesByType(ctx context.Context, type_ sql.NullStrin
 
 const countActivitiesByTypeInRange = `-- name: CountActivitiesByTypeInRange :one
 SELECT COUNT(*) FROM activities WHERE type = ? AND start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
 type CountActivitiesByTypeInRangeParams struct {
@@ -49,9 +214,47 @@ func (q *Queries) CountActivitiesByTypeInRange(ctx context.Context, arg CountAct
 	return count, err
 }
 
+const countActivitiesFiltered = `-- name: CountActivitiesFiltered :one
+SELECT COUNT(*) FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type CountActivitiesFilteredParams struct {
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+}
+
+func (q *Queries) CountActivitiesFiltered(ctx context.Context, arg CountActivitiesFilteredParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivitiesFiltered,
+		arg.Column1,
+		arg.Type,
+		arg.Column3,
+		arg.StartDate,
+		arg.Column5,
+		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countActivitiesInRange = `-- name: CountActivitiesInRange :one
 
-SELECT COUNT(*) FROM activities WHERE start_date >= ? AND start_date <= ?
+SELECT COUNT(*) FROM activities
+WHERE start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
 type CountActivitiesInRangeParams struct {
@@ -67,6 +270,17 @@ func (q *Queries) CountActivitiesInRange(ctx context.Context, arg CountActivitie
 	return count, err
 }
 
+const countActivitiesQualityChecked = `-- name: CountActivitiesQualityChecked :one
+SELECT COUNT(*) FROM activity_quality_checks
+`
+
+func (q *Queries) CountActivitiesQualityChecked(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivitiesQualityChecked)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countActivitiesWithZones = `-- name: CountActivitiesWithZones :one
 SELECT COUNT(DISTINCT activity_id) FROM activity_zones
 `
@@ -78,9 +292,45 @@ func (q *Queries) CountActivitiesWithZones(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countActivitiesWithoutRouteData = `-- name: CountActivitiesWithoutRouteData :one
+SELECT COUNT(*) FROM activities WHERE summary_polyline IS NULL
+`
+
+func (q *Queries) CountActivitiesWithoutRouteData(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivitiesWithoutRouteData)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActivitiesWithoutSocialCounts = `-- name: CountActivitiesWithoutSocialCounts :one
+SELECT COUNT(*) FROM activities
+WHERE id NOT IN (SELECT activity_id FROM activity_social)
+`
+
+func (q *Queries) CountActivitiesWithoutSocialCounts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivitiesWithoutSocialCounts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActivitiesWithoutStreams = `-- name: CountActivitiesWithoutStreams :one
+SELECT COUNT(*) FROM activities a
+LEFT JOIN activity_streams s ON a.id = s.activity_id
+WHERE s.activity_id IS NULL
+`
+
+func (q *Queries) CountActivitiesWithoutStreams(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivitiesWithoutStreams)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countActivitiesWithoutZones = `-- name: CountActivitiesWithoutZones :one
 SELECT COUNT(*) FROM activities a
-LEFT JOIN activity_zones az ON a.id = az.activity_id
+LEFT JOIN activity_zones az ON a.id = az.activity_id AND az.source = 'strava'
 WHERE az.id IS NULL
 `
 
@@ -91,35 +341,197 @@ func (q *Queries) CountActivitiesWithoutZones(ctx context.Context) (int64, error
 	return count, err
 }
 
+const countActivityFlagsByCode = `-- name: CountActivityFlagsByCode :many
+SELECT code, severity, COUNT(*) AS count FROM activity_flags
+GROUP BY code, severity
+ORDER BY severity, count DESC, code
+`
+
+type CountActivityFlagsByCodeRow struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Count    int64  `json:"count"`
+}
+
+func (q *Queries) CountActivityFlagsByCode(ctx context.Context) ([]CountActivityFlagsByCodeRow, error) {
+	rows, err := q.db.QueryContext(ctx, countActivityFlagsByCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountActivityFlagsByCodeRow{}
+	for rows.Next() {
+		var i CountActivityFlagsByCodeRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Severity,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countClubActivities = `-- name: CountClubActivities :one
+SELECT COUNT(*) FROM club_activities WHERE club_id = ?
+`
+
+func (q *Queries) CountClubActivities(ctx context.Context, clubID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countClubActivities, clubID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countClubBackfillActivities = `-- name: CountClubBackfillActivities :one
+SELECT COUNT(*) FROM club_activities
+WHERE club_id = ? AND backfill = 1 AND first_seen_at >= ?
+`
+
+type CountClubBackfillActivitiesParams struct {
+	ClubID      int64     `json:"club_id"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+}
+
+func (q *Queries) CountClubBackfillActivities(ctx context.Context, arg CountClubBackfillActivitiesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countClubBackfillActivities, arg.ClubID, arg.FirstSeenAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countDeadSyncJobsWithError = `-- name: CountDeadSyncJobsWithError :one
+SELECT COUNT(*) FROM sync_jobs WHERE kind = ? AND status = 'dead' AND last_error = ?
+`
+
+type CountDeadSyncJobsWithErrorParams struct {
+	Kind      string         `json:"kind"`
+	LastError sql.NullString `json:"last_error"`
+}
+
+func (q *Queries) CountDeadSyncJobsWithError(ctx context.Context, arg CountDeadSyncJobsWithErrorParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDeadSyncJobsWithError, arg.Kind, arg.LastError)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countExcludedActivities = `-- name: CountExcludedActivities :one
+SELECT COUNT(*) FROM excluded_activities
+`
+
+func (q *Queries) CountExcludedActivities(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countExcludedActivities)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countReadySyncJobs = `-- name: CountReadySyncJobs :one
+SELECT COUNT(*) FROM sync_jobs WHERE status = 'pending' AND run_after <= ?
+`
+
+func (q *Queries) CountReadySyncJobs(ctx context.Context, runAfter time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countReadySyncJobs, runAfter)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSocialListsSynced = `-- name: CountSocialListsSynced :one
+SELECT COUNT(*) FROM activity_social WHERE lists_synced_at IS NOT NULL
+`
+
+func (q *Queries) CountSocialListsSynced(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSocialListsSynced)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSyncJobsByStatus = `-- name: CountSyncJobsByStatus :many
+SELECT kind, status, COUNT(*) AS jobs FROM sync_jobs GROUP BY kind, status ORDER BY kind, status
+`
+
+type CountSyncJobsByStatusRow struct {
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	Jobs   int64  `json:"jobs"`
+}
+
+func (q *Queries) CountSyncJobsByStatus(ctx context.Context) ([]CountSyncJobsByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, countSyncJobsByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountSyncJobsByStatusRow{}
+	for rows.Next() {
+		var i CountSyncJobsByStatusRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.Status,
+			&i.Jobs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createActivity = `-- name: CreateActivity :exec
 INSERT INTO activities (
     id, name, distance, moving_time, elapsed_time, total_elevation_gain,
     type, sport_type, start_date, start_date_local, timezone,
     average_speed, max_speed, average_cadence, average_heartrate,
-    max_heartrate, calories, created_at, updated_at
+    max_heartrate, calories, summary_polyline,
+    start_lat, start_lng, end_lat, end_lng, workout_type, created_at, updated_at
 ) VALUES (
     ?, ?, ?, ?, ?, ?,
     ?, ?, ?, ?, ?,
     ?, ?, ?, ?,
-    ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+    ?, ?, ?,
+    ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
 ON CONFLICT(id) DO UPDATE SET
-    name = excluded.name,
-    distance = excluded.distance,
+    name = COALESCE((SELECT o.name FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.name),
+    distance = COALESCE((SELECT o.distance FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.distance),
     moving_time = excluded.moving_time,
     elapsed_time = excluded.elapsed_time,
     total_elevation_gain = excluded.total_elevation_gain,
-    type = excluded.type,
-    sport_type = excluded.sport_type,
+    type = COALESCE((SELECT o.type FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.type),
+    sport_type = COALESCE((SELECT o.type FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.sport_type),
     start_date = excluded.start_date,
     start_date_local = excluded.start_date_local,
     timezone = excluded.timezone,
-    average_speed = excluded.average_speed,
+    average_speed = COALESCE((SELECT o.distance / excluded.moving_time FROM activity_overrides o
+                              WHERE o.activity_id = excluded.id AND excluded.moving_time > 0), excluded.average_speed),
     max_speed = excluded.max_speed,
     average_cadence = excluded.average_cadence,
     average_heartrate = excluded.average_heartrate,
     max_heartrate = excluded.max_heartrate,
     calories = excluded.calories,
+    summary_polyline = excluded.summary_polyline,
+    start_lat = excluded.start_lat,
+    start_lng = excluded.start_lng,
+    end_lat = excluded.end_lat,
+    end_lng = excluded.end_lng,
+    workout_type = excluded.workout_type,
     updated_at = CURRENT_TIMESTAMP
 `
 
@@ -141,6 +553,12 @@ type CreateActivityParams struct {
 	AverageHeartrate   sql.NullFloat64 `json:"average_heartrate"`
 	MaxHeartrate       sql.NullFloat64 `json:"max_heartrate"`
 	Calories           sql.NullFloat64 `json:"calories"`
+	SummaryPolyline    sql.NullString  `json:"summary_polyline"`
+	StartLat           sql.NullFloat64 `json:"start_lat"`
+	StartLng           sql.NullFloat64 `json:"start_lng"`
+	EndLat             sql.NullFloat64 `json:"end_lat"`
+	EndLng             sql.NullFloat64 `json:"end_lng"`
+	WorkoutType        sql.NullInt64   `json:"workout_type"`
 }
 
 func (q *Queries) CreateActivity(ctx context.Context, arg CreateActivityParams) error {
@@ -162,538 +580,818 @@ func (q *Queries) CreateActivity(ctx context.Context, arg CreateActivityParams)
 		arg.AverageHeartrate,
 		arg.MaxHeartrate,
 		arg.Calories,
+		arg.SummaryPolyline,
+		arg.StartLat,
+		arg.StartLng,
+		arg.EndLat,
+		arg.EndLng,
+		arg.WorkoutType,
 	)
 	return err
 }
 
-const createActivityZone = `-- name: CreateActivityZone :one
-
-INSERT INTO activity_zones (activity_id, zone_type, sensor_based)
-VALUES (?, ?, ?)
-ON CONFLICT(activity_id, zone_type) DO UPDATE SET
-    sensor_based = excluded.sensor_based
-RETURNING id
+const createActivityComment = `-- name: CreateActivityComment :exec
+INSERT OR REPLACE INTO activity_comments (id, activity_id, athlete_name, text, created_at) VALUES (?, ?, ?, ?, ?)
 `
 
-type CreateActivityZoneParams struct {
-	ActivityID  int64  `json:"activity_id"`
-	ZoneType    string `json:"zone_type"`
-	SensorBased int64  `json:"sensor_based"`
+type CreateActivityCommentParams struct {
+	ID          int64        `json:"id"`
+	ActivityID  int64        `json:"activity_id"`
+	AthleteName string       `json:"athlete_name"`
+	Text        string       `json:"text"`
+	CreatedAt   sql.NullTime `json:"created_at"`
 }
 
-// Activity zone queries
-func (q *Queries) CreateActivityZone(ctx context.Context, arg CreateActivityZoneParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, createActivityZone, arg.ActivityID, arg.ZoneType, arg.SensorBased)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+func (q *Queries) CreateActivityComment(ctx context.Context, arg CreateActivityCommentParams) error {
+	_, err := q.db.ExecContext(ctx, createActivityComment,
+		arg.ID,
+		arg.ActivityID,
+		arg.AthleteName,
+		arg.Text,
+		arg.CreatedAt,
+	)
+	return err
 }
 
-const createZoneBucket = `-- name: CreateZoneBucket :exec
-INSERT INTO zone_buckets (activity_zone_id, zone_number, min_value, max_value, time_seconds)
+const createActivityFlag = `-- name: CreateActivityFlag :exec
+INSERT INTO activity_flags (activity_id, code, severity, detail, related_activity_id)
 VALUES (?, ?, ?, ?, ?)
 `
 
-type CreateZoneBucketParams struct {
-	ActivityZoneID int64 `json:"activity_zone_id"`
-	ZoneNumber     int64 `json:"zone_number"`
-	MinValue       int64 `json:"min_value"`
-	MaxValue       int64 `json:"max_value"`
-	TimeSeconds    int64 `json:"time_seconds"`
-}
-
-func (q *Queries) CreateZoneBucket(ctx context.Context, arg CreateZoneBucketParams) error {
-	_, err := q.db.ExecContext(ctx, createZoneBucket,
-		arg.ActivityZoneID,
-		arg.ZoneNumber,
-		arg.MinValue,
-		arg.MaxValue,
-		arg.TimeSeconds,
+type CreateActivityFlagParams struct {
+	ActivityID        int64         `json:"activity_id"`
+	Code              string        `json:"code"`
+	Severity          string        `json:"severity"`
+	Detail            string        `json:"detail"`
+	RelatedActivityID sql.NullInt64 `json:"related_activity_id"`
+}
+
+func (q *Queries) CreateActivityFlag(ctx context.Context, arg CreateActivityFlagParams) error {
+	_, err := q.db.ExecContext(ctx, createActivityFlag,
+		arg.ActivityID,
+		arg.Code,
+		arg.Severity,
+		arg.Detail,
+		arg.RelatedActivityID,
 	)
 	return err
 }
 
-const deleteAuthConfig = `-- name: DeleteAuthConfig :exec
-DELETE FROM auth_config WHERE id = 1
+const createActivityKudo = `-- name: CreateActivityKudo :exec
+INSERT INTO activity_kudos (activity_id, position, athlete_name) VALUES (?, ?, ?)
 `
 
-func (q *Queries) DeleteAuthConfig(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteAuthConfig)
+type CreateActivityKudoParams struct {
+	ActivityID  int64  `json:"activity_id"`
+	Position    int64  `json:"position"`
+	AthleteName string `json:"athlete_name"`
+}
+
+func (q *Queries) CreateActivityKudo(ctx context.Context, arg CreateActivityKudoParams) error {
+	_, err := q.db.ExecContext(ctx, createActivityKudo, arg.ActivityID, arg.Position, arg.AthleteName)
 	return err
 }
 
-const deleteZoneBucketsForActivityZone = `-- name: DeleteZoneBucketsForActivityZone :exec
-DELETE FROM zone_buckets WHERE activity_zone_id = ?
+const createActivityLap = `-- name: CreateActivityLap :exec
+INSERT INTO activity_laps (
+    activity_id, lap_index, name, distance, moving_time, elapsed_time, start_date, total_elevation_gain,
+    average_speed, max_speed, average_heartrate, max_heartrate, average_cadence, average_watts
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
-func (q *Queries) DeleteZoneBucketsForActivityZone(ctx context.Context, activityZoneID int64) error {
-	_, err := q.db.ExecContext(ctx, deleteZoneBucketsForActivityZone, activityZoneID)
+type CreateActivityLapParams struct {
+	ActivityID         int64           `json:"activity_id"`
+	LapIndex           int64           `json:"lap_index"`
+	Name               sql.NullString  `json:"name"`
+	Distance           sql.NullFloat64 `json:"distance"`
+	MovingTime         sql.NullInt64   `json:"moving_time"`
+	ElapsedTime        sql.NullInt64   `json:"elapsed_time"`
+	StartDate          sql.NullTime    `json:"start_date"`
+	TotalElevationGain sql.NullFloat64 `json:"total_elevation_gain"`
+	AverageSpeed       sql.NullFloat64 `json:"average_speed"`
+	MaxSpeed           sql.NullFloat64 `json:"max_speed"`
+	AverageHeartrate   sql.NullFloat64 `json:"average_heartrate"`
+	MaxHeartrate       sql.NullFloat64 `json:"max_heartrate"`
+	AverageCadence     sql.NullFloat64 `json:"average_cadence"`
+	AverageWatts       sql.NullFloat64 `json:"average_watts"`
+}
+
+func (q *Queries) CreateActivityLap(ctx context.Context, arg CreateActivityLapParams) error {
+	_, err := q.db.ExecContext(ctx, createActivityLap,
+		arg.ActivityID,
+		arg.LapIndex,
+		arg.Name,
+		arg.Distance,
+		arg.MovingTime,
+		arg.ElapsedTime,
+		arg.StartDate,
+		arg.TotalElevationGain,
+		arg.AverageSpeed,
+		arg.MaxSpeed,
+		arg.AverageHeartrate,
+		arg.MaxHeartrate,
+		arg.AverageCadence,
+		arg.AverageWatts,
+	)
 	return err
 }
 
-const getActivitiesByDateRange = `-- name: GetActivitiesByDateRange :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE start_date >= ? AND start_date <= ?
-ORDER BY start_date DESC
+const createActivityZone = `-- name: CreateActivityZone :one
+
+INSERT INTO activity_zones (activity_id, zone_type, sensor_based, source)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(activity_id, zone_type) DO UPDATE SET
+    sensor_based = excluded.sensor_based,
+    source = excluded.source
+RETURNING id
 `
 
-type GetActivitiesByDateRangeParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
+type CreateActivityZoneParams struct {
+	ActivityID  int64  `json:"activity_id"`
+	ZoneType    string `json:"zone_type"`
+	SensorBased int64  `json:"sensor_based"`
+	Source      string `json:"source"`
 }
 
-func (q *Queries) GetActivitiesByDateRange(ctx context.Context, arg GetActivitiesByDateRangeParams) ([]Activity, error) {
-	rows, err := q.db.QueryContext(ctx, getActivitiesByDateRange, arg.StartDate, arg.StartDate_2)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Activity{}
-	for rows.Next() {
-		var i Activity
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Distance,
-			&i.MovingTime,
-			&i.ElapsedTime,
-			&i.TotalElevationGain,
-			&i.Type,
-			&i.SportType,
-			&i.StartDate,
-			&i.StartDateLocal,
-			&i.Timezone,
-			&i.AverageSpeed,
-			&i.MaxSpeed,
-			&i.AverageCadence,
-			&i.AverageHeartrate,
-			&i.MaxHeartrate,
-			&i.Calories,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+// Activity zone queries
+func (q *Queries) CreateActivityZone(ctx context.Context, arg CreateActivityZoneParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createActivityZone,
+		arg.ActivityID,
+		arg.ZoneType,
+		arg.SensorBased,
+		arg.Source,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
 }
 
-const getActivitiesByType = `-- name: GetActivitiesByType :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities WHERE type = ? ORDER BY start_date DESC
+const createClubActivity = `-- name: CreateClubActivity :execrows
+INSERT OR IGNORE INTO club_activities (
+    club_id, fingerprint, athlete_name, name, type, sport_type, distance,
+    moving_time, elapsed_time, total_elevation_gain, first_seen_at, backfill
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
-func (q *Queries) GetActivitiesByType(ctx context.Context, type_ sql.NullString) ([]Activity, error) {
-	rows, err := q.db.QueryContext(ctx, getActivitiesByType, type_)
+type CreateClubActivityParams struct {
+	ClubID             int64          `json:"club_id"`
+	Fingerprint        string         `json:"fingerprint"`
+	AthleteName        string         `json:"athlete_name"`
+	Name               string         `json:"name"`
+	Type               sql.NullString `json:"type"`
+	SportType          sql.NullString `json:"sport_type"`
+	Distance           float64        `json:"distance"`
+	MovingTime         int64          `json:"moving_time"`
+	ElapsedTime        int64          `json:"elapsed_time"`
+	TotalElevationGain float64        `json:"total_elevation_gain"`
+	FirstSeenAt        time.Time      `json:"first_seen_at"`
+	Backfill           int64          `json:"backfill"`
+}
+
+func (q *Queries) CreateClubActivity(ctx context.Context, arg CreateClubActivityParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createClubActivity,
+		arg.ClubID,
+		arg.Fingerprint,
+		arg.AthleteName,
+		arg.Name,
+		arg.Type,
+		arg.SportType,
+		arg.Distance,
+		arg.MovingTime,
+		arg.ElapsedTime,
+		arg.TotalElevationGain,
+		arg.FirstSeenAt,
+		arg.Backfill,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Activity{}
-	for rows.Next() {
-		var i Activity
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Distance,
-			&i.MovingTime,
-			&i.ElapsedTime,
-			&i.TotalElevationGain,
-			&i.Type,
-			&i.SportType,
-			&i.StartDate,
-			&i.StartDateLocal,
-			&i.Timezone,
-			&i.AverageSpeed,
-			&i.MaxSpeed,
-			&i.AverageCadence,
-			&i.AverageHeartrate,
-			&i.MaxHeartrate,
-			&i.Calories,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const getActivitiesByTypeAndDateRange = `-- name: GetActivitiesByTypeAndDateRange :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities WHERE type = ? AND start_date >= ? AND start_date <= ? ORDER BY start_date DESC
+const createRace = `-- name: CreateRace :one
+INSERT INTO races (name, race_date, distance, activity_type, priority, goal_time, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, race_date, distance, activity_type, priority, goal_time, notes, created_at
 `
 
-type GetActivitiesByTypeAndDateRangeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
+type CreateRaceParams struct {
+	Name         string         `json:"name"`
+	RaceDate     time.Time      `json:"race_date"`
+	Distance     float64        `json:"distance"`
+	ActivityType string         `json:"activity_type"`
+	Priority     string         `json:"priority"`
+	GoalTime     sql.NullInt64  `json:"goal_time"`
+	Notes        sql.NullString `json:"notes"`
 }
 
-func (q *Queries) GetActivitiesByTypeAndDateRange(ctx context.Context, arg GetActivitiesByTypeAndDateRangeParams) ([]Activity, error) {
-	rows, err := q.db.QueryContext(ctx, getActivitiesByTypeAndDateRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Activity{}
-	for rows.Next() {
-		var i Activity
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Distance,
-			&i.MovingTime,
-			&i.ElapsedTime,
-			&i.TotalElevationGain,
-			&i.Type,
-			&i.SportType,
-			&i.StartDate,
-			&i.StartDateLocal,
-			&i.Timezone,
-			&i.AverageSpeed,
-			&i.MaxSpeed,
-			&i.AverageCadence,
-			&i.AverageHeartrate,
-			&i.MaxHeartrate,
-			&i.Calories,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) CreateRace(ctx context.Context, arg CreateRaceParams) (Race, error) {
+	row := q.db.QueryRowContext(ctx, createRace,
+		arg.Name,
+		arg.RaceDate,
+		arg.Distance,
+		arg.ActivityType,
+		arg.Priority,
+		arg.GoalTime,
+		arg.Notes,
+	)
+	var i Race
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.RaceDate,
+		&i.Distance,
+		&i.ActivityType,
+		&i.Priority,
+		&i.GoalTime,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const getActivitiesWithZones = `-- name: GetActivitiesWithZones :many
-SELECT DISTINCT a.id, a.name, a.type, a.sport_type, a.start_date
-FROM activities a
-JOIN activity_zones az ON a.id = az.activity_id
-ORDER BY a.start_date DESC
-LIMIT ?
+const createRouteEffort = `-- name: CreateRouteEffort :exec
+INSERT INTO route_efforts (activity_id, route_id, shape_distance)
+VALUES (?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    route_id = excluded.route_id,
+    shape_distance = excluded.shape_distance
 `
 
-type GetActivitiesWithZonesRow struct {
-	ID        int64          `json:"id"`
-	Name      string         `json:"name"`
-	Type      sql.NullString `json:"type"`
-	SportType sql.NullString `json:"sport_type"`
-	StartDate sql.NullTime   `json:"start_date"`
+type CreateRouteEffortParams struct {
+	ActivityID    int64   `json:"activity_id"`
+	RouteID       int64   `json:"route_id"`
+	ShapeDistance float64 `json:"shape_distance"`
 }
 
-func (q *Queries) GetActivitiesWithZones(ctx context.Context, limit int64) ([]GetActivitiesWithZonesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getActivitiesWithZones, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetActivitiesWithZonesRow{}
-	for rows.Next() {
-		var i GetActivitiesWithZonesRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Type,
-			&i.SportType,
-			&i.StartDate,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) CreateRouteEffort(ctx context.Context, arg CreateRouteEffortParams) error {
+	_, err := q.db.ExecContext(ctx, createRouteEffort, arg.ActivityID, arg.RouteID, arg.ShapeDistance)
+	return err
 }
 
-const getActivitiesWithoutZones = `-- name: GetActivitiesWithoutZones :many
-SELECT a.id FROM activities a
-LEFT JOIN activity_zones az ON a.id = az.activity_id
-WHERE az.id IS NULL
-ORDER BY a.start_date DESC
-LIMIT ?
+const createRoutesDetected = `-- name: CreateRoutesDetected :one
+
+INSERT INTO routes_detected (
+    name, activity_type, representative_activity_id, polyline, distance,
+    start_lat, start_lng, end_lat, end_lng
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id
 `
 
-func (q *Queries) GetActivitiesWithoutZones(ctx context.Context, limit int64) ([]int64, error) {
-	rows, err := q.db.QueryContext(ctx, getActivitiesWithoutZones, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []int64{}
-	for rows.Next() {
-		var id int64
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		items = append(items, id)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type CreateRoutesDetectedParams struct {
+	Name                     string         `json:"name"`
+	ActivityType             sql.NullString `json:"activity_type"`
+	RepresentativeActivityID int64          `json:"representative_activity_id"`
+	Polyline                 string         `json:"polyline"`
+	Distance                 float64        `json:"distance"`
+	StartLat                 float64        `json:"start_lat"`
+	StartLng                 float64        `json:"start_lng"`
+	EndLat                   float64        `json:"end_lat"`
+	EndLng                   float64        `json:"end_lng"`
+}
+
+// Detected route queries
+func (q *Queries) CreateRoutesDetected(ctx context.Context, arg CreateRoutesDetectedParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createRoutesDetected,
+		arg.Name,
+		arg.ActivityType,
+		arg.RepresentativeActivityID,
+		arg.Polyline,
+		arg.Distance,
+		arg.StartLat,
+		arg.StartLng,
+		arg.EndLat,
+		arg.EndLng,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
 }
 
-const getActivity = `-- name: GetActivity :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities WHERE id = ?
+const createSyncRepair = `-- name: CreateSyncRepair :exec
+INSERT INTO sync_repairs (
+    reason, window_start, window_end, fetched, added, failed, error, repaired_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
-func (q *Queries) GetActivity(ctx context.Context, id int64) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getActivity, id)
-	var i Activity
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+type CreateSyncRepairParams struct {
+	Reason      string         `json:"reason"`
+	WindowStart sql.NullTime   `json:"window_start"`
+	WindowEnd   sql.NullTime   `json:"window_end"`
+	Fetched     int64          `json:"fetched"`
+	Added       int64          `json:"added"`
+	Failed      int64          `json:"failed"`
+	Error       sql.NullString `json:"error"`
+	RepairedAt  time.Time      `json:"repaired_at"`
+}
+
+func (q *Queries) CreateSyncRepair(ctx context.Context, arg CreateSyncRepairParams) error {
+	_, err := q.db.ExecContext(ctx, createSyncRepair,
+		arg.Reason,
+		arg.WindowStart,
+		arg.WindowEnd,
+		arg.Fetched,
+		arg.Added,
+		arg.Failed,
+		arg.Error,
+		arg.RepairedAt,
 	)
-	return i, err
+	return err
 }
 
-const getActivityCountsByMonth = `-- name: GetActivityCountsByMonth :many
-SELECT 
-    strftime('%Y-%m', start_date) as month,
-    type,
-    COUNT(*) as count
-FROM activities 
-WHERE start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-%m', start_date), type
-ORDER BY month DESC, count DESC
+const createThresholdHistory = `-- name: CreateThresholdHistory :exec
+INSERT INTO threshold_history (metric, sport, value, previous_value, activity_id, detected_on)
+VALUES (?, ?, ?, ?, ?, ?)
 `
 
-type GetActivityCountsByMonthParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
+type CreateThresholdHistoryParams struct {
+	Metric        string          `json:"metric"`
+	Sport         string          `json:"sport"`
+	Value         float64         `json:"value"`
+	PreviousValue sql.NullFloat64 `json:"previous_value"`
+	ActivityID    int64           `json:"activity_id"`
+	DetectedOn    time.Time       `json:"detected_on"`
+}
+
+func (q *Queries) CreateThresholdHistory(ctx context.Context, arg CreateThresholdHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createThresholdHistory,
+		arg.Metric,
+		arg.Sport,
+		arg.Value,
+		arg.PreviousValue,
+		arg.ActivityID,
+		arg.DetectedOn,
+	)
+	return err
 }
 
-type GetActivityCountsByMonthRow struct {
-	Month interface{}    `json:"month"`
-	Type  sql.NullString `json:"type"`
-	Count int64          `json:"count"`
+const createZoneBucket = `-- name: CreateZoneBucket :exec
+INSERT INTO zone_buckets (activity_zone_id, zone_number, min_value, max_value, time_seconds)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateZoneBucketParams struct {
+	ActivityZoneID int64 `json:"activity_zone_id"`
+	ZoneNumber     int64 `json:"zone_number"`
+	MinValue       int64 `json:"min_value"`
+	MaxValue       int64 `json:"max_value"`
+	TimeSeconds    int64 `json:"time_seconds"`
 }
 
-func (q *Queries) GetActivityCountsByMonth(ctx context.Context, arg GetActivityCountsByMonthParams) ([]GetActivityCountsByMonthRow, error) {
-	rows, err := q.db.QueryContext(ctx, getActivityCountsByMonth, arg.StartDate, arg.StartDate_2)
+func (q *Queries) CreateZoneBucket(ctx context.Context, arg CreateZoneBucketParams) error {
+	_, err := q.db.ExecContext(ctx, createZoneBucket,
+		arg.ActivityZoneID,
+		arg.ZoneNumber,
+		arg.MinValue,
+		arg.MaxValue,
+		arg.TimeSeconds,
+	)
+	return err
+}
+
+const deadLetterPendingSyncJobs = `-- name: DeadLetterPendingSyncJobs :execrows
+UPDATE sync_jobs
+SET status = 'dead', last_error = ?, updated_at = ?, finished_at = ?
+WHERE kind = ? AND status = 'pending'
+`
+
+type DeadLetterPendingSyncJobsParams struct {
+	LastError  sql.NullString `json:"last_error"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	FinishedAt sql.NullTime   `json:"finished_at"`
+	Kind       string         `json:"kind"`
+}
+
+func (q *Queries) DeadLetterPendingSyncJobs(ctx context.Context, arg DeadLetterPendingSyncJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deadLetterPendingSyncJobs,
+		arg.LastError,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.Kind,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetActivityCountsByMonthRow{}
-	for rows.Next() {
-		var i GetActivityCountsByMonthRow
-		if err := rows.Scan(&i.Month, &i.Type, &i.Count); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const getActivityCountsByWeek = `-- name: GetActivityCountsByWeek :many
-SELECT 
-    strftime('%Y-W%W', start_date) as week,
-    type,
-    COUNT(*) as count
-FROM activities 
-WHERE start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-W%W', start_date), type
-ORDER BY week DESC, count DESC
+const deadLetterSyncJob = `-- name: DeadLetterSyncJob :exec
+UPDATE sync_jobs
+SET status = 'dead', attempts = attempts + 1, last_error = ?, updated_at = ?, finished_at = ?
+WHERE id = ?
 `
 
-type GetActivityCountsByWeekParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
+type DeadLetterSyncJobParams struct {
+	LastError  sql.NullString `json:"last_error"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	FinishedAt sql.NullTime   `json:"finished_at"`
+	ID         int64          `json:"id"`
 }
 
-type GetActivityCountsByWeekRow struct {
-	Week  interface{}    `json:"week"`
-	Type  sql.NullString `json:"type"`
-	Count int64          `json:"count"`
+func (q *Queries) DeadLetterSyncJob(ctx context.Context, arg DeadLetterSyncJobParams) error {
+	_, err := q.db.ExecContext(ctx, deadLetterSyncJob,
+		arg.LastError,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.ID,
+	)
+	return err
 }
 
-func (q *Queries) GetActivityCountsByWeek(ctx context.Context, arg GetActivityCountsByWeekParams) ([]GetActivityCountsByWeekRow, error) {
-	rows, err := q.db.QueryContext(ctx, getActivityCountsByWeek, arg.StartDate, arg.StartDate_2)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetActivityCountsByWeekRow{}
-	for rows.Next() {
-		var i GetActivityCountsByWeekRow
-		if err := rows.Scan(&i.Week, &i.Type, &i.Count); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const deleteActivityComments = `-- name: DeleteActivityComments :exec
+DELETE FROM activity_comments WHERE activity_id = ?
+`
+
+func (q *Queries) DeleteActivityComments(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteActivityComments, activityID)
+	return err
 }
 
-const getActivityTypeSummary = `-- name: GetActivityTypeSummary :many
-SELECT type, COUNT(*) as count FROM activities WHERE type IS NOT NULL GROUP BY type ORDER BY count DESC
+const deleteActivityFlags = `-- name: DeleteActivityFlags :exec
+DELETE FROM activity_flags WHERE activity_id = ?
 `
 
-type GetActivityTypeSummaryRow struct {
-	Type  sql.NullString `json:"type"`
-	Count int64          `json:"count"`
+func (q *Queries) DeleteActivityFlags(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteActivityFlags, activityID)
+	return err
 }
 
-func (q *Queries) GetActivityTypeSummary(ctx context.Context) ([]GetActivityTypeSummaryRow, error) {
-	rows, err := q.db.QueryContext(ctx, getActivityTypeSummary)
+const deleteActivityKudos = `-- name: DeleteActivityKudos :exec
+DELETE FROM activity_kudos WHERE activity_id = ?
+`
+
+func (q *Queries) DeleteActivityKudos(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteActivityKudos, activityID)
+	return err
+}
+
+const deleteActivityLaps = `-- name: DeleteActivityLaps :exec
+DELETE FROM activity_laps WHERE activity_id = ?
+`
+
+func (q *Queries) DeleteActivityLaps(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteActivityLaps, activityID)
+	return err
+}
+
+const deleteAuthConfig = `-- name: DeleteAuthConfig :exec
+DELETE FROM auth_config WHERE id = 1
+`
+
+func (q *Queries) DeleteAuthConfig(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteAuthConfig)
+	return err
+}
+
+const deleteEmptyActivityOverride = `-- name: DeleteEmptyActivityOverride :exec
+DELETE FROM activity_overrides
+WHERE activity_id = ? AND name IS NULL AND type IS NULL AND distance IS NULL AND counted IS NULL
+`
+
+func (q *Queries) DeleteEmptyActivityOverride(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEmptyActivityOverride, activityID)
+	return err
+}
+
+const deleteFinishedSyncJobs = `-- name: DeleteFinishedSyncJobs :execrows
+DELETE FROM sync_jobs
+WHERE status = 'done' AND kind IN ('list_page', 'activity') AND finished_at < ?
+`
+
+func (q *Queries) DeleteFinishedSyncJobs(ctx context.Context, finishedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFinishedSyncJobs, finishedAt)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetActivityTypeSummaryRow{}
-	for rows.Next() {
-		var i GetActivityTypeSummaryRow
-		if err := rows.Scan(&i.Type, &i.Count); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const getActivityTypeSummaryInRange = `-- name: GetActivityTypeSummaryInRange :many
-SELECT type, COUNT(*) as count FROM activities
-WHERE start_date >= ? AND start_date <= ? AND type IS NOT NULL
-GROUP BY type ORDER BY count DESC
+const deleteLocalActivityZones = `-- name: DeleteLocalActivityZones :exec
+DELETE FROM activity_zones WHERE source = 'local' AND zone_type = ?
 `
 
-type GetActivityTypeSummaryInRangeParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
+func (q *Queries) DeleteLocalActivityZones(ctx context.Context, zoneType string) error {
+	_, err := q.db.ExecContext(ctx, deleteLocalActivityZones, zoneType)
+	return err
 }
 
-type GetActivityTypeSummaryInRangeRow struct {
-	Type  sql.NullString `json:"type"`
-	Count int64          `json:"count"`
+const deleteLocalZoneBuckets = `-- name: DeleteLocalZoneBuckets :exec
+DELETE FROM zone_buckets WHERE activity_zone_id IN (
+    SELECT id FROM activity_zones WHERE source = 'local' AND zone_type = ?
+)
+`
+
+func (q *Queries) DeleteLocalZoneBuckets(ctx context.Context, zoneType string) error {
+	_, err := q.db.ExecContext(ctx, deleteLocalZoneBuckets, zoneType)
+	return err
 }
 
-func (q *Queries) GetActivityTypeSummaryInRange(ctx context.Context, arg GetActivityTypeSummaryInRangeParams) ([]GetActivityTypeSummaryInRangeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getActivityTypeSummaryInRange, arg.StartDate, arg.StartDate_2)
+const deleteRace = `-- name: DeleteRace :execrows
+DELETE FROM races WHERE id = ?
+`
+
+func (q *Queries) DeleteRace(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRace, id)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetActivityTypeSummaryInRangeRow{}
-	for rows.Next() {
-		var i GetActivityTypeSummaryInRangeRow
-		if err := rows.Scan(&i.Type, &i.Count); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const getActivityZoneByActivityAndType = `-- name: GetActivityZoneByActivityAndType :one
-SELECT id, activity_id, zone_type, sensor_based, created_at FROM activity_zones WHERE activity_id = ? AND zone_type = ?
+const deleteStaleClubActivities = `-- name: DeleteStaleClubActivities :exec
+DELETE FROM club_activities
+WHERE club_id IN (SELECT id FROM clubs WHERE synced_at < ?)
 `
 
-type GetActivityZoneByActivityAndTypeParams struct {
-	ActivityID int64  `json:"activity_id"`
-	ZoneType   string `json:"zone_type"`
+func (q *Queries) DeleteStaleClubActivities(ctx context.Context, syncedAt sql.NullTime) error {
+	_, err := q.db.ExecContext(ctx, deleteStaleClubActivities, syncedAt)
+	return err
 }
 
-func (q *Queries) GetActivityZoneByActivityAndType(ctx context.Context, arg GetActivityZoneByActivityAndTypeParams) (ActivityZone, error) {
-	row := q.db.QueryRowContext(ctx, getActivityZoneByActivityAndType, arg.ActivityID, arg.ZoneType)
-	var i ActivityZone
-	err := row.Scan(
-		&i.ID,
-		&i.ActivityID,
-		&i.ZoneType,
-		&i.SensorBased,
-		&i.CreatedAt,
-	)
-	return i, err
+const deleteStaleClubs = `-- name: DeleteStaleClubs :execrows
+DELETE FROM clubs WHERE synced_at < ?
+`
+
+func (q *Queries) DeleteStaleClubs(ctx context.Context, syncedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteStaleClubs, syncedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getActivityZones = `-- name: GetActivityZones :many
-SELECT id, activity_id, zone_type, sensor_based, created_at FROM activity_zones WHERE activity_id = ?
+const deleteStaleSavedRoutes = `-- name: DeleteStaleSavedRoutes :execrows
+DELETE FROM saved_routes WHERE synced_at < ?
 `
 
-func (q *Queries) GetActivityZones(ctx context.Context, activityID int64) ([]ActivityZone, error) {
-	rows, err := q.db.QueryContext(ctx, getActivityZones, activityID)
+func (q *Queries) DeleteStaleSavedRoutes(ctx context.Context, syncedAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteStaleSavedRoutes, syncedAt)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	defer rows.Close()
-	items := []ActivityZone{}
-	for rows.Next() {
-		var i ActivityZone
-		if err := rows.Scan(
-			&i.ID,
-			&i.ActivityID,
-			&i.ZoneType,
-			&i.SensorBased,
+	return result.RowsAffected()
+}
+
+const deleteStaleStarredSegments = `-- name: DeleteStaleStarredSegments :execrows
+DELETE FROM starred_segments WHERE synced_at < ?
+`
+
+func (q *Queries) DeleteStaleStarredSegments(ctx context.Context, syncedAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteStaleStarredSegments, syncedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteZoneBucketsForActivityZone = `-- name: DeleteZoneBucketsForActivityZone :exec
+DELETE FROM zone_buckets WHERE activity_zone_id = ?
+`
+
+func (q *Queries) DeleteZoneBucketsForActivityZone(ctx context.Context, activityZoneID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteZoneBucketsForActivityZone, activityZoneID)
+	return err
+}
+
+const deleteZoneSettingsBySource = `-- name: DeleteZoneSettingsBySource :exec
+DELETE FROM zone_settings WHERE source = ?
+`
+
+func (q *Queries) DeleteZoneSettingsBySource(ctx context.Context, source string) error {
+	_, err := q.db.ExecContext(ctx, deleteZoneSettingsBySource, source)
+	return err
+}
+
+const enqueueLapSyncJobs = `-- name: EnqueueLapSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'laps', a.id, ?, ?, ?, ?, ?
+FROM activities a
+WHERE NOT EXISTS (SELECT 1 FROM activity_laps l WHERE l.activity_id = a.id)
+  AND NOT EXISTS (SELECT 1 FROM sync_jobs j WHERE j.kind = 'laps' AND j.target = a.id)
+ORDER BY a.start_date DESC
+`
+
+type EnqueueLapSyncJobsParams struct {
+	Priority    int64     `json:"priority"`
+	MaxAttempts int64     `json:"max_attempts"`
+	RunAfter    time.Time `json:"run_after"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) EnqueueLapSyncJobs(ctx context.Context, arg EnqueueLapSyncJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enqueueLapSyncJobs,
+		arg.Priority,
+		arg.MaxAttempts,
+		arg.RunAfter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const enqueueSocialSyncJobs = `-- name: EnqueueSocialSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'social', s.activity_id, ?, ?, ?, ?, ?
+FROM activity_social s
+WHERE ((s.lists_kudos_count IS NULL AND (s.kudos_count > 0 OR s.comment_count > 0))
+       OR s.lists_kudos_count != s.kudos_count
+       OR s.lists_comment_count != s.comment_count)
+  AND NOT EXISTS (
+      SELECT 1 FROM sync_jobs j
+      WHERE j.kind = 'social' AND j.target = s.activity_id AND j.status IN ('pending', 'running', 'dead')
+  )
+ORDER BY s.activity_id DESC
+ON CONFLICT(kind, target, IFNULL(after_time, ''), IFNULL(before_time, '')) DO UPDATE SET
+    priority = excluded.priority,
+    status = 'pending',
+    attempts = 0,
+    max_attempts = excluded.max_attempts,
+    last_error = NULL,
+    run_after = excluded.run_after,
+    updated_at = excluded.updated_at,
+    finished_at = NULL
+`
+
+type EnqueueSocialSyncJobsParams struct {
+	Priority    int64     `json:"priority"`
+	MaxAttempts int64     `json:"max_attempts"`
+	RunAfter    time.Time `json:"run_after"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) EnqueueSocialSyncJobs(ctx context.Context, arg EnqueueSocialSyncJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enqueueSocialSyncJobs,
+		arg.Priority,
+		arg.MaxAttempts,
+		arg.RunAfter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const enqueueStreamSyncJobs = `-- name: EnqueueStreamSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'streams', a.id, ?, ?, ?, ?, ?
+FROM activities a
+WHERE NOT EXISTS (SELECT 1 FROM activity_streams s WHERE s.activity_id = a.id)
+  AND NOT EXISTS (SELECT 1 FROM sync_jobs j WHERE j.kind = 'streams' AND j.target = a.id)
+ORDER BY a.start_date DESC
+`
+
+type EnqueueStreamSyncJobsParams struct {
+	Priority    int64     `json:"priority"`
+	MaxAttempts int64     `json:"max_attempts"`
+	RunAfter    time.Time `json:"run_after"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) EnqueueStreamSyncJobs(ctx context.Context, arg EnqueueStreamSyncJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enqueueStreamSyncJobs,
+		arg.Priority,
+		arg.MaxAttempts,
+		arg.RunAfter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const enqueueSyncJob = `-- name: EnqueueSyncJob :exec
+INSERT INTO sync_jobs (
+    kind, target, after_time, before_time, priority, max_attempts, run_after, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(kind, target, IFNULL(after_time, ''), IFNULL(before_time, '')) DO UPDATE SET
+    priority = excluded.priority,
+    status = 'pending',
+    attempts = 0,
+    max_attempts = excluded.max_attempts,
+    last_error = NULL,
+    run_after = excluded.run_after,
+    updated_at = excluded.updated_at,
+    finished_at = NULL
+WHERE sync_jobs.status IN ('done', 'dead')
+`
+
+type EnqueueSyncJobParams struct {
+	Kind        string       `json:"kind"`
+	Target      int64        `json:"target"`
+	AfterTime   sql.NullTime `json:"after_time"`
+	BeforeTime  sql.NullTime `json:"before_time"`
+	Priority    int64        `json:"priority"`
+	MaxAttempts int64        `json:"max_attempts"`
+	RunAfter    time.Time    `json:"run_after"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+func (q *Queries) EnqueueSyncJob(ctx context.Context, arg EnqueueSyncJobParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueSyncJob,
+		arg.Kind,
+		arg.Target,
+		arg.AfterTime,
+		arg.BeforeTime,
+		arg.Priority,
+		arg.MaxAttempts,
+		arg.RunAfter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const enqueueZoneSyncJobs = `-- name: EnqueueZoneSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'zones', a.id, ?, ?, ?, ?, ?
+FROM activities a
+WHERE NOT EXISTS (SELECT 1 FROM activity_zones az WHERE az.activity_id = a.id AND az.source = 'strava')
+  AND NOT EXISTS (SELECT 1 FROM sync_jobs j WHERE j.kind = 'zones' AND j.target = a.id)
+ORDER BY a.start_date DESC
+`
+
+type EnqueueZoneSyncJobsParams struct {
+	Priority    int64     `json:"priority"`
+	MaxAttempts int64     `json:"max_attempts"`
+	RunAfter    time.Time `json:"run_after"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) EnqueueZoneSyncJobs(ctx context.Context, arg EnqueueZoneSyncJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enqueueZoneSyncJobs,
+		arg.Priority,
+		arg.MaxAttempts,
+		arg.RunAfter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getActivitiesByDateRange = `-- name: GetActivitiesByDateRange :many
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE start_date >= ? AND start_date <= ?
+ORDER BY start_date DESC
+`
+
+type GetActivitiesByDateRangeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+func (q *Queries) GetActivitiesByDateRange(ctx context.Context, arg GetActivitiesByDateRangeParams) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesByDateRange, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -708,12 +1406,12 @@ func (q *Queries) GetActivityZones(ctx context.Context, activityID int64) ([]Act
 	return items, nil
 }
 
-const getAllActivities = `-- name: GetAllActivities :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities ORDER BY start_date DESC
+const getActivitiesByType = `-- name: GetActivitiesByType :many
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities WHERE type = ? ORDER BY start_date DESC
 `
 
-func (q *Queries) GetAllActivities(ctx context.Context) ([]Activity, error) {
-	rows, err := q.db.QueryContext(ctx, getAllActivities)
+func (q *Queries) GetActivitiesByType(ctx context.Context, type_ sql.NullString) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesByType, type_)
 	if err != nil {
 		return nil, err
 	}
@@ -741,6 +1439,15 @@ func (q *Queries) GetAllActivities(ctx context.Context) ([]Activity, error) {
 			&i.Calories,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -755,23 +1462,58 @@ func (q *Queries) GetAllActivities(ctx context.Context) ([]Activity, error) {
 	return items, nil
 }
 
-const getAllActivityIDs = `-- name: GetAllActivityIDs :many
-SELECT id FROM activities
+const getActivitiesByTypeAndDateRange = `-- name: GetActivitiesByTypeAndDateRange :many
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities WHERE type = ? AND start_date >= ? AND start_date <= ? ORDER BY start_date DESC
 `
 
-func (q *Queries) GetAllActivityIDs(ctx context.Context) ([]int64, error) {
-	rows, err := q.db.QueryContext(ctx, getAllActivityIDs)
+type GetActivitiesByTypeAndDateRangeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+func (q *Queries) GetActivitiesByTypeAndDateRange(ctx context.Context, arg GetActivitiesByTypeAndDateRangeParams) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesByTypeAndDateRange, arg.Type, arg.StartDate, arg.StartDate_2)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []int64{}
+	items := []Activity{}
 	for rows.Next() {
-		var id int64
-		if err := rows.Scan(&id); err != nil {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -782,815 +1524,906 @@ func (q *Queries) GetAllActivityIDs(ctx context.Context) ([]int64, error) {
 	return items, nil
 }
 
-const getAuthConfig = `-- name: GetAuthConfig :one
-SELECT id, client_id, client_secret, access_token, refresh_token, expires_at, created_at, updated_at FROM auth_config WHERE id = 1
+const getActivitiesWithZones = `-- name: GetActivitiesWithZones :many
+SELECT DISTINCT a.id, a.name, a.type, a.sport_type, a.start_date
+FROM activities a
+JOIN activity_zones az ON a.id = az.activity_id
+ORDER BY a.start_date DESC
+LIMIT ?
 `
 
-func (q *Queries) GetAuthConfig(ctx context.Context) (AuthConfig, error) {
-	row := q.db.QueryRowContext(ctx, getAuthConfig)
-	var i AuthConfig
-	err := row.Scan(
-		&i.ID,
-		&i.ClientID,
-		&i.ClientSecret,
-		&i.AccessToken,
-		&i.RefreshToken,
-		&i.ExpiresAt,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type GetActivitiesWithZonesRow struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Type      sql.NullString `json:"type"`
+	SportType sql.NullString `json:"sport_type"`
+	StartDate sql.NullTime   `json:"start_date"`
 }
 
-const getCadenceSummary = `-- name: GetCadenceSummary :one
-SELECT 
-    COALESCE(AVG(average_cadence), 0) as avg_cadence,
-    COALESCE(MIN(average_cadence), 0) as min_cadence,
-    COALESCE(MAX(average_cadence), 0) as max_cadence,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE average_cadence IS NOT NULL AND average_cadence > 0
+func (q *Queries) GetActivitiesWithZones(ctx context.Context, limit int64) ([]GetActivitiesWithZonesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesWithZones, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivitiesWithZonesRow{}
+	for rows.Next() {
+		var i GetActivitiesWithZonesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActivitiesWithoutStreams = `-- name: GetActivitiesWithoutStreams :many
+SELECT a.id FROM activities a
+LEFT JOIN activity_streams s ON a.id = s.activity_id
+WHERE s.activity_id IS NULL
+ORDER BY a.start_date DESC
+LIMIT ?
 `
 
-type GetCadenceSummaryRow struct {
-	AvgCadence    interface{} `json:"avg_cadence"`
-	MinCadence    interface{} `json:"min_cadence"`
-	MaxCadence    interface{} `json:"max_cadence"`
-	ActivityCount int64       `json:"activity_count"`
+func (q *Queries) GetActivitiesWithoutStreams(ctx context.Context, limit int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesWithoutStreams, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) GetCadenceSummary(ctx context.Context) (GetCadenceSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getCadenceSummary)
-	var i GetCadenceSummaryRow
-	err := row.Scan(
-		&i.AvgCadence,
-		&i.MinCadence,
-		&i.MaxCadence,
-		&i.ActivityCount,
-	)
-	return i, err
-}
+const getActivitiesWithoutZones = `-- name: GetActivitiesWithoutZones :many
+SELECT a.id FROM activities a
+LEFT JOIN activity_zones az ON a.id = az.activity_id AND az.source = 'strava'
+WHERE az.id IS NULL
+ORDER BY a.start_date DESC
+LIMIT ?
+`
 
-const getCadenceSummaryByType = `-- name: GetCadenceSummaryByType :one
-SELECT 
-    COALESCE(AVG(average_cadence), 0) as avg_cadence,
-    COALESCE(MIN(average_cadence), 0) as min_cadence,
-    COALESCE(MAX(average_cadence), 0) as max_cadence,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE type = ? AND average_cadence IS NOT NULL AND average_cadence > 0
-`
-
-type GetCadenceSummaryByTypeRow struct {
-	AvgCadence    interface{} `json:"avg_cadence"`
-	MinCadence    interface{} `json:"min_cadence"`
-	MaxCadence    interface{} `json:"max_cadence"`
-	ActivityCount int64       `json:"activity_count"`
+func (q *Queries) GetActivitiesWithoutZones(ctx context.Context, limit int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesWithoutZones, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) GetCadenceSummaryByType(ctx context.Context, type_ sql.NullString) (GetCadenceSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getCadenceSummaryByType, type_)
-	var i GetCadenceSummaryByTypeRow
+const getActivity = `-- name: GetActivity :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities WHERE id = ?
+`
+
+func (q *Queries) GetActivity(ctx context.Context, id int64) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getActivity, id)
+	var i Activity
 	err := row.Scan(
-		&i.AvgCadence,
-		&i.MinCadence,
-		&i.MaxCadence,
-		&i.ActivityCount,
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
 	)
 	return i, err
 }
 
-const getCadenceSummaryByTypeInRange = `-- name: GetCadenceSummaryByTypeInRange :one
+const getActivityCountsByMonth = `-- name: GetActivityCountsByMonth :many
 SELECT 
-    COALESCE(AVG(average_cadence), 0) as avg_cadence,
-    COALESCE(MIN(average_cadence), 0) as min_cadence,
-    COALESCE(MAX(average_cadence), 0) as max_cadence,
-    COUNT(*) as activity_count
+    strftime('%Y-%m', substr(start_date, 1, 19)) as month,
+    type,
+    COUNT(*) as count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0
+WHERE start_date >= ? AND start_date <= ?
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-%m', substr(start_date, 1, 19)), type
+ORDER BY month DESC, count DESC
 `
 
-type GetCadenceSummaryByTypeInRangeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
+type GetActivityCountsByMonthParams struct {
+	StartDate       sql.NullTime   `json:"start_date"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column3         interface{}    `json:"column_3"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
 }
 
-type GetCadenceSummaryByTypeInRangeRow struct {
-	AvgCadence    interface{} `json:"avg_cadence"`
-	MinCadence    interface{} `json:"min_cadence"`
-	MaxCadence    interface{} `json:"max_cadence"`
-	ActivityCount int64       `json:"activity_count"`
+type GetActivityCountsByMonthRow struct {
+	Month interface{}    `json:"month"`
+	Type  sql.NullString `json:"type"`
+	Count int64          `json:"count"`
 }
 
-func (q *Queries) GetCadenceSummaryByTypeInRange(ctx context.Context, arg GetCadenceSummaryByTypeInRangeParams) (GetCadenceSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getCadenceSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetCadenceSummaryByTypeInRangeRow
-	err := row.Scan(
-		&i.AvgCadence,
-		&i.MinCadence,
-		&i.MaxCadence,
-		&i.ActivityCount,
+func (q *Queries) GetActivityCountsByMonth(ctx context.Context, arg GetActivityCountsByMonthParams) ([]GetActivityCountsByMonthRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivityCountsByMonth,
+		arg.StartDate,
+		arg.StartDate_2,
+		arg.Column3,
+		arg.WorkoutCategory,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivityCountsByMonthRow{}
+	for rows.Next() {
+		var i GetActivityCountsByMonthRow
+		if err := rows.Scan(&i.Month, &i.Type, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getCadenceSummaryInRange = `-- name: GetCadenceSummaryInRange :one
+const getActivityCountsByWeek = `-- name: GetActivityCountsByWeek :many
 SELECT 
-    COALESCE(AVG(average_cadence), 0) as avg_cadence,
-    COALESCE(MIN(average_cadence), 0) as min_cadence,
-    COALESCE(MAX(average_cadence), 0) as max_cadence,
-    COUNT(*) as activity_count
+    strftime('%Y-W%W', substr(start_date, 1, 19)) as week,
+    type,
+    COUNT(*) as count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0
+WHERE start_date >= ? AND start_date <= ?
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-W%W', substr(start_date, 1, 19)), type
+ORDER BY week DESC, count DESC
 `
 
-type GetCadenceSummaryInRangeParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
+type GetActivityCountsByWeekParams struct {
+	StartDate       sql.NullTime   `json:"start_date"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column3         interface{}    `json:"column_3"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
 }
 
-type GetCadenceSummaryInRangeRow struct {
-	AvgCadence    interface{} `json:"avg_cadence"`
-	MinCadence    interface{} `json:"min_cadence"`
-	MaxCadence    interface{} `json:"max_cadence"`
-	ActivityCount int64       `json:"activity_count"`
+type GetActivityCountsByWeekRow struct {
+	Week  interface{}    `json:"week"`
+	Type  sql.NullString `json:"type"`
+	Count int64          `json:"count"`
 }
 
-func (q *Queries) GetCadenceSummaryInRange(ctx context.Context, arg GetCadenceSummaryInRangeParams) (GetCadenceSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getCadenceSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetCadenceSummaryInRangeRow
-	err := row.Scan(
-		&i.AvgCadence,
-		&i.MinCadence,
-		&i.MaxCadence,
-		&i.ActivityCount,
+func (q *Queries) GetActivityCountsByWeek(ctx context.Context, arg GetActivityCountsByWeekParams) ([]GetActivityCountsByWeekRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivityCountsByWeek,
+		arg.StartDate,
+		arg.StartDate_2,
+		arg.Column3,
+		arg.WorkoutCategory,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivityCountsByWeekRow{}
+	for rows.Next() {
+		var i GetActivityCountsByWeekRow
+		if err := rows.Scan(&i.Week, &i.Type, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getCaloriesSummary = `-- name: GetCaloriesSummary :one
-
-SELECT 
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(AVG(calories), 0) as avg_calories,
-    COALESCE(MIN(calories), 0) as min_calories,
-    COALESCE(MAX(calories), 0) as max_calories,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE calories IS NOT NULL AND calories > 0
+const getActivityOverride = `-- name: GetActivityOverride :one
+SELECT activity_id, name, type, distance, counted, reason, original_name, original_type, original_sport_type, original_distance, original_average_speed, created_at, updated_at FROM activity_overrides WHERE activity_id = ?
 `
 
-type GetCaloriesSummaryRow struct {
-	TotalCalories interface{} `json:"total_calories"`
-	AvgCalories   interface{} `json:"avg_calories"`
-	MinCalories   interface{} `json:"min_calories"`
-	MaxCalories   interface{} `json:"max_calories"`
-	ActivityCount int64       `json:"activity_count"`
-}
-
-// Metrics aggregation queries
-func (q *Queries) GetCaloriesSummary(ctx context.Context) (GetCaloriesSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getCaloriesSummary)
-	var i GetCaloriesSummaryRow
+func (q *Queries) GetActivityOverride(ctx context.Context, activityID int64) (ActivityOverride, error) {
+	row := q.db.QueryRowContext(ctx, getActivityOverride, activityID)
+	var i ActivityOverride
 	err := row.Scan(
-		&i.TotalCalories,
-		&i.AvgCalories,
-		&i.MinCalories,
-		&i.MaxCalories,
-		&i.ActivityCount,
+		&i.ActivityID,
+		&i.Name,
+		&i.Type,
+		&i.Distance,
+		&i.Counted,
+		&i.Reason,
+		&i.OriginalName,
+		&i.OriginalType,
+		&i.OriginalSportType,
+		&i.OriginalDistance,
+		&i.OriginalAverageSpeed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getCaloriesSummaryByType = `-- name: GetCaloriesSummaryByType :one
-SELECT 
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(AVG(calories), 0) as avg_calories,
-    COALESCE(MIN(calories), 0) as min_calories,
-    COALESCE(MAX(calories), 0) as max_calories,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE type = ? AND calories IS NOT NULL AND calories > 0
+const getActivitySocialCounts = `-- name: GetActivitySocialCounts :one
+SELECT kudos_count, comment_count FROM activity_social WHERE activity_id = ?
 `
 
-type GetCaloriesSummaryByTypeRow struct {
-	TotalCalories interface{} `json:"total_calories"`
-	AvgCalories   interface{} `json:"avg_calories"`
-	MinCalories   interface{} `json:"min_calories"`
-	MaxCalories   interface{} `json:"max_calories"`
-	ActivityCount int64       `json:"activity_count"`
+type GetActivitySocialCountsRow struct {
+	KudosCount   int64 `json:"kudos_count"`
+	CommentCount int64 `json:"comment_count"`
 }
 
-func (q *Queries) GetCaloriesSummaryByType(ctx context.Context, type_ sql.NullString) (GetCaloriesSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getCaloriesSummaryByType, type_)
-	var i GetCaloriesSummaryByTypeRow
+func (q *Queries) GetActivitySocialCounts(ctx context.Context, activityID int64) (GetActivitySocialCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getActivitySocialCounts, activityID)
+	var i GetActivitySocialCountsRow
 	err := row.Scan(
-		&i.TotalCalories,
-		&i.AvgCalories,
-		&i.MinCalories,
-		&i.MaxCalories,
-		&i.ActivityCount,
+		&i.KudosCount,
+		&i.CommentCount,
 	)
 	return i, err
 }
 
-const getCaloriesSummaryByTypeInRange = `-- name: GetCaloriesSummaryByTypeInRange :one
-SELECT 
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(AVG(calories), 0) as avg_calories,
-    COALESCE(MIN(calories), 0) as min_calories,
-    COALESCE(MAX(calories), 0) as max_calories,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0
+const getActivityStreams = `-- name: GetActivityStreams :one
+SELECT activity_id, point_count, time_data, distance_data, latlng_data, altitude_data, velocity_data, heartrate_data, cadence_data, watts_data, grade_data, moving_data, fetched_at FROM activity_streams WHERE activity_id = ?
 `
 
-type GetCaloriesSummaryByTypeInRangeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
+func (q *Queries) GetActivityStreams(ctx context.Context, activityID int64) (ActivityStream, error) {
+	row := q.db.QueryRowContext(ctx, getActivityStreams, activityID)
+	var i ActivityStream
+	err := row.Scan(
+		&i.ActivityID,
+		&i.PointCount,
+		&i.TimeData,
+		&i.DistanceData,
+		&i.LatlngData,
+		&i.AltitudeData,
+		&i.VelocityData,
+		&i.HeartrateData,
+		&i.CadenceData,
+		&i.WattsData,
+		&i.GradeData,
+		&i.MovingData,
+		&i.FetchedAt,
+	)
+	return i, err
 }
 
-type GetCaloriesSummaryByTypeInRangeRow struct {
-	TotalCalories interface{} `json:"total_calories"`
-	AvgCalories   interface{} `json:"avg_calories"`
-	MinCalories   interface{} `json:"min_calories"`
-	MaxCalories   interface{} `json:"max_calories"`
-	ActivityCount int64       `json:"activity_count"`
+const getActivityTypeSummary = `-- name: GetActivityTypeSummary :many
+SELECT type, COUNT(*) as count FROM activities
+WHERE type IS NOT NULL
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY type ORDER BY count DESC
+`
+
+type GetActivityTypeSummaryRow struct {
+	Type  sql.NullString `json:"type"`
+	Count int64          `json:"count"`
 }
 
-func (q *Queries) GetCaloriesSummaryByTypeInRange(ctx context.Context, arg GetCaloriesSummaryByTypeInRangeParams) (GetCaloriesSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getCaloriesSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetCaloriesSummaryByTypeInRangeRow
-	err := row.Scan(
-		&i.TotalCalories,
-		&i.AvgCalories,
-		&i.MinCalories,
-		&i.MaxCalories,
-		&i.ActivityCount,
-	)
-	return i, err
+func (q *Queries) GetActivityTypeSummary(ctx context.Context) ([]GetActivityTypeSummaryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivityTypeSummary)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivityTypeSummaryRow{}
+	for rows.Next() {
+		var i GetActivityTypeSummaryRow
+		if err := rows.Scan(&i.Type, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getCaloriesSummaryInRange = `-- name: GetCaloriesSummaryInRange :one
-SELECT 
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(AVG(calories), 0) as avg_calories,
-    COALESCE(MIN(calories), 0) as min_calories,
-    COALESCE(MAX(calories), 0) as max_calories,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0
+const getActivityTypeSummaryInRange = `-- name: GetActivityTypeSummaryInRange :many
+SELECT type, COUNT(*) as count FROM activities
+WHERE start_date >= ? AND start_date <= ? AND type IS NOT NULL
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY type ORDER BY count DESC
 `
 
-type GetCaloriesSummaryInRangeParams struct {
+type GetActivityTypeSummaryInRangeParams struct {
 	StartDate   sql.NullTime `json:"start_date"`
 	StartDate_2 sql.NullTime `json:"start_date_2"`
 }
 
-type GetCaloriesSummaryInRangeRow struct {
-	TotalCalories interface{} `json:"total_calories"`
-	AvgCalories   interface{} `json:"avg_calories"`
-	MinCalories   interface{} `json:"min_calories"`
-	MaxCalories   interface{} `json:"max_calories"`
-	ActivityCount int64       `json:"activity_count"`
+type GetActivityTypeSummaryInRangeRow struct {
+	Type  sql.NullString `json:"type"`
+	Count int64          `json:"count"`
 }
 
-func (q *Queries) GetCaloriesSummaryInRange(ctx context.Context, arg GetCaloriesSummaryInRangeParams) (GetCaloriesSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getCaloriesSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetCaloriesSummaryInRangeRow
-	err := row.Scan(
-		&i.TotalCalories,
-		&i.AvgCalories,
-		&i.MinCalories,
-		&i.MaxCalories,
-		&i.ActivityCount,
-	)
-	return i, err
+func (q *Queries) GetActivityTypeSummaryInRange(ctx context.Context, arg GetActivityTypeSummaryInRangeParams) ([]GetActivityTypeSummaryInRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivityTypeSummaryInRange, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivityTypeSummaryInRangeRow{}
+	for rows.Next() {
+		var i GetActivityTypeSummaryInRangeRow
+		if err := rows.Scan(&i.Type, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getDistanceSummary = `-- name: GetDistanceSummary :one
-SELECT 
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(AVG(distance), 0) as avg_distance,
-    COALESCE(MIN(distance), 0) as min_distance,
-    COALESCE(MAX(distance), 0) as max_distance,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE distance IS NOT NULL AND distance > 0
+const getActivityZoneByActivityAndType = `-- name: GetActivityZoneByActivityAndType :one
+SELECT id, activity_id, zone_type, sensor_based, created_at, source FROM activity_zones WHERE activity_id = ? AND zone_type = ?
 `
 
-type GetDistanceSummaryRow struct {
-	TotalDistance interface{} `json:"total_distance"`
-	AvgDistance   interface{} `json:"avg_distance"`
-	MinDistance   interface{} `json:"min_distance"`
-	MaxDistance   interface{} `json:"max_distance"`
-	ActivityCount int64       `json:"activity_count"`
+type GetActivityZoneByActivityAndTypeParams struct {
+	ActivityID int64  `json:"activity_id"`
+	ZoneType   string `json:"zone_type"`
 }
 
-func (q *Queries) GetDistanceSummary(ctx context.Context) (GetDistanceSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getDistanceSummary)
-	var i GetDistanceSummaryRow
+func (q *Queries) GetActivityZoneByActivityAndType(ctx context.Context, arg GetActivityZoneByActivityAndTypeParams) (ActivityZone, error) {
+	row := q.db.QueryRowContext(ctx, getActivityZoneByActivityAndType, arg.ActivityID, arg.ZoneType)
+	var i ActivityZone
 	err := row.Scan(
-		&i.TotalDistance,
-		&i.AvgDistance,
-		&i.MinDistance,
-		&i.MaxDistance,
-		&i.ActivityCount,
+		&i.ID,
+		&i.ActivityID,
+		&i.ZoneType,
+		&i.SensorBased,
+		&i.CreatedAt,
+		&i.Source,
 	)
 	return i, err
 }
 
-const getDistanceSummaryByType = `-- name: GetDistanceSummaryByType :one
-SELECT 
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(AVG(distance), 0) as avg_distance,
-    COALESCE(MIN(distance), 0) as min_distance,
-    COALESCE(MAX(distance), 0) as max_distance,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE type = ? AND distance IS NOT NULL AND distance > 0
+const getActivityZones = `-- name: GetActivityZones :many
+SELECT id, activity_id, zone_type, sensor_based, created_at, source FROM activity_zones WHERE activity_id = ?
 `
 
-type GetDistanceSummaryByTypeRow struct {
-	TotalDistance interface{} `json:"total_distance"`
-	AvgDistance   interface{} `json:"avg_distance"`
-	MinDistance   interface{} `json:"min_distance"`
-	MaxDistance   interface{} `json:"max_distance"`
-	ActivityCount int64       `json:"activity_count"`
-}
-
-func (q *Queries) GetDistanceSummaryByType(ctx context.Context, type_ sql.NullString) (GetDistanceSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getDistanceSummaryByType, type_)
-	var i GetDistanceSummaryByTypeRow
-	err := row.Scan(
-		&i.TotalDistance,
-		&i.AvgDistance,
-		&i.MinDistance,
-		&i.MaxDistance,
-		&i.ActivityCount,
-	)
-	return i, err
+func (q *Queries) GetActivityZones(ctx context.Context, activityID int64) ([]ActivityZone, error) {
+	rows, err := q.db.QueryContext(ctx, getActivityZones, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ActivityZone{}
+	for rows.Next() {
+		var i ActivityZone
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActivityID,
+			&i.ZoneType,
+			&i.SensorBased,
+			&i.CreatedAt,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getDistanceSummaryByTypeInRange = `-- name: GetDistanceSummaryByTypeInRange :one
-SELECT 
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(AVG(distance), 0) as avg_distance,
-    COALESCE(MIN(distance), 0) as min_distance,
-    COALESCE(MAX(distance), 0) as max_distance,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0
+const getAllActivities = `-- name: GetAllActivities :many
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities ORDER BY start_date DESC
 `
 
-type GetDistanceSummaryByTypeInRangeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
+func (q *Queries) GetAllActivities(ctx context.Context) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, getAllActivities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-type GetDistanceSummaryByTypeInRangeRow struct {
-	TotalDistance interface{} `json:"total_distance"`
-	AvgDistance   interface{} `json:"avg_distance"`
-	MinDistance   interface{} `json:"min_distance"`
-	MaxDistance   interface{} `json:"max_distance"`
-	ActivityCount int64       `json:"activity_count"`
-}
+const getAllActivityIDs = `-- name: GetAllActivityIDs :many
+SELECT id FROM activities
+`
 
-func (q *Queries) GetDistanceSummaryByTypeInRange(ctx context.Context, arg GetDistanceSummaryByTypeInRangeParams) (GetDistanceSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getDistanceSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetDistanceSummaryByTypeInRangeRow
-	err := row.Scan(
-		&i.TotalDistance,
-		&i.AvgDistance,
-		&i.MinDistance,
-		&i.MaxDistance,
-		&i.ActivityCount,
-	)
-	return i, err
+func (q *Queries) GetAllActivityIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getAllActivityIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getDistanceSummaryInRange = `-- name: GetDistanceSummaryInRange :one
-SELECT 
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(AVG(distance), 0) as avg_distance,
-    COALESCE(MIN(distance), 0) as min_distance,
-    COALESCE(MAX(distance), 0) as max_distance,
-    COUNT(*) as activity_count
-FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0
+const getAuthConfig = `-- name: GetAuthConfig :one
+SELECT id, client_id, client_secret, access_token, refresh_token, expires_at, created_at, updated_at FROM auth_config WHERE id = 1
 `
 
-type GetDistanceSummaryInRangeParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
-}
-
-type GetDistanceSummaryInRangeRow struct {
-	TotalDistance interface{} `json:"total_distance"`
-	AvgDistance   interface{} `json:"avg_distance"`
-	MinDistance   interface{} `json:"min_distance"`
-	MaxDistance   interface{} `json:"max_distance"`
-	ActivityCount int64       `json:"activity_count"`
-}
-
-func (q *Queries) GetDistanceSummaryInRange(ctx context.Context, arg GetDistanceSummaryInRangeParams) (GetDistanceSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getDistanceSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetDistanceSummaryInRangeRow
+func (q *Queries) GetAuthConfig(ctx context.Context) (AuthConfig, error) {
+	row := q.db.QueryRowContext(ctx, getAuthConfig)
+	var i AuthConfig
 	err := row.Scan(
-		&i.TotalDistance,
-		&i.AvgDistance,
-		&i.MinDistance,
-		&i.MaxDistance,
-		&i.ActivityCount,
+		&i.ID,
+		&i.ClientID,
+		&i.ClientSecret,
+		&i.AccessToken,
+		&i.RefreshToken,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getDurationSummary = `-- name: GetDurationSummary :one
+const getCadenceSummary = `-- name: GetCadenceSummary :one
 SELECT 
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(moving_time), 0) as avg_moving_time,
-    COALESCE(MIN(moving_time), 0) as min_moving_time,
-    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COALESCE(AVG(average_cadence), 0) as avg_cadence,
+    COALESCE(MIN(average_cadence), 0) as min_cadence,
+    COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE moving_time IS NOT NULL AND moving_time > 0
+WHERE average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetDurationSummaryRow struct {
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgMovingTime   interface{} `json:"avg_moving_time"`
-	MinMovingTime   interface{} `json:"min_moving_time"`
-	MaxMovingTime   interface{} `json:"max_moving_time"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetCadenceSummaryRow struct {
+	AvgCadence    interface{} `json:"avg_cadence"`
+	MinCadence    interface{} `json:"min_cadence"`
+	MaxCadence    interface{} `json:"max_cadence"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetDurationSummary(ctx context.Context) (GetDurationSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getDurationSummary)
-	var i GetDurationSummaryRow
+func (q *Queries) GetCadenceSummary(ctx context.Context) (GetCadenceSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getCadenceSummary)
+	var i GetCadenceSummaryRow
 	err := row.Scan(
-		&i.TotalMovingTime,
-		&i.AvgMovingTime,
-		&i.MinMovingTime,
-		&i.MaxMovingTime,
+		&i.AvgCadence,
+		&i.MinCadence,
+		&i.MaxCadence,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getDurationSummaryByType = `-- name: GetDurationSummaryByType :one
+const getCadenceSummaryByType = `-- name: GetCadenceSummaryByType :one
 SELECT 
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(moving_time), 0) as avg_moving_time,
-    COALESCE(MIN(moving_time), 0) as min_moving_time,
-    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COALESCE(AVG(average_cadence), 0) as avg_cadence,
+    COALESCE(MIN(average_cadence), 0) as min_cadence,
+    COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND moving_time IS NOT NULL AND moving_time > 0
+WHERE type = ? AND average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetDurationSummaryByTypeRow struct {
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgMovingTime   interface{} `json:"avg_moving_time"`
-	MinMovingTime   interface{} `json:"min_moving_time"`
-	MaxMovingTime   interface{} `json:"max_moving_time"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetCadenceSummaryByTypeRow struct {
+	AvgCadence    interface{} `json:"avg_cadence"`
+	MinCadence    interface{} `json:"min_cadence"`
+	MaxCadence    interface{} `json:"max_cadence"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetDurationSummaryByType(ctx context.Context, type_ sql.NullString) (GetDurationSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getDurationSummaryByType, type_)
-	var i GetDurationSummaryByTypeRow
+func (q *Queries) GetCadenceSummaryByType(ctx context.Context, type_ sql.NullString) (GetCadenceSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getCadenceSummaryByType, type_)
+	var i GetCadenceSummaryByTypeRow
 	err := row.Scan(
-		&i.TotalMovingTime,
-		&i.AvgMovingTime,
-		&i.MinMovingTime,
-		&i.MaxMovingTime,
+		&i.AvgCadence,
+		&i.MinCadence,
+		&i.MaxCadence,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getDurationSummaryByTypeInRange = `-- name: GetDurationSummaryByTypeInRange :one
+const getCadenceSummaryByTypeInRange = `-- name: GetCadenceSummaryByTypeInRange :one
 SELECT 
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(moving_time), 0) as avg_moving_time,
-    COALESCE(MIN(moving_time), 0) as min_moving_time,
-    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COALESCE(AVG(average_cadence), 0) as avg_cadence,
+    COALESCE(MIN(average_cadence), 0) as min_cadence,
+    COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetDurationSummaryByTypeInRangeParams struct {
+type GetCadenceSummaryByTypeInRangeParams struct {
 	Type        sql.NullString `json:"type"`
 	StartDate   sql.NullTime   `json:"start_date"`
 	StartDate_2 sql.NullTime   `json:"start_date_2"`
 }
 
-type GetDurationSummaryByTypeInRangeRow struct {
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgMovingTime   interface{} `json:"avg_moving_time"`
-	MinMovingTime   interface{} `json:"min_moving_time"`
-	MaxMovingTime   interface{} `json:"max_moving_time"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetCadenceSummaryByTypeInRangeRow struct {
+	AvgCadence    interface{} `json:"avg_cadence"`
+	MinCadence    interface{} `json:"min_cadence"`
+	MaxCadence    interface{} `json:"max_cadence"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetDurationSummaryByTypeInRange(ctx context.Context, arg GetDurationSummaryByTypeInRangeParams) (GetDurationSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getDurationSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetDurationSummaryByTypeInRangeRow
+func (q *Queries) GetCadenceSummaryByTypeInRange(ctx context.Context, arg GetCadenceSummaryByTypeInRangeParams) (GetCadenceSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getCadenceSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetCadenceSummaryByTypeInRangeRow
 	err := row.Scan(
-		&i.TotalMovingTime,
-		&i.AvgMovingTime,
-		&i.MinMovingTime,
-		&i.MaxMovingTime,
+		&i.AvgCadence,
+		&i.MinCadence,
+		&i.MaxCadence,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getDurationSummaryInRange = `-- name: GetDurationSummaryInRange :one
+const getCadenceSummaryInRange = `-- name: GetCadenceSummaryInRange :one
 SELECT 
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(moving_time), 0) as avg_moving_time,
-    COALESCE(MIN(moving_time), 0) as min_moving_time,
-    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COALESCE(AVG(average_cadence), 0) as avg_cadence,
+    COALESCE(MIN(average_cadence), 0) as min_cadence,
+    COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0
+WHERE start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetDurationSummaryInRangeParams struct {
+type GetCadenceSummaryInRangeParams struct {
 	StartDate   sql.NullTime `json:"start_date"`
 	StartDate_2 sql.NullTime `json:"start_date_2"`
 }
 
-type GetDurationSummaryInRangeRow struct {
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgMovingTime   interface{} `json:"avg_moving_time"`
-	MinMovingTime   interface{} `json:"min_moving_time"`
-	MaxMovingTime   interface{} `json:"max_moving_time"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetCadenceSummaryInRangeRow struct {
+	AvgCadence    interface{} `json:"avg_cadence"`
+	MinCadence    interface{} `json:"min_cadence"`
+	MaxCadence    interface{} `json:"max_cadence"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetDurationSummaryInRange(ctx context.Context, arg GetDurationSummaryInRangeParams) (GetDurationSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getDurationSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetDurationSummaryInRangeRow
+func (q *Queries) GetCadenceSummaryInRange(ctx context.Context, arg GetCadenceSummaryInRangeParams) (GetCadenceSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getCadenceSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetCadenceSummaryInRangeRow
 	err := row.Scan(
-		&i.TotalMovingTime,
-		&i.AvgMovingTime,
-		&i.MinMovingTime,
-		&i.MaxMovingTime,
+		&i.AvgCadence,
+		&i.MinCadence,
+		&i.MaxCadence,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getElevationSummary = `-- name: GetElevationSummary :one
+const getCaloriesSummary = `-- name: GetCaloriesSummary :one
+
 SELECT 
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
-    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
-    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
-    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(AVG(calories), 0) as avg_calories,
+    COALESCE(MIN(calories), 0) as min_calories,
+    COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+WHERE calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetElevationSummaryRow struct {
-	TotalElevation interface{} `json:"total_elevation"`
-	AvgElevation   interface{} `json:"avg_elevation"`
-	MinElevation   interface{} `json:"min_elevation"`
-	MaxElevation   interface{} `json:"max_elevation"`
-	ActivityCount  int64       `json:"activity_count"`
+type GetCaloriesSummaryRow struct {
+	TotalCalories interface{} `json:"total_calories"`
+	AvgCalories   interface{} `json:"avg_calories"`
+	MinCalories   interface{} `json:"min_calories"`
+	MaxCalories   interface{} `json:"max_calories"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetElevationSummary(ctx context.Context) (GetElevationSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getElevationSummary)
-	var i GetElevationSummaryRow
+// Metrics aggregation queries
+func (q *Queries) GetCaloriesSummary(ctx context.Context) (GetCaloriesSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getCaloriesSummary)
+	var i GetCaloriesSummaryRow
 	err := row.Scan(
-		&i.TotalElevation,
-		&i.AvgElevation,
-		&i.MinElevation,
-		&i.MaxElevation,
-		&i.ActivityCount,
-	)
-	return i, err
+		&i.TotalCalories,
+		&i.AvgCalories,
+		&i.MinCalories,
+		&i.MaxCalories,
+		&i.ActivityCount,
+	)
+	return i, err
 }
 
-const getElevationSummaryByType = `-- name: GetElevationSummaryByType :one
+const getCaloriesSummaryByType = `-- name: GetCaloriesSummaryByType :one
 SELECT 
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
-    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
-    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
-    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(AVG(calories), 0) as avg_calories,
+    COALESCE(MIN(calories), 0) as min_calories,
+    COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+WHERE type = ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetElevationSummaryByTypeRow struct {
-	TotalElevation interface{} `json:"total_elevation"`
-	AvgElevation   interface{} `json:"avg_elevation"`
-	MinElevation   interface{} `json:"min_elevation"`
-	MaxElevation   interface{} `json:"max_elevation"`
-	ActivityCount  int64       `json:"activity_count"`
+type GetCaloriesSummaryByTypeRow struct {
+	TotalCalories interface{} `json:"total_calories"`
+	AvgCalories   interface{} `json:"avg_calories"`
+	MinCalories   interface{} `json:"min_calories"`
+	MaxCalories   interface{} `json:"max_calories"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetElevationSummaryByType(ctx context.Context, type_ sql.NullString) (GetElevationSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getElevationSummaryByType, type_)
-	var i GetElevationSummaryByTypeRow
+func (q *Queries) GetCaloriesSummaryByType(ctx context.Context, type_ sql.NullString) (GetCaloriesSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getCaloriesSummaryByType, type_)
+	var i GetCaloriesSummaryByTypeRow
 	err := row.Scan(
-		&i.TotalElevation,
-		&i.AvgElevation,
-		&i.MinElevation,
-		&i.MaxElevation,
+		&i.TotalCalories,
+		&i.AvgCalories,
+		&i.MinCalories,
+		&i.MaxCalories,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getElevationSummaryByTypeInRange = `-- name: GetElevationSummaryByTypeInRange :one
+const getCaloriesSummaryByTypeInRange = `-- name: GetCaloriesSummaryByTypeInRange :one
 SELECT 
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
-    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
-    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
-    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(AVG(calories), 0) as avg_calories,
+    COALESCE(MIN(calories), 0) as min_calories,
+    COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetElevationSummaryByTypeInRangeParams struct {
+type GetCaloriesSummaryByTypeInRangeParams struct {
 	Type        sql.NullString `json:"type"`
 	StartDate   sql.NullTime   `json:"start_date"`
 	StartDate_2 sql.NullTime   `json:"start_date_2"`
 }
 
-type GetElevationSummaryByTypeInRangeRow struct {
-	TotalElevation interface{} `json:"total_elevation"`
-	AvgElevation   interface{} `json:"avg_elevation"`
-	MinElevation   interface{} `json:"min_elevation"`
-	MaxElevation   interface{} `json:"max_elevation"`
-	ActivityCount  int64       `json:"activity_count"`
+type GetCaloriesSummaryByTypeInRangeRow struct {
+	TotalCalories interface{} `json:"total_calories"`
+	AvgCalories   interface{} `json:"avg_calories"`
+	MinCalories   interface{} `json:"min_calories"`
+	MaxCalories   interface{} `json:"max_calories"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetElevationSummaryByTypeInRange(ctx context.Context, arg GetElevationSummaryByTypeInRangeParams) (GetElevationSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getElevationSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetElevationSummaryByTypeInRangeRow
+func (q *Queries) GetCaloriesSummaryByTypeInRange(ctx context.Context, arg GetCaloriesSummaryByTypeInRangeParams) (GetCaloriesSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getCaloriesSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetCaloriesSummaryByTypeInRangeRow
 	err := row.Scan(
-		&i.TotalElevation,
-		&i.AvgElevation,
-		&i.MinElevation,
-		&i.MaxElevation,
+		&i.TotalCalories,
+		&i.AvgCalories,
+		&i.MinCalories,
+		&i.MaxCalories,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getElevationSummaryInRange = `-- name: GetElevationSummaryInRange :one
+const getCaloriesSummaryInRange = `-- name: GetCaloriesSummaryInRange :one
 SELECT 
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
-    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
-    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
-    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(AVG(calories), 0) as avg_calories,
+    COALESCE(MIN(calories), 0) as min_calories,
+    COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+WHERE start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetElevationSummaryInRangeParams struct {
+type GetCaloriesSummaryInRangeParams struct {
 	StartDate   sql.NullTime `json:"start_date"`
 	StartDate_2 sql.NullTime `json:"start_date_2"`
 }
 
-type GetElevationSummaryInRangeRow struct {
-	TotalElevation interface{} `json:"total_elevation"`
-	AvgElevation   interface{} `json:"avg_elevation"`
-	MinElevation   interface{} `json:"min_elevation"`
-	MaxElevation   interface{} `json:"max_elevation"`
-	ActivityCount  int64       `json:"activity_count"`
+type GetCaloriesSummaryInRangeRow struct {
+	TotalCalories interface{} `json:"total_calories"`
+	AvgCalories   interface{} `json:"avg_calories"`
+	MinCalories   interface{} `json:"min_calories"`
+	MaxCalories   interface{} `json:"max_calories"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetElevationSummaryInRange(ctx context.Context, arg GetElevationSummaryInRangeParams) (GetElevationSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getElevationSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetElevationSummaryInRangeRow
+func (q *Queries) GetCaloriesSummaryInRange(ctx context.Context, arg GetCaloriesSummaryInRangeParams) (GetCaloriesSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getCaloriesSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetCaloriesSummaryInRangeRow
 	err := row.Scan(
-		&i.TotalElevation,
-		&i.AvgElevation,
-		&i.MinElevation,
-		&i.MaxElevation,
+		&i.TotalCalories,
+		&i.AvgCalories,
+		&i.MinCalories,
+		&i.MaxCalories,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getFastestActivity = `-- name: GetFastestActivity :one
-
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE average_speed IS NOT NULL AND average_speed > 0
-ORDER BY average_speed DESC
-LIMIT 1
+const getClub = `-- name: GetClub :one
+SELECT id, name, sport_type, city, state, country, private, member_count, url, synced_at FROM clubs WHERE id = ? LIMIT 1
 `
 
-// Personal records queries
-func (q *Queries) GetFastestActivity(ctx context.Context) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getFastestActivity)
-	var i Activity
+func (q *Queries) GetClub(ctx context.Context, id int64) (Club, error) {
+	row := q.db.QueryRowContext(ctx, getClub, id)
+	var i Club
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
 		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.City,
+		&i.State,
+		&i.Country,
+		&i.Private,
+		&i.MemberCount,
+		&i.Url,
+		&i.SyncedAt,
 	)
 	return i, err
 }
 
-const getFastestActivityByType = `-- name: GetFastestActivityByType :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE type = ? AND average_speed IS NOT NULL AND average_speed > 0
-ORDER BY average_speed DESC
-LIMIT 1
+const getClubLeaderboard = `-- name: GetClubLeaderboard :many
+SELECT
+    athlete_name,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM club_activities
+WHERE club_id = ? AND first_seen_at >= ?
+GROUP BY athlete_name
+ORDER BY SUM(distance) DESC, athlete_name
+LIMIT ?
 `
 
-func (q *Queries) GetFastestActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getFastestActivityByType, type_)
-	var i Activity
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type GetClubLeaderboardParams struct {
+	ClubID      int64     `json:"club_id"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	Limit       int64     `json:"limit"`
 }
 
-const getHeartRateZoneSummary = `-- name: GetHeartRateZoneSummary :many
-SELECT 
-    zb.zone_number,
-    SUM(zb.time_seconds) as total_time,
-    AVG(zb.time_seconds) as avg_time,
-    COUNT(*) as activity_count
-FROM zone_buckets zb
-JOIN activity_zones az ON zb.activity_zone_id = az.id
-WHERE az.zone_type = 'heartrate'
-GROUP BY zb.zone_number
-ORDER BY zb.zone_number
-`
-
-type GetHeartRateZoneSummaryRow struct {
-	ZoneNumber    int64           `json:"zone_number"`
-	TotalTime     sql.NullFloat64 `json:"total_time"`
-	AvgTime       sql.NullFloat64 `json:"avg_time"`
-	ActivityCount int64           `json:"activity_count"`
+type GetClubLeaderboardRow struct {
+	AthleteName     string      `json:"athlete_name"`
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	TotalElevation  interface{} `json:"total_elevation"`
 }
 
-func (q *Queries) GetHeartRateZoneSummary(ctx context.Context) ([]GetHeartRateZoneSummaryRow, error) {
-	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummary)
+func (q *Queries) GetClubLeaderboard(ctx context.Context, arg GetClubLeaderboardParams) ([]GetClubLeaderboardRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClubLeaderboard, arg.ClubID, arg.FirstSeenAt, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetHeartRateZoneSummaryRow{}
+	items := []GetClubLeaderboardRow{}
 	for rows.Next() {
-		var i GetHeartRateZoneSummaryRow
+		var i GetClubLeaderboardRow
 		if err := rows.Scan(
-			&i.ZoneNumber,
-			&i.TotalTime,
-			&i.AvgTime,
+			&i.AthleteName,
 			&i.ActivityCount,
+			&i.TotalDistance,
+			&i.TotalMovingTime,
+			&i.TotalElevation,
 		); err != nil {
 			return nil, err
 		}
@@ -1605,41 +2438,44 @@ func (q *Queries) GetHeartRateZoneSummary(ctx context.Context) ([]GetHeartRateZo
 	return items, nil
 }
 
-const getHeartRateZoneSummaryByType = `-- name: GetHeartRateZoneSummaryByType :many
-SELECT 
-    zb.zone_number,
-    SUM(zb.time_seconds) as total_time,
-    AVG(zb.time_seconds) as avg_time,
-    COUNT(*) as activity_count
-FROM zone_buckets zb
-JOIN activity_zones az ON zb.activity_zone_id = az.id
-JOIN activities a ON az.activity_id = a.id
-WHERE az.zone_type = 'heartrate' AND a.type = ?
-GROUP BY zb.zone_number
-ORDER BY zb.zone_number
+const getClubTypeTotals = `-- name: GetClubTypeTotals :many
+SELECT
+    type,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time
+FROM club_activities
+WHERE club_id = ? AND first_seen_at >= ?
+GROUP BY type
+ORDER BY activity_count DESC
 `
 
-type GetHeartRateZoneSummaryByTypeRow struct {
-	ZoneNumber    int64           `json:"zone_number"`
-	TotalTime     sql.NullFloat64 `json:"total_time"`
-	AvgTime       sql.NullFloat64 `json:"avg_time"`
-	ActivityCount int64           `json:"activity_count"`
+type GetClubTypeTotalsParams struct {
+	ClubID      int64     `json:"club_id"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
 }
 
-func (q *Queries) GetHeartRateZoneSummaryByType(ctx context.Context, type_ sql.NullString) ([]GetHeartRateZoneSummaryByTypeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummaryByType, type_)
+type GetClubTypeTotalsRow struct {
+	Type            sql.NullString `json:"type"`
+	ActivityCount   int64          `json:"activity_count"`
+	TotalDistance   interface{}    `json:"total_distance"`
+	TotalMovingTime interface{}    `json:"total_moving_time"`
+}
+
+func (q *Queries) GetClubTypeTotals(ctx context.Context, arg GetClubTypeTotalsParams) ([]GetClubTypeTotalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClubTypeTotals, arg.ClubID, arg.FirstSeenAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetHeartRateZoneSummaryByTypeRow{}
+	items := []GetClubTypeTotalsRow{}
 	for rows.Next() {
-		var i GetHeartRateZoneSummaryByTypeRow
+		var i GetClubTypeTotalsRow
 		if err := rows.Scan(
-			&i.ZoneNumber,
-			&i.TotalTime,
-			&i.AvgTime,
+			&i.Type,
 			&i.ActivityCount,
+			&i.TotalDistance,
+			&i.TotalMovingTime,
 		); err != nil {
 			return nil, err
 		}
@@ -1654,48 +2490,50 @@ func (q *Queries) GetHeartRateZoneSummaryByType(ctx context.Context, type_ sql.N
 	return items, nil
 }
 
-const getHeartRateZoneSummaryByTypeInRange = `-- name: GetHeartRateZoneSummaryByTypeInRange :many
-SELECT 
-    zb.zone_number,
-    SUM(zb.time_seconds) as total_time,
-    AVG(zb.time_seconds) as avg_time,
-    COUNT(*) as activity_count
-FROM zone_buckets zb
-JOIN activity_zones az ON zb.activity_zone_id = az.id
-JOIN activities a ON az.activity_id = a.id
-WHERE az.zone_type = 'heartrate' AND a.type = ?
-  AND a.start_date >= ? AND a.start_date <= ?
-GROUP BY zb.zone_number
-ORDER BY zb.zone_number
+const getClubWeeklyTotals = `-- name: GetClubWeeklyTotals :many
+SELECT
+    date(substr(first_seen_at, 1, 19), 'weekday 0', '-6 days') as week_start,
+    COUNT(*) as activity_count,
+    COUNT(DISTINCT athlete_name) as athletes,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM club_activities
+WHERE club_id = ? AND backfill = 0 AND first_seen_at >= ?
+GROUP BY date(substr(first_seen_at, 1, 19), 'weekday 0', '-6 days')
+ORDER BY week_start
 `
 
-type GetHeartRateZoneSummaryByTypeInRangeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
+type GetClubWeeklyTotalsParams struct {
+	ClubID      int64     `json:"club_id"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
 }
 
-type GetHeartRateZoneSummaryByTypeInRangeRow struct {
-	ZoneNumber    int64           `json:"zone_number"`
-	TotalTime     sql.NullFloat64 `json:"total_time"`
-	AvgTime       sql.NullFloat64 `json:"avg_time"`
-	ActivityCount int64           `json:"activity_count"`
+type GetClubWeeklyTotalsRow struct {
+	WeekStart       interface{} `json:"week_start"`
+	ActivityCount   int64       `json:"activity_count"`
+	Athletes        int64       `json:"athletes"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	TotalElevation  interface{} `json:"total_elevation"`
 }
 
-func (q *Queries) GetHeartRateZoneSummaryByTypeInRange(ctx context.Context, arg GetHeartRateZoneSummaryByTypeInRangeParams) ([]GetHeartRateZoneSummaryByTypeInRangeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+func (q *Queries) GetClubWeeklyTotals(ctx context.Context, arg GetClubWeeklyTotalsParams) ([]GetClubWeeklyTotalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClubWeeklyTotals, arg.ClubID, arg.FirstSeenAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetHeartRateZoneSummaryByTypeInRangeRow{}
+	items := []GetClubWeeklyTotalsRow{}
 	for rows.Next() {
-		var i GetHeartRateZoneSummaryByTypeInRangeRow
+		var i GetClubWeeklyTotalsRow
 		if err := rows.Scan(
-			&i.ZoneNumber,
-			&i.TotalTime,
-			&i.AvgTime,
+			&i.WeekStart,
 			&i.ActivityCount,
+			&i.Athletes,
+			&i.TotalDistance,
+			&i.TotalMovingTime,
+			&i.TotalElevation,
 		); err != nil {
 			return nil, err
 		}
@@ -1710,489 +2548,476 @@ func (q *Queries) GetHeartRateZoneSummaryByTypeInRange(ctx context.Context, arg
 	return items, nil
 }
 
-const getHeartRateZoneSummaryInRange = `-- name: GetHeartRateZoneSummaryInRange :many
-SELECT 
-    zb.zone_number,
-    SUM(zb.time_seconds) as total_time,
-    AVG(zb.time_seconds) as avg_time,
-    COUNT(*) as activity_count
-FROM zone_buckets zb
-JOIN activity_zones az ON zb.activity_zone_id = az.id
-JOIN activities a ON az.activity_id = a.id
-WHERE az.zone_type = 'heartrate' 
-  AND a.start_date >= ? AND a.start_date <= ?
-GROUP BY zb.zone_number
-ORDER BY zb.zone_number
+const getDeadFullSyncPage = `-- name: GetDeadFullSyncPage :one
+SELECT id, kind, target, after_time, priority, status, attempts, max_attempts, last_error, run_after, created_at, updated_at, finished_at, before_time FROM sync_jobs
+WHERE kind = 'list_page' AND status = 'dead' AND after_time IS NULL AND before_time IS NULL
+ORDER BY target
+LIMIT 1
 `
 
-type GetHeartRateZoneSummaryInRangeParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
-}
-
-type GetHeartRateZoneSummaryInRangeRow struct {
-	ZoneNumber    int64           `json:"zone_number"`
-	TotalTime     sql.NullFloat64 `json:"total_time"`
-	AvgTime       sql.NullFloat64 `json:"avg_time"`
-	ActivityCount int64           `json:"activity_count"`
-}
-
-func (q *Queries) GetHeartRateZoneSummaryInRange(ctx context.Context, arg GetHeartRateZoneSummaryInRangeParams) ([]GetHeartRateZoneSummaryInRangeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummaryInRange, arg.StartDate, arg.StartDate_2)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetHeartRateZoneSummaryInRangeRow{}
-	for rows.Next() {
-		var i GetHeartRateZoneSummaryInRangeRow
-		if err := rows.Scan(
-			&i.ZoneNumber,
-			&i.TotalTime,
-			&i.AvgTime,
-			&i.ActivityCount,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) GetDeadFullSyncPage(ctx context.Context) (SyncJob, error) {
+	row := q.db.QueryRowContext(ctx, getDeadFullSyncPage)
+	var i SyncJob
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Target,
+		&i.AfterTime,
+		&i.Priority,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.LastError,
+		&i.RunAfter,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinishedAt,
+		&i.BeforeTime,
+	)
+	return i, err
 }
 
-const getHeartrateSummary = `-- name: GetHeartrateSummary :one
+const getDistanceSummary = `-- name: GetDistanceSummary :one
 SELECT 
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
-    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
-    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(AVG(distance), 0) as avg_distance,
+    COALESCE(MIN(distance), 0) as min_distance,
+    COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE average_heartrate IS NOT NULL AND average_heartrate > 0
+WHERE distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetHeartrateSummaryRow struct {
-	AvgHeartrate        interface{} `json:"avg_heartrate"`
-	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
-	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
-	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
-	ActivityCount       int64       `json:"activity_count"`
+type GetDistanceSummaryRow struct {
+	TotalDistance interface{} `json:"total_distance"`
+	AvgDistance   interface{} `json:"avg_distance"`
+	MinDistance   interface{} `json:"min_distance"`
+	MaxDistance   interface{} `json:"max_distance"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetHeartrateSummary(ctx context.Context) (GetHeartrateSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getHeartrateSummary)
-	var i GetHeartrateSummaryRow
+func (q *Queries) GetDistanceSummary(ctx context.Context) (GetDistanceSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getDistanceSummary)
+	var i GetDistanceSummaryRow
 	err := row.Scan(
-		&i.AvgHeartrate,
-		&i.MinAvgHeartrate,
-		&i.MaxAvgHeartrate,
-		&i.OverallMaxHeartrate,
+		&i.TotalDistance,
+		&i.AvgDistance,
+		&i.MinDistance,
+		&i.MaxDistance,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getHeartrateSummaryByType = `-- name: GetHeartrateSummaryByType :one
+const getDistanceSummaryByType = `-- name: GetDistanceSummaryByType :one
 SELECT 
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
-    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
-    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(AVG(distance), 0) as avg_distance,
+    COALESCE(MIN(distance), 0) as min_distance,
+    COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+WHERE type = ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetHeartrateSummaryByTypeRow struct {
-	AvgHeartrate        interface{} `json:"avg_heartrate"`
-	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
-	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
-	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
-	ActivityCount       int64       `json:"activity_count"`
+type GetDistanceSummaryByTypeRow struct {
+	TotalDistance interface{} `json:"total_distance"`
+	AvgDistance   interface{} `json:"avg_distance"`
+	MinDistance   interface{} `json:"min_distance"`
+	MaxDistance   interface{} `json:"max_distance"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetHeartrateSummaryByType(ctx context.Context, type_ sql.NullString) (GetHeartrateSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getHeartrateSummaryByType, type_)
-	var i GetHeartrateSummaryByTypeRow
+func (q *Queries) GetDistanceSummaryByType(ctx context.Context, type_ sql.NullString) (GetDistanceSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getDistanceSummaryByType, type_)
+	var i GetDistanceSummaryByTypeRow
 	err := row.Scan(
-		&i.AvgHeartrate,
-		&i.MinAvgHeartrate,
-		&i.MaxAvgHeartrate,
-		&i.OverallMaxHeartrate,
+		&i.TotalDistance,
+		&i.AvgDistance,
+		&i.MinDistance,
+		&i.MaxDistance,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getHeartrateSummaryByTypeInRange = `-- name: GetHeartrateSummaryByTypeInRange :one
+const getDistanceSummaryByTypeInRange = `-- name: GetDistanceSummaryByTypeInRange :one
 SELECT 
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
-    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
-    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(AVG(distance), 0) as avg_distance,
+    COALESCE(MIN(distance), 0) as min_distance,
+    COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetHeartrateSummaryByTypeInRangeParams struct {
+type GetDistanceSummaryByTypeInRangeParams struct {
 	Type        sql.NullString `json:"type"`
 	StartDate   sql.NullTime   `json:"start_date"`
 	StartDate_2 sql.NullTime   `json:"start_date_2"`
 }
 
-type GetHeartrateSummaryByTypeInRangeRow struct {
-	AvgHeartrate        interface{} `json:"avg_heartrate"`
-	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
-	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
-	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
-	ActivityCount       int64       `json:"activity_count"`
+type GetDistanceSummaryByTypeInRangeRow struct {
+	TotalDistance interface{} `json:"total_distance"`
+	AvgDistance   interface{} `json:"avg_distance"`
+	MinDistance   interface{} `json:"min_distance"`
+	MaxDistance   interface{} `json:"max_distance"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetHeartrateSummaryByTypeInRange(ctx context.Context, arg GetHeartrateSummaryByTypeInRangeParams) (GetHeartrateSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getHeartrateSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetHeartrateSummaryByTypeInRangeRow
+func (q *Queries) GetDistanceSummaryByTypeInRange(ctx context.Context, arg GetDistanceSummaryByTypeInRangeParams) (GetDistanceSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getDistanceSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetDistanceSummaryByTypeInRangeRow
 	err := row.Scan(
-		&i.AvgHeartrate,
-		&i.MinAvgHeartrate,
-		&i.MaxAvgHeartrate,
-		&i.OverallMaxHeartrate,
+		&i.TotalDistance,
+		&i.AvgDistance,
+		&i.MinDistance,
+		&i.MaxDistance,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getHeartrateSummaryInRange = `-- name: GetHeartrateSummaryInRange :one
+const getDistanceSummaryInRange = `-- name: GetDistanceSummaryInRange :one
 SELECT 
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
-    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
-    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(AVG(distance), 0) as avg_distance,
+    COALESCE(MIN(distance), 0) as min_distance,
+    COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+WHERE start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetHeartrateSummaryInRangeParams struct {
+type GetDistanceSummaryInRangeParams struct {
 	StartDate   sql.NullTime `json:"start_date"`
 	StartDate_2 sql.NullTime `json:"start_date_2"`
 }
 
-type GetHeartrateSummaryInRangeRow struct {
-	AvgHeartrate        interface{} `json:"avg_heartrate"`
-	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
-	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
-	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
-	ActivityCount       int64       `json:"activity_count"`
+type GetDistanceSummaryInRangeRow struct {
+	TotalDistance interface{} `json:"total_distance"`
+	AvgDistance   interface{} `json:"avg_distance"`
+	MinDistance   interface{} `json:"min_distance"`
+	MaxDistance   interface{} `json:"max_distance"`
+	ActivityCount int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetHeartrateSummaryInRange(ctx context.Context, arg GetHeartrateSummaryInRangeParams) (GetHeartrateSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getHeartrateSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetHeartrateSummaryInRangeRow
+func (q *Queries) GetDistanceSummaryInRange(ctx context.Context, arg GetDistanceSummaryInRangeParams) (GetDistanceSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getDistanceSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetDistanceSummaryInRangeRow
 	err := row.Scan(
-		&i.AvgHeartrate,
-		&i.MinAvgHeartrate,
-		&i.MaxAvgHeartrate,
-		&i.OverallMaxHeartrate,
+		&i.TotalDistance,
+		&i.AvgDistance,
+		&i.MinDistance,
+		&i.MaxDistance,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getHighestElevationActivity = `-- name: GetHighestElevationActivity :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
-ORDER BY total_elevation_gain DESC
-LIMIT 1
-`
-
-func (q *Queries) GetHighestElevationActivity(ctx context.Context) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getHighestElevationActivity)
-	var i Activity
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
-}
-
-const getHighestElevationActivityByType = `-- name: GetHighestElevationActivityByType :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE type = ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
-ORDER BY total_elevation_gain DESC
-LIMIT 1
+const getDurationSummary = `-- name: GetDurationSummary :one
+SELECT 
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(moving_time), 0) as avg_moving_time,
+    COALESCE(MIN(moving_time), 0) as min_moving_time,
+    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-func (q *Queries) GetHighestElevationActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getHighestElevationActivityByType, type_)
-	var i Activity
+type GetDurationSummaryRow struct {
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgMovingTime   interface{} `json:"avg_moving_time"`
+	MinMovingTime   interface{} `json:"min_moving_time"`
+	MaxMovingTime   interface{} `json:"max_moving_time"`
+	ActivityCount   int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetDurationSummary(ctx context.Context) (GetDurationSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getDurationSummary)
+	var i GetDurationSummaryRow
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TotalMovingTime,
+		&i.AvgMovingTime,
+		&i.MinMovingTime,
+		&i.MaxMovingTime,
+		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getLatestActivity = `-- name: GetLatestActivity :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities ORDER BY start_date DESC LIMIT 1
+const getDurationSummaryByType = `-- name: GetDurationSummaryByType :one
+SELECT 
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(moving_time), 0) as avg_moving_time,
+    COALESCE(MIN(moving_time), 0) as min_moving_time,
+    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE type = ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-func (q *Queries) GetLatestActivity(ctx context.Context) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getLatestActivity)
-	var i Activity
+type GetDurationSummaryByTypeRow struct {
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgMovingTime   interface{} `json:"avg_moving_time"`
+	MinMovingTime   interface{} `json:"min_moving_time"`
+	MaxMovingTime   interface{} `json:"max_moving_time"`
+	ActivityCount   int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetDurationSummaryByType(ctx context.Context, type_ sql.NullString) (GetDurationSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getDurationSummaryByType, type_)
+	var i GetDurationSummaryByTypeRow
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TotalMovingTime,
+		&i.AvgMovingTime,
+		&i.MinMovingTime,
+		&i.MaxMovingTime,
+		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getLatestActivityDate = `-- name: GetLatestActivityDate :one
-SELECT MAX(start_date) as latest_date FROM activities
+const getDurationSummaryByTypeInRange = `-- name: GetDurationSummaryByTypeInRange :one
+SELECT 
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(moving_time), 0) as avg_moving_time,
+    COALESCE(MIN(moving_time), 0) as min_moving_time,
+    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-func (q *Queries) GetLatestActivityDate(ctx context.Context) (interface{}, error) {
-	row := q.db.QueryRowContext(ctx, getLatestActivityDate)
-	var latest_date interface{}
-	err := row.Scan(&latest_date)
-	return latest_date, err
+type GetDurationSummaryByTypeInRangeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
 }
 
-const getLongestDistanceActivity = `-- name: GetLongestDistanceActivity :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE distance IS NOT NULL AND distance > 0
-ORDER BY distance DESC
-LIMIT 1
-`
+type GetDurationSummaryByTypeInRangeRow struct {
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgMovingTime   interface{} `json:"avg_moving_time"`
+	MinMovingTime   interface{} `json:"min_moving_time"`
+	MaxMovingTime   interface{} `json:"max_moving_time"`
+	ActivityCount   int64       `json:"activity_count"`
+}
 
-func (q *Queries) GetLongestDistanceActivity(ctx context.Context) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getLongestDistanceActivity)
-	var i Activity
+func (q *Queries) GetDurationSummaryByTypeInRange(ctx context.Context, arg GetDurationSummaryByTypeInRangeParams) (GetDurationSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getDurationSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetDurationSummaryByTypeInRangeRow
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TotalMovingTime,
+		&i.AvgMovingTime,
+		&i.MinMovingTime,
+		&i.MaxMovingTime,
+		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getLongestDistanceActivityByType = `-- name: GetLongestDistanceActivityByType :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE type = ? AND distance IS NOT NULL AND distance > 0
-ORDER BY distance DESC
-LIMIT 1
+const getDurationSummaryInRange = `-- name: GetDurationSummaryInRange :one
+SELECT 
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(moving_time), 0) as avg_moving_time,
+    COALESCE(MIN(moving_time), 0) as min_moving_time,
+    COALESCE(MAX(moving_time), 0) as max_moving_time,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-func (q *Queries) GetLongestDistanceActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getLongestDistanceActivityByType, type_)
-	var i Activity
+type GetDurationSummaryInRangeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetDurationSummaryInRangeRow struct {
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgMovingTime   interface{} `json:"avg_moving_time"`
+	MinMovingTime   interface{} `json:"min_moving_time"`
+	MaxMovingTime   interface{} `json:"max_moving_time"`
+	ActivityCount   int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetDurationSummaryInRange(ctx context.Context, arg GetDurationSummaryInRangeParams) (GetDurationSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getDurationSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetDurationSummaryInRangeRow
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TotalMovingTime,
+		&i.AvgMovingTime,
+		&i.MinMovingTime,
+		&i.MaxMovingTime,
+		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getLongestDurationActivity = `-- name: GetLongestDurationActivity :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE moving_time IS NOT NULL AND moving_time > 0
-ORDER BY moving_time DESC
-LIMIT 1
+const getElevationSummary = `-- name: GetElevationSummary :one
+SELECT 
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
+    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
+    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
+    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-func (q *Queries) GetLongestDurationActivity(ctx context.Context) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getLongestDurationActivity)
-	var i Activity
+type GetElevationSummaryRow struct {
+	TotalElevation interface{} `json:"total_elevation"`
+	AvgElevation   interface{} `json:"avg_elevation"`
+	MinElevation   interface{} `json:"min_elevation"`
+	MaxElevation   interface{} `json:"max_elevation"`
+	ActivityCount  int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetElevationSummary(ctx context.Context) (GetElevationSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getElevationSummary)
+	var i GetElevationSummaryRow
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TotalElevation,
+		&i.AvgElevation,
+		&i.MinElevation,
+		&i.MaxElevation,
+		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getLongestDurationActivityByType = `-- name: GetLongestDurationActivityByType :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE type = ? AND moving_time IS NOT NULL AND moving_time > 0
-ORDER BY moving_time DESC
-LIMIT 1
+const getElevationSummaryByType = `-- name: GetElevationSummaryByType :one
+SELECT 
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
+    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
+    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
+    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE type = ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-func (q *Queries) GetLongestDurationActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getLongestDurationActivityByType, type_)
-	var i Activity
+type GetElevationSummaryByTypeRow struct {
+	TotalElevation interface{} `json:"total_elevation"`
+	AvgElevation   interface{} `json:"avg_elevation"`
+	MinElevation   interface{} `json:"min_elevation"`
+	MaxElevation   interface{} `json:"max_elevation"`
+	ActivityCount  int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetElevationSummaryByType(ctx context.Context, type_ sql.NullString) (GetElevationSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getElevationSummaryByType, type_)
+	var i GetElevationSummaryByTypeRow
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TotalElevation,
+		&i.AvgElevation,
+		&i.MinElevation,
+		&i.MaxElevation,
+		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getMostCaloriesActivity = `-- name: GetMostCaloriesActivity :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE calories IS NOT NULL AND calories > 0
-ORDER BY calories DESC
-LIMIT 1
+const getElevationSummaryByTypeInRange = `-- name: GetElevationSummaryByTypeInRange :one
+SELECT 
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
+    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
+    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
+    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-func (q *Queries) GetMostCaloriesActivity(ctx context.Context) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getMostCaloriesActivity)
-	var i Activity
+type GetElevationSummaryByTypeInRangeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetElevationSummaryByTypeInRangeRow struct {
+	TotalElevation interface{} `json:"total_elevation"`
+	AvgElevation   interface{} `json:"avg_elevation"`
+	MinElevation   interface{} `json:"min_elevation"`
+	MaxElevation   interface{} `json:"max_elevation"`
+	ActivityCount  int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetElevationSummaryByTypeInRange(ctx context.Context, arg GetElevationSummaryByTypeInRangeParams) (GetElevationSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getElevationSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetElevationSummaryByTypeInRangeRow
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Distance,
-		&i.MovingTime,
-		&i.ElapsedTime,
-		&i.TotalElevationGain,
-		&i.Type,
-		&i.SportType,
-		&i.StartDate,
-		&i.StartDateLocal,
-		&i.Timezone,
-		&i.AverageSpeed,
-		&i.MaxSpeed,
-		&i.AverageCadence,
-		&i.AverageHeartrate,
-		&i.MaxHeartrate,
-		&i.Calories,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TotalElevation,
+		&i.AvgElevation,
+		&i.MinElevation,
+		&i.MaxElevation,
+		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getMostCaloriesActivityByType = `-- name: GetMostCaloriesActivityByType :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
-WHERE type = ? AND calories IS NOT NULL AND calories > 0
-ORDER BY calories DESC
+const getElevationSummaryInRange = `-- name: GetElevationSummaryInRange :one
+SELECT 
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation,
+    COALESCE(AVG(total_elevation_gain), 0) as avg_elevation,
+    COALESCE(MIN(total_elevation_gain), 0) as min_elevation,
+    COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetElevationSummaryInRangeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetElevationSummaryInRangeRow struct {
+	TotalElevation interface{} `json:"total_elevation"`
+	AvgElevation   interface{} `json:"avg_elevation"`
+	MinElevation   interface{} `json:"min_elevation"`
+	MaxElevation   interface{} `json:"max_elevation"`
+	ActivityCount  int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetElevationSummaryInRange(ctx context.Context, arg GetElevationSummaryInRangeParams) (GetElevationSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getElevationSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetElevationSummaryInRangeRow
+	err := row.Scan(
+		&i.TotalElevation,
+		&i.AvgElevation,
+		&i.MinElevation,
+		&i.MaxElevation,
+		&i.ActivityCount,
+	)
+	return i, err
+}
+
+const getFastestActivity = `-- name: GetFastestActivity :one
+
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY average_speed DESC
 LIMIT 1
 `
 
-func (q *Queries) GetMostCaloriesActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getMostCaloriesActivityByType, type_)
+// Personal records queries
+func (q *Queries) GetFastestActivity(ctx context.Context) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getFastestActivity)
 	var i Activity
 	err := row.Scan(
 		&i.ID,
@@ -2214,16 +3039,29 @@ func (q *Queries) GetMostCaloriesActivityByType(ctx context.Context, type_ sql.N
 		&i.Calories,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
 	)
 	return i, err
 }
 
-const getOldestActivity = `-- name: GetOldestActivity :one
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities ORDER BY start_date ASC LIMIT 1
+const getFastestActivityByType = `-- name: GetFastestActivityByType :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE type = ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY average_speed DESC
+LIMIT 1
 `
 
-func (q *Queries) GetOldestActivity(ctx context.Context) (Activity, error) {
-	row := q.db.QueryRowContext(ctx, getOldestActivity)
+func (q *Queries) GetFastestActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getFastestActivityByType, type_)
 	var i Activity
 	err := row.Scan(
 		&i.ID,
@@ -2245,93 +3083,69 @@ func (q *Queries) GetOldestActivity(ctx context.Context) (Activity, error) {
 		&i.Calories,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
 	)
 	return i, err
 }
 
-const getOldestActivityDate = `-- name: GetOldestActivityDate :one
-SELECT MIN(start_date) as oldest_date FROM activities
+const getHeartRateZoneSummary = `-- name: GetHeartRateZoneSummary :many
+SELECT 
+    zb.zone_number,
+    SUM(zb.time_seconds) as total_time,
+    AVG(zb.time_seconds) as avg_time,
+    COUNT(*) as activity_count
+FROM zone_buckets zb
+JOIN activity_zones az ON zb.activity_zone_id = az.id
+WHERE az.zone_type = 'heartrate'
+  AND az.activity_id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY zb.zone_number
+ORDER BY zb.zone_number
 `
 
-func (q *Queries) GetOldestActivityDate(ctx context.Context) (interface{}, error) {
-	row := q.db.QueryRowContext(ctx, getOldestActivityDate)
-	var oldest_date interface{}
-	err := row.Scan(&oldest_date)
-	return oldest_date, err
+type GetHeartRateZoneSummaryRow struct {
+	ZoneNumber    int64           `json:"zone_number"`
+	TotalTime     sql.NullFloat64 `json:"total_time"`
+	AvgTime       sql.NullFloat64 `json:"avg_time"`
+	ActivityCount int64           `json:"activity_count"`
 }
 
-const getPeriodStats = `-- name: GetPeriodStats :one
+func (q *Queries) GetHeartRateZoneSummary(ctx context.Context) ([]GetHeartRateZoneSummaryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummary)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetHeartRateZoneSummaryRow{}
+	for rows.Next() {
+		var i GetHeartRateZoneSummaryRow
+		if err := rows.Scan(
+			&i.ZoneNumber,
+			&i.TotalTime,
+			&i.AvgTime,
+			&i.ActivityCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
-SELECT
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(average_speed), 0) as avg_speed
-FROM activities
-WHERE start_date >= ? AND start_date <= ?
-`
-
-type GetPeriodStatsParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
-}
-
-type GetPeriodStatsRow struct {
-	ActivityCount   int64       `json:"activity_count"`
-	TotalDistance   interface{} `json:"total_distance"`
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgSpeed        interface{} `json:"avg_speed"`
-}
-
-// Period comparison queries with detailed metrics
-func (q *Queries) GetPeriodStats(ctx context.Context, arg GetPeriodStatsParams) (GetPeriodStatsRow, error) {
-	row := q.db.QueryRowContext(ctx, getPeriodStats, arg.StartDate, arg.StartDate_2)
-	var i GetPeriodStatsRow
-	err := row.Scan(
-		&i.ActivityCount,
-		&i.TotalDistance,
-		&i.TotalMovingTime,
-		&i.AvgSpeed,
-	)
-	return i, err
-}
-
-const getPeriodStatsByType = `-- name: GetPeriodStatsByType :one
-SELECT
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(average_speed), 0) as avg_speed
-FROM activities
-WHERE type = ? AND start_date >= ? AND start_date <= ?
-`
-
-type GetPeriodStatsByTypeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
-}
-
-type GetPeriodStatsByTypeRow struct {
-	ActivityCount   int64       `json:"activity_count"`
-	TotalDistance   interface{} `json:"total_distance"`
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgSpeed        interface{} `json:"avg_speed"`
-}
-
-func (q *Queries) GetPeriodStatsByType(ctx context.Context, arg GetPeriodStatsByTypeParams) (GetPeriodStatsByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getPeriodStatsByType, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetPeriodStatsByTypeRow
-	err := row.Scan(
-		&i.ActivityCount,
-		&i.TotalDistance,
-		&i.TotalMovingTime,
-		&i.AvgSpeed,
-	)
-	return i, err
-}
-
-const getPowerZoneSummary = `-- name: GetPowerZoneSummary :many
+const getHeartRateZoneSummaryByType = `-- name: GetHeartRateZoneSummaryByType :many
 SELECT 
     zb.zone_number,
     SUM(zb.time_seconds) as total_time,
@@ -2339,27 +3153,29 @@ SELECT
     COUNT(*) as activity_count
 FROM zone_buckets zb
 JOIN activity_zones az ON zb.activity_zone_id = az.id
-WHERE az.zone_type = 'power'
+JOIN activities a ON az.activity_id = a.id
+WHERE az.zone_type = 'heartrate' AND a.type = ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number
 `
 
-type GetPowerZoneSummaryRow struct {
+type GetHeartRateZoneSummaryByTypeRow struct {
 	ZoneNumber    int64           `json:"zone_number"`
 	TotalTime     sql.NullFloat64 `json:"total_time"`
 	AvgTime       sql.NullFloat64 `json:"avg_time"`
 	ActivityCount int64           `json:"activity_count"`
 }
 
-func (q *Queries) GetPowerZoneSummary(ctx context.Context) ([]GetPowerZoneSummaryRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPowerZoneSummary)
+func (q *Queries) GetHeartRateZoneSummaryByType(ctx context.Context, type_ sql.NullString) ([]GetHeartRateZoneSummaryByTypeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummaryByType, type_)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetPowerZoneSummaryRow{}
+	items := []GetHeartRateZoneSummaryByTypeRow{}
 	for rows.Next() {
-		var i GetPowerZoneSummaryRow
+		var i GetHeartRateZoneSummaryByTypeRow
 		if err := rows.Scan(
 			&i.ZoneNumber,
 			&i.TotalTime,
@@ -2379,7 +3195,7 @@ func (q *Queries) GetPowerZoneSummary(ctx context.Context) ([]GetPowerZoneSummar
 	return items, nil
 }
 
-const getPowerZoneSummaryByType = `-- name: GetPowerZoneSummaryByType :many
+const getHeartRateZoneSummaryByTypeInRange = `-- name: GetHeartRateZoneSummaryByTypeInRange :many
 SELECT 
     zb.zone_number,
     SUM(zb.time_seconds) as total_time,
@@ -2388,27 +3204,35 @@ SELECT
 FROM zone_buckets zb
 JOIN activity_zones az ON zb.activity_zone_id = az.id
 JOIN activities a ON az.activity_id = a.id
-WHERE az.zone_type = 'power' AND a.type = ?
+WHERE az.zone_type = 'heartrate' AND a.type = ?
+  AND a.start_date >= ? AND a.start_date <= ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number
 `
 
-type GetPowerZoneSummaryByTypeRow struct {
+type GetHeartRateZoneSummaryByTypeInRangeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetHeartRateZoneSummaryByTypeInRangeRow struct {
 	ZoneNumber    int64           `json:"zone_number"`
 	TotalTime     sql.NullFloat64 `json:"total_time"`
 	AvgTime       sql.NullFloat64 `json:"avg_time"`
 	ActivityCount int64           `json:"activity_count"`
 }
 
-func (q *Queries) GetPowerZoneSummaryByType(ctx context.Context, type_ sql.NullString) ([]GetPowerZoneSummaryByTypeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPowerZoneSummaryByType, type_)
+func (q *Queries) GetHeartRateZoneSummaryByTypeInRange(ctx context.Context, arg GetHeartRateZoneSummaryByTypeInRangeParams) ([]GetHeartRateZoneSummaryByTypeInRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetPowerZoneSummaryByTypeRow{}
+	items := []GetHeartRateZoneSummaryByTypeInRangeRow{}
 	for rows.Next() {
-		var i GetPowerZoneSummaryByTypeRow
+		var i GetHeartRateZoneSummaryByTypeInRangeRow
 		if err := rows.Scan(
 			&i.ZoneNumber,
 			&i.TotalTime,
@@ -2428,8 +3252,8 @@ func (q *Queries) GetPowerZoneSummaryByType(ctx context.Context, type_ sql.NullS
 	return items, nil
 }
 
-const getPowerZoneSummaryInRange = `-- name: GetPowerZoneSummaryInRange :many
-SELECT
+const getHeartRateZoneSummaryInRange = `-- name: GetHeartRateZoneSummaryInRange :many
+SELECT 
     zb.zone_number,
     SUM(zb.time_seconds) as total_time,
     AVG(zb.time_seconds) as avg_time,
@@ -2437,33 +3261,34 @@ SELECT
 FROM zone_buckets zb
 JOIN activity_zones az ON zb.activity_zone_id = az.id
 JOIN activities a ON az.activity_id = a.id
-WHERE az.zone_type = 'power'
+WHERE az.zone_type = 'heartrate' 
   AND a.start_date >= ? AND a.start_date <= ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number
 `
 
-type GetPowerZoneSummaryInRangeParams struct {
+type GetHeartRateZoneSummaryInRangeParams struct {
 	StartDate   sql.NullTime `json:"start_date"`
 	StartDate_2 sql.NullTime `json:"start_date_2"`
 }
 
-type GetPowerZoneSummaryInRangeRow struct {
+type GetHeartRateZoneSummaryInRangeRow struct {
 	ZoneNumber    int64           `json:"zone_number"`
 	TotalTime     sql.NullFloat64 `json:"total_time"`
 	AvgTime       sql.NullFloat64 `json:"avg_time"`
 	ActivityCount int64           `json:"activity_count"`
 }
 
-func (q *Queries) GetPowerZoneSummaryInRange(ctx context.Context, arg GetPowerZoneSummaryInRangeParams) ([]GetPowerZoneSummaryInRangeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPowerZoneSummaryInRange, arg.StartDate, arg.StartDate_2)
+func (q *Queries) GetHeartRateZoneSummaryInRange(ctx context.Context, arg GetHeartRateZoneSummaryInRangeParams) ([]GetHeartRateZoneSummaryInRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getHeartRateZoneSummaryInRange, arg.StartDate, arg.StartDate_2)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetPowerZoneSummaryInRangeRow{}
+	items := []GetHeartRateZoneSummaryInRangeRow{}
 	for rows.Next() {
-		var i GetPowerZoneSummaryInRangeRow
+		var i GetHeartRateZoneSummaryInRangeRow
 		if err := rows.Scan(
 			&i.ZoneNumber,
 			&i.TotalTime,
@@ -2483,402 +3308,3435 @@ func (q *Queries) GetPowerZoneSummaryInRange(ctx context.Context, arg GetPowerZo
 	return items, nil
 }
 
-const getRecentActivities = `-- name: GetRecentActivities :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities ORDER BY start_date DESC LIMIT ?
-`
-
-func (q *Queries) GetRecentActivities(ctx context.Context, limit int64) ([]Activity, error) {
-	rows, err := q.db.QueryContext(ctx, getRecentActivities, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Activity{}
-	for rows.Next() {
-		var i Activity
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Distance,
-			&i.MovingTime,
-			&i.ElapsedTime,
-			&i.TotalElevationGain,
-			&i.Type,
-			&i.SportType,
-			&i.StartDate,
-			&i.StartDateLocal,
-			&i.Timezone,
-			&i.AverageSpeed,
-			&i.MaxSpeed,
-			&i.AverageCadence,
-			&i.AverageHeartrate,
-			&i.MaxHeartrate,
-			&i.Calories,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getSpeedSummary = `-- name: GetSpeedSummary :one
+const getHeartrateSummary = `-- name: GetHeartrateSummary :one
 SELECT 
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(MIN(average_speed), 0) as min_avg_speed,
-    COALESCE(MAX(average_speed), 0) as max_avg_speed,
-    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
+    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
+    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE average_speed IS NOT NULL AND average_speed > 0
+WHERE average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetSpeedSummaryRow struct {
-	AvgSpeed        interface{} `json:"avg_speed"`
-	MinAvgSpeed     interface{} `json:"min_avg_speed"`
-	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
-	OverallMaxSpeed interface{} `json:"overall_max_speed"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetHeartrateSummaryRow struct {
+	AvgHeartrate        interface{} `json:"avg_heartrate"`
+	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
+	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
+	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
+	ActivityCount       int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetSpeedSummary(ctx context.Context) (GetSpeedSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getSpeedSummary)
-	var i GetSpeedSummaryRow
+func (q *Queries) GetHeartrateSummary(ctx context.Context) (GetHeartrateSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getHeartrateSummary)
+	var i GetHeartrateSummaryRow
 	err := row.Scan(
-		&i.AvgSpeed,
-		&i.MinAvgSpeed,
-		&i.MaxAvgSpeed,
-		&i.OverallMaxSpeed,
+		&i.AvgHeartrate,
+		&i.MinAvgHeartrate,
+		&i.MaxAvgHeartrate,
+		&i.OverallMaxHeartrate,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getSpeedSummaryByType = `-- name: GetSpeedSummaryByType :one
+const getHeartrateSummaryByType = `-- name: GetHeartrateSummaryByType :one
 SELECT 
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(MIN(average_speed), 0) as min_avg_speed,
-    COALESCE(MAX(average_speed), 0) as max_avg_speed,
-    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
+    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
+    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND average_speed IS NOT NULL AND average_speed > 0
+WHERE type = ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetSpeedSummaryByTypeRow struct {
-	AvgSpeed        interface{} `json:"avg_speed"`
-	MinAvgSpeed     interface{} `json:"min_avg_speed"`
-	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
-	OverallMaxSpeed interface{} `json:"overall_max_speed"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetHeartrateSummaryByTypeRow struct {
+	AvgHeartrate        interface{} `json:"avg_heartrate"`
+	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
+	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
+	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
+	ActivityCount       int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetSpeedSummaryByType(ctx context.Context, type_ sql.NullString) (GetSpeedSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getSpeedSummaryByType, type_)
-	var i GetSpeedSummaryByTypeRow
+func (q *Queries) GetHeartrateSummaryByType(ctx context.Context, type_ sql.NullString) (GetHeartrateSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getHeartrateSummaryByType, type_)
+	var i GetHeartrateSummaryByTypeRow
 	err := row.Scan(
-		&i.AvgSpeed,
-		&i.MinAvgSpeed,
-		&i.MaxAvgSpeed,
-		&i.OverallMaxSpeed,
+		&i.AvgHeartrate,
+		&i.MinAvgHeartrate,
+		&i.MaxAvgHeartrate,
+		&i.OverallMaxHeartrate,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getSpeedSummaryByTypeInRange = `-- name: GetSpeedSummaryByTypeInRange :one
+const getHeartrateSummaryByTypeInRange = `-- name: GetHeartrateSummaryByTypeInRange :one
 SELECT 
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(MIN(average_speed), 0) as min_avg_speed,
-    COALESCE(MAX(average_speed), 0) as max_avg_speed,
-    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
+    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
+    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetSpeedSummaryByTypeInRangeParams struct {
+type GetHeartrateSummaryByTypeInRangeParams struct {
 	Type        sql.NullString `json:"type"`
 	StartDate   sql.NullTime   `json:"start_date"`
 	StartDate_2 sql.NullTime   `json:"start_date_2"`
 }
 
-type GetSpeedSummaryByTypeInRangeRow struct {
-	AvgSpeed        interface{} `json:"avg_speed"`
-	MinAvgSpeed     interface{} `json:"min_avg_speed"`
-	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
-	OverallMaxSpeed interface{} `json:"overall_max_speed"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetHeartrateSummaryByTypeInRangeRow struct {
+	AvgHeartrate        interface{} `json:"avg_heartrate"`
+	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
+	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
+	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
+	ActivityCount       int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetSpeedSummaryByTypeInRange(ctx context.Context, arg GetSpeedSummaryByTypeInRangeParams) (GetSpeedSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getSpeedSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetSpeedSummaryByTypeInRangeRow
+func (q *Queries) GetHeartrateSummaryByTypeInRange(ctx context.Context, arg GetHeartrateSummaryByTypeInRangeParams) (GetHeartrateSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getHeartrateSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetHeartrateSummaryByTypeInRangeRow
 	err := row.Scan(
-		&i.AvgSpeed,
-		&i.MinAvgSpeed,
-		&i.MaxAvgSpeed,
-		&i.OverallMaxSpeed,
+		&i.AvgHeartrate,
+		&i.MinAvgHeartrate,
+		&i.MaxAvgHeartrate,
+		&i.OverallMaxHeartrate,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getSpeedSummaryInRange = `-- name: GetSpeedSummaryInRange :one
+const getHeartrateSummaryInRange = `-- name: GetHeartrateSummaryInRange :one
 SELECT 
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(MIN(average_speed), 0) as min_avg_speed,
-    COALESCE(MAX(average_speed), 0) as max_avg_speed,
-    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(MIN(average_heartrate), 0) as min_avg_heartrate,
+    COALESCE(MAX(average_heartrate), 0) as max_avg_heartrate,
+    COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0
+WHERE start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 `
 
-type GetSpeedSummaryInRangeParams struct {
+type GetHeartrateSummaryInRangeParams struct {
 	StartDate   sql.NullTime `json:"start_date"`
 	StartDate_2 sql.NullTime `json:"start_date_2"`
 }
 
-type GetSpeedSummaryInRangeRow struct {
-	AvgSpeed        interface{} `json:"avg_speed"`
-	MinAvgSpeed     interface{} `json:"min_avg_speed"`
-	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
-	OverallMaxSpeed interface{} `json:"overall_max_speed"`
-	ActivityCount   int64       `json:"activity_count"`
+type GetHeartrateSummaryInRangeRow struct {
+	AvgHeartrate        interface{} `json:"avg_heartrate"`
+	MinAvgHeartrate     interface{} `json:"min_avg_heartrate"`
+	MaxAvgHeartrate     interface{} `json:"max_avg_heartrate"`
+	OverallMaxHeartrate interface{} `json:"overall_max_heartrate"`
+	ActivityCount       int64       `json:"activity_count"`
 }
 
-func (q *Queries) GetSpeedSummaryInRange(ctx context.Context, arg GetSpeedSummaryInRangeParams) (GetSpeedSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getSpeedSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetSpeedSummaryInRangeRow
+func (q *Queries) GetHeartrateSummaryInRange(ctx context.Context, arg GetHeartrateSummaryInRangeParams) (GetHeartrateSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getHeartrateSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetHeartrateSummaryInRangeRow
 	err := row.Scan(
-		&i.AvgSpeed,
-		&i.MinAvgSpeed,
-		&i.MaxAvgSpeed,
-		&i.OverallMaxSpeed,
+		&i.AvgHeartrate,
+		&i.MinAvgHeartrate,
+		&i.MaxAvgHeartrate,
+		&i.OverallMaxHeartrate,
 		&i.ActivityCount,
 	)
 	return i, err
 }
 
-const getTrainingSummary = `-- name: GetTrainingSummary :one
-
-SELECT
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
-FROM activities
+const getHighestElevationActivity = `-- name: GetHighestElevationActivity :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY total_elevation_gain DESC
+LIMIT 1
 `
 
-type GetTrainingSummaryRow struct {
-	ActivityCount   int64       `json:"activity_count"`
-	TotalDistance   interface{} `json:"total_distance"`
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgSpeed        interface{} `json:"avg_speed"`
-	AvgHeartrate    interface{} `json:"avg_heartrate"`
-	TotalCalories   interface{} `json:"total_calories"`
-	TotalElevation  interface{} `json:"total_elevation"`
-}
-
-// Training summary queries
-func (q *Queries) GetTrainingSummary(ctx context.Context) (GetTrainingSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getTrainingSummary)
-	var i GetTrainingSummaryRow
+func (q *Queries) GetHighestElevationActivity(ctx context.Context) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getHighestElevationActivity)
+	var i Activity
 	err := row.Scan(
-		&i.ActivityCount,
-		&i.TotalDistance,
-		&i.TotalMovingTime,
-		&i.AvgSpeed,
-		&i.AvgHeartrate,
-		&i.TotalCalories,
-		&i.TotalElevation,
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
 	)
 	return i, err
 }
 
-const getTrainingSummaryByType = `-- name: GetTrainingSummaryByType :one
-SELECT
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
-FROM activities
-WHERE type = ?
+const getHighestElevationActivityByType = `-- name: GetHighestElevationActivityByType :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE type = ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY total_elevation_gain DESC
+LIMIT 1
 `
 
-type GetTrainingSummaryByTypeRow struct {
-	ActivityCount   int64       `json:"activity_count"`
-	TotalDistance   interface{} `json:"total_distance"`
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgSpeed        interface{} `json:"avg_speed"`
-	AvgHeartrate    interface{} `json:"avg_heartrate"`
-	TotalCalories   interface{} `json:"total_calories"`
-	TotalElevation  interface{} `json:"total_elevation"`
+func (q *Queries) GetHighestElevationActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getHighestElevationActivityByType, type_)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
+	return i, err
 }
 
-func (q *Queries) GetTrainingSummaryByType(ctx context.Context, type_ sql.NullString) (GetTrainingSummaryByTypeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTrainingSummaryByType, type_)
-	var i GetTrainingSummaryByTypeRow
+const getLatestActivity = `-- name: GetLatestActivity :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities ORDER BY start_date DESC LIMIT 1
+`
+
+func (q *Queries) GetLatestActivity(ctx context.Context) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getLatestActivity)
+	var i Activity
 	err := row.Scan(
-		&i.ActivityCount,
-		&i.TotalDistance,
-		&i.TotalMovingTime,
-		&i.AvgSpeed,
-		&i.AvgHeartrate,
-		&i.TotalCalories,
-		&i.TotalElevation,
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
 	)
 	return i, err
 }
 
-const getTrainingSummaryByTypeInRange = `-- name: GetTrainingSummaryByTypeInRange :one
-SELECT
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
-FROM activities
-WHERE type = ? AND start_date >= ? AND start_date <= ?
+const getLatestActivityDate = `-- name: GetLatestActivityDate :one
+SELECT MAX(start_date) as latest_date FROM activities
 `
 
-type GetTrainingSummaryByTypeInRangeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
+func (q *Queries) GetLatestActivityDate(ctx context.Context) (interface{}, error) {
+	row := q.db.QueryRowContext(ctx, getLatestActivityDate)
+	var latest_date interface{}
+	err := row.Scan(&latest_date)
+	return latest_date, err
 }
 
-type GetTrainingSummaryByTypeInRangeRow struct {
-	ActivityCount   int64       `json:"activity_count"`
-	TotalDistance   interface{} `json:"total_distance"`
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgSpeed        interface{} `json:"avg_speed"`
-	AvgHeartrate    interface{} `json:"avg_heartrate"`
-	TotalCalories   interface{} `json:"total_calories"`
-	TotalElevation  interface{} `json:"total_elevation"`
+const getLatestActivityTimezone = `-- name: GetLatestActivityTimezone :one
+SELECT timezone FROM activities
+WHERE timezone IS NOT NULL AND timezone != ''
+ORDER BY start_date DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestActivityTimezone(ctx context.Context) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, getLatestActivityTimezone)
+	var timezone sql.NullString
+	err := row.Scan(&timezone)
+	return timezone, err
 }
 
-func (q *Queries) GetTrainingSummaryByTypeInRange(ctx context.Context, arg GetTrainingSummaryByTypeInRangeParams) (GetTrainingSummaryByTypeInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTrainingSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
-	var i GetTrainingSummaryByTypeInRangeRow
+const getLatestThreshold = `-- name: GetLatestThreshold :one
+SELECT id, metric, sport, value, previous_value, activity_id, detected_on, created_at FROM threshold_history
+WHERE metric = ? AND sport = ?
+ORDER BY detected_on DESC, id DESC
+LIMIT 1
+`
+
+type GetLatestThresholdParams struct {
+	Metric string `json:"metric"`
+	Sport  string `json:"sport"`
+}
+
+func (q *Queries) GetLatestThreshold(ctx context.Context, arg GetLatestThresholdParams) (ThresholdHistory, error) {
+	row := q.db.QueryRowContext(ctx, getLatestThreshold, arg.Metric, arg.Sport)
+	var i ThresholdHistory
 	err := row.Scan(
-		&i.ActivityCount,
-		&i.TotalDistance,
-		&i.TotalMovingTime,
-		&i.AvgSpeed,
-		&i.AvgHeartrate,
-		&i.TotalCalories,
-		&i.TotalElevation,
-	)
+		&i.ID,
+		&i.Metric,
+		&i.Sport,
+		&i.Value,
+		&i.PreviousValue,
+		&i.ActivityID,
+		&i.DetectedOn,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLongestDistanceActivity = `-- name: GetLongestDistanceActivity :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY distance DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLongestDistanceActivity(ctx context.Context) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getLongestDistanceActivity)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
+	return i, err
+}
+
+const getLongestDistanceActivityByType = `-- name: GetLongestDistanceActivityByType :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE type = ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY distance DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLongestDistanceActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getLongestDistanceActivityByType, type_)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
+	return i, err
+}
+
+const getLongestDurationActivity = `-- name: GetLongestDurationActivity :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY moving_time DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLongestDurationActivity(ctx context.Context) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getLongestDurationActivity)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
+	return i, err
+}
+
+const getLongestDurationActivityByType = `-- name: GetLongestDurationActivityByType :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE type = ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY moving_time DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLongestDurationActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getLongestDurationActivityByType, type_)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
+	return i, err
+}
+
+const getMostCaloriesActivity = `-- name: GetMostCaloriesActivity :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY calories DESC
+LIMIT 1
+`
+
+func (q *Queries) GetMostCaloriesActivity(ctx context.Context) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getMostCaloriesActivity)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
+	return i, err
+}
+
+const getMostCaloriesActivityByType = `-- name: GetMostCaloriesActivityByType :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE type = ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+ORDER BY calories DESC
+LIMIT 1
+`
+
+func (q *Queries) GetMostCaloriesActivityByType(ctx context.Context, type_ sql.NullString) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getMostCaloriesActivityByType, type_)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
 	return i, err
 }
 
-const getTrainingSummaryInRange = `-- name: GetTrainingSummaryInRange :one
-SELECT
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_moving_time,
-    COALESCE(AVG(average_speed), 0) as avg_speed,
-    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
-FROM activities
-WHERE start_date >= ? AND start_date <= ?
+const getMostKudoedActivities = `-- name: GetMostKudoedActivities :many
+SELECT a.id, a.name, a.type, a.start_date_local, a.distance, s.kudos_count, s.comment_count
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ? AND (s.kudos_count > 0 OR s.comment_count > 0)
+ORDER BY s.kudos_count DESC, s.comment_count DESC, a.start_date DESC
+LIMIT ?
+`
+
+type GetMostKudoedActivitiesParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+	Limit       int64        `json:"limit"`
+}
+
+type GetMostKudoedActivitiesRow struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Type           sql.NullString  `json:"type"`
+	StartDateLocal sql.NullTime    `json:"start_date_local"`
+	Distance       sql.NullFloat64 `json:"distance"`
+	KudosCount     int64           `json:"kudos_count"`
+	CommentCount   int64           `json:"comment_count"`
+}
+
+func (q *Queries) GetMostKudoedActivities(ctx context.Context, arg GetMostKudoedActivitiesParams) ([]GetMostKudoedActivitiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMostKudoedActivities, arg.StartDate, arg.StartDate_2, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMostKudoedActivitiesRow{}
+	for rows.Next() {
+		var i GetMostKudoedActivitiesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Type,
+			&i.StartDateLocal,
+			&i.Distance,
+			&i.KudosCount,
+			&i.CommentCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOldestActivity = `-- name: GetOldestActivity :one
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities ORDER BY start_date ASC LIMIT 1
+`
+
+func (q *Queries) GetOldestActivity(ctx context.Context) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, getOldestActivity)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElapsedTime,
+		&i.TotalElevationGain,
+		&i.Type,
+		&i.SportType,
+		&i.StartDate,
+		&i.StartDateLocal,
+		&i.Timezone,
+		&i.AverageSpeed,
+		&i.MaxSpeed,
+		&i.AverageCadence,
+		&i.AverageHeartrate,
+		&i.MaxHeartrate,
+		&i.Calories,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SummaryPolyline,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.WorkoutType,
+		&i.WorkoutCategory,
+		&i.WorkoutConfidence,
+		&i.WorkoutClassifiedAt,
+	)
+	return i, err
+}
+
+const getOldestActivityDate = `-- name: GetOldestActivityDate :one
+SELECT MIN(start_date) as oldest_date FROM activities
+`
+
+func (q *Queries) GetOldestActivityDate(ctx context.Context) (interface{}, error) {
+	row := q.db.QueryRowContext(ctx, getOldestActivityDate)
+	var oldest_date interface{}
+	err := row.Scan(&oldest_date)
+	return oldest_date, err
+}
+
+const getPaceZoneSummary = `-- name: GetPaceZoneSummary :many
+SELECT
+    zb.zone_number,
+    SUM(zb.time_seconds) as total_time,
+    AVG(zb.time_seconds) as avg_time,
+    COUNT(*) as activity_count
+FROM zone_buckets zb
+JOIN activity_zones az ON zb.activity_zone_id = az.id
+JOIN activities a ON az.activity_id = a.id
+WHERE az.zone_type = 'pace'
+  AND (? IS NULL OR a.type = ?)
+  AND (? IS NULL OR a.start_date >= ?)
+  AND (? IS NULL OR a.start_date <= ?)
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY zb.zone_number
+ORDER BY zb.zone_number
+`
+
+type GetPaceZoneSummaryParams struct {
+	Column1     interface{}    `json:"column_1"`
+	Type        sql.NullString `json:"type"`
+	Column3     interface{}    `json:"column_3"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	Column5     interface{}    `json:"column_5"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetPaceZoneSummaryRow struct {
+	ZoneNumber    int64           `json:"zone_number"`
+	TotalTime     sql.NullFloat64 `json:"total_time"`
+	AvgTime       sql.NullFloat64 `json:"avg_time"`
+	ActivityCount int64           `json:"activity_count"`
+}
+
+func (q *Queries) GetPaceZoneSummary(ctx context.Context, arg GetPaceZoneSummaryParams) ([]GetPaceZoneSummaryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPaceZoneSummary,
+		arg.Column1,
+		arg.Type,
+		arg.Column3,
+		arg.StartDate,
+		arg.Column5,
+		arg.StartDate_2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPaceZoneSummaryRow{}
+	for rows.Next() {
+		var i GetPaceZoneSummaryRow
+		if err := rows.Scan(
+			&i.ZoneNumber,
+			&i.TotalTime,
+			&i.AvgTime,
+			&i.ActivityCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPeriodStats = `-- name: GetPeriodStats :one
+
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed
+FROM activities
+WHERE start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetPeriodStatsParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetPeriodStatsRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+}
+
+// Period comparison queries with detailed metrics
+func (q *Queries) GetPeriodStats(ctx context.Context, arg GetPeriodStatsParams) (GetPeriodStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getPeriodStats, arg.StartDate, arg.StartDate_2)
+	var i GetPeriodStatsRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+		&i.AvgSpeed,
+	)
+	return i, err
+}
+
+const getPeriodStatsByType = `-- name: GetPeriodStatsByType :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed
+FROM activities
+WHERE type = ? AND start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetPeriodStatsByTypeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetPeriodStatsByTypeRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+}
+
+func (q *Queries) GetPeriodStatsByType(ctx context.Context, arg GetPeriodStatsByTypeParams) (GetPeriodStatsByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getPeriodStatsByType, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetPeriodStatsByTypeRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+		&i.AvgSpeed,
+	)
+	return i, err
+}
+
+const getPowerZoneSummary = `-- name: GetPowerZoneSummary :many
+SELECT 
+    zb.zone_number,
+    SUM(zb.time_seconds) as total_time,
+    AVG(zb.time_seconds) as avg_time,
+    COUNT(*) as activity_count
+FROM zone_buckets zb
+JOIN activity_zones az ON zb.activity_zone_id = az.id
+WHERE az.zone_type = 'power'
+  AND az.activity_id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY zb.zone_number
+ORDER BY zb.zone_number
+`
+
+type GetPowerZoneSummaryRow struct {
+	ZoneNumber    int64           `json:"zone_number"`
+	TotalTime     sql.NullFloat64 `json:"total_time"`
+	AvgTime       sql.NullFloat64 `json:"avg_time"`
+	ActivityCount int64           `json:"activity_count"`
+}
+
+func (q *Queries) GetPowerZoneSummary(ctx context.Context) ([]GetPowerZoneSummaryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPowerZoneSummary)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPowerZoneSummaryRow{}
+	for rows.Next() {
+		var i GetPowerZoneSummaryRow
+		if err := rows.Scan(
+			&i.ZoneNumber,
+			&i.TotalTime,
+			&i.AvgTime,
+			&i.ActivityCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPowerZoneSummaryByType = `-- name: GetPowerZoneSummaryByType :many
+SELECT 
+    zb.zone_number,
+    SUM(zb.time_seconds) as total_time,
+    AVG(zb.time_seconds) as avg_time,
+    COUNT(*) as activity_count
+FROM zone_buckets zb
+JOIN activity_zones az ON zb.activity_zone_id = az.id
+JOIN activities a ON az.activity_id = a.id
+WHERE az.zone_type = 'power' AND a.type = ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY zb.zone_number
+ORDER BY zb.zone_number
+`
+
+type GetPowerZoneSummaryByTypeRow struct {
+	ZoneNumber    int64           `json:"zone_number"`
+	TotalTime     sql.NullFloat64 `json:"total_time"`
+	AvgTime       sql.NullFloat64 `json:"avg_time"`
+	ActivityCount int64           `json:"activity_count"`
+}
+
+func (q *Queries) GetPowerZoneSummaryByType(ctx context.Context, type_ sql.NullString) ([]GetPowerZoneSummaryByTypeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPowerZoneSummaryByType, type_)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPowerZoneSummaryByTypeRow{}
+	for rows.Next() {
+		var i GetPowerZoneSummaryByTypeRow
+		if err := rows.Scan(
+			&i.ZoneNumber,
+			&i.TotalTime,
+			&i.AvgTime,
+			&i.ActivityCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPowerZoneSummaryInRange = `-- name: GetPowerZoneSummaryInRange :many
+SELECT
+    zb.zone_number,
+    SUM(zb.time_seconds) as total_time,
+    AVG(zb.time_seconds) as avg_time,
+    COUNT(*) as activity_count
+FROM zone_buckets zb
+JOIN activity_zones az ON zb.activity_zone_id = az.id
+JOIN activities a ON az.activity_id = a.id
+WHERE az.zone_type = 'power'
+  AND a.start_date >= ? AND a.start_date <= ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY zb.zone_number
+ORDER BY zb.zone_number
+`
+
+type GetPowerZoneSummaryInRangeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetPowerZoneSummaryInRangeRow struct {
+	ZoneNumber    int64           `json:"zone_number"`
+	TotalTime     sql.NullFloat64 `json:"total_time"`
+	AvgTime       sql.NullFloat64 `json:"avg_time"`
+	ActivityCount int64           `json:"activity_count"`
+}
+
+func (q *Queries) GetPowerZoneSummaryInRange(ctx context.Context, arg GetPowerZoneSummaryInRangeParams) ([]GetPowerZoneSummaryInRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPowerZoneSummaryInRange, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPowerZoneSummaryInRangeRow{}
+	for rows.Next() {
+		var i GetPowerZoneSummaryInRangeRow
+		if err := rows.Scan(
+			&i.ZoneNumber,
+			&i.TotalTime,
+			&i.AvgTime,
+			&i.ActivityCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRace = `-- name: GetRace :one
+SELECT id, name, race_date, distance, activity_type, priority, goal_time, notes, created_at FROM races WHERE id = ?
+`
+
+func (q *Queries) GetRace(ctx context.Context, id int64) (Race, error) {
+	row := q.db.QueryRowContext(ctx, getRace, id)
+	var i Race
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.RaceDate,
+		&i.Distance,
+		&i.ActivityType,
+		&i.Priority,
+		&i.GoalTime,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRateLimitBudget = `-- name: GetRateLimitBudget :one
+SELECT id, window_start, window_usage, window_limit, day_start, daily_usage, daily_limit, updated_at FROM rate_limit_budget WHERE id = 1
+`
+
+func (q *Queries) GetRateLimitBudget(ctx context.Context) (RateLimitBudget, error) {
+	row := q.db.QueryRowContext(ctx, getRateLimitBudget)
+	var i RateLimitBudget
+	err := row.Scan(
+		&i.ID,
+		&i.WindowStart,
+		&i.WindowUsage,
+		&i.WindowLimit,
+		&i.DayStart,
+		&i.DailyUsage,
+		&i.DailyLimit,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getRecentActivities = `-- name: GetRecentActivities :many
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities ORDER BY start_date DESC LIMIT ?
+`
+
+func (q *Queries) GetRecentActivities(ctx context.Context, limit int64) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentActivities, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRouteEffort = `-- name: GetRouteEffort :one
+SELECT activity_id, route_id, shape_distance FROM route_efforts WHERE activity_id = ?
+`
+
+func (q *Queries) GetRouteEffort(ctx context.Context, activityID int64) (RouteEffort, error) {
+	row := q.db.QueryRowContext(ctx, getRouteEffort, activityID)
+	var i RouteEffort
+	err := row.Scan(
+		&i.ActivityID,
+		&i.RouteID,
+		&i.ShapeDistance,
+	)
+	return i, err
+}
+
+const getRouteEffortActivities = `-- name: GetRouteEffortActivities :many
+SELECT a.id, a.name, a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain, a.type, a.sport_type, a.start_date, a.start_date_local, a.timezone, a.average_speed, a.max_speed, a.average_cadence, a.average_heartrate, a.max_heartrate, a.calories, a.created_at, a.updated_at, a.summary_polyline, a.start_lat, a.start_lng, a.end_lat, a.end_lng, a.workout_type, a.workout_category, a.workout_confidence, a.workout_classified_at FROM activities a
+JOIN route_efforts re ON re.activity_id = a.id
+WHERE re.route_id = ?
+ORDER BY a.start_date
+`
+
+func (q *Queries) GetRouteEffortActivities(ctx context.Context, routeID int64) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, getRouteEffortActivities, routeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoutesDetected = `-- name: GetRoutesDetected :one
+SELECT id, name, activity_type, representative_activity_id, polyline, distance, start_lat, start_lng, end_lat, end_lng, created_at FROM routes_detected WHERE id = ?
+`
+
+func (q *Queries) GetRoutesDetected(ctx context.Context, id int64) (RoutesDetected, error) {
+	row := q.db.QueryRowContext(ctx, getRoutesDetected, id)
+	var i RoutesDetected
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ActivityType,
+		&i.RepresentativeActivityID,
+		&i.Polyline,
+		&i.Distance,
+		&i.StartLat,
+		&i.StartLng,
+		&i.EndLat,
+		&i.EndLng,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSavedRouteGPX = `-- name: GetSavedRouteGPX :one
+SELECT id, name, gpx FROM saved_routes WHERE id = ? LIMIT 1
+`
+
+type GetSavedRouteGPXRow struct {
+	ID   int64          `json:"id"`
+	Name string         `json:"name"`
+	Gpx  sql.NullString `json:"gpx"`
+}
+
+func (q *Queries) GetSavedRouteGPX(ctx context.Context, id int64) (GetSavedRouteGPXRow, error) {
+	row := q.db.QueryRowContext(ctx, getSavedRouteGPX, id)
+	var i GetSavedRouteGPXRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Gpx,
+	)
+	return i, err
+}
+
+const getSocialByHour = `-- name: GetSocialByHour :many
+SELECT
+    strftime('%H', substr(a.start_date_local, 1, 19)) as hour,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY strftime('%H', substr(a.start_date_local, 1, 19))
+ORDER BY hour
+`
+
+type GetSocialByHourParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetSocialByHourRow struct {
+	Hour          interface{} `json:"hour"`
+	ActivityCount int64       `json:"activity_count"`
+	TotalKudos    interface{} `json:"total_kudos"`
+	TotalComments interface{} `json:"total_comments"`
+}
+
+func (q *Queries) GetSocialByHour(ctx context.Context, arg GetSocialByHourParams) ([]GetSocialByHourRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSocialByHour, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSocialByHourRow{}
+	for rows.Next() {
+		var i GetSocialByHourRow
+		if err := rows.Scan(
+			&i.Hour,
+			&i.ActivityCount,
+			&i.TotalKudos,
+			&i.TotalComments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSocialByMonth = `-- name: GetSocialByMonth :many
+SELECT
+    strftime('%Y-%m', substr(a.start_date_local, 1, 19)) as month,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY strftime('%Y-%m', substr(a.start_date_local, 1, 19))
+ORDER BY month
+`
+
+type GetSocialByMonthParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetSocialByMonthRow struct {
+	Month         interface{} `json:"month"`
+	ActivityCount int64       `json:"activity_count"`
+	TotalKudos    interface{} `json:"total_kudos"`
+	TotalComments interface{} `json:"total_comments"`
+}
+
+func (q *Queries) GetSocialByMonth(ctx context.Context, arg GetSocialByMonthParams) ([]GetSocialByMonthRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSocialByMonth, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSocialByMonthRow{}
+	for rows.Next() {
+		var i GetSocialByMonthRow
+		if err := rows.Scan(
+			&i.Month,
+			&i.ActivityCount,
+			&i.TotalKudos,
+			&i.TotalComments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSocialByType = `-- name: GetSocialByType :many
+SELECT
+    a.type,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY a.type
+ORDER BY total_kudos DESC
+`
+
+type GetSocialByTypeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetSocialByTypeRow struct {
+	Type          sql.NullString `json:"type"`
+	ActivityCount int64          `json:"activity_count"`
+	TotalKudos    interface{}    `json:"total_kudos"`
+	TotalComments interface{}    `json:"total_comments"`
+}
+
+func (q *Queries) GetSocialByType(ctx context.Context, arg GetSocialByTypeParams) ([]GetSocialByTypeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSocialByType, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSocialByTypeRow{}
+	for rows.Next() {
+		var i GetSocialByTypeRow
+		if err := rows.Scan(
+			&i.Type,
+			&i.ActivityCount,
+			&i.TotalKudos,
+			&i.TotalComments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSocialByWeekday = `-- name: GetSocialByWeekday :many
+SELECT
+    strftime('%w', substr(a.start_date_local, 1, 19)) as weekday,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY strftime('%w', substr(a.start_date_local, 1, 19))
+ORDER BY weekday
+`
+
+type GetSocialByWeekdayParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetSocialByWeekdayRow struct {
+	Weekday       interface{} `json:"weekday"`
+	ActivityCount int64       `json:"activity_count"`
+	TotalKudos    interface{} `json:"total_kudos"`
+	TotalComments interface{} `json:"total_comments"`
+}
+
+func (q *Queries) GetSocialByWeekday(ctx context.Context, arg GetSocialByWeekdayParams) ([]GetSocialByWeekdayRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSocialByWeekday, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSocialByWeekdayRow{}
+	for rows.Next() {
+		var i GetSocialByWeekdayRow
+		if err := rows.Scan(
+			&i.Weekday,
+			&i.ActivityCount,
+			&i.TotalKudos,
+			&i.TotalComments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSocialSummary = `-- name: GetSocialSummary :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments,
+    COALESCE(SUM(CASE WHEN s.kudos_count > 0 THEN 1 ELSE 0 END), 0) as with_kudos,
+    COALESCE(SUM(CASE WHEN s.athlete_count > 1 THEN 1 ELSE 0 END), 0) as group_activities
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+`
+
+type GetSocialSummaryParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetSocialSummaryRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalKudos      interface{} `json:"total_kudos"`
+	TotalComments   interface{} `json:"total_comments"`
+	WithKudos       interface{} `json:"with_kudos"`
+	GroupActivities interface{} `json:"group_activities"`
+}
+
+func (q *Queries) GetSocialSummary(ctx context.Context, arg GetSocialSummaryParams) (GetSocialSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getSocialSummary, arg.StartDate, arg.StartDate_2)
+	var i GetSocialSummaryRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalKudos,
+		&i.TotalComments,
+		&i.WithKudos,
+		&i.GroupActivities,
+	)
+	return i, err
+}
+
+const getSpeedSummary = `-- name: GetSpeedSummary :one
+SELECT 
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(MIN(average_speed), 0) as min_avg_speed,
+    COALESCE(MAX(average_speed), 0) as max_avg_speed,
+    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetSpeedSummaryRow struct {
+	AvgSpeed        interface{} `json:"avg_speed"`
+	MinAvgSpeed     interface{} `json:"min_avg_speed"`
+	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
+	OverallMaxSpeed interface{} `json:"overall_max_speed"`
+	ActivityCount   int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetSpeedSummary(ctx context.Context) (GetSpeedSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getSpeedSummary)
+	var i GetSpeedSummaryRow
+	err := row.Scan(
+		&i.AvgSpeed,
+		&i.MinAvgSpeed,
+		&i.MaxAvgSpeed,
+		&i.OverallMaxSpeed,
+		&i.ActivityCount,
+	)
+	return i, err
+}
+
+const getSpeedSummaryByType = `-- name: GetSpeedSummaryByType :one
+SELECT 
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(MIN(average_speed), 0) as min_avg_speed,
+    COALESCE(MAX(average_speed), 0) as max_avg_speed,
+    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE type = ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetSpeedSummaryByTypeRow struct {
+	AvgSpeed        interface{} `json:"avg_speed"`
+	MinAvgSpeed     interface{} `json:"min_avg_speed"`
+	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
+	OverallMaxSpeed interface{} `json:"overall_max_speed"`
+	ActivityCount   int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetSpeedSummaryByType(ctx context.Context, type_ sql.NullString) (GetSpeedSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getSpeedSummaryByType, type_)
+	var i GetSpeedSummaryByTypeRow
+	err := row.Scan(
+		&i.AvgSpeed,
+		&i.MinAvgSpeed,
+		&i.MaxAvgSpeed,
+		&i.OverallMaxSpeed,
+		&i.ActivityCount,
+	)
+	return i, err
+}
+
+const getSpeedSummaryByTypeInRange = `-- name: GetSpeedSummaryByTypeInRange :one
+SELECT 
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(MIN(average_speed), 0) as min_avg_speed,
+    COALESCE(MAX(average_speed), 0) as max_avg_speed,
+    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetSpeedSummaryByTypeInRangeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetSpeedSummaryByTypeInRangeRow struct {
+	AvgSpeed        interface{} `json:"avg_speed"`
+	MinAvgSpeed     interface{} `json:"min_avg_speed"`
+	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
+	OverallMaxSpeed interface{} `json:"overall_max_speed"`
+	ActivityCount   int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetSpeedSummaryByTypeInRange(ctx context.Context, arg GetSpeedSummaryByTypeInRangeParams) (GetSpeedSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getSpeedSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetSpeedSummaryByTypeInRangeRow
+	err := row.Scan(
+		&i.AvgSpeed,
+		&i.MinAvgSpeed,
+		&i.MaxAvgSpeed,
+		&i.OverallMaxSpeed,
+		&i.ActivityCount,
+	)
+	return i, err
+}
+
+const getSpeedSummaryInRange = `-- name: GetSpeedSummaryInRange :one
+SELECT 
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(MIN(average_speed), 0) as min_avg_speed,
+    COALESCE(MAX(average_speed), 0) as max_avg_speed,
+    COALESCE(MAX(max_speed), 0) as overall_max_speed,
+    COUNT(*) as activity_count
+FROM activities 
+WHERE start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetSpeedSummaryInRangeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetSpeedSummaryInRangeRow struct {
+	AvgSpeed        interface{} `json:"avg_speed"`
+	MinAvgSpeed     interface{} `json:"min_avg_speed"`
+	MaxAvgSpeed     interface{} `json:"max_avg_speed"`
+	OverallMaxSpeed interface{} `json:"overall_max_speed"`
+	ActivityCount   int64       `json:"activity_count"`
+}
+
+func (q *Queries) GetSpeedSummaryInRange(ctx context.Context, arg GetSpeedSummaryInRangeParams) (GetSpeedSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getSpeedSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetSpeedSummaryInRangeRow
+	err := row.Scan(
+		&i.AvgSpeed,
+		&i.MinAvgSpeed,
+		&i.MaxAvgSpeed,
+		&i.OverallMaxSpeed,
+		&i.ActivityCount,
+	)
+	return i, err
+}
+
+const getStoredTotalsByType = `-- name: GetStoredTotalsByType :one
+
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time
+FROM (
+    SELECT
+        COALESCE(o.original_type, a.type) as type,
+        COALESCE(o.original_distance, a.distance) as distance,
+        a.moving_time,
+        a.start_date
+    FROM activities a
+    LEFT JOIN activity_overrides o ON o.activity_id = a.id
+) AS stored
+WHERE type = ?
+`
+
+type GetStoredTotalsByTypeRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+}
+
+// Totals of every stored activity by the type Strava gave it, excluded
+// activities included, to compare with the athlete's stats on Strava
+func (q *Queries) GetStoredTotalsByType(ctx context.Context, type_ sql.NullString) (GetStoredTotalsByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getStoredTotalsByType, type_)
+	var i GetStoredTotalsByTypeRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+	)
+	return i, err
+}
+
+const getStoredTotalsByTypeInRange = `-- name: GetStoredTotalsByTypeInRange :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time
+FROM (
+    SELECT
+        COALESCE(o.original_type, a.type) as type,
+        COALESCE(o.original_distance, a.distance) as distance,
+        a.moving_time,
+        a.start_date
+    FROM activities a
+    LEFT JOIN activity_overrides o ON o.activity_id = a.id
+) AS stored
+WHERE type = ? AND start_date >= ? AND start_date <= ?
+`
+
+type GetStoredTotalsByTypeInRangeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetStoredTotalsByTypeInRangeRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+}
+
+func (q *Queries) GetStoredTotalsByTypeInRange(ctx context.Context, arg GetStoredTotalsByTypeInRangeParams) (GetStoredTotalsByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getStoredTotalsByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetStoredTotalsByTypeInRangeRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+	)
+	return i, err
+}
+
+const getSyncState = `-- name: GetSyncState :one
+SELECT value FROM sync_state WHERE key = ?
+`
+
+func (q *Queries) GetSyncState(ctx context.Context, key string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getSyncState, key)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getTopSupporters = `-- name: GetTopSupporters :many
+SELECT athlete_name, SUM(kudos) as kudos, SUM(comments) as comments
+FROM (
+    SELECT k.athlete_name, 1 as kudos, 0 as comments
+    FROM activity_kudos k
+    JOIN activities a ON a.id = k.activity_id
+    WHERE a.start_date >= ? AND a.start_date <= ?
+    UNION ALL
+    SELECT c.athlete_name, 0 as kudos, 1 as comments
+    FROM activity_comments c
+    JOIN activities a ON a.id = c.activity_id
+    WHERE a.start_date >= ? AND a.start_date <= ?
+)
+GROUP BY athlete_name
+ORDER BY SUM(kudos) + SUM(comments) DESC, athlete_name
+LIMIT ?
+`
+
+type GetTopSupportersParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+	StartDate_3 sql.NullTime `json:"start_date_3"`
+	StartDate_4 sql.NullTime `json:"start_date_4"`
+	Limit       int64        `json:"limit"`
+}
+
+type GetTopSupportersRow struct {
+	AthleteName string      `json:"athlete_name"`
+	Kudos       interface{} `json:"kudos"`
+	Comments    interface{} `json:"comments"`
+}
+
+func (q *Queries) GetTopSupporters(ctx context.Context, arg GetTopSupportersParams) ([]GetTopSupportersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopSupporters,
+		arg.StartDate,
+		arg.StartDate_2,
+		arg.StartDate_3,
+		arg.StartDate_4,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTopSupportersRow{}
+	for rows.Next() {
+		var i GetTopSupportersRow
+		if err := rows.Scan(
+			&i.AthleteName,
+			&i.Kudos,
+			&i.Comments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainingSummary = `-- name: GetTrainingSummary :one
+
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetTrainingSummaryRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+	AvgHeartrate    interface{} `json:"avg_heartrate"`
+	TotalCalories   interface{} `json:"total_calories"`
+	TotalElevation  interface{} `json:"total_elevation"`
+}
+
+// Training summary queries
+func (q *Queries) GetTrainingSummary(ctx context.Context) (GetTrainingSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingSummary)
+	var i GetTrainingSummaryRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+		&i.AvgSpeed,
+		&i.AvgHeartrate,
+		&i.TotalCalories,
+		&i.TotalElevation,
+	)
+	return i, err
+}
+
+const getTrainingSummaryByType = `-- name: GetTrainingSummaryByType :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE type = ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetTrainingSummaryByTypeRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+	AvgHeartrate    interface{} `json:"avg_heartrate"`
+	TotalCalories   interface{} `json:"total_calories"`
+	TotalElevation  interface{} `json:"total_elevation"`
+}
+
+func (q *Queries) GetTrainingSummaryByType(ctx context.Context, type_ sql.NullString) (GetTrainingSummaryByTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingSummaryByType, type_)
+	var i GetTrainingSummaryByTypeRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+		&i.AvgSpeed,
+		&i.AvgHeartrate,
+		&i.TotalCalories,
+		&i.TotalElevation,
+	)
+	return i, err
+}
+
+const getTrainingSummaryByTypeInRange = `-- name: GetTrainingSummaryByTypeInRange :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE type = ? AND start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetTrainingSummaryByTypeInRangeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetTrainingSummaryByTypeInRangeRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+	AvgHeartrate    interface{} `json:"avg_heartrate"`
+	TotalCalories   interface{} `json:"total_calories"`
+	TotalElevation  interface{} `json:"total_elevation"`
+}
+
+func (q *Queries) GetTrainingSummaryByTypeInRange(ctx context.Context, arg GetTrainingSummaryByTypeInRangeParams) (GetTrainingSummaryByTypeInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingSummaryByTypeInRange, arg.Type, arg.StartDate, arg.StartDate_2)
+	var i GetTrainingSummaryByTypeInRangeRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+		&i.AvgSpeed,
+		&i.AvgHeartrate,
+		&i.TotalCalories,
+		&i.TotalElevation,
+	)
+	return i, err
+}
+
+const getTrainingSummaryFiltered = `-- name: GetTrainingSummaryFiltered :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetTrainingSummaryFilteredParams struct {
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+}
+
+type GetTrainingSummaryFilteredRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+	AvgHeartrate    interface{} `json:"avg_heartrate"`
+	TotalCalories   interface{} `json:"total_calories"`
+	TotalElevation  interface{} `json:"total_elevation"`
+}
+
+func (q *Queries) GetTrainingSummaryFiltered(ctx context.Context, arg GetTrainingSummaryFilteredParams) (GetTrainingSummaryFilteredRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingSummaryFiltered,
+		arg.Column1,
+		arg.Type,
+		arg.Column3,
+		arg.StartDate,
+		arg.Column5,
+		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
+	)
+	var i GetTrainingSummaryFilteredRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+		&i.AvgSpeed,
+		&i.AvgHeartrate,
+		&i.TotalCalories,
+		&i.TotalElevation,
+	)
+	return i, err
+}
+
+const getTrainingSummaryInRange = `-- name: GetTrainingSummaryInRange :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+`
+
+type GetTrainingSummaryInRangeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetTrainingSummaryInRangeRow struct {
+	ActivityCount   int64       `json:"activity_count"`
+	TotalDistance   interface{} `json:"total_distance"`
+	TotalMovingTime interface{} `json:"total_moving_time"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+	AvgHeartrate    interface{} `json:"avg_heartrate"`
+	TotalCalories   interface{} `json:"total_calories"`
+	TotalElevation  interface{} `json:"total_elevation"`
+}
+
+func (q *Queries) GetTrainingSummaryInRange(ctx context.Context, arg GetTrainingSummaryInRangeParams) (GetTrainingSummaryInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingSummaryInRange, arg.StartDate, arg.StartDate_2)
+	var i GetTrainingSummaryInRangeRow
+	err := row.Scan(
+		&i.ActivityCount,
+		&i.TotalDistance,
+		&i.TotalMovingTime,
+		&i.AvgSpeed,
+		&i.AvgHeartrate,
+		&i.TotalCalories,
+		&i.TotalElevation,
+	)
+	return i, err
+}
+
+const getWeeklyVolume = `-- name: GetWeeklyVolume :many
+
+SELECT
+    strftime('%Y-W%W', substr(start_date, 1, 19)) as week,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_duration,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-W%W', substr(start_date, 1, 19))
+ORDER BY week DESC
+`
+
+type GetWeeklyVolumeParams struct {
+	StartDate   sql.NullTime `json:"start_date"`
+	StartDate_2 sql.NullTime `json:"start_date_2"`
+}
+
+type GetWeeklyVolumeRow struct {
+	Week           interface{} `json:"week"`
+	ActivityCount  int64       `json:"activity_count"`
+	TotalDistance  interface{} `json:"total_distance"`
+	TotalDuration  interface{} `json:"total_duration"`
+	TotalCalories  interface{} `json:"total_calories"`
+	TotalElevation interface{} `json:"total_elevation"`
+}
+
+// Weekly volume queries for training load analysis
+func (q *Queries) GetWeeklyVolume(ctx context.Context, arg GetWeeklyVolumeParams) ([]GetWeeklyVolumeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWeeklyVolume, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetWeeklyVolumeRow{}
+	for rows.Next() {
+		var i GetWeeklyVolumeRow
+		if err := rows.Scan(
+			&i.Week,
+			&i.ActivityCount,
+			&i.TotalDistance,
+			&i.TotalDuration,
+			&i.TotalCalories,
+			&i.TotalElevation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWeeklyVolumeByType = `-- name: GetWeeklyVolumeByType :many
+SELECT
+    strftime('%Y-W%W', substr(start_date, 1, 19)) as week,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_duration,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE type = ? AND start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-W%W', substr(start_date, 1, 19))
+ORDER BY week DESC
+`
+
+type GetWeeklyVolumeByTypeParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type GetWeeklyVolumeByTypeRow struct {
+	Week           interface{} `json:"week"`
+	ActivityCount  int64       `json:"activity_count"`
+	TotalDistance  interface{} `json:"total_distance"`
+	TotalDuration  interface{} `json:"total_duration"`
+	TotalCalories  interface{} `json:"total_calories"`
+	TotalElevation interface{} `json:"total_elevation"`
+}
+
+func (q *Queries) GetWeeklyVolumeByType(ctx context.Context, arg GetWeeklyVolumeByTypeParams) ([]GetWeeklyVolumeByTypeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWeeklyVolumeByType, arg.Type, arg.StartDate, arg.StartDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetWeeklyVolumeByTypeRow{}
+	for rows.Next() {
+		var i GetWeeklyVolumeByTypeRow
+		if err := rows.Scan(
+			&i.Week,
+			&i.ActivityCount,
+			&i.TotalDistance,
+			&i.TotalDuration,
+			&i.TotalCalories,
+			&i.TotalElevation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkoutCategoryCounts = `-- name: GetWorkoutCategoryCounts :many
+SELECT type, workout_category, COUNT(*) as count FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
+  AND workout_category IS NOT NULL
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY type, workout_category
+ORDER BY count DESC
+`
+
+type GetWorkoutCategoryCountsParams struct {
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+}
+
+type GetWorkoutCategoryCountsRow struct {
+	Type            sql.NullString `json:"type"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+	Count           int64          `json:"count"`
+}
+
+func (q *Queries) GetWorkoutCategoryCounts(ctx context.Context, arg GetWorkoutCategoryCountsParams) ([]GetWorkoutCategoryCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkoutCategoryCounts,
+		arg.Column1,
+		arg.Type,
+		arg.Column3,
+		arg.StartDate,
+		arg.Column5,
+		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetWorkoutCategoryCountsRow{}
+	for rows.Next() {
+		var i GetWorkoutCategoryCountsRow
+		if err := rows.Scan(
+			&i.Type,
+			&i.WorkoutCategory,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getZoneBuckets = `-- name: GetZoneBuckets :many
+SELECT id, activity_zone_id, zone_number, min_value, max_value, time_seconds FROM zone_buckets WHERE activity_zone_id = ? ORDER BY zone_number
+`
+
+func (q *Queries) GetZoneBuckets(ctx context.Context, activityZoneID int64) ([]ZoneBucket, error) {
+	rows, err := q.db.QueryContext(ctx, getZoneBuckets, activityZoneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ZoneBucket{}
+	for rows.Next() {
+		var i ZoneBucket
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActivityZoneID,
+			&i.ZoneNumber,
+			&i.MinValue,
+			&i.MaxValue,
+			&i.TimeSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getZoneSourceCounts = `-- name: GetZoneSourceCounts :many
+SELECT source, COUNT(*) AS activity_count
+FROM activity_zones
+WHERE zone_type = ?
+  AND EXISTS (SELECT 1 FROM zone_buckets zb WHERE zb.activity_zone_id = activity_zones.id)
+GROUP BY source
+ORDER BY source
+`
+
+type GetZoneSourceCountsRow struct {
+	Source        string `json:"source"`
+	ActivityCount int64  `json:"activity_count"`
+}
+
+func (q *Queries) GetZoneSourceCounts(ctx context.Context, zoneType string) ([]GetZoneSourceCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getZoneSourceCounts, zoneType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetZoneSourceCountsRow{}
+	for rows.Next() {
+		var i GetZoneSourceCountsRow
+		if err := rows.Scan(
+			&i.Source,
+			&i.ActivityCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isActivityExcluded = `-- name: IsActivityExcluded :one
+SELECT EXISTS(SELECT 1 FROM excluded_activities WHERE activity_id = ?) AS excluded
+`
+
+func (q *Queries) IsActivityExcluded(ctx context.Context, activityID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, isActivityExcluded, activityID)
+	var excluded int64
+	err := row.Scan(&excluded)
+	return excluded, err
+}
+
+const listActivitiesForRouteMatch = `-- name: ListActivitiesForRouteMatch :many
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE type = ? AND distance >= ? AND distance <= ?
+  AND summary_polyline IS NOT NULL AND summary_polyline != ''
+ORDER BY start_date
+`
+
+type ListActivitiesForRouteMatchParams struct {
+	Type       sql.NullString  `json:"type"`
+	Distance   sql.NullFloat64 `json:"distance"`
+	Distance_2 sql.NullFloat64 `json:"distance_2"`
+}
+
+func (q *Queries) ListActivitiesForRouteMatch(ctx context.Context, arg ListActivitiesForRouteMatchParams) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesForRouteMatch, arg.Type, arg.Distance, arg.Distance_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivitiesNeedingClassification = `-- name: ListActivitiesNeedingClassification :many
+
+SELECT a.id, a.type, a.workout_type, a.moving_time, a.average_speed, a.average_heartrate
+FROM activities a
+LEFT JOIN activity_streams s ON s.activity_id = a.id
+WHERE a.id > ?
+  AND (a.workout_classified_at IS NULL
+       OR a.workout_classified_at < a.updated_at
+       OR a.workout_classified_at < s.fetched_at)
+ORDER BY a.id
+LIMIT ?
+`
+
+type ListActivitiesNeedingClassificationParams struct {
+	ID    int64 `json:"id"`
+	Limit int64 `json:"limit"`
+}
+
+type ListActivitiesNeedingClassificationRow struct {
+	ID               int64           `json:"id"`
+	Type             sql.NullString  `json:"type"`
+	WorkoutType      sql.NullInt64   `json:"workout_type"`
+	MovingTime       sql.NullInt64   `json:"moving_time"`
+	AverageSpeed     sql.NullFloat64 `json:"average_speed"`
+	AverageHeartrate sql.NullFloat64 `json:"average_heartrate"`
+}
+
+// ListActivitiesNeedingClassification pages by ID through activities never
+// classified, or updated or given streams since they were
+func (q *Queries) ListActivitiesNeedingClassification(ctx context.Context, arg ListActivitiesNeedingClassificationParams) ([]ListActivitiesNeedingClassificationRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesNeedingClassification, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActivitiesNeedingClassificationRow{}
+	for rows.Next() {
+		var i ListActivitiesNeedingClassificationRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.WorkoutType,
+			&i.MovingTime,
+			&i.AverageSpeed,
+			&i.AverageHeartrate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivitiesNeedingLocalZones = `-- name: ListActivitiesNeedingLocalZones :many
+SELECT s.activity_id FROM activity_streams s
+JOIN activities a ON a.id = s.activity_id
+LEFT JOIN activity_zones az ON az.activity_id = s.activity_id AND az.zone_type = ?
+WHERE az.id IS NULL
+  AND s.time_data IS NOT NULL
+  AND CASE ?
+      WHEN 'heartrate' THEN s.heartrate_data
+      WHEN 'power' THEN s.watts_data
+      WHEN 'pace' THEN CASE WHEN a.type IN ('Run', 'TrailRun', 'VirtualRun') THEN s.velocity_data END
+  END IS NOT NULL
+ORDER BY s.activity_id DESC
+LIMIT ?
+`
+
+type ListActivitiesNeedingLocalZonesParams struct {
+	ZoneType string      `json:"zone_type"`
+	Column2  interface{} `json:"column_2"`
+	Limit    int64       `json:"limit"`
+}
+
+func (q *Queries) ListActivitiesNeedingLocalZones(ctx context.Context, arg ListActivitiesNeedingLocalZonesParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesNeedingLocalZones, arg.ZoneType, arg.Column2, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var activity_id int64
+		if err := rows.Scan(&activity_id); err != nil {
+			return nil, err
+		}
+		items = append(items, activity_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivitiesNeedingQualityCheck = `-- name: ListActivitiesNeedingQualityCheck :many
+
+SELECT a.id, a.type, a.distance, a.moving_time, a.start_date, a.average_speed, a.max_speed, a.max_heartrate
+FROM activities a
+LEFT JOIN activity_quality_checks c ON c.activity_id = a.id
+LEFT JOIN activity_streams s ON s.activity_id = a.id
+WHERE a.id > ?
+  AND (c.checked_at IS NULL
+       OR c.checked_at < a.updated_at
+       OR c.checked_at < s.fetched_at)
+ORDER BY a.id
+LIMIT ?
+`
+
+type ListActivitiesNeedingQualityCheckParams struct {
+	ID    int64 `json:"id"`
+	Limit int64 `json:"limit"`
+}
+
+type ListActivitiesNeedingQualityCheckRow struct {
+	ID           int64           `json:"id"`
+	Type         sql.NullString  `json:"type"`
+	Distance     sql.NullFloat64 `json:"distance"`
+	MovingTime   sql.NullInt64   `json:"moving_time"`
+	StartDate    sql.NullTime    `json:"start_date"`
+	AverageSpeed sql.NullFloat64 `json:"average_speed"`
+	MaxSpeed     sql.NullFloat64 `json:"max_speed"`
+	MaxHeartrate sql.NullFloat64 `json:"max_heartrate"`
+}
+
+// ListActivitiesNeedingQualityCheck pages by ID through activities never
+// checked, or updated or given streams since they were
+func (q *Queries) ListActivitiesNeedingQualityCheck(ctx context.Context, arg ListActivitiesNeedingQualityCheckParams) ([]ListActivitiesNeedingQualityCheckRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesNeedingQualityCheck, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActivitiesNeedingQualityCheckRow{}
+	for rows.Next() {
+		var i ListActivitiesNeedingQualityCheckRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Distance,
+			&i.MovingTime,
+			&i.StartDate,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.MaxHeartrate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivitiesNeedingThresholdAnalysis = `-- name: ListActivitiesNeedingThresholdAnalysis :many
+SELECT a.id, a.type, a.start_date FROM activities a
+JOIN activity_streams s ON s.activity_id = a.id
+LEFT JOIN threshold_efforts te ON te.activity_id = a.id
+WHERE te.activity_id IS NULL
+  AND s.time_data IS NOT NULL
+ORDER BY a.start_date DESC
+LIMIT ?
+`
+
+type ListActivitiesNeedingThresholdAnalysisRow struct {
+	ID        int64          `json:"id"`
+	Type      sql.NullString `json:"type"`
+	StartDate sql.NullTime   `json:"start_date"`
+}
+
+func (q *Queries) ListActivitiesNeedingThresholdAnalysis(ctx context.Context, limit int64) ([]ListActivitiesNeedingThresholdAnalysisRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesNeedingThresholdAnalysis, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActivitiesNeedingThresholdAnalysisRow{}
+	for rows.Next() {
+		var i ListActivitiesNeedingThresholdAnalysisRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.StartDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivitiesStartedBetween = `-- name: ListActivitiesStartedBetween :many
+SELECT id, distance, moving_time, start_date FROM activities
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND id <> ?
+ORDER BY id
+`
+
+type ListActivitiesStartedBetweenParams struct {
+	Type        sql.NullString `json:"type"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+	ID          int64          `json:"id"`
+}
+
+type ListActivitiesStartedBetweenRow struct {
+	ID         int64           `json:"id"`
+	Distance   sql.NullFloat64 `json:"distance"`
+	MovingTime sql.NullInt64   `json:"moving_time"`
+	StartDate  sql.NullTime    `json:"start_date"`
+}
+
+func (q *Queries) ListActivitiesStartedBetween(ctx context.Context, arg ListActivitiesStartedBetweenParams) ([]ListActivitiesStartedBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesStartedBetween,
+		arg.Type,
+		arg.StartDate,
+		arg.StartDate_2,
+		arg.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActivitiesStartedBetweenRow{}
+	for rows.Next() {
+		var i ListActivitiesStartedBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Distance,
+			&i.MovingTime,
+			&i.StartDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivitiesWithoutRouteEffort = `-- name: ListActivitiesWithoutRouteEffort :many
+SELECT a.id, a.name, a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain, a.type, a.sport_type, a.start_date, a.start_date_local, a.timezone, a.average_speed, a.max_speed, a.average_cadence, a.average_heartrate, a.max_heartrate, a.calories, a.created_at, a.updated_at, a.summary_polyline, a.start_lat, a.start_lng, a.end_lat, a.end_lng, a.workout_type, a.workout_category, a.workout_confidence, a.workout_classified_at FROM activities a
+LEFT JOIN route_efforts re ON re.activity_id = a.id
+WHERE re.activity_id IS NULL
+  AND a.summary_polyline IS NOT NULL AND a.summary_polyline != ''
+ORDER BY a.start_date
+`
+
+func (q *Queries) ListActivitiesWithoutRouteEffort(ctx context.Context) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesWithoutRouteEffort)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivityFlags = `-- name: ListActivityFlags :many
+SELECT f.id, f.activity_id, f.code, f.severity, f.detail, f.related_activity_id, f.detected_at,
+       a.name, a.type, a.start_date, a.distance, a.average_speed
+FROM activity_flags f
+JOIN activities a ON a.id = f.activity_id
+WHERE (? IS NULL OR f.severity = ?)
+  AND (? IS NULL OR f.code = ?)
+  AND (? IS NULL OR a.type = ?)
+ORDER BY a.start_date DESC, f.id
+LIMIT ?
+`
+
+type ListActivityFlagsParams struct {
+	Column1  interface{}    `json:"column_1"`
+	Severity string         `json:"severity"`
+	Column3  interface{}    `json:"column_3"`
+	Code     string         `json:"code"`
+	Column5  interface{}    `json:"column_5"`
+	Type     sql.NullString `json:"type"`
+	Limit    int64          `json:"limit"`
+}
+
+type ListActivityFlagsRow struct {
+	ID                int64           `json:"id"`
+	ActivityID        int64           `json:"activity_id"`
+	Code              string          `json:"code"`
+	Severity          string          `json:"severity"`
+	Detail            string          `json:"detail"`
+	RelatedActivityID sql.NullInt64   `json:"related_activity_id"`
+	DetectedAt        sql.NullTime    `json:"detected_at"`
+	Name              string          `json:"name"`
+	Type              sql.NullString  `json:"type"`
+	StartDate         sql.NullTime    `json:"start_date"`
+	Distance          sql.NullFloat64 `json:"distance"`
+	AverageSpeed      sql.NullFloat64 `json:"average_speed"`
+}
+
+func (q *Queries) ListActivityFlags(ctx context.Context, arg ListActivityFlagsParams) ([]ListActivityFlagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActivityFlags,
+		arg.Column1,
+		arg.Severity,
+		arg.Column3,
+		arg.Code,
+		arg.Column5,
+		arg.Type,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActivityFlagsRow{}
+	for rows.Next() {
+		var i ListActivityFlagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActivityID,
+			&i.Code,
+			&i.Severity,
+			&i.Detail,
+			&i.RelatedActivityID,
+			&i.DetectedAt,
+			&i.Name,
+			&i.Type,
+			&i.StartDate,
+			&i.Distance,
+			&i.AverageSpeed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivityLaps = `-- name: ListActivityLaps :many
+SELECT activity_id, lap_index, name, distance, moving_time, elapsed_time, start_date, total_elevation_gain, average_speed, max_speed, average_heartrate, max_heartrate, average_cadence, average_watts FROM activity_laps WHERE activity_id = ? ORDER BY lap_index
+`
+
+func (q *Queries) ListActivityLaps(ctx context.Context, activityID int64) ([]ActivityLap, error) {
+	rows, err := q.db.QueryContext(ctx, listActivityLaps, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ActivityLap{}
+	for rows.Next() {
+		var i ActivityLap
+		if err := rows.Scan(
+			&i.ActivityID,
+			&i.LapIndex,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.StartDate,
+			&i.TotalElevationGain,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.AverageCadence,
+			&i.AverageWatts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClubActivityCounts = `-- name: ListClubActivityCounts :many
+SELECT
+    club_id,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(CASE WHEN backfill = 0 AND first_seen_at >= ? THEN 1 ELSE 0 END), 0) as recent_count
+FROM club_activities
+GROUP BY club_id
+`
+
+type ListClubActivityCountsRow struct {
+	ClubID        int64       `json:"club_id"`
+	ActivityCount int64       `json:"activity_count"`
+	RecentCount   interface{} `json:"recent_count"`
+}
+
+func (q *Queries) ListClubActivityCounts(ctx context.Context, firstSeenAt time.Time) ([]ListClubActivityCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listClubActivityCounts, firstSeenAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListClubActivityCountsRow{}
+	for rows.Next() {
+		var i ListClubActivityCountsRow
+		if err := rows.Scan(
+			&i.ClubID,
+			&i.ActivityCount,
+			&i.RecentCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClubs = `-- name: ListClubs :many
+SELECT id, name, sport_type, city, state, country, private, member_count, url, synced_at FROM clubs ORDER BY name
+`
+
+func (q *Queries) ListClubs(ctx context.Context) ([]Club, error) {
+	rows, err := q.db.QueryContext(ctx, listClubs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Club{}
+	for rows.Next() {
+		var i Club
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.SportType,
+			&i.City,
+			&i.State,
+			&i.Country,
+			&i.Private,
+			&i.MemberCount,
+			&i.Url,
+			&i.SyncedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLatlngStreams = `-- name: ListLatlngStreams :many
+
+SELECT s.activity_id, a.type, a.start_date, s.latlng_data
+FROM activity_streams s
+JOIN activities a ON a.id = s.activity_id
+WHERE s.latlng_data IS NOT NULL
+  AND s.activity_id > ?
+  AND (? IS NULL OR a.type = ?)
+  AND (? IS NULL OR a.start_date >= ?)
+  AND (? IS NULL OR a.start_date <= ?)
+ORDER BY s.activity_id
+LIMIT ?
+`
+
+type ListLatlngStreamsParams struct {
+	ActivityID  int64          `json:"activity_id"`
+	Column2     interface{}    `json:"column_2"`
+	Type        sql.NullString `json:"type"`
+	Column4     interface{}    `json:"column_4"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	Column6     interface{}    `json:"column_6"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+	Limit       int64          `json:"limit"`
+}
+
+type ListLatlngStreamsRow struct {
+	ActivityID int64          `json:"activity_id"`
+	Type       sql.NullString `json:"type"`
+	StartDate  sql.NullTime   `json:"start_date"`
+	LatlngData sql.NullString `json:"latlng_data"`
+}
+
+// Exploration queries (GPS coverage across all activities)
+// ListLatlngStreams pages through stored GPS streams by activity ID
+func (q *Queries) ListLatlngStreams(ctx context.Context, arg ListLatlngStreamsParams) ([]ListLatlngStreamsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLatlngStreams,
+		arg.ActivityID,
+		arg.Column2,
+		arg.Type,
+		arg.Column4,
+		arg.StartDate,
+		arg.Column6,
+		arg.StartDate_2,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLatlngStreamsRow{}
+	for rows.Next() {
+		var i ListLatlngStreamsRow
+		if err := rows.Scan(
+			&i.ActivityID,
+			&i.Type,
+			&i.StartDate,
+			&i.LatlngData,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPolylinesWithoutStreams = `-- name: ListPolylinesWithoutStreams :many
+SELECT a.id, a.type, a.start_date, a.summary_polyline
+FROM activities a
+LEFT JOIN activity_streams s ON s.activity_id = a.id
+WHERE s.latlng_data IS NULL
+  AND a.summary_polyline IS NOT NULL AND a.summary_polyline != ''
+  AND (? IS NULL OR a.type = ?)
+  AND (? IS NULL OR a.start_date >= ?)
+  AND (? IS NULL OR a.start_date <= ?)
+ORDER BY a.id
+`
+
+type ListPolylinesWithoutStreamsParams struct {
+	Column1     interface{}    `json:"column_1"`
+	Type        sql.NullString `json:"type"`
+	Column3     interface{}    `json:"column_3"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	Column5     interface{}    `json:"column_5"`
+	StartDate_2 sql.NullTime   `json:"start_date_2"`
+}
+
+type ListPolylinesWithoutStreamsRow struct {
+	ID              int64          `json:"id"`
+	Type            sql.NullString `json:"type"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	SummaryPolyline sql.NullString `json:"summary_polyline"`
+}
+
+func (q *Queries) ListPolylinesWithoutStreams(ctx context.Context, arg ListPolylinesWithoutStreamsParams) ([]ListPolylinesWithoutStreamsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPolylinesWithoutStreams,
+		arg.Column1,
+		arg.Type,
+		arg.Column3,
+		arg.StartDate,
+		arg.Column5,
+		arg.StartDate_2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPolylinesWithoutStreamsRow{}
+	for rows.Next() {
+		var i ListPolylinesWithoutStreamsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.StartDate,
+			&i.SummaryPolyline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRaces = `-- name: ListRaces :many
+SELECT id, name, race_date, distance, activity_type, priority, goal_time, notes, created_at FROM races ORDER BY race_date, id
+`
+
+func (q *Queries) ListRaces(ctx context.Context) ([]Race, error) {
+	rows, err := q.db.QueryContext(ctx, listRaces)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Race{}
+	for rows.Next() {
+		var i Race
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.RaceDate,
+			&i.Distance,
+			&i.ActivityType,
+			&i.Priority,
+			&i.GoalTime,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecurringRoutes = `-- name: ListRecurringRoutes :many
+SELECT r.id, r.name, r.activity_type, r.distance, COUNT(re.activity_id) AS effort_count
+FROM routes_detected r
+JOIN route_efforts re ON re.route_id = r.id
+WHERE (? IS NULL OR r.activity_type = ?)
+GROUP BY r.id
+HAVING COUNT(re.activity_id) >= 2
+ORDER BY effort_count DESC, r.id
+LIMIT ?
+`
+
+type ListRecurringRoutesParams struct {
+	Column1      interface{}    `json:"column_1"`
+	ActivityType sql.NullString `json:"activity_type"`
+	Limit        int64          `json:"limit"`
+}
+
+type ListRecurringRoutesRow struct {
+	ID           int64          `json:"id"`
+	Name         string         `json:"name"`
+	ActivityType sql.NullString `json:"activity_type"`
+	Distance     float64        `json:"distance"`
+	EffortCount  int64          `json:"effort_count"`
+}
+
+func (q *Queries) ListRecurringRoutes(ctx context.Context, arg ListRecurringRoutesParams) ([]ListRecurringRoutesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecurringRoutes, arg.Column1, arg.ActivityType, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecurringRoutesRow{}
+	for rows.Next() {
+		var i ListRecurringRoutesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ActivityType,
+			&i.Distance,
+			&i.EffortCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoutesDetected = `-- name: ListRoutesDetected :many
+SELECT id, name, activity_type, representative_activity_id, polyline, distance, start_lat, start_lng, end_lat, end_lng, created_at FROM routes_detected ORDER BY id
+`
+
+func (q *Queries) ListRoutesDetected(ctx context.Context) ([]RoutesDetected, error) {
+	rows, err := q.db.QueryContext(ctx, listRoutesDetected)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RoutesDetected{}
+	for rows.Next() {
+		var i RoutesDetected
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ActivityType,
+			&i.RepresentativeActivityID,
+			&i.Polyline,
+			&i.Distance,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSavedRoutes = `-- name: ListSavedRoutes :many
+SELECT id, name, description, type, sub_type, distance, elevation_gain,
+    estimated_moving_time, polyline, private, starred, created_at, updated_at,
+    gpx IS NOT NULL as has_gpx
+FROM saved_routes
+ORDER BY name
+`
+
+type ListSavedRoutesRow struct {
+	ID                  int64          `json:"id"`
+	Name                string         `json:"name"`
+	Description         sql.NullString `json:"description"`
+	Type                sql.NullString `json:"type"`
+	SubType             sql.NullString `json:"sub_type"`
+	Distance            float64        `json:"distance"`
+	ElevationGain       float64        `json:"elevation_gain"`
+	EstimatedMovingTime int64          `json:"estimated_moving_time"`
+	Polyline            sql.NullString `json:"polyline"`
+	Private             int64          `json:"private"`
+	Starred             int64          `json:"starred"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	HasGpx              interface{}    `json:"has_gpx"`
+}
+
+func (q *Queries) ListSavedRoutes(ctx context.Context) ([]ListSavedRoutesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSavedRoutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSavedRoutesRow{}
+	for rows.Next() {
+		var i ListSavedRoutesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.SubType,
+			&i.Distance,
+			&i.ElevationGain,
+			&i.EstimatedMovingTime,
+			&i.Polyline,
+			&i.Private,
+			&i.Starred,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HasGpx,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSavedRoutesNeedingGPX = `-- name: ListSavedRoutesNeedingGPX :many
+SELECT id, updated_at FROM saved_routes
+WHERE gpx IS NULL OR gpx_updated_at IS NULL OR gpx_updated_at != updated_at
+ORDER BY id
+`
+
+type ListSavedRoutesNeedingGPXRow struct {
+	ID        int64        `json:"id"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+func (q *Queries) ListSavedRoutesNeedingGPX(ctx context.Context) ([]ListSavedRoutesNeedingGPXRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSavedRoutesNeedingGPX)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSavedRoutesNeedingGPXRow{}
+	for rows.Next() {
+		var i ListSavedRoutesNeedingGPXRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStarredSegments = `-- name: ListStarredSegments :many
+SELECT id, name, activity_type, distance, average_grade, maximum_grade, elevation_high, elevation_low, climb_category, city, state, country, private, start_lat, start_lng, end_lat, end_lng, pr_elapsed_time, pr_date, pr_activity_id, effort_count, synced_at FROM starred_segments ORDER BY name
+`
+
+func (q *Queries) ListStarredSegments(ctx context.Context) ([]StarredSegment, error) {
+	rows, err := q.db.QueryContext(ctx, listStarredSegments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StarredSegment{}
+	for rows.Next() {
+		var i StarredSegment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ActivityType,
+			&i.Distance,
+			&i.AverageGrade,
+			&i.MaximumGrade,
+			&i.ElevationHigh,
+			&i.ElevationLow,
+			&i.ClimbCategory,
+			&i.City,
+			&i.State,
+			&i.Country,
+			&i.Private,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.PrElapsedTime,
+			&i.PrDate,
+			&i.PrActivityID,
+			&i.EffortCount,
+			&i.SyncedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncFailures = `-- name: ListSyncFailures :many
+SELECT activity_id, activity_name, start_date, error, attempts, first_failed_at, last_failed_at FROM sync_failures ORDER BY last_failed_at DESC
+`
+
+func (q *Queries) ListSyncFailures(ctx context.Context) ([]SyncFailure, error) {
+	rows, err := q.db.QueryContext(ctx, listSyncFailures)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SyncFailure{}
+	for rows.Next() {
+		var i SyncFailure
+		if err := rows.Scan(
+			&i.ActivityID,
+			&i.ActivityName,
+			&i.StartDate,
+			&i.Error,
+			&i.Attempts,
+			&i.FirstFailedAt,
+			&i.LastFailedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncIntegrity = `-- name: ListSyncIntegrity :many
+SELECT sport, period, window_start, strava_count, local_count, strava_distance, local_distance, strava_moving_time, local_moving_time, repaired_missing, checked_at FROM sync_integrity ORDER BY sport, period
 `
 
-type GetTrainingSummaryInRangeParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
+func (q *Queries) ListSyncIntegrity(ctx context.Context) ([]SyncIntegrity, error) {
+	rows, err := q.db.QueryContext(ctx, listSyncIntegrity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SyncIntegrity{}
+	for rows.Next() {
+		var i SyncIntegrity
+		if err := rows.Scan(
+			&i.Sport,
+			&i.Period,
+			&i.WindowStart,
+			&i.StravaCount,
+			&i.LocalCount,
+			&i.StravaDistance,
+			&i.LocalDistance,
+			&i.StravaMovingTime,
+			&i.LocalMovingTime,
+			&i.RepairedMissing,
+			&i.CheckedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-type GetTrainingSummaryInRangeRow struct {
-	ActivityCount   int64       `json:"activity_count"`
-	TotalDistance   interface{} `json:"total_distance"`
-	TotalMovingTime interface{} `json:"total_moving_time"`
-	AvgSpeed        interface{} `json:"avg_speed"`
-	AvgHeartrate    interface{} `json:"avg_heartrate"`
-	TotalCalories   interface{} `json:"total_calories"`
-	TotalElevation  interface{} `json:"total_elevation"`
-}
+const listSyncRepairs = `-- name: ListSyncRepairs :many
+SELECT id, reason, window_start, window_end, fetched, added, failed, error, repaired_at FROM sync_repairs ORDER BY repaired_at DESC, id DESC LIMIT ?
+`
 
-func (q *Queries) GetTrainingSummaryInRange(ctx context.Context, arg GetTrainingSummaryInRangeParams) (GetTrainingSummaryInRangeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTrainingSummaryInRange, arg.StartDate, arg.StartDate_2)
-	var i GetTrainingSummaryInRangeRow
-	err := row.Scan(
-		&i.ActivityCount,
-		&i.TotalDistance,
-		&i.TotalMovingTime,
-		&i.AvgSpeed,
-		&i.AvgHeartrate,
-		&i.TotalCalories,
-		&i.TotalElevation,
-	)
-	return i, err
+func (q *Queries) ListSyncRepairs(ctx context.Context, limit int64) ([]SyncRepair, error) {
+	rows, err := q.db.QueryContext(ctx, listSyncRepairs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SyncRepair{}
+	for rows.Next() {
+		var i SyncRepair
+		if err := rows.Scan(
+			&i.ID,
+			&i.Reason,
+			&i.WindowStart,
+			&i.WindowEnd,
+			&i.Fetched,
+			&i.Added,
+			&i.Failed,
+			&i.Error,
+			&i.RepairedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getWeeklyVolume = `-- name: GetWeeklyVolume :many
-
-SELECT
-    strftime('%Y-W%W', start_date) as week,
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_duration,
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
-FROM activities
-WHERE start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-W%W', start_date)
-ORDER BY week DESC
+const listThresholdEfforts = `-- name: ListThresholdEfforts :many
+SELECT activity_id, sport, start_date, lthr, ftp, threshold_speed, analyzed_at FROM threshold_efforts
+WHERE sport = ? AND start_date IS NOT NULL
+ORDER BY start_date, activity_id
 `
 
-type GetWeeklyVolumeParams struct {
-	StartDate   sql.NullTime `json:"start_date"`
-	StartDate_2 sql.NullTime `json:"start_date_2"`
+func (q *Queries) ListThresholdEfforts(ctx context.Context, sport string) ([]ThresholdEffort, error) {
+	rows, err := q.db.QueryContext(ctx, listThresholdEfforts, sport)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ThresholdEffort{}
+	for rows.Next() {
+		var i ThresholdEffort
+		if err := rows.Scan(
+			&i.ActivityID,
+			&i.Sport,
+			&i.StartDate,
+			&i.Lthr,
+			&i.Ftp,
+			&i.ThresholdSpeed,
+			&i.AnalyzedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-type GetWeeklyVolumeRow struct {
-	Week           interface{} `json:"week"`
-	ActivityCount  int64       `json:"activity_count"`
-	TotalDistance  interface{} `json:"total_distance"`
-	TotalDuration  interface{} `json:"total_duration"`
-	TotalCalories  interface{} `json:"total_calories"`
-	TotalElevation interface{} `json:"total_elevation"`
-}
+const listThresholdHistory = `-- name: ListThresholdHistory :many
+SELECT id, metric, sport, value, previous_value, activity_id, detected_on, created_at FROM threshold_history ORDER BY detected_on, id
+`
 
-// Weekly volume queries for training load analysis
-func (q *Queries) GetWeeklyVolume(ctx context.Context, arg GetWeeklyVolumeParams) ([]GetWeeklyVolumeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWeeklyVolume, arg.StartDate, arg.StartDate_2)
+func (q *Queries) ListThresholdHistory(ctx context.Context) ([]ThresholdHistory, error) {
+	rows, err := q.db.QueryContext(ctx, listThresholdHistory)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetWeeklyVolumeRow{}
+	items := []ThresholdHistory{}
 	for rows.Next() {
-		var i GetWeeklyVolumeRow
+		var i ThresholdHistory
 		if err := rows.Scan(
-			&i.Week,
-			&i.ActivityCount,
-			&i.TotalDistance,
-			&i.TotalDuration,
-			&i.TotalCalories,
-			&i.TotalElevation,
+			&i.ID,
+			&i.Metric,
+			&i.Sport,
+			&i.Value,
+			&i.PreviousValue,
+			&i.ActivityID,
+			&i.DetectedOn,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -2893,51 +6751,71 @@ func (q *Queries) GetWeeklyVolume(ctx context.Context, arg GetWeeklyVolumeParams
 	return items, nil
 }
 
-const getWeeklyVolumeByType = `-- name: GetWeeklyVolumeByType :many
-SELECT
-    strftime('%Y-W%W', start_date) as week,
-    COUNT(*) as activity_count,
-    COALESCE(SUM(distance), 0) as total_distance,
-    COALESCE(SUM(moving_time), 0) as total_duration,
-    COALESCE(SUM(calories), 0) as total_calories,
-    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
-FROM activities
-WHERE type = ? AND start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-W%W', start_date)
-ORDER BY week DESC
+const listUpcomingRaces = `-- name: ListUpcomingRaces :many
+SELECT id, name, race_date, distance, activity_type, priority, goal_time, notes, created_at FROM races
+WHERE race_date >= ?
+ORDER BY race_date, priority, id
 `
 
-type GetWeeklyVolumeByTypeParams struct {
-	Type        sql.NullString `json:"type"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
+func (q *Queries) ListUpcomingRaces(ctx context.Context, raceDate time.Time) ([]Race, error) {
+	rows, err := q.db.QueryContext(ctx, listUpcomingRaces, raceDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Race{}
+	for rows.Next() {
+		var i Race
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.RaceDate,
+			&i.Distance,
+			&i.ActivityType,
+			&i.Priority,
+			&i.GoalTime,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-type GetWeeklyVolumeByTypeRow struct {
-	Week           interface{} `json:"week"`
-	ActivityCount  int64       `json:"activity_count"`
-	TotalDistance  interface{} `json:"total_distance"`
-	TotalDuration  interface{} `json:"total_duration"`
-	TotalCalories  interface{} `json:"total_calories"`
-	TotalElevation interface{} `json:"total_elevation"`
+const listWorkoutNorms = `-- name: ListWorkoutNorms :many
+SELECT type, moving_time, average_speed, max_heartrate FROM activities
+WHERE type IS NOT NULL AND moving_time > 0
+`
+
+type ListWorkoutNormsRow struct {
+	Type         sql.NullString  `json:"type"`
+	MovingTime   sql.NullInt64   `json:"moving_time"`
+	AverageSpeed sql.NullFloat64 `json:"average_speed"`
+	MaxHeartrate sql.NullFloat64 `json:"max_heartrate"`
 }
 
-func (q *Queries) GetWeeklyVolumeByType(ctx context.Context, arg GetWeeklyVolumeByTypeParams) ([]GetWeeklyVolumeByTypeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWeeklyVolumeByType, arg.Type, arg.StartDate, arg.StartDate_2)
+func (q *Queries) ListWorkoutNorms(ctx context.Context) ([]ListWorkoutNormsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkoutNorms)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetWeeklyVolumeByTypeRow{}
+	items := []ListWorkoutNormsRow{}
 	for rows.Next() {
-		var i GetWeeklyVolumeByTypeRow
+		var i ListWorkoutNormsRow
 		if err := rows.Scan(
-			&i.Week,
-			&i.ActivityCount,
-			&i.TotalDistance,
-			&i.TotalDuration,
-			&i.TotalCalories,
-			&i.TotalElevation,
+			&i.Type,
+			&i.MovingTime,
+			&i.AverageSpeed,
+			&i.MaxHeartrate,
 		); err != nil {
 			return nil, err
 		}
@@ -2952,26 +6830,24 @@ func (q *Queries) GetWeeklyVolumeByType(ctx context.Context, arg GetWeeklyVolume
 	return items, nil
 }
 
-const getZoneBuckets = `-- name: GetZoneBuckets :many
-SELECT id, activity_zone_id, zone_number, min_value, max_value, time_seconds FROM zone_buckets WHERE activity_zone_id = ? ORDER BY zone_number
+const listZoneSettings = `-- name: ListZoneSettings :many
+SELECT zone_type, boundaries, source, updated_at FROM zone_settings ORDER BY zone_type
 `
 
-func (q *Queries) GetZoneBuckets(ctx context.Context, activityZoneID int64) ([]ZoneBucket, error) {
-	rows, err := q.db.QueryContext(ctx, getZoneBuckets, activityZoneID)
+func (q *Queries) ListZoneSettings(ctx context.Context) ([]ZoneSetting, error) {
+	rows, err := q.db.QueryContext(ctx, listZoneSettings)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ZoneBucket{}
+	items := []ZoneSetting{}
 	for rows.Next() {
-		var i ZoneBucket
+		var i ZoneSetting
 		if err := rows.Scan(
-			&i.ID,
-			&i.ActivityZoneID,
-			&i.ZoneNumber,
-			&i.MinValue,
-			&i.MaxValue,
-			&i.TimeSeconds,
+			&i.ZoneType,
+			&i.Boundaries,
+			&i.Source,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -2983,7 +6859,135 @@ func (q *Queries) GetZoneBuckets(ctx context.Context, activityZoneID int64) ([]Z
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return items, nil
+	return items, nil
+}
+
+const markActivityQualityChecked = `-- name: MarkActivityQualityChecked :exec
+INSERT INTO activity_quality_checks (activity_id, checked_at)
+VALUES (?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET checked_at = CURRENT_TIMESTAMP
+`
+
+func (q *Queries) MarkActivityQualityChecked(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, markActivityQualityChecked, activityID)
+	return err
+}
+
+const markSocialListsSynced = `-- name: MarkSocialListsSynced :exec
+UPDATE activity_social
+SET lists_kudos_count = ?, lists_comment_count = ?, lists_synced_at = CURRENT_TIMESTAMP
+WHERE activity_id = ?
+`
+
+type MarkSocialListsSyncedParams struct {
+	ListsKudosCount   sql.NullInt64 `json:"lists_kudos_count"`
+	ListsCommentCount sql.NullInt64 `json:"lists_comment_count"`
+	ActivityID        int64         `json:"activity_id"`
+}
+
+func (q *Queries) MarkSocialListsSynced(ctx context.Context, arg MarkSocialListsSyncedParams) error {
+	_, err := q.db.ExecContext(ctx, markSocialListsSynced, arg.ListsKudosCount, arg.ListsCommentCount, arg.ActivityID)
+	return err
+}
+
+const recordSyncFailure = `-- name: RecordSyncFailure :exec
+INSERT INTO sync_failures (
+    activity_id, activity_name, start_date, error, first_failed_at, last_failed_at
+) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    activity_name = excluded.activity_name,
+    start_date = excluded.start_date,
+    error = excluded.error,
+    attempts = sync_failures.attempts + 1,
+    last_failed_at = excluded.last_failed_at
+`
+
+type RecordSyncFailureParams struct {
+	ActivityID    int64          `json:"activity_id"`
+	ActivityName  sql.NullString `json:"activity_name"`
+	StartDate     sql.NullTime   `json:"start_date"`
+	Error         string         `json:"error"`
+	FirstFailedAt time.Time      `json:"first_failed_at"`
+	LastFailedAt  time.Time      `json:"last_failed_at"`
+}
+
+func (q *Queries) RecordSyncFailure(ctx context.Context, arg RecordSyncFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordSyncFailure,
+		arg.ActivityID,
+		arg.ActivityName,
+		arg.StartDate,
+		arg.Error,
+		arg.FirstFailedAt,
+		arg.LastFailedAt,
+	)
+	return err
+}
+
+const releaseSyncJob = `-- name: ReleaseSyncJob :exec
+UPDATE sync_jobs SET status = 'pending', run_after = ?, updated_at = ? WHERE id = ?
+`
+
+type ReleaseSyncJobParams struct {
+	RunAfter  time.Time `json:"run_after"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64     `json:"id"`
+}
+
+func (q *Queries) ReleaseSyncJob(ctx context.Context, arg ReleaseSyncJobParams) error {
+	_, err := q.db.ExecContext(ctx, releaseSyncJob, arg.RunAfter, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const resetActivityCorrections = `-- name: ResetActivityCorrections :exec
+UPDATE activities SET
+    name = CASE WHEN o.name IS NOT NULL THEN o.original_name ELSE activities.name END,
+    type = CASE WHEN o.type IS NOT NULL THEN o.original_type ELSE activities.type END,
+    sport_type = CASE WHEN o.type IS NOT NULL THEN o.original_sport_type ELSE activities.sport_type END,
+    distance = CASE WHEN o.distance IS NOT NULL THEN o.original_distance ELSE activities.distance END,
+    average_speed = CASE WHEN o.distance IS NOT NULL THEN o.original_average_speed ELSE activities.average_speed END,
+    updated_at = CURRENT_TIMESTAMP
+FROM activity_overrides o
+WHERE o.activity_id = activities.id AND activities.id = ?
+`
+
+func (q *Queries) ResetActivityCorrections(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, resetActivityCorrections, id)
+	return err
+}
+
+const resetRunningSyncJobs = `-- name: ResetRunningSyncJobs :execrows
+UPDATE sync_jobs SET status = 'pending', updated_at = ? WHERE status = 'running'
+`
+
+func (q *Queries) ResetRunningSyncJobs(ctx context.Context, updatedAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resetRunningSyncJobs, updatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const retrySyncJob = `-- name: RetrySyncJob :exec
+UPDATE sync_jobs
+SET status = 'pending', attempts = attempts + 1, last_error = ?, run_after = ?, updated_at = ?
+WHERE id = ?
+`
+
+type RetrySyncJobParams struct {
+	LastError sql.NullString `json:"last_error"`
+	RunAfter  time.Time      `json:"run_after"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	ID        int64          `json:"id"`
+}
+
+func (q *Queries) RetrySyncJob(ctx context.Context, arg RetrySyncJobParams) error {
+	_, err := q.db.ExecContext(ctx, retrySyncJob,
+		arg.LastError,
+		arg.RunAfter,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
 }
 
 const saveAuthConfig = `-- name: SaveAuthConfig :exec
@@ -3021,22 +7025,25 @@ func (q *Queries) SaveAuthConfig(ctx context.Context, arg SaveAuthConfigParams)
 
 const searchActivities = `-- name: SearchActivities :many
 
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
 ORDER BY start_date DESC
 LIMIT ?
 `
 
 type SearchActivitiesParams struct {
-	Column1     interface{}    `json:"column_1"`
-	Type        sql.NullString `json:"type"`
-	Column3     interface{}    `json:"column_3"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	Column5     interface{}    `json:"column_5"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
-	Limit       int64          `json:"limit"`
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+	Limit           int64          `json:"limit"`
 }
 
 // Flexible activity search with sorting
@@ -3048,6 +7055,8 @@ func (q *Queries) SearchActivities(ctx context.Context, arg SearchActivitiesPara
 		arg.StartDate,
 		arg.Column5,
 		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
 		arg.Limit,
 	)
 	if err != nil {
@@ -3077,6 +7086,15 @@ func (q *Queries) SearchActivities(ctx context.Context, arg SearchActivitiesPara
 			&i.Calories,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3092,23 +7110,26 @@ func (q *Queries) SearchActivities(ctx context.Context, arg SearchActivitiesPara
 }
 
 const searchActivitiesByDistance = `-- name: SearchActivitiesByDistance :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND distance IS NOT NULL
 ORDER BY distance DESC
 LIMIT ?
 `
 
 type SearchActivitiesByDistanceParams struct {
-	Column1     interface{}    `json:"column_1"`
-	Type        sql.NullString `json:"type"`
-	Column3     interface{}    `json:"column_3"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	Column5     interface{}    `json:"column_5"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
-	Limit       int64          `json:"limit"`
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+	Limit           int64          `json:"limit"`
 }
 
 func (q *Queries) SearchActivitiesByDistance(ctx context.Context, arg SearchActivitiesByDistanceParams) ([]Activity, error) {
@@ -3119,6 +7140,8 @@ func (q *Queries) SearchActivitiesByDistance(ctx context.Context, arg SearchActi
 		arg.StartDate,
 		arg.Column5,
 		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
 		arg.Limit,
 	)
 	if err != nil {
@@ -3148,6 +7171,15 @@ func (q *Queries) SearchActivitiesByDistance(ctx context.Context, arg SearchActi
 			&i.Calories,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3163,23 +7195,26 @@ func (q *Queries) SearchActivitiesByDistance(ctx context.Context, arg SearchActi
 }
 
 const searchActivitiesByDuration = `-- name: SearchActivitiesByDuration :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND moving_time IS NOT NULL
 ORDER BY moving_time DESC
 LIMIT ?
 `
 
 type SearchActivitiesByDurationParams struct {
-	Column1     interface{}    `json:"column_1"`
-	Type        sql.NullString `json:"type"`
-	Column3     interface{}    `json:"column_3"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	Column5     interface{}    `json:"column_5"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
-	Limit       int64          `json:"limit"`
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+	Limit           int64          `json:"limit"`
 }
 
 func (q *Queries) SearchActivitiesByDuration(ctx context.Context, arg SearchActivitiesByDurationParams) ([]Activity, error) {
@@ -3190,6 +7225,8 @@ func (q *Queries) SearchActivitiesByDuration(ctx context.Context, arg SearchActi
 		arg.StartDate,
 		arg.Column5,
 		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
 		arg.Limit,
 	)
 	if err != nil {
@@ -3219,6 +7256,15 @@ func (q *Queries) SearchActivitiesByDuration(ctx context.Context, arg SearchActi
 			&i.Calories,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3234,23 +7280,26 @@ func (q *Queries) SearchActivitiesByDuration(ctx context.Context, arg SearchActi
 }
 
 const searchActivitiesByElevation = `-- name: SearchActivitiesByElevation :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND total_elevation_gain IS NOT NULL
 ORDER BY total_elevation_gain DESC
 LIMIT ?
 `
 
 type SearchActivitiesByElevationParams struct {
-	Column1     interface{}    `json:"column_1"`
-	Type        sql.NullString `json:"type"`
-	Column3     interface{}    `json:"column_3"`
-	StartDate   sql.NullTime   `json:"start_date"`
-	Column5     interface{}    `json:"column_5"`
-	StartDate_2 sql.NullTime   `json:"start_date_2"`
-	Limit       int64          `json:"limit"`
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+	Limit           int64          `json:"limit"`
 }
 
 func (q *Queries) SearchActivitiesByElevation(ctx context.Context, arg SearchActivitiesByElevationParams) ([]Activity, error) {
@@ -3261,6 +7310,8 @@ func (q *Queries) SearchActivitiesByElevation(ctx context.Context, arg SearchAct
 		arg.StartDate,
 		arg.Column5,
 		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
 		arg.Limit,
 	)
 	if err != nil {
@@ -3290,6 +7341,15 @@ func (q *Queries) SearchActivitiesByElevation(ctx context.Context, arg SearchAct
 			&i.Calories,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3305,16 +7365,102 @@ func (q *Queries) SearchActivitiesByElevation(ctx context.Context, arg SearchAct
 }
 
 const searchActivitiesBySpeed = `-- name: SearchActivitiesBySpeed :many
-SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at FROM activities
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND average_speed IS NOT NULL
 ORDER BY average_speed DESC
 LIMIT ?
 `
 
 type SearchActivitiesBySpeedParams struct {
+	Column1         interface{}    `json:"column_1"`
+	Type            sql.NullString `json:"type"`
+	Column3         interface{}    `json:"column_3"`
+	StartDate       sql.NullTime   `json:"start_date"`
+	Column5         interface{}    `json:"column_5"`
+	StartDate_2     sql.NullTime   `json:"start_date_2"`
+	Column7         interface{}    `json:"column_7"`
+	WorkoutCategory sql.NullString `json:"workout_category"`
+	Limit           int64          `json:"limit"`
+}
+
+func (q *Queries) SearchActivitiesBySpeed(ctx context.Context, arg SearchActivitiesBySpeedParams) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, searchActivitiesBySpeed,
+		arg.Column1,
+		arg.Type,
+		arg.Column3,
+		arg.StartDate,
+		arg.Column5,
+		arg.StartDate_2,
+		arg.Column7,
+		arg.WorkoutCategory,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.Type,
+			&i.SportType,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageCadence,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.Calories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchActivitiesWithRoute = `-- name: SearchActivitiesWithRoute :many
+
+SELECT id, name, distance, moving_time, elapsed_time, total_elevation_gain, type, sport_type, start_date, start_date_local, timezone, average_speed, max_speed, average_cadence, average_heartrate, max_heartrate, calories, created_at, updated_at, summary_polyline, start_lat, start_lng, end_lat, end_lng, workout_type, workout_category, workout_confidence, workout_classified_at FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND summary_polyline IS NOT NULL AND summary_polyline != ''
+ORDER BY start_date DESC
+LIMIT ?
+`
+
+type SearchActivitiesWithRouteParams struct {
 	Column1     interface{}    `json:"column_1"`
 	Type        sql.NullString `json:"type"`
 	Column3     interface{}    `json:"column_3"`
@@ -3324,8 +7470,9 @@ type SearchActivitiesBySpeedParams struct {
 	Limit       int64          `json:"limit"`
 }
 
-func (q *Queries) SearchActivitiesBySpeed(ctx context.Context, arg SearchActivitiesBySpeedParams) ([]Activity, error) {
-	rows, err := q.db.QueryContext(ctx, searchActivitiesBySpeed,
+// Route queries (activities with a stored summary polyline)
+func (q *Queries) SearchActivitiesWithRoute(ctx context.Context, arg SearchActivitiesWithRouteParams) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, searchActivitiesWithRoute,
 		arg.Column1,
 		arg.Type,
 		arg.Column3,
@@ -3361,6 +7508,15 @@ func (q *Queries) SearchActivitiesBySpeed(ctx context.Context, arg SearchActivit
 			&i.Calories,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SummaryPolyline,
+			&i.StartLat,
+			&i.StartLng,
+			&i.EndLat,
+			&i.EndLng,
+			&i.WorkoutType,
+			&i.WorkoutCategory,
+			&i.WorkoutConfidence,
+			&i.WorkoutClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3375,6 +7531,95 @@ func (q *Queries) SearchActivitiesBySpeed(ctx context.Context, arg SearchActivit
 	return items, nil
 }
 
+const setActivityCounted = `-- name: SetActivityCounted :exec
+INSERT INTO activity_overrides (activity_id, counted, reason)
+VALUES (?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    counted = excluded.counted,
+    reason = excluded.reason,
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type SetActivityCountedParams struct {
+	ActivityID int64          `json:"activity_id"`
+	Counted    sql.NullInt64  `json:"counted"`
+	Reason     sql.NullString `json:"reason"`
+}
+
+func (q *Queries) SetActivityCounted(ctx context.Context, arg SetActivityCountedParams) error {
+	_, err := q.db.ExecContext(ctx, setActivityCounted, arg.ActivityID, arg.Counted, arg.Reason)
+	return err
+}
+
+const setSavedRouteGPX = `-- name: SetSavedRouteGPX :exec
+UPDATE saved_routes SET gpx = ?, gpx_updated_at = ? WHERE id = ?
+`
+
+type SetSavedRouteGPXParams struct {
+	Gpx          sql.NullString `json:"gpx"`
+	GpxUpdatedAt sql.NullTime   `json:"gpx_updated_at"`
+	ID           int64          `json:"id"`
+}
+
+func (q *Queries) SetSavedRouteGPX(ctx context.Context, arg SetSavedRouteGPXParams) error {
+	_, err := q.db.ExecContext(ctx, setSavedRouteGPX, arg.Gpx, arg.GpxUpdatedAt, arg.ID)
+	return err
+}
+
+const setSyncIntegrityRepaired = `-- name: SetSyncIntegrityRepaired :exec
+UPDATE sync_integrity SET repaired_missing = ? WHERE sport = ? AND period = ?
+`
+
+type SetSyncIntegrityRepairedParams struct {
+	RepairedMissing sql.NullInt64 `json:"repaired_missing"`
+	Sport           string        `json:"sport"`
+	Period          string        `json:"period"`
+}
+
+func (q *Queries) SetSyncIntegrityRepaired(ctx context.Context, arg SetSyncIntegrityRepairedParams) error {
+	_, err := q.db.ExecContext(ctx, setSyncIntegrityRepaired, arg.RepairedMissing, arg.Sport, arg.Period)
+	return err
+}
+
+const setSyncRepairError = `-- name: SetSyncRepairError :exec
+UPDATE sync_repairs SET error = ?
+WHERE id = (
+    SELECT id FROM sync_repairs
+    WHERE window_start IS ? AND window_end = ?
+    ORDER BY id DESC
+    LIMIT 1
+)
+`
+
+type SetSyncRepairErrorParams struct {
+	Error       sql.NullString `json:"error"`
+	WindowStart sql.NullTime   `json:"window_start"`
+	WindowEnd   sql.NullTime   `json:"window_end"`
+}
+
+func (q *Queries) SetSyncRepairError(ctx context.Context, arg SetSyncRepairErrorParams) error {
+	_, err := q.db.ExecContext(ctx, setSyncRepairError, arg.Error, arg.WindowStart, arg.WindowEnd)
+	return err
+}
+
+const setSyncState = `-- name: SetSyncState :exec
+INSERT INTO sync_state (key, value, updated_at) VALUES (?, ?, ?)
+ON CONFLICT(key) DO UPDATE SET
+    value = excluded.value,
+    updated_at = excluded.updated_at
+`
+
+type SetSyncStateParams struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) SetSyncState(ctx context.Context, arg SetSyncStateParams) error {
+	_, err := q.db.ExecContext(ctx, setSyncState, arg.Key, arg.Value, arg.UpdatedAt)
+	return err
+}
+
 const updateTokens = `-- name: UpdateTokens :exec
 UPDATE auth_config SET
     access_token = ?,
@@ -3394,3 +7639,461 @@ func (q *Queries) UpdateTokens(ctx context.Context, arg UpdateTokensParams) erro
 	_, err := q.db.ExecContext(ctx, updateTokens, arg.AccessToken, arg.RefreshToken, arg.ExpiresAt)
 	return err
 }
+
+const updateWorkoutClassification = `-- name: UpdateWorkoutClassification :exec
+UPDATE activities
+SET workout_category = ?, workout_confidence = ?, workout_classified_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateWorkoutClassificationParams struct {
+	WorkoutCategory   sql.NullString  `json:"workout_category"`
+	WorkoutConfidence sql.NullFloat64 `json:"workout_confidence"`
+	ID                int64           `json:"id"`
+}
+
+func (q *Queries) UpdateWorkoutClassification(ctx context.Context, arg UpdateWorkoutClassificationParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkoutClassification, arg.WorkoutCategory, arg.WorkoutConfidence, arg.ID)
+	return err
+}
+
+const upsertActivityCorrection = `-- name: UpsertActivityCorrection :exec
+INSERT INTO activity_overrides (
+    activity_id, name, type, distance,
+    original_name, original_type, original_sport_type, original_distance, original_average_speed
+)
+SELECT id, ?, ?, ?, name, type, sport_type, distance, average_speed
+FROM activities WHERE id = ?
+ON CONFLICT(activity_id) DO UPDATE SET
+    original_name = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                         THEN excluded.original_name ELSE activity_overrides.original_name END,
+    original_type = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                         THEN excluded.original_type ELSE activity_overrides.original_type END,
+    original_sport_type = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                               THEN excluded.original_sport_type ELSE activity_overrides.original_sport_type END,
+    original_distance = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                             THEN excluded.original_distance ELSE activity_overrides.original_distance END,
+    original_average_speed = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                                  THEN excluded.original_average_speed ELSE activity_overrides.original_average_speed END,
+    name = COALESCE(excluded.name, activity_overrides.name),
+    type = COALESCE(excluded.type, activity_overrides.type),
+    distance = COALESCE(excluded.distance, activity_overrides.distance),
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertActivityCorrectionParams struct {
+	Name     sql.NullString  `json:"name"`
+	Type     sql.NullString  `json:"type"`
+	Distance sql.NullFloat64 `json:"distance"`
+	ID       int64           `json:"id"`
+}
+
+func (q *Queries) UpsertActivityCorrection(ctx context.Context, arg UpsertActivityCorrectionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertActivityCorrection,
+		arg.Name,
+		arg.Type,
+		arg.Distance,
+		arg.ID,
+	)
+	return err
+}
+
+const upsertActivitySocialCounts = `-- name: UpsertActivitySocialCounts :exec
+INSERT INTO activity_social (activity_id, kudos_count, comment_count, athlete_count)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    kudos_count = excluded.kudos_count,
+    comment_count = excluded.comment_count,
+    athlete_count = excluded.athlete_count,
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertActivitySocialCountsParams struct {
+	ActivityID   int64 `json:"activity_id"`
+	KudosCount   int64 `json:"kudos_count"`
+	CommentCount int64 `json:"comment_count"`
+	AthleteCount int64 `json:"athlete_count"`
+}
+
+func (q *Queries) UpsertActivitySocialCounts(ctx context.Context, arg UpsertActivitySocialCountsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertActivitySocialCounts,
+		arg.ActivityID,
+		arg.KudosCount,
+		arg.CommentCount,
+		arg.AthleteCount,
+	)
+	return err
+}
+
+const upsertActivityStreams = `-- name: UpsertActivityStreams :exec
+INSERT INTO activity_streams (
+    activity_id, point_count, time_data, distance_data, latlng_data, altitude_data,
+    velocity_data, heartrate_data, cadence_data, watts_data, grade_data, moving_data
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    point_count = excluded.point_count,
+    time_data = excluded.time_data,
+    distance_data = excluded.distance_data,
+    latlng_data = excluded.latlng_data,
+    altitude_data = excluded.altitude_data,
+    velocity_data = excluded.velocity_data,
+    heartrate_data = excluded.heartrate_data,
+    cadence_data = excluded.cadence_data,
+    watts_data = excluded.watts_data,
+    grade_data = excluded.grade_data,
+    moving_data = excluded.moving_data,
+    fetched_at = CURRENT_TIMESTAMP
+`
+
+type UpsertActivityStreamsParams struct {
+	ActivityID    int64          `json:"activity_id"`
+	PointCount    int64          `json:"point_count"`
+	TimeData      sql.NullString `json:"time_data"`
+	DistanceData  sql.NullString `json:"distance_data"`
+	LatlngData    sql.NullString `json:"latlng_data"`
+	AltitudeData  sql.NullString `json:"altitude_data"`
+	VelocityData  sql.NullString `json:"velocity_data"`
+	HeartrateData sql.NullString `json:"heartrate_data"`
+	CadenceData   sql.NullString `json:"cadence_data"`
+	WattsData     sql.NullString `json:"watts_data"`
+	GradeData     sql.NullString `json:"grade_data"`
+	MovingData    sql.NullString `json:"moving_data"`
+}
+
+func (q *Queries) UpsertActivityStreams(ctx context.Context, arg UpsertActivityStreamsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertActivityStreams,
+		arg.ActivityID,
+		arg.PointCount,
+		arg.TimeData,
+		arg.DistanceData,
+		arg.LatlngData,
+		arg.AltitudeData,
+		arg.VelocityData,
+		arg.HeartrateData,
+		arg.CadenceData,
+		arg.WattsData,
+		arg.GradeData,
+		arg.MovingData,
+	)
+	return err
+}
+
+const upsertClub = `-- name: UpsertClub :exec
+INSERT INTO clubs (id, name, sport_type, city, state, country, private, member_count, url, synced_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    name = excluded.name,
+    sport_type = excluded.sport_type,
+    city = excluded.city,
+    state = excluded.state,
+    country = excluded.country,
+    private = excluded.private,
+    member_count = excluded.member_count,
+    url = excluded.url,
+    synced_at = excluded.synced_at
+`
+
+type UpsertClubParams struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	SportType   sql.NullString `json:"sport_type"`
+	City        sql.NullString `json:"city"`
+	State       sql.NullString `json:"state"`
+	Country     sql.NullString `json:"country"`
+	Private     int64          `json:"private"`
+	MemberCount int64          `json:"member_count"`
+	Url         sql.NullString `json:"url"`
+	SyncedAt    sql.NullTime   `json:"synced_at"`
+}
+
+func (q *Queries) UpsertClub(ctx context.Context, arg UpsertClubParams) error {
+	_, err := q.db.ExecContext(ctx, upsertClub,
+		arg.ID,
+		arg.Name,
+		arg.SportType,
+		arg.City,
+		arg.State,
+		arg.Country,
+		arg.Private,
+		arg.MemberCount,
+		arg.Url,
+		arg.SyncedAt,
+	)
+	return err
+}
+
+const upsertRateLimitBudget = `-- name: UpsertRateLimitBudget :exec
+INSERT INTO rate_limit_budget (
+    id, window_start, window_usage, window_limit, day_start, daily_usage, daily_limit, updated_at
+) VALUES (1, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    window_start = excluded.window_start,
+    window_usage = excluded.window_usage,
+    window_limit = excluded.window_limit,
+    day_start = excluded.day_start,
+    daily_usage = excluded.daily_usage,
+    daily_limit = excluded.daily_limit,
+    updated_at = excluded.updated_at
+`
+
+type UpsertRateLimitBudgetParams struct {
+	WindowStart time.Time `json:"window_start"`
+	WindowUsage int64     `json:"window_usage"`
+	WindowLimit int64     `json:"window_limit"`
+	DayStart    time.Time `json:"day_start"`
+	DailyUsage  int64     `json:"daily_usage"`
+	DailyLimit  int64     `json:"daily_limit"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) UpsertRateLimitBudget(ctx context.Context, arg UpsertRateLimitBudgetParams) error {
+	_, err := q.db.ExecContext(ctx, upsertRateLimitBudget,
+		arg.WindowStart,
+		arg.WindowUsage,
+		arg.WindowLimit,
+		arg.DayStart,
+		arg.DailyUsage,
+		arg.DailyLimit,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const upsertSavedRoute = `-- name: UpsertSavedRoute :exec
+INSERT INTO saved_routes (
+    id, name, description, type, sub_type, distance, elevation_gain,
+    estimated_moving_time, polyline, private, starred, created_at, updated_at, synced_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    name = excluded.name,
+    description = excluded.description,
+    type = excluded.type,
+    sub_type = excluded.sub_type,
+    distance = excluded.distance,
+    elevation_gain = excluded.elevation_gain,
+    estimated_moving_time = excluded.estimated_moving_time,
+    polyline = excluded.polyline,
+    private = excluded.private,
+    starred = excluded.starred,
+    created_at = excluded.created_at,
+    updated_at = excluded.updated_at,
+    synced_at = excluded.synced_at
+`
+
+type UpsertSavedRouteParams struct {
+	ID                  int64          `json:"id"`
+	Name                string         `json:"name"`
+	Description         sql.NullString `json:"description"`
+	Type                sql.NullString `json:"type"`
+	SubType             sql.NullString `json:"sub_type"`
+	Distance            float64        `json:"distance"`
+	ElevationGain       float64        `json:"elevation_gain"`
+	EstimatedMovingTime int64          `json:"estimated_moving_time"`
+	Polyline            sql.NullString `json:"polyline"`
+	Private             int64          `json:"private"`
+	Starred             int64          `json:"starred"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	SyncedAt            time.Time      `json:"synced_at"`
+}
+
+func (q *Queries) UpsertSavedRoute(ctx context.Context, arg UpsertSavedRouteParams) error {
+	_, err := q.db.ExecContext(ctx, upsertSavedRoute,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.SubType,
+		arg.Distance,
+		arg.ElevationGain,
+		arg.EstimatedMovingTime,
+		arg.Polyline,
+		arg.Private,
+		arg.Starred,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.SyncedAt,
+	)
+	return err
+}
+
+const upsertStarredSegment = `-- name: UpsertStarredSegment :exec
+INSERT INTO starred_segments (
+    id, name, activity_type, distance, average_grade, maximum_grade, elevation_high,
+    elevation_low, climb_category, city, state, country, private, start_lat, start_lng,
+    end_lat, end_lng, pr_elapsed_time, pr_date, pr_activity_id, effort_count, synced_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    name = excluded.name,
+    activity_type = excluded.activity_type,
+    distance = excluded.distance,
+    average_grade = excluded.average_grade,
+    maximum_grade = excluded.maximum_grade,
+    elevation_high = excluded.elevation_high,
+    elevation_low = excluded.elevation_low,
+    climb_category = excluded.climb_category,
+    city = excluded.city,
+    state = excluded.state,
+    country = excluded.country,
+    private = excluded.private,
+    start_lat = excluded.start_lat,
+    start_lng = excluded.start_lng,
+    end_lat = excluded.end_lat,
+    end_lng = excluded.end_lng,
+    pr_elapsed_time = excluded.pr_elapsed_time,
+    pr_date = excluded.pr_date,
+    pr_activity_id = excluded.pr_activity_id,
+    effort_count = excluded.effort_count,
+    synced_at = excluded.synced_at
+`
+
+type UpsertStarredSegmentParams struct {
+	ID            int64           `json:"id"`
+	Name          string          `json:"name"`
+	ActivityType  sql.NullString  `json:"activity_type"`
+	Distance      float64         `json:"distance"`
+	AverageGrade  float64         `json:"average_grade"`
+	MaximumGrade  float64         `json:"maximum_grade"`
+	ElevationHigh float64         `json:"elevation_high"`
+	ElevationLow  float64         `json:"elevation_low"`
+	ClimbCategory int64           `json:"climb_category"`
+	City          sql.NullString  `json:"city"`
+	State         sql.NullString  `json:"state"`
+	Country       sql.NullString  `json:"country"`
+	Private       int64           `json:"private"`
+	StartLat      sql.NullFloat64 `json:"start_lat"`
+	StartLng      sql.NullFloat64 `json:"start_lng"`
+	EndLat        sql.NullFloat64 `json:"end_lat"`
+	EndLng        sql.NullFloat64 `json:"end_lng"`
+	PrElapsedTime sql.NullInt64   `json:"pr_elapsed_time"`
+	PrDate        sql.NullString  `json:"pr_date"`
+	PrActivityID  sql.NullInt64   `json:"pr_activity_id"`
+	EffortCount   int64           `json:"effort_count"`
+	SyncedAt      time.Time       `json:"synced_at"`
+}
+
+func (q *Queries) UpsertStarredSegment(ctx context.Context, arg UpsertStarredSegmentParams) error {
+	_, err := q.db.ExecContext(ctx, upsertStarredSegment,
+		arg.ID,
+		arg.Name,
+		arg.ActivityType,
+		arg.Distance,
+		arg.AverageGrade,
+		arg.MaximumGrade,
+		arg.ElevationHigh,
+		arg.ElevationLow,
+		arg.ClimbCategory,
+		arg.City,
+		arg.State,
+		arg.Country,
+		arg.Private,
+		arg.StartLat,
+		arg.StartLng,
+		arg.EndLat,
+		arg.EndLng,
+		arg.PrElapsedTime,
+		arg.PrDate,
+		arg.PrActivityID,
+		arg.EffortCount,
+		arg.SyncedAt,
+	)
+	return err
+}
+
+const upsertSyncIntegrity = `-- name: UpsertSyncIntegrity :exec
+INSERT INTO sync_integrity (
+    sport, period, window_start, strava_count, local_count, strava_distance, local_distance,
+    strava_moving_time, local_moving_time, checked_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(sport, period) DO UPDATE SET
+    window_start = excluded.window_start,
+    strava_count = excluded.strava_count,
+    local_count = excluded.local_count,
+    strava_distance = excluded.strava_distance,
+    local_distance = excluded.local_distance,
+    strava_moving_time = excluded.strava_moving_time,
+    local_moving_time = excluded.local_moving_time,
+    checked_at = excluded.checked_at
+`
+
+type UpsertSyncIntegrityParams struct {
+	Sport            string       `json:"sport"`
+	Period           string       `json:"period"`
+	WindowStart      sql.NullTime `json:"window_start"`
+	StravaCount      int64        `json:"strava_count"`
+	LocalCount       int64        `json:"local_count"`
+	StravaDistance   float64      `json:"strava_distance"`
+	LocalDistance    float64      `json:"local_distance"`
+	StravaMovingTime int64        `json:"strava_moving_time"`
+	LocalMovingTime  int64        `json:"local_moving_time"`
+	CheckedAt        time.Time    `json:"checked_at"`
+}
+
+func (q *Queries) UpsertSyncIntegrity(ctx context.Context, arg UpsertSyncIntegrityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertSyncIntegrity,
+		arg.Sport,
+		arg.Period,
+		arg.WindowStart,
+		arg.StravaCount,
+		arg.LocalCount,
+		arg.StravaDistance,
+		arg.LocalDistance,
+		arg.StravaMovingTime,
+		arg.LocalMovingTime,
+		arg.CheckedAt,
+	)
+	return err
+}
+
+const upsertThresholdEffort = `-- name: UpsertThresholdEffort :exec
+INSERT INTO threshold_efforts (activity_id, sport, start_date, lthr, ftp, threshold_speed, analyzed_at)
+VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET
+    sport = excluded.sport,
+    start_date = excluded.start_date,
+    lthr = excluded.lthr,
+    ftp = excluded.ftp,
+    threshold_speed = excluded.threshold_speed,
+    analyzed_at = CURRENT_TIMESTAMP
+`
+
+type UpsertThresholdEffortParams struct {
+	ActivityID     int64           `json:"activity_id"`
+	Sport          string          `json:"sport"`
+	StartDate      sql.NullTime    `json:"start_date"`
+	Lthr           sql.NullFloat64 `json:"lthr"`
+	Ftp            sql.NullFloat64 `json:"ftp"`
+	ThresholdSpeed sql.NullFloat64 `json:"threshold_speed"`
+}
+
+func (q *Queries) UpsertThresholdEffort(ctx context.Context, arg UpsertThresholdEffortParams) error {
+	_, err := q.db.ExecContext(ctx, upsertThresholdEffort,
+		arg.ActivityID,
+		arg.Sport,
+		arg.StartDate,
+		arg.Lthr,
+		arg.Ftp,
+		arg.ThresholdSpeed,
+	)
+	return err
+}
+
+const upsertZoneSetting = `-- name: UpsertZoneSetting :exec
+INSERT INTO zone_settings (zone_type, boundaries, source, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(zone_type) DO UPDATE SET
+    boundaries = excluded.boundaries,
+    source = excluded.source,
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertZoneSettingParams struct {
+	ZoneType   string `json:"zone_type"`
+	Boundaries string `json:"boundaries"`
+	Source     string `json:"source"`
+}
+
+func (q *Queries) UpsertZoneSetting(ctx context.Context, arg UpsertZoneSettingParams) error {
+	_, err := q.db.ExecContext(ctx, upsertZoneSetting, arg.ZoneType, arg.Boundaries, arg.Source)
+	return err
+}
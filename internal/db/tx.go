@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Writes that take several generated queries, each run in one transaction so
+// a failure part way leaves nothing half done. This file is not generated by
+// sqlc.
+
+// ExecTx runs fn with queries bound to one transaction, committed when fn
+// returns nil and rolled back otherwise. Queries already bound to a
+// transaction run fn in it.
+func (q *Queries) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	beginner, ok := q.db.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	if err := fn(q.WithTx(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveActivityCorrection stores corrections to an activity and applies them
+// to the stored activity
+func (q *Queries) SaveActivityCorrection(ctx context.Context, arg UpsertActivityCorrectionParams) error {
+	return q.ExecTx(ctx, func(q *Queries) error {
+		if err := q.UpsertActivityCorrection(ctx, arg); err != nil {
+			return err
+		}
+		return q.ApplyActivityOverride(ctx, arg.ID)
+	})
+}
+
+// ResetActivityOverride puts Strava's values back on an activity, clears its
+// corrections and drops the override once nothing is left in it
+func (q *Queries) ResetActivityOverride(ctx context.Context, activityID int64) error {
+	return q.ExecTx(ctx, func(q *Queries) error {
+		if err := q.ResetActivityCorrections(ctx, activityID); err != nil {
+			return err
+		}
+		if err := q.ClearActivityCorrections(ctx, activityID); err != nil {
+			return err
+		}
+		return q.DeleteEmptyActivityOverride(ctx, activityID)
+	})
+}
+
+// SetActivityExclusion sets whether an activity counts in stats, dropping
+// the override once nothing is left in it
+func (q *Queries) SetActivityExclusion(ctx context.Context, arg SetActivityCountedParams) error {
+	return q.ExecTx(ctx, func(q *Queries) error {
+		if err := q.SetActivityCounted(ctx, arg); err != nil {
+			return err
+		}
+		return q.DeleteEmptyActivityOverride(ctx, arg.ActivityID)
+	})
+}
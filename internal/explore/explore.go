@@ -0,0 +1,281 @@
+// Package explore aggregates the GPS data of all stored activities into
+// explorer tiles (VeloViewer style) and a heatmap grid.
+package explore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+// HeatmapZoom is the tile zoom used for heatmap cells, about 200-300 m
+const HeatmapZoom = 17
+
+// pageSize is the number of streams loaded per query while aggregating
+const pageSize = 100
+
+// Source is the subset of queries needed to aggregate GPS coverage
+type Source interface {
+	ListLatlngStreams(ctx context.Context, arg db.ListLatlngStreamsParams) ([]db.ListLatlngStreamsRow, error)
+	ListPolylinesWithoutStreams(ctx context.Context, arg db.ListPolylinesWithoutStreamsParams) ([]db.ListPolylinesWithoutStreamsRow, error)
+}
+
+// Filter restricts which activities are aggregated
+type Filter struct {
+	Type      string
+	StartDate sql.NullTime
+	EndDate   sql.NullTime
+}
+
+// TileVisit records when an explorer tile was first reached and how often
+type TileVisit struct {
+	FirstVisited    time.Time
+	FirstActivityID int64
+	Activities      int
+}
+
+// Coverage is the aggregated GPS coverage of a set of activities
+type Coverage struct {
+	Activities    int
+	FromStreams   int
+	FromPolylines int
+	Tiles         map[geo.Tile]*TileVisit
+	// Heat counts the activities passing through each heatmap cell
+	Heat map[geo.Tile]int
+}
+
+// Build aggregates every matching activity, preferring full-resolution GPS
+// streams and falling back to the summary polyline when none are stored
+func Build(ctx context.Context, src Source, filter Filter) (*Coverage, error) {
+	c := &Coverage{
+		Tiles: make(map[geo.Tile]*TileVisit),
+		Heat:  make(map[geo.Tile]int),
+	}
+	hasType := filter.Type != ""
+	typeParam := sql.NullString{String: filter.Type, Valid: hasType}
+
+	var after int64
+	for {
+		rows, err := src.ListLatlngStreams(ctx, db.ListLatlngStreamsParams{
+			ActivityID:  after,
+			Column2:     typeParam,
+			Type:        typeParam,
+			Column4:     filter.StartDate,
+			StartDate:   filter.StartDate,
+			Column6:     filter.EndDate,
+			StartDate_2: filter.EndDate,
+			Limit:       pageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing GPS streams: %w", err)
+		}
+		for _, row := range rows {
+			after = row.ActivityID
+			st, err := streams.FromRow(db.ActivityStream{ActivityID: row.ActivityID, LatlngData: row.LatlngData})
+			if err != nil {
+				logging.Warn("Skipping unreadable GPS stream", "activity_id", row.ActivityID, "error", err)
+				continue
+			}
+			if c.add(row.ActivityID, row.StartDate.Time, st.Points()) {
+				c.FromStreams++
+			}
+		}
+		if len(rows) < pageSize {
+			break
+		}
+	}
+
+	polylines, err := src.ListPolylinesWithoutStreams(ctx, db.ListPolylinesWithoutStreamsParams{
+		Column1:     typeParam,
+		Type:        typeParam,
+		Column3:     filter.StartDate,
+		StartDate:   filter.StartDate,
+		Column5:     filter.EndDate,
+		StartDate_2: filter.EndDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing polylines: %w", err)
+	}
+	for _, row := range polylines {
+		points, err := geo.DecodePolyline(row.SummaryPolyline.String)
+		if err != nil {
+			logging.Warn("Skipping undecodable polyline", "activity_id", row.ID, "error", err)
+			continue
+		}
+		if c.add(row.ID, row.StartDate.Time, points) {
+			c.FromPolylines++
+		}
+	}
+
+	return c, nil
+}
+
+// add merges one activity's track, reporting whether it had any points
+func (c *Coverage) add(activityID int64, date time.Time, points []geo.Point) bool {
+	if len(points) == 0 {
+		return false
+	}
+	c.Activities++
+
+	for t := range geo.TilesAlong(points, geo.ExplorerZoom) {
+		v, ok := c.Tiles[t]
+		if !ok {
+			v = &TileVisit{FirstVisited: date, FirstActivityID: activityID}
+			c.Tiles[t] = v
+		} else if date.Before(v.FirstVisited) {
+			v.FirstVisited, v.FirstActivityID = date, activityID
+		}
+		v.Activities++
+	}
+	for t := range geo.TilesAlong(points, HeatmapZoom) {
+		c.Heat[t]++
+	}
+	return true
+}
+
+// Stats are the headline explorer numbers
+type Stats struct {
+	TilesVisited int
+	MaxSquare    int
+	// MaxSquareOrigin is the north-west tile of the max square
+	MaxSquareOrigin geo.Tile
+	MaxCluster      int
+	// TilesToNextSquare is the fewest new tiles that would grow the max
+	// square by one, checked around the current max square
+	TilesToNextSquare int
+	// NewTilesSince counts tiles first visited on or after the since time
+	NewTilesSince int
+}
+
+// Stats computes the explorer statistics; since bounds NewTilesSince
+func (c *Coverage) Stats(since time.Time) Stats {
+	visited := c.visited()
+	s := Stats{TilesVisited: len(visited)}
+	s.MaxSquare, s.MaxSquareOrigin = geo.MaxSquare(visited)
+	s.MaxCluster = len(geo.MaxCluster(visited))
+
+	if s.MaxSquare > 0 {
+		// A square one larger that contains the current one shares its
+		// tiles, so only its four placements need checking
+		origin := s.MaxSquareOrigin
+		s.TilesToNextSquare = -1
+		for _, d := range [][2]int{{0, 0}, {-1, 0}, {0, -1}, {-1, -1}} {
+			nw := geo.Tile{X: origin.X + d[0], Y: origin.Y + d[1], Z: origin.Z}
+			missing := geo.MissingForSquare(visited, nw, s.MaxSquare+1)
+			if s.TilesToNextSquare < 0 || missing < s.TilesToNextSquare {
+				s.TilesToNextSquare = missing
+			}
+		}
+	}
+
+	for _, v := range c.Tiles {
+		if !v.FirstVisited.Before(since) {
+			s.NewTilesSince++
+		}
+	}
+	return s
+}
+
+// Hotspot is one of the most visited heatmap cells
+type Hotspot struct {
+	Center     geo.Point
+	Activities int
+}
+
+// Hotspots returns the n most visited heatmap cells, busiest first
+func (c *Coverage) Hotspots(n int) []Hotspot {
+	cells := make([]geo.Tile, 0, len(c.Heat))
+	for t := range c.Heat {
+		cells = append(cells, t)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		if c.Heat[a] != c.Heat[b] {
+			return c.Heat[a] > c.Heat[b]
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+
+	if len(cells) > n {
+		cells = cells[:n]
+	}
+	spots := make([]Hotspot, len(cells))
+	for i, t := range cells {
+		spots[i] = Hotspot{Center: t.Center(), Activities: c.Heat[t]}
+	}
+	return spots
+}
+
+// FeatureCollection exports the coverage as GeoJSON polygons: one feature
+// per explorer tile (kind "tile", flagged when part of the max cluster), the
+// max square outline (kind "max_square"), and optionally one feature per
+// heatmap cell (kind "heat")
+func (c *Coverage) FeatureCollection(includeHeat bool) geo.PolygonCollection {
+	visited := c.visited()
+	cluster := make(map[geo.Tile]bool)
+	for _, t := range geo.MaxCluster(visited) {
+		cluster[t] = true
+	}
+
+	fc := geo.PolygonCollection{Type: "FeatureCollection", Features: []geo.PolygonFeature{}}
+	for t, v := range c.Tiles {
+		fc.Features = append(fc.Features, geo.NewPolygonFeature(geo.TileBlockPolygon(t, 1), map[string]any{
+			"kind":              "tile",
+			"x":                 t.X,
+			"y":                 t.Y,
+			"z":                 t.Z,
+			"activities":        v.Activities,
+			"first_visited":     v.FirstVisited.UTC().Format(time.DateOnly),
+			"first_activity_id": v.FirstActivityID,
+			"cluster":           cluster[t],
+		}))
+	}
+
+	if size, nw := geo.MaxSquare(visited); size > 0 {
+		fc.Features = append(fc.Features, geo.NewPolygonFeature(geo.TileBlockPolygon(nw, size), map[string]any{
+			"kind": "max_square",
+			"size": size,
+		}))
+	}
+
+	if includeHeat {
+		for t, n := range c.Heat {
+			fc.Features = append(fc.Features, geo.NewPolygonFeature(geo.TileBlockPolygon(t, 1), map[string]any{
+				"kind":       "heat",
+				"activities": n,
+			}))
+		}
+	}
+
+	// Map iteration is random; keep the output stable
+	sort.SliceStable(fc.Features, func(i, j int) bool {
+		a, b := fc.Features[i].Geometry.Coordinates[0][3], fc.Features[j].Geometry.Coordinates[0][3]
+		ka, kb := fc.Features[i].Properties["kind"].(string), fc.Features[j].Properties["kind"].(string)
+		if ka != kb {
+			return ka > kb // tiles, then max_square, then heat
+		}
+		if a[1] != b[1] {
+			return a[1] > b[1]
+		}
+		return a[0] < b[0]
+	})
+	return fc
+}
+
+func (c *Coverage) visited() map[geo.Tile]struct{} {
+	set := make(map[geo.Tile]struct{}, len(c.Tiles))
+	for t := range c.Tiles {
+		set[t] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,153 @@
+package explore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+)
+
+// fakeSource serves canned streams, honouring the paging cursor
+type fakeSource struct {
+	streams   []db.ListLatlngStreamsRow
+	polylines []db.ListPolylinesWithoutStreamsRow
+	pages     int
+}
+
+func (f *fakeSource) ListLatlngStreams(ctx context.Context, arg db.ListLatlngStreamsParams) ([]db.ListLatlngStreamsRow, error) {
+	f.pages++
+	var rows []db.ListLatlngStreamsRow
+	for _, r := range f.streams {
+		if r.ActivityID > arg.ActivityID && int64(len(rows)) < arg.Limit {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeSource) ListPolylinesWithoutStreams(ctx context.Context, arg db.ListPolylinesWithoutStreamsParams) ([]db.ListPolylinesWithoutStreamsRow, error) {
+	return f.polylines, nil
+}
+
+// gridStream walks the centers of a w x h block of explorer tiles row by row
+func gridStream(t *testing.T, id int64, date time.Time, x, y, w, h int) db.ListLatlngStreamsRow {
+	t.Helper()
+	var latlng [][2]float64
+	for dy := 0; dy < h; dy++ {
+		for dx := 0; dx < w; dx++ {
+			c := geo.Tile{X: x + dx, Y: y + dy, Z: geo.ExplorerZoom}.Center()
+			latlng = append(latlng, [2]float64{c.Lat, c.Lng})
+		}
+	}
+	data, err := json.Marshal(latlng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.ListLatlngStreamsRow{
+		ActivityID: id,
+		StartDate:  sql.NullTime{Time: date, Valid: true},
+		LatlngData: sql.NullString{String: string(data), Valid: true},
+	}
+}
+
+func TestBuildAndStats(t *testing.T) {
+	jan := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	src := &fakeSource{
+		streams: []db.ListLatlngStreamsRow{
+			gridStream(t, 1, jan, 100, 100, 3, 3),
+			gridStream(t, 2, jun, 100, 100, 4, 1), // revisits the top row, adds one tile
+		},
+		polylines: []db.ListPolylinesWithoutStreamsRow{{
+			ID:              3,
+			StartDate:       sql.NullTime{Time: jun, Valid: true},
+			SummaryPolyline: sql.NullString{String: geo.EncodePolyline([]geo.Point{geo.Tile{X: 200, Y: 200, Z: geo.ExplorerZoom}.Center()}), Valid: true},
+		}},
+	}
+
+	c, err := Build(context.Background(), src, Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Activities != 3 || c.FromStreams != 2 || c.FromPolylines != 1 {
+		t.Errorf("unexpected counts: %d activities, %d streams, %d polylines", c.Activities, c.FromStreams, c.FromPolylines)
+	}
+
+	nw := geo.Tile{X: 100, Y: 100, Z: geo.ExplorerZoom}
+	if v := c.Tiles[nw]; v == nil || v.Activities != 2 || v.FirstActivityID != 1 {
+		t.Errorf("unexpected visit for the shared tile: %+v", v)
+	}
+
+	s := c.Stats(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	if s.TilesVisited != 11 {
+		t.Errorf("TilesVisited = %d, want 11", s.TilesVisited)
+	}
+	if s.MaxSquare != 3 || s.MaxSquareOrigin != nw {
+		t.Errorf("MaxSquare = %d at %+v, want 3 at %+v", s.MaxSquare, s.MaxSquareOrigin, nw)
+	}
+	if s.MaxCluster != 1 {
+		t.Errorf("MaxCluster = %d, want 1", s.MaxCluster)
+	}
+	// Growing to 4x4 eastward: (103,100) is visited, 6 more are needed
+	if s.TilesToNextSquare != 6 {
+		t.Errorf("TilesToNextSquare = %d, want 6", s.TilesToNextSquare)
+	}
+	if s.NewTilesSince != 2 {
+		t.Errorf("NewTilesSince = %d, want 2", s.NewTilesSince)
+	}
+}
+
+func TestBuildPages(t *testing.T) {
+	src := &fakeSource{}
+	for i := 1; i <= pageSize+5; i++ {
+		src.streams = append(src.streams, gridStream(t, int64(i), time.Now(), i, 0, 1, 1))
+	}
+
+	c, err := Build(context.Background(), src, Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.FromStreams != pageSize+5 || src.pages != 2 {
+		t.Errorf("expected %d streams over 2 pages, got %d over %d", pageSize+5, c.FromStreams, src.pages)
+	}
+}
+
+func TestHotspotsAndFeatures(t *testing.T) {
+	c := &Coverage{Tiles: map[geo.Tile]*TileVisit{}, Heat: map[geo.Tile]int{}}
+	a := geo.Tile{X: 2620, Y: 6332, Z: geo.ExplorerZoom}.Center()
+	b := geo.Tile{X: 2622, Y: 6332, Z: geo.ExplorerZoom}.Center()
+	for i := 0; i < 3; i++ {
+		c.add(int64(i), time.Now(), []geo.Point{a})
+	}
+	c.add(9, time.Now(), []geo.Point{a, b})
+
+	spots := c.Hotspots(1)
+	if len(spots) != 1 || spots[0].Activities != 4 {
+		t.Errorf("unexpected hotspots: %+v", spots)
+	}
+
+	fc := c.FeatureCollection(false)
+	kinds := map[string]int{}
+	for _, f := range fc.Features {
+		kinds[f.Properties["kind"].(string)]++
+	}
+	if kinds["tile"] != 3 || kinds["max_square"] != 1 || kinds["heat"] != 0 {
+		t.Errorf("unexpected feature kinds: %v", kinds)
+	}
+	if fc.Features[0].Properties["kind"] != "tile" {
+		t.Errorf("expected tiles first, got %v", fc.Features[0].Properties)
+	}
+
+	withHeat := c.FeatureCollection(true)
+	if len(withHeat.Features) <= len(fc.Features) {
+		t.Error("expected heat cells to be included")
+	}
+	if _, err := json.Marshal(withHeat); err != nil {
+		t.Errorf("marshaling: %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+// Package forecast projects weekly training volume. The weekly series is
+// divided by a week-of-year seasonal index from earlier years (how each week
+// compared with the year around it), a damped-trend Holt model is fitted to
+// what's left, and its one-step errors give the spread of the forecast.
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+// MinWeeks is the fewest complete weeks a forecast is made from
+const MinWeeks = 8
+
+// seasonWeeks is the history needed before last year's pattern is used: a
+// year, plus enough weeks to compare the same part of the year twice
+const seasonWeeks = 60
+
+// seasonSmoothing is how many weeks either side are averaged into a week's
+// seasonal value, so that one race or sick week doesn't become a pattern
+const seasonSmoothing = 2
+
+// Seasonal indexes stay within these bounds
+const (
+	minSeasonal = 0.3
+	maxSeasonal = 2.0
+)
+
+// damping shrinks the trend each week ahead so that forecasts level off
+// rather than extrapolate a recent build indefinitely
+const damping = 0.9
+
+// warmup is how many weeks the model runs before its errors are counted
+const warmup = 4
+
+// Smoothing parameters tried when fitting
+var (
+	alphas = []float64{0.1, 0.2, 0.3, 0.4, 0.5}
+	betas  = []float64{0.02, 0.05, 0.1, 0.2}
+)
+
+// Z80 is the normal quantile for an 80% interval, the band forecasts report
+const Z80 = 1.2816
+
+// Point is the forecast for one week
+type Point struct {
+	Start time.Time // Monday of the week
+	Mean  float64
+	Low   float64 // 80% interval
+	High  float64
+}
+
+// Model is a fitted forecast of a weekly series
+type Model struct {
+	level, trend float64
+	alpha, beta  float64
+	sigma        float64   // one-step error, before seasonality
+	seasonal     []float64 // by week of year; nil without seasonality
+	next         time.Time // Monday of the first week forecast
+	weeks        int
+}
+
+// Fit fits a model to weekly values, oldest first, for consecutive complete
+// weeks from the Monday start. It returns false when there are fewer than
+// MinWeeks.
+func Fit(start time.Time, values []float64) (Model, bool) {
+	if len(values) < MinWeeks {
+		return Model{}, false
+	}
+	m := Model{next: start.AddDate(0, 0, 7*len(values)), weeks: len(values)}
+	if len(values) >= seasonWeeks {
+		m.seasonal = seasonality(start, values)
+	}
+
+	adjusted := make([]float64, len(values))
+	for i, v := range values {
+		adjusted[i] = v / m.season(start.AddDate(0, 0, 7*i))
+	}
+
+	best := math.Inf(1)
+	for _, alpha := range alphas {
+		for _, beta := range betas {
+			level, trend, sse := holt(adjusted, alpha, beta)
+			if sse < best {
+				best = sse
+				m.level, m.trend, m.alpha, m.beta = level, trend, alpha, beta
+			}
+		}
+	}
+	m.sigma = math.Sqrt(best / float64(len(values)-warmup))
+	return m, true
+}
+
+// holt runs damped-trend exponential smoothing over values, returning the
+// final level and trend and the squared one-step errors after the warmup
+func holt(values []float64, alpha, beta float64) (level, trend, sse float64) {
+	for _, v := range values[:warmup] {
+		level += v
+	}
+	level /= warmup
+	for i, v := range values {
+		predicted := level + damping*trend
+		if i >= warmup {
+			sse += (v - predicted) * (v - predicted)
+		}
+		next := alpha*v + (1-alpha)*predicted
+		trend = beta*(next-level) + (1-beta)*damping*trend
+		level = next
+	}
+	return level, trend, sse
+}
+
+// seasonality is each week of the year's volume relative to the year around
+// it, averaged over the years seen and shrunk towards 1 when there's only one
+func seasonality(start time.Time, values []float64) []float64 {
+	sums := make([]float64, 53)
+	counts := make([]float64, 53)
+	for i := range values {
+		// The year around the week, which must be mostly seen
+		lo, hi := max(i-26, 0), min(i+26, len(values)-1)
+		if hi-lo < 39 {
+			continue
+		}
+		year := mean(values[lo : hi+1])
+		if year <= 0 {
+			continue
+		}
+		week := mean(values[max(i-seasonSmoothing, 0):min(i+seasonSmoothing+1, len(values))])
+		w := weekOfYear(start.AddDate(0, 0, 7*i))
+		sums[w] += week / year
+		counts[w]++
+	}
+
+	out := make([]float64, 53)
+	var total float64
+	for w := range out {
+		out[w] = 1
+		if counts[w] > 0 {
+			// One year of a pattern counts for half; more years for more
+			shrink := counts[w] / (counts[w] + 1)
+			out[w] = 1 + (sums[w]/counts[w]-1)*shrink
+			out[w] = min(max(out[w], minSeasonal), maxSeasonal)
+		}
+		total += out[w]
+	}
+	// Scale so the year averages 1
+	for w := range out {
+		out[w] *= 53 / total
+	}
+	return out
+}
+
+// weekOfYear is 0 for the seven days from January 1st, up to 52 for the
+// last day or two of the year
+func weekOfYear(t time.Time) int {
+	return (t.YearDay() - 1) / 7
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// season is the seasonal index for the week starting at t
+func (m Model) season(t time.Time) float64 {
+	if m.seasonal == nil {
+		return 1
+	}
+	return m.seasonal[weekOfYear(t)]
+}
+
+// Seasonal reports whether the model uses last year's pattern
+func (m Model) Seasonal() bool {
+	return m.seasonal != nil
+}
+
+// Weeks is how many weeks of history the model was fitted to
+func (m Model) Weeks() int {
+	return m.weeks
+}
+
+// Next is the Monday of the first week forecast
+func (m Model) Next() time.Time {
+	return m.next
+}
+
+// Forecast is the next weeks weeks, starting with Next
+func (m Model) Forecast(weeks int) []Point {
+	out := make([]Point, 0, weeks)
+	for h := 1; h <= weeks; h++ {
+		start := m.next.AddDate(0, 0, 7*(h-1))
+		mean, sd := m.mean(h)*m.season(start), m.spread(h)*m.season(start)
+		out = append(out, Point{
+			Start: start,
+			Mean:  mean,
+			Low:   max(mean-Z80*sd, 0),
+			High:  mean + Z80*sd,
+		})
+	}
+	return out
+}
+
+// mean is the seasonally adjusted forecast h weeks ahead, never negative
+func (m Model) mean(h int) float64 {
+	var damped, f float64 = 0, 1
+	for range h {
+		f *= damping
+		damped += f
+	}
+	return max(m.level+damped*m.trend, 0)
+}
+
+// spread is the standard deviation h weeks ahead before seasonality. Each
+// week's error carries into the level by alpha, so the spread grows with
+// the horizon; trend uncertainty is left out as the trend is damped.
+func (m Model) spread(h int) float64 {
+	return m.sigma * math.Sqrt(1+m.alpha*m.alpha*float64(h-1))
+}
+
+// Total is the forecast sum over the coming weeks, where weights[i] is the
+// fraction of week i counted (less than 1 for part of a week), with its
+// standard deviation. A week's error shifts the level for every week after
+// it, so errors are added up with those knock-on effects rather than as if
+// each week were independent.
+func (m Model) Total(weights []float64) (mean, sd float64) {
+	scaled := make([]float64, len(weights))
+	for i, w := range weights {
+		s := m.season(m.next.AddDate(0, 0, 7*i))
+		scaled[i] = w * s
+		mean += w * m.mean(i+1) * s
+	}
+	var variance, later float64
+	for j := len(scaled) - 1; j >= 0; j-- {
+		c := scaled[j] + m.alpha*later
+		variance += c * c
+		later += scaled[j]
+	}
+	return mean, m.sigma * math.Sqrt(variance)
+}
+
+// AtLeast is the probability that a normally distributed total with the
+// given mean and standard deviation reaches x
+func AtLeast(mean, sd, x float64) float64 {
+	if sd <= 0 {
+		if mean >= x {
+			return 1
+		}
+		return 0
+	}
+	return 0.5 * math.Erfc((x-mean)/(sd*math.Sqrt2))
+}
@@ -0,0 +1,126 @@
+package forecast
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// start is a Monday
+var start = time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC)
+
+// series builds weeks of volume from start as f of the week's start, plus
+// noise of up to spread either way
+func series(weeks int, spread float64, f func(time.Time) float64) []float64 {
+	r := rand.New(rand.NewPCG(1, 2))
+	out := make([]float64, weeks)
+	for i := range out {
+		out[i] = f(start.AddDate(0, 0, 7*i)) + (r.Float64()*2-1)*spread
+	}
+	return out
+}
+
+func TestFitTooShort(t *testing.T) {
+	if _, ok := Fit(start, make([]float64, MinWeeks-1)); ok {
+		t.Error("expected too few weeks to fit")
+	}
+}
+
+func TestForecastFlat(t *testing.T) {
+	values := series(30, 5, func(time.Time) float64 { return 40 })
+	m, ok := Fit(start, values)
+	if !ok || m.Seasonal() {
+		t.Fatalf("expected a non-seasonal fit, got %v, %v", ok, m.Seasonal())
+	}
+	if want := start.AddDate(0, 0, 7*30); !m.Next().Equal(want) {
+		t.Errorf("expected the forecast to start %s, got %s", want, m.Next())
+	}
+
+	points := m.Forecast(8)
+	for i, p := range points {
+		if math.Abs(p.Mean-40) > 4 {
+			t.Errorf("week %d: expected about 40, got %.1f", i, p.Mean)
+		}
+		if p.Low > 40 || p.High < 40 || p.Low >= p.Mean || p.High <= p.Mean {
+			t.Errorf("week %d: expected the interval to straddle 40, got %.1f-%.1f", i, p.Low, p.High)
+		}
+	}
+	// Further ahead is less certain
+	if points[7].High-points[7].Low <= points[0].High-points[0].Low {
+		t.Errorf("expected the interval to widen, got %+v and %+v", points[0], points[7])
+	}
+}
+
+func TestForecastTrendDamped(t *testing.T) {
+	values := series(20, 0, func(w time.Time) float64 {
+		return 20 + float64(w.Sub(start).Hours()/24/7)
+	})
+	m, _ := Fit(start, values)
+	points := m.Forecast(12)
+
+	if points[0].Mean <= values[len(values)-1] {
+		t.Errorf("expected the build to continue, got %.1f after %.1f", points[0].Mean, values[len(values)-1])
+	}
+	// A straight line would reach 51; damping levels it off well before
+	if last := points[11].Mean; last >= 47 || last <= points[0].Mean {
+		t.Errorf("expected a damped rise, got %.1f", last)
+	}
+}
+
+func TestForecastSeasonal(t *testing.T) {
+	// Three years of 60 a week in summer and 20 in winter
+	yearly := func(w time.Time) float64 {
+		return 40 + 20*math.Cos(2*math.Pi*float64(w.YearDay()-196)/365)
+	}
+	values := series(156, 3, yearly)
+	m, _ := Fit(start, values)
+	if !m.Seasonal() {
+		t.Fatal("expected three years to be seasonal")
+	}
+
+	// From mid-October into the new year volume should fall away
+	points := m.Forecast(12)
+	if first := points[0].Start; first.Month() != time.October {
+		t.Fatalf("expected the forecast to start in October, got %s", first)
+	}
+	if points[10].Mean >= points[0].Mean*0.85 {
+		t.Errorf("expected winter to be lower, got %.1f in %s after %.1f", points[10].Mean, points[10].Start.Format(time.DateOnly), points[0].Mean)
+	}
+	if math.Abs(points[10].Mean-yearly(points[10].Start)) > 8 {
+		t.Errorf("expected about %.1f in %s, got %.1f", yearly(points[10].Start), points[10].Start.Format(time.DateOnly), points[10].Mean)
+	}
+}
+
+func TestTotal(t *testing.T) {
+	values := series(30, 5, func(time.Time) float64 { return 40 })
+	m, _ := Fit(start, values)
+
+	weights := []float64{0.5, 1, 1, 1}
+	total, sd := m.Total(weights)
+	var sum, independent float64
+	for i, p := range m.Forecast(4) {
+		sum += p.Mean * weights[i]
+		s := (p.High - p.Mean) / Z80 * weights[i]
+		independent += s * s
+	}
+	if math.Abs(total-sum) > 1e-9 {
+		t.Errorf("expected the total of the weekly forecasts, %.2f, got %.2f", sum, total)
+	}
+	// Errors carry forward, so the total is less certain than independent weeks
+	if sd <= math.Sqrt(independent) {
+		t.Errorf("expected more spread than %.2f, got %.2f", math.Sqrt(independent), sd)
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	if p := AtLeast(100, 10, 100); math.Abs(p-0.5) > 1e-9 {
+		t.Errorf("expected even odds at the mean, got %.3f", p)
+	}
+	if p := AtLeast(100, 10, 100-Z80*10); math.Abs(p-0.9) > 0.001 {
+		t.Errorf("expected 90%% at the low end of the interval, got %.3f", p)
+	}
+	if AtLeast(100, 0, 90) != 1 || AtLeast(100, 0, 110) != 0 {
+		t.Error("expected certainty without spread")
+	}
+}
@@ -0,0 +1,98 @@
+package geo
+
+import "time"
+
+// FeatureCollection is a GeoJSON FeatureCollection (RFC 7946)
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature with a LineString geometry
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         int64          `json:"id"`
+	Geometry   LineString     `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// LineString is a GeoJSON LineString geometry. Coordinates are [lng, lat] per the spec.
+type LineString struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"`
+}
+
+// ToFeature converts a route into a GeoJSON Feature
+func (r Route) ToFeature() Feature {
+	coords := make([][2]float64, len(r.Points))
+	for i, p := range r.Points {
+		coords[i] = [2]float64{p.Lng, p.Lat}
+	}
+
+	props := map[string]any{
+		"activity_id": r.ActivityID,
+		"name":        r.Name,
+		"type":        r.Type,
+		"distance":    r.Distance,
+		"moving_time": r.MovingTime,
+	}
+	if !r.StartDate.IsZero() {
+		props["start_date"] = r.StartDate.UTC().Format(time.RFC3339)
+	}
+
+	return Feature{
+		Type:       "Feature",
+		ID:         r.ActivityID,
+		Geometry:   LineString{Type: "LineString", Coordinates: coords},
+		Properties: props,
+	}
+}
+
+// NewFeatureCollection builds a FeatureCollection with one feature per route
+func NewFeatureCollection(routes []Route) FeatureCollection {
+	features := make([]Feature, 0, len(routes))
+	for _, r := range routes {
+		features = append(features, r.ToFeature())
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// PolygonCollection is a GeoJSON FeatureCollection of polygon features
+type PolygonCollection struct {
+	Type     string           `json:"type"`
+	Features []PolygonFeature `json:"features"`
+}
+
+// PolygonFeature is a GeoJSON Feature with a Polygon geometry
+type PolygonFeature struct {
+	Type       string         `json:"type"`
+	Geometry   Polygon        `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Polygon is a GeoJSON Polygon geometry. The single ring is closed and
+// wound counter-clockwise per the spec.
+type Polygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// TileBlockPolygon returns the polygon covering a size x size block of tiles
+// whose north-west tile is nw
+func TileBlockPolygon(nw Tile, size int) Polygon {
+	a := nw.NorthWest()
+	b := Tile{X: nw.X + size, Y: nw.Y + size, Z: nw.Z}.NorthWest()
+	ring := [][2]float64{
+		{a.Lng, b.Lat}, // south-west
+		{b.Lng, b.Lat},
+		{b.Lng, a.Lat},
+		{a.Lng, a.Lat},
+		{a.Lng, b.Lat},
+	}
+	return Polygon{Type: "Polygon", Coordinates: [][][2]float64{ring}}
+}
+
+// NewPolygonFeature builds a polygon feature with the given properties
+func NewPolygonFeature(p Polygon, props map[string]any) PolygonFeature {
+	return PolygonFeature{Type: "Feature", Geometry: p, Properties: props}
+}
@@ -0,0 +1,122 @@
+package geo
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func sampleRoute() Route {
+	return Route{
+		ActivityID: 42,
+		Name:       "Morning Run",
+		Type:       "Run",
+		StartDate:  time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC),
+		Distance:   5000,
+		MovingTime: 1500,
+		Points:     []Point{{Lat: 38.5, Lng: -120.2}, {Lat: 40.7, Lng: -120.95}},
+	}
+}
+
+func TestNewFeatureCollection(t *testing.T) {
+	fc := NewFeatureCollection([]Route{sampleRoute()})
+
+	data, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string       `json:"type"`
+				Coordinates [][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Type != "FeatureCollection" {
+		t.Errorf("expected FeatureCollection, got %s", decoded.Type)
+	}
+	if len(decoded.Features) != 1 {
+		t.Fatalf("expected 1 feature, got %d", len(decoded.Features))
+	}
+	f := decoded.Features[0]
+	if f.Geometry.Type != "LineString" {
+		t.Errorf("expected LineString, got %s", f.Geometry.Type)
+	}
+	// GeoJSON orders coordinates as [lng, lat]
+	if f.Geometry.Coordinates[0] != [2]float64{-120.2, 38.5} {
+		t.Errorf("expected [lng, lat] ordering, got %v", f.Geometry.Coordinates[0])
+	}
+	if f.Properties["start_date"] != "2024-01-15T08:00:00Z" {
+		t.Errorf("expected start_date property, got %v", f.Properties["start_date"])
+	}
+}
+
+func TestNewFeatureCollection_Empty(t *testing.T) {
+	data, err := json.Marshal(NewFeatureCollection(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"features":[]`) {
+		t.Errorf("expected empty features array, got %s", data)
+	}
+}
+
+func TestWriteGPX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGPX(&buf, []Route{sampleRoute()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc gpxDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if len(doc.Tracks) != 1 {
+		t.Fatalf("expected 1 track, got %d", len(doc.Tracks))
+	}
+	trk := doc.Tracks[0]
+	if trk.Name != "Morning Run (2024-01-15)" {
+		t.Errorf("unexpected track name %q", trk.Name)
+	}
+	if len(trk.Segments) != 1 || len(trk.Segments[0].Points) != 2 {
+		t.Fatalf("expected 1 segment with 2 points, got %+v", trk.Segments)
+	}
+	if trk.Segments[0].Points[1].Lon != -120.95 {
+		t.Errorf("expected lon -120.95, got %v", trk.Segments[0].Points[1].Lon)
+	}
+}
+
+func TestRouteFromActivity(t *testing.T) {
+	a := db.Activity{
+		ID:              7,
+		Name:            "Lunch Ride",
+		Type:            sql.NullString{String: "Ride", Valid: true},
+		SummaryPolyline: sql.NullString{String: googleSample, Valid: true},
+	}
+
+	route, err := RouteFromActivity(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if route.ActivityID != 7 || route.Type != "Ride" || len(route.Points) != 3 {
+		t.Errorf("unexpected route %+v", route)
+	}
+
+	if _, err := RouteFromActivity(db.Activity{ID: 8}); err == nil {
+		t.Error("expected error for activity without polyline")
+	}
+}
@@ -0,0 +1,68 @@
+package geo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type gpxDoc struct {
+	XMLName xml.Name   `xml:"gpx"`
+	Version string     `xml:"version,attr"`
+	Creator string     `xml:"creator,attr"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	Tracks  []gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name"`
+	Type     string       `xml:"type,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+type gpxPoint struct {
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+// WriteGPX writes routes as a GPX 1.1 document with one track per route.
+// Summary polylines carry no timestamps or elevation, so track points are
+// position-only; the activity start date is folded into the track name.
+func WriteGPX(w io.Writer, routes []Route) error {
+	doc := gpxDoc{
+		Version: "1.1",
+		Creator: "strava-mcp",
+		XMLNS:   "http://www.topografix.com/GPX/1/1",
+	}
+
+	for _, r := range routes {
+		pts := make([]gpxPoint, len(r.Points))
+		for i, p := range r.Points {
+			pts[i] = gpxPoint{Lat: p.Lat, Lon: p.Lng}
+		}
+		name := r.Name
+		if !r.StartDate.IsZero() {
+			name = fmt.Sprintf("%s (%s)", r.Name, r.StartDate.UTC().Format(time.DateOnly))
+		}
+		doc.Tracks = append(doc.Tracks, gpxTrack{
+			Name:     name,
+			Type:     r.Type,
+			Segments: []gpxSegment{{Points: pts}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing GPX header: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encoding GPX: %w", err)
+	}
+	return enc.Close()
+}
@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Point is a WGS84 coordinate
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// polylinePrecision is the coordinate scale used by Strava's encoded polylines (5 decimals)
+const polylinePrecision = 1e5
+
+// DecodePolyline decodes a Google encoded polyline string into points
+func DecodePolyline(encoded string) ([]Point, error) {
+	points := []Point{}
+	var lat, lng int64
+	index := 0
+
+	for index < len(encoded) {
+		dLat, next, err := decodeValue(encoded, index)
+		if err != nil {
+			return nil, err
+		}
+		dLng, next, err := decodeValue(encoded, next)
+		if err != nil {
+			return nil, err
+		}
+		index = next
+
+		lat += dLat
+		lng += dLng
+		points = append(points, Point{
+			Lat: float64(lat) / polylinePrecision,
+			Lng: float64(lng) / polylinePrecision,
+		})
+	}
+
+	return points, nil
+}
+
+// decodeValue reads one zig-zag encoded varint starting at index
+func decodeValue(encoded string, index int) (int64, int, error) {
+	var result int64
+	var shift uint
+
+	for {
+		if index >= len(encoded) {
+			return 0, index, fmt.Errorf("truncated polyline at offset %d", index)
+		}
+		b := int64(encoded[index]) - 63
+		index++
+		if b < 0 || b > 63 {
+			return 0, index, fmt.Errorf("invalid polyline character at offset %d", index-1)
+		}
+		result |= (b & 0x1f) << shift
+		shift += 5
+		if b < 0x20 {
+			break
+		}
+	}
+
+	if result&1 != 0 {
+		return ^(result >> 1), index, nil
+	}
+	return result >> 1, index, nil
+}
+
+// EncodePolyline encodes points into a Google encoded polyline string
+func EncodePolyline(points []Point) string {
+	var sb strings.Builder
+	var prevLat, prevLng int64
+
+	for _, p := range points {
+		lat := int64(math.Round(p.Lat * polylinePrecision))
+		lng := int64(math.Round(p.Lng * polylinePrecision))
+		encodeValue(&sb, lat-prevLat)
+		encodeValue(&sb, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+
+	return sb.String()
+}
+
+func encodeValue(sb *strings.Builder, v int64) {
+	u := v << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		sb.WriteByte(byte((0x20 | (u & 0x1f)) + 63))
+		u >>= 5
+	}
+	sb.WriteByte(byte(u + 63))
+}
@@ -0,0 +1,56 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Reference polyline from Google's encoding documentation
+const googleSample = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+func TestDecodePolyline(t *testing.T) {
+	points, err := DecodePolyline(googleSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Point{
+		{Lat: 38.5, Lng: -120.2},
+		{Lat: 40.7, Lng: -120.95},
+		{Lat: 43.252, Lng: -126.453},
+	}
+	if len(points) != len(expected) {
+		t.Fatalf("expected %d points, got %d", len(expected), len(points))
+	}
+	for i, p := range points {
+		if math.Abs(p.Lat-expected[i].Lat) > 1e-9 || math.Abs(p.Lng-expected[i].Lng) > 1e-9 {
+			t.Errorf("point %d: expected %+v, got %+v", i, expected[i], p)
+		}
+	}
+}
+
+func TestDecodePolyline_Empty(t *testing.T) {
+	points, err := DecodePolyline("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(points) != 0 {
+		t.Errorf("expected no points, got %d", len(points))
+	}
+}
+
+func TestDecodePolyline_Truncated(t *testing.T) {
+	if _, err := DecodePolyline("_p~iF~ps|"); err == nil {
+		t.Error("expected error for truncated polyline")
+	}
+}
+
+func TestEncodePolyline_RoundTrip(t *testing.T) {
+	points, err := DecodePolyline(googleSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := EncodePolyline(points); got != googleSample {
+		t.Errorf("expected %q, got %q", googleSample, got)
+	}
+}
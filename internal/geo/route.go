@@ -0,0 +1,42 @@
+package geo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+// Route is an activity's geometry together with the metadata exported alongside it
+type Route struct {
+	ActivityID int64
+	Name       string
+	Type       string
+	StartDate  time.Time
+	Distance   float64 // meters
+	MovingTime int64   // seconds
+	Points     []Point
+}
+
+// RouteFromActivity decodes the stored summary polyline of an activity.
+// Activities without GPS data return an error.
+func RouteFromActivity(a db.Activity) (Route, error) {
+	if !a.SummaryPolyline.Valid || a.SummaryPolyline.String == "" {
+		return Route{}, fmt.Errorf("activity %d has no route", a.ID)
+	}
+
+	points, err := DecodePolyline(a.SummaryPolyline.String)
+	if err != nil {
+		return Route{}, fmt.Errorf("decoding polyline for activity %d: %w", a.ID, err)
+	}
+
+	return Route{
+		ActivityID: a.ID,
+		Name:       a.Name,
+		Type:       a.Type.String,
+		StartDate:  a.StartDate.Time,
+		Distance:   a.Distance.Float64,
+		MovingTime: a.MovingTime.Int64,
+		Points:     points,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package geo
+
+import "math"
+
+// EarthRadius is the mean Earth radius in meters
+const EarthRadius = 6371000.0
+
+// Haversine returns the great-circle distance between two points in meters
+func Haversine(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// PathLength returns the length of a track in meters
+func PathLength(points []Point) float64 {
+	total := 0.0
+	for i := 1; i < len(points); i++ {
+		total += Haversine(points[i-1], points[i])
+	}
+	return total
+}
+
+// Resample returns n points spaced evenly by distance along the track, so
+// tracks recorded at different rates can be compared point by point
+func Resample(points []Point, n int) []Point {
+	if len(points) == 0 || n <= 0 {
+		return nil
+	}
+	if len(points) == 1 || n == 1 {
+		out := make([]Point, n)
+		for i := range out {
+			out[i] = points[0]
+		}
+		return out
+	}
+
+	// cumulative distance at each input point
+	cum := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		cum[i] = cum[i-1] + Haversine(points[i-1], points[i])
+	}
+	total := cum[len(cum)-1]
+
+	out := make([]Point, 0, n)
+	j := 1
+	for i := 0; i < n-1; i++ {
+		target := total * float64(i) / float64(n-1)
+		for j < len(points)-1 && cum[j] < target {
+			j++
+		}
+		seg := cum[j] - cum[j-1]
+		f := 0.0
+		if seg > 0 {
+			f = math.Max(0, math.Min(1, (target-cum[j-1])/seg))
+		}
+		a, b := points[j-1], points[j]
+		out = append(out, Point{Lat: a.Lat + (b.Lat-a.Lat)*f, Lng: a.Lng + (b.Lng-a.Lng)*f})
+	}
+	return append(out, points[len(points)-1])
+}
+
+// ShapeDistance returns the mean distance in meters between corresponding
+// points of two equally resampled tracks. Direction matters: a loop run
+// clockwise and counter-clockwise are different shapes.
+func ShapeDistance(a, b []Point) float64 {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return math.Inf(1)
+	}
+	total := 0.0
+	for i := 0; i < n; i++ {
+		total += Haversine(a[i], b[i])
+	}
+	return total / float64(n)
+}
@@ -0,0 +1,54 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversine(t *testing.T) {
+	// One degree of latitude is about 111.2 km
+	d := Haversine(Point{Lat: 0, Lng: 0}, Point{Lat: 1, Lng: 0})
+	if math.Abs(d-111195) > 10 {
+		t.Errorf("Haversine() = %.0f, want ~111195", d)
+	}
+	if Haversine(Point{Lat: 37.7, Lng: -122.4}, Point{Lat: 37.7, Lng: -122.4}) != 0 {
+		t.Error("expected zero distance for identical points")
+	}
+}
+
+func TestResample(t *testing.T) {
+	// An uneven straight line north: samples should be evenly spaced
+	track := []Point{{Lat: 0, Lng: 0}, {Lat: 0.001, Lng: 0}, {Lat: 0.01, Lng: 0}}
+	out := Resample(track, 11)
+	if len(out) != 11 {
+		t.Fatalf("expected 11 points, got %d", len(out))
+	}
+	for i, p := range out {
+		want := 0.001 * float64(i)
+		if math.Abs(p.Lat-want) > 1e-9 {
+			t.Errorf("point %d: lat %.6f, want %.6f", i, p.Lat, want)
+		}
+	}
+	if out[10] != track[2] {
+		t.Errorf("expected the last point to be the track end, got %v", out[10])
+	}
+}
+
+func TestShapeDistance(t *testing.T) {
+	a := Resample([]Point{{Lat: 0, Lng: 0}, {Lat: 0.01, Lng: 0}}, 16)
+	// Same line recorded with more points
+	b := Resample([]Point{{Lat: 0, Lng: 0}, {Lat: 0.004, Lng: 0}, {Lat: 0.01, Lng: 0}}, 16)
+	if d := ShapeDistance(a, b); d > 1 {
+		t.Errorf("expected near-zero distance for the same line, got %.1f", d)
+	}
+
+	// Same line run in reverse is a different shape
+	rev := Resample([]Point{{Lat: 0.01, Lng: 0}, {Lat: 0, Lng: 0}}, 16)
+	if d := ShapeDistance(a, rev); d < 300 {
+		t.Errorf("expected a large distance for the reversed line, got %.1f", d)
+	}
+
+	if !math.IsInf(ShapeDistance(nil, a), 1) {
+		t.Error("expected infinite distance for an empty track")
+	}
+}
@@ -0,0 +1,195 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// ExplorerZoom is the slippy-map zoom level used for explorer tiles, roughly
+// 1.5 km squares at mid latitudes
+const ExplorerZoom = 14
+
+// Tile is a Web Mercator (slippy map) tile. X grows east, Y grows south.
+type Tile struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	Z int `json:"z"`
+}
+
+// TileAt returns the tile containing p at the given zoom
+func TileAt(p Point, zoom int) Tile {
+	x, y := tileCoords(p, zoom)
+	n := 1 << zoom
+	return Tile{
+		X: clampInt(int(math.Floor(x)), 0, n-1),
+		Y: clampInt(int(math.Floor(y)), 0, n-1),
+		Z: zoom,
+	}
+}
+
+// tileCoords returns fractional tile coordinates
+func tileCoords(p Point, zoom int) (float64, float64) {
+	n := float64(int(1) << zoom)
+	lat := math.Max(-85.05112878, math.Min(85.05112878, p.Lat)) * math.Pi / 180
+	x := (p.Lng + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * n
+	return x, y
+}
+
+// NorthWest returns the tile's north-west corner
+func (t Tile) NorthWest() Point {
+	n := float64(int(1) << t.Z)
+	lat := math.Atan(math.Sinh(math.Pi * (1 - 2*float64(t.Y)/n)))
+	return Point{Lat: lat * 180 / math.Pi, Lng: float64(t.X)/n*360 - 180}
+}
+
+// Center returns the midpoint of the tile
+func (t Tile) Center() Point {
+	nw := t.NorthWest()
+	se := Tile{X: t.X + 1, Y: t.Y + 1, Z: t.Z}.NorthWest()
+	return Point{Lat: (nw.Lat + se.Lat) / 2, Lng: (nw.Lng + se.Lng) / 2}
+}
+
+// TilesAlong returns the tiles a track passes through. Segments are
+// interpolated so sparse tracks, such as decoded summary polylines, don't
+// skip the tiles between two distant points.
+func TilesAlong(points []Point, zoom int) map[Tile]struct{} {
+	tiles := make(map[Tile]struct{})
+	for i, p := range points {
+		tiles[TileAt(p, zoom)] = struct{}{}
+		if i == 0 {
+			continue
+		}
+		x0, y0 := tileCoords(points[i-1], zoom)
+		x1, y1 := tileCoords(p, zoom)
+		// Sample at most a quarter tile apart
+		steps := int(math.Ceil(math.Max(math.Abs(x1-x0), math.Abs(y1-y0)) * 4))
+		if steps > 1<<16 {
+			continue // a teleport (bad GPS fix), not a route segment
+		}
+		for s := 1; s < steps; s++ {
+			f := float64(s) / float64(steps)
+			n := 1 << zoom
+			tiles[Tile{
+				X: clampInt(int(math.Floor(x0+(x1-x0)*f)), 0, n-1),
+				Y: clampInt(int(math.Floor(y0+(y1-y0)*f)), 0, n-1),
+				Z: zoom,
+			}] = struct{}{}
+		}
+	}
+	return tiles
+}
+
+// MaxSquare finds the largest n x n block of visited tiles and returns its
+// size and north-west tile
+func MaxSquare(visited map[Tile]struct{}) (int, Tile) {
+	tiles := sortedTiles(visited)
+
+	// size[t] is the side of the largest square whose south-east corner is t
+	size := make(map[Tile]int, len(tiles))
+	best, bestCorner := 0, Tile{}
+	for _, t := range tiles {
+		up := size[Tile{X: t.X, Y: t.Y - 1, Z: t.Z}]
+		left := size[Tile{X: t.X - 1, Y: t.Y, Z: t.Z}]
+		diag := size[Tile{X: t.X - 1, Y: t.Y - 1, Z: t.Z}]
+		s := 1 + min(up, left, diag)
+		size[t] = s
+		if s > best {
+			best, bestCorner = s, t
+		}
+	}
+	if best == 0 {
+		return 0, Tile{}
+	}
+	return best, Tile{X: bestCorner.X - best + 1, Y: bestCorner.Y - best + 1, Z: bestCorner.Z}
+}
+
+// MaxCluster returns the largest connected group of cluster tiles: visited
+// tiles whose four neighbours are all visited as well
+func MaxCluster(visited map[Tile]struct{}) []Tile {
+	inner := make(map[Tile]bool)
+	for t := range visited {
+		all := true
+		for _, n := range t.neighbours() {
+			if _, ok := visited[n]; !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			inner[t] = true
+		}
+	}
+
+	var best []Tile
+	seen := make(map[Tile]bool, len(inner))
+	for _, start := range sortedTiles(boolSet(inner)) {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		cluster := []Tile{start}
+		for i := 0; i < len(cluster); i++ {
+			for _, n := range cluster[i].neighbours() {
+				if inner[n] && !seen[n] {
+					seen[n] = true
+					cluster = append(cluster, n)
+				}
+			}
+		}
+		if len(cluster) > len(best) {
+			best = cluster
+		}
+	}
+	return best
+}
+
+// MissingForSquare counts the unvisited tiles in the size x size block whose
+// north-west tile is nw
+func MissingForSquare(visited map[Tile]struct{}, nw Tile, size int) int {
+	missing := 0
+	for dy := 0; dy < size; dy++ {
+		for dx := 0; dx < size; dx++ {
+			if _, ok := visited[Tile{X: nw.X + dx, Y: nw.Y + dy, Z: nw.Z}]; !ok {
+				missing++
+			}
+		}
+	}
+	return missing
+}
+
+func (t Tile) neighbours() [4]Tile {
+	return [4]Tile{
+		{X: t.X, Y: t.Y - 1, Z: t.Z},
+		{X: t.X + 1, Y: t.Y, Z: t.Z},
+		{X: t.X, Y: t.Y + 1, Z: t.Z},
+		{X: t.X - 1, Y: t.Y, Z: t.Z},
+	}
+}
+
+// sortedTiles orders tiles north to south, then west to east
+func sortedTiles(set map[Tile]struct{}) []Tile {
+	tiles := make([]Tile, 0, len(set))
+	for t := range set {
+		tiles = append(tiles, t)
+	}
+	sort.Slice(tiles, func(i, j int) bool {
+		if tiles[i].Y != tiles[j].Y {
+			return tiles[i].Y < tiles[j].Y
+		}
+		return tiles[i].X < tiles[j].X
+	})
+	return tiles
+}
+
+func boolSet(m map[Tile]bool) map[Tile]struct{} {
+	set := make(map[Tile]struct{}, len(m))
+	for t := range m {
+		set[t] = struct{}{}
+	}
+	return set
+}
+
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(hi, v))
+}
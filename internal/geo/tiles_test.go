@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func tileSet(tiles ...Tile) map[Tile]struct{} {
+	set := make(map[Tile]struct{}, len(tiles))
+	for _, t := range tiles {
+		set[t] = struct{}{}
+	}
+	return set
+}
+
+// block returns the w x h tiles with north-west corner (x, y)
+func block(x, y, w, h int) []Tile {
+	var tiles []Tile
+	for dy := 0; dy < h; dy++ {
+		for dx := 0; dx < w; dx++ {
+			tiles = append(tiles, Tile{X: x + dx, Y: y + dy, Z: ExplorerZoom})
+		}
+	}
+	return tiles
+}
+
+func TestTileAt(t *testing.T) {
+	// Known zoom 14 tile for central San Francisco
+	got := TileAt(Point{Lat: 37.7749, Lng: -122.4194}, ExplorerZoom)
+	want := Tile{X: 2620, Y: 6332, Z: ExplorerZoom}
+	if got != want {
+		t.Errorf("TileAt() = %+v, want %+v", got, want)
+	}
+
+	// The north-west corner of a tile maps back into that tile
+	if back := TileAt(got.NorthWest(), ExplorerZoom); back != got {
+		t.Errorf("round trip through NorthWest() gave %+v", back)
+	}
+	c := got.Center()
+	if math.Abs(c.Lat-37.7749) > 0.02 || math.Abs(c.Lng+122.4194) > 0.02 {
+		t.Errorf("Center() = %+v is not near the input point", c)
+	}
+}
+
+func TestTilesAlongInterpolates(t *testing.T) {
+	// Two points ~10 tiles apart east-west: every tile in between is visited
+	a := Tile{X: 2620, Y: 6332, Z: ExplorerZoom}.Center()
+	b := Tile{X: 2630, Y: 6332, Z: ExplorerZoom}.Center()
+	tiles := TilesAlong([]Point{a, b}, ExplorerZoom)
+	if len(tiles) != 11 {
+		t.Errorf("expected 11 tiles, got %d", len(tiles))
+	}
+}
+
+func TestMaxSquare(t *testing.T) {
+	tiles := append(block(10, 10, 3, 3), block(20, 20, 2, 5)...)
+	tiles = append(tiles, Tile{X: 13, Y: 10, Z: ExplorerZoom})
+
+	size, nw := MaxSquare(tileSet(tiles...))
+	if size != 3 || nw != (Tile{X: 10, Y: 10, Z: ExplorerZoom}) {
+		t.Errorf("MaxSquare() = %d at %+v, want 3 at (10,10)", size, nw)
+	}
+
+	if size, _ := MaxSquare(nil); size != 0 {
+		t.Errorf("MaxSquare(nil) = %d, want 0", size)
+	}
+}
+
+func TestMaxCluster(t *testing.T) {
+	// A 4x4 block has a 2x2 interior; a separate 3x3 block has a single interior tile
+	visited := tileSet(append(block(0, 0, 4, 4), block(10, 10, 3, 3)...)...)
+
+	cluster := MaxCluster(visited)
+	if len(cluster) != 4 {
+		t.Errorf("expected cluster of 4, got %d: %v", len(cluster), cluster)
+	}
+}
+
+func TestMissingForSquare(t *testing.T) {
+	visited := tileSet(block(0, 0, 3, 3)...)
+	if got := MissingForSquare(visited, Tile{Z: ExplorerZoom}, 4); got != 7 {
+		t.Errorf("MissingForSquare() = %d, want 7", got)
+	}
+}
+
+func TestTileBlockPolygon(t *testing.T) {
+	nw := Tile{X: 2620, Y: 6332, Z: ExplorerZoom}
+	p := TileBlockPolygon(nw, 2)
+	ring := p.Coordinates[0]
+	if len(ring) != 5 || ring[0] != ring[4] {
+		t.Fatalf("expected a closed ring of 5 positions, got %v", ring)
+	}
+	corner := nw.NorthWest()
+	if ring[3] != [2]float64{corner.Lng, corner.Lat} {
+		t.Errorf("expected north-west corner %v, got %v", corner, ring[3])
+	}
+}
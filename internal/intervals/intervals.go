@@ -0,0 +1,557 @@
+// Package intervals finds the work and recovery intervals of a structured
+// session in its pace or power stream. Laps aren't synced, so the structure
+// comes from change points in the stream rather than from the lap button.
+package intervals
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+)
+
+// Metrics intervals are detected from
+const (
+	MetricPace  = "pace"
+	MetricSpeed = "speed"
+	MetricPower = "power"
+)
+
+// maxSampleGap caps the seconds credited to a single sample, so a recording
+// gap doesn't stretch one reading across minutes
+const maxSampleGap = 30
+
+// smoothSeconds is the centred moving average applied before looking for
+// change points, enough to flatten GPS jitter without blurring a rep's edges
+const smoothSeconds = 10
+
+// minSegment is the shortest interval, in seconds, that is detected; anything
+// briefer is a blip, like a sprint to make a light
+const minSegment = 20
+
+// minStep is the smallest change, as a fraction of the session's average,
+// held for minSegment on both sides, that counts as a change point
+const minStep = 0.10
+
+// maxSegments bounds the change-point search
+const maxSegments = 200
+
+// minContrast is how much faster or harder work has to be than recovery for
+// a session to have structure at all; a hilly easy run stays under it
+var minContrast = map[string]float64{
+	MetricPace:  1.25,
+	MetricSpeed: 1.25,
+	MetricPower: 1.30,
+}
+
+// restSpeed is the recovery speed, in m/s, below which recoveries are
+// standing or walking rests rather than jogs
+const restSpeed = 1.5
+
+// Interval is one stretch of a session
+type Interval struct {
+	Work      bool
+	Start     int     // first sample
+	End       int     // one past the last sample
+	Duration  float64 // seconds
+	Distance  float64 // meters, 0 without a distance stream
+	Speed     float64 // m/s
+	Watts     float64
+	Heartrate float64
+}
+
+// Structure is a session split into warm-up, alternating work and recovery
+// intervals, and cool-down. Intervals starts and ends with work.
+type Structure struct {
+	Metric    string
+	WarmUp    Interval // zero Duration when the session starts with work
+	Intervals []Interval
+	CoolDown  Interval // zero Duration when the session ends with work
+}
+
+// Work returns the work intervals
+func (s Structure) Work() []Interval {
+	var out []Interval
+	for _, iv := range s.Intervals {
+		if iv.Work {
+			out = append(out, iv)
+		}
+	}
+	return out
+}
+
+// Recoveries returns the recoveries between work intervals
+func (s Structure) Recoveries() []Interval {
+	var out []Interval
+	for _, iv := range s.Intervals {
+		if !iv.Work {
+			out = append(out, iv)
+		}
+	}
+	return out
+}
+
+// Detect finds the intervals of a run or ride. It returns false when the
+// activity has no usable stream or no work clearly harder than the rest.
+func Detect(st *streams.Streams, activityType string) (Structure, bool) {
+	metric, values := signal(st, activityType)
+	n := len(values)
+	if metric == "" || n < 2 {
+		return Structure{}, false
+	}
+
+	weights := sampleWeights(st.Time)
+	smoothed := smooth(st.Time, values)
+	level := weightedMean(smoothed, weights, 0, n)
+	if level <= 0 {
+		return Structure{}, false
+	}
+
+	segments := changePoints(smoothed, weights, (minStep*level)*(minStep*level)*minSegment/2)
+	if len(segments) < 2 {
+		return Structure{}, false
+	}
+
+	// Split segment levels into two groups, recovery and work
+	means := make([]float64, len(segments))
+	durations := make([]float64, len(segments))
+	for i, seg := range segments {
+		means[i] = weightedMean(values, weights, seg[0], seg[1])
+		durations[i] = sum(weights, seg[0], seg[1])
+	}
+	low, high := twoMeans(means, durations)
+	if low > 0 && high/low < minContrast[metric] {
+		return Structure{}, false
+	}
+	cut := (low + high) / 2
+
+	// Merge neighbouring segments on the same side of the cut
+	var merged []Interval
+	for i, seg := range segments {
+		work := means[i] > cut
+		if last := len(merged) - 1; last >= 0 && merged[last].Work == work {
+			merged[last].End = seg[1]
+			continue
+		}
+		merged = append(merged, Interval{Work: work, Start: seg[0], End: seg[1]})
+	}
+
+	s := Structure{Metric: metric}
+	if !merged[0].Work {
+		s.WarmUp = merged[0]
+		merged = merged[1:]
+	}
+	if last := len(merged) - 1; last >= 0 && !merged[last].Work {
+		s.CoolDown = merged[last]
+		merged = merged[:last]
+	}
+	if len(merged) == 0 {
+		return Structure{}, false
+	}
+	refine(values, weights, &s.WarmUp, merged, &s.CoolDown)
+
+	s.WarmUp = measure(st, weights, s.WarmUp)
+	s.CoolDown = measure(st, weights, s.CoolDown)
+	for _, iv := range merged {
+		s.Intervals = append(s.Intervals, measure(st, weights, iv))
+	}
+	return s, true
+}
+
+// refine moves each boundary between intervals to where it best splits the
+// raw, unsmoothed values, within the smoothing window of where it was found
+func refine(values, weights []float64, warmUp *Interval, ivs []Interval, coolDown *Interval) {
+	p := newSums(values, weights)
+	all := make([]*Interval, 0, len(ivs)+2)
+	if warmUp.End > warmUp.Start {
+		all = append(all, warmUp)
+	}
+	for i := range ivs {
+		all = append(all, &ivs[i])
+	}
+	if coolDown.End > coolDown.Start {
+		all = append(all, coolDown)
+	}
+	for i := 1; i < len(all); i++ {
+		prev, next := all[i-1], all[i]
+		best, at := math.Inf(1), next.Start
+		for k := max(prev.Start+1, next.Start-smoothSeconds); k <= min(next.End-1, next.Start+smoothSeconds); k++ {
+			if c := p.cost(prev.Start, k) + p.cost(k, next.End); c < best {
+				best, at = c, k
+			}
+		}
+		prev.End, next.Start = at, at
+	}
+}
+
+// signal picks the stream intervals are found in: power for rides that have
+// it, otherwise speed
+func signal(st *streams.Streams, activityType string) (string, []float64) {
+	n := len(st.Time)
+	switch thresholds.Sport(activityType) {
+	case thresholds.SportRide:
+		if len(st.Watts) == n {
+			return MetricPower, st.Watts
+		}
+		if len(st.Velocity) == n {
+			return MetricSpeed, st.Velocity
+		}
+	case thresholds.SportRun:
+		if len(st.Velocity) == n {
+			return MetricPace, st.Velocity
+		}
+	}
+	return "", nil
+}
+
+// sampleWeights credits each sample with the seconds since the one before
+func sampleWeights(time []int) []float64 {
+	weights := make([]float64, len(time))
+	for i := 1; i < len(time); i++ {
+		weights[i] = float64(max(min(time[i]-time[i-1], maxSampleGap), 0))
+	}
+	return weights
+}
+
+// smooth applies a centred moving average of smoothSeconds
+func smooth(time []int, values []float64) []float64 {
+	out := make([]float64, len(values))
+	lo, hi := 0, 0
+	var total float64
+	for i := range values {
+		for hi < len(values) && time[hi] <= time[i]+smoothSeconds/2 {
+			total += values[hi]
+			hi++
+		}
+		for time[lo] < time[i]-smoothSeconds/2 {
+			total -= values[lo]
+			lo++
+		}
+		out[i] = total / float64(hi-lo)
+	}
+	return out
+}
+
+// sums holds prefix sums of time-weighted values, for the squared error of
+// any range in constant time
+type sums struct {
+	w, wx, wxx []float64
+}
+
+func newSums(values, weights []float64) sums {
+	n := len(values)
+	p := sums{make([]float64, n+1), make([]float64, n+1), make([]float64, n+1)}
+	for i, v := range values {
+		p.w[i+1] = p.w[i] + weights[i]
+		p.wx[i+1] = p.wx[i] + weights[i]*v
+		p.wxx[i+1] = p.wxx[i] + weights[i]*v*v
+	}
+	return p
+}
+
+// cost is the time-weighted squared error of samples [a, b) about their mean
+func (p sums) cost(a, b int) float64 {
+	tw := p.w[b] - p.w[a]
+	if tw == 0 {
+		return 0
+	}
+	tx := p.wx[b] - p.wx[a]
+	return p.wxx[b] - p.wxx[a] - tx*tx/tw
+}
+
+// changePoints splits values into segments by binary segmentation, splitting
+// wherever it reduces the time-weighted squared error by more than penalty
+func changePoints(values, weights []float64, penalty float64) [][2]int {
+	p := newSums(values, weights)
+	w, cost := p.w, p.cost
+	n := len(values)
+
+	var segments [][2]int
+	pending := [][2]int{{0, n}}
+	for len(pending) > 0 {
+		seg := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		a, b := seg[0], seg[1]
+
+		best, split := penalty, -1
+		if len(segments)+len(pending) < maxSegments && w[b]-w[a] >= 2*minSegment {
+			whole := cost(a, b)
+			for k := a + 1; k < b; k++ {
+				if w[k]-w[a] < minSegment || w[b]-w[k] < minSegment {
+					continue
+				}
+				if gain := whole - cost(a, k) - cost(k, b); gain > best {
+					best, split = gain, k
+				}
+			}
+		}
+		if split < 0 {
+			segments = append(segments, seg)
+			continue
+		}
+		pending = append(pending, [2]int{split, b}, [2]int{a, split})
+	}
+	slices.SortFunc(segments, func(x, y [2]int) int { return x[0] - y[0] })
+	return segments
+}
+
+// twoMeans clusters duration-weighted segment means into a low and a high
+// group and returns the two group means
+func twoMeans(means, durations []float64) (float64, float64) {
+	low, high := slices.Min(means), slices.Max(means)
+	for range 20 {
+		cut := (low + high) / 2
+		var lw, lx, hw, hx float64
+		for i, m := range means {
+			if m > cut {
+				hw += durations[i]
+				hx += durations[i] * m
+			} else {
+				lw += durations[i]
+				lx += durations[i] * m
+			}
+		}
+		if lw == 0 || hw == 0 {
+			break
+		}
+		low, high = lx/lw, hx/hw
+	}
+	return low, high
+}
+
+// measure fills in an interval's duration, distance and averages
+func measure(st *streams.Streams, weights []float64, iv Interval) Interval {
+	if iv.End <= iv.Start {
+		return iv
+	}
+	iv.Duration = sum(weights, iv.Start, iv.End)
+	if len(st.Distance) == len(weights) {
+		// A sample's weight covers the time since the one before it
+		iv.Distance = st.Distance[iv.End-1] - st.Distance[max(iv.Start-1, 0)]
+	}
+	if iv.Distance > 0 && iv.Duration > 0 {
+		iv.Speed = iv.Distance / iv.Duration
+	} else if len(st.Velocity) == len(weights) {
+		iv.Speed = weightedMean(st.Velocity, weights, iv.Start, iv.End)
+	}
+	if len(st.Watts) == len(weights) {
+		iv.Watts = weightedMean(st.Watts, weights, iv.Start, iv.End)
+	}
+	if len(st.Heartrate) == len(weights) {
+		iv.Heartrate = weightedMean(st.Heartrate, weights, iv.Start, iv.End)
+	}
+	return iv
+}
+
+func sum(values []float64, a, b int) float64 {
+	var total float64
+	for _, v := range values[a:b] {
+		total += v
+	}
+	return total
+}
+
+func weightedMean(values, weights []float64, a, b int) float64 {
+	var tw, tx float64
+	for i := a; i < b; i++ {
+		tw += weights[i]
+		tx += weights[i] * values[i]
+	}
+	if tw == 0 {
+		return 0
+	}
+	return tx / tw
+}
+
+// Summary condenses a structure's work intervals into the shorthand coaches
+// write, like 6×800m with 90s recoveries
+type Summary struct {
+	Reps int
+	// Label is the work, e.g. "6×800m", "5×3min", "400m-800m-400m" or
+	// "20min"; sessions with the same label have the same structure
+	Label string
+	// Recovery is the typical recovery time, e.g. "90s"; empty for a single
+	// work interval
+	Recovery  string
+	Rest      bool // recoveries are standing or walking rather than jogging
+	Speed     float64
+	Watts     float64
+	Heartrate float64
+}
+
+// Summarize labels a structure's work and recoveries. Reps are measured by
+// distance or time, whichever they were more consistent in; power reps are
+// always timed.
+func Summarize(s Structure) Summary {
+	work := s.Work()
+	out := Summary{Reps: len(work)}
+
+	var duration, distance, speed, watts, hr float64
+	for _, iv := range work {
+		duration += iv.Duration
+		distance += iv.Distance
+		speed += iv.Speed * iv.Duration
+		watts += iv.Watts * iv.Duration
+		hr += iv.Heartrate * iv.Duration
+	}
+	if duration > 0 {
+		out.Speed = speed / duration
+		if distance > 0 {
+			out.Speed = distance / duration
+		}
+		out.Watts = watts / duration
+		out.Heartrate = hr / duration
+	}
+
+	timed := s.Metric == MetricPower
+	sizes := repSizes(work, timed)
+	if len(sizes) == 1 {
+		out.Label = sizes[0]
+	} else if slices.Equal(sizes, slices.Repeat(sizes[:1], len(sizes))) {
+		out.Label = fmt.Sprintf("%d×%s", len(sizes), sizes[0])
+	} else if len(sizes) <= 8 {
+		out.Label = strings.Join(sizes, "-")
+	} else {
+		out.Label = fmt.Sprintf("%d reps", len(sizes))
+	}
+
+	recoveries := s.Recoveries()
+	if len(recoveries) > 0 {
+		sizes := repSizes(recoveries, true)
+		out.Recovery = typical(sizes)
+		var rs, rd float64
+		for _, iv := range recoveries {
+			rs += iv.Speed * iv.Duration
+			rd += iv.Duration
+		}
+		out.Rest = rd > 0 && rs/rd < restSpeed
+	}
+	return out
+}
+
+// sameSize is the variation under which reps are taken to be the same size
+// and labelled from their average, so boundary noise doesn't split them
+// across a rounding step
+const sameSize = 0.07
+
+// repSizes labels each interval by its rounded distance or duration,
+// whichever the reps sit closer to round values of
+func repSizes(ivs []Interval, timed bool) []string {
+	distances := make([]float64, len(ivs))
+	durations := make([]float64, len(ivs))
+	for i, iv := range ivs {
+		distances[i] = iv.Distance
+		durations[i] = iv.Duration
+	}
+
+	values, round, format := durations, roundTime, formatTime
+	if !timed && slices.Min(distances) > 0 && fit(distances, roundDistance) <= fit(durations, roundTime) {
+		values, round, format = distances, roundDistance, formatDistance
+	}
+
+	sizes := make([]string, len(ivs))
+	if variation(values) <= sameSize {
+		var total float64
+		for _, v := range values {
+			total += v
+		}
+		label := format(round(total / float64(len(values))))
+		for i := range sizes {
+			sizes[i] = label
+		}
+		return sizes
+	}
+	for i, v := range values {
+		sizes[i] = format(round(v))
+	}
+	return sizes
+}
+
+// fit scores how well values suit a measure: their variation plus how far,
+// relatively, they sit from round values
+func fit(values []float64, round func(float64) float64) float64 {
+	var residual float64
+	for _, v := range values {
+		residual += math.Abs(v-round(v)) / v
+	}
+	return variation(values) + residual/float64(len(values))
+}
+
+// typical returns the most common label, the first on a tie
+func typical(labels []string) string {
+	counts := map[string]int{}
+	for _, l := range labels {
+		counts[l]++
+	}
+	best := labels[0]
+	for _, l := range labels {
+		if counts[l] > counts[best] {
+			best = l
+		}
+	}
+	return best
+}
+
+// variation is the coefficient of variation
+func variation(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	mean := total / float64(len(values))
+	if mean == 0 {
+		return math.Inf(1)
+	}
+	var ss float64
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(ss/float64(len(values))) / mean
+}
+
+// roundDistance rounds a rep to the distances track sessions are set in:
+// 50m steps to 400m, 100m to 2km, then half kilometers
+func roundDistance(meters float64) float64 {
+	step := 500.0
+	switch {
+	case meters < 400:
+		step = 50
+	case meters < 2000:
+		step = 100
+	}
+	return max(math.Round(meters/step)*step, step)
+}
+
+// formatDistance prints a rounded distance, e.g. "800m", "1km" or "2.5km"
+func formatDistance(meters float64) string {
+	switch {
+	case math.Mod(meters, 1000) == 0:
+		return fmt.Sprintf("%.0fkm", meters/1000)
+	case meters < 2000:
+		return fmt.Sprintf("%.0fm", meters)
+	}
+	return fmt.Sprintf("%.1fkm", meters/1000)
+}
+
+// roundTime rounds a rep to 15 seconds under two minutes and 30 seconds above
+func roundTime(seconds float64) float64 {
+	if seconds < 120 {
+		return max(math.Round(seconds/15)*15, 15)
+	}
+	return math.Round(seconds/30) * 30
+}
+
+// formatTime prints a rounded duration, e.g. "90s", "3min" or "2min30s"
+func formatTime(seconds float64) string {
+	if seconds < 120 {
+		return fmt.Sprintf("%.0fs", seconds)
+	}
+	rounded := int(seconds)
+	if rounded%60 == 0 {
+		return fmt.Sprintf("%dmin", rounded/60)
+	}
+	return fmt.Sprintf("%dmin%ds", rounded/60, rounded%60)
+}
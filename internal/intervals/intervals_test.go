@@ -0,0 +1,185 @@
+package intervals
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+// block is a stretch of a synthetic session at a steady speed or power
+type block struct {
+	seconds int
+	value   float64
+	hr      float64
+}
+
+// session builds one-second streams from blocks, with GPS-like noise on the
+// speed or power
+func session(blocks []block, power bool) *streams.Streams {
+	rng := rand.New(rand.NewPCG(1, 2))
+	st := &streams.Streams{}
+	var t int
+	var distance float64
+	for _, b := range blocks {
+		for range b.seconds {
+			v := b.value * (1 + rng.NormFloat64()*0.04)
+			st.Time = append(st.Time, t)
+			st.Heartrate = append(st.Heartrate, b.hr)
+			if power {
+				st.Watts = append(st.Watts, v)
+				st.Velocity = append(st.Velocity, 9)
+				distance += 9
+			} else {
+				st.Velocity = append(st.Velocity, v)
+				distance += v
+			}
+			st.Distance = append(st.Distance, distance)
+			t++
+		}
+	}
+	return st
+}
+
+// repeats alternates work and recovery blocks, without a final recovery
+func repeats(n int, work, recovery block) []block {
+	var out []block
+	for i := range n {
+		out = append(out, work)
+		if i < n-1 {
+			out = append(out, recovery)
+		}
+	}
+	return out
+}
+
+func TestDetectTrackSession(t *testing.T) {
+	// 6×800m at 3:25/km (164s) with 90s jog, between 10 minute warm-up and
+	// cool-down
+	blocks := []block{{600, 3.0, 140}}
+	blocks = append(blocks, repeats(6, block{164, 800.0 / 164, 172}, block{90, 2.6, 150})...)
+	blocks = append(blocks, block{600, 3.0, 145})
+
+	s, ok := Detect(session(blocks, false), "Run")
+	if !ok {
+		t.Fatal("expected a structure")
+	}
+	if s.Metric != MetricPace {
+		t.Errorf("expected pace, got %s", s.Metric)
+	}
+	if got := len(s.Work()); got != 6 {
+		t.Fatalf("expected 6 reps, got %d: %+v", got, s.Intervals)
+	}
+	if got := len(s.Recoveries()); got != 5 {
+		t.Errorf("expected 5 recoveries, got %d", got)
+	}
+	if math.Abs(s.WarmUp.Duration-600) > 15 || math.Abs(s.CoolDown.Duration-600) > 15 {
+		t.Errorf("expected 10 minute warm-up and cool-down, got %.0fs and %.0fs", s.WarmUp.Duration, s.CoolDown.Duration)
+	}
+
+	sum := Summarize(s)
+	if sum.Label != "6×800m" || sum.Recovery != "90s" || sum.Rest {
+		t.Errorf("expected 6×800m with 90s jog, got %+v", sum)
+	}
+	if math.Abs(sum.Speed-800.0/164) > 0.1 {
+		t.Errorf("expected work speed ~%.2f, got %.2f", 800.0/164, sum.Speed)
+	}
+	if math.Abs(sum.Heartrate-172) > 3 {
+		t.Errorf("expected work heart rate ~172, got %.0f", sum.Heartrate)
+	}
+}
+
+func TestDetectPyramid(t *testing.T) {
+	// 400m-800m-1200m-800m-400m at 4.5 m/s with 2 minute standing rests
+	var blocks []block
+	for i, meters := range []float64{400, 800, 1200, 800, 400} {
+		if i > 0 {
+			blocks = append(blocks, block{120, 0.3, 130})
+		}
+		blocks = append(blocks, block{int(meters / 4.5), 4.5, 175})
+	}
+
+	s, ok := Detect(session(blocks, false), "Run")
+	if !ok {
+		t.Fatal("expected a structure")
+	}
+	sum := Summarize(s)
+	if sum.Label != "400m-800m-1200m-800m-400m" || sum.Recovery != "2min" || !sum.Rest {
+		t.Errorf("expected a pyramid with 2min rests, got %+v", sum)
+	}
+}
+
+func TestDetectPowerIntervals(t *testing.T) {
+	// 5×4min at 320W with 3 minutes easy, on a ride with constant speed
+	blocks := []block{{900, 180, 130}}
+	blocks = append(blocks, repeats(5, block{240, 320, 165}, block{180, 150, 135})...)
+
+	s, ok := Detect(session(blocks, true), "VirtualRide")
+	if !ok {
+		t.Fatal("expected a structure")
+	}
+	if s.Metric != MetricPower {
+		t.Errorf("expected power, got %s", s.Metric)
+	}
+	sum := Summarize(s)
+	if sum.Label != "5×4min" || sum.Recovery != "3min" {
+		t.Errorf("expected 5×4min with 3min recoveries, got %+v", sum)
+	}
+	if math.Abs(sum.Watts-320) > 10 {
+		t.Errorf("expected ~320W, got %.0f", sum.Watts)
+	}
+}
+
+func TestDetectTempo(t *testing.T) {
+	s, ok := Detect(session([]block{{600, 3.0, 140}, {1200, 4.2, 165}, {600, 3.0, 145}}, false), "Run")
+	if !ok {
+		t.Fatal("expected a structure")
+	}
+	sum := Summarize(s)
+	if sum.Reps != 1 || sum.Label != "20min" || sum.Recovery != "" {
+		t.Errorf("expected a single 20min tempo, got %+v", sum)
+	}
+}
+
+func TestDetectSteady(t *testing.T) {
+	// Drifting a little with the terrain isn't structure
+	blocks := []block{{900, 3.2, 145}, {600, 3.0, 150}, {900, 3.4, 148}}
+	if s, ok := Detect(session(blocks, false), "Run"); ok {
+		t.Errorf("expected no structure, got %+v", s)
+	}
+	if _, ok := Detect(session(blocks, false), "Swim"); ok {
+		t.Error("expected swims to be unsupported")
+	}
+	if _, ok := Detect(&streams.Streams{}, "Run"); ok {
+		t.Error("expected no structure without streams")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	distances := map[float64]string{
+		195:  "200m",
+		812:  "800m",
+		1004: "1km",
+		1590: "1600m",
+		2470: "2.5km",
+		5030: "5km",
+	}
+	for meters, want := range distances {
+		if got := formatDistance(roundDistance(meters)); got != want {
+			t.Errorf("distance %v = %s, want %s", meters, got, want)
+		}
+	}
+
+	times := map[float64]string{
+		28:  "30s",
+		92:  "90s",
+		179: "3min",
+		155: "2min30s",
+	}
+	for seconds, want := range times {
+		if got := formatTime(roundTime(seconds)); got != want {
+			t.Errorf("time %v = %s, want %s", seconds, got, want)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+// Package quality flags activities whose data can't be trusted: speeds no
+// athlete could hold for the activity type (a drive saved as a run), GPS
+// jumps, outdoor activities without distance, heart rate dropouts and
+// duplicate uploads. Activities with an error flag are left out of records
+// and progress; warnings are only reported.
+package quality
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+// Flag codes
+const (
+	CodeImplausibleSpeed     = "implausible_speed"
+	CodeGPSSpike             = "gps_spike"
+	CodeZeroDistance         = "zero_distance"
+	CodeHRDropout            = "hr_dropout"
+	CodeImplausibleHeartrate = "implausible_heartrate"
+	CodeDuplicate            = "duplicate"
+)
+
+// Codes lists every flag code
+var Codes = []string{CodeImplausibleSpeed, CodeGPSSpike, CodeZeroDistance, CodeHRDropout, CodeImplausibleHeartrate, CodeDuplicate}
+
+// Severities. An error leaves the activity out of records and progress.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// minSpeedCheckSeconds is the shortest activity whose average speed is judged
+const minSpeedCheckSeconds = 60
+
+// GPS jumps: a sample further than minSpikeMeters from the one before, at a
+// speed beyond the type's maximum. Jumps adding more than spikeShare of the
+// distance are errors.
+const (
+	minSpikeMeters = 50
+	spikeSpeed     = 50.0 // m/s, for types without a maximum
+	spikeShare     = 0.05
+)
+
+// minZeroDistanceSeconds is how long an activity without distance must be
+// before it's flagged, so that a few seconds of accidental recording aren't
+const minZeroDistanceSeconds = 300
+
+// Heart rate: zero readings, or the same reading for flatlineSeconds or more
+// (a stuck strap), count as dropouts. Dropouts are flagged past dropoutShare
+// of the activity and minDropoutSeconds, or past maxDropoutSeconds.
+const (
+	flatlineSeconds   = 120
+	dropoutShare      = 0.10
+	minDropoutSeconds = 60
+	maxDropoutSeconds = 300
+	maxHeartrate      = 230 // bpm
+)
+
+// Duplicates start within duplicateWindow of each other with distances (or
+// moving times, without distance) within duplicateTolerance
+const (
+	duplicateWindow    = 10 * time.Minute
+	duplicateTolerance = 0.05
+)
+
+// pageSize is how many activities are read at a time
+const pageSize = 200
+
+// limits are the fastest plausible average and maximum speeds for a type
+type limits struct {
+	average float64 // m/s
+	max     float64 // m/s, zero when not checked
+}
+
+// speedLimits returns the limits for an activity type, if it has any
+func speedLimits(activityType string) (limits, bool) {
+	switch activityType {
+	case "Run", "TrailRun", "VirtualRun":
+		// Faster than world record marathon pace held for the whole run, or
+		// a top speed beyond an elite sprinter's
+		return limits{average: 6.5, max: 12.5}, true
+	case "Walk", "Hike":
+		return limits{average: 3.0, max: 8.0}, true
+	case "Ride", "VirtualRide", "GravelRide", "MountainBikeRide", "EBikeRide", "EMountainBikeRide":
+		return limits{average: 20.0, max: 33.0}, true
+	case "Swim":
+		return limits{average: 2.5}, true
+	}
+	return limits{}, false
+}
+
+// outdoorDistance reports whether an activity type normally records distance
+func outdoorDistance(activityType string) bool {
+	switch activityType {
+	case "Run", "TrailRun", "Walk", "Hike", "Ride", "GravelRide", "MountainBikeRide",
+		"EBikeRide", "EMountainBikeRide", "Swim", "NordicSki", "AlpineSki", "BackcountrySki",
+		"Rowing", "Kayaking", "Canoeing", "InlineSkate", "RollerSki":
+		return true
+	}
+	return false
+}
+
+// Activity is the summary of an activity being checked
+type Activity struct {
+	ID           int64
+	Type         string
+	Distance     float64 // meters
+	MovingTime   int64   // seconds
+	Start        time.Time
+	AverageSpeed float64 // m/s
+	MaxSpeed     float64 // m/s
+	MaxHeartrate float64 // bpm
+}
+
+// Candidate is another activity of the same type started around the same time
+type Candidate struct {
+	ID         int64
+	Distance   float64
+	MovingTime int64
+	Start      time.Time
+}
+
+// Flag is a problem found in an activity
+type Flag struct {
+	Code     string
+	Severity string
+	Detail   string
+	// RelatedActivityID is the original of a duplicate
+	RelatedActivityID int64
+}
+
+// Check looks for problems in an activity, its streams (nil without) and
+// other activities of its type started within duplicateWindow
+func Check(a Activity, st *streams.Streams, others []Candidate) []Flag {
+	var flags []Flag
+	if f, ok := checkSpeed(a); ok {
+		flags = append(flags, f)
+	}
+	if f, ok := checkGPS(a, st); ok {
+		flags = append(flags, f)
+	}
+	if f, ok := checkDistance(a); ok {
+		flags = append(flags, f)
+	}
+	if f, ok := checkHeartrate(a, st); ok {
+		flags = append(flags, f)
+	}
+	if f, ok := checkDuplicate(a, others); ok {
+		flags = append(flags, f)
+	}
+	return flags
+}
+
+// checkSpeed flags an average speed beyond what the type allows
+func checkSpeed(a Activity) (Flag, bool) {
+	l, ok := speedLimits(a.Type)
+	if !ok || a.MovingTime < minSpeedCheckSeconds || a.AverageSpeed <= l.average {
+		return Flag{}, false
+	}
+	return Flag{
+		Code:     CodeImplausibleSpeed,
+		Severity: SeverityError,
+		Detail: fmt.Sprintf("average speed %.1f km/h is implausible for a %s (limit %.1f km/h); recorded in a vehicle or the wrong type?",
+			a.AverageSpeed*3.6, a.Type, l.average*3.6),
+	}, true
+}
+
+// checkGPS looks for jumps in the GPS track, or without one, a maximum speed
+// beyond what the type allows
+func checkGPS(a Activity, st *streams.Streams) (Flag, bool) {
+	l, _ := speedLimits(a.Type)
+	if st == nil || len(st.LatLng) < 2 || len(st.Time) != len(st.LatLng) {
+		if l.max > 0 && a.MaxSpeed > l.max {
+			return Flag{
+				Code:     CodeGPSSpike,
+				Severity: SeverityWarning,
+				Detail:   fmt.Sprintf("maximum speed %.1f km/h is implausible for a %s; likely a GPS jump", a.MaxSpeed*3.6, a.Type),
+			}, true
+		}
+		return Flag{}, false
+	}
+
+	limit := l.max
+	if limit == 0 {
+		limit = spikeSpeed
+	}
+	points := st.Points()
+	var jumps int
+	var added, largest float64
+	for i := 1; i < len(points); i++ {
+		d := geo.Haversine(points[i-1], points[i])
+		if d < minSpikeMeters {
+			continue
+		}
+		dt := float64(st.Time[i] - st.Time[i-1])
+		if dt > 0 && d/dt <= limit {
+			continue
+		}
+		jumps++
+		added += d
+		largest = math.Max(largest, d)
+	}
+	if jumps == 0 {
+		return Flag{}, false
+	}
+
+	f := Flag{Code: CodeGPSSpike, Severity: SeverityWarning}
+	share := 0.0
+	if a.Distance > 0 {
+		share = added / a.Distance
+	}
+	if share > spikeShare {
+		f.Severity = SeverityError
+	}
+	f.Detail = fmt.Sprintf("%d GPS %s of up to %.0f m adding %.2f km (%.0f%% of the distance)",
+		jumps, plural(jumps, "jump", "jumps"), largest, added/1000, share*100)
+	return f, true
+}
+
+// checkDistance flags an outdoor activity of some length without distance
+func checkDistance(a Activity) (Flag, bool) {
+	if a.Distance > 0 || a.MovingTime < minZeroDistanceSeconds || !outdoorDistance(a.Type) {
+		return Flag{}, false
+	}
+	return Flag{
+		Code:     CodeZeroDistance,
+		Severity: SeverityWarning,
+		Detail:   fmt.Sprintf("%s of %d minutes recorded no distance; GPS off, or indoors without a sensor?", a.Type, a.MovingTime/60),
+	}, true
+}
+
+// checkHeartrate looks for heart rate dropouts in the stream, or without
+// one, a maximum no heart reaches
+func checkHeartrate(a Activity, st *streams.Streams) (Flag, bool) {
+	if st == nil || len(st.Heartrate) < 2 || len(st.Time) != len(st.Heartrate) {
+		if a.MaxHeartrate > maxHeartrate {
+			return Flag{
+				Code:     CodeImplausibleHeartrate,
+				Severity: SeverityWarning,
+				Detail:   fmt.Sprintf("maximum heart rate %.0f bpm is implausible; likely interference or cadence lock", a.MaxHeartrate),
+			}, true
+		}
+		return Flag{}, false
+	}
+
+	total := float64(st.Time[len(st.Time)-1] - st.Time[0])
+	if total <= 0 {
+		return Flag{}, false
+	}
+	var dropout float64
+	for i := 1; i < len(st.Heartrate); i++ {
+		if st.Heartrate[i] <= 0 {
+			dropout += float64(st.Time[i] - st.Time[i-1])
+		}
+	}
+	// Stretches of the same reading
+	for i := 0; i < len(st.Heartrate); {
+		j := i
+		for j+1 < len(st.Heartrate) && st.Heartrate[j+1] == st.Heartrate[i] {
+			j++
+		}
+		if d := float64(st.Time[j] - st.Time[i]); st.Heartrate[i] > 0 && d >= flatlineSeconds {
+			dropout += d
+		}
+		i = j + 1
+	}
+
+	share := dropout / total
+	if dropout < maxDropoutSeconds && (share < dropoutShare || dropout < minDropoutSeconds) {
+		return Flag{}, false
+	}
+	return Flag{
+		Code:     CodeHRDropout,
+		Severity: SeverityWarning,
+		Detail: fmt.Sprintf("heart rate missing or stuck for %dm %02ds (%.0f%% of the activity); zones and heart rate averages may be off",
+			int(dropout)/60, int(dropout)%60, share*100),
+	}, true
+}
+
+// checkDuplicate flags an activity uploaded again: the later upload, with
+// the higher ID, is the duplicate
+func checkDuplicate(a Activity, others []Candidate) (Flag, bool) {
+	for _, o := range others {
+		if o.ID >= a.ID {
+			continue
+		}
+		gap := a.Start.Sub(o.Start)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap > duplicateWindow {
+			continue
+		}
+		var same bool
+		if a.Distance > 0 || o.Distance > 0 {
+			same = similar(a.Distance, o.Distance)
+		} else {
+			same = similar(float64(a.MovingTime), float64(o.MovingTime))
+		}
+		if !same {
+			continue
+		}
+		return Flag{
+			Code:              CodeDuplicate,
+			Severity:          SeverityError,
+			Detail:            fmt.Sprintf("same start (%d min apart) and distance as activity %d; uploaded twice?", int(gap.Minutes()), o.ID),
+			RelatedActivityID: o.ID,
+		}, true
+	}
+	return Flag{}, false
+}
+
+// similar reports whether a and b are within duplicateTolerance of the larger
+func similar(a, b float64) bool {
+	larger := math.Max(a, b)
+	return larger <= 0 || math.Abs(a-b) <= larger*duplicateTolerance
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// Store is what checking activities needs from the database
+type Store interface {
+	ListActivitiesNeedingQualityCheck(ctx context.Context, arg db.ListActivitiesNeedingQualityCheckParams) ([]db.ListActivitiesNeedingQualityCheckRow, error)
+	ListActivitiesStartedBetween(ctx context.Context, arg db.ListActivitiesStartedBetweenParams) ([]db.ListActivitiesStartedBetweenRow, error)
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+	DeleteActivityFlags(ctx context.Context, activityID int64) error
+	CreateActivityFlag(ctx context.Context, arg db.CreateActivityFlagParams) error
+	MarkActivityQualityChecked(ctx context.Context, activityID int64) error
+}
+
+// Result counts the activities checked and how many were flagged
+type Result struct {
+	Checked int
+	Flagged int
+}
+
+// Run checks activities that are new, updated or have new streams since they
+// were last checked, replacing their flags
+func Run(ctx context.Context, store Store) (Result, error) {
+	var result Result
+	var lastID int64
+	for {
+		rows, err := store.ListActivitiesNeedingQualityCheck(ctx, db.ListActivitiesNeedingQualityCheckParams{ID: lastID, Limit: pageSize})
+		if err != nil {
+			return result, fmt.Errorf("listing activities needing quality checks: %w", err)
+		}
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			flags, err := checkActivity(ctx, store, row)
+			if err != nil {
+				return result, err
+			}
+			result.Checked++
+			if len(flags) > 0 {
+				result.Flagged++
+			}
+			lastID = row.ID
+		}
+		if len(rows) < pageSize {
+			return result, nil
+		}
+	}
+}
+
+func checkActivity(ctx context.Context, store Store, row db.ListActivitiesNeedingQualityCheckRow) ([]Flag, error) {
+	a := Activity{
+		ID:           row.ID,
+		Type:         row.Type.String,
+		Distance:     row.Distance.Float64,
+		MovingTime:   row.MovingTime.Int64,
+		Start:        row.StartDate.Time,
+		AverageSpeed: row.AverageSpeed.Float64,
+		MaxSpeed:     row.MaxSpeed.Float64,
+		MaxHeartrate: row.MaxHeartrate.Float64,
+	}
+
+	var st *streams.Streams
+	streamRow, err := store.GetActivityStreams(ctx, row.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("loading streams for activity %d: %w", row.ID, err)
+	}
+	if err == nil {
+		st, err = streams.FromRow(streamRow)
+		if err != nil {
+			logging.Warn("Skipping unreadable activity streams", "activity_id", row.ID, "error", err)
+			st = nil
+		}
+	}
+
+	var others []Candidate
+	if row.StartDate.Valid && row.Type.Valid {
+		rows, err := store.ListActivitiesStartedBetween(ctx, db.ListActivitiesStartedBetweenParams{
+			Type:        row.Type,
+			StartDate:   sql.NullTime{Time: a.Start.Add(-duplicateWindow), Valid: true},
+			StartDate_2: sql.NullTime{Time: a.Start.Add(duplicateWindow), Valid: true},
+			ID:          row.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("finding possible duplicates of activity %d: %w", row.ID, err)
+		}
+		for _, r := range rows {
+			others = append(others, Candidate{
+				ID:         r.ID,
+				Distance:   r.Distance.Float64,
+				MovingTime: r.MovingTime.Int64,
+				Start:      r.StartDate.Time,
+			})
+		}
+	}
+
+	flags := Check(a, st, others)
+	if err := store.DeleteActivityFlags(ctx, row.ID); err != nil {
+		return nil, fmt.Errorf("clearing flags for activity %d: %w", row.ID, err)
+	}
+	for _, f := range flags {
+		err := store.CreateActivityFlag(ctx, db.CreateActivityFlagParams{
+			ActivityID:        row.ID,
+			Code:              f.Code,
+			Severity:          f.Severity,
+			Detail:            f.Detail,
+			RelatedActivityID: sql.NullInt64{Int64: f.RelatedActivityID, Valid: f.RelatedActivityID != 0},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("saving %s flag for activity %d: %w", f.Code, row.ID, err)
+		}
+	}
+	if err := store.MarkActivityQualityChecked(ctx, row.ID); err != nil {
+		return nil, fmt.Errorf("marking activity %d checked: %w", row.ID, err)
+	}
+	return flags, nil
+}
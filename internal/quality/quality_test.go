@@ -0,0 +1,164 @@
+package quality
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+var start = time.Date(2026, 5, 3, 7, 0, 0, 0, time.UTC)
+
+// run is a plausible 10 km run in 50 minutes
+func run() Activity {
+	return Activity{ID: 10, Type: "Run", Distance: 10000, MovingTime: 3000, Start: start, AverageSpeed: 3.33, MaxSpeed: 5, MaxHeartrate: 182}
+}
+
+// track is a run heading north at 3.33 m/s, one sample a second, with
+// heart rate from hr
+func track(seconds int, hr func(i int) float64) *streams.Streams {
+	st := &streams.Streams{}
+	for i := range seconds {
+		st.Time = append(st.Time, i)
+		st.LatLng = append(st.LatLng, [2]float64{51.5 + float64(i)*3.33/111320, -0.1})
+		st.Heartrate = append(st.Heartrate, hr(i))
+	}
+	return st
+}
+
+func steady(i int) float64 { return 150 + float64(i%7) }
+
+func codes(flags []Flag) string {
+	var out []string
+	for _, f := range flags {
+		out = append(out, f.Code+":"+f.Severity)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestCheckClean(t *testing.T) {
+	if flags := Check(run(), track(3000, steady), nil); len(flags) != 0 {
+		t.Errorf("expected a clean run, got %s", codes(flags))
+	}
+}
+
+func TestCheckSpeed(t *testing.T) {
+	a := run()
+	a.AverageSpeed = 16.7 // 60 km/h
+	flags := Check(a, nil, nil)
+	if codes(flags) != "implausible_speed:error" || !strings.Contains(flags[0].Detail, "60.1 km/h") {
+		t.Errorf("expected a 60 km/h run to be an error, got %+v", flags)
+	}
+
+	// The same speed is fine on a bike
+	a.Type = "Ride"
+	a.MaxSpeed = 20
+	if flags := Check(a, nil, nil); len(flags) != 0 {
+		t.Errorf("expected a 60 km/h ride to be fine, got %s", codes(flags))
+	}
+}
+
+func TestCheckGPS(t *testing.T) {
+	// One sample thrown 100 m east and back
+	st := track(3000, steady)
+	st.LatLng[1000][1] += 100 / 69400.0
+	flags := Check(run(), st, nil)
+	if codes(flags) != "gps_spike:warning" || !strings.Contains(flags[0].Detail, "2 GPS jumps") {
+		t.Errorf("expected a small jump there and back as a warning, got %+v", flags)
+	}
+
+	// Jumps making up a tenth of the distance are an error
+	for _, i := range []int{500, 1500, 2500} {
+		st.LatLng[i][1] += 400 / 69400.0
+	}
+	if flags := Check(run(), st, nil); codes(flags) != "gps_spike:error" {
+		t.Errorf("expected an error, got %+v", flags)
+	}
+
+	// Without streams, the maximum speed is all there is to go on
+	a := run()
+	a.MaxSpeed = 20
+	if flags := Check(a, nil, nil); codes(flags) != "gps_spike:warning" {
+		t.Errorf("expected a 72 km/h top speed to be flagged, got %+v", flags)
+	}
+}
+
+func TestCheckDistance(t *testing.T) {
+	a := run()
+	a.Distance, a.AverageSpeed = 0, 0
+	if flags := Check(a, nil, nil); codes(flags) != "zero_distance:warning" {
+		t.Errorf("expected a run without distance to be flagged, got %+v", flags)
+	}
+
+	a.Type = "WeightTraining"
+	if flags := Check(a, nil, nil); len(flags) != 0 {
+		t.Errorf("expected weight training without distance to be fine, got %s", codes(flags))
+	}
+}
+
+func TestCheckHeartrate(t *testing.T) {
+	// The strap drops out for six minutes
+	dropped := func(i int) float64 {
+		if i >= 1200 && i < 1560 {
+			return 0
+		}
+		return steady(i)
+	}
+	flags := Check(run(), track(3000, dropped), nil)
+	if codes(flags) != "hr_dropout:warning" || !strings.Contains(flags[0].Detail, "6m 00s") {
+		t.Errorf("expected a six minute dropout, got %+v", flags)
+	}
+
+	// A strap stuck on one reading for ten minutes
+	stuck := func(i int) float64 {
+		if i >= 600 && i < 1200 {
+			return 148
+		}
+		return steady(i)
+	}
+	if flags := Check(run(), track(3000, stuck), nil); codes(flags) != "hr_dropout:warning" {
+		t.Errorf("expected a stuck reading to be flagged, got %+v", flags)
+	}
+
+	// A short gap is fine
+	brief := func(i int) float64 {
+		if i >= 100 && i < 130 {
+			return 0
+		}
+		return steady(i)
+	}
+	if flags := Check(run(), track(3000, brief), nil); len(flags) != 0 {
+		t.Errorf("expected a 30s gap to be fine, got %s", codes(flags))
+	}
+
+	a := run()
+	a.MaxHeartrate = 251
+	if flags := Check(a, nil, nil); codes(flags) != "implausible_heartrate:warning" {
+		t.Errorf("expected 251 bpm to be flagged, got %+v", flags)
+	}
+}
+
+func TestCheckDuplicate(t *testing.T) {
+	others := []Candidate{
+		{ID: 4, Distance: 10040, MovingTime: 3010, Start: start.Add(-2 * time.Minute)},
+		{ID: 12, Distance: 10000, MovingTime: 3000, Start: start},
+	}
+	flags := Check(run(), nil, others)
+	if codes(flags) != "duplicate:error" || flags[0].RelatedActivityID != 4 {
+		t.Errorf("expected a duplicate of activity 4, got %+v", flags)
+	}
+
+	// The earlier upload is the original
+	a := run()
+	a.ID = 3
+	if flags := Check(a, nil, others); len(flags) != 0 {
+		t.Errorf("expected the original to be fine, got %s", codes(flags))
+	}
+
+	// A different distance is a different run
+	others[0].Distance = 5000
+	if flags := Check(run(), nil, others[:1]); len(flags) != 0 {
+		t.Errorf("expected a 5 km run not to be a duplicate, got %s", codes(flags))
+	}
+}
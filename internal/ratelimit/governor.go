@@ -0,0 +1,249 @@
+// Package ratelimit keeps one Strava rate limit budget for the whole
+// process, saved in the database so a restart remembers what the current
+// window and day have used.
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+)
+
+// Store saves the budget between runs; *db.Queries is one
+type Store interface {
+	GetRateLimitBudget(ctx context.Context) (db.RateLimitBudget, error)
+	UpsertRateLimitBudget(ctx context.Context, arg db.UpsertRateLimitBudgetParams) error
+}
+
+// Limits assumed until Strava reports the app's own: the read limits a new
+// API application gets
+const (
+	defaultWindowLimit = 100
+	defaultDailyLimit  = 1000
+)
+
+// window is Strava's short rate limit window, starting on the quarter hour
+const window = 15 * time.Minute
+
+// SaveInterval is how often Run saves the budget when it has changed. Usage
+// since the last save is lost on a crash, until Strava next reports it.
+const SaveInterval = 30 * time.Second
+
+// Reserves held back from lower priorities. Each class above backfill keeps
+// a tenth of the 15-minute limit (at least 5 requests) to itself, and the
+// last tenth of the daily limit is kept for sync and tool calls, with the
+// final half of it for tool calls alone.
+const (
+	windowReserveShare = 0.1
+	minWindowReserve   = 5
+	dailyReserveShare  = 0.1
+)
+
+// Governor is a token bucket per 15-minute window and per day, refilled when
+// each resets. A request reserves a token from both before it is sent, so
+// clients in different workers cannot jointly overrun a window. The budget
+// is saved by Run, not on each request, so requests never wait on the
+// database.
+type Governor struct {
+	store Store
+	now   func() time.Time
+
+	mu          sync.Mutex
+	dirty       bool // changed since the last save
+	windowStart time.Time
+	windowUsage int
+	windowLimit int
+	dayStart    time.Time
+	dailyUsage  int
+	dailyLimit  int
+}
+
+// New creates a governor, picking up the usage a previous run saved when it
+// is still in the current window or day
+func New(ctx context.Context, store Store) *Governor {
+	g := &Governor{
+		store:       store,
+		now:         time.Now,
+		windowLimit: defaultWindowLimit,
+		dailyLimit:  defaultDailyLimit,
+	}
+	g.roll(g.now())
+
+	saved, err := store.GetRateLimitBudget(ctx)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.Logger.Warn().Err(err).Msg("failed to load rate limit budget, starting empty")
+		}
+		return g
+	}
+	if saved.WindowLimit > 0 {
+		g.windowLimit = int(saved.WindowLimit)
+	}
+	if saved.DailyLimit > 0 {
+		g.dailyLimit = int(saved.DailyLimit)
+	}
+	if saved.WindowStart.Equal(g.windowStart) {
+		g.windowUsage = int(saved.WindowUsage)
+	}
+	if saved.DayStart.Equal(g.dayStart) {
+		g.dailyUsage = int(saved.DailyUsage)
+	}
+	return g
+}
+
+// Reserve takes one request from the budget. Backfill and sync requests wait
+// for the next window when theirs is used up; tool calls get
+// strava.ErrBudgetExhausted straight away rather than hang, as does any
+// class whose share of the day is used up.
+func (g *Governor) Reserve(ctx context.Context, priority strava.Priority) error {
+	for {
+		wait, err := g.tryReserve(priority)
+		if err != nil || wait <= 0 {
+			return err
+		}
+
+		logging.Logger.Info().
+			Str("priority", priority.String()).
+			Dur("wait", wait.Round(time.Second)).
+			Msg("waiting for rate limit budget")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// tryReserve reserves a request if the budget allows, or returns how long
+// to wait for the next window
+func (g *Governor) tryReserve(priority strava.Priority) (time.Duration, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.roll(now)
+
+	if g.dailyUsage >= g.dailyCap(priority) {
+		return 0, fmt.Errorf("%w: %d of %d daily requests used, %s requests resume at midnight UTC",
+			strava.ErrBudgetExhausted, g.dailyUsage, g.dailyLimit, priority)
+	}
+	if g.windowUsage >= g.windowCap(priority) {
+		if priority == strava.PriorityInteractive {
+			return 0, fmt.Errorf("%w: %d of %d requests used in this 15-minute window",
+				strava.ErrBudgetExhausted, g.windowUsage, g.windowLimit)
+		}
+		// A couple of seconds past the boundary, like the client's own waits
+		return g.windowStart.Add(window).Sub(now) + 2*time.Second, nil
+	}
+
+	g.windowUsage++
+	g.dailyUsage++
+	g.dirty = true
+	return 0, nil
+}
+
+// Observe takes the usage and limits Strava reported, which also count
+// requests made outside this process with the same app
+func (g *Governor) Observe(info strava.RateLimitInfo) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.roll(now)
+	if info.Limit15Min > 0 {
+		g.windowLimit = info.Limit15Min
+	}
+	if info.LimitDaily > 0 {
+		g.dailyLimit = info.LimitDaily
+	}
+	g.windowUsage = max(g.windowUsage, info.Usage15Min)
+	g.dailyUsage = max(g.dailyUsage, info.UsageDaily)
+	if info.IsRateLimited {
+		g.windowUsage = max(g.windowUsage, g.windowLimit)
+	}
+	g.dirty = true
+}
+
+// Run saves the budget every SaveInterval while it changes, and once more
+// when ctx is done
+func (g *Governor) Run(ctx context.Context) {
+	ticker := time.NewTicker(SaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			g.Save()
+			return
+		case <-ticker.C:
+			g.Save()
+		}
+	}
+}
+
+// windowCap is how much of the 15-minute window a priority may use
+func (g *Governor) windowCap(priority strava.Priority) int {
+	reserve := max(minWindowReserve, int(float64(g.windowLimit)*windowReserveShare))
+	return g.windowLimit - reserve*int(strava.PriorityInteractive-priority)
+}
+
+// dailyCap is how much of the day a priority may use
+func (g *Governor) dailyCap(priority strava.Priority) int {
+	reserve := int(float64(g.dailyLimit) * dailyReserveShare)
+	switch priority {
+	case strava.PriorityBackfill:
+		return g.dailyLimit - reserve
+	case strava.PrioritySync:
+		return g.dailyLimit - reserve/2
+	}
+	return g.dailyLimit
+}
+
+// roll refills the buckets once their window or day is over
+func (g *Governor) roll(now time.Time) {
+	now = now.UTC()
+	if windowStart := now.Truncate(window); !windowStart.Equal(g.windowStart) {
+		g.windowStart = windowStart
+		g.windowUsage = 0
+	}
+	if dayStart := now.Truncate(24 * time.Hour); !dayStart.Equal(g.dayStart) {
+		g.dayStart = dayStart
+		g.dailyUsage = 0
+	}
+}
+
+// Save writes the budget to the store if it changed since the last save.
+// The write happens outside the lock, so requests can reserve meanwhile. A
+// failure only costs the usage carried over a restart, so it is logged and
+// the budget is saved again next time.
+func (g *Governor) Save() {
+	g.mu.Lock()
+	if !g.dirty {
+		g.mu.Unlock()
+		return
+	}
+	params := db.UpsertRateLimitBudgetParams{
+		WindowStart: g.windowStart,
+		WindowUsage: int64(g.windowUsage),
+		WindowLimit: int64(g.windowLimit),
+		DayStart:    g.dayStart,
+		DailyUsage:  int64(g.dailyUsage),
+		DailyLimit:  int64(g.dailyLimit),
+		UpdatedAt:   g.now().UTC(),
+	}
+	g.dirty = false
+	g.mu.Unlock()
+
+	if err := g.store.UpsertRateLimitBudget(context.Background(), params); err != nil {
+		logging.Logger.Warn().Err(err).Msg("failed to save rate limit budget")
+		g.mu.Lock()
+		g.dirty = true
+		g.mu.Unlock()
+	}
+}
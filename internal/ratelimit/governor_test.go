@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+)
+
+// memoryStore keeps the saved budget in memory
+type memoryStore struct {
+	budget *db.RateLimitBudget
+	saves  int
+}
+
+func (m *memoryStore) GetRateLimitBudget(ctx context.Context) (db.RateLimitBudget, error) {
+	if m.budget == nil {
+		return db.RateLimitBudget{}, sql.ErrNoRows
+	}
+	return *m.budget, nil
+}
+
+func (m *memoryStore) UpsertRateLimitBudget(ctx context.Context, arg db.UpsertRateLimitBudgetParams) error {
+	m.saves++
+	m.budget = &db.RateLimitBudget{
+		ID:          1,
+		WindowStart: arg.WindowStart,
+		WindowUsage: arg.WindowUsage,
+		WindowLimit: arg.WindowLimit,
+		DayStart:    arg.DayStart,
+		DailyUsage:  arg.DailyUsage,
+		DailyLimit:  arg.DailyLimit,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+	return nil
+}
+
+// newTestGovernor returns a governor whose clock starts at now
+func newTestGovernor(store Store, now time.Time) (*Governor, *time.Time) {
+	clock := now
+	g := &Governor{store: store, now: func() time.Time { return clock }, windowLimit: defaultWindowLimit, dailyLimit: defaultDailyLimit}
+	g.roll(clock)
+	return g, &clock
+}
+
+// reserveAll reserves until the priority has to wait or is refused, and
+// returns how many it got
+func reserveAll(t *testing.T, g *Governor, priority strava.Priority) (int, time.Duration, error) {
+	t.Helper()
+	for n := 0; n < 10000; n++ {
+		wait, err := g.tryReserve(priority)
+		if err != nil || wait > 0 {
+			return n, wait, err
+		}
+	}
+	t.Fatal("budget never ran out")
+	return 0, 0, nil
+}
+
+func TestReservePriorities(t *testing.T) {
+	start := time.Date(2026, 10, 18, 9, 5, 0, 0, time.UTC)
+	g, clock := newTestGovernor(&memoryStore{}, start)
+
+	// Backfill stops 20 short of the 100 request window, sync 10 short
+	n, wait, err := reserveAll(t, g, strava.PriorityBackfill)
+	if n != 80 || err != nil || wait != 10*time.Minute+2*time.Second {
+		t.Fatalf("expected backfill to get 80 and wait for 09:15, got %d, %v, %v", n, wait, err)
+	}
+	n, wait, err = reserveAll(t, g, strava.PrioritySync)
+	if n != 10 || err != nil || wait <= 0 {
+		t.Fatalf("expected sync to get 10 more, got %d, %v, %v", n, wait, err)
+	}
+	// Tool calls get the rest, then are refused rather than kept waiting
+	n, wait, err = reserveAll(t, g, strava.PriorityInteractive)
+	if n != 10 || !errors.Is(err, strava.ErrBudgetExhausted) || wait != 0 {
+		t.Fatalf("expected tool calls to get the last 10, got %d, %v, %v", n, wait, err)
+	}
+
+	// The next window refills, the day keeps counting
+	*clock = start.Add(10 * time.Minute)
+	if wait, err := g.tryReserve(strava.PriorityBackfill); wait != 0 || err != nil {
+		t.Errorf("expected a new window, got %v, %v", wait, err)
+	}
+	if g.windowUsage != 1 || g.dailyUsage != 101 {
+		t.Errorf("expected 1 in the window and 101 today, got %d and %d", g.windowUsage, g.dailyUsage)
+	}
+}
+
+func TestReserveDailyReserve(t *testing.T) {
+	g, _ := newTestGovernor(&memoryStore{}, time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC))
+	g.windowLimit = 10000
+
+	tests := []struct {
+		priority strava.Priority
+		want     int
+	}{
+		{strava.PriorityBackfill, 900},
+		{strava.PrioritySync, 50},
+		{strava.PriorityInteractive, 50},
+	}
+	for _, tt := range tests {
+		n, wait, err := reserveAll(t, g, tt.priority)
+		if n != tt.want || wait != 0 || !errors.Is(err, strava.ErrBudgetExhausted) {
+			t.Errorf("%s: expected %d more before the daily reserve, got %d, %v, %v", tt.priority, tt.want, n, wait, err)
+		}
+	}
+}
+
+func TestReserveWaitsForWindow(t *testing.T) {
+	g, _ := newTestGovernor(&memoryStore{}, time.Date(2026, 10, 18, 9, 5, 0, 0, time.UTC))
+	g.windowUsage = g.windowLimit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := g.Reserve(ctx, strava.PrioritySync); err != context.DeadlineExceeded {
+		t.Errorf("expected sync to wait for the window until cancelled, got %v", err)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	g, _ := newTestGovernor(&memoryStore{}, time.Date(2026, 10, 18, 9, 5, 0, 0, time.UTC))
+	g.windowUsage, g.dailyUsage = 3, 40
+
+	// Strava's counts include requests made elsewhere with the same app
+	g.Observe(strava.RateLimitInfo{Limit15Min: 200, LimitDaily: 2000, Usage15Min: 12, UsageDaily: 30})
+	if g.windowLimit != 200 || g.dailyLimit != 2000 || g.windowUsage != 12 || g.dailyUsage != 40 {
+		t.Errorf("unexpected budget: %+v", g)
+	}
+
+	g.Observe(strava.RateLimitInfo{IsRateLimited: true})
+	if g.windowUsage != 200 {
+		t.Errorf("expected a 429 to use up the window, got %d", g.windowUsage)
+	}
+}
+
+func TestSaveOnlyWhenChanged(t *testing.T) {
+	store := &memoryStore{}
+	g, _ := newTestGovernor(store, time.Date(2026, 10, 18, 9, 5, 0, 0, time.UTC))
+
+	// Requests only touch memory
+	for range 3 {
+		if wait, err := g.tryReserve(strava.PrioritySync); wait != 0 || err != nil {
+			t.Fatalf("unexpected wait %v, %v", wait, err)
+		}
+	}
+	if store.saves != 0 {
+		t.Fatalf("expected no saves while reserving, got %d", store.saves)
+	}
+
+	g.Save()
+	g.Save()
+	if store.saves != 1 || store.budget.WindowUsage != 3 || store.budget.DailyUsage != 3 {
+		t.Errorf("expected one save of 3 requests, got %d saves of %+v", store.saves, store.budget)
+	}
+}
+
+func TestNewRestoresBudget(t *testing.T) {
+	store := &memoryStore{}
+	now := time.Now().UTC()
+	first, _ := newTestGovernor(store, now)
+	first.Observe(strava.RateLimitInfo{Limit15Min: 200, LimitDaily: 2000, Usage15Min: 42, UsageDaily: 420})
+	first.Save()
+
+	g := New(context.Background(), store)
+	if g.windowLimit != 200 || g.dailyLimit != 2000 || g.dailyUsage != 420 {
+		t.Fatalf("expected the saved budget restored, got %+v", g)
+	}
+	// Unless the window ended between the two
+	if g.windowStart.Equal(first.windowStart) && g.windowUsage != 42 {
+		t.Errorf("expected the window usage restored, got %d", g.windowUsage)
+	}
+
+	// A budget saved on an earlier day is only kept for its limits
+	store.budget.DayStart = store.budget.DayStart.AddDate(0, 0, -1)
+	store.budget.WindowStart = store.budget.WindowStart.AddDate(0, 0, -1)
+	g = New(context.Background(), store)
+	if g.windowUsage != 0 || g.dailyUsage != 0 || g.dailyLimit != 2000 {
+		t.Errorf("expected a fresh day with the saved limits, got %+v", g)
+	}
+}
@@ -0,0 +1,158 @@
+// Package routes clusters activities into recurring routes by comparing
+// their summary polylines.
+package routes
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+)
+
+// Matching thresholds. Two activities follow the same route when they start
+// and finish close together, have a similar length, and their resampled
+// tracks stay close on average.
+const (
+	samplePoints        = 32
+	maxEndpointDistance = 250.0 // meters, between starts and between finishes
+	maxLengthRatio      = 0.10  // relative difference in track length
+	maxShapeDistance    = 150.0 // meters, mean deviation of the resampled tracks
+	minTrackLength      = 500.0 // meters; shorter tracks are not routes
+)
+
+// Store is the subset of queries needed for route detection
+type Store interface {
+	ListRoutesDetected(ctx context.Context) ([]db.RoutesDetected, error)
+	ListActivitiesWithoutRouteEffort(ctx context.Context) ([]db.Activity, error)
+	CreateRoutesDetected(ctx context.Context, arg db.CreateRoutesDetectedParams) (int64, error)
+	CreateRouteEffort(ctx context.Context, arg db.CreateRouteEffortParams) error
+}
+
+// Result summarizes a detection run
+type Result struct {
+	Assigned int // activities added to an existing route
+	Created  int // new routes
+}
+
+// route is a detected route prepared for matching
+type route struct {
+	id           int64
+	activityType string
+	start, end   geo.Point
+	length       float64
+	shape        []geo.Point
+}
+
+// Detect assigns every activity that has not been through detection yet,
+// oldest first, to the best matching known route or to a new route of its
+// own. Existing assignments are never changed, so route IDs stay stable.
+func Detect(ctx context.Context, store Store) (Result, error) {
+	var result Result
+
+	rows, err := store.ListRoutesDetected(ctx)
+	if err != nil {
+		return result, fmt.Errorf("listing routes: %w", err)
+	}
+	known := make([]route, 0, len(rows))
+	for _, r := range rows {
+		points, err := geo.DecodePolyline(r.Polyline)
+		if err != nil || len(points) < 2 {
+			logging.Warn("skipping route with unusable polyline", "route_id", r.ID, "error", err)
+			continue
+		}
+		known = append(known, newRoute(r.ID, r.ActivityType.String, points))
+	}
+
+	activities, err := store.ListActivitiesWithoutRouteEffort(ctx)
+	if err != nil {
+		return result, fmt.Errorf("listing unassigned activities: %w", err)
+	}
+
+	for _, a := range activities {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		points, err := geo.DecodePolyline(a.SummaryPolyline.String)
+		if err != nil || len(points) < 2 || geo.PathLength(points) < minTrackLength {
+			continue
+		}
+		candidate := newRoute(0, a.Type.String, points)
+
+		best, bestDistance := -1, maxShapeDistance
+		for i, r := range known {
+			if d, ok := r.match(candidate); ok && d <= bestDistance {
+				best, bestDistance = i, d
+			}
+		}
+
+		if best >= 0 {
+			err := store.CreateRouteEffort(ctx, db.CreateRouteEffortParams{
+				ActivityID:    a.ID,
+				RouteID:       known[best].id,
+				ShapeDistance: bestDistance,
+			})
+			if err != nil {
+				return result, fmt.Errorf("assigning activity %d: %w", a.ID, err)
+			}
+			result.Assigned++
+			continue
+		}
+
+		distance := a.Distance.Float64
+		if distance <= 0 {
+			distance = candidate.length
+		}
+		id, err := store.CreateRoutesDetected(ctx, db.CreateRoutesDetectedParams{
+			Name:                     a.Name,
+			ActivityType:             a.Type,
+			RepresentativeActivityID: a.ID,
+			Polyline:                 a.SummaryPolyline.String,
+			Distance:                 distance,
+			StartLat:                 candidate.start.Lat,
+			StartLng:                 candidate.start.Lng,
+			EndLat:                   candidate.end.Lat,
+			EndLng:                   candidate.end.Lng,
+		})
+		if err != nil {
+			return result, fmt.Errorf("creating route for activity %d: %w", a.ID, err)
+		}
+		if err := store.CreateRouteEffort(ctx, db.CreateRouteEffortParams{ActivityID: a.ID, RouteID: id}); err != nil {
+			return result, fmt.Errorf("assigning activity %d: %w", a.ID, err)
+		}
+		candidate.id = id
+		known = append(known, candidate)
+		result.Created++
+	}
+
+	return result, nil
+}
+
+func newRoute(id int64, activityType string, points []geo.Point) route {
+	return route{
+		id:           id,
+		activityType: activityType,
+		start:        points[0],
+		end:          points[len(points)-1],
+		length:       geo.PathLength(points),
+		shape:        geo.Resample(points, samplePoints),
+	}
+}
+
+// match reports whether c follows route r and, if so, how far its shape deviates
+func (r route) match(c route) (float64, bool) {
+	if r.activityType != c.activityType {
+		return 0, false
+	}
+	if geo.Haversine(r.start, c.start) > maxEndpointDistance || geo.Haversine(r.end, c.end) > maxEndpointDistance {
+		return 0, false
+	}
+	if r.length <= 0 || math.Abs(r.length-c.length)/r.length > maxLengthRatio {
+		return 0, false
+	}
+	d := geo.ShapeDistance(r.shape, c.shape)
+	return d, d <= maxShapeDistance
+}
@@ -0,0 +1,142 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+)
+
+// memStore is an in-memory Store
+type memStore struct {
+	routes     []db.RoutesDetected
+	efforts    map[int64]db.CreateRouteEffortParams
+	activities []db.Activity
+}
+
+func (m *memStore) ListRoutesDetected(ctx context.Context) ([]db.RoutesDetected, error) {
+	return m.routes, nil
+}
+
+func (m *memStore) ListActivitiesWithoutRouteEffort(ctx context.Context) ([]db.Activity, error) {
+	var out []db.Activity
+	for _, a := range m.activities {
+		if _, ok := m.efforts[a.ID]; !ok {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) CreateRoutesDetected(ctx context.Context, arg db.CreateRoutesDetectedParams) (int64, error) {
+	id := int64(len(m.routes) + 1)
+	m.routes = append(m.routes, db.RoutesDetected{
+		ID:                       id,
+		Name:                     arg.Name,
+		ActivityType:             arg.ActivityType,
+		RepresentativeActivityID: arg.RepresentativeActivityID,
+		Polyline:                 arg.Polyline,
+		Distance:                 arg.Distance,
+	})
+	return id, nil
+}
+
+func (m *memStore) CreateRouteEffort(ctx context.Context, arg db.CreateRouteEffortParams) error {
+	m.efforts[arg.ActivityID] = arg
+	return nil
+}
+
+// loop is a ~4 km square loop starting at the given offset (in degrees)
+func loop(offset float64) []geo.Point {
+	base := geo.Point{Lat: 37.77 + offset, Lng: -122.45 + offset}
+	return []geo.Point{
+		base,
+		{Lat: base.Lat + 0.009, Lng: base.Lng},
+		{Lat: base.Lat + 0.009, Lng: base.Lng + 0.011},
+		{Lat: base.Lat, Lng: base.Lng + 0.011},
+		{Lat: base.Lat + 0.0002, Lng: base.Lng + 0.0002},
+	}
+}
+
+func reversed(points []geo.Point) []geo.Point {
+	out := make([]geo.Point, len(points))
+	for i, p := range points {
+		out[len(points)-1-i] = p
+	}
+	return out
+}
+
+func activity(id int64, activityType string, points []geo.Point) db.Activity {
+	return db.Activity{
+		ID:              id,
+		Name:            "Activity",
+		Type:            sql.NullString{String: activityType, Valid: true},
+		StartDate:       sql.NullTime{Time: time.Date(2024, 1, int(id), 7, 0, 0, 0, time.UTC), Valid: true},
+		SummaryPolyline: sql.NullString{String: geo.EncodePolyline(points), Valid: true},
+	}
+}
+
+func TestDetect(t *testing.T) {
+	store := &memStore{
+		efforts: map[int64]db.CreateRouteEffortParams{},
+		activities: []db.Activity{
+			activity(1, "Run", loop(0)),
+			activity(2, "Run", loop(0.0003)),                                         // same loop, slight GPS offset
+			activity(3, "Run", reversed(loop(0))),                                    // same loop, other direction
+			activity(4, "Ride", loop(0)),                                             // same loop on a bike
+			activity(5, "Run", loop(0.05)),                                           // a different neighbourhood
+			activity(6, "Run", []geo.Point{{Lat: 1, Lng: 1}, {Lat: 1.0001, Lng: 1}}), // too short
+		},
+	}
+
+	result, err := Detect(context.Background(), store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Created != 4 || result.Assigned != 1 {
+		t.Errorf("expected 4 routes and 1 assignment, got %+v", result)
+	}
+	if store.efforts[2].RouteID != store.efforts[1].RouteID {
+		t.Error("expected activities 1 and 2 to share a route")
+	}
+	for _, id := range []int64{3, 4, 5} {
+		if store.efforts[id].RouteID == store.efforts[1].RouteID {
+			t.Errorf("activity %d should not share route with activity 1", id)
+		}
+	}
+	if _, ok := store.efforts[6]; ok {
+		t.Error("expected the short track to be skipped")
+	}
+
+	// A later run joins the existing route without renumbering anything
+	store.activities = append(store.activities, activity(7, "Run", loop(-0.0002)))
+	result, err = Detect(context.Background(), store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Created != 0 || result.Assigned != 1 || store.efforts[7].RouteID != store.efforts[1].RouteID {
+		t.Errorf("expected activity 7 to join route %d, got %+v (%+v)", store.efforts[1].RouteID, store.efforts[7], result)
+	}
+}
+
+func TestTrackMatch(t *testing.T) {
+	route, err := NewTrack("Run", geo.EncodePolyline(loop(0)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if same, err := NewTrack("Run", geo.EncodePolyline(loop(0.0003))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, ok := route.Match(same); !ok {
+		t.Error("expected the offset loop to match")
+	}
+	other, _ := NewTrack("Run", geo.EncodePolyline(loop(0.05)))
+	if _, ok := route.Match(other); ok {
+		t.Error("expected a different neighbourhood not to match")
+	}
+	if _, err := NewTrack("Run", ""); err == nil {
+		t.Error("expected an error for an empty polyline")
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+)
+
+// Track is a polyline prepared for matching against other tracks with the
+// same thresholds route detection uses. It lets callers compare tracks
+// that are not stored as detected routes, such as saved Strava routes.
+type Track struct {
+	r route
+}
+
+// NewTrack decodes an encoded polyline into a Track
+func NewTrack(activityType, polyline string) (Track, error) {
+	points, err := geo.DecodePolyline(polyline)
+	if err != nil {
+		return Track{}, fmt.Errorf("decoding polyline: %w", err)
+	}
+	if len(points) < 2 {
+		return Track{}, fmt.Errorf("polyline has %d points", len(points))
+	}
+	return Track{r: newRoute(0, activityType, points)}, nil
+}
+
+// Length is the track length in meters
+func (t Track) Length() float64 {
+	return t.r.length
+}
+
+// Match reports whether other follows t and, if so, the mean deviation of
+// its shape in meters
+func (t Track) Match(other Track) (float64, bool) {
+	return t.r.match(other.r)
+}
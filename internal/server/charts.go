@@ -0,0 +1,225 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/chart"
+	"github.com/joshdurbin/strava-mcp/internal/forecast"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// parseChartFormat validates the chart_format input when a chart was requested
+func parseChartFormat(includeChart bool, format string) (chart.Format, error) {
+	if !includeChart {
+		return "", nil
+	}
+	f, err := chart.ParseFormat(format)
+	if err != nil {
+		return "", NewInvalidInputErrorWithDetails("invalid chart_format", "expected 'png' or 'svg'")
+	}
+	return f, nil
+}
+
+// chartResult renders ch and returns a result carrying both the JSON output
+// (as text, mirroring what the SDK emits when Content is left empty) and the
+// image. Rendering failures are logged and the plain JSON result is used
+// instead, so a chart problem never fails the underlying query.
+func chartResult(tool string, output any, ch chart.Chart, format chart.Format) *mcp.CallToolResult {
+	data, mimeType, err := chart.Render(ch, format)
+	if err != nil {
+		logging.Warn("Chart rendering failed", "tool", tool, "error", err)
+		return nil
+	}
+
+	outJSON, err := json.Marshal(output)
+	if err != nil {
+		logging.Warn("Chart output marshaling failed", "tool", tool, "error", err)
+		return nil
+	}
+
+	logging.Debug("Chart rendered", "tool", tool, "mime_type", mimeType, "bytes", len(data))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: string(outJSON)},
+			&mcp.ImageContent{Data: data, MIMEType: mimeType},
+		},
+	}
+}
+
+// weeklyVolumeChart plots weekly distance in km, oldest week first
+func weeklyVolumeChart(weeks []weeklyVolumeData, activityType string) chart.BarChart {
+	title := "Weekly distance"
+	if activityType != "" {
+		title += " - " + activityType
+	}
+
+	bc := chart.BarChart{Title: title, YLabel: "km"}
+	for i := len(weeks) - 1; i >= 0; i-- {
+		bc.Labels = append(bc.Labels, weeks[i].Week)
+		bc.Values = append(bc.Values, math.Round(weeks[i].TotalDistance/100)/10)
+	}
+	return bc
+}
+
+// forecastChart plots recent weekly distance followed by the forecast and its
+// 80% range
+func forecastChart(h weeklySeries, points []forecast.Point, activityType string) chart.LineChart {
+	title := "Weekly distance forecast"
+	if activityType != "" {
+		title += " - " + activityType
+	}
+	lc := chart.LineChart{Title: title, YLabel: "km"}
+
+	history := h.distance[max(len(h.distance)-recentChartWeeks, 0):]
+	actual := make([]float64, 0, len(history)+len(points))
+	expected := make([]float64, 0, len(history)+len(points))
+	low := make([]float64, 0, len(history)+len(points))
+	high := make([]float64, 0, len(history)+len(points))
+	for i, v := range history {
+		monday := h.start.AddDate(0, 0, 7*(len(h.distance)-len(history)+i))
+		lc.XLabels = append(lc.XLabels, monday.Format("Jan 2"))
+		actual = append(actual, math.Round(v/100)/10)
+		expected = append(expected, math.NaN())
+		low = append(low, math.NaN())
+		high = append(high, math.NaN())
+	}
+	lc.Divider = len(lc.XLabels)
+	for _, p := range points {
+		lc.XLabels = append(lc.XLabels, p.Start.Format("Jan 2"))
+		actual = append(actual, math.NaN())
+		expected = append(expected, math.Round(p.Mean/100)/10)
+		low = append(low, math.Round(p.Low/100)/10)
+		high = append(high, math.Round(p.High/100)/10)
+	}
+	lc.Series = []chart.Series{
+		{Name: "Actual", Values: actual},
+		{Name: "Forecast", Values: expected},
+		{Name: "80% low", Values: low},
+		{Name: "80% high", Values: high},
+	}
+	return lc
+}
+
+// zoneDonutChart shows the share of time spent in each zone
+func zoneDonutChart(output AnalyzeZonesOutput) chart.DonutChart {
+	title := "Heart rate zones"
+	switch output.ZoneType {
+	case "power":
+		title = "Power zones"
+	case "pace":
+		title = "Pace zones"
+	}
+	if output.Filter != "" && output.Filter != "all time" {
+		title += " " + output.Filter
+	}
+
+	dc := chart.DonutChart{Title: title, ZoneColors: true}
+	for _, z := range output.Zones {
+		dc.Slices = append(dc.Slices, chart.Slice{
+			Label: fmt.Sprintf("Z%d", z.Zone),
+			Value: z.Percentage,
+		})
+	}
+	return dc
+}
+
+// progressTrendChart plots the analyzed metric week by week across both the
+// previous and current periods, with a divider where the current period starts
+func progressTrendChart(metric, activityType string, weeks []weeklyVolumeData, currentStart time.Time) chart.LineChart {
+	lc := chart.LineChart{
+		Title: "Weekly " + metric + " trend",
+	}
+	if activityType != "" {
+		lc.Title += " - " + activityType
+	}
+
+	switch metric {
+	case "pace":
+		lc.YLabel = "min/km"
+		lc.InvertY = true
+		lc.YFormat = func(v float64) string {
+			secs := int(math.Round(v))
+			return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+		}
+	case "distance":
+		lc.YLabel = "km"
+	case "duration":
+		lc.YLabel = "hours"
+	case "elevation":
+		lc.YLabel = "m"
+	}
+
+	values := make([]float64, 0, len(weeks))
+	boundary := sqliteWeek(currentStart)
+	for i := len(weeks) - 1; i >= 0; i-- {
+		w := weeks[i]
+		if lc.Divider == 0 && len(lc.XLabels) > 0 && w.Week >= boundary {
+			lc.Divider = len(lc.XLabels)
+		}
+		lc.XLabels = append(lc.XLabels, w.Week)
+		values = append(values, weeklyMetricValue(metric, w))
+	}
+	lc.Series = []chart.Series{{Name: metric, Values: values}}
+	return lc
+}
+
+// weeklyMetricValue extracts a chartable value; NaN marks weeks without data
+func weeklyMetricValue(metric string, w weeklyVolumeData) float64 {
+	switch metric {
+	case "distance":
+		return w.TotalDistance / 1000
+	case "duration":
+		return float64(w.TotalDuration) / 3600
+	case "elevation":
+		return w.TotalElevation
+	default: // pace, as seconds per km
+		if w.TotalDistance <= 0 || w.TotalDuration <= 0 {
+			return math.NaN()
+		}
+		return float64(w.TotalDuration) / (w.TotalDistance / 1000)
+	}
+}
+
+// sqliteWeek formats t like SQLite's strftime('%Y-W%W'), the week key used by
+// the weekly volume queries (weeks start on Monday; days before the first
+// Monday of the year are week 00).
+func sqliteWeek(t time.Time) string {
+	mondayBased := (int(t.Weekday()) + 6) % 7
+	week := (t.YearDay() - 1 + 7 - mondayBased) / 7
+	return fmt.Sprintf("%d-W%02d", t.Year(), week)
+}
+
+// sqliteWeekStart is the Monday starting the week with a sqliteWeek key.
+// Week 00 is the end of the week that started in December, so it shares a
+// Monday with the last week of the year before.
+func sqliteWeekStart(key string) (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(key, "%d-W%d", &year, &week); err != nil {
+		return time.Time{}, fmt.Errorf("parsing week %q: %w", key, err)
+	}
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	mondayBased := (int(jan1.Weekday()) + 6) % 7
+	if week == 0 {
+		return jan1.AddDate(0, 0, -mondayBased), nil
+	}
+	firstMonday := jan1.AddDate(0, 0, (7-mondayBased)%7)
+	return firstMonday.AddDate(0, 0, 7*(week-1)), nil
+}
+
+// gapChart plots the running gap over distance in seconds, above zero when ahead
+func gapChart(gaps []gapSample, compareToDate string) chart.LineChart {
+	lc := chart.LineChart{
+		Title:  "Gap vs " + compareToDate,
+		YLabel: "s ahead",
+		Series: []chart.Series{{Name: "Gap"}},
+	}
+	for _, g := range gaps {
+		lc.XLabels = append(lc.XLabels, fmt.Sprintf("%.1f", g.distance/1000))
+		lc.Series[0].Values = append(lc.Series[0].Values, math.Round(g.gap))
+	}
+	return lc
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MockProgressQuerier returns canned weekly volume rows
+type MockProgressQuerier struct {
+	MockQuerier
+	weeklyVolume []db.GetWeeklyVolumeRow
+}
+
+func (m *MockProgressQuerier) GetWeeklyVolume(ctx context.Context, arg db.GetWeeklyVolumeParams) ([]db.GetWeeklyVolumeRow, error) {
+	return m.weeklyVolume, nil
+}
+
+func newChartTestServer() *Server {
+	return New(&MockProgressQuerier{
+		weeklyVolume: []db.GetWeeklyVolumeRow{
+			{Week: "2024-W10", ActivityCount: 4, TotalDistance: 42000.0, TotalDuration: int64(12600)},
+			{Week: "2024-W09", ActivityCount: 3, TotalDistance: 30000.0, TotalDuration: int64(9300)},
+		},
+	})
+}
+
+func imageContent(t *testing.T, result *mcp.CallToolResult) *mcp.ImageContent {
+	t.Helper()
+	if result == nil {
+		t.Fatal("expected a result with chart content")
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected text and image content, got %d items", len(result.Content))
+	}
+	if _, ok := result.Content[0].(*mcp.TextContent); !ok {
+		t.Errorf("expected first content to be text, got %T", result.Content[0])
+	}
+	img, ok := result.Content[1].(*mcp.ImageContent)
+	if !ok {
+		t.Fatalf("expected second content to be an image, got %T", result.Content[1])
+	}
+	return img
+}
+
+func TestCheckTrainingLoadChart(t *testing.T) {
+	t.Parallel()
+
+	srv := newChartTestServer()
+
+	result, _, err := srv.checkTrainingLoad(context.Background(), nil, CheckTrainingLoadInput{IncludeChart: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img := imageContent(t, result)
+	if img.MIMEType != "image/png" || len(img.Data) == 0 {
+		t.Errorf("expected PNG data, got %s (%d bytes)", img.MIMEType, len(img.Data))
+	}
+
+	// Without include_chart the SDK default (JSON text only) is used
+	result, _, err = srv.checkTrainingLoad(context.Background(), nil, CheckTrainingLoadInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != nil {
+		t.Error("expected nil result without include_chart")
+	}
+}
+
+func TestAnalyzeProgressChartSVG(t *testing.T) {
+	t.Parallel()
+
+	srv := newChartTestServer()
+
+	result, _, err := srv.analyzeProgress(context.Background(), nil, AnalyzeProgressInput{IncludeChart: true, ChartFormat: "svg"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if img := imageContent(t, result); img.MIMEType != "image/svg+xml" {
+		t.Errorf("expected SVG, got %s", img.MIMEType)
+	}
+}
+
+func TestAnalyzeZonesChart(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockZonesQuerier{
+		hrZoneSummary: []db.GetHeartRateZoneSummaryRow{
+			{ZoneNumber: 1, TotalTime: sql.NullFloat64{Float64: 3600, Valid: true}, ActivityCount: 2},
+			{ZoneNumber: 2, TotalTime: sql.NullFloat64{Float64: 7200, Valid: true}, ActivityCount: 2},
+		},
+	})
+
+	result, _, err := srv.analyzeZones(context.Background(), nil, AnalyzeZonesInput{IncludeChart: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	imageContent(t, result)
+}
+
+func TestWeeklyVolumeChartSQLite(t *testing.T) {
+	t.Parallel()
+
+	// Rides in three of the last four weeks, stored through the driver
+	queries := newSQLiteQueries(t)
+	monday := blocks.WeekStart(calendarDay(time.Now()))
+	createSQLiteActivity(t, queries, 1, "Ride", monday.AddDate(0, 0, -26).Add(8*time.Hour), 40000)
+	createSQLiteActivity(t, queries, 2, "Ride", monday.AddDate(0, 0, -24).Add(8*time.Hour), 20000)
+	createSQLiteActivity(t, queries, 3, "Ride", monday.AddDate(0, 0, -12).Add(8*time.Hour), 30000)
+	createSQLiteActivity(t, queries, 4, "Run", monday.AddDate(0, 0, -5).Add(8*time.Hour), 10000)
+
+	weeks, err := fetchWeeklyVolume(context.Background(), queries, "", monday.AddDate(0, 0, -28), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bc := weeklyVolumeChart(weeks, "")
+	want := []string{sqliteWeek(monday.AddDate(0, 0, -28)), sqliteWeek(monday.AddDate(0, 0, -14)), sqliteWeek(monday.AddDate(0, 0, -7))}
+	if !slices.Equal(bc.Labels, want) || !slices.Equal(bc.Values, []float64{60, 30, 10}) {
+		t.Errorf("expected %v with 60, 30 and 10 km, got %v and %v", want, bc.Labels, bc.Values)
+	}
+
+	weeks, err = fetchWeeklyVolume(context.Background(), queries, "Ride", monday.AddDate(0, 0, -28), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lc := progressTrendChart("distance", "Ride", weeks, monday.AddDate(0, 0, -14))
+	if !slices.Equal(lc.XLabels, want[:2]) || lc.Divider != 1 {
+		t.Errorf("expected the two ride weeks split at the second, got %v (divider %d)", lc.XLabels, lc.Divider)
+	}
+}
+
+func TestChartFormatValidation(t *testing.T) {
+	t.Parallel()
+
+	srv := newChartTestServer()
+
+	if _, _, err := srv.checkTrainingLoad(context.Background(), nil, CheckTrainingLoadInput{IncludeChart: true, ChartFormat: "gif"}); err == nil {
+		t.Error("expected error for unsupported chart format")
+	}
+}
+
+func TestWeeklyMetricValue(t *testing.T) {
+	w := weeklyVolumeData{TotalDistance: 10000, TotalDuration: 3000, TotalElevation: 120}
+
+	if got := weeklyMetricValue("pace", w); got != 300 {
+		t.Errorf("expected 300 s/km, got %v", got)
+	}
+	if got := weeklyMetricValue("distance", w); got != 10 {
+		t.Errorf("expected 10 km, got %v", got)
+	}
+	if got := weeklyMetricValue("elevation", w); got != 120 {
+		t.Errorf("expected 120 m, got %v", got)
+	}
+}
+
+func TestSQLiteWeek(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2024-01-01", "2024-W01"}, // Monday
+		{"2023-01-01", "2023-W00"}, // Sunday before the first Monday
+		{"2023-01-02", "2023-W01"},
+		{"2024-12-31", "2024-W53"},
+	}
+	for _, tt := range tests {
+		d, _ := time.Parse("2006-01-02", tt.date)
+		if got := sqliteWeek(d); got != tt.want {
+			t.Errorf("sqliteWeek(%s) = %s, want %s", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestSQLiteWeekStart(t *testing.T) {
+	// Every day maps back to the Monday of its week, across year ends
+	day := time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC)
+	for range 800 {
+		got, err := sqliteWeekStart(sqliteWeek(day))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		if !got.Equal(monday) {
+			t.Fatalf("%s (%s): expected %s, got %s", day.Format("2006-01-02"), sqliteWeek(day), monday.Format("2006-01-02"), got.Format("2006-01-02"))
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	if _, err := sqliteWeekStart("last week"); err == nil {
+		t.Error("expected an error for a malformed key")
+	}
+}
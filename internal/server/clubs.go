@@ -0,0 +1,423 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ClubQuerier defines the interface for club queries
+type ClubQuerier interface {
+	ListClubs(ctx context.Context) ([]db.Club, error)
+	GetClub(ctx context.Context, id int64) (db.Club, error)
+	ListClubActivityCounts(ctx context.Context, firstSeenAt time.Time) ([]db.ListClubActivityCountsRow, error)
+	GetClubWeeklyTotals(ctx context.Context, arg db.GetClubWeeklyTotalsParams) ([]db.GetClubWeeklyTotalsRow, error)
+	GetClubLeaderboard(ctx context.Context, arg db.GetClubLeaderboardParams) ([]db.GetClubLeaderboardRow, error)
+	GetClubTypeTotals(ctx context.Context, arg db.GetClubTypeTotalsParams) ([]db.GetClubTypeTotalsRow, error)
+	CountClubBackfillActivities(ctx context.Context, arg db.CountClubBackfillActivitiesParams) (int64, error)
+}
+
+// Club summary limits
+const (
+	defaultClubWeeks       = 4
+	maxClubWeeks           = 26
+	defaultClubLeaderboard = 10
+	maxClubLeaderboard     = 50
+)
+
+// Input types
+
+// ListClubsInput - input for listing the athlete's clubs
+type ListClubsInput struct{}
+
+// GetClubActivitySummaryInput - input for a club's weekly totals and leaderboard
+type GetClubActivitySummaryInput struct {
+	ClubID int64 `json:"club_id,omitempty" jsonschema:"The club to summarize, from list_clubs. May be left out when the athlete is in one club."`
+	Weeks  int   `json:"weeks,omitempty" jsonschema:"Number of weeks to cover, counting the current week. Default: 4, Maximum: 26."`
+	Limit  int   `json:"limit,omitempty" jsonschema:"Number of members on the leaderboard. Default: 10, Maximum: 50."`
+}
+
+// Output types
+
+type ListClubsOutput struct {
+	Clubs            []ClubSummary     `json:"clubs"`
+	Insights         []Insight         `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions,omitempty"`
+}
+
+// ClubSummary describes a club and how much of its feed is stored
+type ClubSummary struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	SportType        string `json:"sport_type,omitempty"`
+	Location         string `json:"location,omitempty"`
+	Private          bool   `json:"private"`
+	MemberCount      int64  `json:"member_count"`
+	URL              string `json:"url,omitempty"`
+	ActivitiesStored int64  `json:"activities_stored"`
+	ActivitiesWeek   int64  `json:"activities_last_7_days"`
+}
+
+type GetClubActivitySummaryOutput struct {
+	Club             ClubSummary       `json:"club"`
+	Period           string            `json:"period"`
+	Totals           ClubTotals        `json:"totals"`
+	Weekly           []ClubWeek        `json:"weekly"`
+	Leaderboard      []ClubMember      `json:"leaderboard"`
+	ByType           []ClubTypeTotal   `json:"by_type"`
+	Insights         []Insight         `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions,omitempty"`
+}
+
+// ClubTotals totals a club's activities over the period
+type ClubTotals struct {
+	Activities int64  `json:"activities"`
+	Members    int    `json:"active_members"`
+	Distance   string `json:"total_distance"`
+	Duration   string `json:"total_duration"`
+	Elevation  string `json:"total_elevation"`
+	// Backfill counts activities found on the club's first sync, dated then
+	Backfill int64 `json:"backfill_activities,omitempty"`
+}
+
+// ClubWeek is a club's totals for one week, from Monday
+type ClubWeek struct {
+	WeekStart  string `json:"week_start"`
+	Activities int64  `json:"activities"`
+	Athletes   int64  `json:"athletes"`
+	Distance   string `json:"total_distance"`
+	Duration   string `json:"total_duration"`
+	Elevation  string `json:"total_elevation"`
+}
+
+// ClubMember is one athlete's totals on a club leaderboard
+type ClubMember struct {
+	Rank       int    `json:"rank"`
+	Athlete    string `json:"athlete"`
+	Activities int64  `json:"activities"`
+	Distance   string `json:"total_distance"`
+	Duration   string `json:"total_duration"`
+	Elevation  string `json:"total_elevation"`
+}
+
+// ClubTypeTotal is a club's totals for one activity type
+type ClubTypeTotal struct {
+	Type       string `json:"type"`
+	Activities int64  `json:"activities"`
+	Distance   string `json:"total_distance"`
+	Duration   string `json:"total_duration"`
+}
+
+// registerClubTools registers the club tools
+func (s *Server) registerClubTools() {
+	logging.Debug("Registering tool", "name", "list_clubs")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "list_clubs",
+		Description: `List the Strava clubs the athlete belongs to, with how many of each club's activities are stored.
+
+Use when:
+- User asks "Which clubs am I in?"
+- Before get_club_activity_summary, to find a club's ID
+
+Parameters: None
+
+Returns: Each club's ID, name, sport, location, member count, and its activities stored and seen in the last 7 days. Clubs and their feeds sync in the background.
+
+Example: {}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:          "List Clubs",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  ptr(false),
+		},
+	}, s.listClubs)
+
+	logging.Debug("Registering tool", "name", "get_club_activity_summary")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "get_club_activity_summary",
+		Description: `Weekly totals and a member leaderboard for a Strava club, from the activities in its feed.
+
+Use when:
+- User asks "How far did my running club go this week?"
+- User asks "Who's top of the club leaderboard this month?"
+- User wants to see how active a club has been lately
+
+Parameters:
+- club_id (int): The club, from list_clubs. May be left out when the athlete is in one club.
+- weeks (int): Weeks to cover, counting the current week. Default: 4, Max: 26
+- limit (int): Members on the leaderboard. Default: 10, Max: 50
+
+Returns: Totals, weekly totals, a leaderboard by distance and totals by activity type. Strava's club feed has no activity dates and only recent activities, so each activity is dated by the sync that first saw it. Activities already in the feed on the club's first sync count toward the leaderboard but not the weekly totals. Members are named by first name and last initial.
+
+Example: {"club_id": 123456, "weeks": 8}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:          "Get Club Activity Summary",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  ptr(false),
+		},
+	}, s.getClubActivitySummary)
+}
+
+// listClubs lists the athlete's clubs
+func (s *Server) listClubs(ctx context.Context, req *mcp.CallToolRequest, input ListClubsInput) (*mcp.CallToolResult, ListClubsOutput, error) {
+	logging.Info("MCP tool call", "tool", "list_clubs")
+
+	queries := s.queries.(ClubQuerier)
+	clubs, err := queries.ListClubs(ctx)
+	if err != nil {
+		return nil, ListClubsOutput{}, NewDatabaseError(err)
+	}
+	counts, err := queries.ListClubActivityCounts(ctx, time.Now().UTC().Truncate(time.Second).AddDate(0, 0, -7))
+	if err != nil {
+		return nil, ListClubsOutput{}, NewDatabaseError(err)
+	}
+	byClub := make(map[int64]db.ListClubActivityCountsRow, len(counts))
+	for _, c := range counts {
+		byClub[c.ClubID] = c
+	}
+
+	output := ListClubsOutput{Clubs: make([]ClubSummary, 0, len(clubs)), Insights: make([]Insight, 0)}
+	for _, c := range clubs {
+		output.Clubs = append(output.Clubs, convertClub(c, byClub[c.ID]))
+	}
+
+	if len(output.Clubs) == 0 {
+		output.Insights = append(output.Insights, Insight{
+			Type:    "suggestion",
+			Message: "No clubs stored yet. Clubs sync in the background a minute after the server starts, unless it runs with --no-sync",
+		})
+	} else {
+		output.SuggestedActions = SuggestNextActions("clubs")
+	}
+
+	logging.Info("MCP tool completed", "tool", "list_clubs", "clubs", len(output.Clubs))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "list_clubs", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// getClubActivitySummary summarizes a club's recent activities
+func (s *Server) getClubActivitySummary(ctx context.Context, req *mcp.CallToolRequest, input GetClubActivitySummaryInput) (*mcp.CallToolResult, GetClubActivitySummaryOutput, error) {
+	logging.Info("MCP tool call", "tool", "get_club_activity_summary", "club_id", input.ClubID, "weeks", input.Weeks)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "get_club_activity_summary", "input", logging.ToJSON(input))
+	}
+
+	weeks := input.Weeks
+	if weeks <= 0 {
+		weeks = defaultClubWeeks
+	}
+	if weeks > maxClubWeeks {
+		weeks = maxClubWeeks
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultClubLeaderboard
+	}
+	if limit > maxClubLeaderboard {
+		limit = maxClubLeaderboard
+	}
+
+	queries := s.queries.(ClubQuerier)
+	club, err := s.findClub(ctx, queries, input.ClubID)
+	if err != nil {
+		return nil, GetClubActivitySummaryOutput{}, err
+	}
+
+	since := clubPeriodStart(time.Now().UTC(), weeks)
+	weekly, err := queries.GetClubWeeklyTotals(ctx, db.GetClubWeeklyTotalsParams{ClubID: club.ID, FirstSeenAt: since})
+	if err != nil {
+		return nil, GetClubActivitySummaryOutput{}, NewDatabaseError(err)
+	}
+	// A negative limit returns every member, so the totals can be taken from
+	// the leaderboard
+	members, err := queries.GetClubLeaderboard(ctx, db.GetClubLeaderboardParams{ClubID: club.ID, FirstSeenAt: since, Limit: -1})
+	if err != nil {
+		return nil, GetClubActivitySummaryOutput{}, NewDatabaseError(err)
+	}
+	types, err := queries.GetClubTypeTotals(ctx, db.GetClubTypeTotalsParams{ClubID: club.ID, FirstSeenAt: since})
+	if err != nil {
+		return nil, GetClubActivitySummaryOutput{}, NewDatabaseError(err)
+	}
+	backfill, err := queries.CountClubBackfillActivities(ctx, db.CountClubBackfillActivitiesParams{ClubID: club.ID, FirstSeenAt: since})
+	if err != nil {
+		return nil, GetClubActivitySummaryOutput{}, NewDatabaseError(err)
+	}
+
+	counts, err := queries.ListClubActivityCounts(ctx, time.Now().UTC().Truncate(time.Second).AddDate(0, 0, -7))
+	if err != nil {
+		return nil, GetClubActivitySummaryOutput{}, NewDatabaseError(err)
+	}
+	var clubCounts db.ListClubActivityCountsRow
+	for _, c := range counts {
+		if c.ClubID == club.ID {
+			clubCounts = c
+		}
+	}
+
+	output := GetClubActivitySummaryOutput{
+		Club:        convertClub(club, clubCounts),
+		Period:      fmt.Sprintf("%d weeks from %s", weeks, since.Format("2006-01-02")),
+		Weekly:      make([]ClubWeek, 0, len(weekly)),
+		Leaderboard: make([]ClubMember, 0, min(limit, len(members))),
+		ByType:      make([]ClubTypeTotal, 0, len(types)),
+	}
+
+	var distance, elevation float64
+	var duration int64
+	for i, m := range members {
+		distance += toFloat64(m.TotalDistance)
+		duration += toInt64(m.TotalMovingTime)
+		elevation += toFloat64(m.TotalElevation)
+		output.Totals.Activities += m.ActivityCount
+		if i < limit {
+			output.Leaderboard = append(output.Leaderboard, ClubMember{
+				Rank:       i + 1,
+				Athlete:    m.AthleteName,
+				Activities: m.ActivityCount,
+				Distance:   formatDistance(toFloat64(m.TotalDistance)),
+				Duration:   formatDuration(toInt64(m.TotalMovingTime)),
+				Elevation:  fmt.Sprintf("%.0f m", toFloat64(m.TotalElevation)),
+			})
+		}
+	}
+	output.Totals.Members = len(members)
+	output.Totals.Distance = formatDistance(distance)
+	output.Totals.Duration = formatDuration(duration)
+	output.Totals.Elevation = fmt.Sprintf("%.0f m", elevation)
+	output.Totals.Backfill = backfill
+
+	for _, w := range weekly {
+		output.Weekly = append(output.Weekly, ClubWeek{
+			WeekStart:  fmt.Sprintf("%v", w.WeekStart),
+			Activities: w.ActivityCount,
+			Athletes:   w.Athletes,
+			Distance:   formatDistance(toFloat64(w.TotalDistance)),
+			Duration:   formatDuration(toInt64(w.TotalMovingTime)),
+			Elevation:  fmt.Sprintf("%.0f m", toFloat64(w.TotalElevation)),
+		})
+	}
+	for _, t := range types {
+		name := t.Type.String
+		if name == "" {
+			name = "Unknown"
+		}
+		output.ByType = append(output.ByType, ClubTypeTotal{
+			Type:       name,
+			Activities: t.ActivityCount,
+			Distance:   formatDistance(toFloat64(t.TotalDistance)),
+			Duration:   formatDuration(toInt64(t.TotalMovingTime)),
+		})
+	}
+
+	output.Insights = clubInsights(output, weekly)
+	output.SuggestedActions = SuggestNextActions("clubs")
+
+	logging.Info("MCP tool completed", "tool", "get_club_activity_summary", "club_id", club.ID, "activities", output.Totals.Activities, "members", output.Totals.Members)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "get_club_activity_summary", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// findClub gets the club asked for, or the only club when none is given
+func (s *Server) findClub(ctx context.Context, queries ClubQuerier, clubID int64) (db.Club, error) {
+	if clubID != 0 {
+		club, err := queries.GetClub(ctx, clubID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return db.Club{}, NewNotFoundErrorWithID("club", clubID)
+			}
+			return db.Club{}, NewDatabaseError(err)
+		}
+		return club, nil
+	}
+
+	clubs, err := queries.ListClubs(ctx)
+	if err != nil {
+		return db.Club{}, NewDatabaseError(err)
+	}
+	switch len(clubs) {
+	case 0:
+		return db.Club{}, NewInvalidInputErrorWithDetails("No clubs stored", "Clubs sync in the background; try again once the server has synced")
+	case 1:
+		return clubs[0], nil
+	}
+	names := make([]string, 0, len(clubs))
+	for _, c := range clubs {
+		names = append(names, fmt.Sprintf("%s (%d)", c.Name, c.ID))
+	}
+	return db.Club{}, NewInvalidInputErrorWithDetails("club_id is required", "The athlete is in "+strings.Join(names, ", "))
+}
+
+// clubPeriodStart is midnight UTC on the Monday weeks-1 weeks before now's week
+func clubPeriodStart(now time.Time, weeks int) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	sinceMonday := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -sinceMonday-7*(weeks-1))
+}
+
+// convertClub converts a stored club with its activity counts
+func convertClub(c db.Club, counts db.ListClubActivityCountsRow) ClubSummary {
+	location := make([]string, 0, 3)
+	for _, part := range []sql.NullString{c.City, c.State, c.Country} {
+		if part.String != "" {
+			location = append(location, part.String)
+		}
+	}
+	return ClubSummary{
+		ID:               c.ID,
+		Name:             c.Name,
+		SportType:        c.SportType.String,
+		Location:         strings.Join(location, ", "),
+		Private:          c.Private != 0,
+		MemberCount:      c.MemberCount,
+		URL:              c.Url.String,
+		ActivitiesStored: counts.ActivityCount,
+		ActivitiesWeek:   toInt64(counts.RecentCount),
+	}
+}
+
+// clubInsights points out the club's weekly trend and its leader
+func clubInsights(output GetClubActivitySummaryOutput, weekly []db.GetClubWeeklyTotalsRow) []Insight {
+	insights := make([]Insight, 0)
+	if output.Totals.Activities == 0 {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No club activities stored for this period. The club feed syncs in the background and only holds recent activities",
+		})
+	}
+
+	if n := len(weekly); n >= 2 {
+		last, prev := toFloat64(weekly[n-1].TotalDistance), toFloat64(weekly[n-2].TotalDistance)
+		if prev > 0 {
+			change := (last - prev) / prev * 100
+			insights = append(insights, Insight{
+				Type:    "trend",
+				Message: fmt.Sprintf("The club covered %s in the week from %v, %+.0f%% on the week before (the current week may not be over)", formatDistance(last), weekly[n-1].WeekStart, change),
+			})
+		}
+	}
+	if len(output.Leaderboard) > 0 {
+		leader := output.Leaderboard[0]
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("%s leads with %s over %d activities", leader.Athlete, leader.Distance, leader.Activities),
+		})
+	}
+	if output.Totals.Backfill > 0 {
+		insights = append(insights, Insight{
+			Type:    "warning",
+			Message: fmt.Sprintf("%d of these activities were already in the feed on the club's first sync; their dates are unknown, so they are on the leaderboard but not in the weekly totals", output.Totals.Backfill),
+		})
+	}
+	return insights
+}
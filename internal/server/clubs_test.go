@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func clubTestQuerier(now time.Time) *MockQuerier {
+	run := sql.NullString{String: "Run", Valid: true}
+	activity := func(athlete string, km float64, seen time.Time, backfill int64) db.ClubActivity {
+		return db.ClubActivity{
+			ClubID: 42, AthleteName: athlete, Name: "Club run", Type: run,
+			Distance: km * 1000, MovingTime: int64(km * 300), TotalElevationGain: km * 5,
+			FirstSeenAt: seen, Backfill: backfill,
+		}
+	}
+	return &MockQuerier{
+		clubs: []db.Club{{
+			ID: 42, Name: "Riverside Runners", SportType: sql.NullString{String: "running", Valid: true},
+			City: sql.NullString{String: "Portland", Valid: true}, Country: sql.NullString{String: "United States", Valid: true},
+			MemberCount: 318,
+		}},
+		clubActivities: []db.ClubActivity{
+			activity("Ana L.", 20, now.Add(-time.Hour), 0),
+			activity("Ben K.", 8, now.Add(-time.Hour), 0),
+			activity("Ana L.", 10, now.AddDate(0, 0, -8), 0),
+			activity("Ben K.", 30, now.AddDate(0, 0, -15), 1),
+			// Outside the period
+			activity("Cam R.", 50, now.AddDate(0, 0, -60), 0),
+		},
+	}
+}
+
+func TestListClubs(t *testing.T) {
+	t.Parallel()
+
+	_, output, err := New(clubTestQuerier(time.Now().UTC())).listClubs(context.Background(), nil, ListClubsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Clubs) != 1 {
+		t.Fatalf("expected one club, got %+v", output.Clubs)
+	}
+	club := output.Clubs[0]
+	if club.Location != "Portland, United States" || club.ActivitiesStored != 5 || club.ActivitiesWeek != 2 {
+		t.Errorf("unexpected club: %+v", club)
+	}
+
+	_, output, err = New(&MockQuerier{}).listClubs(context.Background(), nil, ListClubsInput{})
+	if err != nil || len(output.Clubs) != 0 || len(output.Insights) != 1 {
+		t.Errorf("expected a suggestion with no clubs, got %+v (%v)", output, err)
+	}
+}
+
+func TestGetClubActivitySummary(t *testing.T) {
+	t.Parallel()
+
+	srv := New(clubTestQuerier(time.Now().UTC()))
+
+	// The only club is used when none is given
+	_, output, err := srv.getClubActivitySummary(context.Background(), nil, GetClubActivitySummaryInput{Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Totals.Activities != 4 || output.Totals.Members != 2 || output.Totals.Distance != "68.00 km" || output.Totals.Backfill != 1 {
+		t.Errorf("unexpected totals: %+v", output.Totals)
+	}
+	if len(output.Leaderboard) != 1 || output.Leaderboard[0].Athlete != "Ben K." || output.Leaderboard[0].Distance != "38.00 km" {
+		t.Errorf("expected Ben K. leading with the backfill counted, got %+v", output.Leaderboard)
+	}
+	var weekly int64
+	for _, w := range output.Weekly {
+		weekly += w.Activities
+	}
+	if weekly != 3 {
+		t.Errorf("expected the backfill left out of the weekly totals, got %+v", output.Weekly)
+	}
+	if len(output.ByType) != 1 || output.ByType[0].Type != "Run" || output.ByType[0].Activities != 4 {
+		t.Errorf("unexpected type totals: %+v", output.ByType)
+	}
+	var messages []string
+	for _, insight := range output.Insights {
+		messages = append(messages, insight.Message)
+	}
+	if joined := strings.Join(messages, "\n"); !strings.Contains(joined, "Ben K. leads") || !strings.Contains(joined, "1 of these activities were already in the feed") {
+		t.Errorf("expected leader and backfill insights, got %v", messages)
+	}
+
+	var toolErr *ToolError
+	if _, _, err := srv.getClubActivitySummary(context.Background(), nil, GetClubActivitySummaryInput{ClubID: 7}); !errors.As(err, &toolErr) || toolErr.Code != ErrNotFound {
+		t.Errorf("expected a missing club error, got %v", err)
+	}
+	if _, _, err := New(&MockQuerier{}).getClubActivitySummary(context.Background(), nil, GetClubActivitySummaryInput{}); err == nil {
+		t.Error("expected an error with no clubs")
+	}
+}
+
+func TestClubPeriodStart(t *testing.T) {
+	t.Parallel()
+
+	// Sunday 18 October 2026, the last day of its week
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)
+	if got := clubPeriodStart(now, 1).Format("2006-01-02"); got != "2026-10-12" {
+		t.Errorf("expected this week's Monday, got %s", got)
+	}
+	if got := clubPeriodStart(now, 4).Format("2006-01-02"); got != "2026-09-21" {
+		t.Errorf("expected the Monday three weeks earlier, got %s", got)
+	}
+}
@@ -0,0 +1,408 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// CompareActivitiesQuerier defines the interface for activity comparison queries
+type CompareActivitiesQuerier interface {
+	GetActivity(ctx context.Context, id int64) (db.Activity, error)
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+}
+
+// Split distance limits in meters
+const (
+	defaultSplitDistance = 1000
+	minSplitDistance     = 100
+)
+
+// gapSamples is the number of points the running gap is sampled at
+const gapSamples = 200
+
+// Input types
+
+// CompareActivitiesInput - input for comparing two specific activities
+type CompareActivitiesInput struct {
+	ActivityID    int64   `json:"activity_id" jsonschema:"The activity to analyze, usually the most recent effort."`
+	CompareToID   int64   `json:"compare_to_id" jsonschema:"The activity to race against as a virtual partner, e.g. last year's edition of the same race."`
+	SplitDistance float64 `json:"split_distance,omitempty" jsonschema:"Split length in meters. Default: 1000. Minimum: 100."`
+	IncludeChart  bool    `json:"include_chart,omitempty" jsonschema:"When true, also return a line chart of the running time gap over distance as image content."`
+	ChartFormat   string  `json:"chart_format,omitempty" jsonschema:"Chart image format when include_chart is set. Valid values: 'png', 'svg'. Default: png."`
+}
+
+// Output types
+
+type CompareActivitiesOutput struct {
+	Activity         ComparedActivity `json:"activity"`
+	CompareTo        ComparedActivity `json:"compare_to"`
+	ComparedDistance string           `json:"compared_distance"`
+	// GapSeconds is how far activity is ahead of compare_to at the end of
+	// the compared distance; negative when behind
+	GapSeconds       float64           `json:"gap_seconds"`
+	Gap              string            `json:"gap"`
+	MaxLead          *GapPoint         `json:"max_lead,omitempty"`
+	MaxDeficit       *GapPoint         `json:"max_deficit,omitempty"`
+	LeadChanges      int               `json:"lead_changes"`
+	BiggestGain      *SplitComparison  `json:"biggest_gain,omitempty"`
+	BiggestLoss      *SplitComparison  `json:"biggest_loss,omitempty"`
+	Splits           []SplitComparison `json:"splits"`
+	Insights         []Insight         `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions,omitempty"`
+}
+
+// ComparedActivity identifies one side of the comparison
+type ComparedActivity struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Date     string `json:"date"`
+	Distance string `json:"distance"`
+}
+
+// GapPoint is the gap at a point along the course
+type GapPoint struct {
+	Distance   string  `json:"distance"`
+	GapSeconds float64 `json:"gap_seconds"`
+}
+
+// SplitComparison compares one split of both activities
+type SplitComparison struct {
+	Split    int    `json:"split"`
+	Distance string `json:"distance"` // distance at the end of the split
+	Time     string `json:"time"`
+	// CompareToTime is the same split in the compare_to activity
+	CompareToTime string `json:"compare_to_time"`
+	// Difference is the split time saved (positive) or lost (negative) in seconds
+	Difference         float64 `json:"difference_seconds"`
+	Pace               string  `json:"pace"`
+	CompareToPace      string  `json:"compare_to_pace"`
+	Heartrate          float64 `json:"heartrate,omitempty"`
+	CompareToHeartrate float64 `json:"compare_to_heartrate,omitempty"`
+	Watts              float64 `json:"watts,omitempty"`
+	CompareToWatts     float64 `json:"compare_to_watts,omitempty"`
+	// GapSeconds is the running gap at the end of the split
+	GapSeconds float64 `json:"gap_seconds"`
+}
+
+// registerCompareActivitiesTools registers the activity-vs-activity comparison tool
+func (s *Server) registerCompareActivitiesTools() {
+	logging.Debug("Registering tool", "name", "compare_activities")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "compare_activities",
+		Description: `Race one activity against another as a virtual partner. Both are aligned by distance using their synced time/distance streams, so the comparison holds even when pauses or GPS sampling differ.
+
+Use when:
+- User asks "How did today's race compare to last year's?" or "Where did I lose time against my PR run?"
+- User reruns a course and wants to know where they gained or lost
+- User asks for split-by-split differences between two specific activities
+
+Parameters:
+- activity_id (int, required): The effort to analyze
+- compare_to_id (int, required): The effort to race against
+- split_distance (number): Split length in meters (default 1000)
+- include_chart (bool): Attach a chart of the running gap over distance
+
+Returns: The running time gap at the end (positive when activity_id is ahead), the biggest lead and deficit and where they happened, how often the lead changed, the split that gained and the split that lost the most, and every split with both times, paces, average HR and power. Times are elapsed, so stops count. Only the distance both activities covered is compared.
+
+Example: {"activity_id": 12345678, "compare_to_id": 11223344}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Compare Activities",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.compareActivities)
+}
+
+// compareActivities aligns two activities by distance and compares them
+func (s *Server) compareActivities(ctx context.Context, req *mcp.CallToolRequest, input CompareActivitiesInput) (*mcp.CallToolResult, CompareActivitiesOutput, error) {
+	logging.Info("MCP tool call", "tool", "compare_activities", "activity_id", input.ActivityID, "compare_to_id", input.CompareToID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "compare_activities", "input", logging.ToJSON(input))
+	}
+
+	if input.ActivityID <= 0 || input.CompareToID <= 0 {
+		return nil, CompareActivitiesOutput{}, NewInvalidInputErrorWithDetails("activity_id and compare_to_id are required", "pass the Strava IDs of the two activities")
+	}
+	if input.ActivityID == input.CompareToID {
+		return nil, CompareActivitiesOutput{}, NewInvalidInputError("activity_id and compare_to_id must be different activities")
+	}
+	chartFormat, err := parseChartFormat(input.IncludeChart, input.ChartFormat)
+	if err != nil {
+		return nil, CompareActivitiesOutput{}, err
+	}
+
+	splitDistance := input.SplitDistance
+	if splitDistance <= 0 {
+		splitDistance = defaultSplitDistance
+	}
+	if splitDistance < minSplitDistance {
+		splitDistance = minSplitDistance
+	}
+
+	queries := s.queries.(CompareActivitiesQuerier)
+	activity, activityStreams, err := loadComparedActivity(ctx, queries, input.ActivityID)
+	if err != nil {
+		return nil, CompareActivitiesOutput{}, err
+	}
+	compareTo, compareToStreams, err := loadComparedActivity(ctx, queries, input.CompareToID)
+	if err != nil {
+		return nil, CompareActivitiesOutput{}, err
+	}
+
+	distance := math.Min(activityStreams.TotalDistance(), compareToStreams.TotalDistance())
+	if distance <= 0 {
+		return nil, CompareActivitiesOutput{}, NewInvalidInputError("activities have no distance to compare")
+	}
+
+	output := CompareActivitiesOutput{
+		Activity:         comparedActivity(activity),
+		CompareTo:        comparedActivity(compareTo),
+		ComparedDistance: formatDistance(distance),
+		Splits:           compareSplits(activityStreams, compareToStreams, distance, splitDistance),
+	}
+
+	gaps := gapProfile(activityStreams, compareToStreams, distance)
+	output.GapSeconds = math.Round(gaps[len(gaps)-1].gap)
+	output.Gap = formatGap(output.GapSeconds)
+	output.MaxLead, output.MaxDeficit, output.LeadChanges = gapExtremes(gaps)
+
+	for i := range output.Splits {
+		split := &output.Splits[i]
+		if split.Difference > 0 && (output.BiggestGain == nil || split.Difference > output.BiggestGain.Difference) {
+			output.BiggestGain = split
+		}
+		if split.Difference < 0 && (output.BiggestLoss == nil || split.Difference < output.BiggestLoss.Difference) {
+			output.BiggestLoss = split
+		}
+	}
+
+	output.Insights = compareActivitiesInsights(output, activityStreams, compareToStreams, distance)
+	output.SuggestedActions = SuggestNextActions("compare_activities")
+
+	var result *mcp.CallToolResult
+	if input.IncludeChart {
+		result = chartResult("compare_activities", output, gapChart(gaps, output.CompareTo.Date), chartFormat)
+	}
+
+	logging.Info("MCP tool completed", "tool", "compare_activities", "splits", len(output.Splits), "gap_seconds", output.GapSeconds)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "compare_activities", "output", logging.ToJSON(output))
+	}
+	return result, output, nil
+}
+
+// loadComparedActivity fetches an activity and its streams, which must
+// include time and distance
+func loadComparedActivity(ctx context.Context, queries CompareActivitiesQuerier, id int64) (db.Activity, *streams.Streams, error) {
+	activity, err := queries.GetActivity(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Activity{}, nil, NewNotFoundErrorWithID("activity", id)
+		}
+		return db.Activity{}, nil, NewDatabaseError(err)
+	}
+
+	noStreams := NewInvalidInputErrorWithDetails(
+		fmt.Sprintf("activity %d has no time/distance streams", id),
+		"streams are synced in the background; indoor activities without distance can't be compared")
+	row, err := queries.GetActivityStreams(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Activity{}, nil, noStreams
+		}
+		return db.Activity{}, nil, NewDatabaseError(err)
+	}
+	st, err := streams.FromRow(row)
+	if err != nil {
+		return db.Activity{}, nil, NewInternalErrorWithCause("failed to decode activity streams", err)
+	}
+	if !st.CanAlign() {
+		return db.Activity{}, nil, noStreams
+	}
+	return activity, st, nil
+}
+
+func comparedActivity(a db.Activity) ComparedActivity {
+	return ComparedActivity{
+		ID:       a.ID,
+		Name:     a.Name,
+		Type:     a.Type.String,
+		Date:     a.StartDate.Time.Format("2006-01-02"),
+		Distance: formatDistance(a.Distance.Float64),
+	}
+}
+
+// compareSplits compares consecutive splits up to distance; a final partial
+// split is included when it is at least a fifth of a full one
+func compareSplits(a, b *streams.Streams, distance, splitDistance float64) []SplitComparison {
+	splits := []SplitComparison{}
+	for start := 0.0; start < distance; start += splitDistance {
+		end := math.Min(start+splitDistance, distance)
+		if end-start < splitDistance/5 && start > 0 {
+			break
+		}
+		segA, okA := a.Between(start, end)
+		segB, okB := b.Between(start, end)
+		if !okA || !okB {
+			break
+		}
+		timeA, _ := a.TimeAt(end)
+		timeB, _ := b.TimeAt(end)
+
+		length := end - start
+		splits = append(splits, SplitComparison{
+			Split:              len(splits) + 1,
+			Distance:           formatDistance(end),
+			Time:               formatDuration(int64(math.Round(segA.Time))),
+			CompareToTime:      formatDuration(int64(math.Round(segB.Time))),
+			Difference:         math.Round(segB.Time - segA.Time),
+			Pace:               formatPace(length / segA.Time),
+			CompareToPace:      formatPace(length / segB.Time),
+			Heartrate:          roundOrZero(segA.Heartrate),
+			CompareToHeartrate: roundOrZero(segB.Heartrate),
+			Watts:              roundOrZero(segA.Watts),
+			CompareToWatts:     roundOrZero(segB.Watts),
+			GapSeconds:         math.Round(timeB - timeA),
+		})
+	}
+	return splits
+}
+
+// gapSample is the running gap at a distance, positive when ahead
+type gapSample struct {
+	distance float64
+	gap      float64
+}
+
+// gapProfile samples the running gap evenly over the compared distance
+func gapProfile(a, b *streams.Streams, distance float64) []gapSample {
+	samples := make([]gapSample, 0, gapSamples+1)
+	for i := 0; i <= gapSamples; i++ {
+		d := distance * float64(i) / gapSamples
+		timeA, okA := a.TimeAt(d)
+		timeB, okB := b.TimeAt(d)
+		if !okA || !okB {
+			continue
+		}
+		samples = append(samples, gapSample{distance: d, gap: timeB - timeA})
+	}
+	return samples
+}
+
+// gapExtremes finds the biggest lead and deficit and counts lead changes,
+// ignoring swings of under a second
+func gapExtremes(gaps []gapSample) (*GapPoint, *GapPoint, int) {
+	var lead, deficit *gapSample
+	changes, sign := 0, 0
+	for i, g := range gaps {
+		if g.gap >= 1 && (lead == nil || g.gap > lead.gap) {
+			lead = &gaps[i]
+		}
+		if g.gap <= -1 && (deficit == nil || g.gap < deficit.gap) {
+			deficit = &gaps[i]
+		}
+
+		s := 0
+		if g.gap >= 1 {
+			s = 1
+		} else if g.gap <= -1 {
+			s = -1
+		}
+		if s != 0 {
+			if sign != 0 && s != sign {
+				changes++
+			}
+			sign = s
+		}
+	}
+	return lead.point(), deficit.point(), changes
+}
+
+func (g *gapSample) point() *GapPoint {
+	if g == nil {
+		return nil
+	}
+	return &GapPoint{Distance: formatDistance(g.distance), GapSeconds: math.Round(g.gap)}
+}
+
+// formatGap prints a gap in seconds as "+1m 5s" (ahead) or "-12s" (behind)
+func formatGap(seconds float64) string {
+	if seconds == 0 {
+		return "even"
+	}
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+	}
+	return sign + formatDuration(int64(math.Abs(seconds)))
+}
+
+func roundOrZero(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Round(v)
+}
+
+func compareActivitiesInsights(output CompareActivitiesOutput, a, b *streams.Streams, distance float64) []Insight {
+	var insights []Insight
+
+	switch {
+	case output.GapSeconds > 0:
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("You finished %s ahead of your %s effort over %s.", formatDuration(int64(output.GapSeconds)), output.CompareTo.Date, output.ComparedDistance),
+		})
+	case output.GapSeconds < 0:
+		insights = append(insights, Insight{
+			Type:    "trend",
+			Message: fmt.Sprintf("You finished %s behind your %s effort over %s.", formatDuration(int64(-output.GapSeconds)), output.CompareTo.Date, output.ComparedDistance),
+		})
+	default:
+		insights = append(insights, Insight{
+			Type:    "trend",
+			Message: fmt.Sprintf("Dead even with your %s effort over %s.", output.CompareTo.Date, output.ComparedDistance),
+		})
+	}
+
+	if output.BiggestGain != nil {
+		insights = append(insights, Insight{
+			Type:    "trend",
+			Message: fmt.Sprintf("Biggest gain: split %d (to %s), %s faster.", output.BiggestGain.Split, output.BiggestGain.Distance, formatDuration(int64(output.BiggestGain.Difference))),
+		})
+	}
+	if output.BiggestLoss != nil {
+		insights = append(insights, Insight{
+			Type:    "warning",
+			Message: fmt.Sprintf("Biggest loss: split %d (to %s), %s slower.", output.BiggestLoss.Split, output.BiggestLoss.Distance, formatDuration(int64(-output.BiggestLoss.Difference))),
+		})
+	}
+
+	hrA, _ := a.Between(0, distance)
+	hrB, _ := b.Between(0, distance)
+	if !math.IsNaN(hrA.Heartrate) && !math.IsNaN(hrB.Heartrate) && output.GapSeconds > 0 && hrA.Heartrate < hrB.Heartrate {
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("Faster at a lower average heart rate (%.0f vs %.0f bpm), a sign of improved fitness.", hrA.Heartrate, hrB.Heartrate),
+		})
+	}
+
+	longer := math.Max(a.TotalDistance(), b.TotalDistance())
+	if longer > distance*1.05 {
+		insights = append(insights, Insight{
+			Type:    "warning",
+			Message: fmt.Sprintf("The activities cover different distances; only the first %s is compared.", output.ComparedDistance),
+		})
+	}
+	return insights
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+// paceStream builds a stream sampled every 100 m with the given seconds per
+// km for each kilometer and a constant heart rate
+func paceStream(id int64, paces []float64, heartrate float64) db.ActivityStream {
+	times, distances, hr := []int{0}, []float64{0}, []float64{heartrate}
+	elapsed := 0.0
+	for _, pace := range paces {
+		for i := 0; i < 10; i++ {
+			elapsed += pace / 10
+			times = append(times, int(elapsed))
+			distances = append(distances, distances[len(distances)-1]+100)
+			hr = append(hr, heartrate)
+		}
+	}
+	encode := func(v any) sql.NullString {
+		b, _ := json.Marshal(v)
+		return sql.NullString{String: string(b), Valid: true}
+	}
+	return db.ActivityStream{
+		ActivityID:    id,
+		PointCount:    int64(len(times)),
+		TimeData:      encode(times),
+		DistanceData:  encode(distances),
+		HeartrateData: encode(hr),
+	}
+}
+
+func compareTestQuerier() *MockQuerier {
+	today := createTestActivity(10, "Parkrun", "Run", time.Date(2024, 6, 8, 9, 0, 0, 0, time.UTC))
+	lastYear := createTestActivity(11, "Parkrun", "Run", time.Date(2023, 6, 10, 9, 0, 0, 0, time.UTC))
+	indoor := createTestActivity(12, "Treadmill", "Run", time.Date(2024, 6, 9, 9, 0, 0, 0, time.UTC))
+	return &MockQuerier{
+		activities: []db.Activity{today, lastYear, indoor},
+		streams: map[int64]db.ActivityStream{
+			// Even 5:00/km against a fast start that faded
+			10: paceStream(10, []float64{300, 300, 300}, 150),
+			11: paceStream(11, []float64{280, 320, 320}, 160),
+			12: {ActivityID: 12},
+		},
+	}
+}
+
+func TestCompareActivities(t *testing.T) {
+	t.Parallel()
+
+	srv := New(compareTestQuerier())
+
+	result, output, err := srv.compareActivities(context.Background(), nil, CompareActivitiesInput{ActivityID: 10, CompareToID: 11})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != nil {
+		t.Error("expected no image content without include_chart")
+	}
+	if output.GapSeconds != 20 || output.Gap != "+20s" {
+		t.Errorf("expected to finish 20s ahead, got %v (%s)", output.GapSeconds, output.Gap)
+	}
+	if len(output.Splits) != 3 {
+		t.Fatalf("expected 3 splits, got %d", len(output.Splits))
+	}
+	if output.Splits[0].Difference != -20 || output.Splits[1].Difference != 20 {
+		t.Errorf("unexpected split differences %+v", output.Splits)
+	}
+	if output.Splits[0].Pace != "5:00/km" || output.Splits[0].CompareToPace != "4:40/km" {
+		t.Errorf("unexpected split paces %s vs %s", output.Splits[0].Pace, output.Splits[0].CompareToPace)
+	}
+	if output.Splits[0].Heartrate != 150 || output.Splits[0].CompareToHeartrate != 160 {
+		t.Errorf("unexpected split heart rates %+v", output.Splits[0])
+	}
+	if output.MaxDeficit == nil || output.MaxDeficit.GapSeconds != -20 || output.MaxDeficit.Distance != "1.00 km" {
+		t.Errorf("expected the biggest deficit at 1 km, got %+v", output.MaxDeficit)
+	}
+	if output.LeadChanges != 1 {
+		t.Errorf("expected 1 lead change, got %d", output.LeadChanges)
+	}
+	if output.BiggestGain == nil || output.BiggestGain.Split != 2 || output.BiggestLoss == nil || output.BiggestLoss.Split != 1 {
+		t.Errorf("expected gain in split 2 and loss in split 1, got %+v / %+v", output.BiggestGain, output.BiggestLoss)
+	}
+
+	lowerHR := false
+	for _, insight := range output.Insights {
+		if insight.Type == "achievement" && containsSubstring(insight.Message, "lower average heart rate") {
+			lowerHR = true
+		}
+	}
+	if !lowerHR {
+		t.Errorf("expected a lower heart rate insight, got %+v", output.Insights)
+	}
+}
+
+func TestCompareActivitiesChart(t *testing.T) {
+	t.Parallel()
+
+	srv := New(compareTestQuerier())
+
+	result, _, err := srv.compareActivities(context.Background(), nil, CompareActivitiesInput{
+		ActivityID: 10, CompareToID: 11, SplitDistance: 500, IncludeChart: true, ChartFormat: "svg",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if img := imageContent(t, result); img.MIMEType != "image/svg+xml" {
+		t.Errorf("expected SVG chart, got %s", img.MIMEType)
+	}
+}
+
+func TestCompareActivitiesErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := New(compareTestQuerier())
+
+	tests := []struct {
+		name  string
+		input CompareActivitiesInput
+	}{
+		{"missing id", CompareActivitiesInput{ActivityID: 10}},
+		{"same activity", CompareActivitiesInput{ActivityID: 10, CompareToID: 10}},
+		{"unknown activity", CompareActivitiesInput{ActivityID: 10, CompareToID: 99}},
+		{"no streams", CompareActivitiesInput{ActivityID: 10, CompareToID: 12}},
+		{"streams not synced", CompareActivitiesInput{ActivityID: 1, CompareToID: 10}},
+	}
+	for _, tt := range tests {
+		if _, _, err := srv.compareActivities(context.Background(), nil, tt.input); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestGapExtremes(t *testing.T) {
+	t.Parallel()
+
+	lead, deficit, changes := gapExtremes([]gapSample{
+		{0, 0}, {100, 3}, {200, 0.5}, {300, -4}, {400, -2}, {500, 6},
+	})
+	if lead == nil || lead.GapSeconds != 6 || deficit == nil || deficit.GapSeconds != -4 {
+		t.Errorf("unexpected extremes %+v / %+v", lead, deficit)
+	}
+	if changes != 2 {
+		t.Errorf("expected 2 lead changes, got %d", changes)
+	}
+}
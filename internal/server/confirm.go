@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// confirmTTL is how long a confirm token stays valid
+const confirmTTL = 10 * time.Minute
+
+// Change is one value a tool is about to change. From is empty for values
+// being added and To for values being removed.
+type Change struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// Confirmation is returned instead of making a change when the client cannot
+// ask the user directly. Calling the tool again with the same parameters and
+// the confirm token makes the change.
+type Confirmation struct {
+	Token     string   `json:"confirm_token"`
+	ExpiresAt string   `json:"expires_at"`
+	Action    string   `json:"action"`
+	Changes   []Change `json:"changes"`
+	Message   string   `json:"message"`
+}
+
+// pendingConfirmation is a confirm token waiting to be used
+type pendingConfirmation struct {
+	tool        string
+	fingerprint string
+	expires     time.Time
+}
+
+// confirmations holds the confirm tokens handed out, each used once
+type confirmations struct {
+	mu      sync.Mutex
+	pending map[string]pendingConfirmation
+}
+
+// confirm asks the user to approve a change before a tool makes it, and
+// returns nil, nil once they have. Clients that support elicitation ask the
+// user with the changes listed; declining returns a cancelled error. Other
+// clients get a Confirmation back, and the change goes ahead when the tool
+// is called again with the same input and its token.
+func (s *Server) confirm(ctx context.Context, req *mcp.CallToolRequest, tool, action string, changes []Change, input any, token string) (*Confirmation, error) {
+	fingerprint, err := confirmFingerprint(input)
+	if err != nil {
+		return nil, NewInternalErrorWithCause("Failed to prepare confirmation", err)
+	}
+
+	if token != "" {
+		if !s.confirmations.use(token, tool, fingerprint, time.Now()) {
+			return nil, NewInvalidInputErrorWithDetails(
+				"Invalid or expired confirm_token",
+				"Call the tool again without confirm_token for a new one; the other parameters must stay the same")
+		}
+		logging.Info("Change confirmed with token", "tool", tool)
+		return nil, nil
+	}
+
+	if canElicit(req) {
+		result, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+			Message:         confirmMessage(action, changes),
+			RequestedSchema: map[string]any{"type": "object", "properties": map[string]any{}},
+		})
+		if err == nil {
+			logging.Info("Change confirmation answered", "tool", tool, "action", result.Action)
+			if result.Action != "accept" {
+				return nil, NewCancelledError(action)
+			}
+			return nil, nil
+		}
+		// Fall back to a token when the client fails to ask
+		logging.Warn("Elicitation failed, falling back to a confirm token", "tool", tool, "error", err)
+	}
+
+	now := time.Now()
+	token, err = s.confirmations.issue(tool, fingerprint, now)
+	if err != nil {
+		return nil, NewInternalErrorWithCause("Failed to create confirm token", err)
+	}
+	return &Confirmation{
+		Token:     token,
+		ExpiresAt: now.Add(confirmTTL).Format(time.RFC3339),
+		Action:    action,
+		Changes:   changes,
+		Message: fmt.Sprintf("Nothing has been changed yet. Show the user the changes and, once they agree, call %s again with the same parameters and confirm_token %q within %d minutes.",
+			tool, token, int(confirmTTL.Minutes())),
+	}, nil
+}
+
+// canElicit reports whether the client can be asked to confirm a change
+func canElicit(req *mcp.CallToolRequest) bool {
+	if req == nil || req.Session == nil {
+		return false
+	}
+	params := req.Session.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Elicitation != nil
+}
+
+// confirmMessage describes a change for the user, one line per value
+func confirmMessage(action string, changes []Change) string {
+	var b strings.Builder
+	b.WriteString(action)
+	b.WriteString("?")
+	for _, c := range changes {
+		switch {
+		case c.From == "":
+			fmt.Fprintf(&b, "\n+ %s: %s", c.Field, c.To)
+		case c.To == "":
+			fmt.Fprintf(&b, "\n- %s: %s", c.Field, c.From)
+		default:
+			fmt.Fprintf(&b, "\n~ %s: %s → %s", c.Field, c.From, c.To)
+		}
+	}
+	return b.String()
+}
+
+// confirmFingerprint identifies a tool input apart from its confirm token, so
+// a token only confirms the change it was issued for
+func confirmFingerprint(input any) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	delete(fields, "confirm_token")
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// issue creates a token for a change, dropping expired ones
+func (c *confirmations) issue(tool, fingerprint string, now time.Time) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil {
+		c.pending = make(map[string]pendingConfirmation)
+	}
+	for t, p := range c.pending {
+		if now.After(p.expires) {
+			delete(c.pending, t)
+		}
+	}
+	c.pending[token] = pendingConfirmation{tool: tool, fingerprint: fingerprint, expires: now.Add(confirmTTL)}
+	return token, nil
+}
+
+// use reports whether a token confirms the change, and uses it up
+func (c *confirmations) use(token, tool, fingerprint string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.pending[token]
+	if !ok || p.tool != tool || p.fingerprint != fingerprint || now.After(p.expires) {
+		return false
+	}
+	delete(c.pending, token)
+	return true
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestConfirmToken(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{})
+	ctx := context.Background()
+	input := RemoveRaceInput{RaceID: 1}
+	changes := []Change{{Field: "name", From: "Tune-up 10k"}}
+
+	confirmation, err := srv.confirm(ctx, nil, "remove_race", "Remove the race", changes, input, "")
+	if err != nil || confirmation == nil || confirmation.Token == "" {
+		t.Fatalf("expected a confirm token, got %+v, %v", confirmation, err)
+	}
+	token := confirmation.Token
+
+	// Only for the same tool and input
+	if _, err := srv.confirm(ctx, nil, "remove_race", "Remove the race", changes, RemoveRaceInput{RaceID: 2, ConfirmToken: token}, token); err == nil {
+		t.Error("expected the token rejected for a different race")
+	}
+	if _, err := srv.confirm(ctx, nil, "update_activity_local", "Reset", changes, input, token); err == nil {
+		t.Error("expected the token rejected for another tool")
+	}
+
+	input.ConfirmToken = token
+	confirmation, err = srv.confirm(ctx, nil, "remove_race", "Remove the race", changes, input, token)
+	if err != nil || confirmation != nil {
+		t.Fatalf("expected the change confirmed, got %+v, %v", confirmation, err)
+	}
+	if _, err := srv.confirm(ctx, nil, "remove_race", "Remove the race", changes, input, token); err == nil {
+		t.Error("expected the token used up")
+	}
+
+	var c confirmations
+	token, _ = c.issue("remove_race", "abc", time.Now())
+	if c.use(token, "remove_race", "abc", time.Now().Add(confirmTTL+time.Second)) {
+		t.Error("expected an expired token rejected")
+	}
+}
+
+func TestConfirmMessage(t *testing.T) {
+	t.Parallel()
+
+	message := confirmMessage("Change activity 1 on Strava", []Change{
+		{Field: "name", From: "Morning Run", To: "Intervals"},
+		{Field: "commute", To: "true"},
+		{Field: "race", From: "Tune-up 10k"},
+	})
+	want := "Change activity 1 on Strava?\n~ name: Morning Run → Intervals\n+ commute: true\n- race: Tune-up 10k"
+	if message != want {
+		t.Errorf("confirmMessage() = %q, want %q", message, want)
+	}
+}
+
+// elicitingClient connects a client that answers confirmations with action
+func elicitingClient(t *testing.T, srv *Server, action string, asked *string) *mcp.ClientSession {
+	t.Helper()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ClientOptions{
+		ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			*asked = req.Params.Message
+			return &mcp.ElicitResult{Action: action}, nil
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := srv.MCPServer().Connect(context.Background(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestConfirmElicitation(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		action  string
+		removed bool
+	}{
+		{action: "accept", removed: true},
+		{action: "decline", removed: false},
+	} {
+		m := &MockQuerier{races: []db.Race{{ID: 1, Name: "Tune-up 10k", RaceDate: time.Now().AddDate(0, 0, 20), Distance: 10000, ActivityType: "Run", Priority: "C"}}}
+		var asked string
+		session := elicitingClient(t, New(m), tt.action, &asked)
+
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "remove_race", Arguments: map[string]any{"race_id": 1}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(asked, "- name: Tune-up 10k") {
+			t.Errorf("expected the user asked with the changes, got %q", asked)
+		}
+		if removed := len(m.races) == 0; removed != tt.removed || result.IsError == tt.removed {
+			t.Errorf("%s: expected removed=%v, got %d races left, error=%v", tt.action, tt.removed, len(m.races), result.IsError)
+		}
+	}
+}
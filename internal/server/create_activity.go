@@ -0,0 +1,487 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+	syncsvc "github.com/joshdurbin/strava-mcp/internal/sync"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Upload settings
+const (
+	// maxUploadBytes is Strava's limit on activity files
+	maxUploadBytes     = 25 << 20
+	defaultUploadWait  = 60
+	maxUploadWait      = 120
+	uploadPollInterval = 2 * time.Second
+)
+
+// uploadDataTypes are the file formats Strava accepts
+var uploadDataTypes = []string{"fit", "fit.gz", "tcx", "tcx.gz", "gpx", "gpx.gz"}
+
+// Input types
+
+// CreateManualActivityInput - input for logging an activity without a file
+type CreateManualActivityInput struct {
+	Name           string  `json:"name" jsonschema:"Activity name, e.g. 'Pool swim'."`
+	SportType      string  `json:"sport_type" jsonschema:"Sport type; the activity type follows it. Common values: Run, TrailRun, Ride, VirtualRide, Swim, Walk, Hike, WeightTraining, Yoga, Workout."`
+	StartDateLocal string  `json:"start_date_local" jsonschema:"Local start time, YYYY-MM-DDTHH:MM or YYYY-MM-DD HH:MM, e.g. '2026-10-17T07:30'."`
+	Duration       string  `json:"duration" jsonschema:"Elapsed time, h:mm:ss or mm:ss, e.g. '45:00'."`
+	DistanceKm     float64 `json:"distance_km,omitempty" jsonschema:"Distance in kilometers. Leave empty for activities without one, e.g. strength training."`
+	Description    string  `json:"description,omitempty" jsonschema:"Optional description."`
+	Trainer        bool    `json:"trainer,omitempty" jsonschema:"When true, mark as done on a trainer or treadmill."`
+	Commute        bool    `json:"commute,omitempty" jsonschema:"When true, mark as a commute."`
+	ConfirmToken   string  `json:"confirm_token,omitempty" jsonschema:"Token from an earlier call's confirmation, once the user has agreed to the change."`
+}
+
+// UploadActivityFileInput - input for uploading a FIT, TCX or GPX file
+type UploadActivityFileInput struct {
+	FilePath      string `json:"file_path,omitempty" jsonschema:"Path to a .fit, .tcx or .gpx file, optionally gzipped, on the machine running the server."`
+	ContentBase64 string `json:"content_base64,omitempty" jsonschema:"The file contents, base64 encoded, in place of file_path. Requires data_type."`
+	DataType      string `json:"data_type,omitempty" jsonschema:"Valid values: fit, fit.gz, tcx, tcx.gz, gpx, gpx.gz. Default: from the file extension."`
+	Name          string `json:"name,omitempty" jsonschema:"Activity name. Default: from the file, or Strava's default."`
+	Description   string `json:"description,omitempty" jsonschema:"Optional description."`
+	Trainer       bool   `json:"trainer,omitempty" jsonschema:"When true, mark as done on a trainer or treadmill."`
+	Commute       bool   `json:"commute,omitempty" jsonschema:"When true, mark as a commute."`
+	ExternalID    string `json:"external_id,omitempty" jsonschema:"Optional identifier of the file in another system."`
+	UploadID      int64  `json:"upload_id,omitempty" jsonschema:"Check on an earlier upload that was still processing instead of uploading a file."`
+	WaitSeconds   int    `json:"wait_seconds,omitempty" jsonschema:"How long to wait for Strava to process the file. Default: 60, maximum: 120."`
+	ConfirmToken  string `json:"confirm_token,omitempty" jsonschema:"Token from an earlier call's confirmation, once the user has agreed to the upload."`
+}
+
+// Output types
+
+type CreateManualActivityOutput struct {
+	Activity *ActivitySummary `json:"activity,omitempty"`
+	Message  string           `json:"message"`
+	// Confirmation is set instead when the change still needs the user's approval
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+type UploadActivityFileOutput struct {
+	UploadID int64 `json:"upload_id,omitempty"`
+	// Status is "created", "processing" or "awaiting_confirmation"
+	Status   string           `json:"status"`
+	Activity *ActivitySummary `json:"activity,omitempty"`
+	Message  string           `json:"message"`
+	// Confirmation is set when the upload still needs the user's approval
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+// registerCreateActivityTools registers the tools that add activities to Strava
+func (s *Server) registerCreateActivityTools() {
+	logging.Debug("Registering tool", "name", "create_manual_activity")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "create_manual_activity",
+		Description: `Log an activity on Strava without a recorded file, such as a swim, gym session or a run the watch missed. It is added to the local data straight away.
+
+Use when:
+- User asks to log a workout, e.g. "Add a 45 minute swim from this morning"
+- User forgot to record an activity and wants it counted
+- User wants to log strength training, yoga or other activities without GPS
+
+Parameters:
+- name (string): Required. Activity name
+- sport_type (string): Required. Run, Ride, Swim, WeightTraining, etc.
+- start_date_local (string): Required. Local start time, YYYY-MM-DDTHH:MM
+- duration (string): Required. Elapsed time, h:mm:ss or mm:ss
+- distance_km (float): Distance in kilometers
+- description (string): Optional description
+- trainer (boolean): Done on a trainer or treadmill
+- commute (boolean): A commute
+- confirm_token (string): Token from an earlier confirmation, once the user agreed
+
+Returns: The new activity. The user is asked to confirm first; clients that cannot ask get a confirmation with a token to call again with. It is public on Strava per the athlete's privacy defaults. Needs the activity:write scope, granted by restarting with --force-reauth if the server was authorized before it was requested.
+
+Example: {"name": "Pool swim", "sport_type": "Swim", "start_date_local": "2026-10-17T07:30", "duration": "45:00", "distance_km": 2}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Create Manual Activity",
+			ReadOnlyHint:    false,
+			IdempotentHint:  false,
+			OpenWorldHint:   ptr(true),
+			DestructiveHint: ptr(false),
+		},
+	}, s.createManualActivity)
+
+	logging.Debug("Registering tool", "name", "upload_activity_file")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "upload_activity_file",
+		Description: `Upload a FIT, TCX or GPX file to Strava as a new activity and wait for Strava to process it. The activity is added to the local data straight away.
+
+Use when:
+- User has a file from a device or app that doesn't sync to Strava, e.g. "Upload ~/Downloads/ride.fit"
+- User exported an activity from another service
+- An earlier upload was still processing and the user wants to know if it finished (pass upload_id)
+
+Parameters:
+- file_path (string): Path to the file on the machine running the server
+- content_base64 (string): The file contents, base64 encoded, in place of file_path
+- data_type (string): fit, fit.gz, tcx, tcx.gz, gpx or gpx.gz (default: from the file extension)
+- name (string): Activity name
+- description (string): Optional description
+- trainer (boolean): Done on a trainer or treadmill
+- commute (boolean): A commute
+- external_id (string): Identifier of the file in another system
+- upload_id (int): Check on an earlier upload instead
+- wait_seconds (int): How long to wait for processing (default: 60, max: 120)
+- confirm_token (string): Token from an earlier confirmation, once the user agreed
+
+Returns: The new activity once Strava has processed the file, or the upload ID to check on later if it is still processing. The user is asked to confirm the upload first; clients that cannot ask get a confirmation with a token to call again with. Strava rejects files it already has as duplicates. Needs the activity:write scope.
+
+Example: {"file_path": "/home/me/Downloads/ride.fit", "name": "Zwift race"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Upload Activity File",
+			ReadOnlyHint:    false,
+			IdempotentHint:  false,
+			OpenWorldHint:   ptr(true),
+			DestructiveHint: ptr(false),
+		},
+	}, s.uploadActivityFile)
+}
+
+// createManualActivity creates an activity on Strava and stores it
+func (s *Server) createManualActivity(ctx context.Context, req *mcp.CallToolRequest, input CreateManualActivityInput) (*mcp.CallToolResult, CreateManualActivityOutput, error) {
+	logging.Info("MCP tool call", "tool", "create_manual_activity", "sport_type", input.SportType, "start_date_local", input.StartDateLocal)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "create_manual_activity", "input", logging.ToJSON(input))
+	}
+
+	manual, err := manualActivity(input)
+	if err != nil {
+		return nil, CreateManualActivityOutput{}, err
+	}
+
+	client, err := s.stravaClient()
+	if err != nil {
+		return nil, CreateManualActivityOutput{}, err
+	}
+
+	action := fmt.Sprintf("Create %s activity %q on Strava", manual.SportType, manual.Name)
+	confirmation, err := s.confirm(ctx, req, "create_manual_activity", action, manualChanges(manual), input, input.ConfirmToken)
+	if err != nil {
+		return nil, CreateManualActivityOutput{}, err
+	}
+	if confirmation != nil {
+		output := CreateManualActivityOutput{Message: "Waiting for the user to confirm the new activity", Confirmation: confirmation}
+		logging.Info("MCP tool completed", "tool", "create_manual_activity", "status", "awaiting_confirmation")
+		return nil, output, nil
+	}
+
+	created, err := client.CreateActivity(ctx, manual)
+	if err != nil {
+		return nil, CreateManualActivityOutput{}, NewStravaError(err)
+	}
+
+	activity, err := s.storeNewActivity(ctx, created)
+	if err != nil {
+		return nil, CreateManualActivityOutput{}, err
+	}
+	output := CreateManualActivityOutput{
+		Activity: &activity,
+		Message:  "Activity created on Strava and added locally",
+	}
+
+	logging.Info("MCP tool completed", "tool", "create_manual_activity", "activity_id", created.ID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "create_manual_activity", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// uploadActivityFile uploads a file to Strava, waits for it to be processed
+// and stores the new activity
+func (s *Server) uploadActivityFile(ctx context.Context, req *mcp.CallToolRequest, input UploadActivityFileInput) (*mcp.CallToolResult, UploadActivityFileOutput, error) {
+	logging.Info("MCP tool call", "tool", "upload_activity_file", "file_path", input.FilePath, "upload_id", input.UploadID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "upload_activity_file", "input", logging.ToJSON(input))
+	}
+
+	wait := input.WaitSeconds
+	if wait <= 0 {
+		wait = defaultUploadWait
+	}
+	if wait > maxUploadWait {
+		wait = maxUploadWait
+	}
+
+	var file strava.UploadFile
+	if input.UploadID == 0 {
+		var err error
+		if file, err = uploadFile(input); err != nil {
+			return nil, UploadActivityFileOutput{}, err
+		}
+	}
+
+	client, err := s.stravaClient()
+	if err != nil {
+		return nil, UploadActivityFileOutput{}, err
+	}
+
+	uploadID := input.UploadID
+	if uploadID == 0 {
+		action := fmt.Sprintf("Upload %s to Strava as a new activity", file.Filename)
+		confirmation, err := s.confirm(ctx, req, "upload_activity_file", action, uploadChanges(file), input, input.ConfirmToken)
+		if err != nil {
+			return nil, UploadActivityFileOutput{}, err
+		}
+		if confirmation != nil {
+			output := UploadActivityFileOutput{
+				Status:       "awaiting_confirmation",
+				Message:      "Waiting for the user to confirm the upload",
+				Confirmation: confirmation,
+			}
+			logging.Info("MCP tool completed", "tool", "upload_activity_file", "status", output.Status)
+			return nil, output, nil
+		}
+
+		upload, err := client.UploadActivity(ctx, file)
+		if err != nil {
+			return nil, UploadActivityFileOutput{}, NewStravaError(err)
+		}
+		uploadID = upload.ID
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Duration(wait)*time.Second)
+	defer cancel()
+	upload, err := client.WaitForUpload(waitCtx, uploadID, uploadPollInterval)
+	if errors.Is(err, strava.ErrUploadPending) {
+		output := UploadActivityFileOutput{
+			UploadID: uploadID,
+			Status:   "processing",
+			Message:  fmt.Sprintf("Strava is still processing the file; call again with upload_id %d to check on it", uploadID),
+		}
+		logging.Info("MCP tool completed", "tool", "upload_activity_file", "upload_id", uploadID, "status", output.Status)
+		return nil, output, nil
+	}
+	if err != nil {
+		return nil, UploadActivityFileOutput{}, NewStravaError(err)
+	}
+	if upload.Error != "" {
+		e := NewStravaError(errors.New(upload.Error))
+		e.Message = "Strava could not process the file"
+		return nil, UploadActivityFileOutput{}, e
+	}
+
+	created, err := client.FetchActivity(ctx, *upload.ActivityID)
+	if err != nil {
+		return nil, UploadActivityFileOutput{}, NewStravaError(err)
+	}
+	activity, err := s.storeNewActivity(ctx, created)
+	if err != nil {
+		return nil, UploadActivityFileOutput{}, err
+	}
+	output := UploadActivityFileOutput{
+		UploadID: uploadID,
+		Status:   "created",
+		Activity: &activity,
+		Message:  "Activity created on Strava and added locally",
+	}
+
+	logging.Info("MCP tool completed", "tool", "upload_activity_file", "upload_id", uploadID, "activity_id", created.ID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "upload_activity_file", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// storeNewActivity adds an activity just created on Strava to the local data,
+// ahead of the next sync
+func (s *Server) storeNewActivity(ctx context.Context, created *strava.Activity) (ActivitySummary, error) {
+	queries := s.queries.(StravaActivityQuerier)
+	if err := queries.CreateActivity(ctx, syncsvc.ConvertActivityToParams(*created)); err != nil {
+		return ActivitySummary{}, NewDatabaseErrorWithContext("saving the new activity", err)
+	}
+	if err := queries.UpsertActivitySocialCounts(ctx, syncsvc.ConvertSocialCountsToParams(*created)); err != nil {
+		return ActivitySummary{}, NewDatabaseErrorWithContext("saving the new activity", err)
+	}
+	activity, err := queries.GetActivity(ctx, created.ID)
+	if err != nil {
+		return ActivitySummary{}, NewDatabaseError(err)
+	}
+	return convertActivity(activity), nil
+}
+
+// manualActivity validates the input and builds the activity to create
+func manualActivity(input CreateManualActivityInput) (strava.ManualActivity, error) {
+	name := strings.TrimSpace(input.Name)
+	sportType := strings.TrimSpace(input.SportType)
+	if name == "" || sportType == "" {
+		return strava.ManualActivity{}, NewInvalidInputError("name and sport_type are required")
+	}
+	start, err := parseLocalStart(input.StartDateLocal)
+	if err != nil {
+		return strava.ManualActivity{}, NewInvalidInputErrorWithDetails(
+			"Invalid start_date_local", "Use YYYY-MM-DDTHH:MM, e.g. '2026-10-17T07:30'")
+	}
+	elapsed, err := parseGoalTime(strings.TrimSpace(input.Duration))
+	if err != nil {
+		return strava.ManualActivity{}, NewInvalidInputErrorWithDetails("Invalid duration", err.Error())
+	}
+	if input.DistanceKm < 0 {
+		return strava.ManualActivity{}, NewInvalidInputError("distance_km must not be negative")
+	}
+	return strava.ManualActivity{
+		Name:           name,
+		SportType:      sportType,
+		StartDateLocal: start,
+		ElapsedTime:    int(elapsed),
+		Description:    strings.TrimSpace(input.Description),
+		Distance:       input.DistanceKm * 1000,
+		Trainer:        input.Trainer,
+		Commute:        input.Commute,
+	}, nil
+}
+
+// manualChanges lists the values of a manual activity for confirmation
+func manualChanges(manual strava.ManualActivity) []Change {
+	changes := []Change{
+		{Field: "name", To: manual.Name},
+		{Field: "sport_type", To: manual.SportType},
+		{Field: "start_date_local", To: manual.StartDateLocal.Format("2006-01-02 15:04")},
+		{Field: "duration", To: formatDuration(int64(manual.ElapsedTime))},
+	}
+	if manual.Distance > 0 {
+		changes = append(changes, Change{Field: "distance", To: formatDistance(manual.Distance)})
+	}
+	if manual.Description != "" {
+		changes = append(changes, Change{Field: "description", To: manual.Description})
+	}
+	if manual.Trainer {
+		changes = append(changes, Change{Field: "trainer", To: "true"})
+	}
+	if manual.Commute {
+		changes = append(changes, Change{Field: "commute", To: "true"})
+	}
+	return changes
+}
+
+// uploadChanges lists what an upload sends for confirmation
+func uploadChanges(file strava.UploadFile) []Change {
+	changes := []Change{
+		{Field: "file", To: fmt.Sprintf("%s (%s, %d KB)", file.Filename, file.DataType, (len(file.Data)+1023)/1024)},
+	}
+	for _, c := range []Change{{Field: "name", To: file.Name}, {Field: "description", To: file.Description}, {Field: "external_id", To: file.ExternalID}} {
+		if c.To != "" {
+			changes = append(changes, c)
+		}
+	}
+	if file.Trainer {
+		changes = append(changes, Change{Field: "trainer", To: "true"})
+	}
+	if file.Commute {
+		changes = append(changes, Change{Field: "commute", To: "true"})
+	}
+	return changes
+}
+
+// parseLocalStart parses a local start time with or without seconds
+func parseLocalStart(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var err error
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02 15:04:05"} {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// uploadFile reads the file to upload from the input and works out its format
+func uploadFile(input UploadActivityFileInput) (strava.UploadFile, error) {
+	file := strava.UploadFile{
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Trainer:     input.Trainer,
+		Commute:     input.Commute,
+		ExternalID:  strings.TrimSpace(input.ExternalID),
+		DataType:    strings.ToLower(strings.TrimPrefix(strings.TrimSpace(input.DataType), ".")),
+	}
+
+	switch {
+	case input.FilePath != "" && input.ContentBase64 != "":
+		return file, NewInvalidInputError("Pass file_path or content_base64, not both")
+	case input.FilePath != "":
+		path := input.FilePath
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		// Only activity files are read, so a path can't send anything else
+		// on the machine to Strava
+		file.Filename = filepath.Base(path)
+		extType := uploadDataType(file.Filename)
+		if extType == "" {
+			return file, NewInvalidInputErrorWithDetails(
+				"file_path is not an activity file", "Upload a .fit, .tcx or .gpx file, optionally gzipped")
+		}
+		if file.DataType == "" {
+			file.DataType = extType
+		}
+		if file.DataType != extType {
+			return file, NewInvalidInputErrorWithDetails(
+				"data_type does not match the file", fmt.Sprintf("%s is a %s file", file.Filename, extType))
+		}
+		// Lstat, so a symlink named like an activity file is not followed
+		info, err := os.Lstat(path)
+		if err != nil {
+			return file, NewInvalidInputErrorWithDetails("Cannot read file_path", err.Error())
+		}
+		if !info.Mode().IsRegular() {
+			return file, NewInvalidInputError("file_path is not a regular file")
+		}
+		if info.Size() > maxUploadBytes {
+			return file, NewInvalidInputError("The file is larger than Strava's 25 MB limit")
+		}
+		if file.Data, err = os.ReadFile(path); err != nil {
+			return file, NewInvalidInputErrorWithDetails("Cannot read file_path", err.Error())
+		}
+	case input.ContentBase64 != "":
+		data, err := base64.StdEncoding.DecodeString(input.ContentBase64)
+		if err != nil {
+			return file, NewInvalidInputErrorWithDetails("content_base64 is not valid base64", err.Error())
+		}
+		if len(data) > maxUploadBytes {
+			return file, NewInvalidInputError("The file is larger than Strava's 25 MB limit")
+		}
+		file.Data = data
+	default:
+		return file, NewInvalidInputErrorWithDetails(
+			"No file to upload", "Pass file_path or content_base64, or upload_id to check on an earlier upload")
+	}
+
+	valid := false
+	for _, t := range uploadDataTypes {
+		valid = valid || file.DataType == t
+	}
+	if !valid {
+		return file, NewInvalidInputErrorWithDetails(
+			"Unknown data_type", "Valid values: "+strings.Join(uploadDataTypes, ", "))
+	}
+	if file.Filename == "" {
+		file.Filename = "activity." + file.DataType
+	}
+	return file, nil
+}
+
+// uploadDataType works out the format from a file name, e.g. "gpx.gz" for
+// "ride.GPX.gz"; empty when it isn't one Strava accepts
+func uploadDataType(filename string) string {
+	name := strings.ToLower(filename)
+	for _, t := range uploadDataTypes {
+		if strings.HasSuffix(name, "."+t) {
+			return t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCreateManualActivity(t *testing.T) {
+	t.Parallel()
+
+	m := &MockQuerier{}
+	f := &fakeStrava{activity: strava.Activity{ID: 55}}
+	srv := stravaTestServer(m, f)
+
+	input := CreateManualActivityInput{
+		Name:           "Pool swim",
+		SportType:      "Swim",
+		StartDateLocal: "2026-10-17T07:30",
+		Duration:       "45:00",
+		DistanceKm:     2,
+	}
+	_, output, err := srv.createManualActivity(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Confirmation == nil || len(output.Confirmation.Changes) != 5 || len(f.created) != 0 {
+		t.Fatalf("expected a confirmation first, got %+v", output)
+	}
+	input.ConfirmToken = output.Confirmation.Token
+	_, output, err = srv.createManualActivity(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.created) != 1 || f.created[0].ElapsedTime != 2700 || f.created[0].Distance != 2000 || f.created[0].StartDateLocal.Hour() != 7 {
+		t.Errorf("unexpected activity sent: %+v", f.created)
+	}
+	if len(m.activities) != 1 || output.Activity == nil || output.Activity.ID != 55 || output.Activity.Type != "Swim" {
+		t.Errorf("expected the activity stored locally, got %+v", output)
+	}
+
+	for _, input := range []CreateManualActivityInput{
+		{Name: "Swim", SportType: "Swim", StartDateLocal: "2026-10-17", Duration: "45:00"},
+		{Name: "Swim", SportType: "Swim", StartDateLocal: "2026-10-17 07:30", Duration: "45 minutes"},
+		{SportType: "Swim", StartDateLocal: "2026-10-17T07:30", Duration: "45:00"},
+	} {
+		if _, _, err := srv.createManualActivity(context.Background(), nil, input); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+	if len(f.created) != 1 {
+		t.Errorf("expected nothing sent for invalid input, got %d", len(f.created))
+	}
+}
+
+// confirmedUpload uploads a file, confirming with the token
+func confirmedUpload(srv *Server, input UploadActivityFileInput) (*mcp.CallToolResult, UploadActivityFileOutput, error) {
+	_, output, err := srv.uploadActivityFile(context.Background(), nil, input)
+	if err != nil || output.Confirmation == nil {
+		return nil, output, err
+	}
+	input.ConfirmToken = output.Confirmation.Token
+	return srv.uploadActivityFile(context.Background(), nil, input)
+}
+
+func TestUploadActivityFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "Ride.GPX")
+	if err := os.WriteFile(path, []byte("<gpx/>"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &MockQuerier{}
+	f := &fakeStrava{activity: strava.Activity{ID: 77, Name: "Evening Ride", Type: "Ride", SportType: "Ride"}}
+	srv := stravaTestServer(m, f)
+
+	_, output, err := confirmedUpload(srv, UploadActivityFileInput{FilePath: path, Trainer: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.uploads) != 1 || f.uploads[0].DataType != "gpx" || f.uploads[0].Filename != "Ride.GPX" || !f.uploads[0].Trainer {
+		t.Errorf("unexpected upload: %+v", f.uploads)
+	}
+	if output.Status != "created" || output.Activity == nil || output.Activity.ID != 77 || len(m.activities) != 1 {
+		t.Errorf("expected the activity stored locally, got %+v", output)
+	}
+
+	// Still processing, then checked on later
+	f.uploadPending = true
+	content := base64.StdEncoding.EncodeToString([]byte("fit"))
+	_, output, err = confirmedUpload(srv, UploadActivityFileInput{ContentBase64: content, DataType: "fit"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != "processing" || output.UploadID != 9 || output.Activity != nil {
+		t.Errorf("expected the upload pending, got %+v", output)
+	}
+	f.uploadPending = false
+	_, output, err = srv.uploadActivityFile(context.Background(), nil, UploadActivityFileInput{UploadID: 9})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != "created" || len(f.uploads) != 2 {
+		t.Errorf("expected the earlier upload finished, got %+v", output)
+	}
+
+	// Only regular activity files, whatever data_type says
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(key, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "key.fit")
+	if err := os.Symlink(key, link); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "dir.gpx")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range []UploadActivityFileInput{
+		{},
+		{ContentBase64: content},
+		{FilePath: filepath.Join(t.TempDir(), "missing.fit")},
+		{FilePath: path, ContentBase64: content},
+		{FilePath: key, DataType: "fit"},
+		{FilePath: path, DataType: "fit"},
+		{FilePath: link},
+		{FilePath: dir},
+	} {
+		if _, _, err := srv.uploadActivityFile(context.Background(), nil, input); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+
+	_, _, err = New(m).uploadActivityFile(context.Background(), nil, UploadActivityFileInput{FilePath: path})
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != ErrStravaError {
+		t.Errorf("expected Strava to be unavailable offline, got %v", err)
+	}
+}
+
+func TestUploadDataType(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"ride.fit":    "fit",
+		"Ride.FIT.gz": "fit.gz",
+		"run.tcx":     "tcx",
+		"hike.gpx.gz": "gpx.gz",
+		"notes.txt":   "",
+		"archive.gz":  "",
+		"fit":         "",
+	}
+	for name, want := range tests {
+		if got := uploadDataType(name); got != want {
+			t.Errorf("uploadDataType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/quality"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DataQualityQuerier defines the interface for data quality queries
+type DataQualityQuerier interface {
+	ListActivityFlags(ctx context.Context, arg db.ListActivityFlagsParams) ([]db.ListActivityFlagsRow, error)
+	CountActivityFlagsByCode(ctx context.Context) ([]db.CountActivityFlagsByCodeRow, error)
+	CountActivitiesQualityChecked(ctx context.Context) (int64, error)
+	CountExcludedActivities(ctx context.Context) (int64, error)
+}
+
+// Flag list limits
+const (
+	defaultFlagLimit = 50
+	maxFlagLimit     = 500
+)
+
+// Input types
+
+// CheckDataQualityInput - input for listing flagged activities
+type CheckDataQualityInput struct {
+	Severity string `json:"severity,omitempty" jsonschema:"Filter by severity. Valid values: 'error' (left out of records and progress), 'warning' (reported only). Leave empty for both."`
+	Code     string `json:"code,omitempty" jsonschema:"Filter by problem. Valid values: 'implausible_speed', 'gps_spike', 'zero_distance', 'hr_dropout', 'implausible_heartrate', 'duplicate'. Leave empty for all."`
+	Type     string `json:"type,omitempty" jsonschema:"Filter to a specific activity type. Common values: Run, Ride, Swim. Leave empty for all types."`
+	Limit    int    `json:"limit,omitempty" jsonschema:"Maximum flags to list, newest activities first. Range: 1-500. Default: 50."`
+}
+
+// Output types
+
+type CheckDataQualityOutput struct {
+	// Checked is how many activities have been checked so far
+	Checked int64 `json:"checked"`
+	// Excluded is how many activities are left out of records and progress
+	Excluded         int64             `json:"excluded"`
+	Summary          []FlagCount       `json:"summary"`
+	Flags            []ActivityFlag    `json:"flags"`
+	Insights         []Insight         `json:"insights"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+}
+
+// FlagCount is how many activities have a problem
+type FlagCount struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Count    int64  `json:"count"`
+}
+
+// ActivityFlag is a problem found in an activity
+type ActivityFlag struct {
+	ActivityID   int64  `json:"activity_id"`
+	Name         string `json:"name"`
+	Type         string `json:"type,omitempty"`
+	Date         string `json:"date,omitempty"`
+	Distance     string `json:"distance,omitempty"`
+	AverageSpeed string `json:"average_speed,omitempty"`
+	Code         string `json:"code"`
+	Severity     string `json:"severity"`
+	Detail       string `json:"detail"`
+	// DuplicateOf is the original upload of a duplicate
+	DuplicateOf int64 `json:"duplicate_of,omitempty"`
+	// Excluded is true when the activity is left out of records and progress
+	Excluded bool `json:"excluded"`
+}
+
+// registerDataQualityTools registers the data quality tool
+func (s *Server) registerDataQualityTools() {
+	logging.Debug("Registering tool", "name", "check_data_quality")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "check_data_quality",
+		Description: `List activities with suspicious data: implausible speeds for the activity type, GPS spikes, outdoor activities without distance, heart rate dropouts and duplicate uploads.
+
+Use when:
+- A record looks wrong, e.g. "Why is my fastest run 60 km/h?"
+- User asks "Are there any problems with my data?" or "Do I have duplicate activities?"
+- User wants to know which activities are left out of records and progress
+
+Parameters:
+- severity (string): "error" or "warning". Default: both.
+- code (string): implausible_speed, gps_spike, zero_distance, hr_dropout, implausible_heartrate or duplicate. Default: all.
+- type (string): Activity type (Run, Ride, etc.). Default: all types.
+- limit (int): Maximum flags to list, 1-500. Default: 50.
+
+Returns: How many activities have been checked and how many are excluded, counts by problem, and the flagged activities newest first with what was found. Activities are checked after each sync; those with an error are left out of records, progress and totals unless counted with exclude_activity, while warnings are reported only.
+
+Example: {"severity": "error", "type": "Run"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Check Data Quality",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.checkDataQuality)
+}
+
+// checkDataQuality lists flagged activities
+func (s *Server) checkDataQuality(ctx context.Context, req *mcp.CallToolRequest, input CheckDataQualityInput) (*mcp.CallToolResult, CheckDataQualityOutput, error) {
+	logging.Info("MCP tool call", "tool", "check_data_quality", "severity", input.Severity, "code", input.Code, "type", input.Type)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "check_data_quality", "input", logging.ToJSON(input))
+	}
+
+	if input.Severity != "" && input.Severity != quality.SeverityError && input.Severity != quality.SeverityWarning {
+		return nil, CheckDataQualityOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("Invalid severity %q", input.Severity), "Valid values: error, warning")
+	}
+	if input.Code != "" && !slices.Contains(quality.Codes, input.Code) {
+		return nil, CheckDataQualityOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("Invalid code %q", input.Code), "Valid values: "+strings.Join(quality.Codes, ", "))
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultFlagLimit
+	}
+	if limit > maxFlagLimit {
+		limit = maxFlagLimit
+	}
+
+	queries := s.queries.(DataQualityQuerier)
+
+	checked, err := queries.CountActivitiesQualityChecked(ctx)
+	if err != nil {
+		return nil, CheckDataQualityOutput{}, NewDatabaseError(err)
+	}
+	excluded, err := queries.CountExcludedActivities(ctx)
+	if err != nil {
+		return nil, CheckDataQualityOutput{}, NewDatabaseError(err)
+	}
+	counts, err := queries.CountActivityFlagsByCode(ctx)
+	if err != nil {
+		return nil, CheckDataQualityOutput{}, NewDatabaseError(err)
+	}
+
+	params := db.ListActivityFlagsParams{Limit: int64(limit)}
+	if input.Severity != "" {
+		params.Column1, params.Severity = input.Severity, input.Severity
+	}
+	if input.Code != "" {
+		params.Column3, params.Code = input.Code, input.Code
+	}
+	if input.Type != "" {
+		params.Column5, params.Type = input.Type, sql.NullString{String: input.Type, Valid: true}
+	}
+	rows, err := queries.ListActivityFlags(ctx, params)
+	if err != nil {
+		return nil, CheckDataQualityOutput{}, NewDatabaseError(err)
+	}
+
+	output := CheckDataQualityOutput{
+		Checked:  checked,
+		Excluded: excluded,
+		Summary:  make([]FlagCount, 0, len(counts)),
+		Flags:    make([]ActivityFlag, 0, len(rows)),
+	}
+	for _, c := range counts {
+		output.Summary = append(output.Summary, FlagCount{Code: c.Code, Severity: c.Severity, Count: c.Count})
+	}
+	for _, r := range rows {
+		output.Flags = append(output.Flags, convertActivityFlag(r))
+	}
+	output.Insights = dataQualityInsights(output)
+	output.SuggestedActions = SuggestNextActions("data_quality")
+
+	logging.Info("MCP tool completed", "tool", "check_data_quality", "flags", len(output.Flags), "excluded", excluded)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "check_data_quality", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// convertActivityFlag converts a flag row for output
+func convertActivityFlag(r db.ListActivityFlagsRow) ActivityFlag {
+	f := ActivityFlag{
+		ActivityID: r.ActivityID,
+		Name:       r.Name,
+		Type:       r.Type.String,
+		Code:       r.Code,
+		Severity:   r.Severity,
+		Detail:     r.Detail,
+		Excluded:   r.Severity == quality.SeverityError,
+	}
+	if r.StartDate.Valid {
+		f.Date = r.StartDate.Time.Format("2006-01-02")
+	}
+	if r.Distance.Valid && r.Distance.Float64 > 0 {
+		f.Distance = formatDistance(r.Distance.Float64)
+	}
+	if r.AverageSpeed.Valid && r.AverageSpeed.Float64 > 0 {
+		f.AverageSpeed = fmt.Sprintf("%.1f km/h", r.AverageSpeed.Float64*3.6)
+	}
+	if r.Code == quality.CodeDuplicate && r.RelatedActivityID.Valid {
+		f.DuplicateOf = r.RelatedActivityID.Int64
+	}
+	return f
+}
+
+// dataQualityInsights summarizes what was found
+func dataQualityInsights(output CheckDataQualityOutput) []Insight {
+	insights := make([]Insight, 0)
+	if output.Checked == 0 {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No activities have been checked yet; checks run after each sync",
+		})
+	}
+
+	var errors, warnings, duplicates int64
+	for _, c := range output.Summary {
+		if c.Severity == quality.SeverityError {
+			errors += c.Count
+		} else {
+			warnings += c.Count
+		}
+		if c.Code == quality.CodeDuplicate {
+			duplicates += c.Count
+		}
+	}
+	if errors == 0 && warnings == 0 {
+		return append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("No problems found in %d activities", output.Checked),
+		})
+	}
+
+	if output.Excluded > 0 {
+		insights = append(insights, Insight{
+			Type:    "warning",
+			Message: fmt.Sprintf("%d of %d activities are left out of records and progress", output.Excluded, output.Checked),
+		})
+	}
+	if duplicates > 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("%d activities look like duplicate uploads; deleting them on Strava removes them here on the next sync", duplicates),
+		})
+	}
+	if warnings > 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("%d warnings, such as GPS jumps or heart rate dropouts, are worth a look but don't affect records", warnings),
+		})
+	}
+	return insights
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func dataQualityTestQuerier() *MockQuerier {
+	day := time.Date(2026, 5, 3, 7, 0, 0, 0, time.UTC)
+	run := sql.NullString{String: "Run", Valid: true}
+	return &MockQuerier{
+		qualityChecked: 120,
+		activityFlags: []db.ListActivityFlagsRow{
+			{
+				ActivityID: 12, Code: "implausible_speed", Severity: "error", Detail: "Average speed of 60.1 km/h is implausible for a Run",
+				Name: "Morning Run", Type: run, StartDate: sql.NullTime{Time: day, Valid: true},
+				Distance: sql.NullFloat64{Float64: 20000, Valid: true}, AverageSpeed: sql.NullFloat64{Float64: 16.7, Valid: true},
+			},
+			{
+				ActivityID: 11, Code: "duplicate", Severity: "error", Detail: "Same start and distance as activity 10",
+				Name: "Morning Run", Type: run, StartDate: sql.NullTime{Time: day.AddDate(0, 0, -1), Valid: true},
+				RelatedActivityID: sql.NullInt64{Int64: 10, Valid: true},
+			},
+			{
+				ActivityID: 9, Code: "hr_dropout", Severity: "warning", Detail: "Heart rate missing or stuck for 6m 00s",
+				Name: "Evening Ride", Type: sql.NullString{String: "Ride", Valid: true}, StartDate: sql.NullTime{Time: day.AddDate(0, 0, -2), Valid: true},
+			},
+		},
+	}
+}
+
+func TestCheckDataQuality(t *testing.T) {
+	t.Parallel()
+
+	srv := New(dataQualityTestQuerier())
+
+	_, output, err := srv.checkDataQuality(context.Background(), nil, CheckDataQualityInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Checked != 120 || output.Excluded != 2 || len(output.Summary) != 3 || len(output.Flags) != 3 {
+		t.Fatalf("expected 2 of 120 excluded and 3 flags, got %+v", output)
+	}
+	speed := output.Flags[0]
+	if !speed.Excluded || speed.AverageSpeed != "60.1 km/h" || speed.Date != "2026-05-03" {
+		t.Errorf("expected an excluded 60 km/h run, got %+v", speed)
+	}
+	if output.Flags[1].DuplicateOf != 10 || output.Flags[2].Excluded {
+		t.Errorf("expected a duplicate of 10 and a warning kept in, got %+v", output.Flags[1:])
+	}
+	if len(output.Insights) != 3 || !strings.Contains(output.Insights[0].Message, "2 of 120") {
+		t.Errorf("expected exclusion, duplicate and warning insights, got %+v", output.Insights)
+	}
+
+	_, output, err = srv.checkDataQuality(context.Background(), nil, CheckDataQualityInput{Severity: "error", Type: "Run", Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Flags) != 1 || output.Flags[0].ActivityID != 12 {
+		t.Errorf("expected only the newest error, got %+v", output.Flags)
+	}
+
+	for _, input := range []CheckDataQualityInput{{Severity: "fatal"}, {Code: "slow"}} {
+		if _, _, err := srv.checkDataQuality(context.Background(), nil, input); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
+
+func TestCheckDataQualityNotChecked(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{})
+
+	_, output, err := srv.checkDataQuality(context.Background(), nil, CheckDataQualityInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Flags) != 0 || len(output.Insights) != 1 || output.Insights[0].Type != "suggestion" {
+		t.Errorf("expected only a suggestion before any checks, got %+v", output)
+	}
+}
@@ -0,0 +1,401 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/intervals"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+	"github.com/joshdurbin/strava-mcp/internal/workouts"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DescribeWorkoutQuerier defines the interface for workout structure queries
+type DescribeWorkoutQuerier interface {
+	GetActivity(ctx context.Context, id int64) (db.Activity, error)
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+	SearchActivities(ctx context.Context, arg db.SearchActivitiesParams) ([]db.Activity, error)
+}
+
+// Limits on the earlier sessions searched for the same structure
+const (
+	workoutCandidates   = 30
+	maxPreviousSessions = 5
+)
+
+// repFade is the slowdown from the first rep to the last worth pointing out
+const repFade = 0.03
+
+// Input types
+
+// DescribeWorkoutInput - input for describing a workout's structure
+type DescribeWorkoutInput struct {
+	ActivityID int64 `json:"activity_id" jsonschema:"The Strava ID of the run or ride to describe."`
+}
+
+// Output types
+
+type DescribeWorkoutOutput struct {
+	Activity ComparedActivity `json:"activity"`
+	// Description is the session in shorthand, e.g.
+	// "6×800m @ 3:25/km with 90s jog, avg HR 172"
+	Description string `json:"description"`
+	Structured  bool   `json:"structured"`
+	// DetectedFrom is the stream intervals were found in: pace, speed or power
+	DetectedFrom     string            `json:"detected_from,omitempty"`
+	WarmUp           string            `json:"warm_up,omitempty"`
+	CoolDown         string            `json:"cool_down,omitempty"`
+	Intervals        []WorkoutInterval `json:"intervals,omitempty"`
+	PreviousSessions []PreviousWorkout `json:"previous_sessions,omitempty"`
+	Insights         []Insight         `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions,omitempty"`
+}
+
+// WorkoutInterval is one work interval or recovery
+type WorkoutInterval struct {
+	Rep       int    `json:"rep,omitempty"` // work rep number
+	Kind      string `json:"kind"`          // work or recovery
+	Duration  string `json:"duration"`
+	Distance  string `json:"distance,omitempty"`
+	Intensity string `json:"intensity"` // pace, speed or power
+	Heartrate int    `json:"heartrate,omitempty"`
+}
+
+// PreviousWorkout is an earlier session with the same structure
+type PreviousWorkout struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Date        string `json:"date"`
+	Description string `json:"description"`
+	// Change is how the described session's work compares, e.g. "4s/km faster"
+	Change string `json:"change"`
+	// Difference is positive when the described session's work was faster
+	// or harder, in s/km, km/h or watts
+	Difference          float64 `json:"difference"`
+	HeartrateDifference int     `json:"heartrate_difference,omitempty"`
+}
+
+// registerDescribeWorkoutTools registers the workout structure tool
+func (s *Server) registerDescribeWorkoutTools() {
+	logging.Debug("Registering tool", "name", "describe_workout")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "describe_workout",
+		Description: `Describe the structure of a run or ride, like "6×800m @ 3:25/km with 90s jog, avg HR 172", and compare it with earlier sessions of the same structure. Work and recovery intervals are found from change points in the synced pace or power stream, so no laps are needed.
+
+Use when:
+- User asks "What was my workout today?" or "How did my 800s go?"
+- User wants to know whether a repeated session is getting faster
+- User asks how consistent their reps were
+
+Parameters:
+- activity_id (int, required): The run or ride to describe
+
+Returns: A one-line description of the session; the warm-up, each rep and recovery with duration, distance, pace (or power) and heart rate; and up to 5 earlier sessions of the same type and workout category with the same reps, each with how this session's work compares. Rides with power are split on power, other rides on speed and runs on pace.
+
+Example: {"activity_id": 12345678}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Describe Workout",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.describeWorkout)
+}
+
+// describeWorkout detects an activity's intervals and finds earlier sessions
+// with the same structure
+func (s *Server) describeWorkout(ctx context.Context, req *mcp.CallToolRequest, input DescribeWorkoutInput) (*mcp.CallToolResult, DescribeWorkoutOutput, error) {
+	logging.Info("MCP tool call", "tool", "describe_workout", "activity_id", input.ActivityID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "describe_workout", "input", logging.ToJSON(input))
+	}
+
+	if input.ActivityID <= 0 {
+		return nil, DescribeWorkoutOutput{}, NewInvalidInputErrorWithDetails("activity_id is required", "pass the Strava ID of a run or ride")
+	}
+
+	queries := s.queries.(DescribeWorkoutQuerier)
+	activity, err := queries.GetActivity(ctx, input.ActivityID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, DescribeWorkoutOutput{}, NewNotFoundErrorWithID("activity", input.ActivityID)
+		}
+		return nil, DescribeWorkoutOutput{}, NewDatabaseError(err)
+	}
+	st, err := workoutStreams(ctx, queries, input.ActivityID)
+	if err != nil {
+		return nil, DescribeWorkoutOutput{}, err
+	}
+	if st == nil {
+		return nil, DescribeWorkoutOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("activity %d has no streams", input.ActivityID),
+			"streams are synced in the background; try again once they have been fetched")
+	}
+
+	output := DescribeWorkoutOutput{Activity: comparedActivity(activity)}
+	structure, ok := intervals.Detect(st, activity.Type.String)
+	if !ok {
+		output.Description = describeSteady(activity)
+		output.Insights = []Insight{{
+			Type:    "trend",
+			Message: "No work and recovery intervals stand out in this activity's pace or power; it looks like a steady session.",
+		}}
+		output.SuggestedActions = SuggestNextActions("describe_workout")
+		logging.Info("MCP tool completed", "tool", "describe_workout", "structured", false)
+		return nil, output, nil
+	}
+
+	summary := intervals.Summarize(structure)
+	output.Structured = true
+	output.DetectedFrom = structure.Metric
+	output.Description = describeSummary(summary, structure.Metric)
+	// Warm-up and cool-down to the minute; they're rarely timed closely
+	if structure.WarmUp.Duration > 0 {
+		output.WarmUp = formatDuration(int64(math.Round(structure.WarmUp.Duration/60) * 60))
+	}
+	if structure.CoolDown.Duration > 0 {
+		output.CoolDown = formatDuration(int64(math.Round(structure.CoolDown.Duration/60) * 60))
+	}
+	rep := 0
+	for _, iv := range structure.Intervals {
+		wi := WorkoutInterval{
+			Kind:      "recovery",
+			Duration:  formatDuration(int64(math.Round(iv.Duration))),
+			Intensity: formatIntensity(structure.Metric, iv.Speed, iv.Watts),
+			Heartrate: int(math.Round(iv.Heartrate)),
+		}
+		if iv.Work {
+			rep++
+			wi.Rep, wi.Kind = rep, "work"
+		}
+		if iv.Distance > 0 {
+			wi.Distance = formatDistance(iv.Distance)
+		}
+		output.Intervals = append(output.Intervals, wi)
+	}
+
+	output.PreviousSessions, err = previousWorkouts(ctx, queries, activity, structure.Metric, summary)
+	if err != nil {
+		return nil, DescribeWorkoutOutput{}, err
+	}
+
+	output.Insights = describeWorkoutInsights(structure, output.PreviousSessions)
+	output.SuggestedActions = SuggestNextActions("describe_workout")
+
+	logging.Info("MCP tool completed", "tool", "describe_workout", "structured", true, "reps", summary.Reps, "previous_sessions", len(output.PreviousSessions))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "describe_workout", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// workoutStreams loads and decodes an activity's streams, returning nil when
+// they haven't been synced
+func workoutStreams(ctx context.Context, queries DescribeWorkoutQuerier, id int64) (*streams.Streams, error) {
+	row, err := queries.GetActivityStreams(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, NewDatabaseError(err)
+	}
+	st, err := streams.FromRow(row)
+	if err != nil {
+		return nil, NewInternalErrorWithCause("failed to decode activity streams", err)
+	}
+	return st, nil
+}
+
+// previousWorkouts finds the most recent earlier sessions of the same type
+// and workout category whose work has the same label
+func previousWorkouts(ctx context.Context, queries DescribeWorkoutQuerier, activity db.Activity, metric string, summary intervals.Summary) ([]PreviousWorkout, error) {
+	category := activity.WorkoutCategory
+	if !category.Valid {
+		category = sql.NullString{String: workouts.CategoryIntervals, Valid: summary.Reps > 1}
+	}
+	before := sql.NullTime{Time: activity.StartDate.Time.Add(-time.Second), Valid: activity.StartDate.Valid}
+	candidates, err := queries.SearchActivities(ctx, db.SearchActivitiesParams{
+		Column1:         activity.Type,
+		Type:            activity.Type,
+		Column5:         before,
+		StartDate_2:     before,
+		Column7:         category,
+		WorkoutCategory: category,
+		Limit:           workoutCandidates,
+	})
+	if err != nil {
+		return nil, NewDatabaseError(err)
+	}
+
+	var previous []PreviousWorkout
+	for _, c := range candidates {
+		if len(previous) == maxPreviousSessions {
+			break
+		}
+		if c.ID == activity.ID {
+			continue
+		}
+		st, err := workoutStreams(ctx, queries, c.ID)
+		if err != nil {
+			logging.Warn("skipping earlier workout", "activity_id", c.ID, "error", err)
+			continue
+		}
+		if st == nil {
+			continue
+		}
+		structure, ok := intervals.Detect(st, c.Type.String)
+		if !ok || structure.Metric != metric {
+			continue
+		}
+		other := intervals.Summarize(structure)
+		if other.Label != summary.Label {
+			continue
+		}
+
+		p := PreviousWorkout{
+			ID:          c.ID,
+			Name:        c.Name,
+			Date:        c.StartDate.Time.Format("2006-01-02"),
+			Description: describeSummary(other, metric),
+		}
+		p.Difference, p.Change = workChange(metric, summary, other)
+		if summary.Heartrate > 0 && other.Heartrate > 0 {
+			p.HeartrateDifference = int(math.Round(summary.Heartrate - other.Heartrate))
+		}
+		previous = append(previous, p)
+	}
+	return previous, nil
+}
+
+// workChange compares the work of two sessions, positive when a was faster
+// or harder
+func workChange(metric string, a, b intervals.Summary) (float64, string) {
+	var diff float64
+	unit, up, down, same := "s/km", "faster", "slower", "same pace"
+	switch metric {
+	case intervals.MetricPower:
+		diff = math.Round(a.Watts - b.Watts)
+		unit, up, down, same = "W", "more", "less", "same power"
+	case intervals.MetricSpeed:
+		diff = math.Round((a.Speed-b.Speed)*36) / 10
+		unit = " km/h"
+	default:
+		if a.Speed > 0 && b.Speed > 0 {
+			diff = math.Round(1000/b.Speed - 1000/a.Speed)
+		}
+	}
+	switch {
+	case diff > 0:
+		return diff, fmt.Sprintf("%g%s %s", diff, unit, up)
+	case diff < 0:
+		return diff, fmt.Sprintf("%g%s %s", -diff, unit, down)
+	}
+	return 0, same
+}
+
+// describeSummary renders a summary as "6×800m @ 3:25/km with 90s jog, avg HR 172"
+func describeSummary(summary intervals.Summary, metric string) string {
+	desc := fmt.Sprintf("%s @ %s", summary.Label, formatIntensity(metric, summary.Speed, summary.Watts))
+	if summary.Recovery != "" {
+		kind := "easy"
+		if metric == intervals.MetricPace {
+			kind = "jog"
+			if summary.Rest {
+				kind = "rest"
+			}
+		}
+		desc += fmt.Sprintf(" with %s %s", summary.Recovery, kind)
+	}
+	if summary.Heartrate > 0 {
+		desc += fmt.Sprintf(", avg HR %.0f", summary.Heartrate)
+	}
+	return desc
+}
+
+// describeSteady renders an activity without intervals from its averages
+func describeSteady(a db.Activity) string {
+	desc := "Steady " + formatDuration(a.MovingTime.Int64)
+	if a.AverageSpeed.Float64 > 0 {
+		metric := intervals.MetricPace
+		if thresholds.Sport(a.Type.String) == thresholds.SportRide {
+			metric = intervals.MetricSpeed
+		}
+		desc += " @ " + formatIntensity(metric, a.AverageSpeed.Float64, 0)
+	}
+	if a.AverageHeartrate.Float64 > 0 {
+		desc += fmt.Sprintf(", avg HR %.0f", a.AverageHeartrate.Float64)
+	}
+	return desc
+}
+
+// formatIntensity prints how hard an interval was in the metric it was
+// detected from
+func formatIntensity(metric string, speed, watts float64) string {
+	switch metric {
+	case intervals.MetricPower:
+		return fmt.Sprintf("%.0fW", watts)
+	case intervals.MetricSpeed:
+		return fmt.Sprintf("%.1f km/h", speed*3.6)
+	}
+	return formatPace(speed)
+}
+
+func describeWorkoutInsights(structure intervals.Structure, previous []PreviousWorkout) []Insight {
+	var insights []Insight
+
+	work := structure.Work()
+	if len(work) >= 3 {
+		first, last := work[0], work[len(work)-1]
+		a, b := first.Speed, last.Speed
+		if structure.Metric == intervals.MetricPower {
+			a, b = first.Watts, last.Watts
+		}
+		switch {
+		case a > 0 && b < a*(1-repFade):
+			insights = append(insights, Insight{
+				Type:    "warning",
+				Message: fmt.Sprintf("The last rep (%s) was %.0f%% off the first (%s); start the reps a little easier or take longer recoveries.", formatIntensity(structure.Metric, last.Speed, last.Watts), (1-b/a)*100, formatIntensity(structure.Metric, first.Speed, first.Watts)),
+			})
+		case a > 0 && b >= a:
+			insights = append(insights, Insight{
+				Type:    "achievement",
+				Message: "You finished the last rep at least as strong as the first, a well-paced session.",
+			})
+		}
+	}
+
+	if len(previous) == 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No earlier session with the same reps was found; repeat it in a few weeks to measure progress.",
+		})
+		return insights
+	}
+
+	last := previous[0]
+	switch {
+	case last.Difference > 0 && last.HeartrateDifference <= 0:
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("Work was %s than the same session on %s at no higher heart rate, a sign of improved fitness.", last.Change, last.Date),
+		})
+	case last.Difference > 0:
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("Work was %s than the same session on %s.", last.Change, last.Date),
+		})
+	case last.Difference < 0:
+		insights = append(insights, Insight{
+			Type:    "trend",
+			Message: fmt.Sprintf("Work was %s than the same session on %s; fatigue, heat or terrain may explain it.", last.Change, last.Date),
+		})
+	}
+	return insights
+}
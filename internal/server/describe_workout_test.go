@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+// trackStream builds one-second streams of a 10 minute warm-up, reps of
+// repMeters at speed with 90s jogs, and a 10 minute cool-down
+func trackStream(id int64, reps int, repMeters, speed, heartrate float64) db.ActivityStream {
+	var times []int
+	var distances, velocity, hr []float64
+	distance := 0.0
+	add := func(seconds int, v, h float64) {
+		for range seconds {
+			distance += v
+			times = append(times, len(times))
+			distances = append(distances, distance)
+			velocity = append(velocity, v)
+			hr = append(hr, h)
+		}
+	}
+	add(600, 3.0, 135)
+	for i := range reps {
+		if i > 0 {
+			add(90, 2.5, 145)
+		}
+		add(int(repMeters/speed), speed, heartrate)
+	}
+	add(600, 3.0, 140)
+
+	encode := func(v any) sql.NullString {
+		b, _ := json.Marshal(v)
+		return sql.NullString{String: string(b), Valid: true}
+	}
+	return db.ActivityStream{
+		ActivityID:    id,
+		PointCount:    int64(len(times)),
+		TimeData:      encode(times),
+		DistanceData:  encode(distances),
+		VelocityData:  encode(velocity),
+		HeartrateData: encode(hr),
+	}
+}
+
+func workoutTestQuerier() *MockQuerier {
+	day := time.Date(2026, 10, 6, 18, 0, 0, 0, time.UTC)
+	session := func(id int64, name, category string, daysAgo int) db.Activity {
+		a := createTestActivity(id, name, "Run", day.AddDate(0, 0, -daysAgo))
+		a.WorkoutCategory = sql.NullString{String: category, Valid: true}
+		a.MovingTime = sql.NullInt64{Int64: 2700, Valid: true}
+		a.AverageSpeed = sql.NullFloat64{Float64: 3.1, Valid: true}
+		return a
+	}
+	return &MockQuerier{
+		activities: []db.Activity{
+			session(20, "Track Tuesday", "intervals", 0),
+			session(21, "Track Tuesday", "intervals", 14),
+			session(22, "Kilometer repeats", "intervals", 21),
+			session(23, "Track, no streams yet", "intervals", 28),
+			session(24, "Easy run", "easy", 1),
+		},
+		streams: map[int64]db.ActivityStream{
+			20: trackStream(20, 6, 800, 800.0/160, 170),
+			21: trackStream(21, 6, 800, 800.0/166, 172),
+			22: trackStream(22, 5, 1000, 1000.0/215, 174),
+			24: paceStream(24, []float64{330, 330, 330, 330, 330}, 140),
+		},
+	}
+}
+
+func TestDescribeWorkout(t *testing.T) {
+	t.Parallel()
+
+	srv := New(workoutTestQuerier())
+
+	_, output, err := srv.describeWorkout(context.Background(), nil, DescribeWorkoutInput{ActivityID: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !output.Structured || output.DetectedFrom != "pace" {
+		t.Fatalf("expected intervals found from pace, got %+v", output)
+	}
+	if want := "6×800m @ 3:20/km with 90s jog, avg HR 170"; output.Description != want {
+		t.Errorf("expected %q, got %q", want, output.Description)
+	}
+	if output.WarmUp != "10m 0s" || output.CoolDown != "10m 0s" {
+		t.Errorf("expected 10 minute warm-up and cool-down, got %q and %q", output.WarmUp, output.CoolDown)
+	}
+	if len(output.Intervals) != 11 || output.Intervals[10].Rep != 6 || output.Intervals[1].Kind != "recovery" {
+		t.Errorf("expected 6 reps and 5 recoveries, got %+v", output.Intervals)
+	}
+
+	// Only the earlier 6×800m matches; the kilometer repeats don't
+	if len(output.PreviousSessions) != 1 {
+		t.Fatalf("expected one earlier session, got %+v", output.PreviousSessions)
+	}
+	prev := output.PreviousSessions[0]
+	if prev.ID != 21 || prev.Change != "7s/km faster" || prev.HeartrateDifference != -2 {
+		t.Errorf("unexpected previous session: %+v", prev)
+	}
+
+	var improved bool
+	for _, i := range output.Insights {
+		if i.Type == "achievement" && strings.Contains(i.Message, "no higher heart rate") {
+			improved = true
+		}
+	}
+	if !improved {
+		t.Errorf("expected an improved fitness insight, got %+v", output.Insights)
+	}
+}
+
+func TestDescribeWorkoutSteady(t *testing.T) {
+	t.Parallel()
+
+	srv := New(workoutTestQuerier())
+
+	_, output, err := srv.describeWorkout(context.Background(), nil, DescribeWorkoutInput{ActivityID: 24})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Structured || output.Description != "Steady 45m 0s @ 5:22/km" {
+		t.Errorf("expected a steady run, got %+v", output)
+	}
+}
+
+func TestDescribeWorkoutErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := New(workoutTestQuerier())
+
+	for _, id := range []int64{0, 99, 23} {
+		if _, _, err := srv.describeWorkout(context.Background(), nil, DescribeWorkoutInput{ActivityID: id}); err == nil {
+			t.Errorf("expected an error for activity %d", id)
+		}
+	}
+}
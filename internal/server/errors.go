@@ -1,6 +1,11 @@
 package server
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+)
 
 // ErrorCode classifies MCP tool errors for structured error handling
 type ErrorCode string
@@ -14,6 +19,10 @@ const (
 	ErrDatabaseError ErrorCode = "DATABASE_ERROR"
 	// ErrInternalError indicates an unexpected internal error
 	ErrInternalError ErrorCode = "INTERNAL_ERROR"
+	// ErrStravaError indicates a Strava API call failed or Strava is unavailable
+	ErrStravaError ErrorCode = "STRAVA_ERROR"
+	// ErrCancelled indicates the user declined a change when asked to confirm it
+	ErrCancelled ErrorCode = "CANCELLED"
 )
 
 // ToolError represents a structured tool error with code, message, and optional details
@@ -86,3 +95,44 @@ func NewInternalErrorWithCause(msg string, err error) *ToolError {
 		Details: err.Error(),
 	}
 }
+
+// NewStravaError creates an error for a failed Strava API call, with what
+// to do about the common failures
+func NewStravaError(err error) *ToolError {
+	e := &ToolError{Code: ErrStravaError, Message: "Strava request failed", Details: err.Error()}
+	switch {
+	case errors.Is(err, strava.ErrMissingScope):
+		e.Message = "Not authorized to change activities on Strava"
+		e.Details = "Restart with --force-reauth to grant the activity:write scope"
+	case errors.Is(err, strava.ErrRateLimited):
+		e.Message = "Strava rate limit reached"
+		e.Details = "Try again after the 15-minute window resets"
+	case errors.Is(err, strava.ErrBudgetExhausted):
+		e.Message = "Shared rate limit budget exhausted"
+		e.Details = "The requests left in this window are reserved; try again after the 15-minute window resets"
+	case errors.Is(err, strava.ErrNotFound):
+		e.Code = ErrNotFound
+		e.Message = "Activity not found on Strava"
+		e.Details = "It may have been deleted, or belong to another athlete"
+	}
+	return e
+}
+
+// NewStravaUnavailableError creates an error for tools that need Strava when
+// running offline
+func NewStravaUnavailableError() *ToolError {
+	return &ToolError{
+		Code:    ErrStravaError,
+		Message: "Strava is not available",
+		Details: "The server is running offline (--no-sync); restart without it to make changes on Strava",
+	}
+}
+
+// NewCancelledError creates an error for a change the user declined
+func NewCancelledError(action string) *ToolError {
+	return &ToolError{
+		Code:    ErrCancelled,
+		Message: "Cancelled by the user",
+		Details: fmt.Sprintf("Nothing was changed: %s", action),
+	}
+}
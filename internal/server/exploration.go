@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/explore"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ExplorationQuerier defines the interface for GPS coverage queries
+type ExplorationQuerier interface {
+	ListLatlngStreams(ctx context.Context, arg db.ListLatlngStreamsParams) ([]db.ListLatlngStreamsRow, error)
+	ListPolylinesWithoutStreams(ctx context.Context, arg db.ListPolylinesWithoutStreamsParams) ([]db.ListPolylinesWithoutStreamsRow, error)
+}
+
+// Hotspot limits
+const (
+	defaultHotspots = 10
+	maxHotspots     = 50
+)
+
+// Input types
+
+// GetExplorationStatsInput - input for explorer tile and heatmap statistics
+type GetExplorationStatsInput struct {
+	Type           string `json:"type,omitempty" jsonschema:"Filter by activity type. Common values: Run, Ride, Walk, Hike. Leave empty for all types."`
+	StartDate      string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD (e.g., 2024-01-15)."`
+	EndDate        string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD (e.g., 2024-12-31)."`
+	Hotspots       int    `json:"hotspots,omitempty" jsonschema:"Number of most-visited heatmap cells to return. Default: 10, Maximum: 50."`
+	IncludeGeoJSON bool   `json:"include_geojson,omitempty" jsonschema:"Include explorer tiles and the max square as a GeoJSON FeatureCollection of polygons. Default: false."`
+	IncludeHeatmap bool   `json:"include_heatmap,omitempty" jsonschema:"With include_geojson, also include every heatmap cell as a polygon with an activity count. Can be large. Default: false."`
+}
+
+// Output types
+
+type GetExplorationStatsOutput struct {
+	Filter             string                 `json:"filter"`
+	ActivitiesAnalyzed int                    `json:"activities_analyzed"`
+	FromStreams        int                    `json:"from_streams"`
+	FromPolylines      int                    `json:"from_polylines"`
+	TilesVisited       int                    `json:"tiles_visited"`
+	MaxSquare          int                    `json:"max_square"`
+	MaxSquareCenter    *geo.Point             `json:"max_square_center,omitempty"`
+	MaxCluster         int                    `json:"max_cluster"`
+	TilesToNextSquare  int                    `json:"tiles_to_next_square,omitempty"`
+	NewTilesLast30Days int                    `json:"new_tiles_last_30_days"`
+	Hotspots           []ExplorationHotspot   `json:"hotspots"`
+	GeoJSON            *geo.PolygonCollection `json:"geojson,omitempty"`
+	Insights           []Insight              `json:"insights,omitempty"`
+	SuggestedActions   []SuggestedAction      `json:"suggested_actions,omitempty"`
+}
+
+// ExplorationHotspot is a frequently visited heatmap cell
+type ExplorationHotspot struct {
+	Lat        float64 `json:"lat"`
+	Lng        float64 `json:"lng"`
+	Activities int     `json:"activities"`
+}
+
+// registerExplorationTools registers the explorer tile tool
+func (s *Server) registerExplorationTools() {
+	logging.Debug("Registering tool", "name", "get_exploration_stats")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "get_exploration_stats",
+		Description: `Explorer tile and heatmap statistics across all stored GPS data, in the style of VeloViewer/StatsHunters.
+
+The world is divided into zoom-14 map tiles (about 1.5 km squares). A tile counts as visited once any activity passes through it.
+
+Use when:
+- User asks "How many tiles have I explored?" or "What's my max square?"
+- User wants motivation to explore new areas, or asks where they go most often
+- User asks for a heatmap of where they run or ride
+
+Parameters:
+- type (string): Filter by activity type (Run, Ride, etc.)
+- start_date, end_date (string): Date range filter, YYYY-MM-DD format
+- hotspots (int): Number of most-visited ~250 m heatmap cells to return (default 10, max 50)
+- include_geojson (bool): Add explorer tiles and the max square as GeoJSON polygons
+- include_heatmap (bool): With include_geojson, add every heatmap cell as well
+
+Returns: Tiles visited, max square (largest fully visited n x n block), max cluster (largest connected group of tiles whose four neighbours are all visited), tiles needed to grow the max square, new tiles in the last 30 days, and hotspots. Uses full GPS streams where synced, summary polylines otherwise.
+
+Example: {} or {"type": "Ride", "include_geojson": true}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Get Exploration Stats",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.getExplorationStats)
+}
+
+// getExplorationStats aggregates explorer tiles and the heatmap
+func (s *Server) getExplorationStats(ctx context.Context, req *mcp.CallToolRequest, input GetExplorationStatsInput) (*mcp.CallToolResult, GetExplorationStatsOutput, error) {
+	logging.Info("MCP tool call", "tool", "get_exploration_stats", "type", input.Type, "start_date", input.StartDate, "end_date", input.EndDate)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "get_exploration_stats", "input", logging.ToJSON(input))
+	}
+
+	startTime, endTime, err := parseServerDateRange(input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, GetExplorationStatsOutput{}, NewInvalidInputError(err.Error())
+	}
+
+	hotspots := input.Hotspots
+	if hotspots <= 0 {
+		hotspots = defaultHotspots
+	}
+	if hotspots > maxHotspots {
+		hotspots = maxHotspots
+	}
+
+	queries := s.queries.(ExplorationQuerier)
+	coverage, err := explore.Build(ctx, queries, explore.Filter{
+		Type:      input.Type,
+		StartDate: startTime,
+		EndDate:   endTime,
+	})
+	if err != nil {
+		return nil, GetExplorationStatsOutput{}, NewDatabaseError(err)
+	}
+
+	stats := coverage.Stats(time.Now().AddDate(0, 0, -30))
+	output := GetExplorationStatsOutput{
+		Filter:             buildFilterDesc(input.Type, input.StartDate, input.EndDate),
+		ActivitiesAnalyzed: coverage.Activities,
+		FromStreams:        coverage.FromStreams,
+		FromPolylines:      coverage.FromPolylines,
+		TilesVisited:       stats.TilesVisited,
+		MaxSquare:          stats.MaxSquare,
+		MaxCluster:         stats.MaxCluster,
+		TilesToNextSquare:  stats.TilesToNextSquare,
+		NewTilesLast30Days: stats.NewTilesSince,
+		Hotspots:           []ExplorationHotspot{},
+	}
+	if stats.MaxSquare > 0 {
+		origin := stats.MaxSquareOrigin
+		nw := origin.NorthWest()
+		se := geo.Tile{X: origin.X + stats.MaxSquare, Y: origin.Y + stats.MaxSquare, Z: origin.Z}.NorthWest()
+		output.MaxSquareCenter = &geo.Point{Lat: roundCoord((nw.Lat + se.Lat) / 2), Lng: roundCoord((nw.Lng + se.Lng) / 2)}
+	}
+	for _, h := range coverage.Hotspots(hotspots) {
+		output.Hotspots = append(output.Hotspots, ExplorationHotspot{
+			Lat:        roundCoord(h.Center.Lat),
+			Lng:        roundCoord(h.Center.Lng),
+			Activities: h.Activities,
+		})
+	}
+	if input.IncludeGeoJSON {
+		fc := coverage.FeatureCollection(input.IncludeHeatmap)
+		output.GeoJSON = &fc
+	}
+
+	output.Insights = explorationInsights(output)
+	output.SuggestedActions = SuggestNextActions("exploration")
+
+	logging.Info("MCP tool completed", "tool", "get_exploration_stats", "activities", output.ActivitiesAnalyzed, "tiles", output.TilesVisited, "max_square", output.MaxSquare)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "get_exploration_stats", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+func explorationInsights(output GetExplorationStatsOutput) []Insight {
+	if output.ActivitiesAnalyzed == 0 {
+		return []Insight{{
+			Type:    "warning",
+			Message: "No activities with GPS data found for this filter.",
+		}}
+	}
+
+	insights := []Insight{{
+		Type: "achievement",
+		Message: fmt.Sprintf("%d tiles explored across %d activities. Max square %dx%d, max cluster %d tiles.",
+			output.TilesVisited, output.ActivitiesAnalyzed, output.MaxSquare, output.MaxSquare, output.MaxCluster),
+	}}
+
+	if output.TilesToNextSquare > 0 {
+		insights = append(insights, Insight{
+			Type: "suggestion",
+			Message: fmt.Sprintf("Visit %d more tile(s) around your max square to grow it to %dx%d.",
+				output.TilesToNextSquare, output.MaxSquare+1, output.MaxSquare+1),
+		})
+	}
+
+	if output.NewTilesLast30Days > 0 {
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("You reached %d new tile(s) in the last 30 days.", output.NewTilesLast30Days),
+		})
+	}
+
+	if output.FromPolylines > 0 {
+		insights = append(insights, Insight{
+			Type: "warning",
+			Message: fmt.Sprintf("%d activities used the simplified summary polyline because their GPS streams are not synced yet; tile counts may change slightly once they are.",
+				output.FromPolylines),
+		})
+	}
+
+	return insights
+}
+
+// roundCoord trims coordinates to 5 decimals (about 1 m)
+func roundCoord(v float64) float64 {
+	return math.Round(v*1e5) / 1e5
+}
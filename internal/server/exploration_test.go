@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetExplorationStats(t *testing.T) {
+	t.Parallel()
+
+	srv := New(mapTestQuerier())
+
+	_, output, err := srv.getExplorationStats(context.Background(), nil, GetExplorationStatsInput{
+		Hotspots:       3,
+		IncludeGeoJSON: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The run has a GPS stream, the ride only a polyline, the treadmill run neither
+	if output.ActivitiesAnalyzed != 2 || output.FromStreams != 1 || output.FromPolylines != 1 {
+		t.Errorf("unexpected sources: %+v", output)
+	}
+	if output.TilesVisited == 0 || output.MaxSquare < 1 || output.MaxSquareCenter == nil {
+		t.Errorf("expected visited tiles and a max square, got %+v", output)
+	}
+	if len(output.Hotspots) != 3 {
+		t.Errorf("expected 3 hotspots, got %d", len(output.Hotspots))
+	}
+	if output.GeoJSON == nil || len(output.GeoJSON.Features) != output.TilesVisited+1 {
+		t.Errorf("expected one feature per tile plus the max square")
+	}
+	if len(output.Insights) == 0 {
+		t.Error("expected insights")
+	}
+}
+
+func TestGetExplorationStatsEmpty(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{})
+
+	_, output, err := srv.getExplorationStats(context.Background(), nil, GetExplorationStatsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.TilesVisited != 0 || len(output.Insights) != 1 || output.Insights[0].Type != "warning" {
+		t.Errorf("expected an empty result with a warning, got %+v", output)
+	}
+	if output.Hotspots == nil {
+		t.Error("expected an empty hotspot list rather than null")
+	}
+}
+
+func TestGetExplorationStatsInvalidDate(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{})
+
+	if _, _, err := srv.getExplorationStats(context.Background(), nil, GetExplorationStatsInput{StartDate: "June"}); err == nil {
+		t.Error("expected error for invalid start_date")
+	}
+}
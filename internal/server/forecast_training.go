@@ -0,0 +1,498 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/forecast"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ForecastQuerier defines the interface for training forecast queries
+type ForecastQuerier interface {
+	GetWeeklyVolume(ctx context.Context, arg db.GetWeeklyVolumeParams) ([]db.GetWeeklyVolumeRow, error)
+	GetWeeklyVolumeByType(ctx context.Context, arg db.GetWeeklyVolumeByTypeParams) ([]db.GetWeeklyVolumeByTypeRow, error)
+}
+
+// Forecast limits
+const (
+	defaultForecastWeeks = 12
+	maxForecastWeeks     = 52
+	// forecastHistoryWeeks is the history read, enough for the same week in
+	// each of the last three years
+	forecastHistoryWeeks = 156
+	// recentForecastWeeks is how many weeks make up recent volume
+	recentForecastWeeks = 8
+	// recentChartWeeks is how much history the chart shows before the forecast
+	recentChartWeeks = 26
+)
+
+// Annual goal statuses
+const (
+	goalAchieved = "achieved"
+	goalOnTrack  = "on_track"
+	goalAtRisk   = "at_risk"
+	goalOffTrack = "off_track"
+)
+
+// Goal probabilities at or above which a goal is on track, or at risk
+const (
+	goalOnTrackProbability = 0.7
+	goalAtRiskProbability  = 0.3
+)
+
+// Input types
+
+// ForecastTrainingInput - input for forecasting weekly volume and year-end totals
+type ForecastTrainingInput struct {
+	Type           string  `json:"type,omitempty" jsonschema:"Filter to a specific activity type. Common values: Run, Ride, Swim. Leave empty to forecast across all activity types."`
+	Weeks          int     `json:"weeks,omitempty" jsonschema:"How many weeks to forecast, starting with the current week. Range: 1-52. Default: 12."`
+	GoalDistanceKm float64 `json:"goal_distance_km,omitempty" jsonschema:"Optional distance goal for the calendar year in kilometers, e.g. 2000."`
+	GoalHours      float64 `json:"goal_hours,omitempty" jsonschema:"Optional moving time goal for the calendar year in hours, e.g. 250."`
+	IncludeChart   bool    `json:"include_chart,omitempty" jsonschema:"When true, also return a line chart of recent weekly distance and the forecast with its range as image content."`
+	ChartFormat    string  `json:"chart_format,omitempty" jsonschema:"Chart image format when include_chart is set. Valid values: 'png', 'svg'. Default: png."`
+}
+
+// Output types
+
+type ForecastTrainingOutput struct {
+	Type string `json:"type,omitempty"`
+	// HistoryWeeks is how many complete weeks the forecast was fitted to
+	HistoryWeeks int `json:"history_weeks"`
+	// Seasonal is true when the same weeks in earlier years shape the forecast
+	Seasonal         bool              `json:"seasonal"`
+	ConfidenceLevel  string            `json:"confidence_level"`
+	Weekly           []ForecastWeek    `json:"weekly"`
+	YearEnd          *YearEndForecast  `json:"year_end,omitempty"`
+	Goals            []GoalForecast    `json:"goals,omitempty"`
+	Insights         []Insight         `json:"insights"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+}
+
+// ForecastWeek is the forecast for one week
+type ForecastWeek struct {
+	Week       string        `json:"week"` // Monday, YYYY-MM-DD
+	InProgress bool          `json:"in_progress,omitempty"`
+	Distance   ForecastRange `json:"distance"`
+	Duration   ForecastRange `json:"duration"`
+	// LastYear is the distance in the same week a year earlier
+	LastYear string `json:"last_year,omitempty"`
+}
+
+// ForecastRange is an expected value with its 80% range, and what's done so
+// far for periods in progress
+type ForecastRange struct {
+	SoFar    string `json:"so_far,omitempty"`
+	Expected string `json:"expected"`
+	Low      string `json:"low"`
+	High     string `json:"high"`
+}
+
+type YearEndForecast struct {
+	Year     int           `json:"year"`
+	DaysLeft int           `json:"days_left"`
+	Distance ForecastRange `json:"distance"`
+	Duration ForecastRange `json:"duration"`
+}
+
+// GoalForecast is how an annual goal is tracking
+type GoalForecast struct {
+	Metric    string `json:"metric"` // distance or duration
+	Goal      string `json:"goal"`
+	SoFar     string `json:"so_far"`
+	Projected string `json:"projected"`
+	// Probability is the chance of reaching the goal, in percent
+	Probability    int    `json:"probability"`
+	RequiredWeekly string `json:"required_weekly"`
+	RecentWeekly   string `json:"recent_weekly"`
+	Status         string `json:"status"` // achieved, on_track, at_risk or off_track
+}
+
+// registerForecastTools registers the training forecast tool
+func (s *Server) registerForecastTools() {
+	logging.Debug("Registering tool", "name", "forecast_training")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "forecast_training",
+		Description: `Forecast weekly distance and moving time and the year-end totals, with 80% ranges.
+
+Use when:
+- User asks "Will I hit 2000 km this year?" or "Am I on track for my annual goal?"
+- User asks "How much will I run this year?"
+- User wants to know what volume to expect over the coming weeks
+
+Parameters:
+- type (string): Activity type (Run, Ride, etc.). Default: all types.
+- weeks (int): Weeks to forecast from the current week, 1-52. Default: 12.
+- goal_distance_km (number): Optional annual distance goal in km
+- goal_hours (number): Optional annual moving time goal in hours
+- include_chart (boolean): Also return a line chart of distance and the forecast
+- chart_format (string): "png" or "svg". Default: "png".
+
+Returns: Weekly forecasts of distance and time with 80% ranges and the same week last year, the projected year-end totals with ranges, and for each goal the chance of reaching it, the weekly volume it needs from here and recent weekly volume. Forecasts follow the recent trend, damped so they level off, and last year's seasonal pattern once there's more than a year of history.
+
+Example: {"type": "Run", "goal_distance_km": 2000}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Forecast Training",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.forecastTraining)
+}
+
+// forecastTraining projects weekly volume and the year-end totals
+func (s *Server) forecastTraining(ctx context.Context, req *mcp.CallToolRequest, input ForecastTrainingInput) (*mcp.CallToolResult, ForecastTrainingOutput, error) {
+	logging.Info("MCP tool call", "tool", "forecast_training", "type", input.Type, "weeks", input.Weeks)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "forecast_training", "input", logging.ToJSON(input))
+	}
+
+	weeks := input.Weeks
+	if weeks <= 0 {
+		weeks = defaultForecastWeeks
+	}
+	if weeks > maxForecastWeeks {
+		weeks = maxForecastWeeks
+	}
+	if input.GoalDistanceKm < 0 || input.GoalHours < 0 {
+		return nil, ForecastTrainingOutput{}, NewInvalidInputError("goals must be positive")
+	}
+	chartFormat, err := parseChartFormat(input.IncludeChart, input.ChartFormat)
+	if err != nil {
+		return nil, ForecastTrainingOutput{}, err
+	}
+
+	queries := s.queries.(ForecastQuerier)
+	now := time.Now()
+	today := calendarDay(now)
+	current := blocks.WeekStart(today)
+
+	rows, err := fetchWeeklyVolume(ctx, queries, input.Type, current.AddDate(0, 0, -7*forecastHistoryWeeks), now)
+	if err != nil {
+		return nil, ForecastTrainingOutput{}, NewDatabaseError(err)
+	}
+	h, err := weeklyHistory(rows, today)
+	if err != nil {
+		return nil, ForecastTrainingOutput{}, NewInternalErrorWithCause("reading weekly volume", err)
+	}
+
+	output := ForecastTrainingOutput{
+		Type:            input.Type,
+		ConfidenceLevel: "80%",
+		Weekly:          make([]ForecastWeek, 0),
+		Insights:        make([]Insight, 0),
+	}
+	distance, ok := forecast.Fit(h.start, h.distance)
+	if !ok {
+		output.HistoryWeeks = len(h.distance)
+		output.Insights = append(output.Insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("Only %d complete weeks of history; forecasts need at least %d", len(h.distance), forecast.MinWeeks),
+		})
+		output.SuggestedActions = SuggestNextActions("forecast")
+		return nil, output, nil
+	}
+	duration, _ := forecast.Fit(h.start, h.duration)
+	output.HistoryWeeks = distance.Weeks()
+	output.Seasonal = distance.Seasonal()
+
+	distancePoints, durationPoints := distance.Forecast(weeks), duration.Forecast(weeks)
+	for i := range distancePoints {
+		d, t := distancePoints[i], durationPoints[i]
+		week := ForecastWeek{
+			Week:     d.Start.Format("2006-01-02"),
+			Distance: distanceRange(d.Mean, d.Low, d.High),
+			Duration: durationRange(t.Mean, t.Low, t.High),
+		}
+		if i == 0 {
+			week.InProgress = true
+			week.Distance.SoFar = formatDistance(h.currentDistance)
+			week.Duration.SoFar = formatDuration(int64(h.currentDuration))
+		}
+		if v, ok := h.lastYear(d.Start, h.distance); ok {
+			week.LastYear = formatDistance(v)
+		}
+		output.Weekly = append(output.Weekly, week)
+	}
+
+	// The rest of the year: what's left of this week, then whole weeks to
+	// December 31st
+	yearEnd := time.Date(today.Year(), 12, 31, 0, 0, 0, 0, time.UTC)
+	daysLeft := int(math.Round(yearEnd.Sub(today).Hours() / 24))
+	var weights []float64
+	for monday := current; !monday.After(yearEnd); monday = monday.AddDate(0, 0, 7) {
+		first := monday
+		if first.Before(today.AddDate(0, 0, 1)) {
+			first = today.AddDate(0, 0, 1)
+		}
+		last := monday.AddDate(0, 0, 6)
+		if last.After(yearEnd) {
+			last = yearEnd
+		}
+		days := math.Max(last.Sub(first).Hours()/24+1, 0)
+		weights = append(weights, days/7)
+	}
+	distanceLeft, distanceSD := distance.Total(weights)
+	durationLeft, durationSD := duration.Total(weights)
+	output.YearEnd = &YearEndForecast{
+		Year:     today.Year(),
+		DaysLeft: daysLeft,
+		Distance: yearEndRange(h.yearDistance, distanceLeft, distanceSD, formatDistance),
+		Duration: yearEndRange(h.yearDuration, durationLeft, durationSD, func(v float64) string { return formatDuration(int64(v)) }),
+	}
+
+	weeksLeft := float64(daysLeft) / 7
+	if input.GoalDistanceKm > 0 {
+		output.Goals = append(output.Goals, goalForecast("distance", input.GoalDistanceKm*1000, h.yearDistance,
+			distanceLeft, distanceSD, weeksLeft, h.recent(h.distance), formatDistance))
+	}
+	if input.GoalHours > 0 {
+		output.Goals = append(output.Goals, goalForecast("duration", input.GoalHours*3600, h.yearDuration,
+			durationLeft, durationSD, weeksLeft, h.recent(h.duration), func(v float64) string { return formatDuration(int64(v)) }))
+	}
+
+	output.Insights = forecastInsights(output, h, distancePoints)
+	output.SuggestedActions = forecastActions(output.Goals)
+
+	var result *mcp.CallToolResult
+	if input.IncludeChart {
+		result = chartResult("forecast_training", output, forecastChart(h, distancePoints, input.Type), chartFormat)
+	}
+
+	logging.Info("MCP tool completed", "tool", "forecast_training", "history_weeks", output.HistoryWeeks, "seasonal", output.Seasonal)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "forecast_training", "output", logging.ToJSON(output))
+	}
+	return result, output, nil
+}
+
+// weeklySeries is complete weeks of volume, oldest first, with this week and
+// this year so far
+type weeklySeries struct {
+	start           time.Time // Monday of the first week with activity
+	distance        []float64 // meters
+	duration        []float64 // seconds
+	currentDistance float64
+	currentDuration float64
+	yearDistance    float64
+	yearDuration    float64
+}
+
+// weeklyHistory turns weekly volume rows into consecutive complete weeks up to
+// the week before today's, filling weeks without activity with zeros
+func weeklyHistory(rows []weeklyVolumeData, today time.Time) (weeklySeries, error) {
+	current := blocks.WeekStart(today)
+	year := fmt.Sprintf("%d-", today.Year())
+
+	type totals struct{ distance, duration float64 }
+	byWeek := make(map[time.Time]totals)
+	var h weeklySeries
+	first := current
+	for _, r := range rows {
+		monday, err := sqliteWeekStart(r.Week)
+		if err != nil {
+			return weeklySeries{}, err
+		}
+		// Week 00 shares its Monday with the last week of the year before
+		t := byWeek[monday]
+		t.distance += r.TotalDistance
+		t.duration += float64(r.TotalDuration)
+		byWeek[monday] = t
+		if monday.Before(first) {
+			first = monday
+		}
+		if len(r.Week) > len(year) && r.Week[:len(year)] == year {
+			h.yearDistance += r.TotalDistance
+			h.yearDuration += float64(r.TotalDuration)
+		}
+	}
+
+	h.start = first
+	for monday := first; monday.Before(current); monday = monday.AddDate(0, 0, 7) {
+		h.distance = append(h.distance, byWeek[monday].distance)
+		h.duration = append(h.duration, byWeek[monday].duration)
+	}
+	h.currentDistance, h.currentDuration = byWeek[current].distance, byWeek[current].duration
+	return h, nil
+}
+
+// lastYear is the value 52 weeks before the week starting monday, if the
+// history reaches back that far
+func (h weeklySeries) lastYear(monday time.Time, values []float64) (float64, bool) {
+	i := int(math.Round(monday.AddDate(0, 0, -7*52).Sub(h.start).Hours() / 24 / 7))
+	if i < 0 || i >= len(values) {
+		return 0, false
+	}
+	return values[i], true
+}
+
+// recent is the average of the last recentForecastWeeks complete weeks
+func (h weeklySeries) recent(values []float64) float64 {
+	n := min(recentForecastWeeks, len(values))
+	if n == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values[len(values)-n:] {
+		sum += v
+	}
+	return sum / float64(n)
+}
+
+func distanceRange(mean, low, high float64) ForecastRange {
+	return ForecastRange{Expected: formatDistance(mean), Low: formatDistance(low), High: formatDistance(high)}
+}
+
+func durationRange(mean, low, high float64) ForecastRange {
+	return ForecastRange{
+		Expected: formatDuration(int64(mean)),
+		Low:      formatDuration(int64(low)),
+		High:     formatDuration(int64(high)),
+	}
+}
+
+// yearEndRange adds the forecast for the rest of the year to the year so far
+func yearEndRange(soFar, left, sd float64, format func(float64) string) ForecastRange {
+	return ForecastRange{
+		SoFar:    format(soFar),
+		Expected: format(soFar + left),
+		Low:      format(soFar + math.Max(left-forecast.Z80*sd, 0)),
+		High:     format(soFar + left + forecast.Z80*sd),
+	}
+}
+
+// goalForecast rates an annual goal against the year-end forecast
+func goalForecast(metric string, goal, soFar, left, sd, weeksLeft, recent float64, format func(float64) string) GoalForecast {
+	g := GoalForecast{
+		Metric:       metric,
+		Goal:         format(goal),
+		SoFar:        format(soFar),
+		Projected:    format(soFar + left),
+		RecentWeekly: format(recent),
+	}
+	remaining := goal - soFar
+	if remaining <= 0 {
+		g.Probability = 100
+		g.RequiredWeekly = format(0)
+		g.Status = goalAchieved
+		return g
+	}
+	if weeksLeft > 0 {
+		g.RequiredWeekly = format(remaining / weeksLeft)
+	} else {
+		g.RequiredWeekly = format(remaining)
+	}
+
+	p := forecast.AtLeast(left, sd, remaining)
+	g.Probability = int(math.Round(p * 100))
+	switch {
+	case p >= goalOnTrackProbability:
+		g.Status = goalOnTrack
+	case p >= goalAtRiskProbability:
+		g.Status = goalAtRisk
+	default:
+		g.Status = goalOffTrack
+	}
+	return g
+}
+
+// forecastInsights summarizes the year end, the coming weeks and goals
+func forecastInsights(output ForecastTrainingOutput, h weeklySeries, points []forecast.Point) []Insight {
+	insights := make([]Insight, 0)
+
+	y := output.YearEnd
+	insights = append(insights, Insight{
+		Type: "trend",
+		Message: fmt.Sprintf("Projected %d total: %s (80%% range %s to %s) and %s of moving time",
+			y.Year, y.Distance.Expected, y.Distance.Low, y.Distance.High, y.Duration.Expected),
+	})
+
+	// The next four weeks against the last four
+	n := min(4, len(points), len(h.distance))
+	var ahead, behind float64
+	for i := range n {
+		ahead += points[i].Mean
+		behind += h.distance[len(h.distance)-1-i]
+	}
+	if behind > 0 {
+		change := (ahead - behind) / behind * 100
+		if math.Abs(change) >= 10 {
+			direction := "rise"
+			if change < 0 {
+				direction = "fall"
+			}
+			msg := fmt.Sprintf("Weekly distance is forecast to %s about %.0f%% over the next %d weeks", direction, math.Abs(change), n)
+			if output.Seasonal {
+				msg += ", in line with the same weeks in earlier years"
+			}
+			insights = append(insights, Insight{Type: "trend", Message: msg})
+		}
+	}
+
+	if !output.Seasonal {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: "Less than a year and a bit of history, so the forecast follows the recent trend without a seasonal pattern",
+		})
+	}
+
+	for _, g := range output.Goals {
+		switch g.Status {
+		case goalAchieved:
+			insights = append(insights, Insight{
+				Type:    "achievement",
+				Message: fmt.Sprintf("%s goal of %s reached with %s so far", metricLabel(g.Metric), g.Goal, g.SoFar),
+			})
+		case goalOnTrack:
+			insights = append(insights, Insight{
+				Type:    "achievement",
+				Message: fmt.Sprintf("On track for %s: %d%% likely, needing %s a week against a recent %s", g.Goal, g.Probability, g.RequiredWeekly, g.RecentWeekly),
+			})
+		case goalAtRisk:
+			insights = append(insights, Insight{
+				Type:    "suggestion",
+				Message: fmt.Sprintf("%s is within reach but not certain (%d%% likely): it needs %s a week against a recent %s", g.Goal, g.Probability, g.RequiredWeekly, g.RecentWeekly),
+			})
+		case goalOffTrack:
+			insights = append(insights, Insight{
+				Type:    "warning",
+				Message: fmt.Sprintf("%s is unlikely at the current rate (%d%%): it needs %s a week against a recent %s, projecting %s", g.Goal, g.Probability, g.RequiredWeekly, g.RecentWeekly, g.Projected),
+			})
+		}
+	}
+	return insights
+}
+
+// forecastActions suggests what to do about goals that need more volume
+func forecastActions(goals []GoalForecast) []SuggestedAction {
+	var actions []SuggestedAction
+	for _, g := range goals {
+		if g.Status != goalAtRisk && g.Status != goalOffTrack {
+			continue
+		}
+		priority := "medium"
+		if g.Status == goalOffTrack {
+			priority = "high"
+		}
+		actions = append(actions, SuggestedAction{
+			Tool:        "check_training_load",
+			Description: fmt.Sprintf("Aim for %s a week to reach %s, building up rather than jumping straight there", g.RequiredWeekly, g.Goal),
+			Priority:    priority,
+		})
+	}
+	return append(actions, SuggestNextActions("forecast")...)
+}
+
+// metricLabel is a capitalized metric name for messages
+func metricLabel(metric string) string {
+	if metric == "duration" {
+		return "Time"
+	}
+	return "Distance"
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+// forecastTestQuerier has seventy weeks of five runs a week up to today,
+// alternating 35 and 45 km weeks, as weekly volume rows
+func forecastTestQuerier(now time.Time) (*MockQuerier, float64) {
+	today := calendarDay(now)
+	first := blocks.WeekStart(today).AddDate(0, 0, -7*70)
+
+	byWeek := make(map[string]*db.GetWeeklyVolumeRow)
+	var keys []string
+	var yearDistance float64
+	for d := first; !d.After(today); d = d.AddDate(0, 0, 1) {
+		weekday := int(d.Sub(first).Hours()/24) % 7
+		if weekday >= 5 {
+			continue
+		}
+		week := int(d.Sub(first).Hours() / 24 / 7)
+		meters := 7000.0
+		if week%2 == 1 {
+			meters = 9000
+		}
+		key := sqliteWeek(d)
+		r, ok := byWeek[key]
+		if !ok {
+			r = &db.GetWeeklyVolumeRow{Week: key, TotalDistance: 0.0, TotalDuration: int64(0)}
+			byWeek[key] = r
+			keys = append(keys, key)
+		}
+		r.ActivityCount++
+		r.TotalDistance = r.TotalDistance.(float64) + meters
+		r.TotalDuration = r.TotalDuration.(int64) + int64(meters/3)
+		if d.Year() == today.Year() {
+			yearDistance += meters
+		}
+	}
+
+	m := &MockQuerier{}
+	// Newest week first, like the query
+	for i := len(keys) - 1; i >= 0; i-- {
+		m.weeklyVolume = append(m.weeklyVolume, *byWeek[keys[i]])
+	}
+	return m, yearDistance
+}
+
+func TestForecastTraining(t *testing.T) {
+	t.Parallel()
+
+	m, yearDistance := forecastTestQuerier(time.Now())
+	srv := New(m)
+
+	_, output, err := srv.forecastTraining(context.Background(), nil, ForecastTrainingInput{
+		Weeks:          6,
+		GoalDistanceKm: yearDistance/1000 + 100000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.HistoryWeeks != 70 || !output.Seasonal {
+		t.Errorf("expected 70 seasonal weeks, got %d (%v)", output.HistoryWeeks, output.Seasonal)
+	}
+	if len(output.Weekly) != 6 || !output.Weekly[0].InProgress || output.Weekly[0].Distance.SoFar == "" {
+		t.Fatalf("expected six weeks starting with this one, got %+v", output.Weekly)
+	}
+	if output.Weekly[1].LastYear == "" {
+		t.Errorf("expected last year's distance, got %+v", output.Weekly[1])
+	}
+	if output.YearEnd == nil || output.YearEnd.Year != time.Now().Year() || output.YearEnd.Distance.SoFar != formatDistance(yearDistance) {
+		t.Errorf("expected this year's %s so far, got %+v", formatDistance(yearDistance), output.YearEnd)
+	}
+
+	if len(output.Goals) != 1 || output.Goals[0].Status != goalOffTrack || output.Goals[0].Probability != 0 {
+		t.Fatalf("expected an unreachable goal, got %+v", output.Goals)
+	}
+	if output.SuggestedActions[0].Priority != "high" || !strings.Contains(output.SuggestedActions[0].Description, output.Goals[0].RequiredWeekly) {
+		t.Errorf("expected an action with the weekly volume needed, got %+v", output.SuggestedActions[0])
+	}
+
+	_, output, err = srv.forecastTraining(context.Background(), nil, ForecastTrainingInput{GoalDistanceKm: 1, GoalHours: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Weekly) != defaultForecastWeeks || len(output.Goals) != 2 || output.Goals[0].Status != goalAchieved || output.Goals[1].Status != goalAchieved {
+		t.Errorf("expected %d weeks and both goals achieved, got %d and %+v", defaultForecastWeeks, len(output.Weekly), output.Goals)
+	}
+
+	if _, _, err := srv.forecastTraining(context.Background(), nil, ForecastTrainingInput{GoalHours: -1}); err == nil {
+		t.Error("expected an error for a negative goal")
+	}
+}
+
+func TestForecastTrainingSQLite(t *testing.T) {
+	t.Parallel()
+
+	// Three runs a week for twelve weeks up to last week, through the driver
+	queries := newSQLiteQueries(t)
+	monday := blocks.WeekStart(calendarDay(time.Now()))
+	var id int64
+	for week := 1; week <= 12; week++ {
+		for _, day := range []int{0, 2, 4} {
+			id++
+			start := monday.AddDate(0, 0, -7*week+day).Add(7 * time.Hour)
+			createSQLiteActivity(t, queries, id, "Run", start, 10000)
+		}
+	}
+
+	rows, err := queries.GetWeeklyVolume(context.Background(), db.GetWeeklyVolumeParams{
+		StartDate:   sql.NullTime{Time: monday.AddDate(0, 0, -7*20), Valid: true},
+		StartDate_2: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 12 || rows[0].Week != sqliteWeek(monday.AddDate(0, 0, -7)) || rows[0].ActivityCount != 3 {
+		t.Fatalf("expected twelve weeks of three runs, got %+v", rows)
+	}
+
+	_, output, err := New(queries).forecastTraining(context.Background(), nil, ForecastTrainingInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.HistoryWeeks != 12 {
+		t.Errorf("expected twelve weeks of history, got %d", output.HistoryWeeks)
+	}
+}
+
+func TestForecastTrainingShortHistory(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{})
+
+	_, output, err := srv.forecastTraining(context.Background(), nil, ForecastTrainingInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.YearEnd != nil || len(output.Insights) != 1 || output.Insights[0].Type != "suggestion" {
+		t.Errorf("expected only a suggestion without history, got %+v", output)
+	}
+}
+
+func TestWeeklyHistory(t *testing.T) {
+	// Week 00 of 2026 finishes the week that started on Monday December 29th
+	rows := []weeklyVolumeData{
+		{Week: "2026-W01", TotalDistance: 30000, TotalDuration: 9000},
+		{Week: "2026-W00", TotalDistance: 10000, TotalDuration: 3000},
+		{Week: "2025-W52", TotalDistance: 20000, TotalDuration: 6000},
+		{Week: "2025-W50", TotalDistance: 40000, TotalDuration: 12000},
+	}
+	today := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	h, err := weeklyHistory(rows, today)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !h.start.Equal(time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected history from December 15th, got %s", h.start)
+	}
+	want := []float64{40000, 0, 30000, 30000}
+	if len(h.distance) != len(want) {
+		t.Fatalf("expected %v, got %v", want, h.distance)
+	}
+	for i := range want {
+		if h.distance[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, h.distance)
+			break
+		}
+	}
+	if h.yearDistance != 40000 || h.currentDistance != 0 {
+		t.Errorf("expected 40 km this year and nothing this week, got %.0f and %.0f", h.yearDistance, h.currentDistance)
+	}
+}
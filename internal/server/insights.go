@@ -169,6 +169,36 @@ func (g *InsightGenerator) GenerateZoneInsights(zonePercentages map[int]float64)
 	return insights
 }
 
+// GenerateIntensityInsights generates insights from a three-zone intensity
+// distribution
+func (g *InsightGenerator) GenerateIntensityInsights(d IntensityDistribution) []Insight {
+	split := fmt.Sprintf("%.0f/%.0f/%.0f", d.Zone1Pct, d.Zone2Pct, d.Zone3Pct)
+
+	switch d.Model {
+	case "polarized":
+		return []Insight{{
+			Type:    "achievement",
+			Message: fmt.Sprintf("Polarized intensity distribution (%s easy/moderate/hard, polarization index %.2f)", split, d.PolarizationIndex),
+		}}
+	case "pyramidal":
+		return []Insight{{
+			Type:    "trend",
+			Message: fmt.Sprintf("Pyramidal intensity distribution (%s easy/moderate/hard), typical of base building", split),
+		}}
+	case "threshold":
+		return []Insight{{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("Most training sits between the thresholds (%s easy/moderate/hard). Making easy days easier and hard days harder usually pays off more.", split),
+		}}
+	case "high_intensity":
+		return []Insight{{
+			Type:    "warning",
+			Message: fmt.Sprintf("As much time above threshold as below it (%s easy/moderate/hard). This is hard to sustain without overreaching.", split),
+		}}
+	}
+	return nil
+}
+
 // GenerateComparisonInsights generates insights from period comparisons
 func (g *InsightGenerator) GenerateComparisonInsights(
 	p1Activities, p2Activities int64,
@@ -319,6 +349,222 @@ func SuggestNextActions(context string) []SuggestedAction {
 				Priority:    "medium",
 			},
 		)
+	case "routes":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "find_activities",
+				Description: "Look up the activities behind these routes",
+				Priority:    "low",
+			},
+			SuggestedAction{
+				Tool:        "get_training_summary",
+				Description: "Get aggregate stats for the same filter",
+				Priority:    "low",
+			},
+		)
+	case "compare_activities":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "compare_route_efforts",
+				Description: "See every effort on this route, not just these two",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "render_activity_map",
+				Description: "Map the activity colored by pace to see where time was lost",
+				Priority:    "low",
+			},
+		)
+	case "route_efforts":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "render_activity_map",
+				Description: "Draw the route on a map",
+				Priority:    "low",
+			},
+			SuggestedAction{
+				Tool:        "analyze_progress",
+				Description: "See whether overall fitness explains the trend",
+				Priority:    "medium",
+			},
+		)
+	case "thresholds":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "analyze_zones",
+				Description: "See time in zone with the current zone boundaries",
+				Priority:    "high",
+			},
+			SuggestedAction{
+				Tool:        "analyze_progress",
+				Description: "See the training behind the change",
+				Priority:    "medium",
+			},
+		)
+	case "describe_workout":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "compare_activities",
+				Description: "Race the previous session rep by rep as a virtual partner",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "count_activities",
+				Description: "Count interval sessions this block with workout_category \"intervals\"",
+				Priority:    "low",
+			},
+		)
+	case "training_blocks":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "check_training_load",
+				Description: "See this week's volume against the last few weeks",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "analyze_zones",
+				Description: "Check how the block's time splits across intensity zones",
+				Priority:    "low",
+			},
+		)
+	case "races":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "get_race_readiness",
+				Description: "Check training against the taper for the next race",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "analyze_training_blocks",
+				Description: "See how the build towards the race is going",
+				Priority:    "low",
+			},
+		)
+	case "race_readiness":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "check_training_load",
+				Description: "Track this week's volume as the race gets closer",
+				Priority:    "low",
+			},
+		)
+	case "forecast":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "check_training_load",
+				Description: "Compare this week with your recent average",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "analyze_progress",
+				Description: "See whether the extra volume is making you faster",
+				Priority:    "low",
+			},
+		)
+	case "data_quality":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "get_personal_records",
+				Description: "See your records without the excluded activities",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "get_activity_details",
+				Description: "Look into a flagged activity",
+				Priority:    "low",
+			},
+		)
+	case "clubs":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "get_club_activity_summary",
+				Description: "See a club's weekly totals and leaderboard",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "get_week_summary",
+				Description: "Compare with your own week",
+				Priority:    "low",
+			},
+		)
+	case "social":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "find_activities",
+				Description: "Look up one of the most-kudoed activities",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "get_training_summary",
+				Description: "Compare engagement with training volume over the same period",
+				Priority:    "low",
+			},
+		)
+	case "overrides":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "get_personal_records",
+				Description: "Check your records with the change",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "check_data_quality",
+				Description: "Look for other activities that need fixing",
+				Priority:    "low",
+			},
+		)
+	case "saved_routes":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "compare_route_efforts",
+				Description: "Compare your efforts on a route you have done before",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "list_starred_segments",
+				Description: "See your PRs on starred segments",
+				Priority:    "low",
+			},
+		)
+	case "starred_segments":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "find_activities",
+				Description: "Find the activity behind a PR",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "list_routes",
+				Description: "Find a saved route to take on a segment",
+				Priority:    "low",
+			},
+		)
+	case "sync_integrity":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "check_data_quality",
+				Description: "Check the stored activities for bad data",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "count_activities",
+				Description: "Count stored activities by type and period",
+				Priority:    "low",
+			},
+		)
+	case "exploration":
+		suggestions = append(suggestions,
+			SuggestedAction{
+				Tool:        "render_activity_map",
+				Description: "Draw the routes behind these tiles on a map",
+				Priority:    "medium",
+			},
+			SuggestedAction{
+				Tool:        "get_activity_route",
+				Description: "Export routes to plan a ride into new tiles",
+				Priority:    "low",
+			},
+		)
 	}
 
 	return suggestions
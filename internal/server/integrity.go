@@ -0,0 +1,278 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SyncIntegrityQuerier defines the interface for sync integrity queries
+type SyncIntegrityQuerier interface {
+	ListSyncIntegrity(ctx context.Context) ([]db.SyncIntegrity, error)
+	ListSyncFailures(ctx context.Context) ([]db.SyncFailure, error)
+	ListSyncRepairs(ctx context.Context, limit int64) ([]db.SyncRepair, error)
+}
+
+// Sync integrity limits
+const (
+	defaultSyncRepairs = 10
+	maxSyncRepairs     = 50
+)
+
+// Input types
+
+// GetSyncIntegrityInput - input for the sync integrity report
+type GetSyncIntegrityInput struct {
+	Limit int `json:"limit,omitempty" jsonschema:"Number of recent repairs to list. Default: 10, Maximum: 50."`
+}
+
+// Output types
+
+type GetSyncIntegrityOutput struct {
+	Status           string                `json:"status"`
+	CheckedAt        string                `json:"checked_at,omitempty"`
+	Periods          []SyncIntegrityPeriod `json:"periods"`
+	FailedActivities []SyncFailureSummary  `json:"failed_activities"`
+	RecentRepairs    []SyncRepairSummary   `json:"recent_repairs"`
+	Insights         []Insight             `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction     `json:"suggested_actions,omitempty"`
+}
+
+// SyncIntegrityPeriod compares Strava's totals for a sport and period with
+// the stored totals
+type SyncIntegrityPeriod struct {
+	Sport          string `json:"sport"`
+	Period         string `json:"period"`
+	Since          string `json:"since,omitempty"`
+	StravaCount    int64  `json:"strava_count"`
+	LocalCount     int64  `json:"local_count"`
+	Missing        int64  `json:"missing"`
+	Extra          int64  `json:"extra"`
+	StravaDistance string `json:"strava_distance"`
+	LocalDistance  string `json:"local_distance"`
+	DistanceDrift  string `json:"distance_drift,omitempty"`
+}
+
+// SyncFailureSummary is an activity that failed to save
+type SyncFailureSummary struct {
+	ActivityID   int64  `json:"activity_id"`
+	Name         string `json:"name,omitempty"`
+	StartDate    string `json:"start_date,omitempty"`
+	Error        string `json:"error"`
+	Attempts     int64  `json:"attempts"`
+	LastFailedAt string `json:"last_failed_at"`
+}
+
+// SyncRepairSummary is one gap repair resync
+type SyncRepairSummary struct {
+	Reason     string `json:"reason"`
+	Window     string `json:"window,omitempty"`
+	Fetched    int64  `json:"fetched"`
+	Added      int64  `json:"added"`
+	Failed     int64  `json:"failed"`
+	Error      string `json:"error,omitempty"`
+	RepairedAt string `json:"repaired_at"`
+}
+
+// registerSyncIntegrityTools registers the sync integrity tools
+func (s *Server) registerSyncIntegrityTools() {
+	logging.Debug("Registering tool", "name", "get_sync_integrity")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "get_sync_integrity",
+		Description: `Report how far the stored activities drift from Strava's own totals, and what was done to repair gaps.
+
+Use when:
+- User asks "Is my data complete?" or "Are any activities missing?"
+- Totals look lower than on Strava
+- Before trusting all-time stats or records
+
+Parameters:
+- limit (int): Recent repairs to list. Default: 10, Max: 50
+
+Returns: For run, ride and swim over the last four weeks, the year to date and all time: Strava's count and distance next to the stored ones, with missing and extra activities. Also activities that failed to save and the recent repairs. A daily background check compares Strava's athlete stats with the stored totals, queues failed activities to be fetched again and the date windows with gaps to be resynced; a repair's fetched and added counts grow as the sync queue lists its window. Strava's stats only count activities visible to everyone, and stored totals leave out excluded activities, so small differences can be expected.
+
+Example: {}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:          "Get Sync Integrity",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  ptr(false),
+		},
+	}, s.getSyncIntegrity)
+}
+
+// getSyncIntegrity reports drift between Strava's totals and the stored ones
+func (s *Server) getSyncIntegrity(ctx context.Context, req *mcp.CallToolRequest, input GetSyncIntegrityInput) (*mcp.CallToolResult, GetSyncIntegrityOutput, error) {
+	logging.Info("MCP tool call", "tool", "get_sync_integrity")
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "get_sync_integrity", "input", logging.ToJSON(input))
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultSyncRepairs
+	}
+	if limit > maxSyncRepairs {
+		limit = maxSyncRepairs
+	}
+
+	queries := s.queries.(SyncIntegrityQuerier)
+	checks, err := queries.ListSyncIntegrity(ctx)
+	if err != nil {
+		return nil, GetSyncIntegrityOutput{}, NewDatabaseError(err)
+	}
+	failures, err := queries.ListSyncFailures(ctx)
+	if err != nil {
+		return nil, GetSyncIntegrityOutput{}, NewDatabaseError(err)
+	}
+	repairs, err := queries.ListSyncRepairs(ctx, int64(limit))
+	if err != nil {
+		return nil, GetSyncIntegrityOutput{}, NewDatabaseError(err)
+	}
+
+	output := GetSyncIntegrityOutput{
+		Status:           "in_sync",
+		Periods:          make([]SyncIntegrityPeriod, 0, len(checks)),
+		FailedActivities: make([]SyncFailureSummary, 0, len(failures)),
+		RecentRepairs:    make([]SyncRepairSummary, 0, len(repairs)),
+	}
+	if len(checks) == 0 {
+		output.Status = "not_checked"
+	}
+
+	var checkedAt time.Time
+	for _, c := range checks {
+		if c.CheckedAt.After(checkedAt) {
+			checkedAt = c.CheckedAt
+		}
+		// Sports the athlete does not do are left out
+		if c.StravaCount == 0 && c.LocalCount == 0 {
+			continue
+		}
+		period := convertSyncIntegrity(c)
+		if period.Missing > 0 {
+			output.Status = "drift"
+		}
+		output.Periods = append(output.Periods, period)
+	}
+	if !checkedAt.IsZero() {
+		output.CheckedAt = checkedAt.Format(time.RFC3339)
+	}
+
+	for _, f := range failures {
+		summary := SyncFailureSummary{
+			ActivityID:   f.ActivityID,
+			Name:         f.ActivityName.String,
+			Error:        f.Error,
+			Attempts:     f.Attempts,
+			LastFailedAt: f.LastFailedAt.Format(time.RFC3339),
+		}
+		if f.StartDate.Valid {
+			summary.StartDate = f.StartDate.Time.Format("2006-01-02")
+		}
+		output.FailedActivities = append(output.FailedActivities, summary)
+	}
+	if len(failures) > 0 {
+		output.Status = "drift"
+	}
+
+	for _, r := range repairs {
+		summary := SyncRepairSummary{
+			Reason:     r.Reason,
+			Fetched:    r.Fetched,
+			Added:      r.Added,
+			Failed:     r.Failed,
+			Error:      r.Error.String,
+			RepairedAt: r.RepairedAt.Format(time.RFC3339),
+		}
+		if r.WindowEnd.Valid {
+			start := "the first activity"
+			if r.WindowStart.Valid {
+				start = r.WindowStart.Time.Format("2006-01-02")
+			}
+			summary.Window = fmt.Sprintf("%s to %s", start, r.WindowEnd.Time.Format("2006-01-02"))
+		}
+		output.RecentRepairs = append(output.RecentRepairs, summary)
+	}
+
+	output.Insights = syncIntegrityInsights(output)
+	if output.Status != "not_checked" {
+		output.SuggestedActions = SuggestNextActions("sync_integrity")
+	}
+
+	logging.Info("MCP tool completed", "tool", "get_sync_integrity", "status", output.Status, "failures", len(output.FailedActivities))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "get_sync_integrity", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// convertSyncIntegrity converts a stored integrity check
+func convertSyncIntegrity(c db.SyncIntegrity) SyncIntegrityPeriod {
+	period := SyncIntegrityPeriod{
+		Sport:          c.Sport,
+		Period:         c.Period,
+		StravaCount:    c.StravaCount,
+		LocalCount:     c.LocalCount,
+		Missing:        max(0, c.StravaCount-c.LocalCount),
+		Extra:          max(0, c.LocalCount-c.StravaCount),
+		StravaDistance: formatDistance(c.StravaDistance),
+		LocalDistance:  formatDistance(c.LocalDistance),
+	}
+	if c.WindowStart.Valid {
+		period.Since = c.WindowStart.Time.Format("2006-01-02")
+	}
+	if c.StravaDistance > 0 {
+		period.DistanceDrift = fmt.Sprintf("%+.1f%%", (c.LocalDistance-c.StravaDistance)/c.StravaDistance*100)
+	}
+	return period
+}
+
+// syncIntegrityInsights explains the drift found and what is being done about it
+func syncIntegrityInsights(output GetSyncIntegrityOutput) []Insight {
+	insights := make([]Insight, 0)
+	if output.Status == "not_checked" {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No integrity check has run yet. It runs in the background five minutes after the server starts and then daily, unless it runs with --no-sync",
+		})
+	}
+
+	var extra bool
+	for _, p := range output.Periods {
+		if p.Period != "all" {
+			continue
+		}
+		if p.Missing > 0 {
+			insights = append(insights, Insight{
+				Type:    "warning",
+				Message: fmt.Sprintf("Strava counts %d more %s activities than are stored. Windows with gaps are resynced, and again whenever a gap grows; excluded activities are not in the stored totals, so they stay as a gap", p.Missing, p.Sport),
+			})
+		}
+		extra = extra || p.Extra > 0
+	}
+	if n := len(output.FailedActivities); n > 0 {
+		message := fmt.Sprintf("%d activities failed to save and are queued to be fetched again", n)
+		if n == 1 {
+			message = fmt.Sprintf("Activity %d failed to save and is queued to be fetched again", output.FailedActivities[0].ActivityID)
+		}
+		insights = append(insights, Insight{Type: "warning", Message: message})
+	}
+	if extra {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: "More activities are stored than Strava's stats count; Strava only counts activities visible to everyone, so private activities show up as extra",
+		})
+	}
+	if output.Status == "in_sync" {
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: "No stored totals fall short of Strava's and no activity is waiting on a failed save",
+		})
+	}
+	return insights
+}
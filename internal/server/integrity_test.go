@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func TestGetSyncIntegrity(t *testing.T) {
+	t.Parallel()
+
+	checkedAt := time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC)
+	m := &MockQuerier{
+		syncIntegrity: []db.SyncIntegrity{
+			{Sport: "ride", Period: "all", StravaCount: 410, LocalCount: 410, StravaDistance: 12000000, LocalDistance: 12000000, CheckedAt: checkedAt},
+			{Sport: "run", Period: "all", StravaCount: 900, LocalCount: 897, StravaDistance: 8000000, LocalDistance: 7960000, CheckedAt: checkedAt},
+			{Sport: "run", Period: "recent", WindowStart: sql.NullTime{Time: checkedAt.AddDate(0, 0, -28), Valid: true},
+				StravaCount: 10, LocalCount: 12, StravaDistance: 80000, LocalDistance: 90000, CheckedAt: checkedAt},
+			// Never swims
+			{Sport: "swim", Period: "all", CheckedAt: checkedAt},
+		},
+		syncFailures: []db.SyncFailure{{ActivityID: 42, ActivityName: sql.NullString{String: "Long Run", Valid: true}, Error: "database is locked", Attempts: 2, LastFailedAt: checkedAt}},
+		syncRepairs: []db.SyncRepair{{
+			Reason: "Strava counts 3 run more in the all window", WindowEnd: sql.NullTime{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			Fetched: 897, Added: 0, RepairedAt: checkedAt,
+		}},
+	}
+
+	_, output, err := New(m).getSyncIntegrity(context.Background(), nil, GetSyncIntegrityInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != "drift" || output.CheckedAt != "2026-10-18T06:00:00Z" || len(output.Periods) != 3 {
+		t.Fatalf("expected drift over three periods, got %+v", output)
+	}
+	run := output.Periods[1]
+	if run.Missing != 3 || run.Extra != 0 || run.DistanceDrift != "-0.5%" {
+		t.Errorf("unexpected run totals: %+v", run)
+	}
+	if recent := output.Periods[2]; recent.Extra != 2 || recent.Since != "2026-09-20" {
+		t.Errorf("unexpected recent totals: %+v", recent)
+	}
+	if len(output.FailedActivities) != 1 || output.FailedActivities[0].Attempts != 2 {
+		t.Errorf("unexpected failures: %+v", output.FailedActivities)
+	}
+	if len(output.RecentRepairs) != 1 || output.RecentRepairs[0].Window != "the first activity to 2026-01-01" {
+		t.Errorf("unexpected repairs: %+v", output.RecentRepairs)
+	}
+
+	var messages []string
+	for _, insight := range output.Insights {
+		messages = append(messages, insight.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{"Strava counts 3 more run activities", "Activity 42 failed to save"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected an insight containing %q, got %v", want, messages)
+		}
+	}
+}
+
+func TestGetSyncIntegrityNotChecked(t *testing.T) {
+	t.Parallel()
+
+	_, output, err := New(&MockQuerier{}).getSyncIntegrity(context.Background(), nil, GetSyncIntegrityInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != "not_checked" || len(output.Insights) != 1 || output.Insights[0].Type != "suggestion" {
+		t.Errorf("expected a single suggestion, got %+v", output)
+	}
+}
@@ -0,0 +1,610 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/routes"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RouteLibraryQuerier defines the interface for saved route and starred
+// segment queries
+type RouteLibraryQuerier interface {
+	ListSavedRoutes(ctx context.Context) ([]db.ListSavedRoutesRow, error)
+	GetSavedRouteGPX(ctx context.Context, id int64) (db.GetSavedRouteGPXRow, error)
+	ListActivitiesForRouteMatch(ctx context.Context, arg db.ListActivitiesForRouteMatchParams) ([]db.Activity, error)
+	ListStarredSegments(ctx context.Context) ([]db.StarredSegment, error)
+}
+
+// Route library limits
+const (
+	defaultLibraryLimit = 20
+	maxLibraryLimit     = 100
+	// routeHistoryTolerance is how far an activity's distance may be from a
+	// saved route's and still be checked against its track
+	routeHistoryTolerance = 0.10
+)
+
+// routeSorts and segmentSorts are the accepted sort_by values, the first
+// being the default
+var (
+	routeSorts   = []string{"name", "distance", "elevation", "flattest", "hilliest", "recent"}
+	segmentSorts = []string{"name", "distance", "grade", "efforts", "recent_pr"}
+)
+
+// Input types
+
+// ListRoutesInput - input for listing saved routes
+type ListRoutesInput struct {
+	RouteID       int64   `json:"route_id,omitempty" jsonschema:"A single saved route to show"`
+	Type          string  `json:"type,omitempty" jsonschema:"Route type: Ride or Run"`
+	MinDistanceKm float64 `json:"min_distance_km,omitempty" jsonschema:"Shortest route to include, in km"`
+	MaxDistanceKm float64 `json:"max_distance_km,omitempty" jsonschema:"Longest route to include, in km"`
+	SortBy        string  `json:"sort_by,omitempty" jsonschema:"name (default), distance, elevation, flattest, hilliest or recent"`
+	Limit         int     `json:"limit,omitempty" jsonschema:"Maximum routes to return. Default: 20, Maximum: 100."`
+	IncludeGPX    bool    `json:"include_gpx,omitempty" jsonschema:"Include the route's GPX export. Requires route_id."`
+}
+
+// ListStarredSegmentsInput - input for listing starred segments
+type ListStarredSegmentsInput struct {
+	ActivityType string `json:"activity_type,omitempty" jsonschema:"Segment type: Ride or Run"`
+	SortBy       string `json:"sort_by,omitempty" jsonschema:"name (default), distance, grade, efforts or recent_pr"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"Maximum segments to return. Default: 20, Maximum: 100."`
+}
+
+// Output types
+
+type ListRoutesOutput struct {
+	Routes           []SavedRouteSummary `json:"routes"`
+	TotalMatching    int                 `json:"total_matching"`
+	GPX              string              `json:"gpx,omitempty"`
+	Insights         []Insight           `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction   `json:"suggested_actions,omitempty"`
+}
+
+// SavedRouteSummary describes a saved route and the athlete's history on it
+type SavedRouteSummary struct {
+	ID            int64         `json:"id"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description,omitempty"`
+	Type          string        `json:"type,omitempty"`
+	SubType       string        `json:"sub_type,omitempty"`
+	Distance      string        `json:"distance"`
+	Elevation     string        `json:"elevation_gain"`
+	ClimbPerKm    string        `json:"climb_per_km"`
+	EstimatedTime string        `json:"estimated_time,omitempty"`
+	Starred       bool          `json:"starred"`
+	Private       bool          `json:"private"`
+	HasGPX        bool          `json:"has_gpx"`
+	UpdatedAt     string        `json:"updated_at,omitempty"`
+	URL           string        `json:"url"`
+	History       *RouteHistory `json:"history,omitempty"`
+}
+
+// RouteHistory is the athlete's synced activities that follow a saved route
+type RouteHistory struct {
+	Completions    int    `json:"completions"`
+	LastCompleted  string `json:"last_completed,omitempty"`
+	BestTime       string `json:"best_time,omitempty"`
+	BestActivityID int64  `json:"best_activity_id,omitempty"`
+}
+
+type ListStarredSegmentsOutput struct {
+	Segments         []StarredSegmentSummary `json:"segments"`
+	TotalMatching    int                     `json:"total_matching"`
+	Insights         []Insight               `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction       `json:"suggested_actions,omitempty"`
+}
+
+// StarredSegmentSummary describes a starred segment and the athlete's
+// history on it
+type StarredSegmentSummary struct {
+	ID            int64          `json:"id"`
+	Name          string         `json:"name"`
+	ActivityType  string         `json:"activity_type,omitempty"`
+	Distance      string         `json:"distance"`
+	AverageGrade  string         `json:"average_grade"`
+	MaximumGrade  string         `json:"maximum_grade"`
+	Elevation     string         `json:"elevation_difference"`
+	ClimbCategory string         `json:"climb_category,omitempty"`
+	Location      string         `json:"location,omitempty"`
+	Private       bool           `json:"private"`
+	URL           string         `json:"url"`
+	History       SegmentHistory `json:"history"`
+}
+
+// SegmentHistory is the athlete's efforts on a segment, as Strava counts them
+type SegmentHistory struct {
+	Efforts      int64  `json:"efforts"`
+	PRTime       string `json:"pr_time,omitempty"`
+	PRDate       string `json:"pr_date,omitempty"`
+	PRActivityID int64  `json:"pr_activity_id,omitempty"`
+}
+
+// registerRouteLibraryTools registers the saved route and starred segment tools
+func (s *Server) registerRouteLibraryTools() {
+	logging.Debug("Registering tool", "name", "list_routes")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "list_routes",
+		Description: `List the routes the athlete created or saved on Strava, with distance, elevation and how often they have done each one.
+
+Use when:
+- User asks "Which of my saved 10k routes is flattest?"
+- User wants a route to ride or run, by length or climbing
+- User asks "Have I ever done my Hill Loop route?"
+- User wants a saved route's GPX to load on a device
+
+Parameters:
+- route_id (int): A single saved route
+- type (string): Ride or Run
+- min_distance_km / max_distance_km (float): Distance range
+- sort_by (string): name (default), distance, elevation, flattest, hilliest (climbing per km) or recent (last updated)
+- limit (int): Maximum routes. Default: 20, Max: 100
+- include_gpx (bool): Include the GPX export. Requires route_id.
+
+Returns: Each route's distance, elevation gain, climbing per km, estimated time and history: how many synced activities followed it, the last time and the best moving time. History compares activity tracks with the route, so it needs both to have map data. Routes sync in the background every few hours.
+
+Example: {"type": "Run", "min_distance_km": 9.5, "max_distance_km": 10.5, "sort_by": "flattest"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:          "List Saved Routes",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  ptr(false),
+		},
+	}, s.listRoutes)
+
+	logging.Debug("Registering tool", "name", "list_starred_segments")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "list_starred_segments",
+		Description: `List the segments the athlete starred on Strava, with grade, location and their PR and effort count on each.
+
+Use when:
+- User asks "What's my PR on my starred climbs?"
+- User asks "Which starred segments have I never done?"
+- User wants a segment to target on their next ride or run
+
+Parameters:
+- activity_type (string): Ride or Run
+- sort_by (string): name (default), distance, grade (steepest first), efforts (most first) or recent_pr
+- limit (int): Maximum segments. Default: 20, Max: 100
+
+Returns: Each segment's distance, average and maximum grade, elevation difference, climb category, location and history: effort count, PR time, date and activity. Segments sync in the background every few hours.
+
+Example: {"activity_type": "Ride", "sort_by": "grade"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:          "List Starred Segments",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  ptr(false),
+		},
+	}, s.listStarredSegments)
+}
+
+// listRoutes lists saved routes with the athlete's history on each
+func (s *Server) listRoutes(ctx context.Context, req *mcp.CallToolRequest, input ListRoutesInput) (*mcp.CallToolResult, ListRoutesOutput, error) {
+	logging.Info("MCP tool call", "tool", "list_routes", "route_id", input.RouteID, "type", input.Type, "sort_by", input.SortBy)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "list_routes", "input", logging.ToJSON(input))
+	}
+
+	routeType, err := libraryActivityType(input.Type, "type")
+	if err != nil {
+		return nil, ListRoutesOutput{}, err
+	}
+	sortBy, err := librarySort(input.SortBy, routeSorts)
+	if err != nil {
+		return nil, ListRoutesOutput{}, err
+	}
+	if input.MinDistanceKm > 0 && input.MaxDistanceKm > 0 && input.MinDistanceKm > input.MaxDistanceKm {
+		return nil, ListRoutesOutput{}, NewInvalidInputError("min_distance_km must not be greater than max_distance_km")
+	}
+	if input.IncludeGPX && input.RouteID == 0 {
+		return nil, ListRoutesOutput{}, NewInvalidInputErrorWithDetails("include_gpx requires route_id", "GPX is returned for one route at a time")
+	}
+	limit := libraryLimit(input.Limit)
+
+	queries := s.queries.(RouteLibraryQuerier)
+	stored, err := queries.ListSavedRoutes(ctx)
+	if err != nil {
+		return nil, ListRoutesOutput{}, NewDatabaseError(err)
+	}
+
+	matching := make([]db.ListSavedRoutesRow, 0, len(stored))
+	for _, r := range stored {
+		if input.RouteID != 0 && r.ID != input.RouteID {
+			continue
+		}
+		if routeType != "" && r.Type.String != routeType {
+			continue
+		}
+		if input.MinDistanceKm > 0 && r.Distance < input.MinDistanceKm*1000 {
+			continue
+		}
+		if input.MaxDistanceKm > 0 && r.Distance > input.MaxDistanceKm*1000 {
+			continue
+		}
+		matching = append(matching, r)
+	}
+	if input.RouteID != 0 && len(matching) == 0 {
+		return nil, ListRoutesOutput{}, NewNotFoundErrorWithID("route", input.RouteID)
+	}
+	sortSavedRoutes(matching, sortBy)
+
+	output := ListRoutesOutput{
+		Routes:        make([]SavedRouteSummary, 0, min(limit, len(matching))),
+		TotalMatching: len(matching),
+	}
+	for _, r := range matching[:min(limit, len(matching))] {
+		summary := convertSavedRoute(r)
+		summary.History, err = savedRouteHistory(ctx, queries, r)
+		if err != nil {
+			return nil, ListRoutesOutput{}, NewDatabaseError(err)
+		}
+		output.Routes = append(output.Routes, summary)
+	}
+
+	if input.IncludeGPX {
+		gpx, err := queries.GetSavedRouteGPX(ctx, input.RouteID)
+		if err != nil {
+			return nil, ListRoutesOutput{}, NewDatabaseError(err)
+		}
+		output.GPX = gpx.Gpx.String
+	}
+
+	output.Insights = savedRouteInsights(output, len(stored), sortBy, input.IncludeGPX)
+	if len(output.Routes) > 0 {
+		output.SuggestedActions = SuggestNextActions("saved_routes")
+	}
+
+	logging.Info("MCP tool completed", "tool", "list_routes", "routes", len(output.Routes), "total_matching", output.TotalMatching)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "list_routes", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// listStarredSegments lists starred segments with the athlete's PR on each
+func (s *Server) listStarredSegments(ctx context.Context, req *mcp.CallToolRequest, input ListStarredSegmentsInput) (*mcp.CallToolResult, ListStarredSegmentsOutput, error) {
+	logging.Info("MCP tool call", "tool", "list_starred_segments", "activity_type", input.ActivityType, "sort_by", input.SortBy)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "list_starred_segments", "input", logging.ToJSON(input))
+	}
+
+	activityType, err := libraryActivityType(input.ActivityType, "activity_type")
+	if err != nil {
+		return nil, ListStarredSegmentsOutput{}, err
+	}
+	sortBy, err := librarySort(input.SortBy, segmentSorts)
+	if err != nil {
+		return nil, ListStarredSegmentsOutput{}, err
+	}
+	limit := libraryLimit(input.Limit)
+
+	stored, err := s.queries.(RouteLibraryQuerier).ListStarredSegments(ctx)
+	if err != nil {
+		return nil, ListStarredSegmentsOutput{}, NewDatabaseError(err)
+	}
+
+	matching := make([]db.StarredSegment, 0, len(stored))
+	for _, seg := range stored {
+		if activityType == "" || seg.ActivityType.String == activityType {
+			matching = append(matching, seg)
+		}
+	}
+	sortStarredSegments(matching, sortBy)
+
+	output := ListStarredSegmentsOutput{
+		Segments:      make([]StarredSegmentSummary, 0, min(limit, len(matching))),
+		TotalMatching: len(matching),
+	}
+	for _, seg := range matching[:min(limit, len(matching))] {
+		output.Segments = append(output.Segments, convertStarredSegment(seg))
+	}
+
+	output.Insights = starredSegmentInsights(matching, len(stored))
+	if len(output.Segments) > 0 {
+		output.SuggestedActions = SuggestNextActions("starred_segments")
+	}
+
+	logging.Info("MCP tool completed", "tool", "list_starred_segments", "segments", len(output.Segments), "total_matching", output.TotalMatching)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "list_starred_segments", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// libraryActivityType normalizes a Ride or Run filter
+func libraryActivityType(value, param string) (string, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", nil
+	case "ride":
+		return "Ride", nil
+	case "run":
+		return "Run", nil
+	}
+	return "", NewInvalidInputErrorWithDetails(fmt.Sprintf("Unknown %s %q", param, value), "Use Ride or Run")
+}
+
+// librarySort checks a sort_by value, defaulting to the first accepted one
+func librarySort(value string, accepted []string) (string, error) {
+	if value == "" {
+		return accepted[0], nil
+	}
+	for _, a := range accepted {
+		if strings.EqualFold(value, a) {
+			return a, nil
+		}
+	}
+	return "", NewInvalidInputErrorWithDetails(fmt.Sprintf("Unknown sort_by %q", value), "Use one of: "+strings.Join(accepted, ", "))
+}
+
+func libraryLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLibraryLimit
+	}
+	return min(limit, maxLibraryLimit)
+}
+
+// climbPerKm is a route's elevation gain per kilometer, in meters
+func climbPerKm(distance, elevationGain float64) float64 {
+	if distance <= 0 {
+		return 0
+	}
+	return elevationGain / (distance / 1000)
+}
+
+// sortSavedRoutes orders routes for a sort_by value, by name within ties
+func sortSavedRoutes(rows []db.ListSavedRoutesRow, sortBy string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch sortBy {
+		case "distance":
+			if a.Distance != b.Distance {
+				return a.Distance < b.Distance
+			}
+		case "elevation":
+			if a.ElevationGain != b.ElevationGain {
+				return a.ElevationGain > b.ElevationGain
+			}
+		case "flattest", "hilliest":
+			ca, cb := climbPerKm(a.Distance, a.ElevationGain), climbPerKm(b.Distance, b.ElevationGain)
+			if ca != cb {
+				return (ca < cb) == (sortBy == "flattest")
+			}
+		case "recent":
+			if !a.UpdatedAt.Time.Equal(b.UpdatedAt.Time) {
+				return a.UpdatedAt.Time.After(b.UpdatedAt.Time)
+			}
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+// sortStarredSegments orders segments for a sort_by value, by name within ties
+func sortStarredSegments(rows []db.StarredSegment, sortBy string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch sortBy {
+		case "distance":
+			if a.Distance != b.Distance {
+				return a.Distance < b.Distance
+			}
+		case "grade":
+			if a.AverageGrade != b.AverageGrade {
+				return a.AverageGrade > b.AverageGrade
+			}
+		case "efforts":
+			if a.EffortCount != b.EffortCount {
+				return a.EffortCount > b.EffortCount
+			}
+		case "recent_pr":
+			// PR dates are YYYY-MM-DD, so they sort as strings
+			if a.PrDate.String != b.PrDate.String {
+				return a.PrDate.String > b.PrDate.String
+			}
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+// savedRouteHistory finds the synced activities that follow a saved route.
+// It returns nil when the route has no track to compare with.
+func savedRouteHistory(ctx context.Context, queries RouteLibraryQuerier, r db.ListSavedRoutesRow) (*RouteHistory, error) {
+	if r.Type.String == "" || r.Distance <= 0 {
+		return nil, nil
+	}
+	track, err := routes.NewTrack(r.Type.String, r.Polyline.String)
+	if err != nil {
+		return nil, nil
+	}
+
+	activities, err := queries.ListActivitiesForRouteMatch(ctx, db.ListActivitiesForRouteMatchParams{
+		Type:       r.Type,
+		Distance:   sql.NullFloat64{Float64: r.Distance * (1 - routeHistoryTolerance), Valid: true},
+		Distance_2: sql.NullFloat64{Float64: r.Distance * (1 + routeHistoryTolerance), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &RouteHistory{}
+	var best int64
+	for _, a := range activities {
+		candidate, err := routes.NewTrack(a.Type.String, a.SummaryPolyline.String)
+		if err != nil {
+			continue
+		}
+		if _, ok := track.Match(candidate); !ok {
+			continue
+		}
+		history.Completions++
+		// Activities come oldest first
+		if a.StartDateLocal.Valid {
+			history.LastCompleted = a.StartDateLocal.Time.Format("2006-01-02")
+		}
+		if t := a.MovingTime.Int64; t > 0 && (best == 0 || t < best) {
+			best = t
+			history.BestActivityID = a.ID
+		}
+	}
+	if best > 0 {
+		history.BestTime = formatDuration(best)
+	}
+	return history, nil
+}
+
+// convertSavedRoute converts a stored saved route
+func convertSavedRoute(r db.ListSavedRoutesRow) SavedRouteSummary {
+	summary := SavedRouteSummary{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description.String,
+		Type:        r.Type.String,
+		SubType:     r.SubType.String,
+		Distance:    formatDistance(r.Distance),
+		Elevation:   fmt.Sprintf("%.0f m", r.ElevationGain),
+		ClimbPerKm:  fmt.Sprintf("%.1f m/km", climbPerKm(r.Distance, r.ElevationGain)),
+		Starred:     r.Starred != 0,
+		Private:     r.Private != 0,
+		HasGPX:      toInt64(r.HasGpx) != 0,
+		URL:         fmt.Sprintf("https://www.strava.com/routes/%d", r.ID),
+	}
+	if r.EstimatedMovingTime > 0 {
+		summary.EstimatedTime = formatDuration(r.EstimatedMovingTime)
+	}
+	if r.UpdatedAt.Valid {
+		summary.UpdatedAt = r.UpdatedAt.Time.Format("2006-01-02")
+	}
+	return summary
+}
+
+// climbCategories names Strava's climb categories, 0 being uncategorized
+var climbCategories = []string{"", "Category 4", "Category 3", "Category 2", "Category 1", "HC"}
+
+// convertStarredSegment converts a stored starred segment
+func convertStarredSegment(seg db.StarredSegment) StarredSegmentSummary {
+	location := make([]string, 0, 3)
+	for _, part := range []sql.NullString{seg.City, seg.State, seg.Country} {
+		if part.String != "" {
+			location = append(location, part.String)
+		}
+	}
+	summary := StarredSegmentSummary{
+		ID:           seg.ID,
+		Name:         seg.Name,
+		ActivityType: seg.ActivityType.String,
+		Distance:     formatDistance(seg.Distance),
+		AverageGrade: fmt.Sprintf("%.1f%%", seg.AverageGrade),
+		MaximumGrade: fmt.Sprintf("%.1f%%", seg.MaximumGrade),
+		Elevation:    fmt.Sprintf("%.0f m", seg.ElevationHigh-seg.ElevationLow),
+		Location:     strings.Join(location, ", "),
+		Private:      seg.Private != 0,
+		URL:          fmt.Sprintf("https://www.strava.com/segments/%d", seg.ID),
+		History: SegmentHistory{
+			Efforts:      seg.EffortCount,
+			PRDate:       seg.PrDate.String,
+			PRActivityID: seg.PrActivityID.Int64,
+		},
+	}
+	if c := seg.ClimbCategory; c > 0 && int(c) < len(climbCategories) {
+		summary.ClimbCategory = climbCategories[c]
+	}
+	if seg.PrElapsedTime.Valid {
+		summary.History.PRTime = formatDuration(seg.PrElapsedTime.Int64)
+	}
+	return summary
+}
+
+// savedRouteInsights answers the sort asked for and points out routes never done
+func savedRouteInsights(output ListRoutesOutput, stored int, sortBy string, includeGPX bool) []Insight {
+	insights := make([]Insight, 0)
+	if stored == 0 {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No saved routes stored yet. Routes sync in the background a few minutes after the server starts, unless it runs with --no-sync",
+		})
+	}
+	if output.TotalMatching == 0 {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("None of the %d saved routes match these filters", stored),
+		})
+	}
+
+	if (sortBy == "flattest" || sortBy == "hilliest") && output.TotalMatching > 1 {
+		first := output.Routes[0]
+		insights = append(insights, Insight{
+			Type:    "trend",
+			Message: fmt.Sprintf("%s is the %s of these %d routes, climbing %s over %s (%s)", first.Name, sortBy, output.TotalMatching, first.Elevation, first.Distance, first.ClimbPerKm),
+		})
+	}
+
+	var never []string
+	for _, r := range output.Routes {
+		if r.History != nil && r.History.Completions == 0 {
+			never = append(never, r.Name)
+		}
+	}
+	if len(never) > 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("No synced activity follows %s yet", strings.Join(never, ", ")),
+		})
+	}
+
+	if includeGPX && output.GPX == "" {
+		insights = append(insights, Insight{
+			Type:    "warning",
+			Message: "This route's GPX has not been exported yet; it is fetched on the next library sync",
+		})
+	}
+	return insights
+}
+
+// starredSegmentInsights points out segments never attempted and the most ridden
+func starredSegmentInsights(matching []db.StarredSegment, stored int) []Insight {
+	insights := make([]Insight, 0)
+	if stored == 0 {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No starred segments stored yet. Segments sync in the background a few minutes after the server starts, unless it runs with --no-sync",
+		})
+	}
+	if len(matching) == 0 {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("None of the %d starred segments match these filters", stored),
+		})
+	}
+
+	var untried int
+	var most db.StarredSegment
+	for _, seg := range matching {
+		if seg.EffortCount == 0 {
+			untried++
+		}
+		if seg.EffortCount > most.EffortCount {
+			most = seg
+		}
+	}
+	if most.EffortCount > 0 {
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("%s is your most-done starred segment, with %d efforts", most.Name, most.EffortCount),
+		})
+	}
+	if untried > 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("%d of these %d starred segments have no effort yet", untried, len(matching)),
+		})
+	}
+	return insights
+}
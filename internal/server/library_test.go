@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+)
+
+// squareLoop is a ~4 km loop starting at the given offset (in degrees)
+func squareLoop(offset float64) string {
+	base := geo.Point{Lat: 37.77 + offset, Lng: -122.45 + offset}
+	return geo.EncodePolyline([]geo.Point{
+		base,
+		{Lat: base.Lat + 0.009, Lng: base.Lng},
+		{Lat: base.Lat + 0.009, Lng: base.Lng + 0.011},
+		{Lat: base.Lat, Lng: base.Lng + 0.011},
+		{Lat: base.Lat + 0.0002, Lng: base.Lng + 0.0002},
+	})
+}
+
+func libraryTestQuerier() *MockQuerier {
+	run := sql.NullString{String: "Run", Valid: true}
+	ride := sql.NullString{String: "Ride", Valid: true}
+	activity := func(id int64, activityType sql.NullString, polyline string, movingTime int64, start time.Time) db.Activity {
+		return db.Activity{
+			ID: id, Name: "Loop", Type: activityType,
+			Distance:        sql.NullFloat64{Float64: 3950, Valid: true},
+			MovingTime:      sql.NullInt64{Int64: movingTime, Valid: true},
+			StartDateLocal:  sql.NullTime{Time: start, Valid: true},
+			SummaryPolyline: sql.NullString{String: polyline, Valid: true},
+		}
+	}
+	return &MockQuerier{
+		savedRoutes: []db.SavedRoute{
+			{ID: 1, Name: "River Loop", Type: run, Distance: 3900, ElevationGain: 20,
+				Polyline: sql.NullString{String: squareLoop(0), Valid: true}, Gpx: sql.NullString{String: "<gpx/>", Valid: true}},
+			{ID: 2, Name: "Hill Loop", Type: run, Distance: 4100, ElevationGain: 160,
+				Polyline: sql.NullString{String: squareLoop(0.05), Valid: true}},
+			{ID: 3, Name: "Century", Type: ride, Distance: 160000, ElevationGain: 1200},
+		},
+		activities: []db.Activity{
+			activity(11, run, squareLoop(0.0003), 1200, time.Date(2026, 9, 1, 7, 0, 0, 0, time.UTC)),
+			activity(12, run, squareLoop(0), 1150, time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)),
+			// Same track on a bike
+			activity(13, ride, squareLoop(0), 600, time.Date(2026, 10, 2, 7, 0, 0, 0, time.UTC)),
+		},
+		starredSegments: []db.StarredSegment{
+			{ID: 21, Name: "Hawk Hill", ActivityType: ride, Distance: 2500, AverageGrade: 5.1, ElevationHigh: 280, ElevationLow: 150,
+				ClimbCategory: 1, EffortCount: 14, PrElapsedTime: sql.NullInt64{Int64: 512, Valid: true}, PrDate: sql.NullString{String: "2026-05-02", Valid: true}},
+			{ID: 22, Name: "Old La Honda", ActivityType: ride, Distance: 5400, AverageGrade: 7.3, ClimbCategory: 3},
+			{ID: 23, Name: "Track Lap", ActivityType: run, Distance: 400, EffortCount: 40},
+		},
+	}
+}
+
+func TestListRoutes(t *testing.T) {
+	t.Parallel()
+
+	srv := New(libraryTestQuerier())
+	input := ListRoutesInput{Type: "run", MinDistanceKm: 3.5, MaxDistanceKm: 4.5, SortBy: "flattest"}
+	_, output, err := srv.listRoutes(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.TotalMatching != 2 || output.Routes[0].Name != "River Loop" || output.Routes[1].Name != "Hill Loop" {
+		t.Fatalf("expected the flatter loop first, got %+v", output.Routes)
+	}
+	river := output.Routes[0]
+	if river.ClimbPerKm != "5.1 m/km" || !river.HasGPX {
+		t.Errorf("unexpected route: %+v", river)
+	}
+	if h := river.History; h == nil || h.Completions != 2 || h.LastCompleted != "2026-10-01" || h.BestActivityID != 12 || h.BestTime != "19m 10s" {
+		t.Errorf("expected two runs on the loop, got %+v", river.History)
+	}
+	if h := output.Routes[1].History; h == nil || h.Completions != 0 {
+		t.Errorf("expected no runs on the hill loop, got %+v", h)
+	}
+	var messages []string
+	for _, insight := range output.Insights {
+		messages = append(messages, insight.Message)
+	}
+	if joined := strings.Join(messages, "\n"); !strings.Contains(joined, "River Loop is the flattest of these 2 routes") || !strings.Contains(joined, "follows Hill Loop") {
+		t.Errorf("expected flattest and never-done insights, got %v", messages)
+	}
+
+	// A route without a track has no history
+	_, output, err = srv.listRoutes(context.Background(), nil, ListRoutesInput{Type: "Ride"})
+	if err != nil || len(output.Routes) != 1 || output.Routes[0].History != nil {
+		t.Errorf("expected the ride without history, got %+v (%v)", output.Routes, err)
+	}
+
+	_, output, err = srv.listRoutes(context.Background(), nil, ListRoutesInput{RouteID: 1, IncludeGPX: true})
+	if err != nil || len(output.Routes) != 1 || output.GPX != "<gpx/>" {
+		t.Errorf("expected the route's GPX, got %+v (%v)", output, err)
+	}
+
+	var toolErr *ToolError
+	if _, _, err := srv.listRoutes(context.Background(), nil, ListRoutesInput{RouteID: 99}); !errors.As(err, &toolErr) || toolErr.Code != ErrNotFound {
+		t.Errorf("expected a missing route error, got %v", err)
+	}
+	for _, bad := range []ListRoutesInput{{IncludeGPX: true}, {SortBy: "longest"}, {Type: "Swim"}, {MinDistanceKm: 10, MaxDistanceKm: 5}} {
+		if _, _, err := srv.listRoutes(context.Background(), nil, bad); err == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
+
+func TestListRoutesEmpty(t *testing.T) {
+	t.Parallel()
+
+	_, output, err := New(&MockQuerier{}).listRoutes(context.Background(), nil, ListRoutesInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Routes) != 0 || len(output.Insights) != 1 || output.Insights[0].Type != "suggestion" {
+		t.Errorf("expected a single suggestion, got %+v", output)
+	}
+}
+
+func TestListStarredSegments(t *testing.T) {
+	t.Parallel()
+
+	srv := New(libraryTestQuerier())
+	_, output, err := srv.listStarredSegments(context.Background(), nil, ListStarredSegmentsInput{ActivityType: "Ride", SortBy: "grade"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.TotalMatching != 2 || output.Segments[0].Name != "Old La Honda" {
+		t.Fatalf("expected the steepest ride segment first, got %+v", output.Segments)
+	}
+	hawk := output.Segments[1]
+	if hawk.ClimbCategory != "Category 4" || hawk.Elevation != "130 m" || hawk.History.PRTime != "8m 32s" || hawk.History.Efforts != 14 {
+		t.Errorf("unexpected segment: %+v", hawk)
+	}
+	var messages []string
+	for _, insight := range output.Insights {
+		messages = append(messages, insight.Message)
+	}
+	if joined := strings.Join(messages, "\n"); !strings.Contains(joined, "Hawk Hill is your most-done") || !strings.Contains(joined, "1 of these 2 starred segments have no effort") {
+		t.Errorf("unexpected insights: %v", messages)
+	}
+
+	if _, _, err := srv.listStarredSegments(context.Background(), nil, ListStarredSegmentsInput{SortBy: "popular"}); err == nil {
+		t.Error("expected an error for an unknown sort")
+	}
+}
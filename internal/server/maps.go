@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/joshdurbin/strava-mcp/internal/chart"
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MapsQuerier defines the interface for map rendering queries
+type MapsQuerier interface {
+	RoutesQuerier
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+}
+
+// maxMapTrackPoints caps the points drawn per track; full-resolution streams
+// can hold several thousand samples, far more than the image can show
+const maxMapTrackPoints = 1000
+
+// minMovingSpeed is the speed (m/s) below which a sample is treated as
+// stopped and left out of the pace gradient
+const minMovingSpeed = 0.5
+
+// Input types
+
+// RenderActivityMapInput - input for rendering route maps
+type RenderActivityMapInput struct {
+	ActivityID  int64  `json:"activity_id,omitempty" jsonschema:"Render a single activity by its Strava activity ID. When set, overrides the filter parameters."`
+	Type        string `json:"type,omitempty" jsonschema:"Filter by activity type for an overlay map. Common values: Run, Ride, Walk, Hike. Leave empty for all types."`
+	StartDate   string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD (e.g., 2024-01-15)."`
+	EndDate     string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD (e.g., 2024-12-31)."`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of activities to overlay. Default: 20, Maximum: 100."`
+	ColorBy     string `json:"color_by,omitempty" jsonschema:"Color the route by a stream metric. Valid values: 'none', 'pace', 'heartrate'. Requires synced activity streams. Default: none."`
+	ShowMarkers bool   `json:"show_markers,omitempty" jsonschema:"Draw green start and red finish markers on each route. Default: false."`
+	Format      string `json:"format,omitempty" jsonschema:"Image format. Valid values: 'png', 'svg'. Default: png."`
+}
+
+// Output types
+
+type RenderActivityMapOutput struct {
+	Format           string            `json:"format"`
+	ColorBy          string            `json:"color_by"`
+	ActivityCount    int               `json:"activity_count"`
+	Filter           string            `json:"filter,omitempty"`
+	Activities       []MapActivity     `json:"activities"`
+	Insights         []Insight         `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions,omitempty"`
+}
+
+// MapActivity describes one route drawn on the map
+type MapActivity struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Date     string `json:"date"`
+	Distance string `json:"distance"`
+	// Source is "streams" when the full-resolution GPS stream was drawn,
+	// otherwise "polyline" for the simplified summary polyline
+	Source string `json:"source"`
+}
+
+// registerMapTools registers the map rendering tool
+func (s *Server) registerMapTools() {
+	logging.Debug("Registering tool", "name", "render_activity_map")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "render_activity_map",
+		Description: `Draw one activity's route, or an overlay of many, as a PNG or SVG image. Rendered locally with no map tiles, so no data is sent to an external map service.
+
+Use when:
+- User asks "Show me my run from this morning" or "What did that route look like?"
+- User wants to see where they have been running or riding over a period
+- User asks where they were fast/slow or where their heart rate spiked on a route
+
+Parameters:
+- activity_id (int): Render a single activity. Overrides filters.
+- type (string): Filter by activity type for an overlay (Run, Ride, etc.)
+- start_date, end_date (string): Date range filter, YYYY-MM-DD format
+- limit (int): Maximum activities to overlay (default 20, max 100)
+- color_by (string): "none" (default), "pace", or "heartrate" gradient from activity streams
+- show_markers (bool): Draw start (green) and finish (red) markers
+- format (string): "png" (default) or "svg"
+
+Returns: The map image (north up, with a scale bar and a legend when colored) plus the list of activities drawn. Indoor activities without GPS are skipped.
+
+Example: {"activity_id": 12345678, "color_by": "pace", "show_markers": true} or {"type": "Ride", "start_date": "2024-06-01"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Render Activity Map",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.renderActivityMap)
+}
+
+// renderActivityMap draws activity routes as an image
+func (s *Server) renderActivityMap(ctx context.Context, req *mcp.CallToolRequest, input RenderActivityMapInput) (*mcp.CallToolResult, RenderActivityMapOutput, error) {
+	logging.Info("MCP tool call", "tool", "render_activity_map", "activity_id", input.ActivityID, "type", input.Type, "color_by", input.ColorBy)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "render_activity_map", "input", logging.ToJSON(input))
+	}
+
+	format, err := chart.ParseFormat(input.Format)
+	if err != nil {
+		return nil, RenderActivityMapOutput{}, NewInvalidInputErrorWithDetails("invalid format", "expected 'png' or 'svg'")
+	}
+
+	colorBy := input.ColorBy
+	if colorBy == "" {
+		colorBy = "none"
+	}
+	if colorBy != "none" && colorBy != "pace" && colorBy != "heartrate" {
+		return nil, RenderActivityMapOutput{}, NewInvalidInputErrorWithDetails("invalid color_by", "expected 'none', 'pace' or 'heartrate'")
+	}
+
+	queries := s.queries.(MapsQuerier)
+	output := RenderActivityMapOutput{Format: string(format), ColorBy: colorBy, Activities: []MapActivity{}}
+
+	var activities []db.Activity
+	if input.ActivityID > 0 {
+		activity, err := queries.GetActivity(ctx, input.ActivityID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, RenderActivityMapOutput{}, NewNotFoundErrorWithID("activity", input.ActivityID)
+			}
+			return nil, RenderActivityMapOutput{}, NewDatabaseError(err)
+		}
+		activities = []db.Activity{activity}
+		output.Filter = fmt.Sprintf("id=%d", input.ActivityID)
+	} else {
+		startTime, endTime, err := parseServerDateRange(input.StartDate, input.EndDate)
+		if err != nil {
+			return nil, RenderActivityMapOutput{}, NewInvalidInputError(err.Error())
+		}
+		hasType := input.Type != ""
+		activities, err = queries.SearchActivitiesWithRoute(ctx, db.SearchActivitiesWithRouteParams{
+			Column1:     sql.NullString{String: input.Type, Valid: hasType},
+			Type:        sql.NullString{String: input.Type, Valid: hasType},
+			Column3:     startTime,
+			StartDate:   startTime,
+			Column5:     endTime,
+			StartDate_2: endTime,
+			Limit:       int64(applyLimit(input.Limit)),
+		})
+		if err != nil {
+			return nil, RenderActivityMapOutput{}, NewDatabaseError(err)
+		}
+		output.Filter = buildFilterDesc(input.Type, input.StartDate, input.EndDate)
+	}
+
+	routeMap := chart.RouteMap{ShowMarkers: input.ShowMarkers}
+	switch colorBy {
+	case "pace":
+		routeMap.GradientLabel = "pace"
+		routeMap.ValueFormat = formatPace
+	case "heartrate":
+		routeMap.GradientLabel = "bpm"
+	}
+
+	withoutStreams := 0
+	for _, a := range activities {
+		track, source, err := mapTrack(ctx, queries, a, colorBy)
+		if err != nil {
+			return nil, RenderActivityMapOutput{}, NewDatabaseError(err)
+		}
+		if len(track.Points) == 0 {
+			logging.Debug("Skipping activity without GPS data", "activity_id", a.ID)
+			continue
+		}
+		if colorBy != "none" && track.Values == nil {
+			withoutStreams++
+		}
+
+		routeMap.Tracks = append(routeMap.Tracks, track)
+		output.Activities = append(output.Activities, MapActivity{
+			ID:       a.ID,
+			Name:     a.Name,
+			Type:     a.Type.String,
+			Date:     a.StartDate.Time.Format("2006-01-02"),
+			Distance: formatDistance(a.Distance.Float64),
+			Source:   source,
+		})
+	}
+	output.ActivityCount = len(output.Activities)
+
+	switch output.ActivityCount {
+	case 0:
+		routeMap.Title = "No routes"
+		output.Insights = append(output.Insights, Insight{
+			Type:    "warning",
+			Message: "No GPS routes found. Indoor activities and activities synced before route support have no polyline.",
+		})
+	case 1:
+		routeMap.Title = output.Activities[0].Name
+	default:
+		routeMap.Title = fmt.Sprintf("%d activities", output.ActivityCount)
+		if output.Filter != "" && output.Filter != "all time" {
+			routeMap.Title += " " + output.Filter
+		}
+	}
+
+	if withoutStreams > 0 {
+		output.Insights = append(output.Insights, Insight{
+			Type: "warning",
+			Message: fmt.Sprintf("%d of %d routes have no %s stream data (not recorded, or streams not synced yet) and are drawn without coloring. Streams are backfilled in the background while sync is running.",
+				withoutStreams, output.ActivityCount, colorBy),
+		})
+	}
+	output.SuggestedActions = SuggestNextActions("routes")
+
+	result := chartResult("render_activity_map", output, routeMap, format)
+
+	logging.Info("MCP tool completed", "tool", "render_activity_map", "activities", output.ActivityCount, "format", output.Format)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "render_activity_map", "activity_count", output.ActivityCount)
+	}
+	return result, output, nil
+}
+
+// mapTrack builds the track for an activity. Coloring needs the activity's
+// streams; otherwise, or when no streams are stored yet, the summary
+// polyline is drawn instead.
+func mapTrack(ctx context.Context, queries MapsQuerier, a db.Activity, colorBy string) (chart.Track, string, error) {
+	if colorBy != "none" {
+		row, err := queries.GetActivityStreams(ctx, a.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return chart.Track{}, "", err
+		}
+		if err == nil {
+			st, err := streams.FromRow(row)
+			if err != nil {
+				logging.Warn("Ignoring unreadable activity streams", "activity_id", a.ID, "error", err)
+			} else if points := st.Points(); len(points) > 0 {
+				return downsampleTrack(points, streamValues(st, colorBy)), "streams", nil
+			}
+		}
+	}
+
+	route, err := geo.RouteFromActivity(a)
+	if err != nil {
+		return chart.Track{}, "", nil
+	}
+	return chart.Track{Points: route.Points}, "polyline", nil
+}
+
+// streamValues picks the gradient metric from the streams; nil when the
+// activity didn't record it
+func streamValues(st *streams.Streams, colorBy string) []float64 {
+	switch colorBy {
+	case "pace":
+		if len(st.Velocity) == 0 {
+			return nil
+		}
+		values := make([]float64, len(st.Velocity))
+		for i, v := range st.Velocity {
+			values[i] = v
+			if v < minMovingSpeed {
+				values[i] = math.NaN()
+			}
+		}
+		return values
+	case "heartrate":
+		if len(st.Heartrate) == 0 {
+			return nil
+		}
+		values := make([]float64, len(st.Heartrate))
+		for i, v := range st.Heartrate {
+			values[i] = v
+			if v <= 0 {
+				values[i] = math.NaN()
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// downsampleTrack keeps every n-th sample so a track stays under maxMapTrackPoints,
+// always keeping the final point
+func downsampleTrack(points []geo.Point, values []float64) chart.Track {
+	if len(values) != len(points) {
+		values = nil
+	}
+	if len(points) <= maxMapTrackPoints {
+		return chart.Track{Points: points, Values: values}
+	}
+
+	stride := int(math.Ceil(float64(len(points)) / maxMapTrackPoints))
+	var track chart.Track
+	for i := 0; i < len(points); i += stride {
+		track.Points = append(track.Points, points[i])
+		if values != nil {
+			track.Values = append(track.Values, values[i])
+		}
+	}
+	if last := len(points) - 1; last%stride != 0 {
+		track.Points = append(track.Points, points[last])
+		if values != nil {
+			track.Values = append(track.Values, values[last])
+		}
+	}
+	return track
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+func mapTestQuerier() *MockQuerier {
+	return &MockQuerier{
+		activities: routeTestActivities(),
+		streams: map[int64]db.ActivityStream{
+			1: {
+				ActivityID:    1,
+				PointCount:    4,
+				LatlngData:    sql.NullString{String: "[[38.5,-120.2],[38.6,-120.3],[38.7,-120.4],[38.8,-120.5]]", Valid: true},
+				VelocityData:  sql.NullString{String: "[0,2.8,3.1,3.3]", Valid: true},
+				HeartrateData: sql.NullString{String: "[130,140,150,160]", Valid: true},
+			},
+		},
+	}
+}
+
+func TestRenderActivityMapSingle(t *testing.T) {
+	t.Parallel()
+
+	srv := New(mapTestQuerier())
+
+	result, output, err := srv.renderActivityMap(context.Background(), nil, RenderActivityMapInput{
+		ActivityID:  1,
+		ColorBy:     "pace",
+		ShowMarkers: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.ActivityCount != 1 || output.Activities[0].Source != "streams" {
+		t.Errorf("expected one activity drawn from streams, got %+v", output.Activities)
+	}
+	if len(output.Insights) != 0 {
+		t.Errorf("expected no insights, got %+v", output.Insights)
+	}
+
+	img := imageContent(t, result)
+	if img.MIMEType != "image/png" {
+		t.Errorf("expected image/png, got %s", img.MIMEType)
+	}
+}
+
+func TestRenderActivityMapOverlay(t *testing.T) {
+	t.Parallel()
+
+	srv := New(mapTestQuerier())
+
+	result, output, err := srv.renderActivityMap(context.Background(), nil, RenderActivityMapInput{
+		ColorBy: "heartrate",
+		Format:  "svg",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The treadmill run has no GPS and is skipped
+	if output.ActivityCount != 2 {
+		t.Fatalf("expected 2 routes, got %+v", output.Activities)
+	}
+	if output.Activities[1].Source != "polyline" {
+		t.Errorf("expected the ride to fall back to its polyline, got %s", output.Activities[1].Source)
+	}
+	if len(output.Insights) != 1 || !strings.Contains(output.Insights[0].Message, "1 of 2") {
+		t.Errorf("expected an insight about missing streams, got %+v", output.Insights)
+	}
+
+	img := imageContent(t, result)
+	if img.MIMEType != "image/svg+xml" || !strings.Contains(string(img.Data), "2 activities") {
+		t.Errorf("unexpected SVG output (%s)", img.MIMEType)
+	}
+}
+
+func TestRenderActivityMapValidation(t *testing.T) {
+	t.Parallel()
+
+	srv := New(mapTestQuerier())
+	ctx := context.Background()
+
+	if _, _, err := srv.renderActivityMap(ctx, nil, RenderActivityMapInput{ColorBy: "cadence"}); err == nil {
+		t.Error("expected error for invalid color_by")
+	}
+	if _, _, err := srv.renderActivityMap(ctx, nil, RenderActivityMapInput{Format: "jpeg"}); err == nil {
+		t.Error("expected error for invalid format")
+	}
+	if _, _, err := srv.renderActivityMap(ctx, nil, RenderActivityMapInput{ActivityID: 999}); err == nil {
+		t.Error("expected not found error")
+	}
+}
+
+func TestDownsampleTrack(t *testing.T) {
+	points := make([]geo.Point, 2500)
+	values := make([]float64, 2500)
+	for i := range points {
+		points[i] = geo.Point{Lat: float64(i), Lng: float64(i)}
+		values[i] = float64(i)
+	}
+
+	track := downsampleTrack(points, values)
+	if len(track.Points) > maxMapTrackPoints+1 || len(track.Points) != len(track.Values) {
+		t.Fatalf("expected at most %d aligned points, got %d points and %d values",
+			maxMapTrackPoints+1, len(track.Points), len(track.Values))
+	}
+	if last := track.Points[len(track.Points)-1]; last.Lat != 2499 {
+		t.Errorf("expected the final point to be kept, got %v", last)
+	}
+
+	// Misaligned values are dropped rather than mis-coloring the route
+	if track := downsampleTrack(points[:10], values[:5]); track.Values != nil {
+		t.Error("expected misaligned values to be dropped")
+	}
+}
+
+func TestStreamValuesPace(t *testing.T) {
+	values := streamValues(mapStreams(t), "pace")
+	if len(values) != 4 || !math.IsNaN(values[0]) || values[2] != 3.1 {
+		t.Errorf("unexpected pace values %v", values)
+	}
+}
+
+func mapStreams(t *testing.T) *streams.Streams {
+	t.Helper()
+	st, err := streams.FromRow(mapTestQuerier().streams[1])
+	if err != nil {
+		t.Fatalf("decoding streams: %v", err)
+	}
+	return st
+}
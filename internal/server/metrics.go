@@ -1,13 +1,17 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/joshdurbin/strava-mcp/internal/db"
 	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/workouts"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -177,10 +181,18 @@ func parseDateRange(startDate, endDate string) (sql.NullTime, sql.NullTime, erro
 
 // Helper to build filter description
 func buildFilterDesc(activityType, startDate, endDate string) string {
+	return buildWorkoutFilterDesc(activityType, "", startDate, endDate)
+}
+
+// buildWorkoutFilterDesc is buildFilterDesc with a workout category filter
+func buildWorkoutFilterDesc(activityType, category, startDate, endDate string) string {
 	var parts []string
 	if activityType != "" {
 		parts = append(parts, "type="+activityType)
 	}
+	if category != "" {
+		parts = append(parts, "workout="+category)
+	}
 	if startDate != "" || endDate != "" {
 		if startDate != "" && endDate != "" {
 			parts = append(parts, fmt.Sprintf("date=%s to %s", startDate, endDate))
@@ -196,6 +208,45 @@ func buildFilterDesc(activityType, startDate, endDate string) string {
 	return fmt.Sprintf("%v", parts)
 }
 
+// workoutCategoryFilter validates a workout_category input. An empty category
+// gives a NULL filter, which the queries ignore.
+func workoutCategoryFilter(category string) (sql.NullString, error) {
+	if category == "" {
+		return sql.NullString{}, nil
+	}
+	if !slices.Contains(workouts.Categories, category) {
+		return sql.NullString{}, NewInvalidInputErrorWithDetails(
+			"invalid workout_category: "+category,
+			"expected one of "+strings.Join(workouts.Categories, ", "),
+		)
+	}
+	return sql.NullString{String: category, Valid: true}, nil
+}
+
+// sumWorkoutCounts totals workout category counts by key, largest first
+func sumWorkoutCounts[T any](rows []db.GetWorkoutCategoryCountsRow, key func(db.GetWorkoutCategoryCountsRow) string, build func(string, int64) T) ([]T, int64) {
+	counts := make(map[string]int64)
+	var keys []string
+	var total int64
+	for _, r := range rows {
+		k := key(r)
+		if _, ok := counts[k]; !ok {
+			keys = append(keys, k)
+		}
+		counts[k] += r.Count
+		total += r.Count
+	}
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return cmp.Compare(counts[b], counts[a])
+	})
+
+	result := make([]T, len(keys))
+	for i, k := range keys {
+		result[i] = build(k, counts[k])
+	}
+	return result, total
+}
+
 // Helper to convert interface{} to float64
 func toFloat64(v interface{}) float64 {
 	if v == nil {
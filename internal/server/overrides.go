@@ -0,0 +1,338 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// OverridesQuerier defines the interface for local activity correction queries
+type OverridesQuerier interface {
+	GetActivity(ctx context.Context, id int64) (db.Activity, error)
+	GetActivityOverride(ctx context.Context, activityID int64) (db.ActivityOverride, error)
+	SaveActivityCorrection(ctx context.Context, arg db.UpsertActivityCorrectionParams) error
+	ResetActivityOverride(ctx context.Context, activityID int64) error
+	SetActivityExclusion(ctx context.Context, arg db.SetActivityCountedParams) error
+	IsActivityExcluded(ctx context.Context, activityID int64) (int64, error)
+}
+
+// Exclusion actions
+const (
+	exclusionExclude = "exclude"
+	exclusionInclude = "include"
+	exclusionClear   = "clear"
+)
+
+// Input types
+
+// UpdateActivityLocalInput - input for correcting an activity locally
+type UpdateActivityLocalInput struct {
+	ActivityID   int64   `json:"activity_id" jsonschema:"The activity to correct."`
+	Name         string  `json:"name,omitempty" jsonschema:"Corrected name. Leave empty to keep the current one."`
+	Type         string  `json:"type,omitempty" jsonschema:"Corrected activity type, e.g. 'Ride' for a ride logged as a run. Common values: Run, Ride, Swim, Walk, Hike. Leave empty to keep the current one."`
+	DistanceKm   float64 `json:"distance_km,omitempty" jsonschema:"Corrected distance in kilometers; the average speed is recalculated from it. Leave empty to keep the current one."`
+	Reset        bool    `json:"reset,omitempty" jsonschema:"When true, drop all corrections and go back to the values from Strava. Other fields are ignored."`
+	ConfirmToken string  `json:"confirm_token,omitempty" jsonschema:"Token from an earlier reset's confirmation, once the user has agreed to drop the corrections."`
+}
+
+// ExcludeActivityInput - input for leaving an activity out of stats
+type ExcludeActivityInput struct {
+	ActivityID int64  `json:"activity_id" jsonschema:"The activity to exclude or include."`
+	Action     string `json:"action,omitempty" jsonschema:"Valid values: 'exclude' (leave out of records, progress and totals), 'include' (count it even when check_data_quality flags it), 'clear' (leave it to the data quality checks). Default: exclude."`
+	Reason     string `json:"reason,omitempty" jsonschema:"Optional note on why, e.g. 'treadmill calibration off'."`
+}
+
+// Output types
+
+type UpdateActivityLocalOutput struct {
+	Activity ActivitySummary `json:"activity"`
+	// Corrections are the values in place of Strava's, empty after a reset
+	Corrections      []Correction      `json:"corrections"`
+	Message          string            `json:"message"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+	// Confirmation is set instead of resetting when the user still needs to
+	// approve dropping the corrections
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+// Correction is a local value replacing Strava's
+type Correction struct {
+	Field    string `json:"field"`
+	Value    string `json:"value"`
+	Original string `json:"original,omitempty"`
+}
+
+type ExcludeActivityOutput struct {
+	Activity ActivitySummary `json:"activity"`
+	// Status is excluded, included or automatic
+	Status string `json:"status"`
+	// Excluded is true when the activity is left out of stats, by hand or
+	// by the data quality checks
+	Excluded         bool              `json:"excluded"`
+	Reason           string            `json:"reason,omitempty"`
+	Message          string            `json:"message"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+}
+
+// registerOverrideTools registers the local activity correction tools
+func (s *Server) registerOverrideTools() {
+	logging.Debug("Registering tool", "name", "update_activity_local")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "update_activity_local",
+		Description: `Correct an activity's name, type or distance in the local database without changing it on Strava.
+
+Use when:
+- An activity has the wrong type, e.g. "That run was actually a bike ride"
+- The distance is wrong, e.g. "My watch said 30 km but it was a 10 km run"
+- User wants to rename an activity for their own records
+- User wants to undo earlier corrections
+
+Parameters:
+- activity_id (int): Required. The activity to correct.
+- name (string): Corrected name
+- type (string): Corrected type (Run, Ride, etc.)
+- distance_km (number): Corrected distance in km; average speed is recalculated
+- reset (boolean): Go back to Strava's values
+- confirm_token (string): Token from an earlier reset's confirmation, once the user agreed
+
+Returns: The activity with the corrections applied, and each corrected field with Strava's original value. Corrections apply to every other tool, survive later syncs, and can be reset at any time; the user is asked to confirm a reset, and clients that cannot ask get a confirmation with a token to call again with. Data quality flags and workout categories are refreshed after the next sync.
+
+Example: {"activity_id": 12345678, "type": "Ride"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Update Activity Locally",
+			ReadOnlyHint:    false,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.updateActivityLocal)
+
+	logging.Debug("Registering tool", "name", "exclude_activity")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "exclude_activity",
+		Description: `Leave an activity out of records, progress and totals, or count one that the data quality checks left out.
+
+Use when:
+- User says "Ignore that activity" or "Don't count that run in my stats"
+- A record or total is skewed by a bad activity
+- check_data_quality flagged an activity that is actually fine
+
+Parameters:
+- activity_id (int): Required. The activity.
+- action (string): "exclude", "include" (count it even when flagged) or "clear" (back to the data quality checks). Default: "exclude".
+- reason (string): Optional note on why
+
+Returns: The activity, whether it is now left out of stats, and why. The activity stays on Strava and in search results.
+
+Example: {"activity_id": 12345678, "reason": "GPS went haywire"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Exclude Activity",
+			ReadOnlyHint:    false,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.excludeActivity)
+}
+
+// updateActivityLocal applies or resets local corrections to an activity
+func (s *Server) updateActivityLocal(ctx context.Context, req *mcp.CallToolRequest, input UpdateActivityLocalInput) (*mcp.CallToolResult, UpdateActivityLocalOutput, error) {
+	logging.Info("MCP tool call", "tool", "update_activity_local", "activity_id", input.ActivityID, "reset", input.Reset)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "update_activity_local", "input", logging.ToJSON(input))
+	}
+
+	name, activityType := strings.TrimSpace(input.Name), strings.TrimSpace(input.Type)
+	if !input.Reset {
+		if name == "" && activityType == "" && input.DistanceKm == 0 {
+			return nil, UpdateActivityLocalOutput{}, NewInvalidInputErrorWithDetails(
+				"Nothing to correct", "Pass name, type or distance_km, or reset to go back to Strava's values")
+		}
+		if input.DistanceKm < 0 {
+			return nil, UpdateActivityLocalOutput{}, NewInvalidInputError("distance_km must be positive")
+		}
+	}
+
+	queries := s.queries.(OverridesQuerier)
+	activity, err := overrideActivity(ctx, queries, input.ActivityID)
+	if err != nil {
+		return nil, UpdateActivityLocalOutput{}, err
+	}
+
+	var message string
+	if input.Reset {
+		current, err := queries.GetActivityOverride(ctx, input.ActivityID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, UpdateActivityLocalOutput{}, NewDatabaseError(err)
+		}
+		if dropped := corrections(current); err == nil && len(dropped) > 0 {
+			changes := make([]Change, 0, len(dropped))
+			for _, c := range dropped {
+				changes = append(changes, Change{Field: c.Field, From: c.Value, To: c.Original})
+			}
+			action := fmt.Sprintf("Drop the local corrections to activity %d", input.ActivityID)
+			confirmation, err := s.confirm(ctx, req, "update_activity_local", action, changes, input, input.ConfirmToken)
+			if err != nil {
+				return nil, UpdateActivityLocalOutput{}, err
+			}
+			if confirmation != nil {
+				output := UpdateActivityLocalOutput{
+					Activity:         convertActivity(activity),
+					Corrections:      dropped,
+					Message:          "Waiting for the user to confirm dropping the corrections",
+					SuggestedActions: SuggestNextActions("overrides"),
+					Confirmation:     confirmation,
+				}
+				logging.Info("MCP tool completed", "tool", "update_activity_local", "activity_id", input.ActivityID, "status", "awaiting_confirmation")
+				return nil, output, nil
+			}
+		}
+		if err := queries.ResetActivityOverride(ctx, input.ActivityID); err != nil {
+			return nil, UpdateActivityLocalOutput{}, NewDatabaseError(err)
+		}
+		message = "Corrections removed; the activity has Strava's values again"
+	} else {
+		arg := db.UpsertActivityCorrectionParams{ID: input.ActivityID}
+		if name != "" {
+			arg.Name = sql.NullString{String: name, Valid: true}
+		}
+		if activityType != "" {
+			arg.Type = sql.NullString{String: activityType, Valid: true}
+		}
+		if input.DistanceKm > 0 {
+			arg.Distance = sql.NullFloat64{Float64: input.DistanceKm * 1000, Valid: true}
+		}
+		if err := queries.SaveActivityCorrection(ctx, arg); err != nil {
+			return nil, UpdateActivityLocalOutput{}, NewDatabaseError(err)
+		}
+		message = "Corrections saved locally and kept through later syncs; Strava is unchanged"
+	}
+
+	activity, err = overrideActivity(ctx, queries, input.ActivityID)
+	if err != nil {
+		return nil, UpdateActivityLocalOutput{}, err
+	}
+	output := UpdateActivityLocalOutput{
+		Activity:         convertActivity(activity),
+		Corrections:      make([]Correction, 0),
+		Message:          message,
+		SuggestedActions: SuggestNextActions("overrides"),
+	}
+	override, err := queries.GetActivityOverride(ctx, input.ActivityID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, UpdateActivityLocalOutput{}, NewDatabaseError(err)
+	}
+	if err == nil {
+		output.Corrections = corrections(override)
+	}
+
+	logging.Info("MCP tool completed", "tool", "update_activity_local", "activity_id", input.ActivityID, "corrections", len(output.Corrections))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "update_activity_local", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// excludeActivity leaves an activity out of stats, or counts it anyway
+func (s *Server) excludeActivity(ctx context.Context, req *mcp.CallToolRequest, input ExcludeActivityInput) (*mcp.CallToolResult, ExcludeActivityOutput, error) {
+	logging.Info("MCP tool call", "tool", "exclude_activity", "activity_id", input.ActivityID, "action", input.Action)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "exclude_activity", "input", logging.ToJSON(input))
+	}
+
+	action := input.Action
+	if action == "" {
+		action = exclusionExclude
+	}
+	arg := db.SetActivityCountedParams{ActivityID: input.ActivityID}
+	switch action {
+	case exclusionExclude:
+		arg.Counted = sql.NullInt64{Int64: 0, Valid: true}
+	case exclusionInclude:
+		arg.Counted = sql.NullInt64{Int64: 1, Valid: true}
+	case exclusionClear:
+	default:
+		return nil, ExcludeActivityOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("Invalid action %q", input.Action), "Valid values: exclude, include, clear")
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason != "" && action != exclusionClear {
+		arg.Reason = sql.NullString{String: reason, Valid: true}
+	}
+
+	queries := s.queries.(OverridesQuerier)
+	activity, err := overrideActivity(ctx, queries, input.ActivityID)
+	if err != nil {
+		return nil, ExcludeActivityOutput{}, err
+	}
+	if err := queries.SetActivityExclusion(ctx, arg); err != nil {
+		return nil, ExcludeActivityOutput{}, NewDatabaseError(err)
+	}
+	excluded, err := queries.IsActivityExcluded(ctx, input.ActivityID)
+	if err != nil {
+		return nil, ExcludeActivityOutput{}, NewDatabaseError(err)
+	}
+
+	output := ExcludeActivityOutput{
+		Activity:         convertActivity(activity),
+		Excluded:         excluded == 1,
+		Reason:           arg.Reason.String,
+		SuggestedActions: SuggestNextActions("overrides"),
+	}
+	switch {
+	case action == exclusionExclude:
+		output.Status = "excluded"
+		output.Message = "Left out of records, progress and totals; still on Strava and in search results"
+	case action == exclusionInclude:
+		output.Status = "included"
+		output.Message = "Counted in records, progress and totals even when flagged by the data quality checks"
+	case output.Excluded:
+		output.Status = "automatic"
+		output.Message = "Left to the data quality checks, which currently leave it out; see check_data_quality"
+	default:
+		output.Status = "automatic"
+		output.Message = "Left to the data quality checks, which currently count it"
+	}
+
+	logging.Info("MCP tool completed", "tool", "exclude_activity", "activity_id", input.ActivityID, "excluded", output.Excluded)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "exclude_activity", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// overrideActivity fetches the activity being corrected or excluded
+func overrideActivity(ctx context.Context, queries OverridesQuerier, id int64) (db.Activity, error) {
+	activity, err := queries.GetActivity(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Activity{}, NewNotFoundErrorWithID("activity", id)
+		}
+		return db.Activity{}, NewDatabaseError(err)
+	}
+	return activity, nil
+}
+
+// corrections lists the corrected fields of an override with Strava's values
+func corrections(o db.ActivityOverride) []Correction {
+	out := make([]Correction, 0, 3)
+	if o.Name.Valid {
+		out = append(out, Correction{Field: "name", Value: o.Name.String, Original: o.OriginalName.String})
+	}
+	if o.Type.Valid {
+		out = append(out, Correction{Field: "type", Value: o.Type.String, Original: o.OriginalType.String})
+	}
+	if o.Distance.Valid {
+		c := Correction{Field: "distance", Value: formatDistance(o.Distance.Float64)}
+		if o.OriginalDistance.Valid {
+			c.Original = formatDistance(o.OriginalDistance.Float64)
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func overridesTestQuerier() *MockQuerier {
+	return &MockQuerier{
+		activities: []db.Activity{{
+			ID:           1,
+			Name:         "Morning Run",
+			Type:         sql.NullString{String: "Run", Valid: true},
+			SportType:    sql.NullString{String: "Run", Valid: true},
+			Distance:     sql.NullFloat64{Float64: 30000, Valid: true},
+			MovingTime:   sql.NullInt64{Int64: 3000, Valid: true},
+			AverageSpeed: sql.NullFloat64{Float64: 10, Valid: true},
+		}},
+		activityFlags: []db.ListActivityFlagsRow{{ActivityID: 1, Code: "implausible_speed", Severity: "error"}},
+	}
+}
+
+func TestUpdateActivityLocal(t *testing.T) {
+	t.Parallel()
+
+	m := overridesTestQuerier()
+	srv := New(m)
+
+	_, output, err := srv.updateActivityLocal(context.Background(), nil, UpdateActivityLocalInput{ActivityID: 1, Type: "Ride"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, output, err = srv.updateActivityLocal(context.Background(), nil, UpdateActivityLocalInput{ActivityID: 1, DistanceKm: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Activity.Type != "Ride" || m.activities[0].AverageSpeed.Float64 != 10000.0/3000 {
+		t.Errorf("expected a 10 km ride, got %+v", output.Activity)
+	}
+	if len(output.Corrections) != 2 || output.Corrections[0].Original != "Run" || output.Corrections[1].Original != formatDistance(30000) {
+		t.Errorf("expected type and distance corrections with Strava's values, got %+v", output.Corrections)
+	}
+
+	_, output, err = srv.updateActivityLocal(context.Background(), nil, UpdateActivityLocalInput{ActivityID: 1, Reset: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Confirmation == nil || len(output.Confirmation.Changes) != 2 || output.Activity.Type != "Ride" {
+		t.Fatalf("expected a confirmation before dropping the corrections, got %+v", output)
+	}
+	_, output, err = srv.updateActivityLocal(context.Background(), nil, UpdateActivityLocalInput{ActivityID: 1, Reset: true, ConfirmToken: output.Confirmation.Token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Activity.Type != "Run" || m.activities[0].Distance.Float64 != 30000 || len(output.Corrections) != 0 || len(m.overrides) != 0 {
+		t.Errorf("expected Strava's values back, got %+v", output)
+	}
+
+	for _, input := range []UpdateActivityLocalInput{{ActivityID: 1}, {ActivityID: 1, DistanceKm: -5}} {
+		if _, _, err := srv.updateActivityLocal(context.Background(), nil, input); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+	if _, _, err := srv.updateActivityLocal(context.Background(), nil, UpdateActivityLocalInput{ActivityID: 2, Name: "x"}); err == nil {
+		t.Error("expected an error for a missing activity")
+	}
+}
+
+func TestExcludeActivity(t *testing.T) {
+	t.Parallel()
+
+	m := overridesTestQuerier()
+	srv := New(m)
+
+	// Counted despite the data quality error
+	_, output, err := srv.excludeActivity(context.Background(), nil, ExcludeActivityInput{ActivityID: 1, Action: "include"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != "included" || output.Excluded {
+		t.Errorf("expected the activity counted, got %+v", output)
+	}
+
+	_, output, err = srv.excludeActivity(context.Background(), nil, ExcludeActivityInput{ActivityID: 1, Reason: "treadmill"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != "excluded" || !output.Excluded || output.Reason != "treadmill" {
+		t.Errorf("expected the activity excluded, got %+v", output)
+	}
+
+	// Back to the flags, which leave it out
+	_, output, err = srv.excludeActivity(context.Background(), nil, ExcludeActivityInput{ActivityID: 1, Action: "clear"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != "automatic" || !output.Excluded || !strings.Contains(output.Message, "check_data_quality") || len(m.overrides) != 0 {
+		t.Errorf("expected the flags to decide, got %+v", output)
+	}
+
+	if _, _, err := srv.excludeActivity(context.Background(), nil, ExcludeActivityInput{ActivityID: 1, Action: "hide"}); err == nil {
+		t.Error("expected an error for an invalid action")
+	}
+}
+
+func TestCountActivitiesSkipsExcluded(t *testing.T) {
+	t.Parallel()
+
+	// Three runs and a ride, one run flagged with a data quality error
+	queries := newSQLiteQueries(t)
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	createSQLiteActivity(t, queries, 1, "Run", start, 10000)
+	createSQLiteActivity(t, queries, 2, "Run", start.AddDate(0, 0, 2), 10000)
+	createSQLiteActivity(t, queries, 3, "Run", start.AddDate(0, 0, 9), 10000)
+	createSQLiteActivity(t, queries, 4, "Ride", start.AddDate(0, 0, 3), 40000)
+	err := queries.CreateActivityFlag(context.Background(), db.CreateActivityFlagParams{
+		ActivityID: 3, Code: "implausible_speed", Severity: "error", Detail: "too fast",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := New(queries)
+	tests := []struct {
+		input CountActivitiesConsolidatedInput
+		want  int64
+	}{
+		{CountActivitiesConsolidatedInput{}, 3},
+		{CountActivitiesConsolidatedInput{GroupBy: "type"}, 3},
+		{CountActivitiesConsolidatedInput{StartDate: "2026-03-01", EndDate: "2026-03-31"}, 3},
+		{CountActivitiesConsolidatedInput{StartDate: "2026-03-01", EndDate: "2026-03-31", GroupBy: "type"}, 3},
+		{CountActivitiesConsolidatedInput{StartDate: "2026-03-01", EndDate: "2026-03-31", GroupBy: "month"}, 3},
+		{CountActivitiesConsolidatedInput{StartDate: "2026-03-01", EndDate: "2026-03-31", GroupBy: "week"}, 3},
+		{CountActivitiesConsolidatedInput{Type: "Run"}, 2},
+		{CountActivitiesConsolidatedInput{Type: "Run", StartDate: "2026-03-01", EndDate: "2026-03-31"}, 2},
+	}
+	for _, tt := range tests {
+		_, output, err := srv.countActivitiesConsolidated(context.Background(), nil, tt.input)
+		if err != nil {
+			t.Fatalf("%+v: unexpected error: %v", tt.input, err)
+		}
+		if output.Count != tt.want {
+			t.Errorf("%+v: expected %d, got %d", tt.input, tt.want, output.Count)
+		}
+	}
+}
+
+func TestResetActivityLocalRollsBack(t *testing.T) {
+	t.Parallel()
+
+	sqlDB := newSQLiteDB(t)
+	queries := db.New(sqlDB)
+	createSQLiteActivity(t, queries, 1, "Run", time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC), 10000)
+	srv := New(queries)
+	if _, _, err := srv.updateActivityLocal(context.Background(), nil, UpdateActivityLocalInput{ActivityID: 1, Name: "Track Session"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Clearing the corrections fails after Strava's values were put back
+	_, err := sqlDB.Exec(`CREATE TRIGGER fail_clear BEFORE UPDATE ON activity_overrides
+		BEGIN SELECT RAISE(ABORT, 'disk I/O error'); END`)
+	if err != nil {
+		t.Fatalf("failed to create trigger: %v", err)
+	}
+	if err := queries.ResetActivityOverride(context.Background(), 1); err == nil {
+		t.Fatal("expected the reset to fail")
+	}
+
+	activity, err := queries.GetActivity(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to get activity: %v", err)
+	}
+	override, err := queries.GetActivityOverride(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to get override: %v", err)
+	}
+	if activity.Name != "Track Session" || override.Name.String != "Track Session" {
+		t.Errorf("expected the correction kept whole, got activity %q with override %+v", activity.Name, override.Name)
+	}
+}
@@ -28,12 +28,18 @@ type AnalyzeProgressInput struct {
 	Metric    string `json:"metric,omitempty" jsonschema:"Which performance metric to analyze for progress. Valid values: 'pace' (speed improvement), 'distance' (volume increase), 'duration' (time increase), 'elevation' (climbing increase). Default: pace."`
 	Type      string `json:"type,omitempty" jsonschema:"Filter analysis to a specific activity type. Common values: Run, Ride, Swim. Leave empty to analyze across all activity types."`
 	Timeframe string `json:"timeframe,omitempty" jsonschema:"Time period to analyze. Valid values: 'last_30_days', 'last_90_days', 'last_6_months', 'last_year'. Compares this period to the equivalent previous period. Default: last_90_days."`
+
+	IncludeChart bool   `json:"include_chart,omitempty" jsonschema:"When true, also return a line chart of the metric week by week across both periods as image content."`
+	ChartFormat  string `json:"chart_format,omitempty" jsonschema:"Chart image format when include_chart is set. Valid values: 'png', 'svg'. Default: png."`
 }
 
 // CheckTrainingLoadInput - input for analyzing training load and volume
 type CheckTrainingLoadInput struct {
 	Weeks int    `json:"weeks,omitempty" jsonschema:"Number of recent weeks to analyze for training load. Range: 1-12. Default: 4 weeks."`
 	Type  string `json:"type,omitempty" jsonschema:"Filter analysis to a specific activity type. Common values: Run, Ride, Swim. Leave empty to analyze total training load across all activities."`
+
+	IncludeChart bool   `json:"include_chart,omitempty" jsonschema:"When true, also return a bar chart of weekly distance as image content."`
+	ChartFormat  string `json:"chart_format,omitempty" jsonschema:"Chart image format when include_chart is set. Valid values: 'png', 'svg'. Default: png."`
 }
 
 // Output types
@@ -51,28 +57,28 @@ type AnalyzeProgressOutput struct {
 }
 
 type PeriodMetrics struct {
-	DateRange     string `json:"date_range"`
-	ActivityCount int64  `json:"activity_count"`
-	Value         string `json:"value"` // Formatted metric value
+	DateRange     string  `json:"date_range"`
+	ActivityCount int64   `json:"activity_count"`
+	Value         string  `json:"value"` // Formatted metric value
 	RawValue      float64 `json:"raw_value,omitempty"`
 }
 
 type CheckTrainingLoadOutput struct {
-	CurrentWeek      WeeklyLoadSummary   `json:"current_week"`
-	RecentWeeks      []WeeklyLoadSummary `json:"recent_weeks"`
-	AverageWeekly    WeeklyLoadSummary   `json:"average_weekly"`
-	LoadStatus       string              `json:"load_status"` // "overreaching", "optimal", "maintaining", "undertraining"
-	LoadChangePercent float64            `json:"load_change_percent"`
-	Insights         []Insight           `json:"insights"`
-	SuggestedActions []SuggestedAction   `json:"suggested_actions"`
+	CurrentWeek       WeeklyLoadSummary   `json:"current_week"`
+	RecentWeeks       []WeeklyLoadSummary `json:"recent_weeks"`
+	AverageWeekly     WeeklyLoadSummary   `json:"average_weekly"`
+	LoadStatus        string              `json:"load_status"` // "overreaching", "optimal", "maintaining", "undertraining"
+	LoadChangePercent float64             `json:"load_change_percent"`
+	Insights          []Insight           `json:"insights"`
+	SuggestedActions  []SuggestedAction   `json:"suggested_actions"`
 }
 
 type WeeklyLoadSummary struct {
-	Week          string `json:"week,omitempty"`
-	ActivityCount int64  `json:"activity_count"`
-	TotalDistance string `json:"total_distance"`
-	TotalDuration string `json:"total_duration"`
-	TotalCalories int    `json:"total_calories,omitempty"`
+	Week           string `json:"week,omitempty"`
+	ActivityCount  int64  `json:"activity_count"`
+	TotalDistance  string `json:"total_distance"`
+	TotalDuration  string `json:"total_duration"`
+	TotalCalories  int    `json:"total_calories,omitempty"`
 	TotalElevation string `json:"total_elevation,omitempty"`
 }
 
@@ -92,10 +98,12 @@ Parameters:
 - metric (string): Which metric to analyze: "pace", "distance", "duration", "elevation". Default: "pace".
 - type (string): Filter to a specific activity type (Run, Ride, Swim, etc.). Leave empty for all types.
 - timeframe (string): Analysis period: "last_30_days", "last_90_days", "last_6_months", "last_year". Default: "last_90_days".
+- include_chart (boolean): Also return a weekly trend line chart as an image. Default: false.
+- chart_format (string): "png" or "svg". Default: "png".
 
-Returns: Current period metrics vs previous period metrics, trend direction (improving/stable/declining), percentage change, and progress insights.
+Returns: Current period metrics vs previous period metrics, trend direction (improving/stable/declining), percentage change, and progress insights. With include_chart, an image of the weekly trend is attached.
 
-Example: {"metric": "pace", "type": "Run", "timeframe": "last_90_days"}`,
+Example: {"metric": "pace", "type": "Run", "timeframe": "last_90_days"} or {"metric": "pace", "include_chart": true}`,
 		Annotations: &mcp.ToolAnnotations{
 			Title:           "Analyze Progress",
 			ReadOnlyHint:    true,
@@ -118,10 +126,12 @@ Use when:
 Parameters:
 - weeks (integer): Number of weeks to analyze. Range: 1-12. Default: 4.
 - type (string): Filter to a specific activity type (Run, Ride, Swim, etc.). Leave empty for total load.
+- include_chart (boolean): Also return a weekly distance bar chart as an image. Default: false.
+- chart_format (string): "png" or "svg". Default: "png".
 
-Returns: Current week summary, recent weeks breakdown, average weekly metrics, load status (overreaching/optimal/maintaining/undertraining), percentage change from average, and training load insights.
+Returns: Current week summary, recent weeks breakdown, average weekly metrics, load status (overreaching/optimal/maintaining/undertraining), percentage change from average, and training load insights. With include_chart, an image of weekly volume is attached.
 
-Example: {"weeks": 4, "type": "Run"} or {"weeks": 8}`,
+Example: {"weeks": 4, "type": "Run"} or {"weeks": 8, "include_chart": true}`,
 		Annotations: &mcp.ToolAnnotations{
 			Title:           "Check Training Load",
 			ReadOnlyHint:    true,
@@ -148,6 +158,11 @@ func (s *Server) analyzeProgress(ctx context.Context, req *mcp.CallToolRequest,
 		logging.Debug("MCP request params", "tool", "analyze_progress", "input", logging.ToJSON(input))
 	}
 
+	chartFormat, err := parseChartFormat(input.IncludeChart, input.ChartFormat)
+	if err != nil {
+		return nil, AnalyzeProgressOutput{}, err
+	}
+
 	// Calculate date ranges based on timeframe
 	now := time.Now()
 	var periodDays int
@@ -319,11 +334,20 @@ func (s *Server) analyzeProgress(ctx context.Context, req *mcp.CallToolRequest,
 		SuggestedActions: SuggestNextActions("progress"),
 	}
 
+	var result *mcp.CallToolResult
+	if input.IncludeChart {
+		weeks, err := fetchWeeklyVolume(ctx, queries, input.Type, previousStart, currentEnd)
+		if err != nil {
+			return nil, AnalyzeProgressOutput{}, err
+		}
+		result = chartResult("analyze_progress", output, progressTrendChart(metric, input.Type, weeks, currentStart), chartFormat)
+	}
+
 	logging.Info("MCP tool completed", "tool", "analyze_progress", "trend", trend, "change_percent", changePercent)
 	if logging.IsVerbose() {
 		logging.Debug("MCP response", "tool", "analyze_progress", "output", logging.ToJSON(output))
 	}
-	return nil, output, nil
+	return result, output, nil
 }
 
 // checkTrainingLoad analyzes weekly training volume for overtraining detection
@@ -345,56 +369,16 @@ func (s *Server) checkTrainingLoad(ctx context.Context, req *mcp.CallToolRequest
 	endDate := now
 	startDate := now.AddDate(0, 0, -weeks*7)
 
-	queries := s.queries.(ProgressQuerier)
-	hasType := input.Type != ""
+	chartFormat, err := parseChartFormat(input.IncludeChart, input.ChartFormat)
+	if err != nil {
+		return nil, CheckTrainingLoadOutput{}, err
+	}
 
-	var weeklyData []weeklyVolumeData
+	queries := s.queries.(ProgressQuerier)
 
-	if hasType {
-		rows, err := queries.GetWeeklyVolumeByType(ctx, db.GetWeeklyVolumeByTypeParams{
-			Type:        sql.NullString{String: input.Type, Valid: true},
-			StartDate:   sql.NullTime{Time: startDate, Valid: true},
-			StartDate_2: sql.NullTime{Time: endDate, Valid: true},
-		})
-		if err != nil {
-			return nil, CheckTrainingLoadOutput{}, fmt.Errorf("fetching weekly volume: %w", err)
-		}
-		for _, r := range rows {
-			week := ""
-			if r.Week != nil {
-				week = fmt.Sprintf("%v", r.Week)
-			}
-			weeklyData = append(weeklyData, weeklyVolumeData{
-				Week:          week,
-				ActivityCount: r.ActivityCount,
-				TotalDistance: toFloat64(r.TotalDistance),
-				TotalDuration: toInt64(r.TotalDuration),
-				TotalCalories: toFloat64(r.TotalCalories),
-				TotalElevation: toFloat64(r.TotalElevation),
-			})
-		}
-	} else {
-		rows, err := queries.GetWeeklyVolume(ctx, db.GetWeeklyVolumeParams{
-			StartDate:   sql.NullTime{Time: startDate, Valid: true},
-			StartDate_2: sql.NullTime{Time: endDate, Valid: true},
-		})
-		if err != nil {
-			return nil, CheckTrainingLoadOutput{}, fmt.Errorf("fetching weekly volume: %w", err)
-		}
-		for _, r := range rows {
-			week := ""
-			if r.Week != nil {
-				week = fmt.Sprintf("%v", r.Week)
-			}
-			weeklyData = append(weeklyData, weeklyVolumeData{
-				Week:          week,
-				ActivityCount: r.ActivityCount,
-				TotalDistance: toFloat64(r.TotalDistance),
-				TotalDuration: toInt64(r.TotalDuration),
-				TotalCalories: toFloat64(r.TotalCalories),
-				TotalElevation: toFloat64(r.TotalElevation),
-			})
-		}
+	weeklyData, err := fetchWeeklyVolume(ctx, queries, input.Type, startDate, endDate)
+	if err != nil {
+		return nil, CheckTrainingLoadOutput{}, err
 	}
 
 	// Calculate averages and current week
@@ -414,11 +398,11 @@ func (s *Server) checkTrainingLoad(ctx context.Context, req *mcp.CallToolRequest
 		totalActivities += w.ActivityCount
 
 		recentWeeks = append(recentWeeks, WeeklyLoadSummary{
-			Week:          w.Week,
-			ActivityCount: w.ActivityCount,
-			TotalDistance: formatDistance(w.TotalDistance),
-			TotalDuration: formatDuration(w.TotalDuration),
-			TotalCalories: int(w.TotalCalories),
+			Week:           w.Week,
+			ActivityCount:  w.ActivityCount,
+			TotalDistance:  formatDistance(w.TotalDistance),
+			TotalDuration:  formatDuration(w.TotalDuration),
+			TotalCalories:  int(w.TotalCalories),
 			TotalElevation: fmt.Sprintf("%.0fm", w.TotalElevation),
 		})
 	}
@@ -462,19 +446,19 @@ func (s *Server) checkTrainingLoad(ctx context.Context, req *mcp.CallToolRequest
 
 	output := CheckTrainingLoadOutput{
 		CurrentWeek: WeeklyLoadSummary{
-			Week:          currentWeek.Week,
-			ActivityCount: currentWeek.ActivityCount,
-			TotalDistance: formatDistance(currentWeek.TotalDistance),
-			TotalDuration: formatDuration(currentWeek.TotalDuration),
-			TotalCalories: int(currentWeek.TotalCalories),
+			Week:           currentWeek.Week,
+			ActivityCount:  currentWeek.ActivityCount,
+			TotalDistance:  formatDistance(currentWeek.TotalDistance),
+			TotalDuration:  formatDuration(currentWeek.TotalDuration),
+			TotalCalories:  int(currentWeek.TotalCalories),
 			TotalElevation: fmt.Sprintf("%.0fm", currentWeek.TotalElevation),
 		},
 		RecentWeeks: recentWeeks,
 		AverageWeekly: WeeklyLoadSummary{
-			ActivityCount: avgActivities,
-			TotalDistance: formatDistance(avgDistance),
-			TotalDuration: formatDuration(int64(avgDuration)),
-			TotalCalories: int(avgCalories),
+			ActivityCount:  avgActivities,
+			TotalDistance:  formatDistance(avgDistance),
+			TotalDuration:  formatDuration(int64(avgDuration)),
+			TotalCalories:  int(avgCalories),
 			TotalElevation: fmt.Sprintf("%.0fm", avgElevation),
 		},
 		LoadStatus:        loadStatus,
@@ -483,11 +467,76 @@ func (s *Server) checkTrainingLoad(ctx context.Context, req *mcp.CallToolRequest
 		SuggestedActions:  SuggestNextActions("week_summary"),
 	}
 
+	var result *mcp.CallToolResult
+	if input.IncludeChart {
+		result = chartResult("check_training_load", output, weeklyVolumeChart(weeklyData, input.Type), chartFormat)
+	}
+
 	logging.Info("MCP tool completed", "tool", "check_training_load", "load_status", loadStatus, "change_percent", loadChangePercent)
 	if logging.IsVerbose() {
 		logging.Debug("MCP response", "tool", "check_training_load", "output", logging.ToJSON(output))
 	}
-	return nil, output, nil
+	return result, output, nil
+}
+
+// weeklyVolumeQuerier is the part of ProgressQuerier that fetchWeeklyVolume needs
+type weeklyVolumeQuerier interface {
+	GetWeeklyVolume(ctx context.Context, arg db.GetWeeklyVolumeParams) ([]db.GetWeeklyVolumeRow, error)
+	GetWeeklyVolumeByType(ctx context.Context, arg db.GetWeeklyVolumeByTypeParams) ([]db.GetWeeklyVolumeByTypeRow, error)
+}
+
+// fetchWeeklyVolume returns per-week totals between start and end, newest week first
+func fetchWeeklyVolume(ctx context.Context, queries weeklyVolumeQuerier, activityType string, start, end time.Time) ([]weeklyVolumeData, error) {
+	var weeklyData []weeklyVolumeData
+
+	if activityType != "" {
+		rows, err := queries.GetWeeklyVolumeByType(ctx, db.GetWeeklyVolumeByTypeParams{
+			Type:        sql.NullString{String: activityType, Valid: true},
+			StartDate:   sql.NullTime{Time: start, Valid: true},
+			StartDate_2: sql.NullTime{Time: end, Valid: true},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetching weekly volume: %w", err)
+		}
+		for _, r := range rows {
+			week := ""
+			if r.Week != nil {
+				week = fmt.Sprintf("%v", r.Week)
+			}
+			weeklyData = append(weeklyData, weeklyVolumeData{
+				Week:           week,
+				ActivityCount:  r.ActivityCount,
+				TotalDistance:  toFloat64(r.TotalDistance),
+				TotalDuration:  toInt64(r.TotalDuration),
+				TotalCalories:  toFloat64(r.TotalCalories),
+				TotalElevation: toFloat64(r.TotalElevation),
+			})
+		}
+		return weeklyData, nil
+	}
+
+	rows, err := queries.GetWeeklyVolume(ctx, db.GetWeeklyVolumeParams{
+		StartDate:   sql.NullTime{Time: start, Valid: true},
+		StartDate_2: sql.NullTime{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching weekly volume: %w", err)
+	}
+	for _, r := range rows {
+		week := ""
+		if r.Week != nil {
+			week = fmt.Sprintf("%v", r.Week)
+		}
+		weeklyData = append(weeklyData, weeklyVolumeData{
+			Week:           week,
+			ActivityCount:  r.ActivityCount,
+			TotalDistance:  toFloat64(r.TotalDistance),
+			TotalDuration:  toInt64(r.TotalDuration),
+			TotalCalories:  toFloat64(r.TotalCalories),
+			TotalElevation: toFloat64(r.TotalElevation),
+		})
+	}
+	return weeklyData, nil
 }
 
 // Helper types
@@ -499,10 +548,10 @@ type periodStatsData struct {
 }
 
 type weeklyVolumeData struct {
-	Week          string
-	ActivityCount int64
-	TotalDistance float64
-	TotalDuration int64
-	TotalCalories float64
+	Week           string
+	ActivityCount  int64
+	TotalDistance  float64
+	TotalDuration  int64
+	TotalCalories  float64
 	TotalElevation float64
 }
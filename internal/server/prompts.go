@@ -178,15 +178,18 @@ Use the following tools to gather data:
 1. **analyze_zones** with zone_type="heartrate"%s for the zone distribution
 2. **get_training_summary**%s for overall volume context
 3. **check_training_load** to understand recent training patterns
+4. **get_threshold_history** to check whether zone boundaries still match current thresholds
 
 Then provide:
 - **Zone Distribution**: Percentage of time in each zone (Z1-Z5)
 - **80/20 Rule Check**: Am I following the recommended ~80%% easy / ~20%% hard distribution?
+- **Intensity Model**: Is my distribution polarized, pyramidal or threshold-heavy (see intensity_distribution and its polarization index)?
 - **Aerobic Base**: Is there sufficient Zone 2 training for aerobic development?
 - **High Intensity**: Is there adequate Zone 4-5 work for fitness gains?
 - **Recommendations**: How should I adjust my training intensity?
+- **Zone Accuracy**: If thresholds have risen or are stale, say so and how it skews the distribution
 
-Note: Zone data requires a Strava Summit subscription. If zone data is unavailable, explain this to the user.`, typeDescription, typeParam, typeParam)
+Note: Zones come from Strava (Summit) or are computed locally from heart rate streams using the athlete's zone boundaries (from their Strava profile or --zones-file). If zone data is unavailable, explain that zone boundaries need to be configured.`, typeDescription, typeParam, typeParam)
 
 	return &mcp.GetPromptResult{
 		Description: "Heart rate zone analysis prompt",
@@ -0,0 +1,725 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/taper"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RacesQuerier defines the interface for race calendar queries
+type RacesQuerier interface {
+	CreateRace(ctx context.Context, arg db.CreateRaceParams) (db.Race, error)
+	GetRace(ctx context.Context, id int64) (db.Race, error)
+	ListRaces(ctx context.Context) ([]db.Race, error)
+	ListUpcomingRaces(ctx context.Context, raceDate time.Time) ([]db.Race, error)
+	DeleteRace(ctx context.Context, id int64) (int64, error)
+	SearchActivities(ctx context.Context, arg db.SearchActivitiesParams) ([]db.Activity, error)
+}
+
+// Race priorities
+const (
+	priorityA = "A"
+	priorityB = "B"
+	priorityC = "C"
+)
+
+// readinessHistoryDays is how much training before the taper, or before
+// today, is read for a readiness check: the four-week baseline plus the three
+// weeks compared with it
+const readinessHistoryDays = 49
+
+// Input types
+
+// AddRaceInput - input for adding a race to the calendar
+type AddRaceInput struct {
+	Name       string  `json:"name" jsonschema:"Race name, e.g. 'Berlin Marathon'."`
+	Date       string  `json:"date" jsonschema:"Race date in YYYY-MM-DD format."`
+	DistanceKm float64 `json:"distance_km" jsonschema:"Race distance in kilometers, e.g. 21.1 for a half marathon."`
+	Type       string  `json:"type,omitempty" jsonschema:"Activity type of the race. Common values: Run, Ride, Swim. Default: Run."`
+	Priority   string  `json:"priority,omitempty" jsonschema:"Race priority. Valid values: 'A' (goal race), 'B', 'C' (tune-up). Default: A."`
+	GoalTime   string  `json:"goal_time,omitempty" jsonschema:"Optional goal finish time as h:mm:ss or mm:ss, e.g. '1:45:00'."`
+	Notes      string  `json:"notes,omitempty" jsonschema:"Optional notes about the race."`
+}
+
+// ListRacesInput - input for listing the race calendar
+type ListRacesInput struct {
+	IncludePast bool `json:"include_past,omitempty" jsonschema:"When true, also list races that have already happened. Default: upcoming races only."`
+}
+
+// RemoveRaceInput - input for removing a race from the calendar
+type RemoveRaceInput struct {
+	RaceID       int64  `json:"race_id" jsonschema:"ID of the race to remove, from list_races."`
+	ConfirmToken string `json:"confirm_token,omitempty" jsonschema:"Token from an earlier call's confirmation, once the user has agreed to remove the race."`
+}
+
+// GetRaceReadinessInput - input for assessing readiness for a race
+type GetRaceReadinessInput struct {
+	RaceID int64 `json:"race_id,omitempty" jsonschema:"ID of the race to assess, from list_races. Default: the next A race, or the next race if there is no A race."`
+}
+
+// Output types
+
+// RaceEntry is a race on the calendar
+type RaceEntry struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Date       string `json:"date"`
+	DaysToRace int    `json:"days_to_race"`
+	Distance   string `json:"distance"`
+	Type       string `json:"type"`
+	Priority   string `json:"priority"`
+	GoalTime   string `json:"goal_time,omitempty"`
+	GoalPace   string `json:"goal_pace,omitempty"` // runs only
+	Notes      string `json:"notes,omitempty"`
+}
+
+type AddRaceOutput struct {
+	Race RaceEntry `json:"race"`
+	// TaperWindow is the recommended taper length, e.g. "10-14 days"
+	TaperWindow      string            `json:"taper_window"`
+	TaperStart       string            `json:"taper_start"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+}
+
+type ListRacesOutput struct {
+	Races            []RaceEntry       `json:"races"`
+	NextRace         *RaceEntry        `json:"next_race,omitempty"`
+	Insights         []Insight         `json:"insights"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+}
+
+type RemoveRaceOutput struct {
+	// Removed is the race removed, or to be removed while Confirmation is set
+	Removed RaceEntry `json:"removed"`
+	// Confirmation is set instead when the user still needs to approve the removal
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+type GetRaceReadinessOutput struct {
+	Race RaceEntry `json:"race"`
+	// Phase is building, taper, race_week or passed
+	Phase string `json:"phase"`
+	// Status is ready, on_track, adjust, early or passed
+	Status      string `json:"status"`
+	TaperWindow string `json:"taper_window"`
+	TaperStart  string `json:"taper_start"`
+	// UsualWeek is the average week over the four weeks before the taper
+	UsualWeek *ReadinessWeek `json:"usual_week,omitempty"`
+	// LastWeeks are the last three 7-day spans, oldest first
+	LastWeeks []ReadinessWeek `json:"last_weeks,omitempty"`
+	// TargetWeekly is the weekly volume to aim for from today to the race
+	TargetWeekly     string            `json:"target_weekly,omitempty"`
+	Checks           []ReadinessCheck  `json:"checks,omitempty"`
+	Insights         []Insight         `json:"insights"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+}
+
+// ReadinessWeek is a week of training before a race
+type ReadinessWeek struct {
+	Dates        string  `json:"dates,omitempty"`
+	Duration     string  `json:"duration"`
+	Distance     string  `json:"distance"`
+	Sessions     float64 `json:"sessions"`
+	HardSessions float64 `json:"hard_sessions"`
+}
+
+// ReadinessCheck is one piece of taper guidance and how training measures up
+type ReadinessCheck struct {
+	Name    string `json:"name"`   // volume_reduction, intensity, frequency, progression or load_ramp
+	Status  string `json:"status"` // good, watch or adjust
+	Value   string `json:"value"`
+	Target  string `json:"target"`
+	Message string `json:"message"`
+}
+
+// registerRaceTools registers the race calendar and readiness tools
+func (s *Server) registerRaceTools() {
+	logging.Debug("Registering tool", "name", "add_race")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "add_race",
+		Description: `Add a race to the local race calendar.
+
+Use when:
+- User says "I'm running the Berlin Marathon on September 27th"
+- User wants race readiness or taper guidance for an upcoming race
+- User sets a goal race or tune-up race
+
+Parameters:
+- name (string, required): Race name
+- date (string, required): Race date in YYYY-MM-DD format
+- distance_km (number, required): Race distance in kilometers
+- type (string): Activity type (Run, Ride, Swim, etc.). Default: Run.
+- priority (string): "A" (goal race), "B" or "C" (tune-up). Default: "A".
+- goal_time (string): Optional goal time as h:mm:ss or mm:ss
+- notes (string): Optional notes
+
+Returns: The race as stored with its ID, and the recommended taper window and start date for its distance.
+
+Example: {"name": "Valencia Half", "date": "2026-10-25", "distance_km": 21.1, "goal_time": "1:29:59"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Add Race",
+			ReadOnlyHint:    false,
+			IdempotentHint:  false,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.addRace)
+
+	logging.Debug("Registering tool", "name", "list_races")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "list_races",
+		Description: `List races on the local race calendar.
+
+Use when:
+- User asks "What races do I have coming up?"
+- User needs a race ID for get_race_readiness or remove_race
+
+Parameters:
+- include_past (boolean): Also list races that have already happened. Default: false.
+
+Returns: Races in date order with days to go, distance, priority, goal time and goal pace, plus the next race.
+
+Example: {} or {"include_past": true}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "List Races",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.listRaces)
+
+	logging.Debug("Registering tool", "name", "remove_race")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "remove_race",
+		Description: `Remove a race from the local race calendar.
+
+Use when:
+- User is no longer doing a race or entered it wrongly
+
+Parameters:
+- race_id (int, required): ID of the race, from list_races
+- confirm_token (string): Token from an earlier confirmation, once the user agreed
+
+Returns: The race that was removed. The user is asked to confirm first; clients that cannot ask get a confirmation with a token to call again with.
+
+Example: {"race_id": 3}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Remove Race",
+			ReadOnlyHint:    false,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(true),
+		},
+	}, s.removeRace)
+
+	logging.Debug("Registering tool", "name", "get_race_readiness")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "get_race_readiness",
+		Description: `Assess readiness for a race from the last three weeks of training against established taper guidance.
+
+Use when:
+- User asks "Am I ready for my race?" or "Is my taper on track?"
+- User asks how much to cut back before a race
+- User wants to know when to start tapering
+
+Parameters:
+- race_id (int): ID of the race, from list_races. Default: the next A race, or the next race.
+
+Returns: Days to the race, the recommended taper window (7-10 days up to 10 km, 10-14 to a half marathon, 14-21 beyond; 50 and 120 km for rides), the phase (building, taper, race_week, passed) and status (ready, on_track, adjust, early, passed). Then the usual week before the taper, the last three weeks, a target weekly volume, and checks: volume cut (aim for about 25% at the start of the taper rising to 50% by race day), intensity (keep hard sessions), frequency (keep at least 80% of sessions) and progression (each taper week lighter than the last), or the load ramp before the taper starts. Checks that need attention come with suggested adjustments.
+
+Example: {} or {"race_id": 3}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Get Race Readiness",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.getRaceReadiness)
+}
+
+// addRace stores a race on the calendar
+func (s *Server) addRace(ctx context.Context, req *mcp.CallToolRequest, input AddRaceInput) (*mcp.CallToolResult, AddRaceOutput, error) {
+	logging.Info("MCP tool call", "tool", "add_race", "name", input.Name, "date", input.Date)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "add_race", "input", logging.ToJSON(input))
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, AddRaceOutput{}, NewInvalidInputError("name is required")
+	}
+	date, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return nil, AddRaceOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("Invalid date %q", input.Date), "Use YYYY-MM-DD, e.g. 2026-10-25")
+	}
+	if input.DistanceKm <= 0 {
+		return nil, AddRaceOutput{}, NewInvalidInputErrorWithDetails("distance_km must be positive", "e.g. 42.195 for a marathon")
+	}
+	activityType := input.Type
+	if activityType == "" {
+		activityType = "Run"
+	}
+	priority := strings.ToUpper(input.Priority)
+	switch priority {
+	case "":
+		priority = priorityA
+	case priorityA, priorityB, priorityC:
+	default:
+		return nil, AddRaceOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("Invalid priority %q", input.Priority), "Valid priorities: A, B, C")
+	}
+	var goal sql.NullInt64
+	if input.GoalTime != "" {
+		seconds, err := parseGoalTime(input.GoalTime)
+		if err != nil {
+			return nil, AddRaceOutput{}, NewInvalidInputErrorWithDetails(
+				fmt.Sprintf("Invalid goal_time %q", input.GoalTime), "Use h:mm:ss or mm:ss, e.g. 1:45:00")
+		}
+		goal = sql.NullInt64{Int64: seconds, Valid: true}
+	}
+	var notes sql.NullString
+	if input.Notes != "" {
+		notes = sql.NullString{String: input.Notes, Valid: true}
+	}
+
+	queries := s.queries.(RacesQuerier)
+	race, err := queries.CreateRace(ctx, db.CreateRaceParams{
+		Name:         name,
+		RaceDate:     date,
+		Distance:     input.DistanceKm * 1000,
+		ActivityType: activityType,
+		Priority:     priority,
+		GoalTime:     goal,
+		Notes:        notes,
+	})
+	if err != nil {
+		return nil, AddRaceOutput{}, NewDatabaseError(err)
+	}
+
+	r := taperRace(race)
+	minDays, maxDays := taper.Window(r)
+	output := AddRaceOutput{
+		Race:             raceEntry(race, time.Now()),
+		TaperWindow:      fmt.Sprintf("%d-%d days", minDays, maxDays),
+		TaperStart:       race.RaceDate.AddDate(0, 0, -(minDays+maxDays)/2).Format("2006-01-02"),
+		SuggestedActions: SuggestNextActions("races"),
+	}
+
+	logging.Info("MCP tool completed", "tool", "add_race", "race_id", race.ID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "add_race", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// listRaces lists the race calendar
+func (s *Server) listRaces(ctx context.Context, req *mcp.CallToolRequest, input ListRacesInput) (*mcp.CallToolResult, ListRacesOutput, error) {
+	logging.Info("MCP tool call", "tool", "list_races", "include_past", input.IncludePast)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "list_races", "input", logging.ToJSON(input))
+	}
+
+	queries := s.queries.(RacesQuerier)
+	now := time.Now()
+	today := calendarDay(now)
+
+	var races []db.Race
+	var err error
+	if input.IncludePast {
+		races, err = queries.ListRaces(ctx)
+	} else {
+		races, err = queries.ListUpcomingRaces(ctx, today)
+	}
+	if err != nil {
+		return nil, ListRacesOutput{}, NewDatabaseError(err)
+	}
+
+	output := ListRacesOutput{
+		Races:    make([]RaceEntry, 0, len(races)),
+		Insights: make([]Insight, 0),
+	}
+	for _, r := range races {
+		entry := raceEntry(r, now)
+		output.Races = append(output.Races, entry)
+		if output.NextRace == nil && !r.RaceDate.Before(today) {
+			output.NextRace = &entry
+		}
+	}
+
+	if next := output.NextRace; next != nil {
+		output.Insights = append(output.Insights, Insight{
+			Type:    "trend",
+			Message: fmt.Sprintf("Next race: %s (%s, %s race) in %d days", next.Name, next.Distance, next.Priority, next.DaysToRace),
+		})
+		output.SuggestedActions = SuggestNextActions("races")
+	} else {
+		output.Insights = append(output.Insights, Insight{
+			Type:    "suggestion",
+			Message: "No upcoming races. Add one with add_race to get taper guidance.",
+		})
+		output.SuggestedActions = make([]SuggestedAction, 0)
+	}
+
+	logging.Info("MCP tool completed", "tool", "list_races", "races", len(output.Races))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "list_races", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// removeRace deletes a race from the calendar
+func (s *Server) removeRace(ctx context.Context, req *mcp.CallToolRequest, input RemoveRaceInput) (*mcp.CallToolResult, RemoveRaceOutput, error) {
+	logging.Info("MCP tool call", "tool", "remove_race", "race_id", input.RaceID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "remove_race", "input", logging.ToJSON(input))
+	}
+
+	queries := s.queries.(RacesQuerier)
+	race, err := queries.GetRace(ctx, input.RaceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, RemoveRaceOutput{}, NewNotFoundErrorWithID("race", input.RaceID)
+		}
+		return nil, RemoveRaceOutput{}, NewDatabaseError(err)
+	}
+	entry := raceEntry(race, time.Now())
+	changes := []Change{{Field: "name", From: entry.Name}, {Field: "date", From: entry.Date}}
+	if entry.Distance != "" {
+		changes = append(changes, Change{Field: "distance", From: entry.Distance})
+	}
+	action := fmt.Sprintf("Remove %s on %s from the race calendar", entry.Name, entry.Date)
+	confirmation, err := s.confirm(ctx, req, "remove_race", action, changes, input, input.ConfirmToken)
+	if err != nil {
+		return nil, RemoveRaceOutput{}, err
+	}
+	if confirmation != nil {
+		logging.Info("MCP tool completed", "tool", "remove_race", "race_id", input.RaceID, "status", "awaiting_confirmation")
+		return nil, RemoveRaceOutput{Removed: entry, Confirmation: confirmation}, nil
+	}
+
+	if _, err := queries.DeleteRace(ctx, input.RaceID); err != nil {
+		return nil, RemoveRaceOutput{}, NewDatabaseError(err)
+	}
+
+	output := RemoveRaceOutput{Removed: entry}
+
+	logging.Info("MCP tool completed", "tool", "remove_race", "race_id", input.RaceID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "remove_race", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// getRaceReadiness assesses the taper for a race
+func (s *Server) getRaceReadiness(ctx context.Context, req *mcp.CallToolRequest, input GetRaceReadinessInput) (*mcp.CallToolResult, GetRaceReadinessOutput, error) {
+	logging.Info("MCP tool call", "tool", "get_race_readiness", "race_id", input.RaceID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "get_race_readiness", "input", logging.ToJSON(input))
+	}
+
+	queries := s.queries.(RacesQuerier)
+	now := time.Now()
+
+	race, err := readinessRace(ctx, queries, input.RaceID, calendarDay(now))
+	if err != nil {
+		return nil, GetRaceReadinessOutput{}, err
+	}
+
+	r := taperRace(race)
+	_, maxDays := taper.Window(r)
+	from := r.Date.AddDate(0, 0, -maxDays)
+	if today := calendarDay(now); today.Before(from) {
+		from = today
+	}
+	start := sql.NullTime{Time: from.AddDate(0, 0, -readinessHistoryDays), Valid: true}
+	activityType := sql.NullString{String: race.ActivityType, Valid: true}
+	activities, err := queries.SearchActivities(ctx, db.SearchActivitiesParams{
+		Column1:   activityType,
+		Type:      activityType,
+		Column3:   start,
+		StartDate: start,
+		Limit:     maxBlockActivities,
+	})
+	if err != nil {
+		return nil, GetRaceReadinessOutput{}, NewDatabaseError(err)
+	}
+	sessions := make([]blocks.Session, 0, len(activities))
+	for _, a := range activities {
+		if a.StartDate.Valid {
+			sessions = append(sessions, blocks.Session{
+				ID:       a.ID,
+				Start:    a.StartDate.Time,
+				Seconds:  float64(a.MovingTime.Int64),
+				Meters:   a.Distance.Float64,
+				Category: a.WorkoutCategory.String,
+			})
+		}
+	}
+
+	a := taper.Assess(r, sessions, now)
+	output := readinessOutput(raceEntry(race, now), a)
+
+	logging.Info("MCP tool completed", "tool", "get_race_readiness", "race_id", race.ID, "phase", a.Phase, "status", a.Status)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "get_race_readiness", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// readinessRace returns the race with the given ID, or the next A race (the
+// next race if there's no A race) when id is zero
+func readinessRace(ctx context.Context, queries RacesQuerier, id int64, today time.Time) (db.Race, error) {
+	if id != 0 {
+		race, err := queries.GetRace(ctx, id)
+		if err == sql.ErrNoRows {
+			return db.Race{}, NewNotFoundErrorWithID("race", id)
+		}
+		if err != nil {
+			return db.Race{}, NewDatabaseError(err)
+		}
+		return race, nil
+	}
+
+	upcoming, err := queries.ListUpcomingRaces(ctx, today)
+	if err != nil {
+		return db.Race{}, NewDatabaseError(err)
+	}
+	if len(upcoming) == 0 {
+		return db.Race{}, NewInvalidInputErrorWithDetails("No upcoming races", "Add one with add_race, or pass race_id for a past race")
+	}
+	for _, r := range upcoming {
+		if r.Priority == priorityA {
+			return r, nil
+		}
+	}
+	return upcoming[0], nil
+}
+
+// readinessOutput turns an assessment into the tool's output
+func readinessOutput(race RaceEntry, a taper.Assessment) GetRaceReadinessOutput {
+	output := GetRaceReadinessOutput{
+		Race:        race,
+		Phase:       a.Phase,
+		Status:      a.Status,
+		TaperWindow: fmt.Sprintf("%d-%d days", a.MinTaper, a.MaxTaper),
+		TaperStart:  a.TaperStart.Format("2006-01-02"),
+		Insights:    make([]Insight, 0),
+	}
+	if a.Phase == taper.PhasePassed {
+		output.Insights = append(output.Insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("%s was %d days ago. Add your next race with add_race.", race.Name, -a.DaysToRace),
+		})
+		output.SuggestedActions = SuggestNextActions("races")
+		return output
+	}
+
+	usual := readinessWeek(a.Baseline, false)
+	output.UsualWeek = &usual
+	for _, w := range a.Weeks {
+		output.LastWeeks = append(output.LastWeeks, readinessWeek(w, true))
+	}
+	output.TargetWeekly = formatDuration(int64(a.TargetSeconds))
+
+	var adjustments []SuggestedAction
+	for _, c := range a.Checks {
+		check, action := readinessCheck(c, a)
+		output.Checks = append(output.Checks, check)
+		if action != nil {
+			adjustments = append(adjustments, *action)
+		}
+		if c.Status == taper.Adjust {
+			output.Insights = append(output.Insights, Insight{Type: "warning", Message: check.Message})
+		}
+	}
+
+	var phase string
+	switch a.Phase {
+	case taper.PhaseBuilding:
+		phase = fmt.Sprintf("the taper starts %s, %d days before the race", output.TaperStart, (a.MinTaper+a.MaxTaper)/2)
+	case taper.PhaseTaper:
+		phase = fmt.Sprintf("you're in the %s taper", output.TaperWindow)
+	case taper.PhaseRaceWeek:
+		phase = "it's race week"
+	}
+	output.Insights = append([]Insight{{
+		Type:    "trend",
+		Message: fmt.Sprintf("%d days to %s (%s); %s", a.DaysToRace, race.Name, race.Distance, phase),
+	}}, output.Insights...)
+	if a.Status == taper.StatusReady {
+		c, _ := a.Check(taper.CheckVolume)
+		output.Insights = append(output.Insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("Taper on track: volume down %.0f%% with intensity and frequency kept", c.Value*100),
+		})
+	}
+
+	if a.Phase == taper.PhaseBuilding && len(adjustments) == 0 {
+		adjustments = append(adjustments, SuggestedAction{
+			Tool:        "get_race_readiness",
+			Description: fmt.Sprintf("Check again once the taper starts on %s", output.TaperStart),
+			Priority:    "low",
+		})
+	}
+	output.SuggestedActions = append(adjustments, SuggestNextActions("race_readiness")...)
+	return output
+}
+
+// readinessCheck describes a taper check, with an adjustment when it needs one
+func readinessCheck(c taper.Check, a taper.Assessment) (ReadinessCheck, *SuggestedAction) {
+	out := ReadinessCheck{Name: c.Name, Status: c.Status}
+	priority := "medium"
+	if c.Status == taper.Adjust {
+		priority = "high"
+	}
+	var action *SuggestedAction
+	adjust := func(tool, description string) {
+		if c.Status != taper.Good {
+			action = &SuggestedAction{Tool: tool, Description: description, Priority: priority}
+		}
+	}
+	usual := formatDuration(int64(a.Baseline.Seconds))
+	target := formatDuration(int64(a.TargetSeconds))
+
+	switch c.Name {
+	case taper.CheckVolume:
+		out.Value = fmt.Sprintf("%.0f%% less volume than usual", c.Value*100)
+		out.Target = fmt.Sprintf("%.0f-%.0f%% less", c.Low*100, c.High*100)
+		switch {
+		case c.Value < c.Low:
+			out.Message = fmt.Sprintf("Volume is only down %.0f%% on your usual %s a week; aim for %.0f-%.0f%% by now", c.Value*100, usual, c.Low*100, c.High*100)
+			adjust("check_training_load", fmt.Sprintf("Cut to about %s a week until the race (usually %s) by shortening sessions rather than dropping them", target, usual))
+		case c.Value > c.High:
+			out.Message = fmt.Sprintf("Volume is down %.0f%%, more than the %.0f-%.0f%% needed; too much rest can leave you flat", c.Value*100, c.Low*100, c.High*100)
+			adjust("check_training_load", fmt.Sprintf("Build back to about %s a week with short easy sessions", target))
+		default:
+			out.Message = fmt.Sprintf("Volume is down %.0f%%, right for this point in the taper", c.Value*100)
+		}
+	case taper.CheckIntensity:
+		last := a.Weeks[len(a.Weeks)-1]
+		out.Value = fmt.Sprintf("%.0f hard sessions in the last 7 days vs %.1f usual", last.HardSessions, a.Baseline.HardSessions)
+		out.Target = "at least half your usual hard sessions"
+		if c.Status == taper.Good {
+			out.Message = "Intensity is being kept"
+		} else {
+			out.Message = "Hard sessions have dropped off; a taper cuts volume, not intensity"
+			adjust("find_activities", "Keep one short session at race pace each week, about a third of a usual workout; find a recent one with workout_category \"intervals\"")
+		}
+	case taper.CheckFrequency:
+		out.Value = fmt.Sprintf("%.1f sessions a week vs %.1f usual (%.0f%%)", a.Recent.Sessions, a.Baseline.Sessions, c.Value*100)
+		out.Target = fmt.Sprintf("at least %.0f%% of usual", c.Low*100)
+		if c.Status == taper.Good {
+			out.Message = "Training frequency is being kept"
+		} else {
+			out.Message = "Fewer sessions than usual; frequency keeps the feel for race pace"
+			adjust("check_training_load", fmt.Sprintf("Keep about %.0f sessions a week and make each one shorter", math.Ceil(a.Baseline.Sessions*c.Low)))
+		}
+	case taper.CheckProgression:
+		out.Value = fmt.Sprintf("biggest week-on-week rise %.0f%%", c.Value*100)
+		out.Target = "each taper week lighter than the last"
+		if c.Status == taper.Good {
+			out.Message = "Volume is coming down week by week"
+		} else {
+			out.Message = fmt.Sprintf("Volume rose %.0f%% on the week before during the taper", c.Value*100)
+			adjust("check_training_load", fmt.Sprintf("Keep the rest of the taper under %s a week and lighter than last week", target))
+		}
+	case taper.CheckRamp:
+		out.Value = fmt.Sprintf("last 7 days at %.0f%% of your usual %s a week", c.Value*100, usual)
+		out.Target = fmt.Sprintf("up to %.0f%%", c.High*100)
+		if c.Status == taper.Good {
+			out.Message = "Load is building steadily"
+		} else {
+			out.Message = "Load jumped well above usual; a sharp ramp this close to the taper raises injury risk"
+			adjust("analyze_training_blocks", fmt.Sprintf("Hold around %s a week until the taper on %s rather than building further", usual, a.TaperStart.Format("2006-01-02")))
+		}
+	}
+	return out, action
+}
+
+// readinessWeek formats a load
+func readinessWeek(l taper.Load, dated bool) ReadinessWeek {
+	w := ReadinessWeek{
+		Duration:     formatDuration(int64(l.Seconds)),
+		Distance:     formatDistance(l.Meters),
+		Sessions:     math.Round(l.Sessions*10) / 10,
+		HardSessions: math.Round(l.HardSessions*10) / 10,
+	}
+	if dated {
+		w.Dates = fmt.Sprintf("%s to %s", l.Start.Format("2006-01-02"), l.Start.AddDate(0, 0, l.Days-1).Format("2006-01-02"))
+	}
+	return w
+}
+
+// raceEntry formats a race as of now
+func raceEntry(r db.Race, now time.Time) RaceEntry {
+	entry := RaceEntry{
+		ID:         r.ID,
+		Name:       r.Name,
+		Date:       r.RaceDate.Format("2006-01-02"),
+		DaysToRace: int(math.Round(calendarDay(r.RaceDate).Sub(calendarDay(now)).Hours() / 24)),
+		Distance:   formatDistance(r.Distance),
+		Type:       r.ActivityType,
+		Priority:   r.Priority,
+		Notes:      r.Notes.String,
+	}
+	if r.GoalTime.Valid {
+		entry.GoalTime = formatGoalTime(r.GoalTime.Int64)
+		if thresholds.Sport(r.ActivityType) == thresholds.SportRun && r.GoalTime.Int64 > 0 {
+			entry.GoalPace = formatPace(r.Distance / float64(r.GoalTime.Int64))
+		}
+	}
+	return entry
+}
+
+// taperRace is the taper package's view of a race
+func taperRace(r db.Race) taper.Race {
+	return taper.Race{Date: r.RaceDate, Distance: r.Distance, Type: r.ActivityType}
+}
+
+// calendarDay is midnight UTC on t's date, matching how race dates are stored
+func calendarDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// parseGoalTime parses h:mm:ss or mm:ss into seconds
+func parseGoalTime(s string) (int64, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("expected h:mm:ss or mm:ss")
+	}
+	var total int64
+	for i, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, fmt.Errorf("invalid time component %q", p)
+		}
+		total = total*60 + n
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("goal time must be positive")
+	}
+	return total, nil
+}
+
+// formatGoalTime formats seconds as h:mm:ss, or mm:ss under an hour
+func formatGoalTime(seconds int64) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/taper"
+)
+
+func TestAddRace(t *testing.T) {
+	t.Parallel()
+
+	m := &MockQuerier{}
+	srv := New(m)
+
+	_, output, err := srv.addRace(context.Background(), nil, AddRaceInput{
+		Name:       "City Half",
+		Date:       "2030-04-07",
+		DistanceKm: 21.1,
+		Priority:   "b",
+		GoalTime:   "1:45:30",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.races) != 1 || m.races[0].Distance != 21100 || m.races[0].ActivityType != "Run" || m.races[0].GoalTime.Int64 != 6330 {
+		t.Fatalf("unexpected stored race: %+v", m.races)
+	}
+	if output.Race.Priority != "B" || output.Race.GoalTime != "1:45:30" || output.Race.GoalPace == "" {
+		t.Errorf("unexpected race: %+v", output.Race)
+	}
+	if output.TaperWindow != "10-14 days" || output.TaperStart != "2030-03-26" {
+		t.Errorf("expected a 12 day taper from 2030-03-26, got %s from %s", output.TaperWindow, output.TaperStart)
+	}
+
+	invalid := []AddRaceInput{
+		{Date: "2030-04-07", DistanceKm: 10},
+		{Name: "Race", Date: "April 7th", DistanceKm: 10},
+		{Name: "Race", Date: "2030-04-07"},
+		{Name: "Race", Date: "2030-04-07", DistanceKm: 10, Priority: "D"},
+		{Name: "Race", Date: "2030-04-07", DistanceKm: 10, GoalTime: "45:75"},
+	}
+	for _, in := range invalid {
+		if _, _, err := srv.addRace(context.Background(), nil, in); err == nil {
+			t.Errorf("expected an error for %+v", in)
+		}
+	}
+}
+
+func TestParseGoalTime(t *testing.T) {
+	tests := map[string]int64{
+		"1:45:00": 6300,
+		"45:30":   2730,
+		"3:05:09": 11109,
+	}
+	for in, want := range tests {
+		if got, err := parseGoalTime(in); err != nil || got != want {
+			t.Errorf("parseGoalTime(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "90", "1:2:3:4", "0:00", "1:60:00", "a:00"} {
+		if _, err := parseGoalTime(in); err == nil {
+			t.Errorf("expected an error for %q", in)
+		}
+	}
+	if got := formatGoalTime(6330); got != "1:45:30" {
+		t.Errorf("expected 1:45:30, got %s", got)
+	}
+}
+
+func TestListAndRemoveRaces(t *testing.T) {
+	t.Parallel()
+
+	today := calendarDay(time.Now())
+	m := &MockQuerier{races: []db.Race{
+		{ID: 1, Name: "Last Spring", RaceDate: today.AddDate(0, -6, 0), Distance: 10000, ActivityType: "Run", Priority: "A"},
+		{ID: 2, Name: "Tune-up 10k", RaceDate: today.AddDate(0, 0, 20), Distance: 10000, ActivityType: "Run", Priority: "C"},
+		{ID: 3, Name: "Marathon", RaceDate: today.AddDate(0, 0, 60), Distance: 42195, ActivityType: "Run", Priority: "A"},
+	}}
+	srv := New(m)
+
+	_, output, err := srv.listRaces(context.Background(), nil, ListRacesInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Races) != 2 || output.NextRace == nil || output.NextRace.ID != 2 || output.NextRace.DaysToRace != 20 {
+		t.Errorf("expected two upcoming races with the 10k next, got %+v", output)
+	}
+
+	_, output, err = srv.listRaces(context.Background(), nil, ListRacesInput{IncludePast: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Races) != 3 || output.NextRace.ID != 2 {
+		t.Errorf("expected all three races with the 10k next, got %+v", output)
+	}
+
+	// Nothing is removed until confirmed
+	_, removed, err := srv.removeRace(context.Background(), nil, RemoveRaceInput{RaceID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed.Confirmation == nil || len(m.races) != 3 {
+		t.Fatalf("expected a confirmation first, got %+v", removed)
+	}
+	_, removed, err = srv.removeRace(context.Background(), nil, RemoveRaceInput{RaceID: 2, ConfirmToken: removed.Confirmation.Token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed.Removed.Name != "Tune-up 10k" || removed.Confirmation != nil || len(m.races) != 2 {
+		t.Errorf("expected the 10k removed, got %+v", removed)
+	}
+	if _, _, err := srv.removeRace(context.Background(), nil, RemoveRaceInput{RaceID: 2}); err == nil {
+		t.Error("expected an error removing a missing race")
+	}
+}
+
+// raceTestQuerier has ten weeks of running towards a half marathon four days
+// away: six runs and eight hours a week, two of them intervals, kept up into
+// race week without a taper. It also has a 5k ride as a B race before it.
+func raceTestQuerier(now time.Time) *MockQuerier {
+	today := calendarDay(now)
+	raceDay := today.AddDate(0, 0, 4)
+
+	m := &MockQuerier{races: []db.Race{
+		{ID: 1, Name: "Crit", RaceDate: today.AddDate(0, 0, 2), Distance: 5000, ActivityType: "Ride", Priority: "B"},
+		{ID: 2, Name: "Autumn Half", RaceDate: raceDay, Distance: 21097, ActivityType: "Run", Priority: "A", GoalTime: sql.NullInt64{Int64: 5400, Valid: true}},
+	}}
+	id := int64(100)
+	for d := raceDay.AddDate(0, 0, -70); !d.After(today); d = d.AddDate(0, 0, 1) {
+		daysOut := int(raceDay.Sub(d).Hours() / 24)
+		if daysOut%7 == 0 {
+			continue
+		}
+		category := "easy"
+		if daysOut%7 == 2 || daysOut%7 == 5 {
+			category = "intervals"
+		}
+		a := createTestActivity(id, "Run", "Run", d.Add(7*time.Hour))
+		a.MovingTime = sql.NullInt64{Int64: 8 * 3600 / 6, Valid: true}
+		a.Distance = sql.NullFloat64{Float64: 8 * 3600 / 6 * 3.2, Valid: true}
+		a.WorkoutCategory = sql.NullString{String: category, Valid: true}
+		m.activities = append(m.activities, a)
+		id++
+	}
+	return m
+}
+
+func TestGetRaceReadiness(t *testing.T) {
+	t.Parallel()
+
+	srv := New(raceTestQuerier(time.Now()))
+
+	// The A race is picked over the B race before it
+	_, output, err := srv.getRaceReadiness(context.Background(), nil, GetRaceReadinessInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Race.ID != 2 || output.Race.DaysToRace != 4 || output.Race.GoalPace == "" {
+		t.Fatalf("expected the half marathon 4 days out, got %+v", output.Race)
+	}
+	if output.Phase != taper.PhaseRaceWeek || output.Status != taper.StatusAdjust || output.TaperWindow != "10-14 days" {
+		t.Errorf("expected an untapered race week, got %s, %s, %s", output.Phase, output.Status, output.TaperWindow)
+	}
+	if output.UsualWeek == nil || output.UsualWeek.Duration != "8h 0m" || output.UsualWeek.Sessions != 6 || len(output.LastWeeks) != 3 {
+		t.Errorf("unexpected weeks: %+v %+v", output.UsualWeek, output.LastWeeks)
+	}
+
+	var volume *ReadinessCheck
+	for i, c := range output.Checks {
+		if c.Name == taper.CheckVolume {
+			volume = &output.Checks[i]
+		}
+	}
+	if volume == nil || volume.Status != taper.Adjust || !strings.Contains(volume.Value, "0% less") {
+		t.Fatalf("expected no cut in volume to need adjusting, got %+v", output.Checks)
+	}
+
+	first := output.SuggestedActions[0]
+	if first.Tool != "check_training_load" || first.Priority != "high" || !strings.Contains(first.Description, "8h 0m") {
+		t.Errorf("expected an adjustment to cut volume first, got %+v", output.SuggestedActions)
+	}
+	var warned bool
+	for _, i := range output.Insights {
+		if i.Type == "warning" && strings.Contains(i.Message, "Volume is only down") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("expected a volume warning, got %+v", output.Insights)
+	}
+
+	// The ride is assessed on its own, with no rides to go on
+	_, output, err = srv.getRaceReadiness(context.Background(), nil, GetRaceReadinessInput{RaceID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Race.Type != "Ride" || output.UsualWeek.Sessions != 0 {
+		t.Errorf("expected the ride with no training, got %+v", output)
+	}
+
+	if _, _, err := srv.getRaceReadiness(context.Background(), nil, GetRaceReadinessInput{RaceID: 99}); err == nil {
+		t.Error("expected an error for a missing race")
+	}
+	if _, _, err := New(&MockQuerier{}).getRaceReadiness(context.Background(), nil, GetRaceReadinessInput{}); err == nil {
+		t.Error("expected an error with no races")
+	}
+}
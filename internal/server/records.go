@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strconv"
 
 	"github.com/joshdurbin/strava-mcp/internal/db"
@@ -22,6 +23,7 @@ type RecordsQuerier interface {
 	GetHighestElevationActivityByType(ctx context.Context, activityType sql.NullString) (db.Activity, error)
 	GetMostCaloriesActivity(ctx context.Context) (db.Activity, error)
 	GetMostCaloriesActivityByType(ctx context.Context, activityType sql.NullString) (db.Activity, error)
+	CountExcludedActivities(ctx context.Context) (int64, error)
 }
 
 // Input types
@@ -64,7 +66,7 @@ Parameters:
 - type (string): Filter to a specific activity type (Run, Ride, Swim, etc.). Leave empty for overall bests.
 - categories (array): Which record categories to include: "fastest", "longest_distance", "longest_duration", "highest_elevation", "most_calories". Omit for all categories.
 
-Returns: List of personal records, each with category name, the activity details (id, name, date, etc.), the record value, and the metric type. Includes achievement insights.
+Returns: List of personal records, each with category name, the activity details (id, name, date, etc.), the record value, and the metric type. Includes achievement insights. Activities flagged with data errors (see check_data_quality) are left out.
 
 Example: {"type": "Run"} or {"categories": ["fastest", "longest_distance"]}`,
 		Annotations: &mcp.ToolAnnotations{
@@ -223,6 +225,12 @@ func (s *Server) getPersonalRecords(ctx context.Context, req *mcp.CallToolReques
 			Message: generateRecordsInsight(records, input.Type),
 		})
 	}
+	if excluded, err := queries.CountExcludedActivities(ctx); err == nil && excluded > 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: fmt.Sprintf("%d activities with suspicious data are left out of records; see check_data_quality", excluded),
+		})
+	}
 
 	output := GetPersonalRecordsOutput{
 		Type:             input.Type,
@@ -0,0 +1,371 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RouteEffortsQuerier defines the interface for detected route queries
+type RouteEffortsQuerier interface {
+	GetRoutesDetected(ctx context.Context, id int64) (db.RoutesDetected, error)
+	GetRouteEffort(ctx context.Context, activityID int64) (db.RouteEffort, error)
+	GetRouteEffortActivities(ctx context.Context, routeID int64) ([]db.Activity, error)
+	ListRecurringRoutes(ctx context.Context, arg db.ListRecurringRoutesParams) ([]db.ListRecurringRoutesRow, error)
+}
+
+// minTrendEfforts is the number of efforts needed before a trend is reported
+const minTrendEfforts = 3
+
+// Input types
+
+// CompareRouteEffortsInput - input for comparing efforts on a recurring route
+type CompareRouteEffortsInput struct {
+	RouteID    int64  `json:"route_id,omitempty" jsonschema:"ID of a detected route. Leave empty (with no activity_id) to list recurring routes."`
+	ActivityID int64  `json:"activity_id,omitempty" jsonschema:"Compare efforts on the route this activity followed."`
+	Type       string `json:"type,omitempty" jsonschema:"When listing routes, filter by activity type. Common values: Run, Ride, Walk, Hike."`
+	Limit      int    `json:"limit,omitempty" jsonschema:"When listing routes, maximum number to return. Default: 20, Maximum: 100."`
+}
+
+// Output types
+
+type CompareRouteEffortsOutput struct {
+	Routes           []RecurringRoute    `json:"routes,omitempty"`
+	Route            *DetectedRoute      `json:"route,omitempty"`
+	Efforts          []RouteEffortDetail `json:"efforts,omitempty"`
+	Summary          *RouteEffortSummary `json:"summary,omitempty"`
+	Insights         []Insight           `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction   `json:"suggested_actions,omitempty"`
+}
+
+// RecurringRoute is a detected route that has been done more than once
+type RecurringRoute struct {
+	RouteID  int64  `json:"route_id"`
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Distance string `json:"distance"`
+	Efforts  int64  `json:"efforts"`
+}
+
+// DetectedRoute describes the route being compared
+type DetectedRoute struct {
+	RouteID                  int64  `json:"route_id"`
+	Name                     string `json:"name"`
+	Type                     string `json:"type,omitempty"`
+	Distance                 string `json:"distance"`
+	RepresentativeActivityID int64  `json:"representative_activity_id"`
+}
+
+// RouteEffortDetail is one activity on the route
+type RouteEffortDetail struct {
+	ActivityID       int64   `json:"activity_id"`
+	Name             string  `json:"name"`
+	Date             string  `json:"date"`
+	MovingTime       int64   `json:"moving_time"`
+	Duration         string  `json:"duration"`
+	Pace             string  `json:"pace,omitempty"`
+	SpeedKmh         float64 `json:"speed_kmh,omitempty"`
+	AverageHeartrate float64 `json:"average_heartrate,omitempty"`
+	IsBest           bool    `json:"is_best,omitempty"`
+}
+
+// RouteEffortSummary compares the efforts on a route
+type RouteEffortSummary struct {
+	Efforts         int    `json:"efforts"`
+	BestActivityID  int64  `json:"best_activity_id"`
+	BestDuration    string `json:"best_duration"`
+	LatestDuration  string `json:"latest_duration"`
+	AverageDuration string `json:"average_duration"`
+	// Trend is "improving", "stable" or "declining", from a linear fit of
+	// moving time over date; empty with fewer than three efforts
+	Trend string `json:"trend,omitempty"`
+	// TrendPerMonth is the fitted change in moving time per 30 days in seconds;
+	// negative is faster
+	TrendPerMonth float64 `json:"trend_seconds_per_month,omitempty"`
+}
+
+// registerRouteEffortTools registers the recurring route comparison tool
+func (s *Server) registerRouteEffortTools() {
+	logging.Debug("Registering tool", "name", "compare_route_efforts")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "compare_route_efforts",
+		Description: `Compare every effort on a recurring route: the same loop or out-and-back done on different days.
+
+Routes are detected automatically by matching activity polylines (start and finish within about 250 m, similar length and shape). Route IDs are stable across syncs.
+
+Use when:
+- User asks "Am I faster on my usual Tuesday loop?" or "How does today's run compare to last time on this route?"
+- User wants to see their regular routes
+- User asks for progress on a specific course without segment data
+
+Parameters:
+- route_id (int): A detected route to compare
+- activity_id (int): Compare efforts on the route this activity followed
+- type (string): With neither ID, filter the route list by activity type
+- limit (int): With neither ID, maximum routes to list (default 20, max 100)
+
+Returns: With an ID, every effort on the route (date, moving time, pace, speed, average HR) with the best flagged, plus best, latest and average times and a trend from a linear fit over time. With neither ID, the recurring routes ordered by how often they were done.
+
+Example: {} to list routes, then {"route_id": 3} or {"activity_id": 12345678}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Compare Route Efforts",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.compareRouteEfforts)
+}
+
+// compareRouteEfforts lists recurring routes or compares efforts on one
+func (s *Server) compareRouteEfforts(ctx context.Context, req *mcp.CallToolRequest, input CompareRouteEffortsInput) (*mcp.CallToolResult, CompareRouteEffortsOutput, error) {
+	logging.Info("MCP tool call", "tool", "compare_route_efforts", "route_id", input.RouteID, "activity_id", input.ActivityID, "type", input.Type)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "compare_route_efforts", "input", logging.ToJSON(input))
+	}
+
+	queries := s.queries.(RouteEffortsQuerier)
+
+	routeID := input.RouteID
+	if routeID == 0 && input.ActivityID != 0 {
+		effort, err := queries.GetRouteEffort(ctx, input.ActivityID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, CompareRouteEffortsOutput{}, NewNotFoundErrorWithID("Route for activity", input.ActivityID)
+			}
+			return nil, CompareRouteEffortsOutput{}, NewDatabaseError(err)
+		}
+		routeID = effort.RouteID
+	}
+
+	var output CompareRouteEffortsOutput
+	var err error
+	if routeID == 0 {
+		output, err = listRecurringRoutes(ctx, queries, input)
+	} else {
+		output, err = routeEffortComparison(ctx, queries, routeID)
+	}
+	if err != nil {
+		return nil, CompareRouteEffortsOutput{}, err
+	}
+	output.SuggestedActions = SuggestNextActions("route_efforts")
+
+	logging.Info("MCP tool completed", "tool", "compare_route_efforts", "route_id", routeID, "routes", len(output.Routes), "efforts", len(output.Efforts))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "compare_route_efforts", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+func listRecurringRoutes(ctx context.Context, queries RouteEffortsQuerier, input CompareRouteEffortsInput) (CompareRouteEffortsOutput, error) {
+	hasType := input.Type != ""
+	rows, err := queries.ListRecurringRoutes(ctx, db.ListRecurringRoutesParams{
+		Column1:      sql.NullString{String: input.Type, Valid: hasType},
+		ActivityType: sql.NullString{String: input.Type, Valid: hasType},
+		Limit:        int64(applyLimit(input.Limit)),
+	})
+	if err != nil {
+		return CompareRouteEffortsOutput{}, NewDatabaseError(err)
+	}
+
+	output := CompareRouteEffortsOutput{Routes: make([]RecurringRoute, len(rows))}
+	for i, r := range rows {
+		output.Routes[i] = RecurringRoute{
+			RouteID:  r.ID,
+			Name:     r.Name,
+			Type:     r.ActivityType.String,
+			Distance: formatDistance(r.Distance),
+			Efforts:  r.EffortCount,
+		}
+	}
+
+	if len(rows) == 0 {
+		output.Insights = []Insight{{
+			Type:    "warning",
+			Message: "No recurring routes found yet. Routes appear once two activities with GPS follow the same course.",
+		}}
+	} else {
+		top := rows[0]
+		output.Insights = []Insight{{
+			Type:    "trend",
+			Message: fmt.Sprintf("Your most frequent route is %q (%s), done %d times.", top.Name, formatDistance(top.Distance), top.EffortCount),
+		}}
+	}
+	return output, nil
+}
+
+func routeEffortComparison(ctx context.Context, queries RouteEffortsQuerier, routeID int64) (CompareRouteEffortsOutput, error) {
+	route, err := queries.GetRoutesDetected(ctx, routeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return CompareRouteEffortsOutput{}, NewNotFoundErrorWithID("Route", routeID)
+		}
+		return CompareRouteEffortsOutput{}, NewDatabaseError(err)
+	}
+
+	activities, err := queries.GetRouteEffortActivities(ctx, routeID)
+	if err != nil {
+		return CompareRouteEffortsOutput{}, NewDatabaseError(err)
+	}
+
+	output := CompareRouteEffortsOutput{
+		Route: &DetectedRoute{
+			RouteID:                  route.ID,
+			Name:                     route.Name,
+			Type:                     route.ActivityType.String,
+			Distance:                 formatDistance(route.Distance),
+			RepresentativeActivityID: route.RepresentativeActivityID,
+		},
+		Efforts: make([]RouteEffortDetail, 0, len(activities)),
+	}
+
+	best := -1
+	for _, a := range activities {
+		e := RouteEffortDetail{
+			ActivityID:       a.ID,
+			Name:             a.Name,
+			MovingTime:       a.MovingTime.Int64,
+			Duration:         formatDuration(a.MovingTime.Int64),
+			Pace:             formatPace(a.AverageSpeed.Float64),
+			SpeedKmh:         math.Round(a.AverageSpeed.Float64*3.6*10) / 10,
+			AverageHeartrate: math.Round(a.AverageHeartrate.Float64),
+		}
+		if a.StartDate.Valid {
+			e.Date = a.StartDate.Time.Format(time.DateOnly)
+		}
+		output.Efforts = append(output.Efforts, e)
+		if e.MovingTime > 0 && (best < 0 || e.MovingTime < output.Efforts[best].MovingTime) {
+			best = len(output.Efforts) - 1
+		}
+	}
+
+	if best < 0 {
+		output.Insights = []Insight{{
+			Type:    "warning",
+			Message: "No timed efforts on this route.",
+		}}
+		return output, nil
+	}
+	output.Efforts[best].IsBest = true
+	output.Summary = summarizeRouteEfforts(activities, output.Efforts, best)
+	output.Insights = routeEffortInsights(output.Summary, output.Efforts, best)
+	return output, nil
+}
+
+// summarizeRouteEfforts computes best, latest and average times and fits a
+// line through moving time against date to find the trend
+func summarizeRouteEfforts(activities []db.Activity, efforts []RouteEffortDetail, best int) *RouteEffortSummary {
+	summary := &RouteEffortSummary{
+		Efforts:        len(efforts),
+		BestActivityID: efforts[best].ActivityID,
+		BestDuration:   efforts[best].Duration,
+		LatestDuration: efforts[len(efforts)-1].Duration,
+	}
+
+	var xs, ys []float64
+	var total float64
+	for i, a := range activities {
+		if efforts[i].MovingTime <= 0 || !a.StartDate.Valid {
+			continue
+		}
+		xs = append(xs, a.StartDate.Time.Sub(activities[0].StartDate.Time).Hours()/24)
+		ys = append(ys, float64(efforts[i].MovingTime))
+		total += float64(efforts[i].MovingTime)
+	}
+	if len(ys) == 0 {
+		return summary
+	}
+	average := total / float64(len(ys))
+	summary.AverageDuration = formatDuration(int64(math.Round(average)))
+
+	if len(ys) < minTrendEfforts {
+		return summary
+	}
+	slope, ok := linearSlope(xs, ys)
+	if !ok {
+		return summary
+	}
+	summary.TrendPerMonth = math.Round(slope * 30)
+	switch change := slope * 30 / average; {
+	case change < -0.01:
+		summary.Trend = "improving"
+	case change > 0.01:
+		summary.Trend = "declining"
+	default:
+		summary.Trend = "stable"
+	}
+	return summary
+}
+
+// linearSlope returns the least-squares slope of ys over xs
+func linearSlope(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var num, den float64
+	for i := range xs {
+		num += (xs[i] - meanX) * (ys[i] - meanY)
+		den += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if den == 0 {
+		return 0, false
+	}
+	return num / den, true
+}
+
+func routeEffortInsights(summary *RouteEffortSummary, efforts []RouteEffortDetail, best int) []Insight {
+	var insights []Insight
+
+	latest := len(efforts) - 1
+	if best == latest && len(efforts) > 1 {
+		insights = append(insights, Insight{
+			Type:    "achievement",
+			Message: fmt.Sprintf("Your latest effort (%s) is your fastest of %d on this route.", efforts[latest].Duration, len(efforts)),
+		})
+	} else if len(efforts) > 1 {
+		gap := efforts[latest].MovingTime - efforts[best].MovingTime
+		if gap > 0 {
+			insights = append(insights, Insight{
+				Type:    "trend",
+				Message: fmt.Sprintf("Your latest effort was %s slower than your best (%s on %s).", formatDuration(gap), efforts[best].Duration, efforts[best].Date),
+			})
+		}
+	}
+
+	switch summary.Trend {
+	case "improving":
+		insights = append(insights, Insight{
+			Type:    "trend",
+			Message: fmt.Sprintf("You're getting faster on this route, about %s quicker per month.", formatDuration(int64(-summary.TrendPerMonth))),
+		})
+	case "declining":
+		insights = append(insights, Insight{
+			Type:    "warning",
+			Message: fmt.Sprintf("Your times on this route are slipping, about %s slower per month.", formatDuration(int64(summary.TrendPerMonth))),
+		})
+	case "stable":
+		insights = append(insights, Insight{
+			Type:    "trend",
+			Message: "Your times on this route are steady.",
+		})
+	}
+
+	if len(efforts) < minTrendEfforts {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: "Repeat this route a few more times to see a trend.",
+		})
+	}
+	return insights
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+// routeEffortQuerier has a loop run four times, getting faster, and a ride
+// done once
+func routeEffortQuerier() *MockQuerier {
+	var activities []db.Activity
+	var efforts []db.RouteEffort
+	for i, moving := range []int64{1500, 1480, 1450, 1420} {
+		a := createTestActivity(int64(i+1), "Tuesday Loop", "Run", time.Date(2024, 3, 5+7*i, 7, 0, 0, 0, time.UTC))
+		a.MovingTime = sql.NullInt64{Int64: moving, Valid: true}
+		a.AverageSpeed = sql.NullFloat64{Float64: 5000 / float64(moving), Valid: true}
+		a.AverageHeartrate = sql.NullFloat64{Float64: 150, Valid: true}
+		activities = append(activities, a)
+		efforts = append(efforts, db.RouteEffort{ActivityID: a.ID, RouteID: 1})
+	}
+	ride := createTestActivity(5, "Hill Ride", "Ride", time.Date(2024, 3, 6, 7, 0, 0, 0, time.UTC))
+	activities = append(activities, ride)
+	efforts = append(efforts, db.RouteEffort{ActivityID: 5, RouteID: 2})
+
+	return &MockQuerier{
+		activities: activities,
+		detectedRoutes: []db.RoutesDetected{
+			{ID: 1, Name: "Tuesday Loop", ActivityType: sql.NullString{String: "Run", Valid: true}, RepresentativeActivityID: 1, Distance: 5000},
+			{ID: 2, Name: "Hill Ride", ActivityType: sql.NullString{String: "Ride", Valid: true}, RepresentativeActivityID: 5, Distance: 30000},
+		},
+		routeEfforts: efforts,
+	}
+}
+
+func TestCompareRouteEffortsListsRecurringRoutes(t *testing.T) {
+	t.Parallel()
+
+	srv := New(routeEffortQuerier())
+
+	_, output, err := srv.compareRouteEfforts(context.Background(), nil, CompareRouteEffortsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Routes) != 1 || output.Routes[0].RouteID != 1 || output.Routes[0].Efforts != 4 {
+		t.Fatalf("expected only the repeated loop, got %+v", output.Routes)
+	}
+	if output.Route != nil || len(output.Efforts) != 0 {
+		t.Error("expected no effort comparison when listing routes")
+	}
+}
+
+func TestCompareRouteEffortsByActivity(t *testing.T) {
+	t.Parallel()
+
+	srv := New(routeEffortQuerier())
+
+	_, output, err := srv.compareRouteEfforts(context.Background(), nil, CompareRouteEffortsInput{ActivityID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Route == nil || output.Route.RouteID != 1 {
+		t.Fatalf("expected route 1, got %+v", output.Route)
+	}
+	if len(output.Efforts) != 4 {
+		t.Fatalf("expected 4 efforts, got %d", len(output.Efforts))
+	}
+	if !output.Efforts[3].IsBest || output.Efforts[0].IsBest {
+		t.Errorf("expected the latest effort to be best, got %+v", output.Efforts)
+	}
+	if output.Efforts[0].Pace != "5:00/km" {
+		t.Errorf("expected 5:00/km pace, got %s", output.Efforts[0].Pace)
+	}
+
+	summary := output.Summary
+	if summary == nil || summary.BestActivityID != 4 || summary.Trend != "improving" || summary.TrendPerMonth >= 0 {
+		t.Fatalf("expected an improving trend, got %+v", summary)
+	}
+	if summary.AverageDuration != "24m 23s" {
+		t.Errorf("expected average 24m 23s, got %s", summary.AverageDuration)
+	}
+
+	hasAchievement := false
+	for _, insight := range output.Insights {
+		if insight.Type == "achievement" {
+			hasAchievement = true
+		}
+	}
+	if !hasAchievement {
+		t.Errorf("expected a latest-is-best achievement, got %+v", output.Insights)
+	}
+}
+
+func TestCompareRouteEffortsSingleEffort(t *testing.T) {
+	t.Parallel()
+
+	srv := New(routeEffortQuerier())
+
+	_, output, err := srv.compareRouteEfforts(context.Background(), nil, CompareRouteEffortsInput{RouteID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The ride has no moving time, so there is nothing to compare
+	if len(output.Efforts) != 1 || output.Summary != nil {
+		t.Errorf("expected one untimed effort and no summary, got %+v", output)
+	}
+}
+
+func TestCompareRouteEffortsNotFound(t *testing.T) {
+	t.Parallel()
+
+	srv := New(routeEffortQuerier())
+
+	if _, _, err := srv.compareRouteEfforts(context.Background(), nil, CompareRouteEffortsInput{RouteID: 99}); err == nil {
+		t.Error("expected an error for an unknown route")
+	}
+	if _, _, err := srv.compareRouteEfforts(context.Background(), nil, CompareRouteEffortsInput{ActivityID: 99}); err == nil {
+		t.Error("expected an error for an activity without a route")
+	}
+}
+
+func TestLinearSlope(t *testing.T) {
+	t.Parallel()
+
+	slope, ok := linearSlope([]float64{0, 1, 2, 3}, []float64{10, 8, 6, 4})
+	if !ok || slope != -2 {
+		t.Errorf("expected slope -2, got %v (%v)", slope, ok)
+	}
+	if _, ok := linearSlope([]float64{1, 1}, []float64{3, 4}); ok {
+		t.Error("expected no slope when x doesn't vary")
+	}
+}
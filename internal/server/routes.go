@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RoutesQuerier defines the interface for route geometry queries
+type RoutesQuerier interface {
+	GetActivity(ctx context.Context, id int64) (db.Activity, error)
+	SearchActivitiesWithRoute(ctx context.Context, arg db.SearchActivitiesWithRouteParams) ([]db.Activity, error)
+}
+
+// Input types
+
+// GetActivityRouteInput - input for exporting activity routes
+type GetActivityRouteInput struct {
+	ActivityID int64  `json:"activity_id,omitempty" jsonschema:"Export the route of a single activity by its Strava activity ID. When set, overrides the filter parameters."`
+	Type       string `json:"type,omitempty" jsonschema:"Filter by activity type. Common values: Run, Ride, Walk, Hike. Leave empty for all types."`
+	StartDate  string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD (e.g., 2024-01-15)."`
+	EndDate    string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD (e.g., 2024-12-31)."`
+	Limit      int    `json:"limit,omitempty" jsonschema:"Maximum number of routes to return. Default: 20, Maximum: 100."`
+	Format     string `json:"format,omitempty" jsonschema:"Output format. Valid values: 'geojson' (FeatureCollection of LineStrings), 'gpx' (GPX 1.1 document as a string). Default: geojson."`
+}
+
+// Output types
+
+type GetActivityRouteOutput struct {
+	Format           string                 `json:"format"`
+	RouteCount       int                    `json:"route_count"`
+	Filter           string                 `json:"filter,omitempty"`
+	GeoJSON          *geo.FeatureCollection `json:"geojson,omitempty"`
+	GPX              string                 `json:"gpx,omitempty"`
+	Insights         []Insight              `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction      `json:"suggested_actions,omitempty"`
+}
+
+// registerRouteTools registers the route export tool
+func (s *Server) registerRouteTools() {
+	logging.Debug("Registering tool", "name", "get_activity_route")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "get_activity_route",
+		Description: `Export the GPS route of one or more activities as GeoJSON or GPX, decoded from the Strava summary polyline.
+
+Use when:
+- User asks "Where did I run this morning?" or "Show me the route of my last ride"
+- User wants to map, visualize, or export their routes
+- User asks for a GPX file of an activity
+
+Parameters:
+- activity_id (int): Export a single activity's route. Overrides filters.
+- type (string): Filter by activity type (Run, Ride, etc.)
+- start_date, end_date (string): Date range filter, YYYY-MM-DD format
+- limit (int): Maximum routes to return (default 20, max 100)
+- format (string): "geojson" (default) or "gpx"
+
+Returns: A GeoJSON FeatureCollection (one LineString per activity, coordinates in [lng, lat] order, with id/name/type/date/distance properties) or a GPX 1.1 document. Indoor activities without GPS data are skipped.
+
+Example: {"activity_id": 12345678} or {"type": "Run", "start_date": "2024-06-01", "format": "gpx"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Get Activity Route",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.getActivityRoute)
+}
+
+// getActivityRoute exports activity routes as GeoJSON or GPX
+func (s *Server) getActivityRoute(ctx context.Context, req *mcp.CallToolRequest, input GetActivityRouteInput) (*mcp.CallToolResult, GetActivityRouteOutput, error) {
+	logging.Info("MCP tool call", "tool", "get_activity_route", "activity_id", input.ActivityID, "type", input.Type, "format", input.Format)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "get_activity_route", "input", logging.ToJSON(input))
+	}
+
+	format := input.Format
+	if format == "" {
+		format = "geojson"
+	}
+	if format != "geojson" && format != "gpx" {
+		return nil, GetActivityRouteOutput{}, NewInvalidInputErrorWithDetails("invalid format", "expected 'geojson' or 'gpx'")
+	}
+
+	queries := s.queries.(RoutesQuerier)
+	output := GetActivityRouteOutput{Format: format}
+
+	var activities []db.Activity
+	if input.ActivityID > 0 {
+		activity, err := queries.GetActivity(ctx, input.ActivityID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, GetActivityRouteOutput{}, NewNotFoundErrorWithID("activity", input.ActivityID)
+			}
+			return nil, GetActivityRouteOutput{}, NewDatabaseError(err)
+		}
+		activities = []db.Activity{activity}
+		output.Filter = fmt.Sprintf("id=%d", input.ActivityID)
+	} else {
+		startTime, endTime, err := parseServerDateRange(input.StartDate, input.EndDate)
+		if err != nil {
+			return nil, GetActivityRouteOutput{}, NewInvalidInputError(err.Error())
+		}
+		hasType := input.Type != ""
+		activities, err = queries.SearchActivitiesWithRoute(ctx, db.SearchActivitiesWithRouteParams{
+			Column1:     sql.NullString{String: input.Type, Valid: hasType},
+			Type:        sql.NullString{String: input.Type, Valid: hasType},
+			Column3:     startTime,
+			StartDate:   startTime,
+			Column5:     endTime,
+			StartDate_2: endTime,
+			Limit:       int64(applyLimit(input.Limit)),
+		})
+		if err != nil {
+			return nil, GetActivityRouteOutput{}, NewDatabaseError(err)
+		}
+		output.Filter = buildFilterDesc(input.Type, input.StartDate, input.EndDate)
+	}
+
+	routes := make([]geo.Route, 0, len(activities))
+	for _, a := range activities {
+		route, err := geo.RouteFromActivity(a)
+		if err != nil {
+			logging.Debug("Skipping activity without usable route", "activity_id", a.ID, "error", err)
+			continue
+		}
+		routes = append(routes, route)
+	}
+	output.RouteCount = len(routes)
+
+	if len(routes) == 0 {
+		output.Insights = []Insight{{
+			Type:    "warning",
+			Message: "No GPS routes found. Indoor activities and activities synced before route support have no polyline.",
+		}}
+	}
+
+	switch format {
+	case "gpx":
+		var buf bytes.Buffer
+		if err := geo.WriteGPX(&buf, routes); err != nil {
+			return nil, GetActivityRouteOutput{}, NewInternalErrorWithCause("encoding GPX", err)
+		}
+		output.GPX = buf.String()
+	default:
+		fc := geo.NewFeatureCollection(routes)
+		output.GeoJSON = &fc
+	}
+	output.SuggestedActions = SuggestNextActions("routes")
+
+	logging.Info("MCP tool completed", "tool", "get_activity_route", "routes", output.RouteCount, "format", format)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "get_activity_route", "route_count", output.RouteCount)
+	}
+	return nil, output, nil
+}
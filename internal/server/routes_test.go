@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func routeTestActivities() []db.Activity {
+	run := createTestActivity(1, "Morning Run", "Run", time.Date(2024, 6, 1, 7, 0, 0, 0, time.UTC))
+	run.SummaryPolyline = sql.NullString{String: "_p~iF~ps|U_ulLnnqC_mqNvxq`@", Valid: true}
+	ride := createTestActivity(2, "Lunch Ride", "Ride", time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC))
+	ride.SummaryPolyline = sql.NullString{String: "_p~iF~ps|U", Valid: true}
+	indoor := createTestActivity(3, "Treadmill", "Run", time.Date(2024, 6, 3, 18, 0, 0, 0, time.UTC))
+	return []db.Activity{run, ride, indoor}
+}
+
+func TestGetActivityRouteGeoJSON(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{activities: routeTestActivities()})
+
+	_, output, err := srv.getActivityRoute(context.Background(), nil, GetActivityRouteInput{Type: "Run"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Format != "geojson" {
+		t.Errorf("expected default format geojson, got %s", output.Format)
+	}
+	if output.RouteCount != 1 || output.GeoJSON == nil || len(output.GeoJSON.Features) != 1 {
+		t.Fatalf("expected 1 run route, got %+v", output)
+	}
+	feature := output.GeoJSON.Features[0]
+	if feature.ID != 1 || len(feature.Geometry.Coordinates) != 3 {
+		t.Errorf("unexpected feature %+v", feature)
+	}
+	if feature.Geometry.Coordinates[0] != [2]float64{-120.2, 38.5} {
+		t.Errorf("expected [lng, lat] coordinates, got %v", feature.Geometry.Coordinates[0])
+	}
+}
+
+func TestGetActivityRouteGPX(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{activities: routeTestActivities()})
+
+	_, output, err := srv.getActivityRoute(context.Background(), nil, GetActivityRouteInput{ActivityID: 2, Format: "gpx"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.RouteCount != 1 || output.GeoJSON != nil {
+		t.Errorf("expected a single GPX route, got %+v", output)
+	}
+	if !strings.Contains(output.GPX, "<trk>") || !strings.Contains(output.GPX, "Lunch Ride") {
+		t.Errorf("unexpected GPX output: %s", output.GPX)
+	}
+}
+
+func TestGetActivityRouteWithoutGPS(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{activities: routeTestActivities()})
+
+	_, output, err := srv.getActivityRoute(context.Background(), nil, GetActivityRouteInput{ActivityID: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.RouteCount != 0 || len(output.Insights) == 0 {
+		t.Errorf("expected no routes with an explanatory insight, got %+v", output)
+	}
+}
+
+func TestGetActivityRouteErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{activities: routeTestActivities()})
+	ctx := context.Background()
+
+	if _, _, err := srv.getActivityRoute(ctx, nil, GetActivityRouteInput{Format: "kml"}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if _, _, err := srv.getActivityRoute(ctx, nil, GetActivityRouteInput{ActivityID: 999}); err == nil {
+		t.Error("expected not found error")
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/joshdurbin/strava-mcp/internal/db"
 	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -30,11 +32,14 @@ type Querier interface {
 	CountActivitiesByTypeInRange(ctx context.Context, arg db.CountActivitiesByTypeInRangeParams) (int64, error)
 	GetActivityCountsByMonth(ctx context.Context, arg db.GetActivityCountsByMonthParams) ([]db.GetActivityCountsByMonthRow, error)
 	GetActivityCountsByWeek(ctx context.Context, arg db.GetActivityCountsByWeekParams) ([]db.GetActivityCountsByWeekRow, error)
+	GetWorkoutCategoryCounts(ctx context.Context, arg db.GetWorkoutCategoryCountsParams) ([]db.GetWorkoutCategoryCountsRow, error)
+	CountActivitiesFiltered(ctx context.Context, arg db.CountActivitiesFilteredParams) (int64, error)
 	// Training summary queries
 	GetTrainingSummary(ctx context.Context) (db.GetTrainingSummaryRow, error)
 	GetTrainingSummaryByType(ctx context.Context, activityType sql.NullString) (db.GetTrainingSummaryByTypeRow, error)
 	GetTrainingSummaryInRange(ctx context.Context, arg db.GetTrainingSummaryInRangeParams) (db.GetTrainingSummaryInRangeRow, error)
 	GetTrainingSummaryByTypeInRange(ctx context.Context, arg db.GetTrainingSummaryByTypeInRangeParams) (db.GetTrainingSummaryByTypeInRangeRow, error)
+	GetTrainingSummaryFiltered(ctx context.Context, arg db.GetTrainingSummaryFilteredParams) (db.GetTrainingSummaryFilteredRow, error)
 	// Period stats queries
 	GetPeriodStats(ctx context.Context, arg db.GetPeriodStatsParams) (db.GetPeriodStatsRow, error)
 	GetPeriodStatsByType(ctx context.Context, arg db.GetPeriodStatsByTypeParams) (db.GetPeriodStatsByTypeRow, error)
@@ -51,6 +56,8 @@ type Querier interface {
 	GetPowerZoneSummary(ctx context.Context) ([]db.GetPowerZoneSummaryRow, error)
 	GetPowerZoneSummaryByType(ctx context.Context, activityType sql.NullString) ([]db.GetPowerZoneSummaryByTypeRow, error)
 	GetPowerZoneSummaryInRange(ctx context.Context, arg db.GetPowerZoneSummaryInRangeParams) ([]db.GetPowerZoneSummaryInRangeRow, error)
+	GetZoneSourceCounts(ctx context.Context, zoneType string) ([]db.GetZoneSourceCountsRow, error)
+	GetPaceZoneSummary(ctx context.Context, arg db.GetPaceZoneSummaryParams) ([]db.GetPaceZoneSummaryRow, error)
 	// Personal records queries
 	GetFastestActivity(ctx context.Context) (db.Activity, error)
 	GetFastestActivityByType(ctx context.Context, activityType sql.NullString) (db.Activity, error)
@@ -71,12 +78,107 @@ type Querier interface {
 	SearchActivitiesByDuration(ctx context.Context, arg db.SearchActivitiesByDurationParams) ([]db.Activity, error)
 	SearchActivitiesBySpeed(ctx context.Context, arg db.SearchActivitiesBySpeedParams) ([]db.Activity, error)
 	SearchActivitiesByElevation(ctx context.Context, arg db.SearchActivitiesByElevationParams) ([]db.Activity, error)
+	// Route queries
+	SearchActivitiesWithRoute(ctx context.Context, arg db.SearchActivitiesWithRouteParams) ([]db.Activity, error)
+	// Stream queries
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+	// Exploration queries
+	ListLatlngStreams(ctx context.Context, arg db.ListLatlngStreamsParams) ([]db.ListLatlngStreamsRow, error)
+	ListPolylinesWithoutStreams(ctx context.Context, arg db.ListPolylinesWithoutStreamsParams) ([]db.ListPolylinesWithoutStreamsRow, error)
+	// Detected route queries
+	GetRoutesDetected(ctx context.Context, id int64) (db.RoutesDetected, error)
+	GetRouteEffort(ctx context.Context, activityID int64) (db.RouteEffort, error)
+	GetRouteEffortActivities(ctx context.Context, routeID int64) ([]db.Activity, error)
+	ListRecurringRoutes(ctx context.Context, arg db.ListRecurringRoutesParams) ([]db.ListRecurringRoutesRow, error)
+	// Threshold queries
+	ListThresholdHistory(ctx context.Context) ([]db.ThresholdHistory, error)
+	// Race calendar queries
+	CreateRace(ctx context.Context, arg db.CreateRaceParams) (db.Race, error)
+	GetRace(ctx context.Context, id int64) (db.Race, error)
+	ListRaces(ctx context.Context) ([]db.Race, error)
+	ListUpcomingRaces(ctx context.Context, raceDate time.Time) ([]db.Race, error)
+	DeleteRace(ctx context.Context, id int64) (int64, error)
+	// Data quality queries
+	ListActivityFlags(ctx context.Context, arg db.ListActivityFlagsParams) ([]db.ListActivityFlagsRow, error)
+	CountActivityFlagsByCode(ctx context.Context) ([]db.CountActivityFlagsByCodeRow, error)
+	CountActivitiesQualityChecked(ctx context.Context) (int64, error)
+	CountExcludedActivities(ctx context.Context) (int64, error)
+	// Activity override queries
+	GetActivityOverride(ctx context.Context, activityID int64) (db.ActivityOverride, error)
+	SaveActivityCorrection(ctx context.Context, arg db.UpsertActivityCorrectionParams) error
+	ResetActivityOverride(ctx context.Context, activityID int64) error
+	SetActivityExclusion(ctx context.Context, arg db.SetActivityCountedParams) error
+	IsActivityExcluded(ctx context.Context, activityID int64) (int64, error)
+	// Strava write-back queries
+	CreateActivity(ctx context.Context, arg db.CreateActivityParams) error
+	UpsertActivitySocialCounts(ctx context.Context, arg db.UpsertActivitySocialCountsParams) error
+	// Social queries
+	GetSocialSummary(ctx context.Context, arg db.GetSocialSummaryParams) (db.GetSocialSummaryRow, error)
+	GetSocialByMonth(ctx context.Context, arg db.GetSocialByMonthParams) ([]db.GetSocialByMonthRow, error)
+	GetSocialByType(ctx context.Context, arg db.GetSocialByTypeParams) ([]db.GetSocialByTypeRow, error)
+	GetSocialByHour(ctx context.Context, arg db.GetSocialByHourParams) ([]db.GetSocialByHourRow, error)
+	GetSocialByWeekday(ctx context.Context, arg db.GetSocialByWeekdayParams) ([]db.GetSocialByWeekdayRow, error)
+	GetMostKudoedActivities(ctx context.Context, arg db.GetMostKudoedActivitiesParams) ([]db.GetMostKudoedActivitiesRow, error)
+	GetTopSupporters(ctx context.Context, arg db.GetTopSupportersParams) ([]db.GetTopSupportersRow, error)
+	CountSocialListsSynced(ctx context.Context) (int64, error)
+	// Club queries
+	ListClubs(ctx context.Context) ([]db.Club, error)
+	GetClub(ctx context.Context, id int64) (db.Club, error)
+	ListClubActivityCounts(ctx context.Context, firstSeenAt time.Time) ([]db.ListClubActivityCountsRow, error)
+	GetClubWeeklyTotals(ctx context.Context, arg db.GetClubWeeklyTotalsParams) ([]db.GetClubWeeklyTotalsRow, error)
+	GetClubLeaderboard(ctx context.Context, arg db.GetClubLeaderboardParams) ([]db.GetClubLeaderboardRow, error)
+	GetClubTypeTotals(ctx context.Context, arg db.GetClubTypeTotalsParams) ([]db.GetClubTypeTotalsRow, error)
+	CountClubBackfillActivities(ctx context.Context, arg db.CountClubBackfillActivitiesParams) (int64, error)
+	// Route library queries
+	ListSavedRoutes(ctx context.Context) ([]db.ListSavedRoutesRow, error)
+	GetSavedRouteGPX(ctx context.Context, id int64) (db.GetSavedRouteGPXRow, error)
+	ListActivitiesForRouteMatch(ctx context.Context, arg db.ListActivitiesForRouteMatchParams) ([]db.Activity, error)
+	ListStarredSegments(ctx context.Context) ([]db.StarredSegment, error)
+	// Sync integrity queries
+	ListSyncIntegrity(ctx context.Context) ([]db.SyncIntegrity, error)
+	ListSyncFailures(ctx context.Context) ([]db.SyncFailure, error)
+	ListSyncRepairs(ctx context.Context, limit int64) ([]db.SyncRepair, error)
 }
 
 // Server wraps the MCP server and database queries
 type Server struct {
 	mcp     *mcp.Server
 	queries Querier
+	// newStravaClient connects tools that change activities to Strava; nil
+	// when running offline
+	newStravaClient func() (StravaClient, error)
+	// confirmations are the confirm tokens handed out for changes waiting on
+	// the user
+	confirmations confirmations
+}
+
+// StravaClient defines the Strava API calls made by tools that create and
+// change activities
+type StravaClient interface {
+	UpdateActivity(ctx context.Context, activityID int64, update strava.ActivityUpdate) (*strava.Activity, error)
+	CreateActivity(ctx context.Context, manual strava.ManualActivity) (*strava.Activity, error)
+	UploadActivity(ctx context.Context, file strava.UploadFile) (*strava.Upload, error)
+	WaitForUpload(ctx context.Context, uploadID int64, interval time.Duration) (*strava.Upload, error)
+	FetchActivity(ctx context.Context, activityID int64) (*strava.Activity, error)
+}
+
+// WithStrava lets tools change activities on Strava. newClient is called for
+// each change so it can use a freshly refreshed access token.
+func (s *Server) WithStrava(newClient func() (StravaClient, error)) *Server {
+	s.newStravaClient = newClient
+	return s
+}
+
+// stravaClient returns a client for changing activities on Strava
+func (s *Server) stravaClient() (StravaClient, error) {
+	if s.newStravaClient == nil {
+		return nil, NewStravaUnavailableError()
+	}
+	client, err := s.newStravaClient()
+	if err != nil {
+		return nil, NewStravaError(err)
+	}
+	return client, nil
 }
 
 // MCPServer returns the underlying MCP server (for use with HTTP/SSE transport)
@@ -104,6 +206,24 @@ func New(queries Querier) *Server {
 	s.registerZoneTools()
 	s.registerProgressTools()
 	s.registerRecordsTools()
+	s.registerRouteTools()
+	s.registerMapTools()
+	s.registerExplorationTools()
+	s.registerRouteEffortTools()
+	s.registerCompareActivitiesTools()
+	s.registerThresholdTools()
+	s.registerDescribeWorkoutTools()
+	s.registerTrainingBlockTools()
+	s.registerRaceTools()
+	s.registerForecastTools()
+	s.registerDataQualityTools()
+	s.registerOverrideTools()
+	s.registerStravaActivityTools()
+	s.registerCreateActivityTools()
+	s.registerSocialTools()
+	s.registerClubTools()
+	s.registerRouteLibraryTools()
+	s.registerSyncIntegrityTools()
 
 	logging.Debug("Registering MCP resources")
 	s.registerResources()
@@ -111,7 +231,7 @@ func New(queries Querier) *Server {
 	logging.Debug("Registering MCP prompts")
 	s.registerPrompts()
 
-	logging.Info("MCP server initialized", "tools_registered", 11, "resources_registered", 4, "prompts_registered", 4)
+	logging.Info("MCP server initialized", "tools_registered", 36, "resources_registered", 4, "prompts_registered", 4)
 	return s
 }
 
@@ -140,10 +260,11 @@ Parameters:
 - type (string): Filter by activity type (Run, Ride, Swim, Walk, Hike, etc.).
 - start_date (string): Start date in YYYY-MM-DD format.
 - end_date (string): End date in YYYY-MM-DD format.
+- workout_category (string): Filter by classified workout: "easy", "tempo", "intervals", "long", or "race".
 - sort_by (string): Sort results by "date", "distance", "duration", "pace", or "elevation". Default: "date".
 - limit (integer): Number of activities to return. Default: 20, Max: 100.
 
-Returns: List of activities with id, name, type, date, distance, duration, pace, elevation, heartrate, and calories.
+Returns: List of activities with id, name, type, date, distance, duration, pace, elevation, heartrate, calories, and workout category with its confidence.
 
 Example: {"query": "latest"} or {"type": "Run", "start_date": "2024-01-01", "sort_by": "distance", "limit": 10}`,
 		Annotations: &mcp.ToolAnnotations{
@@ -165,16 +286,18 @@ Use when:
 - User asks "How many runs have I done?" or "Activity count by month"
 - User wants to see training frequency over time
 - User needs activity breakdown by type
+- User asks "How many tempo runs did I do this block?"
 
 Parameters:
 - type (string): Filter by activity type (Run, Ride, Swim, etc.). Leave empty for all types.
 - start_date (string): Start date in YYYY-MM-DD format. Leave empty for all time.
 - end_date (string): End date in YYYY-MM-DD format. Leave empty for all time.
-- group_by (string): Group results by "type", "month", or "week". Omit for total count only.
+- workout_category (string): Count only "easy", "tempo", "intervals", "long", or "race" workouts.
+- group_by (string): Group results by "type", "workout_category", "month", or "week". Omit for total count only.
 
-Returns: Total count and/or grouped counts with activity type breakdown.
+Returns: Total count and/or grouped counts with activity type or workout category breakdown.
 
-Example: {"group_by": "type"} or {"type": "Run", "start_date": "2024-01-01", "group_by": "month"}`,
+Example: {"group_by": "type"} or {"type": "Run", "workout_category": "tempo", "start_date": "2024-01-01", "group_by": "month"}`,
 		Annotations: &mcp.ToolAnnotations{
 			Title:           "Count Activities",
 			ReadOnlyHint:    true,
@@ -194,11 +317,13 @@ Use when:
 - User asks "What's my total distance?" or "Training overview"
 - User wants aggregate stats for all activities or a specific type/period
 - User needs to understand their overall training volume
+- User wants volume for one kind of session, like long runs or intervals
 
 Parameters:
 - type (string): Filter by activity type (Run, Ride, Swim, etc.). Leave empty for all types.
 - start_date (string): Start date in YYYY-MM-DD format. Leave empty for all time.
 - end_date (string): End date in YYYY-MM-DD format. Leave empty for all time.
+- workout_category (string): Summarize only "easy", "tempo", "intervals", "long", or "race" workouts.
 
 Returns: Activity count, total distance, total duration, average pace, average heartrate, total calories, and total elevation with insights.
 
@@ -285,9 +410,10 @@ type FindActivitiesInput struct {
 	ID    int64  `json:"id,omitempty" jsonschema:"Get a specific activity by its unique Strava activity ID. When set, overrides other parameters."`
 
 	// Filters
-	Type      string `json:"type,omitempty" jsonschema:"Filter by activity type. Common values: Run, Ride, Swim, Walk, Hike, VirtualRide, WeightTraining, Yoga."`
-	StartDate string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD (e.g., 2024-01-15)."`
-	EndDate   string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD (e.g., 2024-12-31)."`
+	Type            string `json:"type,omitempty" jsonschema:"Filter by activity type. Common values: Run, Ride, Swim, Walk, Hike, VirtualRide, WeightTraining, Yoga."`
+	StartDate       string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD (e.g., 2024-01-15)."`
+	EndDate         string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD (e.g., 2024-12-31)."`
+	WorkoutCategory string `json:"workout_category,omitempty" jsonschema:"Filter by classified workout category. Valid values: easy, tempo, intervals, long, race."`
 
 	// Sorting and pagination
 	SortBy string `json:"sort_by,omitempty" jsonschema:"Sort results by this field. Valid values: date (newest first), distance (longest first), duration (longest first), pace (fastest first), elevation (most climbing first). Default: date."`
@@ -305,18 +431,20 @@ type FindActivitiesOutput struct {
 
 // CountActivitiesConsolidatedInput - input for counting activities with optional grouping
 type CountActivitiesConsolidatedInput struct {
-	Type      string `json:"type,omitempty" jsonschema:"Filter by activity type. Common values: Run, Ride, Swim, Walk, Hike. Leave empty to count all activity types."`
-	StartDate string `json:"start_date,omitempty" jsonschema:"Count activities on or after this date. Format: YYYY-MM-DD. Leave empty to include all historical activities."`
-	EndDate   string `json:"end_date,omitempty" jsonschema:"Count activities on or before this date. Format: YYYY-MM-DD. Leave empty to include up to today."`
-	GroupBy   string `json:"group_by,omitempty" jsonschema:"How to group results. Valid values: 'type' (by activity type), 'month' (by calendar month), 'week' (by calendar week). Omit for single total count."`
+	Type            string `json:"type,omitempty" jsonschema:"Filter by activity type. Common values: Run, Ride, Swim, Walk, Hike. Leave empty to count all activity types."`
+	StartDate       string `json:"start_date,omitempty" jsonschema:"Count activities on or after this date. Format: YYYY-MM-DD. Leave empty to include all historical activities."`
+	EndDate         string `json:"end_date,omitempty" jsonschema:"Count activities on or before this date. Format: YYYY-MM-DD. Leave empty to include up to today."`
+	WorkoutCategory string `json:"workout_category,omitempty" jsonschema:"Count only activities classified into this workout category. Valid values: easy, tempo, intervals, long, race."`
+	GroupBy         string `json:"group_by,omitempty" jsonschema:"How to group results. Valid values: 'type' (by activity type), 'workout_category' (by classified workout category), 'month' (by calendar month), 'week' (by calendar week). Omit for single total count."`
 }
 
 // Consolidated count activities output
 type CountActivitiesConsolidatedOutput struct {
-	Count    int64           `json:"count,omitempty"`
-	ByType   []TypeCount     `json:"by_type,omitempty"`
-	ByPeriod []PeriodSummary `json:"by_period,omitempty"`
-	Filter   string          `json:"filter,omitempty"`
+	Count             int64                  `json:"count,omitempty"`
+	ByType            []TypeCount            `json:"by_type,omitempty"`
+	ByWorkoutCategory []WorkoutCategoryCount `json:"by_workout_category,omitempty"`
+	ByPeriod          []PeriodSummary        `json:"by_period,omitempty"`
+	Filter            string                 `json:"filter,omitempty"`
 }
 
 type PeriodSummary struct {
@@ -327,9 +455,10 @@ type PeriodSummary struct {
 
 // TrainingSummaryInput - input for retrieving training summary statistics
 type TrainingSummaryInput struct {
-	Type      string `json:"type,omitempty" jsonschema:"Filter statistics by activity type. Common values: Run, Ride, Swim, Walk, Hike. Leave empty for summary across all activity types."`
-	StartDate string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD. Leave empty for all-time statistics."`
-	EndDate   string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD. Leave empty to include up to today."`
+	Type            string `json:"type,omitempty" jsonschema:"Filter statistics by activity type. Common values: Run, Ride, Swim, Walk, Hike. Leave empty for summary across all activity types."`
+	StartDate       string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD. Leave empty for all-time statistics."`
+	EndDate         string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD. Leave empty to include up to today."`
+	WorkoutCategory string `json:"workout_category,omitempty" jsonschema:"Summarize only activities classified into this workout category. Valid values: easy, tempo, intervals, long, race."`
 }
 
 type TrainingSummaryOutput struct {
@@ -397,17 +526,19 @@ type WeekSummaryOutput struct {
 }
 
 type ActivitySummary struct {
-	ID            int64  `json:"id,omitempty"`
-	Name          string `json:"name,omitempty"`
-	Type          string `json:"type,omitempty"`
-	Date          string `json:"date,omitempty"`
-	Distance      string `json:"distance,omitempty"`
-	Duration      string `json:"duration,omitempty"`
-	Pace          string `json:"pace,omitempty"`
-	ElevationGain string `json:"elevation_gain,omitempty"`
-	AvgHeartrate  int    `json:"avg_heartrate_bpm,omitempty"`
-	MaxHeartrate  int    `json:"max_heartrate_bpm,omitempty"`
-	Calories      int    `json:"calories,omitempty"`
+	ID                int64   `json:"id,omitempty"`
+	Name              string  `json:"name,omitempty"`
+	Type              string  `json:"type,omitempty"`
+	Date              string  `json:"date,omitempty"`
+	Distance          string  `json:"distance,omitempty"`
+	Duration          string  `json:"duration,omitempty"`
+	Pace              string  `json:"pace,omitempty"`
+	ElevationGain     string  `json:"elevation_gain,omitempty"`
+	AvgHeartrate      int     `json:"avg_heartrate_bpm,omitempty"`
+	MaxHeartrate      int     `json:"max_heartrate_bpm,omitempty"`
+	Calories          int     `json:"calories,omitempty"`
+	WorkoutCategory   string  `json:"workout_category,omitempty"`
+	WorkoutConfidence float64 `json:"workout_confidence,omitempty"`
 }
 
 type TypeCount struct {
@@ -415,11 +546,16 @@ type TypeCount struct {
 	Count int64  `json:"count"`
 }
 
+type WorkoutCategoryCount struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
 // Tool handlers
 
 // findActivities - consolidated activity search handler
 func (s *Server) findActivities(ctx context.Context, req *mcp.CallToolRequest, input FindActivitiesInput) (*mcp.CallToolResult, FindActivitiesOutput, error) {
-	logging.Info("MCP tool call", "tool", "find_activities", "query", input.Query, "id", input.ID, "type", input.Type, "sort_by", input.SortBy)
+	logging.Info("MCP tool call", "tool", "find_activities", "query", input.Query, "id", input.ID, "type", input.Type, "workout_category", input.WorkoutCategory, "sort_by", input.SortBy)
 	if logging.IsVerbose() {
 		logging.Debug("MCP request params", "tool", "find_activities", "input", logging.ToJSON(input))
 	}
@@ -527,6 +663,11 @@ func (s *Server) findActivities(ctx context.Context, req *mcp.CallToolRequest, i
 		sortBy = "date"
 	}
 
+	category, err := workoutCategoryFilter(input.WorkoutCategory)
+	if err != nil {
+		return nil, FindActivitiesOutput{}, err
+	}
+
 	// Parse date filters
 	hasType := input.Type != ""
 	hasDateRange := input.StartDate != "" || input.EndDate != ""
@@ -543,53 +684,63 @@ func (s *Server) findActivities(ctx context.Context, req *mcp.CallToolRequest, i
 	switch sortBy {
 	case "distance":
 		activities, err = s.queries.SearchActivitiesByDistance(ctx, db.SearchActivitiesByDistanceParams{
-			Column1:   sql.NullString{String: input.Type, Valid: hasType},
-			Type:      sql.NullString{String: input.Type, Valid: hasType},
-			Column3:   startTime,
-			StartDate: startTime,
-			Column5:   endTime,
-			StartDate_2: endTime,
-			Limit:     int64(limit),
+			Column1:         sql.NullString{String: input.Type, Valid: hasType},
+			Type:            sql.NullString{String: input.Type, Valid: hasType},
+			Column3:         startTime,
+			StartDate:       startTime,
+			Column5:         endTime,
+			StartDate_2:     endTime,
+			Column7:         category,
+			WorkoutCategory: category,
+			Limit:           int64(limit),
 		})
 	case "duration":
 		activities, err = s.queries.SearchActivitiesByDuration(ctx, db.SearchActivitiesByDurationParams{
-			Column1:   sql.NullString{String: input.Type, Valid: hasType},
-			Type:      sql.NullString{String: input.Type, Valid: hasType},
-			Column3:   startTime,
-			StartDate: startTime,
-			Column5:   endTime,
-			StartDate_2: endTime,
-			Limit:     int64(limit),
+			Column1:         sql.NullString{String: input.Type, Valid: hasType},
+			Type:            sql.NullString{String: input.Type, Valid: hasType},
+			Column3:         startTime,
+			StartDate:       startTime,
+			Column5:         endTime,
+			StartDate_2:     endTime,
+			Column7:         category,
+			WorkoutCategory: category,
+			Limit:           int64(limit),
 		})
 	case "pace", "speed":
 		activities, err = s.queries.SearchActivitiesBySpeed(ctx, db.SearchActivitiesBySpeedParams{
-			Column1:   sql.NullString{String: input.Type, Valid: hasType},
-			Type:      sql.NullString{String: input.Type, Valid: hasType},
-			Column3:   startTime,
-			StartDate: startTime,
-			Column5:   endTime,
-			StartDate_2: endTime,
-			Limit:     int64(limit),
+			Column1:         sql.NullString{String: input.Type, Valid: hasType},
+			Type:            sql.NullString{String: input.Type, Valid: hasType},
+			Column3:         startTime,
+			StartDate:       startTime,
+			Column5:         endTime,
+			StartDate_2:     endTime,
+			Column7:         category,
+			WorkoutCategory: category,
+			Limit:           int64(limit),
 		})
 	case "elevation":
 		activities, err = s.queries.SearchActivitiesByElevation(ctx, db.SearchActivitiesByElevationParams{
-			Column1:   sql.NullString{String: input.Type, Valid: hasType},
-			Type:      sql.NullString{String: input.Type, Valid: hasType},
-			Column3:   startTime,
-			StartDate: startTime,
-			Column5:   endTime,
-			StartDate_2: endTime,
-			Limit:     int64(limit),
+			Column1:         sql.NullString{String: input.Type, Valid: hasType},
+			Type:            sql.NullString{String: input.Type, Valid: hasType},
+			Column3:         startTime,
+			StartDate:       startTime,
+			Column5:         endTime,
+			StartDate_2:     endTime,
+			Column7:         category,
+			WorkoutCategory: category,
+			Limit:           int64(limit),
 		})
 	default: // date
 		activities, err = s.queries.SearchActivities(ctx, db.SearchActivitiesParams{
-			Column1:   sql.NullString{String: input.Type, Valid: hasType},
-			Type:      sql.NullString{String: input.Type, Valid: hasType},
-			Column3:   startTime,
-			StartDate: startTime,
-			Column5:   endTime,
-			StartDate_2: endTime,
-			Limit:     int64(limit),
+			Column1:         sql.NullString{String: input.Type, Valid: hasType},
+			Type:            sql.NullString{String: input.Type, Valid: hasType},
+			Column3:         startTime,
+			StartDate:       startTime,
+			Column5:         endTime,
+			StartDate_2:     endTime,
+			Column7:         category,
+			WorkoutCategory: category,
+			Limit:           int64(limit),
 		})
 	}
 
@@ -610,20 +761,60 @@ func (s *Server) findActivities(ctx context.Context, req *mcp.CallToolRequest, i
 
 // Consolidated count activities handler
 func (s *Server) countActivitiesConsolidated(ctx context.Context, req *mcp.CallToolRequest, input CountActivitiesConsolidatedInput) (*mcp.CallToolResult, CountActivitiesConsolidatedOutput, error) {
-	logging.Info("MCP tool call", "tool", "count_activities", "type", input.Type, "group_by", input.GroupBy, "start", input.StartDate, "end", input.EndDate)
+	logging.Info("MCP tool call", "tool", "count_activities", "type", input.Type, "workout_category", input.WorkoutCategory, "group_by", input.GroupBy, "start", input.StartDate, "end", input.EndDate)
 	if logging.IsVerbose() {
 		logging.Debug("MCP request params", "tool", "count_activities", "input", logging.ToJSON(input))
 	}
 
 	output := CountActivitiesConsolidatedOutput{
-		Filter: buildFilterDesc(input.Type, input.StartDate, input.EndDate),
+		Filter: buildWorkoutFilterDesc(input.Type, input.WorkoutCategory, input.StartDate, input.EndDate),
+	}
+
+	category, err := workoutCategoryFilter(input.WorkoutCategory)
+	if err != nil {
+		return nil, CountActivitiesConsolidatedOutput{}, err
 	}
 
 	hasType := input.Type != ""
 	hasDateRange := input.StartDate != "" || input.EndDate != ""
 
-	switch input.GroupBy {
-	case "type":
+	switch {
+	case input.GroupBy == "workout_category" || (input.GroupBy == "type" && category.Valid):
+		// Type summaries don't know about categories, so group the
+		// classified counts instead
+		start, end, err := parseServerDateRange(input.StartDate, input.EndDate)
+		if err != nil {
+			return nil, CountActivitiesConsolidatedOutput{}, err
+		}
+		activityType := sql.NullString{String: input.Type, Valid: hasType}
+		rows, err := s.queries.GetWorkoutCategoryCounts(ctx, db.GetWorkoutCategoryCountsParams{
+			Column1:         activityType,
+			Type:            activityType,
+			Column3:         start,
+			StartDate:       start,
+			Column5:         end,
+			StartDate_2:     end,
+			Column7:         category,
+			WorkoutCategory: category,
+		})
+		if err != nil {
+			return nil, CountActivitiesConsolidatedOutput{}, err
+		}
+		if input.GroupBy == "type" {
+			output.ByType, output.Count = sumWorkoutCounts(rows,
+				func(r db.GetWorkoutCategoryCountsRow) string { return r.Type.String },
+				func(key string, count int64) TypeCount { return TypeCount{Type: key, Count: count} },
+			)
+		} else {
+			output.ByWorkoutCategory, output.Count = sumWorkoutCounts(rows,
+				func(r db.GetWorkoutCategoryCountsRow) string { return r.WorkoutCategory.String },
+				func(key string, count int64) WorkoutCategoryCount {
+					return WorkoutCategoryCount{Category: key, Count: count}
+				},
+			)
+		}
+
+	case input.GroupBy == "type":
 		// Group by activity type
 		if hasDateRange {
 			start, end, err := parseServerDateRange(input.StartDate, input.EndDate)
@@ -664,15 +855,17 @@ func (s *Server) countActivitiesConsolidated(ctx context.Context, req *mcp.CallT
 			output.Count = total
 		}
 
-	case "month":
+	case input.GroupBy == "month":
 		// Group by month
 		start, end, err := parseServerDateRange(input.StartDate, input.EndDate)
 		if err != nil {
 			return nil, CountActivitiesConsolidatedOutput{}, err
 		}
 		rows, err := s.queries.GetActivityCountsByMonth(ctx, db.GetActivityCountsByMonthParams{
-			StartDate:   start,
-			StartDate_2: end,
+			StartDate:       start,
+			StartDate_2:     end,
+			Column3:         category,
+			WorkoutCategory: category,
 		})
 		if err != nil {
 			return nil, CountActivitiesConsolidatedOutput{}, err
@@ -695,15 +888,17 @@ func (s *Server) countActivitiesConsolidated(ctx context.Context, req *mcp.CallT
 		}
 		output.Count = total
 
-	case "week":
+	case input.GroupBy == "week":
 		// Group by week
 		start, end, err := parseServerDateRange(input.StartDate, input.EndDate)
 		if err != nil {
 			return nil, CountActivitiesConsolidatedOutput{}, err
 		}
 		rows, err := s.queries.GetActivityCountsByWeek(ctx, db.GetActivityCountsByWeekParams{
-			StartDate:   start,
-			StartDate_2: end,
+			StartDate:       start,
+			StartDate_2:     end,
+			Column3:         category,
+			WorkoutCategory: category,
 		})
 		if err != nil {
 			return nil, CountActivitiesConsolidatedOutput{}, err
@@ -729,8 +924,23 @@ func (s *Server) countActivitiesConsolidated(ctx context.Context, req *mcp.CallT
 	default:
 		// Simple count
 		var count int64
-		var err error
-		if hasType && hasDateRange {
+		if category.Valid {
+			start, end, parseErr := parseServerDateRange(input.StartDate, input.EndDate)
+			if parseErr != nil {
+				return nil, CountActivitiesConsolidatedOutput{}, parseErr
+			}
+			activityType := sql.NullString{String: input.Type, Valid: hasType}
+			count, err = s.queries.CountActivitiesFiltered(ctx, db.CountActivitiesFilteredParams{
+				Column1:         activityType,
+				Type:            activityType,
+				Column3:         start,
+				StartDate:       start,
+				Column5:         end,
+				StartDate_2:     end,
+				Column7:         category,
+				WorkoutCategory: category,
+			})
+		} else if hasType && hasDateRange {
 			start, end, parseErr := parseServerDateRange(input.StartDate, input.EndDate)
 			if parseErr != nil {
 				return nil, CountActivitiesConsolidatedOutput{}, parseErr
@@ -769,13 +979,18 @@ func (s *Server) countActivitiesConsolidated(ctx context.Context, req *mcp.CallT
 
 // Training summary handler
 func (s *Server) getTrainingSummary(ctx context.Context, req *mcp.CallToolRequest, input TrainingSummaryInput) (*mcp.CallToolResult, TrainingSummaryOutput, error) {
-	logging.Info("MCP tool call", "tool", "get_training_summary", "type", input.Type, "start", input.StartDate, "end", input.EndDate)
+	logging.Info("MCP tool call", "tool", "get_training_summary", "type", input.Type, "workout_category", input.WorkoutCategory, "start", input.StartDate, "end", input.EndDate)
 	if logging.IsVerbose() {
 		logging.Debug("MCP request params", "tool", "get_training_summary", "input", logging.ToJSON(input))
 	}
 
 	output := TrainingSummaryOutput{
-		Filter: buildFilterDesc(input.Type, input.StartDate, input.EndDate),
+		Filter: buildWorkoutFilterDesc(input.Type, input.WorkoutCategory, input.StartDate, input.EndDate),
+	}
+
+	category, err := workoutCategoryFilter(input.WorkoutCategory)
+	if err != nil {
+		return nil, TrainingSummaryOutput{}, err
 	}
 
 	hasType := input.Type != ""
@@ -783,7 +998,35 @@ func (s *Server) getTrainingSummary(ctx context.Context, req *mcp.CallToolReques
 
 	var row trainingSummaryData
 
-	if hasType && hasDateRange {
+	if category.Valid {
+		start, end, err := parseServerDateRange(input.StartDate, input.EndDate)
+		if err != nil {
+			return nil, TrainingSummaryOutput{}, err
+		}
+		activityType := sql.NullString{String: input.Type, Valid: hasType}
+		dbRow, err := s.queries.GetTrainingSummaryFiltered(ctx, db.GetTrainingSummaryFilteredParams{
+			Column1:         activityType,
+			Type:            activityType,
+			Column3:         start,
+			StartDate:       start,
+			Column5:         end,
+			StartDate_2:     end,
+			Column7:         category,
+			WorkoutCategory: category,
+		})
+		if err != nil {
+			return nil, TrainingSummaryOutput{}, err
+		}
+		row = trainingSummaryData{
+			ActivityCount:   dbRow.ActivityCount,
+			TotalDistance:   toFloat64(dbRow.TotalDistance),
+			TotalMovingTime: toInt64(dbRow.TotalMovingTime),
+			AvgSpeed:        toFloat64(dbRow.AvgSpeed),
+			AvgHeartrate:    toFloat64(dbRow.AvgHeartrate),
+			TotalCalories:   toFloat64(dbRow.TotalCalories),
+			TotalElevation:  toFloat64(dbRow.TotalElevation),
+		}
+	} else if hasType && hasDateRange {
 		start, end, err := parseServerDateRange(input.StartDate, input.EndDate)
 		if err != nil {
 			return nil, TrainingSummaryOutput{}, err
@@ -1092,14 +1335,14 @@ func (s *Server) getWeekSummary(ctx context.Context, req *mcp.CallToolRequest, i
 	}
 
 	output := WeekSummaryOutput{
-		Week:           weekLabel,
-		DateRange:      fmt.Sprintf("%s to %s", weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02")),
-		ActivityCount:  int64(len(activities)),
-		TotalDistance:  formatDistance(totalDistance),
-		TotalDuration:  formatDuration(totalDuration),
-		TotalCalories:  int(totalCalories),
-		TotalElevation: fmt.Sprintf("%.0fm", totalElevation),
-		Activities:     convertActivities(activities),
+		Week:             weekLabel,
+		DateRange:        fmt.Sprintf("%s to %s", weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02")),
+		ActivityCount:    int64(len(activities)),
+		TotalDistance:    formatDistance(totalDistance),
+		TotalDuration:    formatDuration(totalDuration),
+		TotalCalories:    int(totalCalories),
+		TotalElevation:   fmt.Sprintf("%.0fm", totalElevation),
+		Activities:       convertActivities(activities),
 		SuggestedActions: SuggestNextActions("week_summary"),
 	}
 
@@ -1190,6 +1433,10 @@ func convertActivity(a db.Activity) ActivitySummary {
 	if a.Calories.Valid && a.Calories.Float64 > 0 {
 		summary.Calories = int(a.Calories.Float64)
 	}
+	if a.WorkoutCategory.Valid {
+		summary.WorkoutCategory = a.WorkoutCategory.String
+		summary.WorkoutConfidence = math.Round(a.WorkoutConfidence.Float64*100) / 100
+	}
 
 	return summary
 }
@@ -1,20 +1,92 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/joshdurbin/strava-mcp/internal/db"
+
+	_ "modernc.org/sqlite"
 )
 
+// newSQLiteQueries opens a temporary database with the full schema, so the
+// real queries run against times stored the way the driver stores them
+func newSQLiteQueries(t *testing.T) *db.Queries {
+	t.Helper()
+	return db.New(newSQLiteDB(t))
+}
+
+// newSQLiteDB opens a temporary database with the full schema
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	schema, err := os.ReadFile("../../sql/schema.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	sqlDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := sqlDB.Exec(string(schema)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return sqlDB
+}
+
+// createSQLiteActivity stores an activity of the type starting at start
+func createSQLiteActivity(t *testing.T, queries *db.Queries, id int64, activityType string, start time.Time, meters float64) {
+	t.Helper()
+	err := queries.CreateActivity(context.Background(), db.CreateActivityParams{
+		ID:             id,
+		Name:           activityType + " " + strconv.FormatInt(id, 10),
+		Distance:       sql.NullFloat64{Float64: meters, Valid: true},
+		MovingTime:     sql.NullInt64{Int64: int64(meters / 3), Valid: true},
+		ElapsedTime:    sql.NullInt64{Int64: int64(meters / 3), Valid: true},
+		Type:           sql.NullString{String: activityType, Valid: true},
+		SportType:      sql.NullString{String: activityType, Valid: true},
+		StartDate:      sql.NullTime{Time: start.UTC(), Valid: true},
+		StartDateLocal: sql.NullTime{Time: start.UTC(), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to create activity %d: %v", id, err)
+	}
+}
+
 // MockQuerier implements the Querier interface for testing
 type MockQuerier struct {
 	activities          []db.Activity
 	activityTypeSummary []db.GetActivityTypeSummaryRow
 	countByMonth        []db.GetActivityCountsByMonthRow
 	countByWeek         []db.GetActivityCountsByWeekRow
+	streams             map[int64]db.ActivityStream
+	detectedRoutes      []db.RoutesDetected
+	routeEfforts        []db.RouteEffort
+	thresholdHistory    []db.ThresholdHistory
+	races               []db.Race
+	weeklyVolume        []db.GetWeeklyVolumeRow
+	activityFlags       []db.ListActivityFlagsRow
+	qualityChecked      int64
+	overrides           map[int64]db.ActivityOverride
+	social              map[int64]db.UpsertActivitySocialCountsParams
+	supporters          []db.GetTopSupportersRow
+	socialListsSynced   int64
+	clubs               []db.Club
+	clubActivities      []db.ClubActivity
+	savedRoutes         []db.SavedRoute
+	starredSegments     []db.StarredSegment
+	syncIntegrity       []db.SyncIntegrity
+	syncFailures        []db.SyncFailure
+	syncRepairs         []db.SyncRepair
 }
 
 func (m *MockQuerier) GetActivity(ctx context.Context, id int64) (db.Activity, error) {
@@ -131,6 +203,12 @@ func (m *MockQuerier) GetPowerZoneSummaryByType(ctx context.Context, activityTyp
 func (m *MockQuerier) GetPowerZoneSummaryInRange(ctx context.Context, arg db.GetPowerZoneSummaryInRangeParams) ([]db.GetPowerZoneSummaryInRangeRow, error) {
 	return nil, nil
 }
+func (m *MockQuerier) GetZoneSourceCounts(ctx context.Context, zoneType string) ([]db.GetZoneSourceCountsRow, error) {
+	return nil, nil
+}
+func (m *MockQuerier) GetPaceZoneSummary(ctx context.Context, arg db.GetPaceZoneSummaryParams) ([]db.GetPaceZoneSummaryRow, error) {
+	return nil, nil
+}
 
 // New interface methods
 func (m *MockQuerier) CountActivitiesInRange(ctx context.Context, arg db.CountActivitiesInRangeParams) (int64, error) {
@@ -318,7 +396,7 @@ func (m *MockQuerier) GetMostCaloriesActivityByType(ctx context.Context, t sql.N
 
 // Weekly volume methods
 func (m *MockQuerier) GetWeeklyVolume(ctx context.Context, arg db.GetWeeklyVolumeParams) ([]db.GetWeeklyVolumeRow, error) {
-	return nil, nil
+	return m.weeklyVolume, nil
 }
 
 func (m *MockQuerier) GetWeeklyVolumeByType(ctx context.Context, arg db.GetWeeklyVolumeByTypeParams) ([]db.GetWeeklyVolumeByTypeRow, error) {
@@ -333,6 +411,9 @@ func (m *MockQuerier) SearchActivities(ctx context.Context, arg db.SearchActivit
 		if arg.Type.Valid && (!a.Type.Valid || a.Type.String != arg.Type.String) {
 			continue
 		}
+		if arg.WorkoutCategory.Valid && a.WorkoutCategory != arg.WorkoutCategory {
+			continue
+		}
 		result = append(result, a)
 		if int64(len(result)) >= arg.Limit {
 			break
@@ -357,6 +438,112 @@ func (m *MockQuerier) SearchActivitiesByElevation(ctx context.Context, arg db.Se
 	return m.activities, nil
 }
 
+func (m *MockQuerier) SearchActivitiesWithRoute(ctx context.Context, arg db.SearchActivitiesWithRouteParams) ([]db.Activity, error) {
+	var result []db.Activity
+	for _, a := range m.activities {
+		if !a.SummaryPolyline.Valid || a.SummaryPolyline.String == "" {
+			continue
+		}
+		if arg.Type.Valid && (!a.Type.Valid || a.Type.String != arg.Type.String) {
+			continue
+		}
+		result = append(result, a)
+		if int64(len(result)) >= arg.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuerier) GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error) {
+	if row, ok := m.streams[activityID]; ok {
+		return row, nil
+	}
+	return db.ActivityStream{}, sql.ErrNoRows
+}
+
+func (m *MockQuerier) ListLatlngStreams(ctx context.Context, arg db.ListLatlngStreamsParams) ([]db.ListLatlngStreamsRow, error) {
+	var rows []db.ListLatlngStreamsRow
+	for _, a := range m.activities {
+		row, ok := m.streams[a.ID]
+		if !ok || !row.LatlngData.Valid || a.ID <= arg.ActivityID {
+			continue
+		}
+		rows = append(rows, db.ListLatlngStreamsRow{
+			ActivityID: a.ID,
+			Type:       a.Type,
+			StartDate:  a.StartDate,
+			LatlngData: row.LatlngData,
+		})
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) ListPolylinesWithoutStreams(ctx context.Context, arg db.ListPolylinesWithoutStreamsParams) ([]db.ListPolylinesWithoutStreamsRow, error) {
+	var rows []db.ListPolylinesWithoutStreamsRow
+	for _, a := range m.activities {
+		if _, ok := m.streams[a.ID]; ok || !a.SummaryPolyline.Valid || a.SummaryPolyline.String == "" {
+			continue
+		}
+		rows = append(rows, db.ListPolylinesWithoutStreamsRow{
+			ID:              a.ID,
+			Type:            a.Type,
+			StartDate:       a.StartDate,
+			SummaryPolyline: a.SummaryPolyline,
+		})
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetRoutesDetected(ctx context.Context, id int64) (db.RoutesDetected, error) {
+	for _, r := range m.detectedRoutes {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return db.RoutesDetected{}, sql.ErrNoRows
+}
+
+func (m *MockQuerier) GetRouteEffort(ctx context.Context, activityID int64) (db.RouteEffort, error) {
+	for _, e := range m.routeEfforts {
+		if e.ActivityID == activityID {
+			return e, nil
+		}
+	}
+	return db.RouteEffort{}, sql.ErrNoRows
+}
+
+func (m *MockQuerier) GetRouteEffortActivities(ctx context.Context, routeID int64) ([]db.Activity, error) {
+	var result []db.Activity
+	for _, a := range m.activities {
+		for _, e := range m.routeEfforts {
+			if e.ActivityID == a.ID && e.RouteID == routeID {
+				result = append(result, a)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *MockQuerier) ListRecurringRoutes(ctx context.Context, arg db.ListRecurringRoutesParams) ([]db.ListRecurringRoutesRow, error) {
+	var rows []db.ListRecurringRoutesRow
+	for _, r := range m.detectedRoutes {
+		if arg.ActivityType.Valid && r.ActivityType.String != arg.ActivityType.String {
+			continue
+		}
+		var count int64
+		for _, e := range m.routeEfforts {
+			if e.RouteID == r.ID {
+				count++
+			}
+		}
+		if count >= 2 {
+			rows = append(rows, db.ListRecurringRoutesRow{ID: r.ID, Name: r.Name, ActivityType: r.ActivityType, Distance: r.Distance, EffortCount: count})
+		}
+	}
+	return rows, nil
+}
+
 // Test helpers
 func createTestActivity(id int64, name, activityType string, date time.Time) db.Activity {
 	return db.Activity{
@@ -450,6 +637,132 @@ func TestConvertActivities(t *testing.T) {
 	}
 }
 
+// workoutQuerier has runs and a ride labelled by the workout classifier,
+// plus one activity that hasn't been classified
+func workoutQuerier() *MockQuerier {
+	day := time.Date(2026, 9, 1, 7, 0, 0, 0, time.UTC)
+	classified := func(id int64, activityType, category string) db.Activity {
+		a := createTestActivity(id, category, activityType, day.AddDate(0, 0, int(id)))
+		a.WorkoutCategory = sql.NullString{String: category, Valid: true}
+		a.WorkoutConfidence = sql.NullFloat64{Float64: 0.8123, Valid: true}
+		return a
+	}
+	return &MockQuerier{
+		activities: []db.Activity{
+			classified(1, "Run", "easy"),
+			classified(2, "Run", "tempo"),
+			classified(3, "Run", "easy"),
+			classified(4, "Ride", "easy"),
+			classified(5, "Run", "intervals"),
+			createTestActivity(6, "Yoga", "Yoga", day.AddDate(0, 0, 6)),
+		},
+	}
+}
+
+func TestWorkoutCategoryFilter(t *testing.T) {
+	t.Parallel()
+
+	srv := New(workoutQuerier())
+	ctx := context.Background()
+
+	_, found, err := srv.findActivities(ctx, nil, FindActivitiesInput{WorkoutCategory: "easy"})
+	if err != nil {
+		t.Fatalf("find_activities: %v", err)
+	}
+	if len(found.Activities) != 3 {
+		t.Fatalf("expected 3 easy activities, got %+v", found.Activities)
+	}
+	if a := found.Activities[0]; a.WorkoutCategory != "easy" || a.WorkoutConfidence != 0.81 {
+		t.Errorf("expected workout category and rounded confidence, got %+v", a)
+	}
+
+	_, count, err := srv.countActivitiesConsolidated(ctx, nil, CountActivitiesConsolidatedInput{Type: "Run", WorkoutCategory: "easy"})
+	if err != nil {
+		t.Fatalf("count_activities: %v", err)
+	}
+	if count.Count != 2 || count.Filter != "[type=Run workout=easy]" {
+		t.Errorf("expected 2 easy runs, got %+v", count)
+	}
+
+	_, grouped, err := srv.countActivitiesConsolidated(ctx, nil, CountActivitiesConsolidatedInput{GroupBy: "workout_category"})
+	if err != nil {
+		t.Fatalf("count_activities by category: %v", err)
+	}
+	want := []WorkoutCategoryCount{{"easy", 3}, {"tempo", 1}, {"intervals", 1}}
+	if grouped.Count != 5 || !slices.Equal(grouped.ByWorkoutCategory, want) {
+		t.Errorf("expected %v, got %+v", want, grouped)
+	}
+
+	_, byType, err := srv.countActivitiesConsolidated(ctx, nil, CountActivitiesConsolidatedInput{GroupBy: "type", WorkoutCategory: "easy"})
+	if err != nil {
+		t.Fatalf("count_activities by type: %v", err)
+	}
+	if wantTypes := []TypeCount{{"Run", 2}, {"Ride", 1}}; !slices.Equal(byType.ByType, wantTypes) {
+		t.Errorf("expected %v, got %+v", wantTypes, byType.ByType)
+	}
+
+	_, summary, err := srv.getTrainingSummary(ctx, nil, TrainingSummaryInput{Type: "Run", WorkoutCategory: "tempo"})
+	if err != nil {
+		t.Fatalf("get_training_summary: %v", err)
+	}
+	if summary.ActivityCount != 1 || summary.TotalDistance != "5.00 km" {
+		t.Errorf("expected one tempo run, got %+v", summary)
+	}
+
+	if _, _, err := srv.countActivitiesConsolidated(ctx, nil, CountActivitiesConsolidatedInput{WorkoutCategory: "recovery"}); err == nil {
+		t.Error("expected an error for an unknown workout category")
+	}
+}
+
+func TestCountActivitiesByPeriodSQLite(t *testing.T) {
+	t.Parallel()
+
+	// Runs over two weeks in March, two of them tempo, stored through the driver
+	queries := newSQLiteQueries(t)
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	createSQLiteActivity(t, queries, 1, "Run", start, 10000)
+	createSQLiteActivity(t, queries, 2, "Run", start.AddDate(0, 0, 2), 8000)
+	createSQLiteActivity(t, queries, 3, "Run", start.AddDate(0, 0, 9), 12000)
+	for id, category := range map[int64]string{1: "easy", 2: "tempo", 3: "tempo"} {
+		err := queries.UpdateWorkoutClassification(context.Background(), db.UpdateWorkoutClassificationParams{
+			WorkoutCategory:   sql.NullString{String: category, Valid: true},
+			WorkoutConfidence: sql.NullFloat64{Float64: 0.8, Valid: true},
+			ID:                id,
+		})
+		if err != nil {
+			t.Fatalf("failed to classify activity %d: %v", id, err)
+		}
+	}
+
+	srv := New(queries)
+	tests := []struct {
+		groupBy string
+		want    []PeriodSummary
+	}{
+		{"week", []PeriodSummary{
+			{Period: sqliteWeek(start.AddDate(0, 0, 7)), Total: 1, ByType: map[string]int64{"Run": 1}},
+			{Period: sqliteWeek(start), Total: 1, ByType: map[string]int64{"Run": 1}},
+		}},
+		{"month", []PeriodSummary{{Period: "2026-03", Total: 2, ByType: map[string]int64{"Run": 2}}}},
+	}
+	for _, tt := range tests {
+		_, output, err := srv.countActivitiesConsolidated(context.Background(), nil, CountActivitiesConsolidatedInput{
+			StartDate: "2026-03-01", EndDate: "2026-03-31", WorkoutCategory: "tempo", GroupBy: tt.groupBy,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.groupBy, err)
+		}
+		if len(output.ByPeriod) != len(tt.want) {
+			t.Fatalf("%s: expected %+v, got %+v", tt.groupBy, tt.want, output.ByPeriod)
+		}
+		for i, p := range output.ByPeriod {
+			if p.Period != tt.want[i].Period || p.Total != tt.want[i].Total || !maps.Equal(p.ByType, tt.want[i].ByType) {
+				t.Errorf("%s: expected %+v, got %+v", tt.groupBy, tt.want[i], p)
+			}
+		}
+	}
+}
+
 func TestServerNew(t *testing.T) {
 	t.Parallel()
 
@@ -533,9 +846,9 @@ func TestFormatPace(t *testing.T) {
 		mps      float64
 		expected string
 	}{
-		{2.78, "5:59/km"},  // ~6 min/km pace
-		{3.33, "5:00/km"},  // 5 min/km pace
-		{4.0, "4:10/km"},   // 4 m/s = 4:10/km pace
+		{2.78, "5:59/km"}, // ~6 min/km pace
+		{3.33, "5:00/km"}, // 5 min/km pace
+		{4.0, "4:10/km"},  // 4 m/s = 4:10/km pace
 		{0, ""},
 		{-1, ""},
 	}
@@ -578,3 +891,605 @@ func TestInsightGenerator(t *testing.T) {
 		t.Error("expected at least one zone insight")
 	}
 }
+
+func (m *MockQuerier) ListThresholdHistory(ctx context.Context) ([]db.ThresholdHistory, error) {
+	return m.thresholdHistory, nil
+}
+
+func (m *MockQuerier) CreateRace(ctx context.Context, arg db.CreateRaceParams) (db.Race, error) {
+	r := db.Race{
+		ID:           int64(len(m.races) + 1),
+		Name:         arg.Name,
+		RaceDate:     arg.RaceDate,
+		Distance:     arg.Distance,
+		ActivityType: arg.ActivityType,
+		Priority:     arg.Priority,
+		GoalTime:     arg.GoalTime,
+		Notes:        arg.Notes,
+	}
+	m.races = append(m.races, r)
+	return r, nil
+}
+
+func (m *MockQuerier) GetRace(ctx context.Context, id int64) (db.Race, error) {
+	for _, r := range m.races {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return db.Race{}, sql.ErrNoRows
+}
+
+func (m *MockQuerier) ListRaces(ctx context.Context) ([]db.Race, error) {
+	return m.races, nil
+}
+
+func (m *MockQuerier) ListUpcomingRaces(ctx context.Context, raceDate time.Time) ([]db.Race, error) {
+	var out []db.Race
+	for _, r := range m.races {
+		if !r.RaceDate.Before(raceDate) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (m *MockQuerier) DeleteRace(ctx context.Context, id int64) (int64, error) {
+	for i, r := range m.races {
+		if r.ID == id {
+			m.races = append(m.races[:i], m.races[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (m *MockQuerier) ListActivityFlags(ctx context.Context, arg db.ListActivityFlagsParams) ([]db.ListActivityFlagsRow, error) {
+	var out []db.ListActivityFlagsRow
+	for _, f := range m.activityFlags {
+		if (arg.Column1 != nil && f.Severity != arg.Severity) ||
+			(arg.Column3 != nil && f.Code != arg.Code) ||
+			(arg.Column5 != nil && f.Type != arg.Type) {
+			continue
+		}
+		if int64(len(out)) == arg.Limit {
+			break
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+func (m *MockQuerier) CountActivityFlagsByCode(ctx context.Context) ([]db.CountActivityFlagsByCodeRow, error) {
+	var out []db.CountActivityFlagsByCodeRow
+	for _, f := range m.activityFlags {
+		i := slices.IndexFunc(out, func(c db.CountActivityFlagsByCodeRow) bool { return c.Code == f.Code && c.Severity == f.Severity })
+		if i < 0 {
+			out = append(out, db.CountActivityFlagsByCodeRow{Code: f.Code, Severity: f.Severity})
+			i = len(out) - 1
+		}
+		out[i].Count++
+	}
+	return out, nil
+}
+
+func (m *MockQuerier) CountActivitiesQualityChecked(ctx context.Context) (int64, error) {
+	return m.qualityChecked, nil
+}
+
+func (m *MockQuerier) CountExcludedActivities(ctx context.Context) (int64, error) {
+	excluded := make(map[int64]bool)
+	for _, f := range m.activityFlags {
+		if f.Severity == "error" {
+			excluded[f.ActivityID] = true
+		}
+	}
+	return int64(len(excluded)), nil
+}
+
+func (m *MockQuerier) GetActivityOverride(ctx context.Context, activityID int64) (db.ActivityOverride, error) {
+	if o, ok := m.overrides[activityID]; ok {
+		return o, nil
+	}
+	return db.ActivityOverride{}, sql.ErrNoRows
+}
+
+func (m *MockQuerier) UpsertActivityCorrection(ctx context.Context, arg db.UpsertActivityCorrectionParams) error {
+	a, err := m.GetActivity(ctx, arg.ID)
+	if err != nil {
+		return nil
+	}
+	if m.overrides == nil {
+		m.overrides = make(map[int64]db.ActivityOverride)
+	}
+	o, ok := m.overrides[arg.ID]
+	if !ok || (!o.Name.Valid && !o.Type.Valid && !o.Distance.Valid) {
+		o.ActivityID = arg.ID
+		o.OriginalName = sql.NullString{String: a.Name, Valid: true}
+		o.OriginalType, o.OriginalSportType = a.Type, a.SportType
+		o.OriginalDistance, o.OriginalAverageSpeed = a.Distance, a.AverageSpeed
+	}
+	if arg.Name.Valid {
+		o.Name = arg.Name
+	}
+	if arg.Type.Valid {
+		o.Type = arg.Type
+	}
+	if arg.Distance.Valid {
+		o.Distance = arg.Distance
+	}
+	m.overrides[arg.ID] = o
+	return nil
+}
+
+func (m *MockQuerier) ApplyActivityOverride(ctx context.Context, id int64) error {
+	o, ok := m.overrides[id]
+	if !ok {
+		return nil
+	}
+	for i, a := range m.activities {
+		if a.ID != id {
+			continue
+		}
+		if o.Name.Valid {
+			a.Name = o.Name.String
+		}
+		if o.Type.Valid {
+			a.Type, a.SportType = o.Type, o.Type
+		}
+		if o.Distance.Valid {
+			a.Distance = o.Distance
+			if a.MovingTime.Int64 > 0 {
+				a.AverageSpeed = sql.NullFloat64{Float64: o.Distance.Float64 / float64(a.MovingTime.Int64), Valid: true}
+			}
+		}
+		m.activities[i] = a
+	}
+	return nil
+}
+
+func (m *MockQuerier) ResetActivityCorrections(ctx context.Context, id int64) error {
+	o, ok := m.overrides[id]
+	if !ok {
+		return nil
+	}
+	for i, a := range m.activities {
+		if a.ID != id {
+			continue
+		}
+		if o.Name.Valid {
+			a.Name = o.OriginalName.String
+		}
+		if o.Type.Valid {
+			a.Type, a.SportType = o.OriginalType, o.OriginalSportType
+		}
+		if o.Distance.Valid {
+			a.Distance, a.AverageSpeed = o.OriginalDistance, o.OriginalAverageSpeed
+		}
+		m.activities[i] = a
+	}
+	return nil
+}
+
+func (m *MockQuerier) ClearActivityCorrections(ctx context.Context, activityID int64) error {
+	if o, ok := m.overrides[activityID]; ok {
+		m.overrides[activityID] = db.ActivityOverride{ActivityID: activityID, Counted: o.Counted, Reason: o.Reason}
+	}
+	return nil
+}
+
+func (m *MockQuerier) SetActivityCounted(ctx context.Context, arg db.SetActivityCountedParams) error {
+	if m.overrides == nil {
+		m.overrides = make(map[int64]db.ActivityOverride)
+	}
+	o := m.overrides[arg.ActivityID]
+	o.ActivityID, o.Counted, o.Reason = arg.ActivityID, arg.Counted, arg.Reason
+	m.overrides[arg.ActivityID] = o
+	return nil
+}
+
+func (m *MockQuerier) DeleteEmptyActivityOverride(ctx context.Context, activityID int64) error {
+	if o, ok := m.overrides[activityID]; ok && !o.Name.Valid && !o.Type.Valid && !o.Distance.Valid && !o.Counted.Valid {
+		delete(m.overrides, activityID)
+	}
+	return nil
+}
+
+func (m *MockQuerier) SaveActivityCorrection(ctx context.Context, arg db.UpsertActivityCorrectionParams) error {
+	if err := m.UpsertActivityCorrection(ctx, arg); err != nil {
+		return err
+	}
+	return m.ApplyActivityOverride(ctx, arg.ID)
+}
+
+func (m *MockQuerier) ResetActivityOverride(ctx context.Context, activityID int64) error {
+	if err := m.ResetActivityCorrections(ctx, activityID); err != nil {
+		return err
+	}
+	if err := m.ClearActivityCorrections(ctx, activityID); err != nil {
+		return err
+	}
+	return m.DeleteEmptyActivityOverride(ctx, activityID)
+}
+
+func (m *MockQuerier) SetActivityExclusion(ctx context.Context, arg db.SetActivityCountedParams) error {
+	if err := m.SetActivityCounted(ctx, arg); err != nil {
+		return err
+	}
+	return m.DeleteEmptyActivityOverride(ctx, arg.ActivityID)
+}
+
+func (m *MockQuerier) IsActivityExcluded(ctx context.Context, activityID int64) (int64, error) {
+	if o, ok := m.overrides[activityID]; ok && o.Counted.Valid {
+		if o.Counted.Int64 == 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	for _, f := range m.activityFlags {
+		if f.ActivityID == activityID && f.Severity == "error" {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (m *MockQuerier) CreateActivity(ctx context.Context, arg db.CreateActivityParams) error {
+	a := db.Activity{
+		ID:           arg.ID,
+		Name:         arg.Name,
+		Distance:     arg.Distance,
+		MovingTime:   arg.MovingTime,
+		Type:         arg.Type,
+		SportType:    arg.SportType,
+		StartDate:    arg.StartDate,
+		AverageSpeed: arg.AverageSpeed,
+	}
+	i := slices.IndexFunc(m.activities, func(x db.Activity) bool { return x.ID == arg.ID })
+	if i < 0 {
+		m.activities = append(m.activities, a)
+	} else {
+		m.activities[i] = a
+	}
+	return m.ApplyActivityOverride(ctx, arg.ID)
+}
+
+// matchesWorkoutFilter applies the flexible type/date/workout category filter
+func matchesWorkoutFilter(a db.Activity, activityType sql.NullString, start, end sql.NullTime, category sql.NullString) bool {
+	if activityType.Valid && a.Type != activityType {
+		return false
+	}
+	if start.Valid && a.StartDate.Time.Before(start.Time) {
+		return false
+	}
+	if end.Valid && a.StartDate.Time.After(end.Time) {
+		return false
+	}
+	return !category.Valid || a.WorkoutCategory == category
+}
+
+func (m *MockQuerier) CountActivitiesFiltered(ctx context.Context, arg db.CountActivitiesFilteredParams) (int64, error) {
+	var count int64
+	for _, a := range m.activities {
+		if matchesWorkoutFilter(a, arg.Type, arg.StartDate, arg.StartDate_2, arg.WorkoutCategory) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockQuerier) GetTrainingSummaryFiltered(ctx context.Context, arg db.GetTrainingSummaryFilteredParams) (db.GetTrainingSummaryFilteredRow, error) {
+	var row db.GetTrainingSummaryFilteredRow
+	var distance float64
+	var movingTime int64
+	for _, a := range m.activities {
+		if matchesWorkoutFilter(a, arg.Type, arg.StartDate, arg.StartDate_2, arg.WorkoutCategory) {
+			row.ActivityCount++
+			distance += a.Distance.Float64
+			movingTime += a.MovingTime.Int64
+		}
+	}
+	row.TotalDistance = distance
+	row.TotalMovingTime = movingTime
+	return row, nil
+}
+
+func (m *MockQuerier) GetWorkoutCategoryCounts(ctx context.Context, arg db.GetWorkoutCategoryCountsParams) ([]db.GetWorkoutCategoryCountsRow, error) {
+	var rows []db.GetWorkoutCategoryCountsRow
+	for _, a := range m.activities {
+		if !a.WorkoutCategory.Valid || !matchesWorkoutFilter(a, arg.Type, arg.StartDate, arg.StartDate_2, arg.WorkoutCategory) {
+			continue
+		}
+		rows = append(rows, db.GetWorkoutCategoryCountsRow{Type: a.Type, WorkoutCategory: a.WorkoutCategory, Count: 1})
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) UpsertActivitySocialCounts(ctx context.Context, arg db.UpsertActivitySocialCountsParams) error {
+	if m.social == nil {
+		m.social = make(map[int64]db.UpsertActivitySocialCountsParams)
+	}
+	m.social[arg.ActivityID] = arg
+	return nil
+}
+
+// socialActivities returns the activities in range with stored counts
+func (m *MockQuerier) socialActivities(start, end sql.NullTime) []db.Activity {
+	var activities []db.Activity
+	for _, a := range m.activities {
+		if _, ok := m.social[a.ID]; ok && matchesWorkoutFilter(a, sql.NullString{}, start, end, sql.NullString{}) {
+			activities = append(activities, a)
+		}
+	}
+	return activities
+}
+
+// socialGroups totals counts by key, in key order
+func (m *MockQuerier) socialGroups(start, end sql.NullTime, key func(db.Activity) string) ([]string, map[string][3]int64) {
+	totals := make(map[string][3]int64)
+	for _, a := range m.socialActivities(start, end) {
+		s := m.social[a.ID]
+		k := key(a)
+		t := totals[k]
+		totals[k] = [3]int64{t[0] + 1, t[1] + s.KudosCount, t[2] + s.CommentCount}
+	}
+	keys := make([]string, 0, len(totals))
+	for k := range totals {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys, totals
+}
+
+func (m *MockQuerier) GetSocialSummary(ctx context.Context, arg db.GetSocialSummaryParams) (db.GetSocialSummaryRow, error) {
+	var row db.GetSocialSummaryRow
+	var kudos, comments, withKudos, group int64
+	for _, a := range m.socialActivities(arg.StartDate, arg.StartDate_2) {
+		s := m.social[a.ID]
+		row.ActivityCount++
+		kudos += s.KudosCount
+		comments += s.CommentCount
+		if s.KudosCount > 0 {
+			withKudos++
+		}
+		if s.AthleteCount > 1 {
+			group++
+		}
+	}
+	row.TotalKudos, row.TotalComments, row.WithKudos, row.GroupActivities = kudos, comments, withKudos, group
+	return row, nil
+}
+
+func (m *MockQuerier) GetSocialByMonth(ctx context.Context, arg db.GetSocialByMonthParams) ([]db.GetSocialByMonthRow, error) {
+	keys, totals := m.socialGroups(arg.StartDate, arg.StartDate_2, func(a db.Activity) string { return a.StartDateLocal.Time.Format("2006-01") })
+	rows := make([]db.GetSocialByMonthRow, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, db.GetSocialByMonthRow{Month: k, ActivityCount: totals[k][0], TotalKudos: totals[k][1], TotalComments: totals[k][2]})
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetSocialByType(ctx context.Context, arg db.GetSocialByTypeParams) ([]db.GetSocialByTypeRow, error) {
+	keys, totals := m.socialGroups(arg.StartDate, arg.StartDate_2, func(a db.Activity) string { return a.Type.String })
+	rows := make([]db.GetSocialByTypeRow, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, db.GetSocialByTypeRow{Type: sql.NullString{String: k, Valid: k != ""}, ActivityCount: totals[k][0], TotalKudos: totals[k][1], TotalComments: totals[k][2]})
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetSocialByHour(ctx context.Context, arg db.GetSocialByHourParams) ([]db.GetSocialByHourRow, error) {
+	keys, totals := m.socialGroups(arg.StartDate, arg.StartDate_2, func(a db.Activity) string { return a.StartDateLocal.Time.Format("15") })
+	rows := make([]db.GetSocialByHourRow, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, db.GetSocialByHourRow{Hour: k, ActivityCount: totals[k][0], TotalKudos: totals[k][1], TotalComments: totals[k][2]})
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetSocialByWeekday(ctx context.Context, arg db.GetSocialByWeekdayParams) ([]db.GetSocialByWeekdayRow, error) {
+	keys, totals := m.socialGroups(arg.StartDate, arg.StartDate_2, func(a db.Activity) string {
+		return strconv.Itoa(int(a.StartDateLocal.Time.Weekday()))
+	})
+	rows := make([]db.GetSocialByWeekdayRow, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, db.GetSocialByWeekdayRow{Weekday: k, ActivityCount: totals[k][0], TotalKudos: totals[k][1], TotalComments: totals[k][2]})
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetMostKudoedActivities(ctx context.Context, arg db.GetMostKudoedActivitiesParams) ([]db.GetMostKudoedActivitiesRow, error) {
+	var rows []db.GetMostKudoedActivitiesRow
+	for _, a := range m.socialActivities(arg.StartDate, arg.StartDate_2) {
+		s := m.social[a.ID]
+		if s.KudosCount == 0 && s.CommentCount == 0 {
+			continue
+		}
+		rows = append(rows, db.GetMostKudoedActivitiesRow{
+			ID: a.ID, Name: a.Name, Type: a.Type, StartDateLocal: a.StartDateLocal, Distance: a.Distance,
+			KudosCount: s.KudosCount, CommentCount: s.CommentCount,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b db.GetMostKudoedActivitiesRow) int {
+		return int(b.KudosCount - a.KudosCount)
+	})
+	if int64(len(rows)) > arg.Limit {
+		rows = rows[:arg.Limit]
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetTopSupporters(ctx context.Context, arg db.GetTopSupportersParams) ([]db.GetTopSupportersRow, error) {
+	return m.supporters, nil
+}
+
+func (m *MockQuerier) CountSocialListsSynced(ctx context.Context) (int64, error) {
+	return m.socialListsSynced, nil
+}
+
+func (m *MockQuerier) ListClubs(ctx context.Context) ([]db.Club, error) {
+	return m.clubs, nil
+}
+
+func (m *MockQuerier) GetClub(ctx context.Context, id int64) (db.Club, error) {
+	for _, c := range m.clubs {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return db.Club{}, sql.ErrNoRows
+}
+
+func (m *MockQuerier) ListClubActivityCounts(ctx context.Context, firstSeenAt time.Time) ([]db.ListClubActivityCountsRow, error) {
+	var rows []db.ListClubActivityCountsRow
+	for _, c := range m.clubs {
+		row := db.ListClubActivityCountsRow{ClubID: c.ID}
+		var recent int64
+		for _, a := range m.clubActivities {
+			if a.ClubID != c.ID {
+				continue
+			}
+			row.ActivityCount++
+			if a.Backfill == 0 && !a.FirstSeenAt.Before(firstSeenAt) {
+				recent++
+			}
+		}
+		row.RecentCount = recent
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// clubActivitiesSince returns a club's activities seen on or after since
+func (m *MockQuerier) clubActivitiesSince(clubID int64, since time.Time) []db.ClubActivity {
+	var activities []db.ClubActivity
+	for _, a := range m.clubActivities {
+		if a.ClubID == clubID && !a.FirstSeenAt.Before(since) {
+			activities = append(activities, a)
+		}
+	}
+	return activities
+}
+
+func (m *MockQuerier) GetClubWeeklyTotals(ctx context.Context, arg db.GetClubWeeklyTotalsParams) ([]db.GetClubWeeklyTotalsRow, error) {
+	var rows []db.GetClubWeeklyTotalsRow
+	for _, a := range m.clubActivitiesSince(arg.ClubID, arg.FirstSeenAt) {
+		if a.Backfill != 0 {
+			continue
+		}
+		day := a.FirstSeenAt.Truncate(24 * time.Hour)
+		week := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)).Format("2006-01-02")
+		i := slices.IndexFunc(rows, func(r db.GetClubWeeklyTotalsRow) bool { return r.WeekStart == week })
+		if i < 0 {
+			rows = append(rows, db.GetClubWeeklyTotalsRow{WeekStart: week, TotalDistance: 0.0, TotalMovingTime: int64(0), TotalElevation: 0.0})
+			i = len(rows) - 1
+		}
+		rows[i].ActivityCount++
+		rows[i].Athletes = 1
+		rows[i].TotalDistance = rows[i].TotalDistance.(float64) + a.Distance
+		rows[i].TotalMovingTime = rows[i].TotalMovingTime.(int64) + a.MovingTime
+		rows[i].TotalElevation = rows[i].TotalElevation.(float64) + a.TotalElevationGain
+	}
+	slices.SortFunc(rows, func(a, b db.GetClubWeeklyTotalsRow) int {
+		return strings.Compare(a.WeekStart.(string), b.WeekStart.(string))
+	})
+	return rows, nil
+}
+
+func (m *MockQuerier) GetClubLeaderboard(ctx context.Context, arg db.GetClubLeaderboardParams) ([]db.GetClubLeaderboardRow, error) {
+	var rows []db.GetClubLeaderboardRow
+	for _, a := range m.clubActivitiesSince(arg.ClubID, arg.FirstSeenAt) {
+		i := slices.IndexFunc(rows, func(r db.GetClubLeaderboardRow) bool { return r.AthleteName == a.AthleteName })
+		if i < 0 {
+			rows = append(rows, db.GetClubLeaderboardRow{AthleteName: a.AthleteName, TotalDistance: 0.0, TotalMovingTime: int64(0), TotalElevation: 0.0})
+			i = len(rows) - 1
+		}
+		rows[i].ActivityCount++
+		rows[i].TotalDistance = rows[i].TotalDistance.(float64) + a.Distance
+		rows[i].TotalMovingTime = rows[i].TotalMovingTime.(int64) + a.MovingTime
+		rows[i].TotalElevation = rows[i].TotalElevation.(float64) + a.TotalElevationGain
+	}
+	slices.SortStableFunc(rows, func(a, b db.GetClubLeaderboardRow) int {
+		return cmp.Compare(b.TotalDistance.(float64), a.TotalDistance.(float64))
+	})
+	if arg.Limit >= 0 && int64(len(rows)) > arg.Limit {
+		rows = rows[:arg.Limit]
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetClubTypeTotals(ctx context.Context, arg db.GetClubTypeTotalsParams) ([]db.GetClubTypeTotalsRow, error) {
+	var rows []db.GetClubTypeTotalsRow
+	for _, a := range m.clubActivitiesSince(arg.ClubID, arg.FirstSeenAt) {
+		i := slices.IndexFunc(rows, func(r db.GetClubTypeTotalsRow) bool { return r.Type == a.Type })
+		if i < 0 {
+			rows = append(rows, db.GetClubTypeTotalsRow{Type: a.Type, TotalDistance: 0.0, TotalMovingTime: int64(0)})
+			i = len(rows) - 1
+		}
+		rows[i].ActivityCount++
+		rows[i].TotalDistance = rows[i].TotalDistance.(float64) + a.Distance
+		rows[i].TotalMovingTime = rows[i].TotalMovingTime.(int64) + a.MovingTime
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) CountClubBackfillActivities(ctx context.Context, arg db.CountClubBackfillActivitiesParams) (int64, error) {
+	var count int64
+	for _, a := range m.clubActivitiesSince(arg.ClubID, arg.FirstSeenAt) {
+		if a.Backfill != 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockQuerier) ListSavedRoutes(ctx context.Context) ([]db.ListSavedRoutesRow, error) {
+	rows := make([]db.ListSavedRoutesRow, 0, len(m.savedRoutes))
+	for _, r := range m.savedRoutes {
+		rows = append(rows, db.ListSavedRoutesRow{
+			ID: r.ID, Name: r.Name, Description: r.Description, Type: r.Type, SubType: r.SubType,
+			Distance: r.Distance, ElevationGain: r.ElevationGain, EstimatedMovingTime: r.EstimatedMovingTime,
+			Polyline: r.Polyline, Private: r.Private, Starred: r.Starred, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+			HasGpx: int64(0),
+		})
+		if r.Gpx.Valid {
+			rows[len(rows)-1].HasGpx = int64(1)
+		}
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) GetSavedRouteGPX(ctx context.Context, id int64) (db.GetSavedRouteGPXRow, error) {
+	for _, r := range m.savedRoutes {
+		if r.ID == id {
+			return db.GetSavedRouteGPXRow{ID: r.ID, Name: r.Name, Gpx: r.Gpx}, nil
+		}
+	}
+	return db.GetSavedRouteGPXRow{}, sql.ErrNoRows
+}
+
+func (m *MockQuerier) ListActivitiesForRouteMatch(ctx context.Context, arg db.ListActivitiesForRouteMatchParams) ([]db.Activity, error) {
+	var rows []db.Activity
+	for _, a := range m.activities {
+		if a.Type == arg.Type && a.Distance.Float64 >= arg.Distance.Float64 && a.Distance.Float64 <= arg.Distance_2.Float64 && a.SummaryPolyline.String != "" {
+			rows = append(rows, a)
+		}
+	}
+	return rows, nil
+}
+
+func (m *MockQuerier) ListStarredSegments(ctx context.Context) ([]db.StarredSegment, error) {
+	return m.starredSegments, nil
+}
+
+func (m *MockQuerier) ListSyncIntegrity(ctx context.Context) ([]db.SyncIntegrity, error) {
+	return m.syncIntegrity, nil
+}
+
+func (m *MockQuerier) ListSyncFailures(ctx context.Context) ([]db.SyncFailure, error) {
+	return m.syncFailures, nil
+}
+
+func (m *MockQuerier) ListSyncRepairs(ctx context.Context, limit int64) ([]db.SyncRepair, error) {
+	return m.syncRepairs[:min(int(limit), len(m.syncRepairs))], nil
+}
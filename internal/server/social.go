@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SocialQuerier defines the interface for kudos and comment queries
+type SocialQuerier interface {
+	GetSocialSummary(ctx context.Context, arg db.GetSocialSummaryParams) (db.GetSocialSummaryRow, error)
+	GetSocialByMonth(ctx context.Context, arg db.GetSocialByMonthParams) ([]db.GetSocialByMonthRow, error)
+	GetSocialByType(ctx context.Context, arg db.GetSocialByTypeParams) ([]db.GetSocialByTypeRow, error)
+	GetSocialByHour(ctx context.Context, arg db.GetSocialByHourParams) ([]db.GetSocialByHourRow, error)
+	GetSocialByWeekday(ctx context.Context, arg db.GetSocialByWeekdayParams) ([]db.GetSocialByWeekdayRow, error)
+	GetMostKudoedActivities(ctx context.Context, arg db.GetMostKudoedActivitiesParams) ([]db.GetMostKudoedActivitiesRow, error)
+	GetTopSupporters(ctx context.Context, arg db.GetTopSupportersParams) ([]db.GetTopSupportersRow, error)
+	CountSocialListsSynced(ctx context.Context) (int64, error)
+}
+
+// Social stats limits
+const (
+	defaultSocialLimit = 5
+	maxSocialLimit     = 25
+)
+
+// Input types
+
+// GetSocialStatsInput - input for kudos and comment statistics
+type GetSocialStatsInput struct {
+	StartDate string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD (e.g., 2024-01-15). Default: 12 months ago."`
+	EndDate   string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD (e.g., 2024-12-31). Default: today."`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Number of most-kudoed activities and top supporters to return. Default: 5, Maximum: 25."`
+}
+
+// Output types
+
+type GetSocialStatsOutput struct {
+	Filter           string            `json:"filter"`
+	Summary          SocialSummary     `json:"summary"`
+	Monthly          []SocialPeriod    `json:"monthly"`
+	MostKudoed       []KudoedActivity  `json:"most_kudoed"`
+	ByType           []SocialBreakdown `json:"by_type"`
+	ByTimeOfDay      []SocialBreakdown `json:"by_time_of_day"`
+	ByWeekday        []SocialBreakdown `json:"by_weekday"`
+	TopSupporters    []SocialSupporter `json:"top_supporters,omitempty"`
+	Insights         []Insight         `json:"insights,omitempty"`
+	SuggestedActions []SuggestedAction `json:"suggested_actions,omitempty"`
+}
+
+// SocialSummary totals kudos and comments over the period
+type SocialSummary struct {
+	Activities          int64   `json:"activities"`
+	TotalKudos          int64   `json:"total_kudos"`
+	TotalComments       int64   `json:"total_comments"`
+	AvgKudos            float64 `json:"avg_kudos"`
+	AvgComments         float64 `json:"avg_comments"`
+	ActivitiesWithKudos int64   `json:"activities_with_kudos"`
+	GroupActivities     int64   `json:"group_activities"`
+}
+
+// SocialPeriod is kudos and comments for one month
+type SocialPeriod struct {
+	Period     string  `json:"period"`
+	Activities int64   `json:"activities"`
+	Kudos      int64   `json:"kudos"`
+	Comments   int64   `json:"comments"`
+	AvgKudos   float64 `json:"avg_kudos"`
+}
+
+// SocialBreakdown is kudos and comments for a group of activities, such as
+// one activity type or time of day
+type SocialBreakdown struct {
+	Group       string  `json:"group"`
+	Activities  int64   `json:"activities"`
+	Kudos       int64   `json:"kudos"`
+	Comments    int64   `json:"comments"`
+	AvgKudos    float64 `json:"avg_kudos"`
+	AvgComments float64 `json:"avg_comments"`
+}
+
+// KudoedActivity is an activity with its kudos and comment counts
+type KudoedActivity struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Date     string  `json:"date"`
+	Distance float64 `json:"distance_km"`
+	Kudos    int64   `json:"kudos"`
+	Comments int64   `json:"comments"`
+}
+
+// SocialSupporter is an athlete who gave kudos or comments
+type SocialSupporter struct {
+	Name     string `json:"name"`
+	Kudos    int64  `json:"kudos"`
+	Comments int64  `json:"comments"`
+}
+
+// registerSocialTools registers the kudos and comments tool
+func (s *Server) registerSocialTools() {
+	logging.Debug("Registering tool", "name", "get_social_stats")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "get_social_stats",
+		Description: `Kudos and comment statistics: engagement trends, most-kudoed activities, and which activity types, times of day and weekdays get the most interaction.
+
+Use when:
+- User asks "Which of my activities got the most kudos?"
+- User wants to know whether their engagement is going up or down
+- User asks what kind of activity or what time of day gets the most kudos
+- User asks who gives them the most kudos
+
+Parameters:
+- start_date (string): Start date, YYYY-MM-DD. Default: 12 months ago.
+- end_date (string): End date, YYYY-MM-DD. Default: today.
+- limit (int): Most-kudoed activities and top supporters to return. Default: 5, Max: 25
+
+Returns: Totals and averages, a monthly trend, the most-kudoed activities, and breakdowns by type, time of day and weekday. Counts come from the regular sync. Top supporters need the kudoer and comment lists, synced when the server runs with --sync-social-lists.
+
+Example: {"start_date": "2024-01-01", "limit": 10}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:          "Get Social Stats",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  ptr(false),
+		},
+	}, s.getSocialStats)
+}
+
+// getSocialStats summarizes kudos and comments over a period
+func (s *Server) getSocialStats(ctx context.Context, req *mcp.CallToolRequest, input GetSocialStatsInput) (*mcp.CallToolResult, GetSocialStatsOutput, error) {
+	logging.Info("MCP tool call", "tool", "get_social_stats", "start_date", input.StartDate, "end_date", input.EndDate)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "get_social_stats", "input", logging.ToJSON(input))
+	}
+
+	start, end, err := socialDateRange(input.StartDate, input.EndDate, time.Now())
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewInvalidInputErrorWithDetails(
+			"Invalid date format", "Use YYYY-MM-DD format (e.g., 2024-01-15)")
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultSocialLimit
+	}
+	if limit > maxSocialLimit {
+		limit = maxSocialLimit
+	}
+
+	queries := s.queries.(SocialQuerier)
+
+	summaryRow, err := queries.GetSocialSummary(ctx, db.GetSocialSummaryParams{StartDate: start, StartDate_2: end})
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+	}
+	monthRows, err := queries.GetSocialByMonth(ctx, db.GetSocialByMonthParams{StartDate: start, StartDate_2: end})
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+	}
+	typeRows, err := queries.GetSocialByType(ctx, db.GetSocialByTypeParams{StartDate: start, StartDate_2: end})
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+	}
+	hourRows, err := queries.GetSocialByHour(ctx, db.GetSocialByHourParams{StartDate: start, StartDate_2: end})
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+	}
+	weekdayRows, err := queries.GetSocialByWeekday(ctx, db.GetSocialByWeekdayParams{StartDate: start, StartDate_2: end})
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+	}
+	kudoedRows, err := queries.GetMostKudoedActivities(ctx, db.GetMostKudoedActivitiesParams{StartDate: start, StartDate_2: end, Limit: int64(limit)})
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+	}
+	listsSynced, err := queries.CountSocialListsSynced(ctx)
+	if err != nil {
+		return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+	}
+
+	output := GetSocialStatsOutput{
+		Filter:      buildFilterDesc("", start.Time.Format("2006-01-02"), end.Time.Format("2006-01-02")),
+		Summary:     convertSocialSummary(summaryRow),
+		Monthly:     make([]SocialPeriod, 0, len(monthRows)),
+		MostKudoed:  make([]KudoedActivity, 0, len(kudoedRows)),
+		ByType:      make([]SocialBreakdown, 0, len(typeRows)),
+		ByTimeOfDay: socialByTimeOfDay(hourRows),
+		ByWeekday:   make([]SocialBreakdown, 0, len(weekdayRows)),
+	}
+
+	for _, r := range monthRows {
+		kudos := toInt64(r.TotalKudos)
+		output.Monthly = append(output.Monthly, SocialPeriod{
+			Period:     fmt.Sprintf("%v", r.Month),
+			Activities: r.ActivityCount,
+			Kudos:      kudos,
+			Comments:   toInt64(r.TotalComments),
+			AvgKudos:   socialAverage(kudos, r.ActivityCount),
+		})
+	}
+	for _, r := range typeRows {
+		group := r.Type.String
+		if group == "" {
+			group = "Unknown"
+		}
+		output.ByType = append(output.ByType, socialBreakdown(group, r.ActivityCount, r.TotalKudos, r.TotalComments))
+	}
+	for _, r := range weekdayRows {
+		output.ByWeekday = append(output.ByWeekday, socialBreakdown(weekdayName(r.Weekday), r.ActivityCount, r.TotalKudos, r.TotalComments))
+	}
+	for _, r := range kudoedRows {
+		activity := KudoedActivity{
+			ID:       r.ID,
+			Name:     r.Name,
+			Type:     r.Type.String,
+			Distance: math.Round(r.Distance.Float64/10) / 100,
+			Kudos:    r.KudosCount,
+			Comments: r.CommentCount,
+		}
+		if r.StartDateLocal.Valid {
+			activity.Date = r.StartDateLocal.Time.Format("2006-01-02")
+		}
+		output.MostKudoed = append(output.MostKudoed, activity)
+	}
+
+	if listsSynced > 0 {
+		supporterRows, err := queries.GetTopSupporters(ctx, db.GetTopSupportersParams{
+			StartDate: start, StartDate_2: end, StartDate_3: start, StartDate_4: end, Limit: int64(limit),
+		})
+		if err != nil {
+			return nil, GetSocialStatsOutput{}, NewDatabaseError(err)
+		}
+		output.TopSupporters = make([]SocialSupporter, 0, len(supporterRows))
+		for _, r := range supporterRows {
+			output.TopSupporters = append(output.TopSupporters, SocialSupporter{
+				Name:     r.AthleteName,
+				Kudos:    toInt64(r.Kudos),
+				Comments: toInt64(r.Comments),
+			})
+		}
+	}
+
+	output.Insights = socialInsights(output, listsSynced)
+	output.SuggestedActions = SuggestNextActions("social")
+
+	logging.Info("MCP tool completed", "tool", "get_social_stats", "activities", output.Summary.Activities, "kudos", output.Summary.TotalKudos)
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "get_social_stats", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// socialDateRange parses the period, defaulting to the 12 months up to now
+func socialDateRange(startDate, endDate string, now time.Time) (sql.NullTime, sql.NullTime, error) {
+	start, end, err := parseServerDateRange(startDate, endDate)
+	if err != nil {
+		return start, end, err
+	}
+	if !end.Valid {
+		end = sql.NullTime{Time: now, Valid: true}
+	}
+	if !start.Valid {
+		start = sql.NullTime{Time: end.Time.AddDate(-1, 0, 0), Valid: true}
+	}
+	return start, end, nil
+}
+
+// convertSocialSummary converts the summary row, adding the averages
+func convertSocialSummary(r db.GetSocialSummaryRow) SocialSummary {
+	kudos, comments := toInt64(r.TotalKudos), toInt64(r.TotalComments)
+	return SocialSummary{
+		Activities:          r.ActivityCount,
+		TotalKudos:          kudos,
+		TotalComments:       comments,
+		AvgKudos:            socialAverage(kudos, r.ActivityCount),
+		AvgComments:         socialAverage(comments, r.ActivityCount),
+		ActivitiesWithKudos: toInt64(r.WithKudos),
+		GroupActivities:     toInt64(r.GroupActivities),
+	}
+}
+
+// socialBreakdown builds a breakdown entry from grouped totals
+func socialBreakdown(group string, activities int64, kudos, comments interface{}) SocialBreakdown {
+	k, c := toInt64(kudos), toInt64(comments)
+	return SocialBreakdown{
+		Group:       group,
+		Activities:  activities,
+		Kudos:       k,
+		Comments:    c,
+		AvgKudos:    socialAverage(k, activities),
+		AvgComments: socialAverage(c, activities),
+	}
+}
+
+// socialAverage is total per activity, rounded to one decimal
+func socialAverage(total, activities int64) float64 {
+	if activities == 0 {
+		return 0
+	}
+	return math.Round(float64(total)/float64(activities)*10) / 10
+}
+
+// timesOfDay are the buckets start hours are grouped into, in order
+var timesOfDay = []string{"morning", "midday", "afternoon", "evening", "night"}
+
+// timeOfDay buckets a local start hour
+func timeOfDay(hour int) string {
+	switch {
+	case hour >= 5 && hour < 11:
+		return "morning"
+	case hour >= 11 && hour < 14:
+		return "midday"
+	case hour >= 14 && hour < 18:
+		return "afternoon"
+	case hour >= 18 && hour < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// socialByTimeOfDay groups the hourly rows into times of day, leaving out
+// those without activities
+func socialByTimeOfDay(rows []db.GetSocialByHourRow) []SocialBreakdown {
+	type totals struct{ activities, kudos, comments int64 }
+	byBucket := make(map[string]*totals)
+	for _, r := range rows {
+		hour, err := strconv.Atoi(fmt.Sprintf("%v", r.Hour))
+		if err != nil {
+			continue
+		}
+		bucket := timeOfDay(hour)
+		if byBucket[bucket] == nil {
+			byBucket[bucket] = &totals{}
+		}
+		byBucket[bucket].activities += r.ActivityCount
+		byBucket[bucket].kudos += toInt64(r.TotalKudos)
+		byBucket[bucket].comments += toInt64(r.TotalComments)
+	}
+
+	breakdown := make([]SocialBreakdown, 0, len(byBucket))
+	for _, bucket := range timesOfDay {
+		if t := byBucket[bucket]; t != nil {
+			breakdown = append(breakdown, socialBreakdown(bucket, t.activities, t.kudos, t.comments))
+		}
+	}
+	return breakdown
+}
+
+// weekdayName names a strftime %w weekday, 0 being Sunday
+func weekdayName(v interface{}) string {
+	day, err := strconv.Atoi(fmt.Sprintf("%v", v))
+	if err != nil || day < 0 || day > 6 {
+		return fmt.Sprintf("%v", v)
+	}
+	return time.Weekday(day).String()
+}
+
+// minSocialGroup is the fewest activities a group needs before it is called
+// out as getting the most interaction
+const minSocialGroup = 3
+
+// socialInsights points out the engagement trend and what gets the most kudos
+func socialInsights(output GetSocialStatsOutput, listsSynced int64) []Insight {
+	insights := make([]Insight, 0)
+	if output.Summary.Activities == 0 {
+		return append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No kudos or comment counts stored for this period yet; they are filled in by the next sync",
+		})
+	}
+
+	if n := len(output.Monthly); n >= 6 {
+		recent := socialPeriodAverage(output.Monthly[n-3:])
+		earlier := socialPeriodAverage(output.Monthly[n-6 : n-3])
+		if earlier > 0 {
+			change := (recent - earlier) / earlier * 100
+			switch {
+			case change >= 20:
+				insights = append(insights, Insight{Type: "trend", Message: fmt.Sprintf("Kudos per activity are up %.0f%% over the last 3 months (%.1f vs %.1f)", change, recent, earlier)})
+			case change <= -20:
+				insights = append(insights, Insight{Type: "trend", Message: fmt.Sprintf("Kudos per activity are down %.0f%% over the last 3 months (%.1f vs %.1f)", -change, recent, earlier)})
+			}
+		}
+	}
+
+	if best, ok := bestSocialGroup(output.ByType); ok && len(output.ByType) > 1 {
+		insights = append(insights, Insight{Type: "achievement", Message: fmt.Sprintf("%s activities get the most kudos, %.1f on average", best.Group, best.AvgKudos)})
+	}
+	if best, ok := bestSocialGroup(output.ByTimeOfDay); ok && len(output.ByTimeOfDay) > 1 {
+		insights = append(insights, Insight{Type: "trend", Message: fmt.Sprintf("Activities in the %s get the most kudos, %.1f on average", best.Group, best.AvgKudos)})
+	}
+	if output.Summary.GroupActivities > 0 {
+		insights = append(insights, Insight{Type: "trend", Message: fmt.Sprintf("%d of %d activities were with other athletes", output.Summary.GroupActivities, output.Summary.Activities)})
+	}
+
+	if listsSynced == 0 && output.Summary.TotalKudos+output.Summary.TotalComments > 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: "Run the server with --sync-social-lists to see who gives the most kudos and comments",
+		})
+	}
+	return insights
+}
+
+// socialPeriodAverage is the kudos per activity across months
+func socialPeriodAverage(periods []SocialPeriod) float64 {
+	var activities, kudos int64
+	for _, p := range periods {
+		activities += p.Activities
+		kudos += p.Kudos
+	}
+	if activities == 0 {
+		return 0
+	}
+	return float64(kudos) / float64(activities)
+}
+
+// bestSocialGroup finds the group with the most kudos per activity, among
+// those with enough activities to compare
+func bestSocialGroup(groups []SocialBreakdown) (SocialBreakdown, bool) {
+	var best SocialBreakdown
+	found := false
+	for _, g := range groups {
+		if g.Activities < minSocialGroup || g.AvgKudos == 0 {
+			continue
+		}
+		if !found || g.AvgKudos > best.AvgKudos {
+			best, found = g, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func socialTestQuerier() *MockQuerier {
+	m := &MockQuerier{social: make(map[int64]db.UpsertActivitySocialCountsParams)}
+	add := func(id int64, name, activityType string, start time.Time, kudos, comments, athletes int64) {
+		m.activities = append(m.activities, db.Activity{
+			ID: id, Name: name, Type: sql.NullString{String: activityType, Valid: true},
+			StartDate: sql.NullTime{Time: start, Valid: true}, StartDateLocal: sql.NullTime{Time: start, Valid: true},
+			Distance: sql.NullFloat64{Float64: 10000, Valid: true},
+		})
+		m.social[id] = db.UpsertActivitySocialCountsParams{ActivityID: id, KudosCount: kudos, CommentCount: comments, AthleteCount: athletes}
+	}
+	// Saturday morning runs with friends, quiet weekday evening rides
+	saturday := time.Date(2026, 9, 5, 8, 0, 0, 0, time.UTC)
+	add(1, "Long Run", "Run", saturday, 12, 2, 3)
+	add(2, "Long Run", "Run", saturday.AddDate(0, 0, 7), 15, 3, 4)
+	add(3, "Parkrun", "Run", saturday.AddDate(0, 0, 14), 9, 0, 1)
+	wednesday := time.Date(2026, 9, 9, 19, 0, 0, 0, time.UTC)
+	add(4, "Evening Ride", "Ride", wednesday, 2, 0, 1)
+	add(5, "Evening Ride", "Ride", wednesday.AddDate(0, 0, 7), 1, 0, 1)
+	add(6, "Evening Ride", "Ride", wednesday.AddDate(0, 0, 14), 0, 0, 1)
+	// Outside the period
+	add(7, "Old Marathon", "Run", time.Date(2024, 4, 21, 9, 0, 0, 0, time.UTC), 80, 20, 1)
+	return m
+}
+
+func TestGetSocialStats(t *testing.T) {
+	t.Parallel()
+
+	m := socialTestQuerier()
+	srv := New(m)
+	input := GetSocialStatsInput{StartDate: "2026-01-01", EndDate: "2026-10-01", Limit: 2}
+
+	_, output, err := srv.getSocialStats(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	summary := output.Summary
+	if summary.Activities != 6 || summary.TotalKudos != 39 || summary.TotalComments != 5 || summary.AvgKudos != 6.5 {
+		t.Fatalf("expected 39 kudos over 6 activities, got %+v", summary)
+	}
+	if summary.ActivitiesWithKudos != 5 || summary.GroupActivities != 2 {
+		t.Errorf("expected 5 with kudos and 2 group activities, got %+v", summary)
+	}
+	if len(output.MostKudoed) != 2 || output.MostKudoed[0].ID != 2 || output.MostKudoed[0].Distance != 10 {
+		t.Errorf("expected the two most-kudoed runs, got %+v", output.MostKudoed)
+	}
+	if len(output.ByTimeOfDay) != 2 || output.ByTimeOfDay[0].Group != "morning" || output.ByTimeOfDay[1].Group != "evening" {
+		t.Errorf("expected morning and evening groups, got %+v", output.ByTimeOfDay)
+	}
+	if len(output.ByWeekday) != 2 || output.ByWeekday[0].Group != "Wednesday" || output.ByWeekday[1].Group != "Saturday" {
+		t.Errorf("expected weekday names, got %+v", output.ByWeekday)
+	}
+	if output.TopSupporters != nil {
+		t.Errorf("expected no supporters without synced lists, got %+v", output.TopSupporters)
+	}
+
+	var messages []string
+	for _, insight := range output.Insights {
+		messages = append(messages, insight.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{"Run activities get the most kudos, 12.0", "in the morning", "2 of 6 activities", "--sync-social-lists"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected an insight containing %q, got %v", want, messages)
+		}
+	}
+
+	m.socialListsSynced = 3
+	m.supporters = []db.GetTopSupportersRow{{AthleteName: "Jane D.", Kudos: int64(3), Comments: int64(2)}}
+	_, output, err = srv.getSocialStats(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.TopSupporters) != 1 || output.TopSupporters[0].Comments != 2 {
+		t.Errorf("expected the top supporter, got %+v", output.TopSupporters)
+	}
+
+	if _, _, err := srv.getSocialStats(context.Background(), nil, GetSocialStatsInput{StartDate: "09/01/2026"}); err == nil {
+		t.Error("expected an error for a bad date")
+	}
+}
+
+func TestGetSocialStatsEmpty(t *testing.T) {
+	t.Parallel()
+
+	_, output, err := New(&MockQuerier{}).getSocialStats(context.Background(), nil, GetSocialStatsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Summary.Activities != 0 || len(output.Insights) != 1 || output.Insights[0].Type != "suggestion" {
+		t.Errorf("expected a single suggestion with no counts, got %+v", output)
+	}
+}
+
+func TestSocialDateRange(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	start, end, err := socialDateRange("", "", now)
+	if err != nil || !end.Time.Equal(now) || !start.Time.Equal(now.AddDate(-1, 0, 0)) {
+		t.Errorf("expected the last 12 months, got %v to %v (%v)", start.Time, end.Time, err)
+	}
+	start, _, err = socialDateRange("", "2025-06-30", now)
+	if err != nil || start.Time.Format("2006-01-02") != "2024-06-30" {
+		t.Errorf("expected 12 months before the end date, got %v (%v)", start.Time, err)
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	t.Parallel()
+
+	for hour, want := range map[int]string{4: "night", 5: "morning", 12: "midday", 15: "afternoon", 21: "evening", 23: "night"} {
+		if got := timeOfDay(hour); got != want {
+			t.Errorf("timeOfDay(%d) = %q, want %q", hour, got, want)
+		}
+	}
+}
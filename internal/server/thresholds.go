@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+	"github.com/joshdurbin/strava-mcp/internal/zones"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ThresholdsQuerier defines the interface for threshold history queries
+type ThresholdsQuerier interface {
+	GetActivity(ctx context.Context, id int64) (db.Activity, error)
+	ListThresholdHistory(ctx context.Context) ([]db.ThresholdHistory, error)
+}
+
+// newThresholdDays is how far back thresholds count as new when no since
+// date is given
+const newThresholdDays = 30
+
+// Input types
+
+// GetThresholdHistoryInput - input for threshold history
+type GetThresholdHistoryInput struct {
+	Metric string `json:"metric,omitempty" jsonschema:"Only this threshold. Values: lthr, ftp, threshold_pace. Default: all."`
+	Sport  string `json:"sport,omitempty" jsonschema:"Only this sport. Values: run, ride. Default: both."`
+	Since  string `json:"since,omitempty" jsonschema:"Report thresholds detected on or after this date as new. Format: YYYY-MM-DD. Default: the last 30 days."`
+}
+
+// Output types
+
+type GetThresholdHistoryOutput struct {
+	Current []CurrentThreshold `json:"current"`
+	// NewThresholds are changes detected on or after Since
+	Since         string            `json:"since"`
+	NewThresholds []ThresholdChange `json:"new_thresholds,omitempty"`
+	History       []ThresholdChange `json:"history"`
+	// SuggestedZonesFile holds --zones-file settings built from the current
+	// thresholds
+	SuggestedZonesFile map[string]any    `json:"suggested_zones_file,omitempty"`
+	Insights           []Insight         `json:"insights,omitempty"`
+	SuggestedActions   []SuggestedAction `json:"suggested_actions,omitempty"`
+}
+
+// CurrentThreshold is the latest estimate of one threshold
+type CurrentThreshold struct {
+	Metric       string  `json:"metric"`
+	Sport        string  `json:"sport"`
+	Value        float64 `json:"value"` // bpm, watts, or m/s for threshold_pace
+	Display      string  `json:"display"`
+	DetectedOn   string  `json:"detected_on"`
+	AgeDays      int     `json:"age_days"`
+	ActivityID   int64   `json:"activity_id"`
+	ActivityName string  `json:"activity_name,omitempty"`
+	// Stale is set when no effort has raised the threshold in 90 days
+	Stale bool `json:"stale,omitempty"`
+}
+
+// ThresholdChange is one detected threshold
+type ThresholdChange struct {
+	Metric   string  `json:"metric"`
+	Sport    string  `json:"sport"`
+	Value    float64 `json:"value"`
+	Display  string  `json:"display"`
+	Previous string  `json:"previous,omitempty"`
+	// ChangePct is the rise over the previous threshold; for threshold_pace
+	// it's the rise in speed
+	ChangePct  float64 `json:"change_pct,omitempty"`
+	DetectedOn string  `json:"detected_on"`
+	ActivityID int64   `json:"activity_id"`
+}
+
+// registerThresholdTools registers the threshold history tool
+func (s *Server) registerThresholdTools() {
+	logging.Debug("Registering tool", "name", "get_threshold_history")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "get_threshold_history",
+		Description: `Get current and past threshold estimates: lactate threshold heart rate (LTHR), functional threshold power (FTP) and threshold pace.
+
+Thresholds are estimated in the background from the best 20 minutes of every activity with streams: 95% of the best 20-minute heart rate (LTHR) and power (FTP, rides only), and the best 20-minute run speed extrapolated to an hour (threshold pace, road and treadmill runs only). A new threshold is recorded when an effort beats the current one by 2% or more. Thresholds only move up; one that hasn't moved in 90 days is flagged stale.
+
+Use when:
+- User asks "Has my FTP gone up?" or "What's my threshold heart rate?"
+- User asks whether their zones are up to date
+- User wants to set zones from their current fitness
+
+Parameters:
+- metric (string): lthr, ftp or threshold_pace (default all)
+- sport (string): run or ride (default both); LTHR is tracked separately for each
+- since (string): YYYY-MM-DD; thresholds detected on or after it are reported as new (default the last 30 days). Pass the date of the last check to see only what has changed since.
+
+Returns: The current estimate of each threshold with its age and source activity, every detected change with the rise over the previous value, the thresholds detected since the cutoff, and a --zones-file built from the current thresholds.
+
+Example: {} or {"metric": "ftp", "since": "2026-09-01"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Get Threshold History",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.getThresholdHistory)
+}
+
+// getThresholdHistory reports threshold estimates and those detected since
+// the cutoff
+func (s *Server) getThresholdHistory(ctx context.Context, req *mcp.CallToolRequest, input GetThresholdHistoryInput) (*mcp.CallToolResult, GetThresholdHistoryOutput, error) {
+	logging.Info("MCP tool call", "tool", "get_threshold_history", "metric", input.Metric, "sport", input.Sport, "since", input.Since)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "get_threshold_history", "input", logging.ToJSON(input))
+	}
+
+	switch input.Metric {
+	case "", thresholds.MetricLTHR, thresholds.MetricFTP, thresholds.MetricThresholdPace:
+	default:
+		return nil, GetThresholdHistoryOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("Invalid metric %q", input.Metric),
+			"Valid metrics: lthr, ftp, threshold_pace")
+	}
+	switch input.Sport {
+	case "", thresholds.SportRun, thresholds.SportRide:
+	default:
+		return nil, GetThresholdHistoryOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("Invalid sport %q", input.Sport),
+			"Valid sports: run, ride")
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -newThresholdDays).Truncate(24 * time.Hour)
+	if input.Since != "" {
+		t, err := time.Parse("2006-01-02", input.Since)
+		if err != nil {
+			return nil, GetThresholdHistoryOutput{}, NewInvalidInputErrorWithDetails(
+				fmt.Sprintf("Invalid since date %q", input.Since),
+				"Use YYYY-MM-DD, e.g. 2026-09-01")
+		}
+		since = t
+	}
+
+	queries := s.queries.(ThresholdsQuerier)
+
+	rows, err := queries.ListThresholdHistory(ctx)
+	if err != nil {
+		return nil, GetThresholdHistoryOutput{}, NewDatabaseError(err)
+	}
+
+	output := GetThresholdHistoryOutput{
+		Current: make([]CurrentThreshold, 0),
+		Since:   since.Format("2006-01-02"),
+		History: make([]ThresholdChange, 0),
+	}
+	latest := make(map[[2]string]db.ThresholdHistory)
+	for _, r := range rows {
+		if (input.Metric != "" && r.Metric != input.Metric) || (input.Sport != "" && r.Sport != input.Sport) {
+			continue
+		}
+		change := thresholdChange(r)
+		output.History = append(output.History, change)
+		if !r.DetectedOn.Before(since) {
+			output.NewThresholds = append(output.NewThresholds, change)
+		}
+		// Rows are oldest first, so the last one seen is current
+		latest[[2]string{r.Metric, r.Sport}] = r
+	}
+
+	for _, t := range thresholds.Tracked {
+		r, ok := latest[[2]string{t.Metric, t.Sport}]
+		if !ok {
+			continue
+		}
+		current := CurrentThreshold{
+			Metric:     r.Metric,
+			Sport:      r.Sport,
+			Value:      r.Value,
+			Display:    formatThreshold(r.Metric, r.Value),
+			DetectedOn: r.DetectedOn.Format("2006-01-02"),
+			AgeDays:    int(now.Sub(r.DetectedOn).Hours() / 24),
+			ActivityID: r.ActivityID,
+			Stale:      now.Sub(r.DetectedOn) > thresholds.StaleAfter,
+		}
+		if activity, err := queries.GetActivity(ctx, r.ActivityID); err == nil {
+			current.ActivityName = activity.Name
+		} else if err != sql.ErrNoRows {
+			return nil, GetThresholdHistoryOutput{}, NewDatabaseError(err)
+		}
+		output.Current = append(output.Current, current)
+	}
+
+	output.SuggestedZonesFile = suggestedZonesFile(output.Current)
+	output.Insights = thresholdInsights(output)
+	output.SuggestedActions = SuggestNextActions("thresholds")
+
+	logging.Info("MCP tool completed", "tool", "get_threshold_history", "current", len(output.Current), "history", len(output.History), "new", len(output.NewThresholds))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "get_threshold_history", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+func thresholdChange(r db.ThresholdHistory) ThresholdChange {
+	change := ThresholdChange{
+		Metric:     r.Metric,
+		Sport:      r.Sport,
+		Value:      r.Value,
+		Display:    formatThreshold(r.Metric, r.Value),
+		DetectedOn: r.DetectedOn.Format("2006-01-02"),
+		ActivityID: r.ActivityID,
+	}
+	if r.PreviousValue.Valid && r.PreviousValue.Float64 > 0 {
+		change.Previous = formatThreshold(r.Metric, r.PreviousValue.Float64)
+		change.ChangePct = math.Round((r.Value/r.PreviousValue.Float64-1)*1000) / 10
+	}
+	return change
+}
+
+// formatThreshold formats a threshold value in its metric's units
+func formatThreshold(metric string, value float64) string {
+	switch metric {
+	case thresholds.MetricLTHR:
+		return fmt.Sprintf("%.0f bpm", value)
+	case thresholds.MetricFTP:
+		return fmt.Sprintf("%.0f W", value)
+	case thresholds.MetricThresholdPace:
+		return formatPace(value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
+
+// suggestedZonesFile builds zones file settings from current thresholds. The
+// zones file holds one set of heart rate zones, so running LTHR is preferred
+// when both sports have one.
+func suggestedZonesFile(current []CurrentThreshold) map[string]any {
+	settings := make(map[string]any)
+	for _, c := range current {
+		switch c.Metric {
+		case thresholds.MetricLTHR:
+			if _, ok := settings["heartrate"]; !ok || c.Sport == thresholds.SportRun {
+				settings["heartrate"] = zones.LTHRBoundaries(c.Value)
+			}
+		case thresholds.MetricFTP:
+			settings["power"] = zones.FTPBoundaries(c.Value)
+		case thresholds.MetricThresholdPace:
+			settings["threshold_pace"] = formatPaceMinutes(c.Value)
+		}
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+	return settings
+}
+
+// formatPaceMinutes formats a speed in m/s as "m:ss" per km, the way zones
+// files expect threshold pace
+func formatPaceMinutes(mps float64) string {
+	secPerKm := int(math.Round(1000 / mps))
+	return fmt.Sprintf("%d:%02d", secPerKm/60, secPerKm%60)
+}
+
+// thresholdInsights reports new and stale thresholds
+func thresholdInsights(output GetThresholdHistoryOutput) []Insight {
+	var insights []Insight
+	if len(output.Current) == 0 {
+		return []Insight{{
+			Type:    "suggestion",
+			Message: "No thresholds detected yet. Estimates need 20 minutes of heart rate, power or run pace data; a 20-minute time trial or a hard tempo session gives the best estimate.",
+		}}
+	}
+
+	for _, c := range output.NewThresholds {
+		msg := fmt.Sprintf("New %s %s: %s on %s.", c.Sport, thresholdName(c.Metric), c.Display, c.DetectedOn)
+		if c.Previous != "" {
+			msg = fmt.Sprintf("New %s %s: %s on %s, up %.1f%% from %s.", c.Sport, thresholdName(c.Metric), c.Display, c.DetectedOn, c.ChangePct, c.Previous)
+		}
+		insights = append(insights, Insight{Type: "achievement", Message: msg})
+	}
+	if len(output.NewThresholds) > 0 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: "Zones based on older thresholds undercount time at intensity. Save suggested_zones_file and pass it with --zones-file to recompute zones.",
+		})
+	}
+
+	for _, c := range output.Current {
+		if c.Stale {
+			insights = append(insights, Insight{
+				Type:    "suggestion",
+				Message: fmt.Sprintf("Your %s %s (%s) is %d days old. A 20-minute all-out effort would confirm or raise it.", c.Sport, thresholdName(c.Metric), c.Display, c.AgeDays),
+			})
+		}
+	}
+	return insights
+}
+
+func thresholdName(metric string) string {
+	switch metric {
+	case thresholds.MetricLTHR:
+		return "threshold heart rate"
+	case thresholds.MetricFTP:
+		return "FTP"
+	case thresholds.MetricThresholdPace:
+		return "threshold pace"
+	}
+	return metric
+}
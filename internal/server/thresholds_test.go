@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+// thresholdQuerier has a running LTHR that rose recently and an FTP that
+// hasn't moved in months
+func thresholdQuerier() *MockQuerier {
+	now := time.Now()
+	return &MockQuerier{
+		activities: []db.Activity{
+			createTestActivity(1, "Tempo Run", "Run", now.AddDate(0, -6, 0)),
+			createTestActivity(2, "Threshold Intervals", "Run", now.AddDate(0, 0, -5)),
+			createTestActivity(3, "FTP Test", "Ride", now.AddDate(0, -5, 0)),
+		},
+		thresholdHistory: []db.ThresholdHistory{
+			{ID: 1, Metric: "lthr", Sport: "run", Value: 165, ActivityID: 1, DetectedOn: now.AddDate(0, -6, 0)},
+			{ID: 2, Metric: "ftp", Sport: "ride", Value: 250, ActivityID: 3, DetectedOn: now.AddDate(0, -5, 0)},
+			{ID: 3, Metric: "lthr", Sport: "run", Value: 170, PreviousValue: sql.NullFloat64{Float64: 165, Valid: true}, ActivityID: 2, DetectedOn: now.AddDate(0, 0, -5)},
+		},
+	}
+}
+
+func TestGetThresholdHistory(t *testing.T) {
+	t.Parallel()
+
+	mock := thresholdQuerier()
+	srv := New(mock)
+
+	_, output, err := srv.getThresholdHistory(context.Background(), nil, GetThresholdHistoryInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(output.Current) != 2 {
+		t.Fatalf("expected current LTHR and FTP, got %+v", output.Current)
+	}
+	lthr, ftp := output.Current[0], output.Current[1]
+	if lthr.Metric != "lthr" || lthr.Value != 170 || lthr.ActivityName != "Threshold Intervals" || lthr.Stale {
+		t.Errorf("unexpected current LTHR: %+v", lthr)
+	}
+	if ftp.Metric != "ftp" || ftp.Display != "250 W" || !ftp.Stale {
+		t.Errorf("expected a stale FTP, got %+v", ftp)
+	}
+
+	if len(output.History) != 3 {
+		t.Errorf("expected 3 history entries, got %d", len(output.History))
+	}
+	if len(output.NewThresholds) != 1 || output.NewThresholds[0].ChangePct != 3 || output.NewThresholds[0].Previous != "165 bpm" {
+		t.Errorf("expected the recent LTHR rise, got %+v", output.NewThresholds)
+	}
+
+	if got, want := output.SuggestedZonesFile["heartrate"], []int{145, 153, 162, 170}; !slices.Equal(got.([]int), want) {
+		t.Errorf("expected heart rate zones %v, got %v", want, got)
+	}
+	if _, ok := output.SuggestedZonesFile["power"]; !ok {
+		t.Error("expected suggested power zones")
+	}
+
+	var achievements, suggestions int
+	for _, i := range output.Insights {
+		switch i.Type {
+		case "achievement":
+			achievements++
+		case "suggestion":
+			suggestions++
+		}
+	}
+	if achievements != 1 || suggestions != 2 {
+		t.Errorf("expected one achievement and suggestions to update zones and retest FTP, got %+v", output.Insights)
+	}
+
+	// Calling again, such as on a retry, reports the same thresholds
+	_, output, err = srv.getThresholdHistory(context.Background(), nil, GetThresholdHistoryInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.NewThresholds) != 1 {
+		t.Errorf("expected the LTHR rise reported again, got %+v", output.NewThresholds)
+	}
+}
+
+func TestGetThresholdHistorySince(t *testing.T) {
+	t.Parallel()
+
+	srv := New(thresholdQuerier())
+	now := time.Now().UTC()
+
+	tests := []struct {
+		since string
+		want  int
+	}{
+		{now.AddDate(0, 0, -1).Format("2006-01-02"), 0},
+		{now.AddDate(0, 0, -5).Format("2006-01-02"), 1},
+		{now.AddDate(-1, 0, 0).Format("2006-01-02"), 3},
+	}
+	for _, tt := range tests {
+		_, output, err := srv.getThresholdHistory(context.Background(), nil, GetThresholdHistoryInput{Since: tt.since})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.NewThresholds) != tt.want || output.Since != tt.since {
+			t.Errorf("since %s: expected %d new thresholds, got %+v", tt.since, tt.want, output.NewThresholds)
+		}
+	}
+
+	if _, _, err := srv.getThresholdHistory(context.Background(), nil, GetThresholdHistoryInput{Since: "last week"}); err == nil {
+		t.Error("expected an error for an invalid since date")
+	}
+}
+
+func TestGetThresholdHistoryFilters(t *testing.T) {
+	t.Parallel()
+
+	srv := New(thresholdQuerier())
+
+	_, output, err := srv.getThresholdHistory(context.Background(), nil, GetThresholdHistoryInput{Metric: "ftp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Current) != 1 || len(output.History) != 1 || output.Current[0].Metric != "ftp" {
+		t.Errorf("expected only FTP, got %+v", output)
+	}
+	if _, ok := output.SuggestedZonesFile["heartrate"]; ok {
+		t.Error("expected no heart rate zones when filtered to FTP")
+	}
+
+	for _, input := range []GetThresholdHistoryInput{{Metric: "vo2max"}, {Sport: "swim"}} {
+		if _, _, err := srv.getThresholdHistory(context.Background(), nil, input); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
+
+func TestGetThresholdHistoryEmpty(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{})
+
+	_, output, err := srv.getThresholdHistory(context.Background(), nil, GetThresholdHistoryInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Current) != 0 || output.SuggestedZonesFile != nil {
+		t.Errorf("expected no thresholds, got %+v", output)
+	}
+	if len(output.Insights) != 1 || output.Insights[0].Type != "suggestion" {
+		t.Errorf("expected a suggestion to do a threshold effort, got %+v", output.Insights)
+	}
+}
+
+func TestFormatPaceMinutes(t *testing.T) {
+	t.Parallel()
+
+	if got := formatPaceMinutes(4); got != "4:10" {
+		t.Errorf("expected 4:10, got %s", got)
+	}
+}
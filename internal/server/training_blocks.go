@@ -0,0 +1,400 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TrainingBlocksQuerier defines the interface for training block queries
+type TrainingBlocksQuerier interface {
+	SearchActivities(ctx context.Context, arg db.SearchActivitiesParams) ([]db.Activity, error)
+	ListThresholdHistory(ctx context.Context) ([]db.ThresholdHistory, error)
+}
+
+// Limits on the history analyzed for training blocks
+const (
+	defaultBlockWeeks = 26
+	minBlockWeeks     = 8
+	maxBlockWeeks     = 104
+	// maxBlockActivities bounds the activities read, well above two years of
+	// twice-daily training
+	maxBlockActivities = 5000
+)
+
+// Reporting limits for training blocks
+const (
+	maxKeySessions     = 3
+	maxBlockComparison = 3
+	// recoveryOverdue is how many weeks without a recovery or taper week
+	// earns a warning
+	recoveryOverdue = 6
+	// efficiencyNotable is the change in aerobic efficiency, in percent,
+	// worth pointing out
+	efficiencyNotable = 2.0
+)
+
+// Input types
+
+// AnalyzeTrainingBlocksInput - input for segmenting training into phases
+type AnalyzeTrainingBlocksInput struct {
+	Weeks int    `json:"weeks,omitempty" jsonschema:"Number of recent weeks to segment into blocks. Range: 8-104. Default: 26 weeks."`
+	Type  string `json:"type,omitempty" jsonschema:"Filter analysis to a specific activity type. Common values: Run, Ride, Swim. Leave empty to segment total training across all activities."`
+}
+
+// Output types
+
+type AnalyzeTrainingBlocksOutput struct {
+	Type         string            `json:"type,omitempty"`
+	DateRange    string            `json:"date_range"`
+	Blocks       []TrainingBlock   `json:"blocks"`
+	CurrentBlock *TrainingBlock    `json:"current_block,omitempty"`
+	Comparisons  []BlockComparison `json:"comparisons,omitempty"`
+	Insights     []Insight         `json:"insights"`
+	// SuggestedActions for follow-up tools
+	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+}
+
+// TrainingBlock is a run of weeks in one phase
+type TrainingBlock struct {
+	Phase          string       `json:"phase"` // base, build, peak, taper or recovery
+	Start          string       `json:"start"`
+	End            string       `json:"end"`
+	Weeks          int          `json:"weeks"`
+	ActivityCount  int          `json:"activity_count"`
+	TotalDistance  string       `json:"total_distance"`
+	TotalDuration  string       `json:"total_duration"`
+	WeeklyDistance string       `json:"weekly_distance"`
+	WeeklyDuration string       `json:"weekly_duration"`
+	HardPercent    float64      `json:"hard_percent"` // share of moving time in tempo, interval and race sessions
+	KeySessions    []KeySession `json:"key_sessions,omitempty"`
+	// FitnessChange describes how aerobic efficiency moved across the block,
+	// e.g. "Run efficiency up 3.1%"
+	FitnessChange    string   `json:"fitness_change,omitempty"`
+	EfficiencyChange *float64 `json:"efficiency_change,omitempty"`
+	ThresholdChanges []string `json:"threshold_changes,omitempty"`
+
+	weeklySeconds float64
+	weeklyMeters  float64
+}
+
+// KeySession is a race, long session or hard session that stood out in a block
+type KeySession struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Date     string `json:"date"`
+	Type     string `json:"type"`
+	Category string `json:"workout_category,omitempty"`
+	Distance string `json:"distance"`
+	Duration string `json:"duration"`
+}
+
+// BlockComparison compares the current block with an earlier one
+type BlockComparison struct {
+	Phase string `json:"phase"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Summary describes the difference, e.g.
+	// "6h 10m/week vs 5h 20m (+16%), 18% hard vs 12%"
+	Summary              string  `json:"summary"`
+	WeeklyDurationChange float64 `json:"weekly_duration_change_percent"`
+	WeeklyDistanceChange float64 `json:"weekly_distance_change_percent"`
+	HardPercentChange    float64 `json:"hard_percent_change"`
+}
+
+// registerTrainingBlockTools registers the periodization tool
+func (s *Server) registerTrainingBlockTools() {
+	logging.Debug("Registering tool", "name", "analyze_training_blocks")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "analyze_training_blocks",
+		Description: `Split training history into base, build, peak, taper and recovery blocks from rolling weekly volume and intensity, and compare the current block with earlier ones.
+
+Use when:
+- User asks "What phase of training am I in?" or "Am I building or peaking?"
+- User wants to compare this build with their last one
+- User asks how a training block went or whether they're due a recovery week
+- User wants more than check_training_load's 12 weeks of flat weekly totals
+
+Parameters:
+- weeks (integer): Number of recent weeks to segment. Range: 8-104. Default: 26.
+- type (string): Filter to a specific activity type (Run, Ride, Swim, etc.). Leave empty for all training.
+
+Returns: Blocks oldest first, each with its phase, dates, totals, weekly averages, share of time in hard sessions (tempo, intervals, races), key sessions, the change in aerobic efficiency across the block and any threshold changes detected during it. The current block is compared with the block before it and up to 3 earlier blocks of the same phase. Weeks are labelled from moving time against the previous 4 weeks and from workout categories: a drop of 15% or more before a race is a taper, a drop of 30% or more otherwise is recovery, growth or a hard share over 12% is build, and near-maximal weeks over 20% hard are peak. The week in progress keeps the previous week's phase.
+
+Example: {"weeks": 26, "type": "Run"} or {"weeks": 52}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Analyze Training Blocks",
+			ReadOnlyHint:    true,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(false),
+			DestructiveHint: ptr(false),
+		},
+	}, s.analyzeTrainingBlocks)
+}
+
+// analyzeTrainingBlocks segments recent weeks into training phases and
+// compares the current block with earlier ones
+func (s *Server) analyzeTrainingBlocks(ctx context.Context, req *mcp.CallToolRequest, input AnalyzeTrainingBlocksInput) (*mcp.CallToolResult, AnalyzeTrainingBlocksOutput, error) {
+	weeks := input.Weeks
+	if weeks <= 0 {
+		weeks = defaultBlockWeeks
+	}
+	weeks = min(max(weeks, minBlockWeeks), maxBlockWeeks)
+
+	logging.Info("MCP tool call", "tool", "analyze_training_blocks", "weeks", weeks, "type", input.Type)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "analyze_training_blocks", "input", logging.ToJSON(input))
+	}
+
+	now := time.Now()
+	start := blocks.WeekStart(now).AddDate(0, 0, -7*(weeks-1))
+
+	queries := s.queries.(TrainingBlocksQuerier)
+
+	var activityType sql.NullString
+	if input.Type != "" {
+		activityType = sql.NullString{String: input.Type, Valid: true}
+	}
+	from := sql.NullTime{Time: start, Valid: true}
+	activities, err := queries.SearchActivities(ctx, db.SearchActivitiesParams{
+		Column1:   activityType,
+		Type:      activityType,
+		Column3:   from,
+		StartDate: from,
+		Limit:     maxBlockActivities,
+	})
+	if err != nil {
+		return nil, AnalyzeTrainingBlocksOutput{}, NewDatabaseError(err)
+	}
+	history, err := queries.ListThresholdHistory(ctx)
+	if err != nil {
+		return nil, AnalyzeTrainingBlocksOutput{}, NewDatabaseError(err)
+	}
+
+	sessions := make([]blocks.Session, 0, len(activities))
+	for _, a := range activities {
+		if !a.StartDate.Valid {
+			continue
+		}
+		sessions = append(sessions, blocks.Session{
+			ID:        a.ID,
+			Name:      a.Name,
+			Type:      a.Type.String,
+			Start:     a.StartDate.Time,
+			Seconds:   float64(a.MovingTime.Int64),
+			Meters:    a.Distance.Float64,
+			Speed:     a.AverageSpeed.Float64,
+			Heartrate: a.AverageHeartrate.Float64,
+			Category:  a.WorkoutCategory.String,
+		})
+	}
+
+	segmented := blocks.Segment(blocks.Weeks(sessions, start, now))
+	output := AnalyzeTrainingBlocksOutput{
+		Type:      input.Type,
+		DateRange: fmt.Sprintf("%s to %s", start.Format("2006-01-02"), now.Format("2006-01-02")),
+		Blocks:    make([]TrainingBlock, 0, len(segmented)),
+	}
+	for _, b := range segmented {
+		output.Blocks = append(output.Blocks, trainingBlock(b, thresholdChangesIn(history, input.Type, b)))
+	}
+	if len(output.Blocks) > 0 {
+		current := output.Blocks[len(output.Blocks)-1]
+		output.CurrentBlock = &current
+		output.Comparisons = compareBlocks(output.Blocks)
+	}
+	output.Insights = trainingBlockInsights(segmented, output)
+	output.SuggestedActions = SuggestNextActions("training_blocks")
+
+	logging.Info("MCP tool completed", "tool", "analyze_training_blocks", "blocks", len(output.Blocks), "activities", len(sessions))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "analyze_training_blocks", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// trainingBlock summarizes a block for output
+func trainingBlock(b blocks.Block, thresholdChanges []string) TrainingBlock {
+	seconds, meters, _, sessions := b.Totals()
+	out := TrainingBlock{
+		Phase:            b.Phase,
+		Start:            b.Start().Format("2006-01-02"),
+		End:              b.End().Format("2006-01-02"),
+		Weeks:            len(b.Weeks),
+		ActivityCount:    sessions,
+		TotalDistance:    formatDistance(meters),
+		TotalDuration:    formatDuration(int64(seconds)),
+		WeeklyDistance:   formatDistance(b.WeeklyMeters()),
+		WeeklyDuration:   formatDuration(int64(b.WeeklySeconds())),
+		HardPercent:      math.Round(b.Intensity()*1000) / 10,
+		ThresholdChanges: thresholdChanges,
+		weeklySeconds:    b.WeeklySeconds(),
+		weeklyMeters:     b.WeeklyMeters(),
+	}
+	for _, s := range b.KeySessions(maxKeySessions) {
+		out.KeySessions = append(out.KeySessions, KeySession{
+			ID:       s.ID,
+			Name:     s.Name,
+			Date:     s.Start.Format("2006-01-02"),
+			Type:     s.Type,
+			Category: s.Category,
+			Distance: formatDistance(s.Meters),
+			Duration: formatDuration(int64(s.Seconds)),
+		})
+	}
+	if activityType, change, ok := b.EfficiencyChange(); ok {
+		change = math.Round(change*10) / 10
+		out.EfficiencyChange = &change
+		direction := "up"
+		if change < 0 {
+			direction = "down"
+		}
+		out.FitnessChange = fmt.Sprintf("%s efficiency %s %.1f%%", activityType, direction, math.Abs(change))
+	}
+	return out
+}
+
+// thresholdChangesIn describes threshold changes detected during a block,
+// for the sport of activityType when one is given
+func thresholdChangesIn(history []db.ThresholdHistory, activityType string, b blocks.Block) []string {
+	var out []string
+	end := b.End().AddDate(0, 0, 1)
+	for _, h := range history {
+		if h.DetectedOn.Before(b.Start()) || !h.DetectedOn.Before(end) || !h.PreviousValue.Valid {
+			continue
+		}
+		if activityType != "" && h.Sport != thresholds.Sport(activityType) {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s %s %s → %s on %s", h.Sport, thresholdName(h.Metric),
+			formatThreshold(h.Metric, h.PreviousValue.Float64), formatThreshold(h.Metric, h.Value),
+			h.DetectedOn.Format("2006-01-02")))
+	}
+	return out
+}
+
+// compareBlocks compares the last block with the one before it and with up
+// to maxBlockComparison earlier blocks of the same phase, most recent first
+func compareBlocks(all []TrainingBlock) []BlockComparison {
+	current := all[len(all)-1]
+	var out []BlockComparison
+	var samePhase int
+	for i := len(all) - 2; i >= 0; i-- {
+		b := all[i]
+		same := b.Phase == current.Phase && samePhase < maxBlockComparison
+		if same {
+			samePhase++
+		}
+		if same || i == len(all)-2 {
+			out = append(out, compareBlock(current, b))
+		}
+	}
+	return out
+}
+
+// compareBlock compares block a with an earlier block b
+func compareBlock(a, b TrainingBlock) BlockComparison {
+	out := BlockComparison{
+		Phase:             b.Phase,
+		Start:             b.Start,
+		End:               b.End,
+		HardPercentChange: math.Round((a.HardPercent-b.HardPercent)*10) / 10,
+	}
+	if b.weeklySeconds > 0 {
+		out.WeeklyDurationChange = math.Round((a.weeklySeconds-b.weeklySeconds)/b.weeklySeconds*1000) / 10
+	}
+	if b.weeklyMeters > 0 {
+		out.WeeklyDistanceChange = math.Round((a.weeklyMeters-b.weeklyMeters)/b.weeklyMeters*1000) / 10
+	}
+	out.Summary = fmt.Sprintf("%s/week vs %s (%+.0f%%), %.0f%% hard vs %.0f%%",
+		a.WeeklyDuration, b.WeeklyDuration, out.WeeklyDurationChange, a.HardPercent, b.HardPercent)
+	return out
+}
+
+// trainingBlockInsights describes where the athlete is in their training and
+// how the current block stacks up
+func trainingBlockInsights(segmented []blocks.Block, output AnalyzeTrainingBlocksOutput) []Insight {
+	insights := make([]Insight, 0)
+	if output.CurrentBlock == nil {
+		return insights
+	}
+	current := *output.CurrentBlock
+	if _, _, _, sessions := segmented[0].Totals(); sessions == 0 && len(segmented) == 1 {
+		insights = append(insights, Insight{
+			Type:    "suggestion",
+			Message: "No training in this window; try more weeks or a different type",
+		})
+		return insights
+	}
+
+	insights = append(insights, Insight{
+		Type: "trend",
+		Message: fmt.Sprintf("You're in week %d of a %s block that started %s, averaging %s/week and %.0f%% hard",
+			current.Weeks, current.Phase, current.Start, current.WeeklyDuration, current.HardPercent),
+	})
+
+	// Weeks of loading since the last easier week
+	var loading int
+	for i := len(segmented) - 1; i >= 0; i-- {
+		if segmented[i].Phase == blocks.PhaseRecovery || segmented[i].Phase == blocks.PhaseTaper {
+			break
+		}
+		loading += len(segmented[i].Weeks)
+	}
+	if loading >= recoveryOverdue && current.Phase != blocks.PhaseBase {
+		insights = append(insights, Insight{
+			Type:    "warning",
+			Message: fmt.Sprintf("%d weeks since your last recovery week; a lighter week every 3-5 weeks helps absorb the training", loading),
+		})
+	}
+
+	// Volume against the biggest earlier block of the same phase
+	var biggest *TrainingBlock
+	for i := range output.Blocks[:len(output.Blocks)-1] {
+		b := &output.Blocks[i]
+		if b.Phase == current.Phase && (biggest == nil || b.weeklySeconds > biggest.weeklySeconds) {
+			biggest = b
+		}
+	}
+	if biggest != nil && biggest.weeklySeconds > 0 {
+		change := (current.weeklySeconds - biggest.weeklySeconds) / biggest.weeklySeconds * 100
+		if change > 0 {
+			insights = append(insights, Insight{
+				Type:    "achievement",
+				Message: fmt.Sprintf("Your biggest %s block yet: %s/week, %.0f%% more than the %s block from %s", current.Phase, current.WeeklyDuration, change, biggest.Phase, biggest.Start),
+			})
+		} else {
+			insights = append(insights, Insight{
+				Type:    "trend",
+				Message: fmt.Sprintf("%.0f%% less weekly volume than your biggest %s block, from %s", -change, current.Phase, biggest.Start),
+			})
+		}
+	}
+
+	if current.EfficiencyChange != nil {
+		switch change := *current.EfficiencyChange; {
+		case change >= efficiencyNotable:
+			insights = append(insights, Insight{
+				Type:    "achievement",
+				Message: fmt.Sprintf("Aerobic fitness is improving: %s across this block, covering more ground per heartbeat on easy sessions", current.FitnessChange),
+			})
+		case change <= -efficiencyNotable:
+			insights = append(insights, Insight{
+				Type:    "warning",
+				Message: fmt.Sprintf("%s across this block; heart rate is up for the same pace on easy sessions, which can mean fatigue, heat or illness", current.FitnessChange),
+			})
+		}
+	}
+	for _, c := range current.ThresholdChanges {
+		insights = append(insights, Insight{Type: "achievement", Message: "New threshold this block: " + c})
+	}
+	return insights
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+)
+
+// blockTestQuerier has eleven complete weeks of running before the current
+// one: four of base, four of build, a recovery week and two bigger build
+// weeks with a threshold pace gain
+func blockTestQuerier(now time.Time) *MockQuerier {
+	start := blocks.WeekStart(now).AddDate(0, 0, -7*11)
+	plan := []struct {
+		hours float64
+		hard  float64
+	}{
+		{5, 0}, {5, 0}, {5, 0}, {5, 0},
+		{5.5, 0.1}, {6, 0.1}, {6.5, 0.1}, {7, 0.1},
+		{2, 0},
+		{8, 0.15}, {8, 0.15},
+	}
+
+	m := &MockQuerier{}
+	id := int64(100)
+	for i, w := range plan {
+		monday := start.AddDate(0, 0, 7*i)
+		add := func(day int, seconds float64, category string, speed float64) {
+			a := createTestActivity(id, "Run", "Run", monday.AddDate(0, 0, day).Add(7*time.Hour))
+			a.MovingTime = sql.NullInt64{Int64: int64(seconds), Valid: true}
+			a.Distance = sql.NullFloat64{Float64: seconds * speed, Valid: true}
+			a.AverageSpeed = sql.NullFloat64{Float64: speed, Valid: true}
+			a.AverageHeartrate = sql.NullFloat64{Float64: 145, Valid: true}
+			a.WorkoutCategory = sql.NullString{String: category, Valid: true}
+			m.activities = append(m.activities, a)
+			id++
+		}
+		total := w.hours * 3600
+		hard := total * w.hard
+		if hard > 0 {
+			add(1, hard, "intervals", 4.2)
+		}
+		for day := range 4 {
+			add(2*day, (total-hard)/4, "easy", 3.0)
+		}
+	}
+
+	m.thresholdHistory = []db.ThresholdHistory{{
+		ID:            1,
+		Metric:        thresholds.MetricThresholdPace,
+		Sport:         thresholds.SportRun,
+		Value:         4.0,
+		PreviousValue: sql.NullFloat64{Float64: 3.9, Valid: true},
+		ActivityID:    150,
+		DetectedOn:    start.AddDate(0, 0, 7*9+1),
+	}}
+	return m
+}
+
+func TestAnalyzeTrainingBlocks(t *testing.T) {
+	t.Parallel()
+
+	srv := New(blockTestQuerier(time.Now()))
+
+	_, output, err := srv.analyzeTrainingBlocks(context.Background(), nil, AnalyzeTrainingBlocksInput{Weeks: 12, Type: "Run"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var phases []string
+	for _, b := range output.Blocks {
+		phases = append(phases, b.Phase)
+	}
+	if strings.Join(phases, ",") != "base,build,recovery,build" {
+		t.Fatalf("expected base, build, recovery, build, got %v", phases)
+	}
+
+	current := output.CurrentBlock
+	// The two build weeks and the week in progress
+	if current == nil || current.Phase != blocks.PhaseBuild || current.Weeks != 3 {
+		t.Fatalf("expected a 3 week build block, got %+v", current)
+	}
+	if current.HardPercent != 15 {
+		t.Errorf("expected 15%% hard, got %.1f", current.HardPercent)
+	}
+	if len(current.KeySessions) != 2 {
+		t.Errorf("expected the longest run and the longest interval session, got %+v", current.KeySessions)
+	}
+	if current.FitnessChange == "" {
+		t.Error("expected an efficiency change from the easy runs")
+	}
+	if len(current.ThresholdChanges) != 1 || !strings.Contains(current.ThresholdChanges[0], "threshold pace") {
+		t.Errorf("expected the threshold pace gain, got %v", current.ThresholdChanges)
+	}
+
+	// The recovery week before it and the earlier build
+	if len(output.Comparisons) != 2 || output.Comparisons[0].Phase != blocks.PhaseRecovery || output.Comparisons[1].Phase != blocks.PhaseBuild {
+		t.Fatalf("expected comparisons with the recovery and earlier build, got %+v", output.Comparisons)
+	}
+	if output.Comparisons[1].WeeklyDurationChange <= 0 || output.Comparisons[1].HardPercentChange != 5 {
+		t.Errorf("expected more volume and 5 points more hard than the last build, got %+v", output.Comparisons[1])
+	}
+
+	var biggest bool
+	for _, i := range output.Insights {
+		if i.Type == "achievement" && strings.Contains(i.Message, "biggest build block") {
+			biggest = true
+		}
+	}
+	if !biggest {
+		t.Errorf("expected a biggest build insight, got %+v", output.Insights)
+	}
+}
+
+func TestAnalyzeTrainingBlocksEmpty(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockQuerier{})
+
+	_, output, err := srv.analyzeTrainingBlocks(context.Background(), nil, AnalyzeTrainingBlocksInput{Weeks: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Weeks is raised to the minimum, all of them empty
+	if len(output.Blocks) != 1 || output.Blocks[0].Weeks != minBlockWeeks || output.Blocks[0].Phase != blocks.PhaseRecovery {
+		t.Errorf("expected one empty block over %d weeks, got %+v", minBlockWeeks, output.Blocks)
+	}
+	if len(output.Insights) != 1 || output.Insights[0].Type != "suggestion" {
+		t.Errorf("expected a suggestion to widen the search, got %+v", output.Insights)
+	}
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+	syncsvc "github.com/joshdurbin/strava-mcp/internal/sync"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// StravaActivityQuerier defines the interface for keeping the local copy of
+// an activity changed on Strava up to date
+type StravaActivityQuerier interface {
+	GetActivity(ctx context.Context, id int64) (db.Activity, error)
+	CreateActivity(ctx context.Context, arg db.CreateActivityParams) error
+	GetActivityOverride(ctx context.Context, activityID int64) (db.ActivityOverride, error)
+	UpsertActivitySocialCounts(ctx context.Context, arg db.UpsertActivitySocialCountsParams) error
+}
+
+// Input types
+
+// UpdateStravaActivityInput - input for changing an activity on Strava
+type UpdateStravaActivityInput struct {
+	ActivityID   int64  `json:"activity_id" jsonschema:"The activity to change."`
+	Name         string `json:"name,omitempty" jsonschema:"New name. Leave empty to keep the current one."`
+	Description  string `json:"description,omitempty" jsonschema:"New description. Leave empty to keep the current one."`
+	SportType    string `json:"sport_type,omitempty" jsonschema:"New sport type; the activity type follows it. Common values: Run, TrailRun, Ride, GravelRide, VirtualRide, Swim, Walk, Hike. Leave empty to keep the current one."`
+	GearID       string `json:"gear_id,omitempty" jsonschema:"Strava gear ID, e.g. 'g1234567' or 'b1234567'; 'none' removes the gear. Leave empty to keep the current gear."`
+	Commute      *bool  `json:"commute,omitempty" jsonschema:"Mark or unmark as a commute. Leave out to keep as is."`
+	Trainer      *bool  `json:"trainer,omitempty" jsonschema:"Mark or unmark as done on a trainer. Leave out to keep as is."`
+	HideFromHome *bool  `json:"hide_from_home,omitempty" jsonschema:"Mute or unmute the activity in followers' home feeds. Leave out to keep as is."`
+	ConfirmToken string `json:"confirm_token,omitempty" jsonschema:"Token from an earlier call's confirmation, once the user has agreed to the change."`
+}
+
+// Output types
+
+type UpdateStravaActivityOutput struct {
+	Activity ActivitySummary `json:"activity"`
+	// Changed lists the fields sent to Strava
+	Changed  []string  `json:"changed"`
+	Message  string    `json:"message"`
+	Insights []Insight `json:"insights"`
+	// Confirmation is set instead when the change still needs the user's approval
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+// registerStravaActivityTools registers the tools that change activities on Strava
+func (s *Server) registerStravaActivityTools() {
+	logging.Debug("Registering tool", "name", "update_strava_activity")
+	mcp.AddTool(s.mcp, &mcp.Tool{
+		Name: "update_strava_activity",
+		Description: `Change an activity on Strava: its name, description, sport type, gear, commute and trainer flags, or whether it shows in followers' feeds. The local copy is updated too.
+
+Use when:
+- User asks to rename activities, e.g. "Rename my Morning Runs to say what the workout was"
+- User wants to fix the sport type or gear on Strava itself
+- User wants to mark a ride as a commute or mute an activity from the feed
+
+Parameters:
+- activity_id (int): Required. The activity to change.
+- name (string): New name
+- description (string): New description
+- sport_type (string): New sport type (Run, TrailRun, Ride, etc.)
+- gear_id (string): Strava gear ID, or "none" to remove the gear
+- commute (boolean): Commute flag
+- trainer (boolean): Trainer flag
+- hide_from_home (boolean): Mute from followers' home feeds
+- confirm_token (string): Token from an earlier confirmation, once the user agreed
+
+Returns: The activity as updated and the fields changed. The user is asked to confirm the change first; clients that cannot ask get a confirmation with a token to call again with. This changes the activity on Strava for everyone who can see it; to fix data for these tools only, use update_activity_local. Needs the activity:write scope, granted by restarting with --force-reauth if the server was authorized before it was requested.
+
+Example: {"activity_id": 12345678, "name": "6x800m intervals"}`,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Update Strava Activity",
+			ReadOnlyHint:    false,
+			IdempotentHint:  true,
+			OpenWorldHint:   ptr(true),
+			DestructiveHint: ptr(true),
+		},
+	}, s.updateStravaActivity)
+}
+
+// updateStravaActivity changes an activity on Strava and stores the result
+func (s *Server) updateStravaActivity(ctx context.Context, req *mcp.CallToolRequest, input UpdateStravaActivityInput) (*mcp.CallToolResult, UpdateStravaActivityOutput, error) {
+	logging.Info("MCP tool call", "tool", "update_strava_activity", "activity_id", input.ActivityID)
+	if logging.IsVerbose() {
+		logging.Debug("MCP request params", "tool", "update_strava_activity", "input", logging.ToJSON(input))
+	}
+
+	update, changed := activityUpdate(input)
+	if len(changed) == 0 {
+		return nil, UpdateStravaActivityOutput{}, NewInvalidInputErrorWithDetails(
+			"Nothing to change", "Pass name, description, sport_type, gear_id, commute, trainer or hide_from_home")
+	}
+
+	queries := s.queries.(StravaActivityQuerier)
+	current, err := queries.GetActivity(ctx, input.ActivityID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, UpdateStravaActivityOutput{}, NewNotFoundErrorWithID("activity", input.ActivityID)
+		}
+		return nil, UpdateStravaActivityOutput{}, NewDatabaseError(err)
+	}
+
+	client, err := s.stravaClient()
+	if err != nil {
+		return nil, UpdateStravaActivityOutput{}, err
+	}
+
+	action := fmt.Sprintf("Change activity %d (%s) on Strava", input.ActivityID, current.Name)
+	confirmation, err := s.confirm(ctx, req, "update_strava_activity", action, updateChanges(current, update), input, input.ConfirmToken)
+	if err != nil {
+		return nil, UpdateStravaActivityOutput{}, err
+	}
+	if confirmation != nil {
+		output := UpdateStravaActivityOutput{
+			Activity:     convertActivity(current),
+			Changed:      changed,
+			Message:      "Waiting for the user to confirm the change",
+			Insights:     make([]Insight, 0),
+			Confirmation: confirmation,
+		}
+		logging.Info("MCP tool completed", "tool", "update_strava_activity", "activity_id", input.ActivityID, "status", "awaiting_confirmation")
+		return nil, output, nil
+	}
+	updated, err := client.UpdateActivity(ctx, input.ActivityID, update)
+	if err != nil {
+		return nil, UpdateStravaActivityOutput{}, NewStravaError(err)
+	}
+
+	// Local corrections are applied again as the activity is stored
+	if err := queries.CreateActivity(ctx, syncsvc.ConvertActivityToParams(*updated)); err != nil {
+		return nil, UpdateStravaActivityOutput{}, NewDatabaseErrorWithContext("saving the updated activity", err)
+	}
+	if err := queries.UpsertActivitySocialCounts(ctx, syncsvc.ConvertSocialCountsToParams(*updated)); err != nil {
+		return nil, UpdateStravaActivityOutput{}, NewDatabaseErrorWithContext("saving the updated activity", err)
+	}
+	activity, err := queries.GetActivity(ctx, input.ActivityID)
+	if err != nil {
+		return nil, UpdateStravaActivityOutput{}, NewDatabaseError(err)
+	}
+
+	output := UpdateStravaActivityOutput{
+		Activity: convertActivity(activity),
+		Changed:  changed,
+		Message:  "Activity updated on Strava and locally",
+		Insights: make([]Insight, 0),
+	}
+	override, err := queries.GetActivityOverride(ctx, input.ActivityID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, UpdateStravaActivityOutput{}, NewDatabaseError(err)
+	}
+	if (update.Name != nil && override.Name.Valid) || (update.SportType != nil && override.Type.Valid) {
+		output.Insights = append(output.Insights, Insight{
+			Type:    "warning",
+			Message: "A local correction from update_activity_local still replaces the Strava value here; reset it to use the new one",
+		})
+	}
+
+	logging.Info("MCP tool completed", "tool", "update_strava_activity", "activity_id", input.ActivityID, "changed", strings.Join(changed, ","))
+	if logging.IsVerbose() {
+		logging.Debug("MCP response", "tool", "update_strava_activity", "output", logging.ToJSON(output))
+	}
+	return nil, output, nil
+}
+
+// activityUpdate builds the Strava update from the input, with the names of
+// the fields it changes
+func activityUpdate(input UpdateStravaActivityInput) (strava.ActivityUpdate, []string) {
+	var update strava.ActivityUpdate
+	changed := make([]string, 0)
+	set := func(field, value string, dst **string) {
+		if value = strings.TrimSpace(value); value != "" {
+			*dst = &value
+			changed = append(changed, field)
+		}
+	}
+	set("name", input.Name, &update.Name)
+	set("description", input.Description, &update.Description)
+	set("sport_type", input.SportType, &update.SportType)
+	set("gear_id", input.GearID, &update.GearID)
+	if input.Commute != nil {
+		update.Commute = input.Commute
+		changed = append(changed, "commute")
+	}
+	if input.Trainer != nil {
+		update.Trainer = input.Trainer
+		changed = append(changed, "trainer")
+	}
+	if input.HideFromHome != nil {
+		update.HideFromHome = input.HideFromHome
+		changed = append(changed, "hide_from_home")
+	}
+	return update, changed
+}
+
+// updateChanges lists what an update changes, against the local copy where it
+// has the value
+func updateChanges(current db.Activity, update strava.ActivityUpdate) []Change {
+	changes := make([]Change, 0)
+	add := func(field, from string, to *string) {
+		if to != nil {
+			changes = append(changes, Change{Field: field, From: from, To: *to})
+		}
+	}
+	flag := func(field string, to *bool) {
+		if to != nil {
+			changes = append(changes, Change{Field: field, To: fmt.Sprintf("%t", *to)})
+		}
+	}
+	add("name", current.Name, update.Name)
+	add("description", "", update.Description)
+	add("sport_type", current.SportType.String, update.SportType)
+	add("gear_id", "", update.GearID)
+	flag("commute", update.Commute)
+	flag("trainer", update.Trainer)
+	flag("hide_from_home", update.HideFromHome)
+	return changes
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// fakeStrava records updates and answers with the activity as changed.
+// Uploads finish as activity, or stay pending when uploadPending is set.
+type fakeStrava struct {
+	activity      strava.Activity
+	updates       []strava.ActivityUpdate
+	created       []strava.ManualActivity
+	uploads       []strava.UploadFile
+	uploadPending bool
+	err           error
+}
+
+func (f *fakeStrava) UpdateActivity(ctx context.Context, activityID int64, update strava.ActivityUpdate) (*strava.Activity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.updates = append(f.updates, update)
+	a := f.activity
+	if update.Name != nil {
+		a.Name = *update.Name
+	}
+	if update.SportType != nil {
+		a.SportType = *update.SportType
+	}
+	return &a, nil
+}
+
+func (f *fakeStrava) CreateActivity(ctx context.Context, manual strava.ManualActivity) (*strava.Activity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.created = append(f.created, manual)
+	a := f.activity
+	a.Name, a.Type, a.SportType = manual.Name, manual.SportType, manual.SportType
+	a.StartDateLocal, a.ElapsedTime, a.Distance = manual.StartDateLocal, manual.ElapsedTime, manual.Distance
+	return &a, nil
+}
+
+func (f *fakeStrava) UploadActivity(ctx context.Context, file strava.UploadFile) (*strava.Upload, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.uploads = append(f.uploads, file)
+	return &strava.Upload{ID: 9, Status: "Your activity is still being processed."}, nil
+}
+
+func (f *fakeStrava) WaitForUpload(ctx context.Context, uploadID int64, interval time.Duration) (*strava.Upload, error) {
+	if f.uploadPending {
+		return &strava.Upload{ID: uploadID}, strava.ErrUploadPending
+	}
+	return &strava.Upload{ID: uploadID, ActivityID: &f.activity.ID}, nil
+}
+
+func (f *fakeStrava) FetchActivity(ctx context.Context, activityID int64) (*strava.Activity, error) {
+	a := f.activity
+	return &a, nil
+}
+
+func stravaTestServer(m *MockQuerier, f *fakeStrava) *Server {
+	return New(m).WithStrava(func() (StravaClient, error) { return f, nil })
+}
+
+// confirmedStravaUpdate updates an activity, confirming with the token
+func confirmedStravaUpdate(srv *Server, input UpdateStravaActivityInput) (*mcp.CallToolResult, UpdateStravaActivityOutput, error) {
+	_, output, err := srv.updateStravaActivity(context.Background(), nil, input)
+	if err != nil || output.Confirmation == nil {
+		return nil, output, err
+	}
+	input.ConfirmToken = output.Confirmation.Token
+	return srv.updateStravaActivity(context.Background(), nil, input)
+}
+
+func TestUpdateStravaActivity(t *testing.T) {
+	t.Parallel()
+
+	m := &MockQuerier{activities: []db.Activity{{ID: 1, Name: "Morning Run", Type: sql.NullString{String: "Run", Valid: true}}}}
+	f := &fakeStrava{activity: strava.Activity{ID: 1, Name: "Morning Run", Type: "Run", SportType: "Run", Distance: 8000}}
+	srv := stravaTestServer(m, f)
+
+	commute := false
+	input := UpdateStravaActivityInput{ActivityID: 1, Name: " 6x800m intervals ", Commute: &commute}
+	_, output, err := srv.updateStravaActivity(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Confirmation == nil || len(f.updates) != 0 {
+		t.Fatalf("expected a confirmation before changing Strava, got %+v", output)
+	}
+	if c := output.Confirmation.Changes[0]; c.Field != "name" || c.From != "Morning Run" || c.To != "6x800m intervals" {
+		t.Errorf("expected the name change listed, got %+v", output.Confirmation.Changes)
+	}
+	input.ConfirmToken = output.Confirmation.Token
+	_, output, err = srv.updateStravaActivity(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.updates) != 1 || *f.updates[0].Name != "6x800m intervals" || f.updates[0].Commute == nil || f.updates[0].Trainer != nil {
+		t.Fatalf("expected the name and commute flag sent, got %+v", f.updates)
+	}
+	if output.Activity.Name != "6x800m intervals" || m.activities[0].Distance.Float64 != 8000 {
+		t.Errorf("expected the local copy updated, got %+v", output.Activity)
+	}
+	if len(output.Changed) != 2 || len(output.Insights) != 0 {
+		t.Errorf("expected name and commute changed without warnings, got %+v", output)
+	}
+
+	// A local name correction still wins
+	m.overrides = map[int64]db.ActivityOverride{1: {ActivityID: 1, Name: sql.NullString{String: "Track session", Valid: true}}}
+	_, output, err = confirmedStravaUpdate(srv, UpdateStravaActivityInput{ActivityID: 1, Name: "Intervals"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Activity.Name != "Track session" || len(output.Insights) != 1 {
+		t.Errorf("expected the local correction kept with a warning, got %+v", output)
+	}
+
+	if _, _, err := srv.updateStravaActivity(context.Background(), nil, UpdateStravaActivityInput{ActivityID: 1, Name: "  "}); err == nil {
+		t.Error("expected an error with nothing to change")
+	}
+	if _, _, err := srv.updateStravaActivity(context.Background(), nil, UpdateStravaActivityInput{ActivityID: 2, Name: "x"}); err == nil {
+		t.Error("expected an error for a missing activity")
+	}
+}
+
+func TestUpdateStravaActivityErrors(t *testing.T) {
+	t.Parallel()
+
+	m := &MockQuerier{activities: []db.Activity{{ID: 1, Name: "Morning Run"}}}
+	input := UpdateStravaActivityInput{ActivityID: 1, Name: "Intervals"}
+
+	_, _, err := New(m).updateStravaActivity(context.Background(), nil, input)
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != ErrStravaError {
+		t.Errorf("expected Strava to be unavailable offline, got %v", err)
+	}
+
+	_, _, err = confirmedStravaUpdate(stravaTestServer(m, &fakeStrava{err: strava.ErrMissingScope}), input)
+	if !errors.As(err, &toolErr) || toolErr.Code != ErrStravaError || toolErr.Details != "Restart with --force-reauth to grant the activity:write scope" {
+		t.Errorf("expected a missing scope error, got %v", err)
+	}
+
+	// The shared budget's error comes back wrapped by the HTTP client
+	budgetErr := &url.Error{Op: "Put", URL: "https://www.strava.com/api/v3/activities/1", Err: strava.ErrBudgetExhausted}
+	_, _, err = confirmedStravaUpdate(stravaTestServer(m, &fakeStrava{err: budgetErr}), input)
+	if !errors.As(err, &toolErr) || toolErr.Message != "Shared rate limit budget exhausted" {
+		t.Errorf("expected a budget exhausted error, got %v", err)
+	}
+	if m.activities[0].Name != "Morning Run" {
+		t.Errorf("expected the local copy unchanged, got %q", m.activities[0].Name)
+	}
+}
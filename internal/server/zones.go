@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/joshdurbin/strava-mcp/internal/db"
@@ -26,6 +27,8 @@ type ZonesQuerier interface {
 	GetPowerZoneSummaryByType(ctx context.Context, activityType sql.NullString) ([]db.GetPowerZoneSummaryByTypeRow, error)
 	GetPowerZoneSummaryInRange(ctx context.Context, arg db.GetPowerZoneSummaryInRangeParams) ([]db.GetPowerZoneSummaryInRangeRow, error)
 	GetActivity(ctx context.Context, id int64) (db.Activity, error)
+	GetZoneSourceCounts(ctx context.Context, zoneType string) ([]db.GetZoneSourceCountsRow, error)
+	GetPaceZoneSummary(ctx context.Context, arg db.GetPaceZoneSummaryParams) ([]db.GetPaceZoneSummaryRow, error)
 }
 
 // Zone input types
@@ -37,10 +40,13 @@ type GetActivityZonesInput struct {
 
 // AnalyzeZonesInput - input for aggregated zone analysis across activities
 type AnalyzeZonesInput struct {
-	ZoneType  string `json:"zone_type,omitempty" jsonschema:"Type of training zones to analyze. Valid values: 'heartrate' (heart rate zones 1-5) or 'power' (power zones for cycling). Default: heartrate."`
+	ZoneType  string `json:"zone_type,omitempty" jsonschema:"Type of training zones to analyze. Valid values: 'heartrate' (heart rate zones 1-5), 'power' (power zones for cycling) or 'pace' (running pace zones 1-5 from threshold pace). Default: heartrate."`
 	Type      string `json:"type,omitempty" jsonschema:"Filter analysis to a specific activity type. Common values: Run, Ride, Swim. Leave empty to analyze all activities with zone data."`
 	StartDate string `json:"start_date,omitempty" jsonschema:"Include activities on or after this date. Format: YYYY-MM-DD. Leave empty for all-time analysis."`
 	EndDate   string `json:"end_date,omitempty" jsonschema:"Include activities on or before this date. Format: YYYY-MM-DD. Leave empty to include up to today."`
+
+	IncludeChart bool   `json:"include_chart,omitempty" jsonschema:"When true, also return a donut chart of the zone distribution as image content."`
+	ChartFormat  string `json:"chart_format,omitempty" jsonschema:"Chart image format when include_chart is set. Valid values: 'png', 'svg'. Default: png."`
 }
 
 // Zone output types
@@ -57,26 +63,41 @@ type ActivityZonesOutput struct {
 type ZoneData struct {
 	Type        string       `json:"type"` // heartrate or power
 	SensorBased bool         `json:"sensor_based"`
+	Source      string       `json:"source"` // strava, or local when computed from streams
 	Buckets     []ZoneBucket `json:"buckets"`
 	TotalTime   string       `json:"total_time"`
 }
 
 type ZoneBucket struct {
-	Zone       int     `json:"zone"`        // 1-5
-	MinValue   int     `json:"min_value"`   // BPM or watts
-	MaxValue   int     `json:"max_value"`   // BPM or watts (-1 for unbounded)
-	TimeSpent  string  `json:"time_spent"`  // Human readable
-	Percentage float64 `json:"percentage"`  // % of total zone time
+	Zone       int     `json:"zone"`                 // 1-5
+	MinValue   int     `json:"min_value"`            // BPM or watts
+	MaxValue   int     `json:"max_value"`            // BPM or watts (-1 for unbounded)
+	TimeSpent  string  `json:"time_spent"`           // Human readable
+	Percentage float64 `json:"percentage"`           // % of total zone time
+	PaceRange  string  `json:"pace_range,omitempty"` // pace zones only; bounds are speeds in mm/s
 }
 
 type AnalyzeZonesOutput struct {
-	ZoneType         string            `json:"zone_type"`
-	Zones            []ZoneSummaryRow  `json:"zones"`
-	TotalTime        string            `json:"total_time"`
-	ActivityCount    int64             `json:"activity_count"`
-	Filter           string            `json:"filter,omitempty"`
-	Insights         []Insight         `json:"insights"`
-	SuggestedActions []SuggestedAction `json:"suggested_actions"`
+	ZoneType         string                 `json:"zone_type"`
+	Zones            []ZoneSummaryRow       `json:"zones"`
+	TotalTime        string                 `json:"total_time"`
+	ActivityCount    int64                  `json:"activity_count"`
+	Sources          map[string]int64       `json:"sources,omitempty"` // activities with zones from strava vs computed locally
+	Intensity        *IntensityDistribution `json:"intensity_distribution,omitempty"`
+	Filter           string                 `json:"filter,omitempty"`
+	Insights         []Insight              `json:"insights"`
+	SuggestedActions []SuggestedAction      `json:"suggested_actions"`
+}
+
+// IntensityDistribution collapses the zones into Seiler's three-zone model:
+// below the first ventilatory threshold (zones 1-2), between the thresholds
+// (zone 3) and above the second (zone 4 and up)
+type IntensityDistribution struct {
+	Zone1Pct          float64 `json:"zone_1_pct"`
+	Zone2Pct          float64 `json:"zone_2_pct"`
+	Zone3Pct          float64 `json:"zone_3_pct"`
+	PolarizationIndex float64 `json:"polarization_index"` // > 2.0 is polarized (Treff et al.)
+	Model             string  `json:"model"`              // polarized, pyramidal, threshold, high_intensity or balanced
 }
 
 type ZoneSummaryRow struct {
@@ -104,7 +125,7 @@ Parameters:
 
 Returns: Activity name/type, list of zones (heartrate and/or power) with buckets showing zone number, BPM/watt ranges, time spent, and percentage of total time. Includes training intensity insights.
 
-Note: Requires Strava Summit subscription for zone data to be available.
+Note: Zones come from Strava (Summit) when available, otherwise they are computed locally from the activity's heart rate or power stream using the athlete's zone boundaries. Each zone reports its source.
 
 Example: {"activity_id": 12345678901}`,
 		Annotations: &mcp.ToolAnnotations{
@@ -127,16 +148,18 @@ Use when:
 - User asks "Am I training at the right intensity?" or about the 80/20 rule
 
 Parameters:
-- zone_type (string): Type of zones to analyze: "heartrate" or "power". Default: "heartrate".
+- zone_type (string): Type of zones to analyze: "heartrate", "power" or "pace" (running pace zones from threshold pace or VDOT). Default: "heartrate".
 - type (string): Filter by activity type (Run, Ride, etc.). Leave empty for all types.
 - start_date (string): Start date in YYYY-MM-DD format. Leave empty for all time.
 - end_date (string): End date in YYYY-MM-DD format. Leave empty for all time.
+- include_chart (boolean): Also return a zone distribution donut chart as an image. Default: false.
+- chart_format (string): "png" or "svg". Default: "png".
 
-Returns: Zone-by-zone breakdown with total time, average time per activity, activity count, and percentage of total training time. Includes insights about training intensity balance (80/20 rule compliance).
+Returns: Zone-by-zone breakdown with total time, average time per activity, activity count, and percentage of total training time. intensity_distribution summarizes Seiler's three zones with a polarization index and classifies the distribution as polarized, pyramidal, threshold, high_intensity or balanced. Includes insights about training intensity balance (80/20 rule compliance, polarization). Sources counts activities whose zones came from Strava versus computed locally from streams. With include_chart, a donut chart of the distribution is attached.
 
-Note: Requires Strava Summit subscription for zone data to be available.
+Note: Zones come from Strava (Summit) when available, otherwise they are computed locally from streams using the athlete's zone boundaries.
 
-Example: {"zone_type": "heartrate", "type": "Run"} or {"zone_type": "power", "start_date": "2024-01-01"}`,
+Example: {"zone_type": "heartrate", "type": "Run"} or {"zone_type": "pace", "start_date": "2024-01-01"}`,
 		Annotations: &mcp.ToolAnnotations{
 			Title:           "Analyze Training Zones",
 			ReadOnlyHint:    true,
@@ -188,13 +211,15 @@ func (s *Server) getActivityZones(ctx context.Context, req *mcp.CallToolRequest,
 
 	for _, zone := range zones {
 		buckets, err := queries.GetZoneBuckets(ctx, zone.ID)
-		if err != nil {
+		if err != nil || len(buckets) == 0 {
+			// Locally computed zones leave an empty row when the stream had no data
 			continue
 		}
 
 		zoneData := ZoneData{
 			Type:        zone.ZoneType,
 			SensorBased: zone.SensorBased == 1,
+			Source:      zone.Source,
 			Buckets:     []ZoneBucket{},
 		}
 
@@ -215,6 +240,7 @@ func (s *Server) getActivityZones(ctx context.Context, req *mcp.CallToolRequest,
 				MaxValue:   int(b.MaxValue),
 				TimeSpent:  formatDurationHuman(b.TimeSeconds),
 				Percentage: pct,
+				PaceRange:  paceRange(zone.ZoneType, b.MinValue, b.MaxValue),
 			})
 
 			// Collect for insights (heartrate zones only)
@@ -253,6 +279,17 @@ func (s *Server) analyzeZones(ctx context.Context, req *mcp.CallToolRequest, inp
 		logging.Debug("MCP request params", "tool", "analyze_zones", "input", logging.ToJSON(input))
 	}
 
+	chartFormat, err := parseChartFormat(input.IncludeChart, input.ChartFormat)
+	if err != nil {
+		return nil, AnalyzeZonesOutput{}, err
+	}
+
+	if zoneType != "heartrate" && zoneType != "power" && zoneType != "pace" {
+		return nil, AnalyzeZonesOutput{}, NewInvalidInputErrorWithDetails(
+			fmt.Sprintf("invalid zone_type %q", zoneType),
+			"Valid values: heartrate, power, pace")
+	}
+
 	queries := s.queries.(ZonesQuerier)
 	filter := buildFilterDesc(input.Type, input.StartDate, input.EndDate)
 
@@ -260,9 +297,24 @@ func (s *Server) analyzeZones(ctx context.Context, req *mcp.CallToolRequest, inp
 	hasDateRange := input.StartDate != "" || input.EndDate != ""
 
 	var rows []hrZoneRow
-	var err error
 
-	if zoneType == "heartrate" {
+	if zoneType == "pace" {
+		start, end, parseErr := parseZoneDateRange(input.StartDate, input.EndDate)
+		if parseErr != nil {
+			return nil, AnalyzeZonesOutput{}, parseErr
+		}
+		activityType := sql.NullString{String: input.Type, Valid: hasType}
+		dbRows, dbErr := queries.GetPaceZoneSummary(ctx, db.GetPaceZoneSummaryParams{
+			Column1:     activityType,
+			Type:        activityType,
+			Column3:     start,
+			StartDate:   start,
+			Column5:     end,
+			StartDate_2: end,
+		})
+		err = dbErr
+		rows = convertPaceZoneRows(dbRows)
+	} else if zoneType == "heartrate" {
 		if hasType && hasDateRange {
 			start, end, parseErr := parseZoneDateRange(input.StartDate, input.EndDate)
 			if parseErr != nil {
@@ -338,11 +390,28 @@ func (s *Server) analyzeZones(ctx context.Context, req *mcp.CallToolRequest, inp
 	// Build output with insights
 	output := buildAnalyzeZonesOutput(rows, zoneType, filter)
 
+	sources, err := queries.GetZoneSourceCounts(ctx, zoneType)
+	if err != nil {
+		logging.Error("analyze_zones failed", "error", err)
+		return nil, AnalyzeZonesOutput{}, fmt.Errorf("querying zone sources: %w", err)
+	}
+	if len(sources) > 0 {
+		output.Sources = make(map[string]int64, len(sources))
+		for _, src := range sources {
+			output.Sources[src.Source] = src.ActivityCount
+		}
+	}
+
+	var result *mcp.CallToolResult
+	if input.IncludeChart {
+		result = chartResult("analyze_zones", output, zoneDonutChart(output), chartFormat)
+	}
+
 	logging.Info("MCP tool completed", "tool", "analyze_zones", "zone_count", len(output.Zones))
 	if logging.IsVerbose() {
 		logging.Debug("MCP response", "tool", "analyze_zones", "output", logging.ToJSON(output))
 	}
-	return nil, output, nil
+	return result, output, nil
 }
 
 // Helper types and functions for zone processing
@@ -469,6 +538,83 @@ func convertPowerZoneRowsInRange(rows []db.GetPowerZoneSummaryInRangeRow) []hrZo
 	return result
 }
 
+func convertPaceZoneRows(rows []db.GetPaceZoneSummaryRow) []hrZoneRow {
+	result := make([]hrZoneRow, len(rows))
+	for i, r := range rows {
+		result[i] = hrZoneRow{
+			ZoneNumber:    r.ZoneNumber,
+			TotalTime:     toInt64(r.TotalTime),
+			AvgTime:       toFloat64(r.AvgTime),
+			ActivityCount: r.ActivityCount,
+		}
+	}
+	return result
+}
+
+// paceRange describes a pace zone's bounds, which are stored as speeds in mm/s
+func paceRange(zoneType string, minValue, maxValue int64) string {
+	if zoneType != "pace" {
+		return ""
+	}
+	slowest := formatPace(float64(minValue) / 1000)
+	fastest := formatPace(float64(maxValue) / 1000)
+	switch {
+	case minValue <= 0 && maxValue > 0:
+		return "slower than " + fastest
+	case maxValue < 0:
+		return "faster than " + slowest
+	default:
+		return slowest + " - " + fastest
+	}
+}
+
+// intensityDistribution maps zone percentages onto Seiler's three zones.
+// Returns nil with fewer than four zones, where the mapping doesn't hold.
+func intensityDistribution(zonePercentages map[int]float64) *IntensityDistribution {
+	var d IntensityDistribution
+	maxZone := 0
+	for zone, pct := range zonePercentages {
+		maxZone = max(maxZone, zone)
+		switch {
+		case zone <= 2:
+			d.Zone1Pct += pct
+		case zone == 3:
+			d.Zone2Pct += pct
+		default:
+			d.Zone3Pct += pct
+		}
+	}
+	if maxZone < 4 || d.Zone1Pct+d.Zone2Pct+d.Zone3Pct == 0 {
+		return nil
+	}
+
+	d.PolarizationIndex = polarizationIndex(d.Zone1Pct, d.Zone2Pct, d.Zone3Pct)
+	z1, z2, z3 := d.Zone1Pct, d.Zone2Pct, d.Zone3Pct
+	switch {
+	case z1 > z3 && z3 > z2 && d.PolarizationIndex > 2:
+		d.Model = "polarized"
+	case z1 > z2 && z2 > z3:
+		d.Model = "pyramidal"
+	case z2 >= z1 && z2 >= z3:
+		d.Model = "threshold"
+	case z3 >= z1:
+		d.Model = "high_intensity"
+	default:
+		d.Model = "balanced"
+	}
+	return &d
+}
+
+// polarizationIndex is Treff et al.'s log10(z1/z2 * z3 * 100) over zone
+// fractions. An empty zone 2 counts as 1%; without zone 3 work it is 0.
+func polarizationIndex(z1Pct, z2Pct, z3Pct float64) float64 {
+	if z3Pct <= 0 || z1Pct <= 0 {
+		return 0
+	}
+	z2 := max(z2Pct/100, 0.01)
+	return math.Round(math.Log10(z1Pct/100/z2*(z3Pct/100)*100)*100) / 100
+}
+
 func buildAnalyzeZonesOutput(rows []hrZoneRow, zoneType, filter string) AnalyzeZonesOutput {
 	output := AnalyzeZonesOutput{
 		ZoneType: zoneType,
@@ -508,6 +654,9 @@ func buildAnalyzeZonesOutput(rows []hrZoneRow, zoneType, filter string) AnalyzeZ
 	// Generate insights
 	generator := NewInsightGenerator()
 	output.Insights = generator.GenerateZoneInsights(zonePercentages)
+	if output.Intensity = intensityDistribution(zonePercentages); output.Intensity != nil {
+		output.Insights = append(output.Insights, generator.GenerateIntensityInsights(*output.Intensity)...)
+	}
 	output.SuggestedActions = SuggestNextActions("zones")
 
 	return output
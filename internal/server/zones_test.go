@@ -17,6 +17,9 @@ type MockZonesQuerier struct {
 	activitiesWithZones []db.GetActivitiesWithZonesRow
 	hrZoneSummary       []db.GetHeartRateZoneSummaryRow
 	powerZoneSummary    []db.GetPowerZoneSummaryRow
+	zoneSources         []db.GetZoneSourceCountsRow
+	paceZoneSummary     []db.GetPaceZoneSummaryRow
+	paceArg             db.GetPaceZoneSummaryParams
 }
 
 func (m *MockZonesQuerier) GetActivityZones(ctx context.Context, activityID int64) ([]db.ActivityZone, error) {
@@ -133,6 +136,15 @@ func (m *MockZonesQuerier) GetPowerZoneSummaryInRange(ctx context.Context, arg d
 	return result, nil
 }
 
+func (m *MockZonesQuerier) GetZoneSourceCounts(ctx context.Context, zoneType string) ([]db.GetZoneSourceCountsRow, error) {
+	return m.zoneSources, nil
+}
+
+func (m *MockZonesQuerier) GetPaceZoneSummary(ctx context.Context, arg db.GetPaceZoneSummaryParams) ([]db.GetPaceZoneSummaryRow, error) {
+	m.paceArg = arg
+	return m.paceZoneSummary, nil
+}
+
 // Test GetActivityZones tool
 func TestGetActivityZones(t *testing.T) {
 	t.Parallel()
@@ -199,6 +211,42 @@ func TestGetActivityZones(t *testing.T) {
 	}
 }
 
+func TestGetActivityZonesLocal(t *testing.T) {
+	t.Parallel()
+
+	mock := &MockZonesQuerier{
+		MockQuerier: MockQuerier{
+			activities: []db.Activity{
+				{ID: 123, Name: "Morning Run", Type: sql.NullString{String: "Run", Valid: true}},
+			},
+		},
+		activityZones: []db.ActivityZone{
+			{ID: 1, ActivityID: 123, ZoneType: "heartrate", SensorBased: 1, Source: "local"},
+			{ID: 2, ActivityID: 123, ZoneType: "power", SensorBased: 1, Source: "local"}, // no power meter
+		},
+		zoneBuckets: map[int64][]db.ZoneBucket{
+			1: {
+				{ID: 1, ActivityZoneID: 1, ZoneNumber: 1, MinValue: 0, MaxValue: 120, TimeSeconds: 600},
+				{ID: 2, ActivityZoneID: 1, ZoneNumber: 2, MinValue: 120, MaxValue: -1, TimeSeconds: 900},
+			},
+		},
+	}
+
+	srv := New(mock)
+	_, output, err := srv.getActivityZones(context.Background(), nil, GetActivityZonesInput{ActivityID: 123})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The empty power row is skipped rather than reported as 0s in every zone
+	if len(output.Zones) != 1 {
+		t.Fatalf("expected 1 zone type, got %d", len(output.Zones))
+	}
+	if output.Zones[0].Source != "local" {
+		t.Errorf("expected source local, got %q", output.Zones[0].Source)
+	}
+}
+
 func TestGetActivityZonesNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -271,6 +319,11 @@ func TestAnalyzeZonesHeartrate(t *testing.T) {
 		},
 	}
 
+	mock.zoneSources = []db.GetZoneSourceCountsRow{
+		{Source: "local", ActivityCount: 4},
+		{Source: "strava", ActivityCount: 2},
+	}
+
 	srv := New(mock)
 	ctx := context.Background()
 
@@ -279,6 +332,10 @@ func TestAnalyzeZonesHeartrate(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
+	if output.Sources["local"] != 4 || output.Sources["strava"] != 2 {
+		t.Errorf("expected 4 local and 2 strava activities, got %v", output.Sources)
+	}
+
 	if output.ZoneType != "heartrate" {
 		t.Errorf("expected zone type 'heartrate', got %q", output.ZoneType)
 	}
@@ -326,6 +383,118 @@ func TestAnalyzeZonesPower(t *testing.T) {
 	}
 }
 
+func TestAnalyzeZonesPace(t *testing.T) {
+	t.Parallel()
+
+	mock := &MockZonesQuerier{
+		paceZoneSummary: []db.GetPaceZoneSummaryRow{
+			{ZoneNumber: 1, TotalTime: sql.NullFloat64{Float64: 4000, Valid: true}, AvgTime: sql.NullFloat64{Float64: 1000, Valid: true}, ActivityCount: 4},
+			{ZoneNumber: 2, TotalTime: sql.NullFloat64{Float64: 4000, Valid: true}, AvgTime: sql.NullFloat64{Float64: 1000, Valid: true}, ActivityCount: 4},
+			{ZoneNumber: 3, TotalTime: sql.NullFloat64{Float64: 500, Valid: true}, AvgTime: sql.NullFloat64{Float64: 125, Valid: true}, ActivityCount: 4},
+			{ZoneNumber: 4, TotalTime: sql.NullFloat64{Float64: 1000, Valid: true}, AvgTime: sql.NullFloat64{Float64: 250, Valid: true}, ActivityCount: 4},
+			{ZoneNumber: 5, TotalTime: sql.NullFloat64{Float64: 500, Valid: true}, AvgTime: sql.NullFloat64{Float64: 125, Valid: true}, ActivityCount: 4},
+		},
+	}
+
+	srv := New(mock)
+	_, output, err := srv.analyzeZones(context.Background(), nil, AnalyzeZonesInput{ZoneType: "pace", Type: "Run", StartDate: "2024-01-01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !mock.paceArg.Type.Valid || mock.paceArg.Type.String != "Run" || !mock.paceArg.StartDate.Valid || mock.paceArg.StartDate_2.Valid {
+		t.Errorf("unexpected query filters: %+v", mock.paceArg)
+	}
+	if output.ZoneType != "pace" || len(output.Zones) != 5 {
+		t.Fatalf("expected 5 pace zones, got %+v", output)
+	}
+
+	// 80/5/15 is textbook polarized
+	d := output.Intensity
+	if d == nil {
+		t.Fatal("expected an intensity distribution")
+	}
+	if d.Zone1Pct != 80 || d.Zone2Pct != 5 || d.Zone3Pct != 15 {
+		t.Errorf("expected 80/5/15, got %.1f/%.1f/%.1f", d.Zone1Pct, d.Zone2Pct, d.Zone3Pct)
+	}
+	if d.Model != "polarized" || d.PolarizationIndex != 2.38 {
+		t.Errorf("expected polarized with index 2.38, got %s %.2f", d.Model, d.PolarizationIndex)
+	}
+	found := false
+	for _, insight := range output.Insights {
+		if containsSubstring(insight.Message, "Polarized") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a polarization insight, got %+v", output.Insights)
+	}
+}
+
+func TestAnalyzeZonesInvalidZoneType(t *testing.T) {
+	t.Parallel()
+
+	srv := New(&MockZonesQuerier{})
+	if _, _, err := srv.analyzeZones(context.Background(), nil, AnalyzeZonesInput{ZoneType: "cadence"}); err == nil {
+		t.Error("expected an error for an unknown zone type")
+	}
+}
+
+func TestIntensityDistribution(t *testing.T) {
+	tests := []struct {
+		name  string
+		zones map[int]float64
+		model string
+	}{
+		{"polarized", map[int]float64{1: 50, 2: 30, 3: 5, 4: 10, 5: 5}, "polarized"},
+		{"pyramidal", map[int]float64{1: 40, 2: 35, 3: 15, 4: 7, 5: 3}, "pyramidal"},
+		{"threshold", map[int]float64{1: 20, 2: 20, 3: 45, 4: 10, 5: 5}, "threshold"},
+		{"high intensity", map[int]float64{1: 20, 2: 10, 3: 10, 4: 40, 5: 20}, "high_intensity"},
+		{"balanced", map[int]float64{1: 40, 2: 20, 3: 20, 4: 15, 5: 5}, "balanced"},
+		{"seven power zones", map[int]float64{1: 30, 2: 42, 3: 16, 4: 7, 5: 3, 6: 1, 7: 1}, "pyramidal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := intensityDistribution(tt.zones)
+			if d == nil || d.Model != tt.model {
+				t.Errorf("expected %s, got %+v", tt.model, d)
+			}
+		})
+	}
+
+	if d := intensityDistribution(map[int]float64{1: 50, 2: 30, 3: 20}); d != nil {
+		t.Errorf("expected no distribution for three zones, got %+v", d)
+	}
+}
+
+func TestPolarizationIndex(t *testing.T) {
+	if pi := polarizationIndex(80, 0, 20); pi != 3.2 {
+		t.Errorf("expected an empty zone 2 to count as 1%%, got %.2f", pi)
+	}
+	if pi := polarizationIndex(90, 10, 0); pi != 0 {
+		t.Errorf("expected 0 without zone 3, got %.2f", pi)
+	}
+}
+
+func TestPaceRange(t *testing.T) {
+	tests := []struct {
+		min, max int64
+		want     string
+	}{
+		{0, 3101, "slower than 5:22/km"},
+		{3101, 3509, "5:22/km - 4:44/km"},
+		{4040, -1, "faster than 4:07/km"},
+	}
+	for _, tt := range tests {
+		if got := paceRange("pace", tt.min, tt.max); got != tt.want {
+			t.Errorf("paceRange(%d, %d) = %q, expected %q", tt.min, tt.max, got, tt.want)
+		}
+	}
+	if got := paceRange("heartrate", 120, 150); got != "" {
+		t.Errorf("expected no pace range for heart rate zones, got %q", got)
+	}
+}
+
 func TestAnalyzeZonesDefaultsToHeartrate(t *testing.T) {
 	t.Parallel()
 
@@ -1,9 +1,12 @@
 package strava
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
 	"strconv"
@@ -30,23 +33,39 @@ const (
 
 // Activity represents a Strava activity from the API
 type Activity struct {
-	ID                 int64     `json:"id"`
-	Name               string    `json:"name"`
-	Distance           float64   `json:"distance"`
-	MovingTime         int       `json:"moving_time"`
-	ElapsedTime        int       `json:"elapsed_time"`
-	TotalElevationGain float64   `json:"total_elevation_gain"`
-	Type               string    `json:"type"`
-	SportType          string    `json:"sport_type"`
-	StartDate          time.Time `json:"start_date"`
-	StartDateLocal     time.Time `json:"start_date_local"`
-	Timezone           string    `json:"timezone"`
-	AverageSpeed       float64   `json:"average_speed"`
-	MaxSpeed           float64   `json:"max_speed"`
-	AverageCadence     float64   `json:"average_cadence"`
-	AverageHeartrate   float64   `json:"average_heartrate"`
-	MaxHeartrate       float64   `json:"max_heartrate"`
-	Kilojoules         float64   `json:"kilojoules"`
+	ID                 int64       `json:"id"`
+	Name               string      `json:"name"`
+	Distance           float64     `json:"distance"`
+	MovingTime         int         `json:"moving_time"`
+	ElapsedTime        int         `json:"elapsed_time"`
+	TotalElevationGain float64     `json:"total_elevation_gain"`
+	Type               string      `json:"type"`
+	SportType          string      `json:"sport_type"`
+	StartDate          time.Time   `json:"start_date"`
+	StartDateLocal     time.Time   `json:"start_date_local"`
+	Timezone           string      `json:"timezone"`
+	AverageSpeed       float64     `json:"average_speed"`
+	MaxSpeed           float64     `json:"max_speed"`
+	AverageCadence     float64     `json:"average_cadence"`
+	AverageHeartrate   float64     `json:"average_heartrate"`
+	MaxHeartrate       float64     `json:"max_heartrate"`
+	Kilojoules         float64     `json:"kilojoules"`
+	StartLatlng        []float64   `json:"start_latlng"`
+	EndLatlng          []float64   `json:"end_latlng"`
+	Map                PolylineMap `json:"map"`
+	KudosCount         int         `json:"kudos_count"`
+	CommentCount       int         `json:"comment_count"`
+	AthleteCount       int         `json:"athlete_count"` // athletes on the activity, 1 when solo
+	// WorkoutType is Strava's workout tag: 0 default, 1 race, 2 long run, 3
+	// workout for runs; 10 default, 11 race, 12 workout for rides. nil when
+	// the athlete never set one.
+	WorkoutType *int `json:"workout_type"`
+}
+
+// PolylineMap holds the encoded route geometry attached to an activity
+type PolylineMap struct {
+	ID              string `json:"id"`
+	SummaryPolyline string `json:"summary_polyline"`
 }
 
 // ActivityZone represents zone data from the Strava API
@@ -63,6 +82,65 @@ type TimedZoneRange struct {
 	Time float64 `json:"time"` // seconds (API returns float)
 }
 
+// ActivityStreams holds the per-sample time series for an activity, as
+// returned by the streams endpoint with key_by_type=true. Streams the device
+// did not record are nil.
+type ActivityStreams struct {
+	Time           *Stream[int]        `json:"time,omitempty"`
+	Distance       *Stream[float64]    `json:"distance,omitempty"`
+	Latlng         *Stream[[2]float64] `json:"latlng,omitempty"`
+	Altitude       *Stream[float64]    `json:"altitude,omitempty"`
+	VelocitySmooth *Stream[float64]    `json:"velocity_smooth,omitempty"`
+	Heartrate      *Stream[float64]    `json:"heartrate,omitempty"`
+	Cadence        *Stream[float64]    `json:"cadence,omitempty"`
+	Watts          *Stream[float64]    `json:"watts,omitempty"`
+	GradeSmooth    *Stream[float64]    `json:"grade_smooth,omitempty"`
+	Moving         *Stream[bool]       `json:"moving,omitempty"`
+}
+
+// Stream is a single time series; Data is aligned by index with the other
+// streams of the same activity
+type Stream[T any] struct {
+	Data         []T    `json:"data"`
+	SeriesType   string `json:"series_type"`
+	OriginalSize int    `json:"original_size"`
+	Resolution   string `json:"resolution"`
+}
+
+// AthleteZones holds the athlete's configured heart rate and power zones
+type AthleteZones struct {
+	HeartRate *AthleteZoneSet `json:"heart_rate,omitempty"`
+	Power     *AthleteZoneSet `json:"power,omitempty"`
+}
+
+// AthleteZoneSet is one zone type's ranges. Max is -1 for the top zone.
+type AthleteZoneSet struct {
+	CustomZones bool        `json:"custom_zones"`
+	Zones       []ZoneRange `json:"zones"`
+}
+
+// ZoneRange is the bounds of a single zone
+type ZoneRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// ActivityUpdate is the set of activity fields Strava lets the owner change.
+// Nil fields are left as they are; GearID "none" removes the gear.
+type ActivityUpdate struct {
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	Type         *string `json:"type,omitempty"`
+	SportType    *string `json:"sport_type,omitempty"`
+	GearID       *string `json:"gear_id,omitempty"`
+	Commute      *bool   `json:"commute,omitempty"`
+	Trainer      *bool   `json:"trainer,omitempty"`
+	HideFromHome *bool   `json:"hide_from_home,omitempty"`
+}
+
+// streamKeys is the set of streams requested for every activity
+const streamKeys = "time,distance,latlng,altitude,velocity_smooth,heartrate,cadence,watts,grade_smooth,moving"
+
 // RateLimitInfo contains rate limit information from the API
 type RateLimitInfo struct {
 	Limit15Min    int
@@ -156,6 +234,14 @@ var ErrPremiumRequired = fmt.Errorf("strava summit subscription required")
 // ErrRateLimited indicates the API returned a 429 rate limit error
 var ErrRateLimited = fmt.Errorf("rate limited")
 
+// ErrMissingScope indicates the access token lacks the OAuth scope the
+// endpoint needs; re-authenticating grants the current scopes
+var ErrMissingScope = fmt.Errorf("access token missing required scope")
+
+// ErrNotFound indicates Strava has no such resource, or it belongs to
+// another athlete
+var ErrNotFound = fmt.Errorf("not found on strava")
+
 // Client is a Strava API client with automatic retry and backoff
 type Client struct {
 	httpClient  *retryablehttp.Client
@@ -163,6 +249,7 @@ type Client struct {
 	baseURL     string
 	rateMu      sync.RWMutex
 	rateLimit   RateLimitInfo
+	governed    *governedTransport // nil without a governor
 }
 
 // RetryConfig holds retry/backoff settings
@@ -211,6 +298,11 @@ func newClientWithConfig(accessToken, baseURL string, maxRetries int, minWait, m
 			return false, ctx.Err()
 		}
 
+		// The shared budget has no room left; retrying would not find any
+		if errors.Is(err, ErrBudgetExhausted) {
+			return false, err
+		}
+
 		// Retry on connection errors
 		if err != nil {
 			return true, nil
@@ -320,11 +412,18 @@ func newClientWithConfig(accessToken, baseURL string, maxRetries int, minWait, m
 		}
 	}
 
-	return &Client{
+	c := &Client{
 		httpClient:  client,
 		accessToken: accessToken,
 		baseURL:     baseURL,
 	}
+
+	// Every attempt, retries included, comes out of the shared budget
+	if g := currentGovernor(); g != nil {
+		c.governed = &governedTransport{base: client.HTTPClient.Transport, governor: g, priority: PrioritySync}
+		client.HTTPClient.Transport = c.governed
+	}
+	return c
 }
 
 // WithRetryConfig sets custom retry configuration (useful for testing)
@@ -335,6 +434,15 @@ func (c *Client) WithRetryConfig(maxRetries int, initialBackoff, maxBackoff time
 	return c
 }
 
+// WithPriority sets the priority the client's requests are budgeted under
+// when a governor is set. Clients default to PrioritySync.
+func (c *Client) WithPriority(priority Priority) *Client {
+	if c.governed != nil {
+		c.governed.priority = priority
+	}
+	return c
+}
+
 // GetRateLimit returns the current rate limit info (with recalculated reset times)
 func (c *Client) GetRateLimit() RateLimitInfo {
 	c.rateMu.RLock()
@@ -410,7 +518,7 @@ func (c *Client) FetchAllActivities(ctx context.Context, progress ProgressCallba
 	page := 1
 
 	for {
-		activities, rateLimit, err := c.fetchActivitiesPage(ctx, page, 0)
+		activities, rateLimit, err := c.fetchActivitiesPage(ctx, page, 0, 0)
 
 		result := FetchResult{
 			Activities:   activities,
@@ -440,12 +548,22 @@ func (c *Client) FetchAllActivities(ctx context.Context, progress ProgressCallba
 
 // FetchActivitiesSince fetches activities since a given timestamp (for delta sync)
 func (c *Client) FetchActivitiesSince(ctx context.Context, since time.Time, progress ProgressCallback) ([]Activity, error) {
+	return c.FetchActivitiesBetween(ctx, since, time.Time{}, progress)
+}
+
+// FetchActivitiesBetween fetches activities that started after one time and
+// before another. A zero before leaves the window open to now.
+func (c *Client) FetchActivitiesBetween(ctx context.Context, after, before time.Time, progress ProgressCallback) ([]Activity, error) {
 	var allActivities []Activity
 	page := 1
-	afterEpoch := since.Unix()
+	afterEpoch := after.Unix()
+	var beforeEpoch int64
+	if !before.IsZero() {
+		beforeEpoch = before.Unix()
+	}
 
 	for {
-		activities, rateLimit, err := c.fetchActivitiesPage(ctx, page, afterEpoch)
+		activities, rateLimit, err := c.fetchActivitiesPage(ctx, page, afterEpoch, beforeEpoch)
 
 		result := FetchResult{
 			Activities:   activities,
@@ -473,6 +591,22 @@ func (c *Client) FetchActivitiesSince(ctx context.Context, since time.Time, prog
 	return allActivities, nil
 }
 
+// FetchActivitiesPage fetches a single page of the athlete's activities,
+// newest first, or oldest first from after when after is set. A non-zero
+// before leaves out activities that started from then on. An empty page is
+// past the last one.
+func (c *Client) FetchActivitiesPage(ctx context.Context, page int, after, before time.Time) ([]Activity, error) {
+	var afterEpoch, beforeEpoch int64
+	if !after.IsZero() {
+		afterEpoch = after.Unix()
+	}
+	if !before.IsZero() {
+		beforeEpoch = before.Unix()
+	}
+	activities, _, err := c.fetchActivitiesPage(ctx, page, afterEpoch, beforeEpoch)
+	return activities, err
+}
+
 // FetchActivityZones fetches zone data for a specific activity
 // Note: This endpoint requires Strava Summit (premium) subscription
 func (c *Client) FetchActivityZones(ctx context.Context, activityID int64) ([]ActivityZone, error) {
@@ -524,11 +658,187 @@ func (c *Client) FetchActivityZones(ctx context.Context, activityID int64) ([]Ac
 	return zones, nil
 }
 
-func (c *Client) fetchActivitiesPage(ctx context.Context, page int, after int64) ([]Activity, RateLimitInfo, error) {
+// FetchActivityStreams fetches the full-resolution streams for an activity.
+// Returns nil when Strava has no stream data (e.g. manual activities).
+func (c *Client) FetchActivityStreams(ctx context.Context, activityID int64) (*ActivityStreams, error) {
+	url := fmt.Sprintf("%s/activities/%d/streams?keys=%s&key_by_type=true", c.baseURL, activityID, streamKeys)
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.updateRateLimit(resp)
+
+	// 429 handled by retryablehttp, but if we still get here after retries exhausted
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, ErrRateLimited
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil // No stream data available for this activity
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var streams ActivityStreams
+	if err := json.NewDecoder(resp.Body).Decode(&streams); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return &streams, nil
+}
+
+// FetchAthleteZones fetches the authenticated athlete's heart rate and power
+// zones. Requires the profile:read_all scope; tokens granted before that
+// scope was requested get ErrMissingScope.
+func (c *Client) FetchAthleteZones(ctx context.Context) (*AthleteZones, error) {
+	url := fmt.Sprintf("%s/athlete/zones", c.baseURL)
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.updateRateLimit(resp)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, ErrRateLimited
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, ErrMissingScope
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var zones AthleteZones
+	if err := json.NewDecoder(resp.Body).Decode(&zones); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return &zones, nil
+}
+
+// UpdateActivity changes an activity on Strava and returns it as updated.
+// Requires the activity:write scope; tokens granted before that scope was
+// requested get ErrMissingScope.
+func (c *Client) UpdateActivity(ctx context.Context, activityID int64, update ActivityUpdate) (*Activity, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("encoding update: %w", err)
+	}
+
+	var activity Activity
+	url := fmt.Sprintf("%s/activities/%d", c.baseURL, activityID)
+	if err := c.send(ctx, http.MethodPut, url, "application/json", body, &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// FetchActivity fetches a single activity
+func (c *Client) FetchActivity(ctx context.Context, activityID int64) (*Activity, error) {
+	var activity Activity
+	url := fmt.Sprintf("%s/activities/%d", c.baseURL, activityID)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// send makes a request and decodes the JSON response into out, or reads the
+// body as is when out is a *[]byte. 401 and 403 are ErrMissingScope and 404
+// is ErrNotFound; any other status outside 2xx is an error.
+//
+// A POST is sent once: Strava may already have created the activity or
+// upload when a timeout or 5xx comes back, so retrying could create a
+// duplicate, and a 429 is returned as ErrRateLimited rather than waited out.
+func (c *Client) send(ctx context.Context, method, url, contentType string, body []byte, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	var resp *http.Response
+	if method == http.MethodPost {
+		resp, err = c.httpClient.HTTPClient.Do(req)
+	} else {
+		var retryReq *retryablehttp.Request
+		if retryReq, err = retryablehttp.FromRequest(req); err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		resp, err = c.httpClient.Do(retryReq)
+	}
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.updateRateLimit(resp)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrMissingScope
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		// Strava explains rejected fields in the body
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		if *raw, err = io.ReadAll(resp.Body); err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) fetchActivitiesPage(ctx context.Context, page int, after, before int64) ([]Activity, RateLimitInfo, error) {
 	url := fmt.Sprintf("%s/athlete/activities?page=%d&per_page=%d", c.baseURL, page, perPage)
 	if after > 0 {
 		url += fmt.Sprintf("&after=%d", after)
 	}
+	if before > 0 {
+		url += fmt.Sprintf("&before=%d", before)
+	}
 
 	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -408,3 +408,136 @@ func TestFetchActivitiesSince(t *testing.T) {
 		t.Errorf("expected 1 activity, got %d", len(result))
 	}
 }
+
+func TestFetchActivityStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/activities/404/streams" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path != "/activities/42/streams" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("key_by_type") != "true" {
+			t.Error("expected key_by_type=true")
+		}
+		w.Write([]byte(`{
+			"time": {"data": [0, 1, 2], "series_type": "distance", "original_size": 3, "resolution": "high"},
+			"latlng": {"data": [[37.1, -122.1], [37.2, -122.2], [37.3, -122.3]]},
+			"heartrate": {"data": [120, 125, 130]},
+			"moving": {"data": [false, true, true]}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+
+	streams, err := client.FetchActivityStreams(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if streams.Time == nil || len(streams.Time.Data) != 3 || streams.Time.Resolution != "high" {
+		t.Errorf("unexpected time stream: %+v", streams.Time)
+	}
+	if streams.Latlng == nil || streams.Latlng.Data[2] != [2]float64{37.3, -122.3} {
+		t.Errorf("unexpected latlng stream: %+v", streams.Latlng)
+	}
+	if streams.Heartrate == nil || streams.Heartrate.Data[1] != 125 {
+		t.Errorf("unexpected heartrate stream: %+v", streams.Heartrate)
+	}
+	if streams.Moving == nil || streams.Moving.Data[0] {
+		t.Errorf("unexpected moving stream: %+v", streams.Moving)
+	}
+	if streams.Watts != nil {
+		t.Error("expected watts stream to be absent")
+	}
+
+	streams, err = client.FetchActivityStreams(context.Background(), 404)
+	if err != nil {
+		t.Fatalf("unexpected error for missing activity: %v", err)
+	}
+	if streams != nil {
+		t.Error("expected nil streams for 404")
+	}
+}
+
+func TestFetchAthleteZones(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer old-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/athlete/zones" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"heart_rate": {"custom_zones": true, "zones": [
+				{"min": 0, "max": 125}, {"min": 125, "max": 150}, {"min": 150, "max": 165},
+				{"min": 165, "max": 180}, {"min": 180, "max": -1}
+			]}
+		}`))
+	}))
+	defer server.Close()
+
+	zones, err := NewClientWithBaseURL("test-token", server.URL).FetchAthleteZones(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zones.HeartRate == nil || len(zones.HeartRate.Zones) != 5 || !zones.HeartRate.CustomZones {
+		t.Fatalf("unexpected heart rate zones: %+v", zones.HeartRate)
+	}
+	if zones.HeartRate.Zones[4] != (ZoneRange{Min: 180, Max: -1}) {
+		t.Errorf("unexpected top zone: %+v", zones.HeartRate.Zones[4])
+	}
+	if zones.Power != nil {
+		t.Error("expected no power zones")
+	}
+
+	_, err = NewClientWithBaseURL("old-token", server.URL).FetchAthleteZones(context.Background())
+	if err != ErrMissingScope {
+		t.Errorf("expected ErrMissingScope, got %v", err)
+	}
+}
+
+func TestUpdateActivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") == "Bearer read-token":
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		case r.URL.Path == "/activities/404":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method != http.MethodPut || r.URL.Path != "/activities/123" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		// Only the fields being changed are sent
+		if len(body) != 2 || body["name"] != "Tempo 10k" || body["commute"] != false {
+			t.Errorf("unexpected body: %v", body)
+		}
+		w.Write([]byte(`{"id": 123, "name": "Tempo 10k", "type": "Run", "sport_type": "Run", "distance": 10000}`))
+	}))
+	defer server.Close()
+
+	name, commute := "Tempo 10k", false
+	update := ActivityUpdate{Name: &name, Commute: &commute}
+	activity, err := NewClientWithBaseURL("test-token", server.URL).UpdateActivity(context.Background(), 123, update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if activity.ID != 123 || activity.Name != "Tempo 10k" {
+		t.Errorf("unexpected activity: %+v", activity)
+	}
+
+	if _, err := NewClientWithBaseURL("read-token", server.URL).UpdateActivity(context.Background(), 123, update); err != ErrMissingScope {
+		t.Errorf("expected ErrMissingScope, got %v", err)
+	}
+	if _, err := NewClientWithBaseURL("test-token", server.URL).UpdateActivity(context.Background(), 404, update); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
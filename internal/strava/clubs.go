@@ -0,0 +1,58 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// clubsPerPage is the most clubs or club activities fetched per request.
+// Strava only returns a club's most recent activities.
+const clubsPerPage = 200
+
+// Club is a club the athlete belongs to
+type Club struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	SportType   string `json:"sport_type"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Country     string `json:"country"`
+	Private     bool   `json:"private"`
+	MemberCount int    `json:"member_count"`
+	URL         string `json:"url"`
+}
+
+// ClubActivity is an activity in a club's feed. Strava leaves out the
+// activity ID and start date, and names the athlete by first name and last
+// initial only.
+type ClubActivity struct {
+	Athlete            AthleteSummary `json:"athlete"`
+	Name               string         `json:"name"`
+	Type               string         `json:"type"`
+	SportType          string         `json:"sport_type"`
+	Distance           float64        `json:"distance"`
+	MovingTime         int            `json:"moving_time"`
+	ElapsedTime        int            `json:"elapsed_time"`
+	TotalElevationGain float64        `json:"total_elevation_gain"`
+}
+
+// FetchAthleteClubs fetches the clubs the athlete belongs to
+func (c *Client) FetchAthleteClubs(ctx context.Context) ([]Club, error) {
+	clubs := make([]Club, 0)
+	url := fmt.Sprintf("%s/athlete/clubs?per_page=%d", c.baseURL, clubsPerPage)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &clubs); err != nil {
+		return nil, err
+	}
+	return clubs, nil
+}
+
+// FetchClubActivities fetches a club's most recent activities, newest first
+func (c *Client) FetchClubActivities(ctx context.Context, clubID int64) ([]ClubActivity, error) {
+	activities := make([]ClubActivity, 0)
+	url := fmt.Sprintf("%s/clubs/%d/activities?per_page=%d", c.baseURL, clubID, clubsPerPage)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &activities); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
@@ -0,0 +1,46 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchClubs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/athlete/clubs":
+			w.Write([]byte(`[{"id": 42, "name": "Riverside Runners", "sport_type": "running", "city": "Portland", "private": true, "member_count": 318}]`))
+		case "/clubs/42/activities":
+			if r.URL.Query().Get("per_page") != "200" {
+				t.Errorf("expected a full page requested, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"athlete": {"firstname": "Ana", "lastname": "L."}, "name": "Tempo Tuesday", "type": "Run", "sport_type": "Run", "distance": 10012.5, "moving_time": 2700, "elapsed_time": 2790, "total_elevation_gain": 45}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	clubs, err := client.FetchAthleteClubs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clubs) != 1 || clubs[0].ID != 42 || !clubs[0].Private || clubs[0].MemberCount != 318 {
+		t.Errorf("unexpected clubs: %+v", clubs)
+	}
+
+	activities, err := client.FetchClubActivities(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(activities) != 1 || activities[0].Athlete.Name() != "Ana L." || activities[0].MovingTime != 2700 || activities[0].Distance != 10012.5 {
+		t.Errorf("unexpected club activities: %+v", activities)
+	}
+
+	if _, err := client.FetchClubActivities(context.Background(), 7); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
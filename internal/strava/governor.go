@@ -0,0 +1,100 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Priority is the class a request is budgeted under. The top of each rate
+// limit window is held back for the classes above, so a backfill cannot use
+// up the requests a tool call or new activities need.
+type Priority int
+
+// Priority classes, lowest first
+const (
+	PriorityBackfill    Priority = iota // streams, laps, zones and other history
+	PrioritySync                        // new activities and other regular sync
+	PriorityInteractive                 // tool calls made for the user
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackfill:
+		return "backfill"
+	case PrioritySync:
+		return "sync"
+	case PriorityInteractive:
+		return "interactive"
+	}
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
+// ErrBudgetExhausted indicates the shared rate limit budget has no room left
+// for a request of its priority until the window or the day resets
+var ErrBudgetExhausted = fmt.Errorf("rate limit budget exhausted")
+
+// Governor shares one rate limit budget between every client in the process
+type Governor interface {
+	// Reserve takes one request from the budget for the priority, waiting
+	// for the next window when that is allowed, or returns ErrBudgetExhausted
+	Reserve(ctx context.Context, priority Priority) error
+	// Observe records the usage and limits Strava reported with a response
+	Observe(info RateLimitInfo)
+}
+
+var (
+	governorMu sync.RWMutex
+	governor   Governor
+)
+
+// SetGovernor makes every client created afterwards reserve each request,
+// retries included, from g. A nil g lets clients send requests unchecked.
+func SetGovernor(g Governor) {
+	governorMu.Lock()
+	governor = g
+	governorMu.Unlock()
+}
+
+func currentGovernor() Governor {
+	governorMu.RLock()
+	defer governorMu.RUnlock()
+	return governor
+}
+
+type priorityKey struct{}
+
+// ContextWithPriority sets the priority of requests made with ctx, in place
+// of the client's own
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// governedTransport reserves every request from the governor before sending
+// it and reports the rate limit headers of the response back
+type governedTransport struct {
+	base     http.RoundTripper
+	governor Governor
+	priority Priority
+}
+
+func (t *governedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	priority := t.priority
+	if p, ok := req.Context().Value(priorityKey{}).(Priority); ok {
+		priority = p
+	}
+	if err := t.governor.Reserve(req.Context(), priority); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	info := parseRateLimitHeaders(resp.Header, time.Now())
+	info.IsRateLimited = resp.StatusCode == http.StatusTooManyRequests
+	t.governor.Observe(info)
+	return resp, nil
+}
@@ -0,0 +1,67 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// recordingGovernor records the priority of each reservation and can refuse them
+type recordingGovernor struct {
+	reserved []Priority
+	observed []RateLimitInfo
+	refuse   bool
+}
+
+func (g *recordingGovernor) Reserve(ctx context.Context, priority Priority) error {
+	if g.refuse {
+		return ErrBudgetExhausted
+	}
+	g.reserved = append(g.reserved, priority)
+	return nil
+}
+
+func (g *recordingGovernor) Observe(info RateLimitInfo) {
+	g.observed = append(g.observed, info)
+}
+
+func TestGovernedClient(t *testing.T) {
+	governor := &recordingGovernor{}
+	SetGovernor(governor)
+	defer SetGovernor(nil)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "200,2000")
+		w.Header().Set("X-RateLimit-Usage", "12,340")
+		w.Write([]byte(`{"id": 1, "name": "Morning Run"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL).WithPriority(PriorityBackfill)
+	ctx := context.Background()
+	if _, err := client.FetchActivity(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.FetchActivity(ContextWithPriority(ctx, PriorityInteractive), 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(governor.reserved) != 2 || governor.reserved[0] != PriorityBackfill || governor.reserved[1] != PriorityInteractive {
+		t.Errorf("expected a backfill then an interactive reservation, got %v", governor.reserved)
+	}
+	if len(governor.observed) != 2 || governor.observed[0].Usage15Min != 12 || governor.observed[0].LimitDaily != 2000 {
+		t.Errorf("expected the usage headers observed, got %+v", governor.observed)
+	}
+
+	// A refused reservation is neither sent nor retried
+	governor.refuse = true
+	if _, err := client.FetchActivity(ctx, 1); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected no request past the budget, got %d requests", requests)
+	}
+}
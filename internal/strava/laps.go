@@ -0,0 +1,38 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Lap is one lap of an activity, from the device's lap button or Strava's
+// automatic splits
+type Lap struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	LapIndex           int       `json:"lap_index"`
+	Distance           float64   `json:"distance"`
+	MovingTime         int       `json:"moving_time"`
+	ElapsedTime        int       `json:"elapsed_time"`
+	StartDate          time.Time `json:"start_date"`
+	TotalElevationGain float64   `json:"total_elevation_gain"`
+	AverageSpeed       float64   `json:"average_speed"`
+	MaxSpeed           float64   `json:"max_speed"`
+	AverageHeartrate   float64   `json:"average_heartrate"`
+	MaxHeartrate       float64   `json:"max_heartrate"`
+	AverageCadence     float64   `json:"average_cadence"`
+	AverageWatts       float64   `json:"average_watts"`
+}
+
+// FetchActivityLaps fetches an activity's laps. Activities without laps
+// (manual entries, some uploads) have none.
+func (c *Client) FetchActivityLaps(ctx context.Context, activityID int64) ([]Lap, error) {
+	var laps []Lap
+	url := fmt.Sprintf("%s/activities/%d/laps", c.baseURL, activityID)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &laps); err != nil {
+		return nil, err
+	}
+	return laps, nil
+}
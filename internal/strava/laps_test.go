@@ -0,0 +1,69 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchActivityLaps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/activities/42/laps":
+			w.Write([]byte(`[{"id": 1, "name": "Lap 1", "lap_index": 1, "distance": 1000, "moving_time": 240, "elapsed_time": 245,
+				"start_date": "2026-10-01T07:00:00Z", "average_speed": 4.17, "average_heartrate": 152.5},
+				{"id": 2, "name": "Lap 2", "lap_index": 2, "distance": 1000, "moving_time": 236, "elapsed_time": 236}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	laps, err := client.FetchActivityLaps(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(laps) != 2 || laps[0].LapIndex != 1 || laps[0].AverageHeartrate != 152.5 || laps[1].MovingTime != 236 {
+		t.Errorf("unexpected laps: %+v", laps)
+	}
+
+	if _, err := client.FetchActivityLaps(context.Background(), 43); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound for a missing activity, got %v", err)
+	}
+}
+
+func TestFetchActivitiesPage(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[{"id": 7, "name": "Morning Run", "type": "Run"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	after := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	activities, err := client.FetchActivitiesPage(context.Background(), 3, after, time.Time{})
+	if err != nil || len(activities) != 1 || activities[0].ID != 7 {
+		t.Fatalf("unexpected page: %+v (%v)", activities, err)
+	}
+	if want := "page=3&per_page=200&after=1790812800"; query != want {
+		t.Errorf("expected query %q, got %q", want, query)
+	}
+
+	if _, err := client.FetchActivitiesPage(context.Background(), 1, time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "page=1&per_page=200"; query != want {
+		t.Errorf("expected a full sync page without after, got %q", query)
+	}
+
+	if _, err := client.FetchActivitiesPage(context.Background(), 2, time.Time{}, after); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "page=2&per_page=200&before=1790812800"; query != want {
+		t.Errorf("expected a page ending at before, got %q", query)
+	}
+}
@@ -0,0 +1,120 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// libraryPerPage is the number of saved routes or starred segments fetched
+// per request
+const libraryPerPage = 100
+
+// Athlete is the authenticated athlete
+type Athlete struct {
+	ID        int64  `json:"id"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+}
+
+// Route is a route the athlete created or saved. Type is 1 for rides and 2
+// for runs; SubType is 1 road, 2 mountain bike, 3 cross, 4 trail or 5 mixed.
+type Route struct {
+	ID                  int64     `json:"id"`
+	Name                string    `json:"name"`
+	Description         string    `json:"description"`
+	Distance            float64   `json:"distance"`
+	ElevationGain       float64   `json:"elevation_gain"`
+	Type                int       `json:"type"`
+	SubType             int       `json:"sub_type"`
+	Private             bool      `json:"private"`
+	Starred             bool      `json:"starred"`
+	EstimatedMovingTime int       `json:"estimated_moving_time"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	Map                 struct {
+		SummaryPolyline string `json:"summary_polyline"`
+	} `json:"map"`
+}
+
+// Segment is a segment the athlete starred, with their own stats on it
+type Segment struct {
+	ID                  int64        `json:"id"`
+	Name                string       `json:"name"`
+	ActivityType        string       `json:"activity_type"`
+	Distance            float64      `json:"distance"`
+	AverageGrade        float64      `json:"average_grade"`
+	MaximumGrade        float64      `json:"maximum_grade"`
+	ElevationHigh       float64      `json:"elevation_high"`
+	ElevationLow        float64      `json:"elevation_low"`
+	StartLatlng         []float64    `json:"start_latlng"`
+	EndLatlng           []float64    `json:"end_latlng"`
+	ClimbCategory       int          `json:"climb_category"`
+	City                string       `json:"city"`
+	State               string       `json:"state"`
+	Country             string       `json:"country"`
+	Private             bool         `json:"private"`
+	AthleteSegmentStats SegmentStats `json:"athlete_segment_stats"`
+}
+
+// SegmentStats is the athlete's history on a segment. PRDate is a date,
+// YYYY-MM-DD, and empty without an effort.
+type SegmentStats struct {
+	PRElapsedTime *int   `json:"pr_elapsed_time"`
+	PRDate        string `json:"pr_date"`
+	PRActivityID  *int64 `json:"pr_activity_id"`
+	EffortCount   int    `json:"effort_count"`
+}
+
+// FetchAthlete fetches the authenticated athlete
+func (c *Client) FetchAthlete(ctx context.Context) (*Athlete, error) {
+	var athlete Athlete
+	if err := c.send(ctx, http.MethodGet, c.baseURL+"/athlete", "", nil, &athlete); err != nil {
+		return nil, err
+	}
+	return &athlete, nil
+}
+
+// FetchAthleteRoutes fetches every route the athlete created or saved.
+// Private routes need the read_all scope.
+func (c *Client) FetchAthleteRoutes(ctx context.Context, athleteID int64) ([]Route, error) {
+	routes := make([]Route, 0)
+	for page := 1; ; page++ {
+		var batch []Route
+		url := fmt.Sprintf("%s/athletes/%d/routes?page=%d&per_page=%d", c.baseURL, athleteID, page, libraryPerPage)
+		if err := c.send(ctx, http.MethodGet, url, "", nil, &batch); err != nil {
+			return nil, err
+		}
+		routes = append(routes, batch...)
+		if len(batch) < libraryPerPage {
+			return routes, nil
+		}
+	}
+}
+
+// ExportRouteGPX fetches a route as a GPX document
+func (c *Client) ExportRouteGPX(ctx context.Context, routeID int64) ([]byte, error) {
+	var gpx []byte
+	url := fmt.Sprintf("%s/routes/%d/export_gpx", c.baseURL, routeID)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &gpx); err != nil {
+		return nil, err
+	}
+	return gpx, nil
+}
+
+// FetchStarredSegments fetches every segment the athlete starred
+func (c *Client) FetchStarredSegments(ctx context.Context) ([]Segment, error) {
+	segments := make([]Segment, 0)
+	for page := 1; ; page++ {
+		var batch []Segment
+		url := fmt.Sprintf("%s/segments/starred?page=%d&per_page=%d", c.baseURL, page, libraryPerPage)
+		if err := c.send(ctx, http.MethodGet, url, "", nil, &batch); err != nil {
+			return nil, err
+		}
+		segments = append(segments, batch...)
+		if len(batch) < libraryPerPage {
+			return segments, nil
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchRouteLibrary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/athlete":
+			w.Write([]byte(`{"id": 1234, "firstname": "Ana", "lastname": "Lopez"}`))
+		case "/athletes/1234/routes":
+			// A full first page, then the last one
+			if r.URL.Query().Get("page") == "1" {
+				routes := make([]string, libraryPerPage)
+				for i := range routes {
+					routes[i] = fmt.Sprintf(`{"id": %d, "name": "Route %d", "type": 2}`, i+1, i+1)
+				}
+				w.Write([]byte("[" + strings.Join(routes, ",") + "]"))
+				return
+			}
+			w.Write([]byte(`[{"id": 3141592653589793, "name": "River Loop", "distance": 10050.2, "elevation_gain": 42.5, "type": 2, "sub_type": 1,
+				"estimated_moving_time": 3000, "updated_at": "2026-09-01T10:00:00Z", "map": {"summary_polyline": "abc"}}]`))
+		case "/routes/3141592653589793/export_gpx":
+			w.Header().Set("Content-Type", "application/gpx+xml")
+			w.Write([]byte(`<?xml version="1.0"?><gpx></gpx>`))
+		case "/segments/starred":
+			w.Write([]byte(`[{"id": 99, "name": "Hill Climb", "activity_type": "Run", "distance": 800, "average_grade": 6.2,
+				"athlete_segment_stats": {"pr_elapsed_time": 245, "pr_date": "2026-05-02", "pr_activity_id": 555, "effort_count": 14}},
+				{"id": 100, "name": "Never Run", "activity_type": "Run", "athlete_segment_stats": {"pr_elapsed_time": null, "pr_date": null, "effort_count": 0}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	athlete, err := client.FetchAthlete(context.Background())
+	if err != nil || athlete.ID != 1234 {
+		t.Fatalf("unexpected athlete: %+v (%v)", athlete, err)
+	}
+
+	routes, err := client.FetchAthleteRoutes(context.Background(), athlete.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := routes[len(routes)-1]
+	if len(routes) != libraryPerPage+1 || last.ID != 3141592653589793 || last.Map.SummaryPolyline != "abc" || last.UpdatedAt.IsZero() {
+		t.Errorf("expected both pages of routes, got %d ending %+v", len(routes), last)
+	}
+
+	gpx, err := client.ExportRouteGPX(context.Background(), last.ID)
+	if err != nil || !strings.HasPrefix(string(gpx), "<?xml") {
+		t.Errorf("expected the GPX document, got %q (%v)", gpx, err)
+	}
+
+	segments, err := client.FetchStarredSegments(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segments) != 2 || *segments[0].AthleteSegmentStats.PRElapsedTime != 245 || *segments[0].AthleteSegmentStats.PRActivityID != 555 {
+		t.Errorf("unexpected segments: %+v", segments)
+	}
+	if stats := segments[1].AthleteSegmentStats; stats.PRElapsedTime != nil || stats.PRDate != "" {
+		t.Errorf("expected no PR on a segment never done, got %+v", stats)
+	}
+}
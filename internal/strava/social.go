@@ -0,0 +1,55 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// socialPerPage is the most kudos or comments fetched for an activity
+const socialPerPage = 200
+
+// AthleteSummary is the name Strava shows for another athlete: their first
+// name and last initial
+type AthleteSummary struct {
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+}
+
+// Name returns the athlete's display name
+func (a AthleteSummary) Name() string {
+	if a.Lastname == "" {
+		return a.Firstname
+	}
+	return a.Firstname + " " + a.Lastname
+}
+
+// Comment is a comment on an activity
+type Comment struct {
+	ID         int64          `json:"id"`
+	ActivityID int64          `json:"activity_id"`
+	Text       string         `json:"text"`
+	Athlete    AthleteSummary `json:"athlete"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// FetchActivityKudoers fetches the athletes who gave an activity kudos
+func (c *Client) FetchActivityKudoers(ctx context.Context, activityID int64) ([]AthleteSummary, error) {
+	kudoers := make([]AthleteSummary, 0)
+	url := fmt.Sprintf("%s/activities/%d/kudos?per_page=%d", c.baseURL, activityID, socialPerPage)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &kudoers); err != nil {
+		return nil, err
+	}
+	return kudoers, nil
+}
+
+// FetchActivityComments fetches the comments on an activity
+func (c *Client) FetchActivityComments(ctx context.Context, activityID int64) ([]Comment, error) {
+	comments := make([]Comment, 0)
+	url := fmt.Sprintf("%s/activities/%d/comments?per_page=%d", c.baseURL, activityID, socialPerPage)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
@@ -0,0 +1,46 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchActivitySocial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "200" {
+			t.Errorf("expected a full page requested, got %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/activities/123/kudos":
+			w.Write([]byte(`[{"firstname": "Ana", "lastname": "L."}, {"firstname": "Ben", "lastname": ""}]`))
+		case "/activities/123/comments":
+			w.Write([]byte(`[{"id": 7, "activity_id": 123, "text": "Great pace!", "athlete": {"firstname": "Ana", "lastname": "L."}, "created_at": "2026-10-17T09:00:00Z"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	kudoers, err := client.FetchActivityKudoers(context.Background(), 123)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kudoers) != 2 || kudoers[0].Name() != "Ana L." || kudoers[1].Name() != "Ben" {
+		t.Errorf("unexpected kudoers: %+v", kudoers)
+	}
+
+	comments, err := client.FetchActivityComments(context.Background(), 123)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Text != "Great pace!" || comments[0].Athlete.Name() != "Ana L." || comments[0].CreatedAt.IsZero() {
+		t.Errorf("unexpected comments: %+v", comments)
+	}
+
+	if _, err := client.FetchActivityKudoers(context.Background(), 404); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ActivityTotals are an athlete's totals for one sport over one period
+type ActivityTotals struct {
+	Count         int     `json:"count"`
+	Distance      float64 `json:"distance"`
+	MovingTime    int64   `json:"moving_time"`
+	ElapsedTime   int64   `json:"elapsed_time"`
+	ElevationGain float64 `json:"elevation_gain"`
+}
+
+// AthleteStats are an athlete's totals per sport for the last four weeks,
+// the year to date and all time. Strava only counts activities visible to
+// everyone.
+type AthleteStats struct {
+	RecentRunTotals  ActivityTotals `json:"recent_run_totals"`
+	YTDRunTotals     ActivityTotals `json:"ytd_run_totals"`
+	AllRunTotals     ActivityTotals `json:"all_run_totals"`
+	RecentRideTotals ActivityTotals `json:"recent_ride_totals"`
+	YTDRideTotals    ActivityTotals `json:"ytd_ride_totals"`
+	AllRideTotals    ActivityTotals `json:"all_ride_totals"`
+	RecentSwimTotals ActivityTotals `json:"recent_swim_totals"`
+	YTDSwimTotals    ActivityTotals `json:"ytd_swim_totals"`
+	AllSwimTotals    ActivityTotals `json:"all_swim_totals"`
+}
+
+// FetchAthleteStats fetches the athlete's activity totals. Strava only
+// serves the stats of the authenticated athlete.
+func (c *Client) FetchAthleteStats(ctx context.Context, athleteID int64) (*AthleteStats, error) {
+	var stats AthleteStats
+	url := fmt.Sprintf("%s/athletes/%d/stats", c.baseURL, athleteID)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
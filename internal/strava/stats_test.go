@@ -0,0 +1,57 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchAthleteStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/athletes/7/stats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"recent_run_totals": {"count": 12, "distance": 98000.5, "moving_time": 32000, "elapsed_time": 33000, "elevation_gain": 640},
+			"ytd_run_totals": {"count": 140, "distance": 1200000, "moving_time": 400000}, "all_ride_totals": {"count": 900}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	stats, err := client.FetchAthleteStats(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.RecentRunTotals.Count != 12 || stats.RecentRunTotals.Distance != 98000.5 || stats.YTDRunTotals.MovingTime != 400000 || stats.AllRideTotals.Count != 900 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if _, err := client.FetchAthleteStats(context.Background(), 8); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFetchActivitiesBetween(t *testing.T) {
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("after") != "1767225600" || query.Get("before") != "1769904000" {
+			t.Errorf("unexpected window: %s", r.URL.RawQuery)
+		}
+		if query.Get("page") == "1" {
+			w.Write([]byte(`[{"id": 1, "name": "January Run", "type": "Run"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	activities, err := client.FetchActivitiesBetween(context.Background(), after, before, nil)
+	if err != nil || len(activities) != 1 {
+		t.Errorf("expected one activity, got %+v (%v)", activities, err)
+	}
+}
@@ -0,0 +1,171 @@
+package strava
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ManualActivity is an activity entered by hand, without a recorded file
+type ManualActivity struct {
+	Name           string
+	SportType      string
+	StartDateLocal time.Time
+	ElapsedTime    int // seconds
+	Description    string
+	Distance       float64 // meters
+	Trainer        bool
+	Commute        bool
+}
+
+// UploadFile is an activity file to upload. DataType is one of fit, fit.gz,
+// tcx, tcx.gz, gpx or gpx.gz.
+type UploadFile struct {
+	Data        []byte
+	Filename    string
+	DataType    string
+	Name        string
+	Description string
+	Trainer     bool
+	Commute     bool
+	ExternalID  string
+}
+
+// Upload is the processing status of an uploaded file. Strava processes
+// uploads asynchronously; ActivityID is set once the activity exists and
+// Error once processing failed, e.g. for a duplicate.
+type Upload struct {
+	ID         int64  `json:"id"`
+	IDStr      string `json:"id_str"`
+	ExternalID string `json:"external_id"`
+	Error      string `json:"error"`
+	Status     string `json:"status"`
+	ActivityID *int64 `json:"activity_id"`
+}
+
+// Done reports whether Strava has finished processing the upload
+func (u *Upload) Done() bool {
+	return u.ActivityID != nil || u.Error != ""
+}
+
+// ErrUploadPending is returned by WaitForUpload when the context ends before
+// Strava finishes processing
+var ErrUploadPending = fmt.Errorf("upload still processing")
+
+// CreateActivity creates a manual activity on Strava. Requires the
+// activity:write scope.
+func (c *Client) CreateActivity(ctx context.Context, manual ManualActivity) (*Activity, error) {
+	form := url.Values{}
+	form.Set("name", manual.Name)
+	form.Set("sport_type", manual.SportType)
+	form.Set("start_date_local", manual.StartDateLocal.Format("2006-01-02T15:04:05"))
+	form.Set("elapsed_time", strconv.Itoa(manual.ElapsedTime))
+	if manual.Description != "" {
+		form.Set("description", manual.Description)
+	}
+	if manual.Distance > 0 {
+		form.Set("distance", strconv.FormatFloat(manual.Distance, 'f', -1, 64))
+	}
+	if manual.Trainer {
+		form.Set("trainer", "1")
+	}
+	if manual.Commute {
+		form.Set("commute", "1")
+	}
+
+	var activity Activity
+	err := c.send(ctx, http.MethodPost, c.baseURL+"/activities", "application/x-www-form-urlencoded", []byte(form.Encode()), &activity)
+	if err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// UploadActivity uploads an activity file. The activity is created once
+// Strava has processed the file; see GetUpload and WaitForUpload. Requires
+// the activity:write scope.
+func (c *Client) UploadActivity(ctx context.Context, file UploadFile) (*Upload, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", file.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("encoding upload: %w", err)
+	}
+	if _, err := part.Write(file.Data); err != nil {
+		return nil, fmt.Errorf("encoding upload: %w", err)
+	}
+	fields := map[string]string{
+		"data_type":   file.DataType,
+		"name":        file.Name,
+		"description": file.Description,
+		"external_id": file.ExternalID,
+	}
+	if file.Trainer {
+		fields["trainer"] = "1"
+	}
+	if file.Commute {
+		fields["commute"] = "1"
+	}
+	for key, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := w.WriteField(key, value); err != nil {
+			return nil, fmt.Errorf("encoding upload: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("encoding upload: %w", err)
+	}
+
+	var upload Upload
+	if err := c.send(ctx, http.MethodPost, c.baseURL+"/uploads", w.FormDataContentType(), body.Bytes(), &upload); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// GetUpload fetches the processing status of an upload
+func (c *Client) GetUpload(ctx context.Context, uploadID int64) (*Upload, error) {
+	var upload Upload
+	url := fmt.Sprintf("%s/uploads/%d", c.baseURL, uploadID)
+	if err := c.send(ctx, http.MethodGet, url, "", nil, &upload); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// WaitForUpload polls an upload every interval until Strava has processed it.
+// When ctx ends first, the last status is returned with ErrUploadPending.
+func (c *Client) WaitForUpload(ctx context.Context, uploadID int64, interval time.Duration) (*Upload, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *Upload
+	for {
+		upload, err := c.GetUpload(ctx, uploadID)
+		switch {
+		case err == nil:
+			last = upload
+			if upload.Done() {
+				return upload, nil
+			}
+		case ctx.Err() == nil:
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if last == nil {
+				last = &Upload{ID: uploadID}
+			}
+			return last, ErrUploadPending
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package strava
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCreateActivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/activities" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.PostForm.Get("sport_type") == "Nope" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "Bad Request", "errors": [{"field": "sport_type", "code": "invalid"}]}`))
+			return
+		}
+		if r.PostForm.Get("name") != "Pool swim" || r.PostForm.Get("start_date_local") != "2026-10-17T07:30:00" ||
+			r.PostForm.Get("elapsed_time") != "2700" || r.PostForm.Get("distance") != "2000" || r.PostForm.Has("commute") {
+			t.Errorf("unexpected form: %v", r.PostForm)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 55, "name": "Pool swim", "type": "Swim", "sport_type": "Swim", "distance": 2000, "elapsed_time": 2700}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	manual := ManualActivity{
+		Name:           "Pool swim",
+		SportType:      "Swim",
+		StartDateLocal: time.Date(2026, 10, 17, 7, 30, 0, 0, time.UTC),
+		ElapsedTime:    2700,
+		Distance:       2000,
+	}
+	activity, err := client.CreateActivity(context.Background(), manual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if activity.ID != 55 || activity.SportType != "Swim" {
+		t.Errorf("unexpected activity: %+v", activity)
+	}
+
+	manual.SportType = "Nope"
+	if _, err := client.CreateActivity(context.Background(), manual); err == nil {
+		t.Error("expected an error for an invalid sport type")
+	}
+}
+
+func TestCreateActivityNotRetried(t *testing.T) {
+	var requests atomic.Int32
+	status := http.StatusBadGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	manual := ManualActivity{Name: "Pool swim", SportType: "Swim", StartDateLocal: time.Now(), ElapsedTime: 2700}
+
+	// Strava may have created the activity before the 502
+	if _, err := client.CreateActivity(context.Background(), manual); err == nil || requests.Load() != 1 {
+		t.Errorf("expected one request and an error, got %d requests (%v)", requests.Load(), err)
+	}
+
+	// A 429 comes straight back rather than waiting for the window
+	status = http.StatusTooManyRequests
+	if _, err := client.UploadActivity(context.Background(), UploadFile{Filename: "a.fit", DataType: "fit", Data: []byte("x")}); err != ErrRateLimited || requests.Load() != 2 {
+		t.Errorf("expected ErrRateLimited after one request, got %d requests (%v)", requests.Load(), err)
+	}
+}
+
+func TestUploadActivity(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/uploads":
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("missing file: %v", err)
+			}
+			data, _ := io.ReadAll(file)
+			if header.Filename != "ride.gpx" || string(data) != "<gpx/>" || r.FormValue("data_type") != "gpx" || r.FormValue("trainer") != "1" {
+				t.Errorf("unexpected upload: %s %q %v", header.Filename, data, r.MultipartForm.Value)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 9, "id_str": "9", "status": "Your activity is still being processed."}`))
+		case r.URL.Path == "/uploads/9":
+			if polls.Add(1) < 2 {
+				w.Write([]byte(`{"id": 9, "status": "Your activity is still being processed."}`))
+				return
+			}
+			w.Write([]byte(`{"id": 9, "status": "Your activity is ready.", "activity_id": 77}`))
+		case r.URL.Path == "/uploads/10":
+			w.Write([]byte(`{"id": 10, "status": "There was an error processing your activity.", "error": "duplicate of activity 77"}`))
+		default:
+			w.Write([]byte(`{"id": 11, "status": "Your activity is still being processed."}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	upload, err := client.UploadActivity(context.Background(), UploadFile{Data: []byte("<gpx/>"), Filename: "ride.gpx", DataType: "gpx", Trainer: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upload.ID != 9 || upload.Done() {
+		t.Errorf("expected a pending upload, got %+v", upload)
+	}
+
+	upload, err = client.WaitForUpload(context.Background(), 9, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upload.ActivityID == nil || *upload.ActivityID != 77 {
+		t.Errorf("expected activity 77, got %+v", upload)
+	}
+
+	upload, err = client.WaitForUpload(context.Background(), 10, time.Millisecond)
+	if err != nil || upload.Error == "" {
+		t.Errorf("expected a processing error, got %+v, %v", upload, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	upload, err = client.WaitForUpload(ctx, 11, 5*time.Millisecond)
+	if err != ErrUploadPending || upload == nil || upload.ID != 11 {
+		t.Errorf("expected the upload still pending, got %+v, %v", upload, err)
+	}
+}
@@ -0,0 +1,81 @@
+package streams
+
+import (
+	"math"
+	"sort"
+)
+
+// Segment summarizes the samples between two distances
+type Segment struct {
+	Time float64 // seconds taken, interpolated at both ends
+	// Heartrate and Watts are sample averages; NaN when not recorded
+	Heartrate float64
+	Watts     float64
+}
+
+// CanAlign reports whether the activity has the time and distance streams
+// needed to line it up against another by distance
+func (s *Streams) CanAlign() bool {
+	return len(s.Time) > 1 && len(s.Distance) == len(s.Time)
+}
+
+// TotalDistance returns the final recorded distance in meters
+func (s *Streams) TotalDistance() float64 {
+	if len(s.Distance) == 0 {
+		return 0
+	}
+	return s.Distance[len(s.Distance)-1]
+}
+
+// TimeAt returns the elapsed time in seconds when the activity reached
+// distance d, interpolating between samples. ok is false past the end or
+// without time and distance streams.
+func (s *Streams) TimeAt(d float64) (float64, bool) {
+	if !s.CanAlign() || d < 0 || d > s.TotalDistance() {
+		return 0, false
+	}
+	// Distance never decreases, so the first sample at or beyond d brackets it
+	i := sort.SearchFloat64s(s.Distance, d)
+	if i == 0 {
+		return float64(s.Time[0]), true
+	}
+	d0, d1 := s.Distance[i-1], s.Distance[i]
+	t0, t1 := float64(s.Time[i-1]), float64(s.Time[i])
+	if d1 == d0 {
+		return t1, true
+	}
+	return t0 + (t1-t0)*(d-d0)/(d1-d0), true
+}
+
+// Between summarizes the activity from distance d0 to d1
+func (s *Streams) Between(d0, d1 float64) (Segment, bool) {
+	t0, ok0 := s.TimeAt(d0)
+	t1, ok1 := s.TimeAt(d1)
+	if !ok0 || !ok1 {
+		return Segment{}, false
+	}
+
+	lo := sort.SearchFloat64s(s.Distance, d0)
+	hi := sort.SearchFloat64s(s.Distance, d1)
+	return Segment{
+		Time:      t1 - t0,
+		Heartrate: mean(s.Heartrate, lo, hi),
+		Watts:     mean(s.Watts, lo, hi),
+	}, true
+}
+
+// mean averages the positive values in values[lo:hi], NaN when there are none
+func mean(values []float64, lo, hi int) float64 {
+	hi = min(hi, len(values))
+	sum, n := 0.0, 0
+	for i := lo; i < hi; i++ {
+		if values[i] > 0 {
+			sum += values[i]
+			n++
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum / float64(n)
+}
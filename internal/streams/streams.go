@@ -0,0 +1,80 @@
+// Package streams decodes the per-sample activity time series stored in the
+// activity_streams table.
+package streams
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
+)
+
+// Streams holds the decoded time series of one activity. Slices are aligned
+// by index; a stream the device did not record is nil.
+type Streams struct {
+	ActivityID int64
+	Time       []int        // seconds since start
+	Distance   []float64    // meters since start
+	LatLng     [][2]float64 // [lat, lng]
+	Altitude   []float64    // meters
+	Velocity   []float64    // meters per second (smoothed)
+	Heartrate  []float64    // bpm
+	Cadence    []float64    // rpm, or steps per minute per leg for runs
+	Watts      []float64
+	Grade      []float64 // percent (smoothed)
+	Moving     []bool
+}
+
+// FromRow decodes a stored stream row
+func FromRow(row db.ActivityStream) (*Streams, error) {
+	s := &Streams{ActivityID: row.ActivityID}
+	fields := []struct {
+		name string
+		col  sql.NullString
+		dst  any
+	}{
+		{"time", row.TimeData, &s.Time},
+		{"distance", row.DistanceData, &s.Distance},
+		{"latlng", row.LatlngData, &s.LatLng},
+		{"altitude", row.AltitudeData, &s.Altitude},
+		{"velocity", row.VelocityData, &s.Velocity},
+		{"heartrate", row.HeartrateData, &s.Heartrate},
+		{"cadence", row.CadenceData, &s.Cadence},
+		{"watts", row.WattsData, &s.Watts},
+		{"grade", row.GradeData, &s.Grade},
+		{"moving", row.MovingData, &s.Moving},
+	}
+	for _, f := range fields {
+		if !f.col.Valid || f.col.String == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(f.col.String), f.dst); err != nil {
+			return nil, fmt.Errorf("decoding %s stream for activity %d: %w", f.name, row.ActivityID, err)
+		}
+	}
+	return s, nil
+}
+
+// Len returns the number of samples, taken from the time stream or, failing
+// that, the longest stream present
+func (s *Streams) Len() int {
+	if len(s.Time) > 0 {
+		return len(s.Time)
+	}
+	return max(len(s.Distance), len(s.LatLng), len(s.Altitude), len(s.Velocity),
+		len(s.Heartrate), len(s.Cadence), len(s.Watts), len(s.Grade), len(s.Moving))
+}
+
+// Points returns the GPS track, or nil when the activity has no latlng stream
+func (s *Streams) Points() []geo.Point {
+	if len(s.LatLng) == 0 {
+		return nil
+	}
+	points := make([]geo.Point, len(s.LatLng))
+	for i, ll := range s.LatLng {
+		points[i] = geo.Point{Lat: ll[0], Lng: ll[1]}
+	}
+	return points
+}
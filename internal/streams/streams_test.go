@@ -0,0 +1,129 @@
+package streams
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+func valid(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+func TestFromRow(t *testing.T) {
+	row := db.ActivityStream{
+		ActivityID:    42,
+		PointCount:    3,
+		TimeData:      valid("[0,5,10]"),
+		LatlngData:    valid("[[37.1,-122.1],[37.2,-122.2],[37.3,-122.3]]"),
+		HeartrateData: valid("[120,125,130]"),
+		MovingData:    valid("[false,true,true]"),
+	}
+
+	s, err := FromRow(row)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.ActivityID != 42 {
+		t.Errorf("ActivityID = %d, want 42", s.ActivityID)
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", s.Len())
+	}
+	if s.Time[2] != 10 {
+		t.Errorf("Time[2] = %d, want 10", s.Time[2])
+	}
+	if s.Heartrate[1] != 125 {
+		t.Errorf("Heartrate[1] = %v, want 125", s.Heartrate[1])
+	}
+	if s.Moving[0] || !s.Moving[1] {
+		t.Errorf("Moving = %v", s.Moving)
+	}
+	if s.Watts != nil {
+		t.Errorf("expected nil watts, got %v", s.Watts)
+	}
+
+	points := s.Points()
+	if len(points) != 3 || points[1].Lat != 37.2 || points[1].Lng != -122.2 {
+		t.Errorf("Points() = %v", points)
+	}
+}
+
+func TestFromRowEmpty(t *testing.T) {
+	s, err := FromRow(db.ActivityStream{ActivityID: 7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", s.Len())
+	}
+	if s.Points() != nil {
+		t.Error("expected nil points")
+	}
+}
+
+func TestFromRowInvalid(t *testing.T) {
+	_, err := FromRow(db.ActivityStream{ActivityID: 1, HeartrateData: valid("not json")})
+	if err == nil {
+		t.Error("expected error for malformed stream")
+	}
+}
+
+func TestTimeAt(t *testing.T) {
+	s := &Streams{
+		Time:     []int{0, 10, 20, 30},
+		Distance: []float64{0, 50, 50, 150},
+	}
+
+	tests := []struct {
+		d    float64
+		want float64
+		ok   bool
+	}{
+		{0, 0, true},
+		{25, 5, true},
+		{50, 10, true}, // the first sample to reach 50 m, not the stop after it
+		{100, 25, true},
+		{150, 30, true},
+		{151, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := s.TimeAt(tt.d)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("TimeAt(%v) = %v, %v; want %v, %v", tt.d, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := (&Streams{Time: []int{0, 1}}).TimeAt(0); ok {
+		t.Error("expected no alignment without a distance stream")
+	}
+}
+
+func TestBetween(t *testing.T) {
+	s := &Streams{
+		Time:      []int{0, 10, 20, 30, 40},
+		Distance:  []float64{0, 100, 200, 300, 400},
+		Heartrate: []float64{100, 120, 140, 160, 180},
+	}
+
+	seg, ok := s.Between(100, 300)
+	if !ok {
+		t.Fatal("expected a segment")
+	}
+	if seg.Time != 20 {
+		t.Errorf("Time = %v, want 20", seg.Time)
+	}
+	if seg.Heartrate != 130 {
+		t.Errorf("Heartrate = %v, want 130", seg.Heartrate)
+	}
+	if !math.IsNaN(seg.Watts) {
+		t.Errorf("Watts = %v, want NaN without a power stream", seg.Watts)
+	}
+
+	if _, ok := s.Between(300, 500); ok {
+		t.Error("expected no segment past the end")
+	}
+}
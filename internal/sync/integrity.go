@@ -0,0 +1,399 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+)
+
+// Integrity periods, matching Strava's athlete stats
+const (
+	PeriodRecent = "recent" // the last four weeks
+	PeriodYTD    = "ytd"
+	PeriodAll    = "all"
+)
+
+// recentStatsDays is the length of Strava's recent totals
+const recentStatsDays = 28
+
+// integritySports are the sports in Strava's athlete stats and the stored
+// activity type each is compared with
+var integritySports = []struct{ sport, activityType string }{
+	{"run", "Run"},
+	{"ride", "Ride"},
+	{"swim", "Swim"},
+}
+
+// IntegrityCheck compares Strava's totals for one sport and period with the
+// stored totals. WindowStart is zero for all time.
+type IntegrityCheck struct {
+	Sport            string
+	Period           string
+	WindowStart      time.Time
+	StravaCount      int64
+	LocalCount       int64
+	StravaDistance   float64
+	LocalDistance    float64
+	StravaMovingTime int64
+	LocalMovingTime  int64
+}
+
+// Missing is how many more activities Strava counts than are stored
+func (c IntegrityCheck) Missing() int64 {
+	return max(0, c.StravaCount-c.LocalCount)
+}
+
+// RepairWindow is a date window to resync. Missing is, per sport, how many
+// more activities Strava counts in just this window than are stored.
+type RepairWindow struct {
+	Period  string
+	Start   time.Time
+	End     time.Time
+	Missing map[string]int64
+}
+
+// RepairResult is one gap repair queued. What its pages fetch and store is
+// added to its entry in the repair log as they run.
+type RepairResult struct {
+	Reason      string
+	WindowStart time.Time
+	WindowEnd   time.Time
+}
+
+// IntegrityResult is what an integrity check found and queued to repair.
+// Queued is the activities that failed to save, queued to be fetched again.
+type IntegrityResult struct {
+	Checks  []IntegrityCheck
+	Repairs []RepairResult
+	Queued  int
+}
+
+// SaveActivity saves a synced activity with its kudos and comment counts. A
+// failed save is recorded so gap repair fetches the activity again; a
+// successful one clears any earlier failure.
+func SaveActivity(ctx context.Context, queries *db.Queries, activity strava.Activity) error {
+	if err := queries.CreateActivity(ctx, ConvertActivityToParams(activity)); err != nil {
+		now := time.Now().UTC().Truncate(time.Second)
+		recordErr := queries.RecordSyncFailure(ctx, db.RecordSyncFailureParams{
+			ActivityID:    activity.ID,
+			ActivityName:  toNullString(activity.Name),
+			StartDate:     toNullTime(activity.StartDate),
+			Error:         err.Error(),
+			FirstFailedAt: now,
+			LastFailedAt:  now,
+		})
+		if recordErr != nil {
+			logging.Warn("failed to record sync failure", "activity_id", activity.ID, "error", recordErr)
+		}
+		return err
+	}
+	if err := queries.UpsertActivitySocialCounts(ctx, ConvertSocialCountsToParams(activity)); err != nil {
+		logging.Warn("failed to save social counts", "activity_id", activity.ID, "error", err)
+	}
+	if err := queries.ClearSyncFailure(ctx, activity.ID); err != nil {
+		logging.Warn("failed to clear sync failure", "activity_id", activity.ID, "error", err)
+	}
+	return nil
+}
+
+// CheckIntegrity compares Strava's athlete stats with the stored totals and
+// queues what it can repair on the sync queue: activities that failed to
+// save are fetched again by ID, and date windows where Strava counts more
+// activities than are stored are listed again page by page. A window is not
+// resynced again until its gap grows, so gaps repair cannot close, such as
+// excluded activities, cost one resync.
+func (s *Service) CheckIntegrity(ctx context.Context) (IntegrityResult, error) {
+	var result IntegrityResult
+	now := time.Now().UTC().Truncate(time.Second)
+
+	athlete, err := s.client.FetchAthlete(ctx)
+	if err != nil {
+		return result, fmt.Errorf("fetching athlete: %w", err)
+	}
+	stats, err := s.client.FetchAthleteStats(ctx, athlete.ID)
+	if err != nil {
+		return result, fmt.Errorf("fetching athlete stats: %w", err)
+	}
+
+	loc := s.athleteLocation(ctx)
+	result.Checks, err = s.compareStats(ctx, stats, now, loc)
+	if err != nil {
+		return result, err
+	}
+	stored, err := s.queries.ListSyncIntegrity(ctx)
+	if err != nil {
+		return result, fmt.Errorf("listing integrity checks: %w", err)
+	}
+	repaired := make(map[string]int64, len(stored))
+	for _, row := range stored {
+		if row.RepairedMissing.Valid {
+			repaired[row.Sport+"/"+row.Period] = row.RepairedMissing.Int64
+		}
+	}
+
+	failures, err := s.queries.ListSyncFailures(ctx)
+	if err != nil {
+		return result, fmt.Errorf("listing sync failures: %w", err)
+	}
+	// The sync queue fetches them, leaving any already queued as they are
+	for _, f := range failures {
+		if err := EnqueueJob(ctx, s.queries, JobActivity, f.ActivityID, PriorityActivity, time.Time{}, now); err != nil {
+			return result, fmt.Errorf("queueing activity %d: %w", f.ActivityID, err)
+		}
+		result.Queued++
+	}
+
+	windows := RepairWindows(result.Checks, now, loc)
+	for _, w := range windows {
+		if !w.needsRepair(repaired) {
+			continue
+		}
+		repair, err := s.repairWindow(ctx, w, now)
+		if err != nil {
+			return result, err
+		}
+		result.Repairs = append(result.Repairs, repair)
+		for sport, missing := range w.Missing {
+			if missing > 0 {
+				repaired[sport+"/"+w.Period] = missing
+			}
+		}
+	}
+
+	if err := s.saveChecks(ctx, result.Checks, windows, repaired); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// compareStats pairs Strava's totals with the stored totals for each sport
+// and period. Stored activities count under the type Strava gave them, with
+// excluded ones included, since Strava's stats know nothing of local changes.
+// The year starts at midnight on January 1 where the athlete is.
+func (s *Service) compareStats(ctx context.Context, stats *strava.AthleteStats, now time.Time, loc *time.Location) ([]IntegrityCheck, error) {
+	totals := map[string][3]strava.ActivityTotals{
+		"run":  {stats.RecentRunTotals, stats.YTDRunTotals, stats.AllRunTotals},
+		"ride": {stats.RecentRideTotals, stats.YTDRideTotals, stats.AllRideTotals},
+		"swim": {stats.RecentSwimTotals, stats.YTDSwimTotals, stats.AllSwimTotals},
+	}
+	recentStart, ytdStart := statsPeriodStarts(now, loc)
+
+	checks := make([]IntegrityCheck, 0, 3*len(integritySports))
+	for _, sport := range integritySports {
+		activityType := sql.NullString{String: sport.activityType, Valid: true}
+		for i, period := range []string{PeriodRecent, PeriodYTD, PeriodAll} {
+			check := IntegrityCheck{
+				Sport:            sport.sport,
+				Period:           period,
+				StravaCount:      int64(totals[sport.sport][i].Count),
+				StravaDistance:   totals[sport.sport][i].Distance,
+				StravaMovingTime: totals[sport.sport][i].MovingTime,
+			}
+			if period == PeriodAll {
+				local, err := s.queries.GetStoredTotalsByType(ctx, activityType)
+				if err != nil {
+					return nil, fmt.Errorf("summing stored %s activities: %w", sport.activityType, err)
+				}
+				check.LocalCount = local.ActivityCount
+				check.LocalDistance = summaryFloat(local.TotalDistance)
+				check.LocalMovingTime = int64(summaryFloat(local.TotalMovingTime))
+			} else {
+				check.WindowStart = recentStart
+				if period == PeriodYTD {
+					check.WindowStart = ytdStart
+				}
+				local, err := s.queries.GetStoredTotalsByTypeInRange(ctx, db.GetStoredTotalsByTypeInRangeParams{
+					Type:        activityType,
+					StartDate:   sql.NullTime{Time: check.WindowStart, Valid: true},
+					StartDate_2: sql.NullTime{Time: now, Valid: true},
+				})
+				if err != nil {
+					return nil, fmt.Errorf("summing stored %s activities: %w", sport.activityType, err)
+				}
+				check.LocalCount = local.ActivityCount
+				check.LocalDistance = summaryFloat(local.TotalDistance)
+				check.LocalMovingTime = int64(summaryFloat(local.TotalMovingTime))
+			}
+			checks = append(checks, check)
+		}
+	}
+	return checks, nil
+}
+
+// saveChecks stores the latest checks. A period whose window is no longer
+// missing activities forgets its last repair, so a new gap is repaired.
+func (s *Service) saveChecks(ctx context.Context, checks []IntegrityCheck, windows []RepairWindow, repaired map[string]int64) error {
+	for _, c := range checks {
+		err := s.queries.UpsertSyncIntegrity(ctx, db.UpsertSyncIntegrityParams{
+			Sport:            c.Sport,
+			Period:           c.Period,
+			WindowStart:      toNullTime(c.WindowStart),
+			StravaCount:      c.StravaCount,
+			LocalCount:       c.LocalCount,
+			StravaDistance:   c.StravaDistance,
+			LocalDistance:    c.LocalDistance,
+			StravaMovingTime: c.StravaMovingTime,
+			LocalMovingTime:  c.LocalMovingTime,
+			CheckedAt:        time.Now().UTC().Truncate(time.Second),
+		})
+		if err != nil {
+			return fmt.Errorf("saving %s %s integrity check: %w", c.Sport, c.Period, err)
+		}
+	}
+	for _, w := range windows {
+		for _, sport := range integritySports {
+			params := db.SetSyncIntegrityRepairedParams{Sport: sport.sport, Period: w.Period}
+			if missing, ok := repaired[sport.sport+"/"+w.Period]; ok && w.Missing[sport.sport] > 0 {
+				params.RepairedMissing = sql.NullInt64{Int64: missing, Valid: true}
+			}
+			if err := s.queries.SetSyncIntegrityRepaired(ctx, params); err != nil {
+				return fmt.Errorf("saving %s %s repair: %w", sport.sport, w.Period, err)
+			}
+		}
+	}
+	return nil
+}
+
+// repairWindow logs a repair of a window and queues the first page of the
+// activities in it. Each page queues the next, so the sync queue lists the
+// whole window with its retries, backoff and dead-lettering.
+func (s *Service) repairWindow(ctx context.Context, w RepairWindow, now time.Time) (RepairResult, error) {
+	repair := RepairResult{Reason: w.reason(), WindowStart: w.Start, WindowEnd: w.End}
+	err := s.queries.CreateSyncRepair(ctx, db.CreateSyncRepairParams{
+		Reason:      repair.Reason,
+		WindowStart: toNullTime(repair.WindowStart),
+		WindowEnd:   toNullTime(repair.WindowEnd),
+		RepairedAt:  now,
+	})
+	if err != nil {
+		return repair, fmt.Errorf("recording repair: %w", err)
+	}
+	if err := EnqueueListPage(ctx, s.queries, 1, PriorityRepairPage, w.Start, w.End, now); err != nil {
+		return repair, fmt.Errorf("queueing repair of the %s window: %w", w.Period, err)
+	}
+	return repair, nil
+}
+
+// RepairWindows splits the checks into non-overlapping date windows: the
+// recent four weeks, the rest of the year and everything older. Each
+// window's gap is its period's gap less the gap of the windows inside it,
+// so a gap found all-time but not this year only resyncs earlier years. In
+// January, when the year starts inside the recent window, there is no year
+// window. loc is where the athlete is, whose year Strava's totals follow.
+func RepairWindows(checks []IntegrityCheck, now time.Time, loc *time.Location) []RepairWindow {
+	recentStart, ytdStart := statsPeriodStarts(now, loc)
+	gaps := make(map[string]map[string]int64, 3)
+	for _, c := range checks {
+		if gaps[c.Period] == nil {
+			gaps[c.Period] = make(map[string]int64)
+		}
+		gaps[c.Period][c.Sport] = c.StravaCount - c.LocalCount
+	}
+
+	recent := RepairWindow{Period: PeriodRecent, Start: recentStart, End: now, Missing: make(map[string]int64)}
+	older := RepairWindow{Period: PeriodAll, End: ytdStart, Missing: make(map[string]int64)}
+	year := RepairWindow{Period: PeriodYTD, Start: ytdStart, End: recentStart, Missing: make(map[string]int64)}
+	hasYear := ytdStart.Before(recentStart)
+	if !hasYear {
+		older.End = recentStart
+	}
+	for _, sport := range integritySports {
+		s := sport.sport
+		recent.Missing[s] = gaps[PeriodRecent][s]
+		if hasYear {
+			year.Missing[s] = gaps[PeriodYTD][s] - gaps[PeriodRecent][s]
+			older.Missing[s] = gaps[PeriodAll][s] - gaps[PeriodYTD][s]
+		} else {
+			older.Missing[s] = gaps[PeriodAll][s] - gaps[PeriodRecent][s]
+		}
+	}
+
+	if hasYear {
+		return []RepairWindow{recent, year, older}
+	}
+	return []RepairWindow{recent, older}
+}
+
+// needsRepair reports whether any sport is missing more activities in the
+// window than when it was last repaired
+func (w RepairWindow) needsRepair(repaired map[string]int64) bool {
+	for sport, missing := range w.Missing {
+		if last, ok := repaired[sport+"/"+w.Period]; missing > 0 && (!ok || missing > last) {
+			return true
+		}
+	}
+	return false
+}
+
+// reason describes the gaps that put a window up for repair
+func (w RepairWindow) reason() string {
+	var gaps []string
+	for sport, missing := range w.Missing {
+		if missing > 0 {
+			gaps = append(gaps, fmt.Sprintf("%d %s", missing, sport))
+		}
+	}
+	sort.Strings(gaps)
+	return fmt.Sprintf("Strava counts %s more in the %s window", strings.Join(gaps, ", "), w.Period)
+}
+
+// statsPeriodStarts are the starts of Strava's recent and year-to-date
+// totals, in UTC. The year is the athlete's local one.
+func statsPeriodStarts(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	recentStart := now.AddDate(0, 0, -recentStatsDays)
+	local := now.In(loc)
+	ytdStart := time.Date(local.Year(), 1, 1, 0, 0, 0, 0, loc).UTC()
+	return recentStart, ytdStart
+}
+
+// athleteLocation is the time zone of the athlete's latest activity, or UTC
+// when no activity has one
+func (s *Service) athleteLocation(ctx context.Context) *time.Location {
+	tz, err := s.queries.GetLatestActivityTimezone(ctx)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.Warn("failed to get athlete time zone, using UTC", "error", err)
+		}
+		return time.UTC
+	}
+	return ParseStravaTimezone(tz.String)
+}
+
+// ParseStravaTimezone reads a time zone as Strava gives it on activities,
+// such as "(GMT-08:00) America/Los_Angeles". The fixed offset is used when
+// the zone name is unknown, and UTC when neither can be read.
+func ParseStravaTimezone(tz string) *time.Location {
+	offset, name, _ := strings.Cut(tz, " ")
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc
+	}
+	var sign byte
+	var hours, minutes int
+	if _, err := fmt.Sscanf(offset, "(GMT%c%d:%d)", &sign, &hours, &minutes); err == nil {
+		seconds := (hours*60 + minutes) * 60
+		if sign == '-' {
+			seconds = -seconds
+		}
+		return time.FixedZone(strings.Trim(offset, "()"), seconds)
+	}
+	return time.UTC
+}
+
+// summaryFloat reads a COALESCE(SUM(...), 0) column, which SQLite returns as
+// an int64 or a float64
+func summaryFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
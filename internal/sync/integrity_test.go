@@ -0,0 +1,101 @@
+package sync
+
+import (
+	"testing"
+	"time"
+)
+
+func integrityChecks(recent, ytd, all [2]int64) []IntegrityCheck {
+	checks := []IntegrityCheck{
+		{Sport: "run", Period: PeriodRecent, StravaCount: recent[0], LocalCount: recent[1]},
+		{Sport: "run", Period: PeriodYTD, StravaCount: ytd[0], LocalCount: ytd[1]},
+		{Sport: "run", Period: PeriodAll, StravaCount: all[0], LocalCount: all[1]},
+	}
+	return checks
+}
+
+func TestRepairWindows(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// Two runs missing from earlier this year, one from an older year
+	windows := RepairWindows(integrityChecks([2]int64{10, 10}, [2]int64{120, 118}, [2]int64{900, 897}), now, time.UTC)
+	if len(windows) != 3 {
+		t.Fatalf("expected recent, year and older windows, got %+v", windows)
+	}
+	recent, year, older := windows[0], windows[1], windows[2]
+	if recent.Missing["run"] != 0 || year.Missing["run"] != 2 || older.Missing["run"] != 1 {
+		t.Errorf("unexpected gaps: %d %d %d", recent.Missing["run"], year.Missing["run"], older.Missing["run"])
+	}
+	if !year.Start.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !year.End.Equal(now.AddDate(0, 0, -28)) {
+		t.Errorf("unexpected year window: %v to %v", year.Start, year.End)
+	}
+	if !older.Start.IsZero() || !older.End.Equal(year.Start) {
+		t.Errorf("unexpected older window: %v to %v", older.Start, older.End)
+	}
+	if got := year.reason(); got != "Strava counts 2 run more in the ytd window" {
+		t.Errorf("unexpected reason %q", got)
+	}
+
+	// Private activities stored locally offset a gap in the same window
+	windows = RepairWindows(integrityChecks([2]int64{10, 12}, [2]int64{120, 120}, [2]int64{900, 900}), now, time.UTC)
+	if windows[1].Missing["run"] != 2 || windows[0].Missing["run"] != -2 {
+		t.Errorf("expected the recent surplus to leave a gap earlier in the year, got %+v", windows)
+	}
+
+	// In January the year is inside the recent window
+	january := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	windows = RepairWindows(integrityChecks([2]int64{5, 4}, [2]int64{3, 2}, [2]int64{900, 898}), january, time.UTC)
+	if len(windows) != 2 || windows[1].Missing["run"] != 1 || !windows[1].End.Equal(january.AddDate(0, 0, -28)) {
+		t.Errorf("expected recent and older windows, got %+v", windows)
+	}
+}
+
+func TestRepairWindowNeedsRepair(t *testing.T) {
+	w := RepairWindow{Period: PeriodYTD, Missing: map[string]int64{"run": 2, "ride": -1}}
+	if !w.needsRepair(map[string]int64{}) {
+		t.Error("expected a new gap to need repair")
+	}
+	if w.needsRepair(map[string]int64{"run/ytd": 2}) {
+		t.Error("expected a gap repair could not close to wait until it grows")
+	}
+	if !w.needsRepair(map[string]int64{"run/ytd": 1}) {
+		t.Error("expected a grown gap to need repair")
+	}
+	if (RepairWindow{Period: PeriodAll, Missing: map[string]int64{"run": 0}}).needsRepair(nil) {
+		t.Error("expected no repair without a gap")
+	}
+}
+
+func TestRepairWindowsAthleteYear(t *testing.T) {
+	la := ParseStravaTimezone("(GMT-08:00) America/Los_Angeles")
+
+	// Early on New Year's Day in UTC it is still December 31 in Los Angeles
+	now := time.Date(2027, 1, 1, 5, 0, 0, 0, time.UTC)
+	windows := RepairWindows(integrityChecks([2]int64{10, 10}, [2]int64{120, 118}, [2]int64{900, 897}), now, la)
+	if len(windows) != 3 {
+		t.Fatalf("expected recent, year and older windows, got %+v", windows)
+	}
+	if want := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC); !windows[1].Start.Equal(want) {
+		t.Errorf("expected the year to start at %v, got %v", want, windows[1].Start)
+	}
+}
+
+func TestParseStravaTimezone(t *testing.T) {
+	tests := []struct {
+		tz     string
+		offset int
+	}{
+		{"(GMT+10:00) Australia/Brisbane", 10 * 3600},
+		{"(GMT-03:30) Unknown/Place", -(3*3600 + 30*60)},
+		{"(GMT+00:00) UTC", 0},
+		{"", 0},
+		{"garbage", 0},
+	}
+	at := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		_, offset := at.In(ParseStravaTimezone(tt.tz)).Zone()
+		if offset != tt.offset {
+			t.Errorf("%q: expected offset %d, got %d", tt.tz, tt.offset, offset)
+		}
+	}
+}
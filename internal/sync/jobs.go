@@ -0,0 +1,293 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+)
+
+// Sync job kinds. A list_page job's target is the page number, of a full
+// sync without an after or before time, a delta sync from an after time or
+// a gap repair ending at a before time; the others are fetches for one
+// activity and their target is its ID.
+const (
+	JobListPage = "list_page"
+	JobActivity = "activity"
+	JobStreams  = "streams"
+	JobZones    = "zones"
+	JobLaps     = "laps"
+	JobSocial   = "social"
+)
+
+// Sync job priorities, highest first: new activities, then activities that
+// failed to save, then gap repair pages and the per-activity backfills.
+// Zones come after streams and laps since they need Summit, and kudos and
+// comments go last.
+const (
+	PriorityListPage   = 100
+	PriorityActivity   = 90
+	PriorityRepairPage = 60
+	PriorityStreams    = 50
+	PriorityLaps       = 40
+	PriorityZones      = 30
+	PrioritySocial     = 20
+)
+
+// Sync job retries: a failed job waits 5 minutes, doubling each time up to 6
+// hours, and is dead-lettered after JobMaxAttempts attempts
+const (
+	JobMaxAttempts     = 5
+	jobRetryBackoff    = 5 * time.Minute
+	jobMaxRetryBackoff = 6 * time.Hour
+)
+
+// JobRequestPriority is the rate limit budget class a job's requests are
+// reserved under: list pages and activities bring in new data, the rest,
+// gap repair pages included, is backfill
+func JobRequestPriority(job db.SyncJob) strava.Priority {
+	switch job.Kind {
+	case JobListPage:
+		if !job.BeforeTime.Valid {
+			return strava.PrioritySync
+		}
+	case JobActivity:
+		return strava.PrioritySync
+	}
+	return strava.PriorityBackfill
+}
+
+// ErrUnknownJob is returned for a job kind this version does not run
+var ErrUnknownJob = errors.New("unknown sync job kind")
+
+// JobBackoff is how long a job waits after its attempts-th failed attempt
+func JobBackoff(attempts int64) time.Duration {
+	wait := jobRetryBackoff
+	for i := int64(1); i < attempts && wait < jobMaxRetryBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, jobMaxRetryBackoff)
+}
+
+// IsPermanentJobError reports whether a job failed in a way retrying cannot
+// fix, so it is dead-lettered straight away
+func IsPermanentJobError(err error) bool {
+	return errors.Is(err, ErrPremiumRequired) || errors.Is(err, strava.ErrMissingScope) || errors.Is(err, ErrUnknownJob)
+}
+
+// EnqueueJob queues a job to run from now. A finished or dead-lettered job
+// for the same target is queued again with its attempts reset; a pending or
+// running one is left as it is. after only applies to list pages, where a
+// zero after lists every activity for a full sync; pages listing from
+// different times are separate jobs.
+func EnqueueJob(ctx context.Context, queries *db.Queries, kind string, target, priority int64, after, now time.Time) error {
+	return enqueueJob(ctx, queries, kind, target, priority, after, time.Time{}, now)
+}
+
+// EnqueueListPage queues a page of the activities that started between
+// after and before, like EnqueueJob. A zero before leaves the window open to
+// now.
+func EnqueueListPage(ctx context.Context, queries *db.Queries, page, priority int64, after, before, now time.Time) error {
+	return enqueueJob(ctx, queries, JobListPage, page, priority, after, before, now)
+}
+
+func enqueueJob(ctx context.Context, queries *db.Queries, kind string, target, priority int64, after, before, now time.Time) error {
+	return queries.EnqueueSyncJob(ctx, db.EnqueueSyncJobParams{
+		Kind:        kind,
+		Target:      target,
+		AfterTime:   toNullTime(after),
+		BeforeTime:  toNullTime(before),
+		Priority:    priority,
+		MaxAttempts: JobMaxAttempts,
+		RunAfter:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+}
+
+// EnqueueActivityDataJobs queues streams, laps and, unless zones is false,
+// zones jobs for every activity still missing them, newest first. Activities
+// that already have a job of the kind, whatever its status, are skipped, so
+// one Strava had no data for is not fetched again. Returns the jobs queued.
+func EnqueueActivityDataJobs(ctx context.Context, queries *db.Queries, zones bool, now time.Time) (int64, error) {
+	params := db.EnqueueStreamSyncJobsParams{
+		Priority:    PriorityStreams,
+		MaxAttempts: JobMaxAttempts,
+		RunAfter:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	queued, err := queries.EnqueueStreamSyncJobs(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("queueing streams jobs: %w", err)
+	}
+
+	params.Priority = PriorityLaps
+	n, err := queries.EnqueueLapSyncJobs(ctx, db.EnqueueLapSyncJobsParams(params))
+	if err != nil {
+		return queued, fmt.Errorf("queueing laps jobs: %w", err)
+	}
+	queued += n
+
+	if zones {
+		params.Priority = PriorityZones
+		n, err := queries.EnqueueZoneSyncJobs(ctx, db.EnqueueZoneSyncJobsParams(params))
+		if err != nil {
+			return queued, fmt.Errorf("queueing zones jobs: %w", err)
+		}
+		queued += n
+	}
+	return queued, nil
+}
+
+// EnqueueSocialJobs queues a kudos and comments job for every activity whose
+// counts have changed since its lists were fetched. A finished job is queued
+// again; a dead-lettered one is left alone. Returns the jobs queued.
+func EnqueueSocialJobs(ctx context.Context, queries *db.Queries, now time.Time) (int64, error) {
+	queued, err := queries.EnqueueSocialSyncJobs(ctx, db.EnqueueSocialSyncJobsParams{
+		Priority:    PrioritySocial,
+		MaxAttempts: JobMaxAttempts,
+		RunAfter:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("queueing kudos and comments jobs: %w", err)
+	}
+	return queued, nil
+}
+
+// RunJob runs one claimed job and returns how many activities it saved, for
+// list pages and activity jobs, or 1 once a per-activity fetch is stored.
+// Rate limiting is returned as ErrRateLimited so the job can wait for the
+// window without using up an attempt.
+func (s *Service) RunJob(ctx context.Context, job db.SyncJob) (int, error) {
+	ctx = strava.ContextWithPriority(ctx, JobRequestPriority(job))
+	switch job.Kind {
+	case JobListPage:
+		return s.runListPage(ctx, job)
+	case JobActivity:
+		return s.runActivity(ctx, job.Target)
+	case JobStreams:
+		if err := s.SyncStreamsForActivity(ctx, job.Target); err != nil {
+			return 0, err
+		}
+	case JobZones:
+		if err := s.SyncZonesForActivity(ctx, job.Target); err != nil {
+			return 0, err
+		}
+	case JobLaps:
+		if err := s.SyncLapsForActivity(ctx, job.Target); err != nil {
+			return 0, err
+		}
+	case JobSocial:
+		if err := s.runSocial(ctx, job.Target); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownJob, job.Kind)
+	}
+	return 1, nil
+}
+
+// runListPage saves a page of activities and queues the next page. An
+// activity that fails to save is queued to be fetched again on its own. A
+// gap repair page adds what it fetched to its repair in the repair log.
+func (s *Service) runListPage(ctx context.Context, job db.SyncJob) (int, error) {
+	var after, before time.Time
+	if job.AfterTime.Valid {
+		after = job.AfterTime.Time
+	}
+	if job.BeforeTime.Valid {
+		before = job.BeforeTime.Time
+	}
+	activities, err := s.client.FetchActivitiesPage(ctx, int(job.Target), after, before)
+	if err != nil {
+		return 0, err
+	}
+	if len(activities) == 0 {
+		return 0, nil
+	}
+
+	repair := job.BeforeTime.Valid
+	var stored int64
+	if repair {
+		if stored, err = s.queries.CountActivities(ctx); err != nil {
+			return 0, fmt.Errorf("counting activities: %w", err)
+		}
+	}
+	now := time.Now().UTC()
+	saved := 0
+	for _, activity := range activities {
+		if err := SaveActivity(ctx, s.queries, activity); err != nil {
+			logging.Warn("failed to save activity, queued to fetch again", "activity_id", activity.ID, "error", err)
+			if err := EnqueueJob(ctx, s.queries, JobActivity, activity.ID, PriorityActivity, time.Time{}, now); err != nil {
+				return saved, fmt.Errorf("queueing activity %d: %w", activity.ID, err)
+			}
+			continue
+		}
+		saved++
+	}
+
+	if repair {
+		count, err := s.queries.CountActivities(ctx)
+		if err != nil {
+			return saved, fmt.Errorf("counting activities: %w", err)
+		}
+		err = s.queries.AddSyncRepairProgress(ctx, db.AddSyncRepairProgressParams{
+			Fetched:     int64(len(activities)),
+			Added:       count - stored,
+			Failed:      int64(len(activities) - saved),
+			WindowStart: job.AfterTime,
+			WindowEnd:   job.BeforeTime,
+		})
+		if err != nil {
+			logging.Warn("failed to record gap repair progress", "error", err)
+		}
+	}
+
+	if err := EnqueueListPage(ctx, s.queries, job.Target+1, job.Priority, after, before, now); err != nil {
+		return saved, fmt.Errorf("queueing page %d: %w", job.Target+1, err)
+	}
+	return saved, nil
+}
+
+// runActivity fetches one activity by ID and saves it. One deleted on Strava
+// since it was listed has nothing left to save.
+func (s *Service) runActivity(ctx context.Context, activityID int64) (int, error) {
+	activity, err := s.client.FetchActivity(ctx, activityID)
+	if err == strava.ErrNotFound {
+		return 0, s.queries.ClearSyncFailure(ctx, activityID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := SaveActivity(ctx, s.queries, *activity); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// runSocial fetches who gave an activity kudos and its comments for its
+// current counts. An activity deleted or made private on Strava is marked
+// synced so it is not queued again.
+func (s *Service) runSocial(ctx context.Context, activityID int64) error {
+	counts, err := s.queries.GetActivitySocialCounts(ctx, activityID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting kudos and comment counts: %w", err)
+	}
+
+	err = s.SyncSocialListsForActivity(ctx, activityID, counts.KudosCount, counts.CommentCount)
+	if errors.Is(err, strava.ErrNotFound) {
+		return s.markSocialListsSynced(ctx, activityID, counts.KudosCount, counts.CommentCount)
+	}
+	return err
+}
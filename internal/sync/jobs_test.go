@@ -0,0 +1,70 @@
+package sync
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/strava"
+)
+
+func TestJobBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int64
+		want     time.Duration
+	}{
+		{1, 5 * time.Minute},
+		{2, 10 * time.Minute},
+		{4, 40 * time.Minute},
+		{8, 6 * time.Hour},
+		{50, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := JobBackoff(tt.attempts); got != tt.want {
+			t.Errorf("JobBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestIsPermanentJobError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{ErrPremiumRequired, true},
+		{strava.ErrMissingScope, true},
+		{fmt.Errorf("%w: %q", ErrUnknownJob, "segments"), true},
+		{ErrRateLimited, false},
+		{errors.New("unexpected status code: 500"), false},
+		{fmt.Errorf("fetching streams: %w", errors.New("connection reset")), false},
+	}
+	for _, tt := range tests {
+		if got := IsPermanentJobError(tt.err); got != tt.want {
+			t.Errorf("IsPermanentJobError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestJobRequestPriority(t *testing.T) {
+	repairEnd := sql.NullTime{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	tests := []struct {
+		job  db.SyncJob
+		want strava.Priority
+	}{
+		{db.SyncJob{Kind: JobListPage}, strava.PrioritySync},
+		{db.SyncJob{Kind: JobListPage, BeforeTime: repairEnd}, strava.PriorityBackfill},
+		{db.SyncJob{Kind: JobActivity}, strava.PrioritySync},
+		{db.SyncJob{Kind: JobStreams}, strava.PriorityBackfill},
+		{db.SyncJob{Kind: JobLaps}, strava.PriorityBackfill},
+		{db.SyncJob{Kind: JobZones}, strava.PriorityBackfill},
+		{db.SyncJob{Kind: JobSocial}, strava.PriorityBackfill},
+	}
+	for _, tt := range tests {
+		if got := JobRequestPriority(tt.job); got != tt.want {
+			t.Errorf("JobRequestPriority(%q, before %v) = %s, want %s", tt.job.Kind, tt.job.BeforeTime.Valid, got, tt.want)
+		}
+	}
+}
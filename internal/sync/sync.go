@@ -2,7 +2,10 @@ package sync
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/joshdurbin/strava-mcp/internal/logging"
 	"time"
@@ -55,6 +58,9 @@ func (s *Service) Sync(ctx context.Context, fetchProgress FetchProgressCallback,
 		if err := s.queries.CreateActivity(ctx, params); err != nil {
 			return fmt.Errorf("saving activity %d (%s): %w", activity.ID, activity.Name, err)
 		}
+		if err := s.queries.UpsertActivitySocialCounts(ctx, ConvertSocialCountsToParams(activity)); err != nil {
+			return fmt.Errorf("saving social counts for activity %d: %w", activity.ID, err)
+		}
 
 		if saveProgress != nil {
 			saveProgress(i+1, len(activities), activity.Name)
@@ -87,6 +93,9 @@ func (s *Service) SyncDelta(ctx context.Context, since time.Time, fetchProgress
 		if err := s.queries.CreateActivity(ctx, params); err != nil {
 			return i, fmt.Errorf("saving activity %d (%s): %w", activity.ID, activity.Name, err)
 		}
+		if err := s.queries.UpsertActivitySocialCounts(ctx, ConvertSocialCountsToParams(activity)); err != nil {
+			return i, fmt.Errorf("saving social counts for activity %d: %w", activity.ID, err)
+		}
 
 		if saveProgress != nil {
 			saveProgress(i+1, len(activities), activity.Name)
@@ -96,7 +105,9 @@ func (s *Service) SyncDelta(ctx context.Context, since time.Time, fetchProgress
 	return len(activities), nil
 }
 
-// ConvertActivityToParams converts a Strava activity to database params
+// ConvertActivityToParams converts a Strava activity to database params.
+// The summary polyline is stored even when empty so that NULL only marks rows
+// synced before route support was added.
 func ConvertActivityToParams(a strava.Activity) db.CreateActivityParams {
 	return db.CreateActivityParams{
 		ID:                 a.ID,
@@ -116,7 +127,43 @@ func ConvertActivityToParams(a strava.Activity) db.CreateActivityParams {
 		AverageHeartrate:   toNullFloat64(a.AverageHeartrate),
 		MaxHeartrate:       toNullFloat64(a.MaxHeartrate),
 		Calories:           toNullFloat64(a.Kilojoules),
+		SummaryPolyline:    sql.NullString{String: a.Map.SummaryPolyline, Valid: true},
+		StartLat:           latlngAt(a.StartLatlng, 0),
+		StartLng:           latlngAt(a.StartLatlng, 1),
+		EndLat:             latlngAt(a.EndLatlng, 0),
+		EndLng:             latlngAt(a.EndLatlng, 1),
+		WorkoutType:        workoutType(a.WorkoutType),
+	}
+}
+
+// ConvertSocialCountsToParams converts a Strava activity's kudos, comment and
+// athlete counts to database params. Activities Strava reports without an
+// athlete count were done solo.
+func ConvertSocialCountsToParams(a strava.Activity) db.UpsertActivitySocialCountsParams {
+	return db.UpsertActivitySocialCountsParams{
+		ActivityID:   a.ID,
+		KudosCount:   int64(a.KudosCount),
+		CommentCount: int64(a.CommentCount),
+		AthleteCount: int64(max(a.AthleteCount, 1)),
+	}
+}
+
+// workoutType stores Strava's workout_type, which is 0 for untagged runs, so
+// only a missing value is NULL
+func workoutType(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+// latlngAt returns one coordinate of a Strava [lat, lng] pair, or NULL when
+// the activity has no GPS data (indoor activities report an empty array).
+func latlngAt(pair []float64, i int) sql.NullFloat64 {
+	if len(pair) != 2 {
+		return sql.NullFloat64{}
 	}
+	return sql.NullFloat64{Float64: pair[i], Valid: true}
 }
 
 func toNullFloat64(v float64) sql.NullFloat64 {
@@ -135,9 +182,6 @@ func toNullTime(v time.Time) sql.NullTime {
 	return sql.NullTime{Time: v, Valid: !v.IsZero()}
 }
 
-// ZoneSyncProgressCallback is called after each activity zone is synced
-type ZoneSyncProgressCallback func(current, total int, activityID int64)
-
 // ErrPremiumRequired is returned when Strava Summit subscription is required
 var ErrPremiumRequired = strava.ErrPremiumRequired
 
@@ -174,6 +218,7 @@ func (s *Service) SyncZonesForActivity(ctx context.Context, activityID int64) er
 			ActivityID:  activityID,
 			ZoneType:    zone.Type,
 			SensorBased: sensorBased,
+			Source:      "strava",
 		})
 		if err != nil {
 			return fmt.Errorf("creating activity zone: %w", err)
@@ -201,61 +246,466 @@ func (s *Service) SyncZonesForActivity(ctx context.Context, activityID int64) er
 	return nil
 }
 
-// SyncZones syncs zone data for activities that don't have zones yet
-// Returns the number synced and an error. If ErrPremiumRequired is returned,
-// the caller should stop trying to sync zones.
-func (s *Service) SyncZones(ctx context.Context, batchSize int, progress ZoneSyncProgressCallback) (int, error) {
-	// Get activities without zones
-	activityIDs, err := s.queries.GetActivitiesWithoutZones(ctx, int64(batchSize))
+// ConvertStreamsToParams serializes each stream's data as a JSON array.
+// A nil streams value yields an empty row so the activity is not fetched again.
+func ConvertStreamsToParams(activityID int64, streams *strava.ActivityStreams) (db.UpsertActivityStreamsParams, error) {
+	params := db.UpsertActivityStreamsParams{ActivityID: activityID}
+	if streams == nil {
+		return params, nil
+	}
+
+	var err error
+	set := func(dst *sql.NullString, data any, n int) {
+		if err != nil || n == 0 {
+			return
+		}
+		var b []byte
+		if b, err = json.Marshal(data); err == nil {
+			*dst = sql.NullString{String: string(b), Valid: true}
+			params.PointCount = max(params.PointCount, int64(n))
+		}
+	}
+
+	if st := streams.Time; st != nil {
+		set(&params.TimeData, st.Data, len(st.Data))
+	}
+	if st := streams.Distance; st != nil {
+		set(&params.DistanceData, st.Data, len(st.Data))
+	}
+	if st := streams.Latlng; st != nil {
+		set(&params.LatlngData, st.Data, len(st.Data))
+	}
+	if st := streams.Altitude; st != nil {
+		set(&params.AltitudeData, st.Data, len(st.Data))
+	}
+	if st := streams.VelocitySmooth; st != nil {
+		set(&params.VelocityData, st.Data, len(st.Data))
+	}
+	if st := streams.Heartrate; st != nil {
+		set(&params.HeartrateData, st.Data, len(st.Data))
+	}
+	if st := streams.Cadence; st != nil {
+		set(&params.CadenceData, st.Data, len(st.Data))
+	}
+	if st := streams.Watts; st != nil {
+		set(&params.WattsData, st.Data, len(st.Data))
+	}
+	if st := streams.GradeSmooth; st != nil {
+		set(&params.GradeData, st.Data, len(st.Data))
+	}
+	if st := streams.Moving; st != nil {
+		set(&params.MovingData, st.Data, len(st.Data))
+	}
+
 	if err != nil {
-		return 0, fmt.Errorf("getting activities without zones: %w", err)
+		return params, fmt.Errorf("encoding streams: %w", err)
 	}
+	return params, nil
+}
 
-	if len(activityIDs) == 0 {
-		return 0, nil
+// SyncStreamsForActivity fetches and stores stream data for a single activity
+func (s *Service) SyncStreamsForActivity(ctx context.Context, activityID int64) error {
+	streams, err := s.client.FetchActivityStreams(ctx, activityID)
+	if err != nil {
+		if err == strava.ErrRateLimited {
+			return ErrRateLimited
+		}
+		return fmt.Errorf("fetching streams: %w", err)
 	}
 
-	synced := 0
-	consecutiveRateLimits := 0
-	maxConsecutiveRateLimits := 3 // Stop batch if we hit 3 consecutive rate limits
+	params, err := ConvertStreamsToParams(activityID, streams)
+	if err != nil {
+		return err
+	}
+
+	if err := s.queries.UpsertActivityStreams(ctx, params); err != nil {
+		return fmt.Errorf("saving streams: %w", err)
+	}
 
-	for i, id := range activityIDs {
-		if progress != nil {
-			progress(i+1, len(activityIDs), id)
+	return nil
+}
+
+// ConvertLapToParams converts a Strava lap to database params
+func ConvertLapToParams(activityID int64, l strava.Lap) db.CreateActivityLapParams {
+	return db.CreateActivityLapParams{
+		ActivityID:         activityID,
+		LapIndex:           int64(l.LapIndex),
+		Name:               toNullString(l.Name),
+		Distance:           toNullFloat64(l.Distance),
+		MovingTime:         toNullInt64(int64(l.MovingTime)),
+		ElapsedTime:        toNullInt64(int64(l.ElapsedTime)),
+		StartDate:          toNullTime(l.StartDate),
+		TotalElevationGain: toNullFloat64(l.TotalElevationGain),
+		AverageSpeed:       toNullFloat64(l.AverageSpeed),
+		MaxSpeed:           toNullFloat64(l.MaxSpeed),
+		AverageHeartrate:   toNullFloat64(l.AverageHeartrate),
+		MaxHeartrate:       toNullFloat64(l.MaxHeartrate),
+		AverageCadence:     toNullFloat64(l.AverageCadence),
+		AverageWatts:       toNullFloat64(l.AverageWatts),
+	}
+}
+
+// SyncLapsForActivity fetches an activity's laps and replaces the stored
+// ones. An activity deleted on Strava since it synced is left as it is.
+func (s *Service) SyncLapsForActivity(ctx context.Context, activityID int64) error {
+	laps, err := s.client.FetchActivityLaps(ctx, activityID)
+	if err == strava.ErrNotFound {
+		return nil
+	}
+	if err == strava.ErrRateLimited {
+		return ErrRateLimited
+	}
+	if err != nil {
+		return fmt.Errorf("fetching laps: %w", err)
+	}
+
+	if err := s.queries.DeleteActivityLaps(ctx, activityID); err != nil {
+		return fmt.Errorf("deleting existing laps: %w", err)
+	}
+	for _, lap := range laps {
+		if err := s.queries.CreateActivityLap(ctx, ConvertLapToParams(activityID, lap)); err != nil {
+			return fmt.Errorf("saving lap: %w", err)
 		}
+	}
+	return nil
+}
 
-		if err := s.SyncZonesForActivity(ctx, id); err != nil {
-			// If premium is required, stop immediately and return the error
-			if err == ErrPremiumRequired {
-				return synced, ErrPremiumRequired
-			}
-			// If rate limited multiple times in a row, stop this batch
-			if err == ErrRateLimited {
-				consecutiveRateLimits++
-				if consecutiveRateLimits >= maxConsecutiveRateLimits {
-					logging.Warn("stopping zone sync batch due to repeated rate limiting",
-						"consecutive_rate_limits", consecutiveRateLimits,
-						"synced_so_far", synced)
-					return synced, ErrRateLimited
-				}
-				continue
+// SyncSocialListsForActivity fetches and stores who gave an activity kudos
+// and its comments. kudosCount and commentCount are the counts the lists are
+// fetched for; a list is skipped when its count is zero.
+func (s *Service) SyncSocialListsForActivity(ctx context.Context, activityID, kudosCount, commentCount int64) error {
+	var kudoers []strava.AthleteSummary
+	var comments []strava.Comment
+	var err error
+	if kudosCount > 0 {
+		if kudoers, err = s.client.FetchActivityKudoers(ctx, activityID); err != nil {
+			return socialFetchError("kudos", err)
+		}
+	}
+	if commentCount > 0 {
+		if comments, err = s.client.FetchActivityComments(ctx, activityID); err != nil {
+			return socialFetchError("comments", err)
+		}
+	}
+
+	if err := s.queries.DeleteActivityKudos(ctx, activityID); err != nil {
+		return fmt.Errorf("deleting kudos: %w", err)
+	}
+	for i, k := range kudoers {
+		if err := s.queries.CreateActivityKudo(ctx, db.CreateActivityKudoParams{
+			ActivityID:  activityID,
+			Position:    int64(i),
+			AthleteName: k.Name(),
+		}); err != nil {
+			return fmt.Errorf("saving kudos: %w", err)
+		}
+	}
+
+	if err := s.queries.DeleteActivityComments(ctx, activityID); err != nil {
+		return fmt.Errorf("deleting comments: %w", err)
+	}
+	for _, c := range comments {
+		if err := s.queries.CreateActivityComment(ctx, db.CreateActivityCommentParams{
+			ID:          c.ID,
+			ActivityID:  activityID,
+			AthleteName: c.Athlete.Name(),
+			Text:        c.Text,
+			CreatedAt:   toNullTime(c.CreatedAt),
+		}); err != nil {
+			return fmt.Errorf("saving comments: %w", err)
+		}
+	}
+
+	return s.markSocialListsSynced(ctx, activityID, kudosCount, commentCount)
+}
+
+// markSocialListsSynced records the counts the lists were fetched for, so
+// they are fetched again only when the counts change
+func (s *Service) markSocialListsSynced(ctx context.Context, activityID, kudosCount, commentCount int64) error {
+	if err := s.queries.MarkSocialListsSynced(ctx, db.MarkSocialListsSyncedParams{
+		ListsKudosCount:   sql.NullInt64{Int64: kudosCount, Valid: true},
+		ListsCommentCount: sql.NullInt64{Int64: commentCount, Valid: true},
+		ActivityID:        activityID,
+	}); err != nil {
+		return fmt.Errorf("marking social lists synced: %w", err)
+	}
+	return nil
+}
+
+func socialFetchError(list string, err error) error {
+	if err == strava.ErrRateLimited {
+		return ErrRateLimited
+	}
+	return fmt.Errorf("fetching %s: %w", list, err)
+}
+
+// ClubSyncResult is what a club sync stored
+type ClubSyncResult struct {
+	Clubs         int
+	NewActivities int
+	RemovedClubs  int
+}
+
+// SyncClubs stores the athlete's clubs and the new activities in each club's
+// feed, and removes clubs the athlete has left. Club activities come without
+// an ID or date, so they are matched by fingerprint and dated now; those
+// found on a club's first sync are stored as backfill.
+func (s *Service) SyncClubs(ctx context.Context) (ClubSyncResult, error) {
+	var result ClubSyncResult
+	syncedAt := time.Now().UTC().Truncate(time.Second)
+
+	clubs, err := s.client.FetchAthleteClubs(ctx)
+	if err != nil {
+		return result, fmt.Errorf("fetching clubs: %w", err)
+	}
+
+	for _, club := range clubs {
+		if err := s.queries.UpsertClub(ctx, ConvertClubToParams(club, syncedAt)); err != nil {
+			return result, fmt.Errorf("saving club %d (%s): %w", club.ID, club.Name, err)
+		}
+		result.Clubs++
+
+		stored, err := s.queries.CountClubActivities(ctx, club.ID)
+		if err != nil {
+			return result, fmt.Errorf("counting activities for club %d: %w", club.ID, err)
+		}
+		activities, err := s.client.FetchClubActivities(ctx, club.ID)
+		if err != nil {
+			if err == strava.ErrRateLimited {
+				return result, ErrRateLimited
 			}
-			// Log but continue - some activities may not have zone data
-			logging.Warn("failed to sync zones", "activity_id", id, "error", err)
-			consecutiveRateLimits = 0 // Reset on non-rate-limit errors
+			// Clubs whose feed cannot be read are kept, with their activities
+			logging.Warn("failed to fetch club activities", "club_id", club.ID, "error", err)
 			continue
 		}
 
-		synced++
-		consecutiveRateLimits = 0 // Reset on success
+		backfill := stored == 0
+		for _, params := range ConvertClubActivitiesToParams(club.ID, activities, syncedAt, backfill) {
+			added, err := s.queries.CreateClubActivity(ctx, params)
+			if err != nil {
+				return result, fmt.Errorf("saving activity for club %d: %w", club.ID, err)
+			}
+			result.NewActivities += int(added)
+		}
+	}
+
+	stale := sql.NullTime{Time: syncedAt, Valid: true}
+	if err := s.queries.DeleteStaleClubActivities(ctx, stale); err != nil {
+		return result, fmt.Errorf("deleting activities of clubs left: %w", err)
+	}
+	removed, err := s.queries.DeleteStaleClubs(ctx, stale)
+	if err != nil {
+		return result, fmt.Errorf("deleting clubs left: %w", err)
+	}
+	result.RemovedClubs = int(removed)
+
+	return result, nil
+}
+
+// ConvertClubToParams converts a Strava club to database params
+func ConvertClubToParams(c strava.Club, syncedAt time.Time) db.UpsertClubParams {
+	var private int64
+	if c.Private {
+		private = 1
+	}
+	return db.UpsertClubParams{
+		ID:          c.ID,
+		Name:        c.Name,
+		SportType:   toNullString(c.SportType),
+		City:        toNullString(c.City),
+		State:       toNullString(c.State),
+		Country:     toNullString(c.Country),
+		Private:     private,
+		MemberCount: int64(c.MemberCount),
+		Url:         toNullString(c.URL),
+		SyncedAt:    toNullTime(syncedAt),
+	}
+}
+
+// ConvertClubActivitiesToParams converts a club's feed to database params.
+// Identical activities in one feed get numbered fingerprints so each is kept.
+func ConvertClubActivitiesToParams(clubID int64, activities []strava.ClubActivity, seenAt time.Time, backfill bool) []db.CreateClubActivityParams {
+	var flag int64
+	if backfill {
+		flag = 1
+	}
+	seen := make(map[string]int)
+	params := make([]db.CreateClubActivityParams, 0, len(activities))
+	for _, a := range activities {
+		fingerprint := ClubActivityFingerprint(a)
+		seen[fingerprint]++
+		if n := seen[fingerprint]; n > 1 {
+			fingerprint = fmt.Sprintf("%s#%d", fingerprint, n)
+		}
+		params = append(params, db.CreateClubActivityParams{
+			ClubID:             clubID,
+			Fingerprint:        fingerprint,
+			AthleteName:        a.Athlete.Name(),
+			Name:               a.Name,
+			Type:               toNullString(a.Type),
+			SportType:          toNullString(a.SportType),
+			Distance:           a.Distance,
+			MovingTime:         int64(a.MovingTime),
+			ElapsedTime:        int64(a.ElapsedTime),
+			TotalElevationGain: a.TotalElevationGain,
+			FirstSeenAt:        seenAt,
+			Backfill:           flag,
+		})
+	}
+	return params
+}
+
+// ClubActivityFingerprint identifies a club activity across syncs
+func ClubActivityFingerprint(a strava.ClubActivity) string {
+	key := fmt.Sprintf("%s|%s|%s|%s|%.1f|%d|%d|%.1f", a.Athlete.Name(), a.Name, a.Type, a.SportType,
+		a.Distance, a.MovingTime, a.ElapsedTime, a.TotalElevationGain)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// LibrarySyncResult is what a route library sync stored
+type LibrarySyncResult struct {
+	Routes          int
+	GPXExported     int
+	RemovedRoutes   int
+	Segments        int
+	RemovedSegments int
+}
+
+// SyncRouteLibrary stores the athlete's saved routes and starred segments
+// and removes those no longer on Strava. A route's GPX is exported only
+// when the route is new or has changed since its last export.
+func (s *Service) SyncRouteLibrary(ctx context.Context) (LibrarySyncResult, error) {
+	var result LibrarySyncResult
+	syncedAt := time.Now().UTC().Truncate(time.Second)
+
+	athlete, err := s.client.FetchAthlete(ctx)
+	if err != nil {
+		return result, fmt.Errorf("fetching athlete: %w", err)
+	}
+
+	routes, err := s.client.FetchAthleteRoutes(ctx, athlete.ID)
+	if err != nil {
+		return result, fmt.Errorf("fetching routes: %w", err)
+	}
+	for _, route := range routes {
+		if err := s.queries.UpsertSavedRoute(ctx, ConvertRouteToParams(route, syncedAt)); err != nil {
+			return result, fmt.Errorf("saving route %d (%s): %w", route.ID, route.Name, err)
+		}
+		result.Routes++
+	}
+	removed, err := s.queries.DeleteStaleSavedRoutes(ctx, syncedAt)
+	if err != nil {
+		return result, fmt.Errorf("deleting removed routes: %w", err)
+	}
+	result.RemovedRoutes = int(removed)
+
+	pending, err := s.queries.ListSavedRoutesNeedingGPX(ctx)
+	if err != nil {
+		return result, fmt.Errorf("listing routes needing GPX: %w", err)
+	}
+	for _, route := range pending {
+		gpx, err := s.client.ExportRouteGPX(ctx, route.ID)
+		if err != nil {
+			if err == strava.ErrRateLimited {
+				return result, ErrRateLimited
+			}
+			// Routes whose GPX cannot be exported are kept and retried next sync
+			logging.Warn("failed to export route GPX", "route_id", route.ID, "error", err)
+			continue
+		}
+		err = s.queries.SetSavedRouteGPX(ctx, db.SetSavedRouteGPXParams{
+			Gpx:          sql.NullString{String: string(gpx), Valid: true},
+			GpxUpdatedAt: route.UpdatedAt,
+			ID:           route.ID,
+		})
+		if err != nil {
+			return result, fmt.Errorf("saving GPX for route %d: %w", route.ID, err)
+		}
+		result.GPXExported++
+	}
 
-		// Small delay to respect rate limits
-		select {
-		case <-ctx.Done():
-			return synced, ctx.Err()
-		case <-time.After(100 * time.Millisecond):
+	segments, err := s.client.FetchStarredSegments(ctx)
+	if err != nil {
+		return result, fmt.Errorf("fetching starred segments: %w", err)
+	}
+	for _, segment := range segments {
+		if err := s.queries.UpsertStarredSegment(ctx, ConvertSegmentToParams(segment, syncedAt)); err != nil {
+			return result, fmt.Errorf("saving segment %d (%s): %w", segment.ID, segment.Name, err)
 		}
+		result.Segments++
+	}
+	removed, err = s.queries.DeleteStaleStarredSegments(ctx, syncedAt)
+	if err != nil {
+		return result, fmt.Errorf("deleting unstarred segments: %w", err)
 	}
+	result.RemovedSegments = int(removed)
+
+	return result, nil
+}
+
+// routeTypes and routeSubTypes name Strava's numeric route types
+var (
+	routeTypes    = map[int]string{1: "Ride", 2: "Run"}
+	routeSubTypes = map[int]string{1: "road", 2: "mountain_bike", 3: "cross", 4: "trail", 5: "mixed"}
+)
 
-	return synced, nil
+// ConvertRouteToParams converts a Strava route to database params
+func ConvertRouteToParams(r strava.Route, syncedAt time.Time) db.UpsertSavedRouteParams {
+	return db.UpsertSavedRouteParams{
+		ID:                  r.ID,
+		Name:                r.Name,
+		Description:         toNullString(r.Description),
+		Type:                toNullString(routeTypes[r.Type]),
+		SubType:             toNullString(routeSubTypes[r.SubType]),
+		Distance:            r.Distance,
+		ElevationGain:       r.ElevationGain,
+		EstimatedMovingTime: int64(r.EstimatedMovingTime),
+		Polyline:            toNullString(r.Map.SummaryPolyline),
+		Private:             boolToInt64(r.Private),
+		Starred:             boolToInt64(r.Starred),
+		CreatedAt:           toNullTime(r.CreatedAt),
+		UpdatedAt:           toNullTime(r.UpdatedAt),
+		SyncedAt:            syncedAt,
+	}
+}
+
+// ConvertSegmentToParams converts a starred Strava segment to database params
+func ConvertSegmentToParams(s strava.Segment, syncedAt time.Time) db.UpsertStarredSegmentParams {
+	params := db.UpsertStarredSegmentParams{
+		ID:            s.ID,
+		Name:          s.Name,
+		ActivityType:  toNullString(s.ActivityType),
+		Distance:      s.Distance,
+		AverageGrade:  s.AverageGrade,
+		MaximumGrade:  s.MaximumGrade,
+		ElevationHigh: s.ElevationHigh,
+		ElevationLow:  s.ElevationLow,
+		ClimbCategory: int64(s.ClimbCategory),
+		City:          toNullString(s.City),
+		State:         toNullString(s.State),
+		Country:       toNullString(s.Country),
+		Private:       boolToInt64(s.Private),
+		StartLat:      latlngAt(s.StartLatlng, 0),
+		StartLng:      latlngAt(s.StartLatlng, 1),
+		EndLat:        latlngAt(s.EndLatlng, 0),
+		EndLng:        latlngAt(s.EndLatlng, 1),
+		PrDate:        toNullString(s.AthleteSegmentStats.PRDate),
+		EffortCount:   int64(s.AthleteSegmentStats.EffortCount),
+		SyncedAt:      syncedAt,
+	}
+	if stats := s.AthleteSegmentStats; stats.PRElapsedTime != nil {
+		params.PrElapsedTime = sql.NullInt64{Int64: int64(*stats.PRElapsedTime), Valid: true}
+	}
+	if stats := s.AthleteSegmentStats; stats.PRActivityID != nil {
+		params.PrActivityID = sql.NullInt64{Int64: *stats.PRActivityID, Valid: true}
+	}
+	return params
+}
+
+func boolToInt64(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
 }
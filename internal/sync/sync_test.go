@@ -30,6 +30,9 @@ func TestConvertActivityToParams(t *testing.T) {
 		AverageHeartrate:   145.0,
 		MaxHeartrate:       175.0,
 		Kilojoules:         350.0,
+		StartLatlng:        []float64{48.8566, 2.3522},
+		EndLatlng:          []float64{48.8606, 2.3376},
+		Map:                strava.PolylineMap{SummaryPolyline: "_p~iF~ps|U_ulLnnqC"},
 	}
 
 	params := ConvertActivityToParams(activity)
@@ -55,6 +58,15 @@ func TestConvertActivityToParams(t *testing.T) {
 	if !params.Calories.Valid || params.Calories.Float64 != 350.0 {
 		t.Errorf("expected calories 350.0, got %+v", params.Calories)
 	}
+	if !params.SummaryPolyline.Valid || params.SummaryPolyline.String != "_p~iF~ps|U_ulLnnqC" {
+		t.Errorf("expected summary polyline, got %+v", params.SummaryPolyline)
+	}
+	if !params.StartLat.Valid || params.StartLat.Float64 != 48.8566 {
+		t.Errorf("expected start lat 48.8566, got %+v", params.StartLat)
+	}
+	if !params.EndLng.Valid || params.EndLng.Float64 != 2.3376 {
+		t.Errorf("expected end lng 2.3376, got %+v", params.EndLng)
+	}
 }
 
 func TestConvertActivityToParams_ZeroValues(t *testing.T) {
@@ -83,6 +95,12 @@ func TestConvertActivityToParams_ZeroValues(t *testing.T) {
 	if params.StartDate.Valid {
 		t.Error("expected start date to be invalid for zero time")
 	}
+	if !params.SummaryPolyline.Valid || params.SummaryPolyline.String != "" {
+		t.Errorf("expected empty (non-NULL) summary polyline without GPS data, got %+v", params.SummaryPolyline)
+	}
+	if params.StartLat.Valid || params.EndLng.Valid {
+		t.Error("expected latlng to be invalid without GPS data")
+	}
 }
 
 func TestToNullFloat64(t *testing.T) {
@@ -152,3 +170,143 @@ func TestToNullTime(t *testing.T) {
 		t.Error("expected invalid time for zero value")
 	}
 }
+
+func TestConvertStreamsToParams(t *testing.T) {
+	streams := &strava.ActivityStreams{
+		Time:      &strava.Stream[int]{Data: []int{0, 1, 2}},
+		Latlng:    &strava.Stream[[2]float64]{Data: [][2]float64{{37.1, -122.1}, {37.2, -122.2}, {37.3, -122.3}}},
+		Heartrate: &strava.Stream[float64]{Data: []float64{120, 125, 130}},
+		Moving:    &strava.Stream[bool]{Data: []bool{false, true, true}},
+		Watts:     &strava.Stream[float64]{Data: []float64{}},
+	}
+
+	params, err := ConvertStreamsToParams(42, streams)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if params.ActivityID != 42 {
+		t.Errorf("ActivityID = %d, want 42", params.ActivityID)
+	}
+	if params.PointCount != 3 {
+		t.Errorf("PointCount = %d, want 3", params.PointCount)
+	}
+	if params.TimeData.String != "[0,1,2]" {
+		t.Errorf("TimeData = %q", params.TimeData.String)
+	}
+	if params.LatlngData.String != "[[37.1,-122.1],[37.2,-122.2],[37.3,-122.3]]" {
+		t.Errorf("LatlngData = %q", params.LatlngData.String)
+	}
+	if params.MovingData.String != "[false,true,true]" {
+		t.Errorf("MovingData = %q", params.MovingData.String)
+	}
+	if params.WattsData.Valid {
+		t.Error("empty watts stream should be stored as NULL")
+	}
+	if params.CadenceData.Valid {
+		t.Error("missing cadence stream should be stored as NULL")
+	}
+}
+
+func TestConvertStreamsToParams_Nil(t *testing.T) {
+	params, err := ConvertStreamsToParams(7, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.ActivityID != 7 || params.PointCount != 0 || params.TimeData.Valid {
+		t.Errorf("expected empty marker row, got %+v", params)
+	}
+}
+
+func TestConvertSocialCountsToParams(t *testing.T) {
+	params := ConvertSocialCountsToParams(strava.Activity{ID: 3, KudosCount: 14, CommentCount: 2, AthleteCount: 4})
+	if params.ActivityID != 3 || params.KudosCount != 14 || params.CommentCount != 2 || params.AthleteCount != 4 {
+		t.Errorf("unexpected params: %+v", params)
+	}
+
+	// Older summaries without athlete_count count as solo
+	if params := ConvertSocialCountsToParams(strava.Activity{ID: 4}); params.AthleteCount != 1 {
+		t.Errorf("expected a solo activity, got %+v", params)
+	}
+}
+
+func TestConvertClubActivitiesToParams(t *testing.T) {
+	seenAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	tempo := strava.ClubActivity{
+		Athlete: strava.AthleteSummary{Firstname: "Ana", Lastname: "L."},
+		Name:    "Tempo Tuesday", Type: "Run", SportType: "Run",
+		Distance: 10012.5, MovingTime: 2700, ElapsedTime: 2790,
+	}
+	easy := tempo
+	easy.Name = "Easy"
+
+	params := ConvertClubActivitiesToParams(42, []strava.ClubActivity{tempo, easy, tempo}, seenAt, true)
+	if len(params) != 3 || params[0].AthleteName != "Ana L." || params[0].Backfill != 1 || !params[0].FirstSeenAt.Equal(seenAt) {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+	if params[0].Fingerprint == params[1].Fingerprint || params[2].Fingerprint != params[0].Fingerprint+"#2" {
+		t.Errorf("expected distinct fingerprints with the repeat numbered, got %q %q %q",
+			params[0].Fingerprint, params[1].Fingerprint, params[2].Fingerprint)
+	}
+
+	// The same activity gets the same fingerprint in a later sync
+	later := ConvertClubActivitiesToParams(42, []strava.ClubActivity{easy}, seenAt.Add(time.Hour), false)
+	if later[0].Fingerprint != params[1].Fingerprint || later[0].Backfill != 0 {
+		t.Errorf("expected a stable fingerprint, got %+v", later[0])
+	}
+}
+
+func TestConvertRouteToParams(t *testing.T) {
+	syncedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	route := strava.Route{ID: 7, Name: "River Loop", Distance: 10050, ElevationGain: 42, Type: 2, SubType: 4, Starred: true}
+	route.Map.SummaryPolyline = "abc"
+
+	params := ConvertRouteToParams(route, syncedAt)
+	if params.Type.String != "Run" || params.SubType.String != "trail" || params.Starred != 1 || params.Private != 0 {
+		t.Errorf("unexpected params: %+v", params)
+	}
+	if params.Polyline.String != "abc" || params.Description.Valid || params.UpdatedAt.Valid || !params.SyncedAt.Equal(syncedAt) {
+		t.Errorf("unexpected params: %+v", params)
+	}
+
+	// Unknown types are left NULL
+	if params := ConvertRouteToParams(strava.Route{ID: 8, Type: 9}, syncedAt); params.Type.Valid || params.SubType.Valid {
+		t.Errorf("expected no type, got %+v", params)
+	}
+}
+
+func TestConvertSegmentToParams(t *testing.T) {
+	syncedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	pr, activityID := 312, int64(99)
+	segment := strava.Segment{
+		ID: 5, Name: "Hawk Hill", ActivityType: "Ride", Distance: 2500, AverageGrade: 5.1,
+		StartLatlng: []float64{37.83, -122.48}, EndLatlng: []float64{},
+		AthleteSegmentStats: strava.SegmentStats{PRElapsedTime: &pr, PRDate: "2026-05-02", PRActivityID: &activityID, EffortCount: 14},
+	}
+
+	params := ConvertSegmentToParams(segment, syncedAt)
+	if params.PrElapsedTime.Int64 != 312 || params.PrActivityID.Int64 != 99 || params.PrDate.String != "2026-05-02" || params.EffortCount != 14 {
+		t.Errorf("unexpected stats: %+v", params)
+	}
+	if params.StartLat.Float64 != 37.83 || params.StartLng.Float64 != -122.48 || params.EndLat.Valid {
+		t.Errorf("unexpected coordinates: %+v", params)
+	}
+
+	// A segment never ridden has no PR
+	if params := ConvertSegmentToParams(strava.Segment{ID: 6}, syncedAt); params.PrElapsedTime.Valid || params.PrActivityID.Valid || params.PrDate.Valid {
+		t.Errorf("expected no PR, got %+v", params)
+	}
+}
+
+func TestConvertLapToParams(t *testing.T) {
+	lap := strava.Lap{LapIndex: 3, Name: "Lap 3", Distance: 800, MovingTime: 165, ElapsedTime: 170, AverageHeartrate: 171.4}
+
+	params := ConvertLapToParams(42, lap)
+	if params.ActivityID != 42 || params.LapIndex != 3 || params.Distance.Float64 != 800 || params.MovingTime.Int64 != 165 {
+		t.Errorf("unexpected lap: %+v", params)
+	}
+	// Laps recorded without a sensor have no heart rate or power
+	if params.AverageHeartrate.Float64 != 171.4 || params.AverageWatts.Valid || params.StartDate.Valid {
+		t.Errorf("unexpected optional fields: %+v", params)
+	}
+}
@@ -0,0 +1,372 @@
+// Package taper checks the weeks before a race against established taper
+// guidance: cut volume progressively by 40-60% over one to three weeks,
+// longer for longer races, while keeping intensity and training frequency
+// (Bosquet et al. 2007; Mujika and Padilla 2003).
+package taper
+
+import (
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+)
+
+// Where the race falls relative to the taper
+const (
+	PhaseBuilding = "building" // the taper hasn't started
+	PhaseTaper    = "taper"
+	PhaseRaceWeek = "race_week"
+	PhasePassed   = "passed"
+)
+
+// Overall readiness
+const (
+	StatusReady   = "ready"    // race week with every check good
+	StatusOnTrack = "on_track" // nothing needs changing
+	StatusAdjust  = "adjust"   // at least one check needs a change
+	StatusEarly   = "early"    // too early to taper
+	StatusPassed  = "passed"
+)
+
+// Check statuses
+const (
+	Good   = "good"
+	Watch  = "watch"
+	Adjust = "adjust"
+)
+
+// Check names
+const (
+	CheckVolume      = "volume_reduction"
+	CheckIntensity   = "intensity"
+	CheckFrequency   = "frequency"
+	CheckProgression = "progression"
+	CheckRamp        = "load_ramp"
+)
+
+// baselineDays is how much training before the taper sets the athlete's
+// usual load
+const baselineDays = 28
+
+// minTaperDays is how long a taper must have run before its volume is judged
+const minTaperDays = 3
+
+// The volume cut aimed for rises from startReduction at the beginning of the
+// taper to finalReduction by race day, give or take reductionBand. A cut off
+// by more than another reductionBand needs adjusting.
+const (
+	startReduction = 0.25
+	finalReduction = 0.50
+	reductionBand  = 0.10
+)
+
+// Training frequency as a fraction of usual: holding 80% keeps the feel for
+// the sport; below 60% needs adjusting
+const (
+	frequencyGood  = 0.80
+	frequencyWatch = 0.60
+)
+
+// intensityWatch is the fraction of usual hard sessions below which intensity
+// is slipping
+const intensityWatch = 0.5
+
+// progressionSlack is how much a taper week may grow on the week before
+const progressionSlack = 0.05
+
+// rampWatch is how far above the usual weekly load the last week may be
+// before the build is ramping too fast for the time left
+const rampWatch = 1.3
+
+// Race is the race being tapered for
+type Race struct {
+	Date     time.Time
+	Distance float64 // meters
+	Type     string  // activity type
+}
+
+// Window is the recommended taper length in days for a race, longer for
+// longer races
+func Window(r Race) (minDays, maxDays int) {
+	var short, medium float64
+	switch thresholds.Sport(r.Type) {
+	case thresholds.SportRun:
+		short, medium = 10500, 21500
+	case thresholds.SportRide:
+		short, medium = 50000, 120000
+	default:
+		return 10, 14
+	}
+	switch {
+	case r.Distance <= short:
+		return 7, 10
+	case r.Distance <= medium:
+		return 10, 14
+	}
+	return 14, 21
+}
+
+// Load is training per week over a span of days
+type Load struct {
+	Start        time.Time // first day of the span
+	Days         int
+	Seconds      float64
+	Meters       float64
+	Sessions     float64
+	HardSessions float64
+}
+
+// weekly scales the load to seven days
+func (l Load) weekly() Load {
+	if l.Days <= 0 || l.Days == 7 {
+		return l
+	}
+	f := 7 / float64(l.Days)
+	l.Seconds *= f
+	l.Meters *= f
+	l.Sessions *= f
+	l.HardSessions *= f
+	return l
+}
+
+// Check is one piece of taper guidance and how the athlete measures up
+type Check struct {
+	Name   string
+	Status string // good, watch or adjust
+	// Value is the measurement and Low to High the range aimed for; each is a
+	// fraction, e.g. 0.45 for a 45% volume cut
+	Value float64
+	Low   float64
+	High  float64
+}
+
+// Assessment is the athlete's readiness for a race
+type Assessment struct {
+	DaysToRace int
+	MinTaper   int
+	MaxTaper   int
+	TaperStart time.Time
+	Phase      string
+	Status     string
+	// Baseline is the weekly load over the four weeks before the taper, or
+	// the last four weeks when the taper is still ahead
+	Baseline Load
+	// Weeks are the last three 7-day spans ending today, oldest first
+	Weeks []Load
+	// Recent is the weekly rate over the last week, or over the taper so far
+	// when it started less than a week ago
+	Recent Load
+	Checks []Check
+	// TargetSeconds is the weekly moving time aimed for between today and
+	// the race
+	TargetSeconds float64
+}
+
+// Check returns the named check, if it was made
+func (a Assessment) Check(name string) (Check, bool) {
+	for _, c := range a.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Check{}, false
+}
+
+// Assess rates sessions against the taper guidance for race as of today.
+// Sessions should cover at least the seven weeks before the race or today,
+// whichever is earlier.
+func Assess(race Race, sessions []blocks.Session, today time.Time) Assessment {
+	today = day(today)
+	raceDay := day(race.Date)
+	a := Assessment{DaysToRace: int(math.Round(raceDay.Sub(today).Hours() / 24))}
+	a.MinTaper, a.MaxTaper = Window(race)
+	taperDays := (a.MinTaper + a.MaxTaper) / 2
+	a.TaperStart = raceDay.AddDate(0, 0, -taperDays)
+
+	switch {
+	case a.DaysToRace < 0:
+		a.Phase, a.Status = PhasePassed, StatusPassed
+		return a
+	case a.DaysToRace <= 7:
+		a.Phase = PhaseRaceWeek
+	case a.DaysToRace <= taperDays:
+		a.Phase = PhaseTaper
+	default:
+		a.Phase = PhaseBuilding
+	}
+
+	for i := 2; i >= 0; i-- {
+		a.Weeks = append(a.Weeks, load(sessions, today.AddDate(0, 0, -7*i-6), 7))
+	}
+	a.Recent = a.Weeks[2]
+
+	if a.Phase == PhaseBuilding {
+		a.Baseline = load(sessions, today.AddDate(0, 0, -baselineDays+1), baselineDays).weekly()
+		a.TargetSeconds = a.Baseline.Seconds
+		a.Checks = append(a.Checks, rampCheck(a))
+		a.Status = StatusEarly
+		return a
+	}
+
+	a.Baseline = load(sessions, raceDay.AddDate(0, 0, -a.MaxTaper-baselineDays), baselineDays).weekly()
+	daysIn := taperDays - a.DaysToRace
+	if daysIn < 7 {
+		a.Recent = load(sessions, today.AddDate(0, 0, -daysIn+1), max(daysIn, 1)).weekly()
+	}
+	// The cut aimed for over the recent span, and over the days left
+	target := reduction(float64(a.DaysToRace)+float64(a.Recent.Days)/2, taperDays)
+	a.TargetSeconds = a.Baseline.Seconds * (1 - reduction(float64(a.DaysToRace)/2, taperDays))
+
+	if daysIn >= minTaperDays {
+		a.Checks = append(a.Checks, volumeCheck(a, target), frequencyCheck(a))
+	}
+	a.Checks = append(a.Checks, intensityCheck(a), progressionCheck(a))
+
+	a.Status = StatusOnTrack
+	if a.Phase == PhaseRaceWeek {
+		a.Status = StatusReady
+	}
+	for _, c := range a.Checks {
+		switch c.Status {
+		case Adjust:
+			a.Status = StatusAdjust
+		case Watch:
+			if a.Status == StatusReady {
+				a.Status = StatusOnTrack
+			}
+		}
+	}
+	return a
+}
+
+// reduction is the volume cut aimed for daysOut days before the race, rising
+// from startReduction when the taper starts to finalReduction on race day
+func reduction(daysOut float64, taperDays int) float64 {
+	progress := min(max(1-daysOut/float64(taperDays), 0), 1)
+	return startReduction + (finalReduction-startReduction)*progress
+}
+
+// volumeCheck compares the recent cut in volume with the cut aimed for
+func volumeCheck(a Assessment, target float64) Check {
+	c := Check{Name: CheckVolume, Low: target - reductionBand, High: target + reductionBand}
+	if a.Baseline.Seconds == 0 {
+		c.Status = Watch
+		return c
+	}
+	c.Value = 1 - a.Recent.Seconds/a.Baseline.Seconds
+	c.Status = band(c.Value, c.Low, c.High, reductionBand)
+	return c
+}
+
+// frequencyCheck compares sessions per week with usual
+func frequencyCheck(a Assessment) Check {
+	c := Check{Name: CheckFrequency, Low: frequencyGood, High: 1}
+	if a.Baseline.Sessions == 0 {
+		c.Status = Good
+		return c
+	}
+	c.Value = a.Recent.Sessions / a.Baseline.Sessions
+	switch {
+	case c.Value >= frequencyGood:
+		c.Status = Good
+	case c.Value >= frequencyWatch:
+		c.Status = Watch
+	default:
+		c.Status = Adjust
+	}
+	return c
+}
+
+// intensityCheck compares hard sessions over the last full week with usual.
+// An athlete who doesn't usually train hard has no intensity to keep.
+func intensityCheck(a Assessment) Check {
+	c := Check{Name: CheckIntensity, Low: intensityWatch, High: 1}
+	if a.Baseline.HardSessions < intensityWatch {
+		c.Status = Good
+		c.Value = 1
+		return c
+	}
+	last := a.Weeks[len(a.Weeks)-1]
+	c.Value = last.HardSessions / a.Baseline.HardSessions
+	switch {
+	case last.HardSessions == 0:
+		c.Status = Adjust
+	case c.Value < intensityWatch:
+		c.Status = Watch
+	default:
+		c.Status = Good
+	}
+	return c
+}
+
+// progressionCheck looks for a week of the taper that grew on the week
+// before. Value is the biggest such growth.
+func progressionCheck(a Assessment) Check {
+	c := Check{Name: CheckProgression, High: progressionSlack, Status: Good}
+	for i := 1; i < len(a.Weeks); i++ {
+		prev, w := a.Weeks[i-1], a.Weeks[i]
+		if w.Start.AddDate(0, 0, w.Days).Before(a.TaperStart) || prev.Seconds == 0 {
+			continue
+		}
+		growth := w.Seconds/prev.Seconds - 1
+		if growth > c.Value {
+			c.Value = growth
+		}
+	}
+	if c.Value > progressionSlack {
+		c.Status = Adjust
+	}
+	return c
+}
+
+// rampCheck compares the last week with usual before the taper starts
+func rampCheck(a Assessment) Check {
+	c := Check{Name: CheckRamp, High: rampWatch, Status: Good}
+	if a.Baseline.Seconds == 0 {
+		return c
+	}
+	c.Value = a.Recent.Seconds / a.Baseline.Seconds
+	if c.Value > rampWatch {
+		c.Status = Watch
+	}
+	return c
+}
+
+// band rates value against low to high, needing adjustment when it's more
+// than slack outside
+func band(value, low, high, slack float64) string {
+	switch {
+	case value >= low && value <= high:
+		return Good
+	case value >= low-slack && value <= high+slack:
+		return Watch
+	}
+	return Adjust
+}
+
+// load sums sessions over days days from start
+func load(sessions []blocks.Session, start time.Time, days int) Load {
+	l := Load{Start: start, Days: days}
+	end := start.AddDate(0, 0, days)
+	for _, s := range sessions {
+		d := day(s.Start)
+		if d.Before(start) || !d.Before(end) {
+			continue
+		}
+		l.Seconds += s.Seconds
+		l.Meters += s.Meters
+		l.Sessions++
+		if s.Hard() {
+			l.HardSessions++
+		}
+	}
+	return l
+}
+
+// day is midnight at the start of t's date, in UTC so that calendar dates
+// compare whatever zone they were parsed in
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
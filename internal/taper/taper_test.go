@@ -0,0 +1,156 @@
+package taper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/blocks"
+	"github.com/joshdurbin/strava-mcp/internal/workouts"
+)
+
+// raceDay is a half marathon, with a 12 day taper
+var raceDay = time.Date(2026, 11, 15, 0, 0, 0, 0, time.UTC)
+
+var halfMarathon = Race{Date: raceDay, Distance: 21097, Type: "Run"}
+
+// training builds ten weeks of runs up to today: six a week totalling eight
+// hours, two of them hard, with a rest day a week. scale sets each day's
+// volume from how many days out from the race it is; hard says whether the
+// usual hard days are kept.
+func training(today time.Time, scale func(daysOut int) float64, hard func(daysOut int) bool) []blocks.Session {
+	var out []blocks.Session
+	for d := raceDay.AddDate(0, 0, -70); !d.After(today); d = d.AddDate(0, 0, 1) {
+		daysOut := int(raceDay.Sub(d).Hours() / 24)
+		if daysOut%7 == 0 {
+			continue
+		}
+		category := workouts.CategoryEasy
+		if (daysOut%7 == 2 || daysOut%7 == 5) && hard(daysOut) {
+			category = workouts.CategoryIntervals
+		}
+		seconds := 8 * 3600.0 / 6 * scale(daysOut)
+		out = append(out, blocks.Session{
+			ID:       int64(len(out) + 1),
+			Type:     "Run",
+			Start:    d.Add(7 * time.Hour),
+			Seconds:  seconds,
+			Meters:   seconds * 3.2,
+			Category: category,
+		})
+	}
+	return out
+}
+
+// progressive cuts volume along the targeted ramp over the last 12 days
+func progressive(daysOut int) float64 {
+	if daysOut > 12 {
+		return 1
+	}
+	progress := 1 - float64(daysOut)/12
+	return 1 - (startReduction + (finalReduction-startReduction)*progress)
+}
+
+func unchanged(int) float64 { return 1 }
+
+func always(int) bool { return true }
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		race     Race
+		min, max int
+	}{
+		{Race{Distance: 5000, Type: "Run"}, 7, 10},
+		{halfMarathon, 10, 14},
+		{Race{Distance: 42195, Type: "Run"}, 14, 21},
+		{Race{Distance: 160000, Type: "Ride"}, 14, 21},
+		{Race{Distance: 1500, Type: "Swim"}, 10, 14},
+	}
+	for _, tt := range tests {
+		if lo, hi := Window(tt.race); lo != tt.min || hi != tt.max {
+			t.Errorf("%.0fm %s: expected %d-%d days, got %d-%d", tt.race.Distance, tt.race.Type, tt.min, tt.max, lo, hi)
+		}
+	}
+}
+
+func TestAssessOnTrack(t *testing.T) {
+	today := raceDay.AddDate(0, 0, -4)
+	a := Assess(halfMarathon, training(today, progressive, always), today)
+
+	if a.Phase != PhaseRaceWeek || a.DaysToRace != 4 {
+		t.Fatalf("expected race week, 4 days out, got %s, %d", a.Phase, a.DaysToRace)
+	}
+	if a.Status != StatusReady {
+		t.Errorf("expected ready, got %s: %+v", a.Status, a.Checks)
+	}
+	if a.Baseline.Seconds != 8*3600 || a.Baseline.Sessions != 6 || a.Baseline.HardSessions != 2 {
+		t.Errorf("unexpected baseline: %+v", a.Baseline)
+	}
+	if c, ok := a.Check(CheckVolume); !ok || c.Value < 0.35 || c.Value > 0.5 {
+		t.Errorf("expected a cut of about 40%%, got %+v", c)
+	}
+}
+
+func TestAssessUndertapered(t *testing.T) {
+	today := raceDay.AddDate(0, 0, -4)
+	a := Assess(halfMarathon, training(today, unchanged, always), today)
+
+	if a.Status != StatusAdjust {
+		t.Errorf("expected adjust, got %s", a.Status)
+	}
+	if c, _ := a.Check(CheckVolume); c.Status != Adjust || c.Value != 0 {
+		t.Errorf("expected no cut in volume to need adjusting, got %+v", c)
+	}
+	if a.TargetSeconds > a.Baseline.Seconds*0.6 {
+		t.Errorf("expected a target well below the usual %.0fs, got %.0fs", a.Baseline.Seconds, a.TargetSeconds)
+	}
+}
+
+func TestAssessIntensityDropped(t *testing.T) {
+	today := raceDay.AddDate(0, 0, -3)
+	easyTaper := func(daysOut int) bool { return daysOut > 12 }
+	a := Assess(halfMarathon, training(today, progressive, easyTaper), today)
+
+	if c, _ := a.Check(CheckIntensity); c.Status != Adjust {
+		t.Errorf("expected dropping all hard sessions to need adjusting, got %+v", c)
+	}
+	if c, _ := a.Check(CheckVolume); c.Status != Good {
+		t.Errorf("expected the volume cut to be good, got %+v", c)
+	}
+}
+
+func TestAssessProgression(t *testing.T) {
+	// Cutting hard, then doing more again
+	bounce := func(daysOut int) float64 {
+		switch {
+		case daysOut > 12:
+			return 1
+		case daysOut > 5:
+			return 0.4
+		}
+		return 0.7
+	}
+	today := raceDay.AddDate(0, 0, -1)
+	a := Assess(halfMarathon, training(today, bounce, always), today)
+	if c, _ := a.Check(CheckProgression); c.Status != Adjust {
+		t.Errorf("expected volume rising in the taper to need adjusting, got %+v", c)
+	}
+}
+
+func TestAssessEarly(t *testing.T) {
+	today := raceDay.AddDate(0, 0, -30)
+	a := Assess(halfMarathon, training(today, unchanged, always), today)
+	if a.Phase != PhaseBuilding || a.Status != StatusEarly {
+		t.Errorf("expected building and early, got %s and %s", a.Phase, a.Status)
+	}
+	if !a.TaperStart.Equal(raceDay.AddDate(0, 0, -12)) {
+		t.Errorf("expected the taper to start 12 days out, got %s", a.TaperStart)
+	}
+	if c, _ := a.Check(CheckRamp); c.Status != Good {
+		t.Errorf("expected a steady load to be fine, got %+v", c)
+	}
+
+	a = Assess(halfMarathon, nil, raceDay.AddDate(0, 0, 1))
+	if a.Status != StatusPassed || len(a.Checks) != 0 {
+		t.Errorf("expected a passed race with no checks, got %+v", a)
+	}
+}
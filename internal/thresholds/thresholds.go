@@ -0,0 +1,347 @@
+// Package thresholds estimates lactate threshold heart rate, functional
+// threshold power and threshold pace from the hardest 20 minutes of each
+// activity's streams, and records each time an estimate rises.
+package thresholds
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+// Metrics
+const (
+	MetricLTHR          = "lthr"
+	MetricFTP           = "ftp"
+	MetricThresholdPace = "threshold_pace" // stored as a speed in m/s
+)
+
+// Sports thresholds are tracked per; running and cycling thresholds differ
+const (
+	SportRun  = "run"
+	SportRide = "ride"
+)
+
+// Tracked lists the metric and sport pairs with a threshold history
+var Tracked = []struct{ Metric, Sport string }{
+	{MetricLTHR, SportRun},
+	{MetricThresholdPace, SportRun},
+	{MetricLTHR, SportRide},
+	{MetricFTP, SportRide},
+}
+
+// StaleAfter is how long a threshold goes unconfirmed before it's worth
+// retesting
+const StaleAfter = 90 * 24 * time.Hour
+
+// window is the effort length, in seconds, estimates are taken from
+const window = 20 * 60
+
+// maxSampleGap caps the seconds credited to a single sample, so a recording
+// gap doesn't stretch one reading across minutes
+const maxSampleGap = 30
+
+// Factors turning the best 20-minute mean into a threshold
+const (
+	lthrFactor = 0.95 // Friel's 20-minute test
+	ftpFactor  = 0.95 // Allen and Coggan's 20-minute test
+)
+
+// speedFactor extrapolates a 20-minute speed to the hour threshold pace is
+// held for, with Riegel's fatigue exponent of 1.06
+var speedFactor = math.Pow(3, -0.06)
+
+// minRise is the fraction an estimate must beat the current threshold by to
+// count as a new one, so day-to-day noise doesn't fill the history
+const minRise = 0.02
+
+// baselineWindow is how much of the oldest data the first threshold is
+// taken from
+const baselineWindow = 90 * 24 * time.Hour
+
+// pageSize is the number of activities analyzed per query
+const pageSize = 100
+
+// Plausible ranges; anything outside is a sensor fault
+var limits = map[string][2]float64{
+	MetricLTHR:          {100, 210},
+	MetricFTP:           {50, 600},
+	MetricThresholdPace: {1.5, 7},
+}
+
+// Sport returns the sport an activity type's thresholds count toward, or ""
+func Sport(activityType string) string {
+	switch activityType {
+	case "Run", "VirtualRun", "TrailRun":
+		return SportRun
+	case "Ride", "VirtualRide", "GravelRide", "MountainBikeRide":
+		return SportRide
+	}
+	return ""
+}
+
+// Estimate is one activity's threshold estimates; zero when unavailable
+type Estimate struct {
+	LTHR           float64 // bpm
+	FTP            float64 // watts
+	ThresholdSpeed float64 // m/s
+}
+
+// Analyze estimates thresholds from an activity's best 20 minutes. FTP comes
+// from rides only and threshold pace from road and treadmill runs only,
+// since trail pace says more about the terrain than the runner.
+func Analyze(st *streams.Streams, activityType string) Estimate {
+	var e Estimate
+	sport := Sport(activityType)
+	if sport == "" {
+		return e
+	}
+
+	if hr, ok := BestMean(st.Time, st.Heartrate, st.Moving, false); ok {
+		e.LTHR = plausible(MetricLTHR, math.Round(hr*lthrFactor))
+	}
+	if sport == SportRide {
+		// Coasting counts: zero watts is part of the effort
+		if watts, ok := BestMean(st.Time, st.Watts, st.Moving, true); ok {
+			e.FTP = plausible(MetricFTP, math.Round(watts*ftpFactor))
+		}
+	}
+	if activityType == "Run" || activityType == "VirtualRun" {
+		if speed, ok := BestMean(st.Time, st.Velocity, st.Moving, false); ok {
+			e.ThresholdSpeed = plausible(MetricThresholdPace, math.Round(speed*speedFactor*1000)/1000)
+		}
+	}
+	return e
+}
+
+func plausible(metric string, v float64) float64 {
+	if l := limits[metric]; v < l[0] || v > l[1] {
+		return 0
+	}
+	return v
+}
+
+// BestMean returns the highest time-weighted mean of values over any 20
+// minutes of moving time. Samples flagged as not moving are skipped, as are
+// zero values unless includeZero is set. ok is false with under 20 minutes.
+func BestMean(time []int, values []float64, moving []bool, includeZero bool) (float64, bool) {
+	n := min(len(time), len(values))
+	if n < 2 {
+		return 0, false
+	}
+
+	// Running totals of counted seconds and value-seconds, so any window's
+	// mean is a difference of two entries
+	secs := make([]float64, n)
+	sums := make([]float64, n)
+	for i := 1; i < n; i++ {
+		dt := float64(min(time[i]-time[i-1], maxSampleGap))
+		v := values[i]
+		if dt < 0 || (i < len(moving) && !moving[i]) || v < 0 || (v == 0 && !includeZero) {
+			dt = 0
+		}
+		secs[i] = secs[i-1] + dt
+		sums[i] = sums[i-1] + v*dt
+	}
+
+	best, found := 0.0, false
+	start := 0
+	for end := 1; end < n; end++ {
+		// Keep the window as short as it can be while still 20 minutes long
+		for start+1 < end && secs[end]-secs[start+1] >= window {
+			start++
+		}
+		if d := secs[end] - secs[start]; d >= window {
+			if mean := (sums[end] - sums[start]) / d; mean > best {
+				best, found = mean, true
+			}
+		}
+	}
+	return best, found
+}
+
+// Store is the subset of queries needed to detect thresholds
+type Store interface {
+	ListActivitiesNeedingThresholdAnalysis(ctx context.Context, limit int64) ([]db.ListActivitiesNeedingThresholdAnalysisRow, error)
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+	UpsertThresholdEffort(ctx context.Context, arg db.UpsertThresholdEffortParams) error
+	ListThresholdEfforts(ctx context.Context, sport string) ([]db.ThresholdEffort, error)
+	GetLatestThreshold(ctx context.Context, arg db.GetLatestThresholdParams) (db.ThresholdHistory, error)
+	CreateThresholdHistory(ctx context.Context, arg db.CreateThresholdHistoryParams) error
+}
+
+// Result summarizes a Run
+type Result struct {
+	Analyzed int                               // activities analyzed
+	Detected []db.CreateThresholdHistoryParams // new thresholds, oldest first
+}
+
+// Run analyzes activities with streams that haven't been analyzed yet, then
+// records any estimate that beats the current threshold by minRise.
+// Thresholds only move up: a month of easy runs says nothing about
+// threshold, so a threshold stands until a harder effort replaces it.
+func Run(ctx context.Context, store Store) (Result, error) {
+	var result Result
+
+	for {
+		rows, err := store.ListActivitiesNeedingThresholdAnalysis(ctx, pageSize)
+		if err != nil {
+			return result, fmt.Errorf("listing activities needing threshold analysis: %w", err)
+		}
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			if err := analyzeActivity(ctx, store, row); err != nil {
+				return result, err
+			}
+			result.Analyzed++
+		}
+		if len(rows) < pageSize {
+			break
+		}
+	}
+
+	efforts := make(map[string][]db.ThresholdEffort)
+	for _, t := range Tracked {
+		if _, ok := efforts[t.Sport]; !ok {
+			rows, err := store.ListThresholdEfforts(ctx, t.Sport)
+			if err != nil {
+				return result, fmt.Errorf("listing %s threshold efforts: %w", t.Sport, err)
+			}
+			efforts[t.Sport] = rows
+		}
+		detected, err := detect(ctx, store, t.Metric, t.Sport, efforts[t.Sport])
+		if err != nil {
+			return result, err
+		}
+		result.Detected = append(result.Detected, detected...)
+	}
+	return result, nil
+}
+
+func analyzeActivity(ctx context.Context, store Store, row db.ListActivitiesNeedingThresholdAnalysisRow) error {
+	var e Estimate
+	streamRow, err := store.GetActivityStreams(ctx, row.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("loading streams for activity %d: %w", row.ID, err)
+	}
+	if err == nil {
+		st, err := streams.FromRow(streamRow)
+		if err != nil {
+			logging.Warn("Skipping unreadable activity streams", "activity_id", row.ID, "error", err)
+		} else {
+			e = Analyze(st, row.Type.String)
+		}
+	}
+
+	// A row is stored even without estimates so the activity isn't retried
+	err = store.UpsertThresholdEffort(ctx, db.UpsertThresholdEffortParams{
+		ActivityID:     row.ID,
+		Sport:          Sport(row.Type.String),
+		StartDate:      row.StartDate,
+		Lthr:           nullIfZero(e.LTHR),
+		Ftp:            nullIfZero(e.FTP),
+		ThresholdSpeed: nullIfZero(e.ThresholdSpeed),
+	})
+	if err != nil {
+		return fmt.Errorf("saving threshold effort: %w", err)
+	}
+	return nil
+}
+
+// detect walks a sport's efforts oldest first and records each one that
+// beats the current threshold. With no history yet, the first threshold is
+// the best effort from the oldest baselineWindow of data, rather than
+// whatever easy activity happens to come first.
+func detect(ctx context.Context, store Store, metric, sport string, efforts []db.ThresholdEffort) ([]db.CreateThresholdHistoryParams, error) {
+	var candidates []db.ThresholdEffort
+	for _, e := range efforts {
+		if Value(metric, e) > 0 {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var detected []db.CreateThresholdHistoryParams
+	record := func(e db.ThresholdEffort, previous float64) error {
+		arg := db.CreateThresholdHistoryParams{
+			Metric:        metric,
+			Sport:         sport,
+			Value:         Value(metric, e),
+			PreviousValue: nullIfZero(previous),
+			ActivityID:    e.ActivityID,
+			DetectedOn:    e.StartDate.Time,
+		}
+		if err := store.CreateThresholdHistory(ctx, arg); err != nil {
+			return fmt.Errorf("saving %s %s threshold: %w", sport, metric, err)
+		}
+		detected = append(detected, arg)
+		return nil
+	}
+
+	latest, err := store.GetLatestThreshold(ctx, db.GetLatestThresholdParams{Metric: metric, Sport: sport})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("loading current %s %s threshold: %w", sport, metric, err)
+	}
+
+	current, after := latest.Value, latest.DetectedOn
+	if err == sql.ErrNoRows {
+		cutoff := candidates[0].StartDate.Time.Add(baselineWindow)
+		best := candidates[0]
+		for _, e := range candidates {
+			if e.StartDate.Time.After(cutoff) {
+				break
+			}
+			if Value(metric, e) > Value(metric, best) {
+				best = e
+			}
+		}
+		if err := record(best, 0); err != nil {
+			return nil, err
+		}
+		current, after = Value(metric, best), best.StartDate.Time
+	}
+
+	for _, e := range candidates {
+		if !e.StartDate.Time.After(after) {
+			continue
+		}
+		if v := Value(metric, e); v >= current*(1+minRise) {
+			if err := record(e, current); err != nil {
+				return nil, err
+			}
+			current = v
+		}
+	}
+	return detected, nil
+}
+
+// Value returns an effort's estimate for a metric, 0 when it has none
+func Value(metric string, e db.ThresholdEffort) float64 {
+	var v sql.NullFloat64
+	switch metric {
+	case MetricLTHR:
+		v = e.Lthr
+	case MetricFTP:
+		v = e.Ftp
+	case MetricThresholdPace:
+		v = e.ThresholdSpeed
+	}
+	if !v.Valid {
+		return 0
+	}
+	return v.Float64
+}
+
+func nullIfZero(v float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: v, Valid: v > 0}
+}
@@ -0,0 +1,309 @@
+package thresholds
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+// memStore is an in-memory Store
+type memStore struct {
+	activities []db.ListActivitiesNeedingThresholdAnalysisRow
+	streams    map[int64]db.ActivityStream
+	efforts    map[int64]db.ThresholdEffort
+	history    []db.ThresholdHistory
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		streams: map[int64]db.ActivityStream{},
+		efforts: map[int64]db.ThresholdEffort{},
+	}
+}
+
+func (m *memStore) ListActivitiesNeedingThresholdAnalysis(ctx context.Context, limit int64) ([]db.ListActivitiesNeedingThresholdAnalysisRow, error) {
+	var out []db.ListActivitiesNeedingThresholdAnalysisRow
+	for _, a := range m.activities {
+		if _, done := m.efforts[a.ID]; done {
+			continue
+		}
+		if _, ok := m.streams[a.ID]; !ok {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out[:min(len(out), int(limit))], nil
+}
+
+func (m *memStore) GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error) {
+	row, ok := m.streams[activityID]
+	if !ok {
+		return db.ActivityStream{}, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+func (m *memStore) UpsertThresholdEffort(ctx context.Context, arg db.UpsertThresholdEffortParams) error {
+	m.efforts[arg.ActivityID] = db.ThresholdEffort{
+		ActivityID:     arg.ActivityID,
+		Sport:          arg.Sport,
+		StartDate:      arg.StartDate,
+		Lthr:           arg.Lthr,
+		Ftp:            arg.Ftp,
+		ThresholdSpeed: arg.ThresholdSpeed,
+	}
+	return nil
+}
+
+func (m *memStore) ListThresholdEfforts(ctx context.Context, sport string) ([]db.ThresholdEffort, error) {
+	var out []db.ThresholdEffort
+	for _, e := range m.efforts {
+		if e.Sport == sport {
+			out = append(out, e)
+		}
+	}
+	slices.SortFunc(out, func(a, b db.ThresholdEffort) int {
+		return a.StartDate.Time.Compare(b.StartDate.Time)
+	})
+	return out, nil
+}
+
+func (m *memStore) GetLatestThreshold(ctx context.Context, arg db.GetLatestThresholdParams) (db.ThresholdHistory, error) {
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if h := m.history[i]; h.Metric == arg.Metric && h.Sport == arg.Sport {
+			return h, nil
+		}
+	}
+	return db.ThresholdHistory{}, sql.ErrNoRows
+}
+
+func (m *memStore) CreateThresholdHistory(ctx context.Context, arg db.CreateThresholdHistoryParams) error {
+	m.history = append(m.history, db.ThresholdHistory{
+		ID:            int64(len(m.history) + 1),
+		Metric:        arg.Metric,
+		Sport:         arg.Sport,
+		Value:         arg.Value,
+		PreviousValue: arg.PreviousValue,
+		ActivityID:    arg.ActivityID,
+		DetectedOn:    arg.DetectedOn,
+	})
+	return nil
+}
+
+func jsonColumn(t *testing.T, v any) sql.NullString {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// steady returns an hour of one-second samples at a constant value
+func steady(value float64) ([]int, []float64) {
+	times := make([]int, 3600)
+	values := make([]float64, 3600)
+	for i := range times {
+		times[i] = i
+		values[i] = value
+	}
+	return times, values
+}
+
+// addRun stores a run whose heart rate holds at hr for an hour
+func (m *memStore) addRun(t *testing.T, id int64, day time.Time, hr float64) {
+	t.Helper()
+	times, values := steady(hr)
+	m.activities = append(m.activities, db.ListActivitiesNeedingThresholdAnalysisRow{
+		ID:        id,
+		Type:      sql.NullString{String: "Run", Valid: true},
+		StartDate: sql.NullTime{Time: day, Valid: true},
+	})
+	m.streams[id] = db.ActivityStream{
+		ActivityID:    id,
+		TimeData:      jsonColumn(t, times),
+		HeartrateData: jsonColumn(t, values),
+	}
+}
+
+func TestBestMean(t *testing.T) {
+	// 10 minutes easy, 20 minutes hard, 10 minutes easy
+	times := make([]int, 2400)
+	values := make([]float64, 2400)
+	for i := range times {
+		times[i] = i
+		values[i] = 140
+		if i >= 600 && i < 1800 {
+			values[i] = 170
+		}
+	}
+
+	got, ok := BestMean(times, values, nil, false)
+	if !ok {
+		t.Fatal("expected a 20 minute window")
+	}
+	if math.Abs(got-170) > 0.1 {
+		t.Errorf("expected best mean ~170, got %.2f", got)
+	}
+}
+
+func TestBestMeanTooShort(t *testing.T) {
+	times, values := steady(150)
+	if _, ok := BestMean(times[:1000], values[:1000], nil, false); ok {
+		t.Error("expected no window under 20 minutes")
+	}
+}
+
+func TestBestMeanSkipsStopsAndGaps(t *testing.T) {
+	times, values := steady(150)
+	moving := make([]bool, len(times))
+	for i := range moving {
+		moving[i] = i >= 1800
+	}
+	if _, ok := BestMean(times, values, moving, false); !ok {
+		t.Error("expected 30 moving minutes to make a window")
+	}
+
+	// A two-hour recording gap only counts for maxSampleGap seconds
+	gapped := []int{0, 7200}
+	if _, ok := BestMean(gapped, []float64{150, 150}, nil, false); ok {
+		t.Error("expected a recording gap not to make a window")
+	}
+}
+
+func TestBestMeanZeros(t *testing.T) {
+	// Alternating 300 W and coasting averages 150 W when zeros count
+	times := make([]int, 3600)
+	values := make([]float64, 3600)
+	for i := range times {
+		times[i] = i
+		if i%2 == 0 {
+			values[i] = 300
+		}
+	}
+
+	withZeros, _ := BestMean(times, values, nil, true)
+	if math.Abs(withZeros-150) > 1 {
+		t.Errorf("expected ~150 W including zeros, got %.1f", withZeros)
+	}
+	withoutZeros, _ := BestMean(times, values, nil, false)
+	if math.Abs(withoutZeros-300) > 1 {
+		t.Errorf("expected ~300 W excluding zeros, got %.1f", withoutZeros)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	times, hr := steady(170)
+	_, watts := steady(280)
+	_, speed := steady(4)
+	st := &streams.Streams{Time: times, Heartrate: hr, Watts: watts, Velocity: speed}
+
+	ride := Analyze(st, "Ride")
+	if ride.LTHR != 162 || ride.FTP != 266 || ride.ThresholdSpeed != 0 {
+		t.Errorf("unexpected ride estimate %+v", ride)
+	}
+
+	run := Analyze(st, "Run")
+	if run.LTHR != 162 || run.FTP != 0 {
+		t.Errorf("unexpected run estimate %+v", run)
+	}
+	if want := 4 * speedFactor; math.Abs(run.ThresholdSpeed-want) > 0.001 {
+		t.Errorf("expected threshold speed %.3f, got %.3f", want, run.ThresholdSpeed)
+	}
+
+	if trail := Analyze(st, "TrailRun"); trail.ThresholdSpeed != 0 || trail.LTHR == 0 {
+		t.Errorf("expected trail runs to give heart rate but not pace, got %+v", trail)
+	}
+	if swim := Analyze(st, "Swim"); swim != (Estimate{}) {
+		t.Errorf("expected no estimate for a swim, got %+v", swim)
+	}
+}
+
+func TestAnalyzeImplausible(t *testing.T) {
+	times, hr := steady(250)
+	if e := Analyze(&streams.Streams{Time: times, Heartrate: hr}, "Run"); e.LTHR != 0 {
+		t.Errorf("expected implausible heart rate to be dropped, got %v", e.LTHR)
+	}
+}
+
+func TestRunDetectsRises(t *testing.T) {
+	ctx := context.Background()
+	m := newMemStore()
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	// Baseline is the best of the first 90 days, not the first activity
+	m.addRun(t, 1, start, 160)
+	m.addRun(t, 2, start.AddDate(0, 0, 30), 175)
+	m.addRun(t, 3, start.AddDate(0, 0, 60), 165)
+	// Under minRise: noise, not a new threshold
+	m.addRun(t, 4, start.AddDate(0, 0, 120), 177)
+	// A real rise
+	m.addRun(t, 5, start.AddDate(0, 0, 150), 182)
+
+	result, err := Run(ctx, m)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Analyzed != 5 {
+		t.Errorf("expected 5 activities analyzed, got %d", result.Analyzed)
+	}
+
+	var lthr []db.ThresholdHistory
+	for _, h := range m.history {
+		if h.Metric == MetricLTHR && h.Sport == SportRun {
+			lthr = append(lthr, h)
+		}
+	}
+	if len(lthr) != 2 {
+		t.Fatalf("expected baseline and one rise, got %+v", lthr)
+	}
+	if lthr[0].ActivityID != 2 || lthr[0].PreviousValue.Valid {
+		t.Errorf("expected baseline from activity 2 with no previous value, got %+v", lthr[0])
+	}
+	if lthr[1].ActivityID != 5 || lthr[1].PreviousValue.Float64 != lthr[0].Value {
+		t.Errorf("expected rise from activity 5 over the baseline, got %+v", lthr[1])
+	}
+
+	// A second run has nothing new to analyze or detect
+	result, err = Run(ctx, m)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if result.Analyzed != 0 || len(result.Detected) != 0 {
+		t.Errorf("expected no new work, got %+v", result)
+	}
+
+	// Later efforts are compared against the stored threshold; lower ones
+	// leave it standing
+	m.addRun(t, 6, start.AddDate(0, 0, 200), 150)
+	m.addRun(t, 7, start.AddDate(0, 0, 210), 190)
+	result, err = Run(ctx, m)
+	if err != nil {
+		t.Fatalf("third Run: %v", err)
+	}
+	if len(result.Detected) != 1 || result.Detected[0].ActivityID != 7 {
+		t.Errorf("expected only activity 7 detected, got %+v", result.Detected)
+	}
+}
+
+func TestSport(t *testing.T) {
+	tests := map[string]string{
+		"Run":         SportRun,
+		"TrailRun":    SportRun,
+		"VirtualRide": SportRide,
+		"GravelRide":  SportRide,
+		"Swim":        "",
+	}
+	for activityType, want := range tests {
+		if got := Sport(activityType); got != want {
+			t.Errorf("Sport(%q) = %q, want %q", activityType, got, want)
+		}
+	}
+}
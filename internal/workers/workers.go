@@ -3,14 +3,21 @@ package workers
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/joshdurbin/strava-mcp/internal/auth"
 	"github.com/joshdurbin/strava-mcp/internal/db"
 	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/quality"
+	"github.com/joshdurbin/strava-mcp/internal/routes"
 	"github.com/joshdurbin/strava-mcp/internal/strava"
 	syncsvc "github.com/joshdurbin/strava-mcp/internal/sync"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+	"github.com/joshdurbin/strava-mcp/internal/workouts"
+	"github.com/joshdurbin/strava-mcp/internal/zones"
 )
 
 // TokenRefresher keeps auth tokens up to date
@@ -92,160 +99,417 @@ func (t *TokenRefresher) checkAndRefresh() {
 	}
 }
 
-// ActivitySyncer periodically syncs activities from Strava
-type ActivitySyncer struct {
-	queries     *db.Queries
-	storage     *auth.Storage
-	interval    time.Duration
-	retryConfig strava.RetryConfig
+// SyncJobPollInterval is how often the sync scheduler runs queued jobs that
+// have come due, such as retries, between scheduling passes
+const SyncJobPollInterval = time.Minute
+
+// finishedJobRetention is how long finished list page and activity jobs are
+// kept. Finished per-activity data jobs are kept for good, so an activity
+// Strava has no streams, laps or zones for is not fetched again.
+const finishedJobRetention = 7 * 24 * time.Hour
+
+// maxConsecutiveJobFailures ends a run of the queue when jobs keep failing,
+// so a Strava outage does not use up every job's attempts
+const maxConsecutiveJobFailures = 3
+
+// SyncScheduler syncs activities, streams, laps, zones and optionally kudos
+// and comments from Strava through the durable sync_jobs queue. Every
+// interval it queues the pages of new activities and the per-activity
+// fetches still missing; it runs due jobs highest priority first while there
+// is rate limit headroom, retrying failures with backoff and dead-lettering
+// jobs that keep failing.
+type SyncScheduler struct {
+	queries         *db.Queries
+	storage         *auth.Storage
+	interval        time.Duration
+	pollInterval    time.Duration
+	premiumRequired bool // Set to true if we detect zones need Summit
+	socialLists     bool
+	retryConfig     strava.RetryConfig
 }
 
-// NewActivitySyncer creates a new activity sync worker
-func NewActivitySyncer(queries *db.Queries, storage *auth.Storage, interval time.Duration, retryConfig strava.RetryConfig) *ActivitySyncer {
-	return &ActivitySyncer{
-		queries:     queries,
-		storage:     storage,
-		interval:    interval,
-		retryConfig: retryConfig,
+// NewSyncScheduler creates a new sync scheduler worker. socialLists enables
+// fetching who gave kudos and the comments, one or two requests per activity.
+func NewSyncScheduler(queries *db.Queries, storage *auth.Storage, interval time.Duration, retryConfig strava.RetryConfig, socialLists bool) *SyncScheduler {
+	return &SyncScheduler{
+		queries:      queries,
+		storage:      storage,
+		interval:     interval,
+		pollInterval: SyncJobPollInterval,
+		socialLists:  socialLists,
+		retryConfig:  retryConfig,
 	}
 }
 
-// Run starts the activity sync worker
-func (a *ActivitySyncer) Run(ctx context.Context) {
+// Run starts the sync scheduler worker
+func (s *SyncScheduler) Run(ctx context.Context) {
 	log := logging.Logger
-	log.Info().Dur("interval", a.interval).Msg("activity syncer started")
+	log.Info().Dur("interval", s.interval).Dur("poll_interval", s.pollInterval).Msg("sync scheduler started")
 
-	ticker := time.NewTicker(a.interval)
-	defer ticker.Stop()
+	// Jobs a crash or shutdown left running are picked up again
+	if resumed, err := s.queries.ResetRunningSyncJobs(ctx, time.Now().UTC()); err != nil {
+		log.Error().Err(err).Msg("failed to reset running sync jobs")
+	} else if resumed > 0 {
+		log.Info().Int64("jobs", resumed).Msg("resuming interrupted sync jobs")
+	}
+
+	s.schedule(ctx)
+	s.runJobs(ctx)
 
-	// Do an initial sync
-	a.syncActivities(ctx)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	poll := time.NewTicker(s.pollInterval)
+	defer poll.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Info().Msg("activity syncer stopped")
+			log.Info().Msg("sync scheduler stopped")
 			return
 		case <-ticker.C:
-			a.syncActivities(ctx)
+			s.schedule(ctx)
+			s.runJobs(ctx)
+		case <-poll.C:
+			s.runJobs(ctx)
 		}
 	}
 }
 
-func (a *ActivitySyncer) syncActivities(ctx context.Context) {
+// schedule queues the first page of a full sync when one is needed, the
+// first page of new activities unless pages are still queued from the last
+// pass, and the streams, laps, zones and kudos and comments still missing
+func (s *SyncScheduler) schedule(ctx context.Context) {
 	log := logging.Logger
-	log.Info().Msg("starting activity sync")
+	now := time.Now().UTC()
 
-	// Get valid access token
-	accessToken, err := a.storage.GetValidAccessToken()
+	s.scheduleFullSync(ctx, now)
+
+	active, err := s.queries.CountActiveDeltaPages(ctx)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to get access token for sync")
+		log.Error().Err(err).Msg("failed to count queued activity pages")
 		return
 	}
+	if active == 0 {
+		latestDate, err := s.getLatestActivityDate(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to get latest activity date")
+			return
+		}
+		// Without activities the full sync fetches them all
+		if !latestDate.IsZero() {
+			if err := syncsvc.EnqueueJob(ctx, s.queries, syncsvc.JobListPage, 1, syncsvc.PriorityListPage, latestDate, now); err != nil {
+				log.Error().Err(err).Msg("failed to queue activity sync")
+				return
+			}
+			log.Info().Str("since", latestDate.Format(time.RFC3339)).Msg("queued delta sync")
+		}
+	}
 
-	// Create Strava client with retry config
-	client := strava.NewClientWithRetryConfig(accessToken, a.retryConfig)
+	// A zones job dead-lettered for Summit means none will succeed
+	if !s.premiumRequired {
+		dead, err := s.queries.CountDeadSyncJobsWithError(ctx, db.CountDeadSyncJobsWithErrorParams{
+			Kind:      syncsvc.JobZones,
+			LastError: sql.NullString{String: syncsvc.ErrPremiumRequired.Error(), Valid: true},
+		})
+		if err == nil && dead > 0 {
+			s.premiumRequired = true
+			log.Info().Msg("zone sync disabled - Activity Zones API requires Strava Summit (premium) subscription")
+		}
+	}
 
-	// Wait for rate limits before starting (in case we're approaching limits from previous sync)
-	if err := client.WaitForRateLimit(ctx); err != nil {
-		log.Info().Err(err).Msg("activity sync cancelled while waiting for rate limit")
-		return
+	queued, err := syncsvc.EnqueueActivityDataJobs(ctx, s.queries, !s.premiumRequired, now)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to queue activity data sync")
+	} else if queued > 0 {
+		log.Info().Int64("jobs", queued).Msg("queued streams, laps and zones sync")
 	}
 
-	// Get the latest activity date for delta sync
-	latestDate, err := a.getLatestActivityDate(ctx)
+	if s.socialLists {
+		queued, err := syncsvc.EnqueueSocialJobs(ctx, s.queries, now)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to queue kudos and comment sync")
+		} else if queued > 0 {
+			log.Info().Int64("jobs", queued).Msg("queued kudos and comment sync")
+		}
+	}
+
+	cutoff := sql.NullTime{Time: now.Add(-finishedJobRetention), Valid: true}
+	if _, err := s.queries.DeleteFinishedSyncJobs(ctx, cutoff); err != nil {
+		log.Warn().Err(err).Msg("failed to prune finished sync jobs")
+	}
+}
+
+// scheduleFullSync queues a full sync when there are no activities or a
+// backfill needs one, unless one is still paging. A full sync that stopped at
+// a dead-lettered page is resumed from that page, so the older activities
+// after it are not left for a delta sync to skip, up to fullSyncMaxResumes
+// times; after that the page stays dead and no new full sync is queued.
+func (s *SyncScheduler) scheduleFullSync(ctx context.Context, now time.Time) {
+	log := logging.Logger
+
+	active, err := s.queries.CountActiveFullSyncPages(ctx)
 	if err != nil {
-		log.Warn().Err(err).Msg("failed to get latest activity date, doing full sync")
+		log.Error().Err(err).Msg("failed to count queued full sync pages")
+		return
+	}
+	if active > 0 {
+		return
 	}
 
-	var activities []strava.Activity
+	page, err := s.queries.GetDeadFullSyncPage(ctx)
+	if err == nil {
+		s.resumeFullSync(ctx, page, now)
+		return
+	}
+	if err != sql.ErrNoRows {
+		log.Error().Err(err).Msg("failed to look for an unfinished full sync")
+		return
+	}
 
-	progressCallback := func(result strava.FetchResult) {
-		rl := result.RateLimit
-		logEvent := log.Debug()
-		// Upgrade to Info level if retrying or rate limited
-		if result.IsRetrying || rl.IsRateLimited {
-			logEvent = log.Info()
-		}
-		logEvent.
-			Int("page", result.Page).
-			Int("activities_on_page", len(result.Activities)).
-			Int("total_fetched", result.TotalFetched).
-			Str("15min_usage", fmt.Sprintf("%d/%d", rl.Usage15Min, rl.Limit15Min)).
-			Str("daily_usage", fmt.Sprintf("%d/%d", rl.UsageDaily, rl.LimitDaily)).
-			Bool("is_retrying", result.IsRetrying).
-			Bool("rate_limited", rl.IsRateLimited).
-			Msg("activity sync progress")
+	if !s.needsFullSync(ctx) {
+		return
 	}
+	if err := syncsvc.EnqueueJob(ctx, s.queries, syncsvc.JobListPage, 1, syncsvc.PriorityListPage, time.Time{}, now); err != nil {
+		log.Error().Err(err).Msg("failed to queue full sync")
+		return
+	}
+	setFullSyncResumes(ctx, s.queries, 0, now)
+	// Recorded once the last page comes back empty
+	log.Info().Msg("queued full sync")
+}
 
-	if !latestDate.IsZero() {
-		log.Info().Str("since", latestDate.Format(time.RFC3339)).Msg("performing delta sync")
-		activities, err = client.FetchActivitiesSince(ctx, latestDate, progressCallback)
-	} else {
-		log.Info().Msg("performing full sync")
-		activities, err = client.FetchAllActivities(ctx, progressCallback)
+// resumeFullSync queues a full sync's dead-lettered page again, unless the
+// full sync has been resumed fullSyncMaxResumes times already
+func (s *SyncScheduler) resumeFullSync(ctx context.Context, page db.SyncJob, now time.Time) {
+	log := logging.Logger
+
+	resumes := fullSyncResumes(ctx, s.queries)
+	if resumes >= fullSyncMaxResumes {
+		log.Debug().Int64("page", page.Target).Msg("full sync dead-lettered, not resuming")
+		return
 	}
+	if err := syncsvc.EnqueueJob(ctx, s.queries, syncsvc.JobListPage, page.Target, syncsvc.PriorityListPage, time.Time{}, now); err != nil {
+		log.Error().Err(err).Msg("failed to resume full sync")
+		return
+	}
+	setFullSyncResumes(ctx, s.queries, resumes+1, now)
+	log.Info().Int64("page", page.Target).Int("resume", resumes+1).Msg("resuming full sync")
+}
+
+// needsFullSync reports whether there are no activities yet or a backfill
+// needs every activity fetched again
+func (s *SyncScheduler) needsFullSync(ctx context.Context) bool {
+	latestDate, err := s.getLatestActivityDate(ctx)
+	if err != nil {
+		logging.Logger.Error().Err(err).Msg("failed to get latest activity date")
+		return false
+	}
+	return latestDate.IsZero() || needsRouteBackfill(ctx, s.queries) || needsSocialBackfill(ctx, s.queries)
+}
+
+// runJobs runs the queued jobs that are due, if there are any
+func (s *SyncScheduler) runJobs(ctx context.Context) {
+	log := logging.Logger
 
+	ready, err := s.queries.CountReadySyncJobs(ctx, time.Now().UTC())
 	if err != nil {
-		log.Error().Err(err).Msg("failed to fetch activities")
+		log.Error().Err(err).Msg("failed to count due sync jobs")
+		return
+	}
+	if ready == 0 {
+		log.Debug().Msg("no sync jobs due")
 		return
 	}
 
-	if len(activities) == 0 {
+	accessToken, err := s.storage.GetValidAccessToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get access token for sync")
+		return
+	}
+
+	client := strava.NewClientWithRetryConfig(accessToken, s.retryConfig)
+	log.Info().Int64("jobs_due", ready).Msg("starting sync jobs")
+	s.runQueue(ctx, client)
+}
+
+// syncJobStats counts what a run of the queue did
+type syncJobStats struct {
+	done       int
+	retried    int
+	dead       int
+	activities int // activities saved
+	streams    int // activities with streams stored
+}
+
+// runQueue claims and runs due jobs until none are left, the rate limit
+// budget refuses a job or jobs keep failing. New activities and streams are
+// analysed once the run ends.
+func (s *SyncScheduler) runQueue(ctx context.Context, client *strava.Client) {
+	log := logging.Logger
+	syncService := syncsvc.NewService(s.queries, client)
+
+	var stats syncJobStats
+	defer func() {
+		if stats.activities > 0 {
+			DetectRoutes(ctx, s.queries)
+		}
+		if stats.streams > 0 {
+			ComputeLocalZones(ctx, s.queries)
+			AnalyzeThresholds(ctx, s.queries)
+		}
+		if stats.activities > 0 || stats.streams > 0 {
+			ClassifyWorkouts(ctx, s.queries)
+			CheckDataQuality(ctx, s.queries)
+		}
+
 		rl := client.GetRateLimit()
 		log.Info().
+			Int("done", stats.done).
+			Int("retried", stats.retried).
+			Int("dead", stats.dead).
+			Int("activities_saved", stats.activities).
 			Str("15min_usage", fmt.Sprintf("%d/%d", rl.Usage15Min, rl.Limit15Min)).
 			Str("daily_usage", fmt.Sprintf("%d/%d", rl.UsageDaily, rl.LimitDaily)).
-			Msg("no new activities to sync")
+			Msg("sync jobs completed")
+	}()
+
+	failures := 0
+	for ctx.Err() == nil {
+		// The shared rate limit budget paces each request, waiting for the
+		// next window or refusing once the day's share is used up
+		now := time.Now().UTC()
+		job, err := s.queries.ClaimSyncJob(ctx, db.ClaimSyncJobParams{UpdatedAt: now, RunAfter: now})
+		if err == sql.ErrNoRows {
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to claim sync job")
+			return
+		}
+
+		saved, err := syncService.RunJob(ctx, job)
+		if err == nil {
+			failures = 0
+			stats.done++
+			switch job.Kind {
+			case syncsvc.JobListPage, syncsvc.JobActivity:
+				stats.activities += saved
+			case syncsvc.JobStreams:
+				stats.streams++
+			}
+			if err := s.queries.CompleteSyncJob(ctx, db.CompleteSyncJobParams{
+				UpdatedAt:  time.Now().UTC(),
+				FinishedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+				ID:         job.ID,
+			}); err != nil {
+				log.Error().Err(err).Int64("job_id", job.ID).Msg("failed to complete sync job")
+				return
+			}
+			log.Debug().Str("kind", job.Kind).Int64("target", job.Target).Msg("sync job done")
+			if job.Kind == syncsvc.JobListPage && !job.AfterTime.Valid {
+				s.finishFullSync(ctx)
+			}
+			continue
+		}
+
+		if !s.failJob(ctx, job, err, &stats) {
+			return
+		}
+		if failures++; failures >= maxConsecutiveJobFailures {
+			log.Warn().Int("consecutive_failures", failures).Msg("sync jobs stopping after repeated failures")
+			return
+		}
+	}
+}
+
+// finishFullSync records the full sync once a page of it has come back
+// empty. A page with activities always queues the next, so no page left
+// queued means the last one has run.
+func (s *SyncScheduler) finishFullSync(ctx context.Context) {
+	active, err := s.queries.CountActiveFullSyncPages(ctx)
+	if err != nil {
+		logging.Logger.Error().Err(err).Msg("failed to count queued full sync pages")
 		return
 	}
+	if active == 0 {
+		recordFullSync(ctx, s.queries, time.Now().UTC())
+		logging.Logger.Info().Msg("full sync completed")
+	}
+}
 
-	rl := client.GetRateLimit()
-	log.Info().
-		Int("count", len(activities)).
-		Str("15min_usage", fmt.Sprintf("%d/%d", rl.Usage15Min, rl.Limit15Min)).
-		Str("daily_usage", fmt.Sprintf("%d/%d", rl.UsageDaily, rl.LimitDaily)).
-		Msg("fetched activities")
+// failJob records a failed job: put back without using an attempt when the
+// run was cancelled or rate limited, dead-lettered when retrying cannot help
+// or its attempts are used up, otherwise retried after a backoff. Returns
+// whether the run should go on.
+func (s *SyncScheduler) failJob(ctx context.Context, job db.SyncJob, jobErr error, stats *syncJobStats) bool {
+	log := logging.Logger
+	now := time.Now().UTC()
+	lastError := sql.NullString{String: jobErr.Error(), Valid: true}
 
-	// Save activities to database
-	saved := 0
-	for _, activity := range activities {
-		// Check for context cancellation
-		select {
-		case <-ctx.Done():
-			log.Info().Int("fetched", len(activities)).Int("saved", saved).Msg("sync interrupted")
-			return
-		default:
+	// The context may already be cancelled, so the job is put back regardless
+	bg := context.WithoutCancel(ctx)
+
+	switch {
+	case ctx.Err() != nil:
+		if err := s.queries.ReleaseSyncJob(bg, db.ReleaseSyncJobParams{RunAfter: now, UpdatedAt: now, ID: job.ID}); err != nil {
+			log.Error().Err(err).Int64("job_id", job.ID).Msg("failed to release sync job")
 		}
+		return false
+
+	case errors.Is(jobErr, syncsvc.ErrRateLimited) || errors.Is(jobErr, strava.ErrBudgetExhausted):
+		// Retries already waited for the window, or the shared budget has no
+		// room left for this job's class; leave the rest for the next pass
+		runAfter := now.Add(15 * time.Minute)
+		if err := s.queries.ReleaseSyncJob(ctx, db.ReleaseSyncJobParams{RunAfter: runAfter, UpdatedAt: now, ID: job.ID}); err != nil {
+			log.Error().Err(err).Int64("job_id", job.ID).Msg("failed to release sync job")
+		}
+		log.Info().Str("kind", job.Kind).Int64("target", job.Target).Msg("sync jobs stopping - rate limited")
+		return false
+
+	case syncsvc.IsPermanentJobError(jobErr) || job.Attempts+1 >= job.MaxAttempts:
+		stats.dead++
+		finished := sql.NullTime{Time: now, Valid: true}
+		if err := s.queries.DeadLetterSyncJob(ctx, db.DeadLetterSyncJobParams{LastError: lastError, UpdatedAt: now, FinishedAt: finished, ID: job.ID}); err != nil {
+			log.Error().Err(err).Int64("job_id", job.ID).Msg("failed to dead-letter sync job")
+			return false
+		}
+		log.Warn().Err(jobErr).Str("kind", job.Kind).Int64("target", job.Target).Int64("attempts", job.Attempts+1).Msg("sync job dead-lettered")
 
-		params := syncsvc.ConvertActivityToParams(activity)
-		if err := a.queries.CreateActivity(ctx, params); err != nil {
-			log.Error().
-				Int64("activity_id", activity.ID).
-				Str("activity_name", activity.Name).
-				Err(err).
-				Msg("failed to save activity")
-			continue
+		// A gap repair that stopped short says so in the repair log
+		if job.Kind == syncsvc.JobListPage && job.BeforeTime.Valid {
+			err := s.queries.SetSyncRepairError(ctx, db.SetSyncRepairErrorParams{Error: lastError, WindowStart: job.AfterTime, WindowEnd: job.BeforeTime})
+			if err != nil {
+				log.Warn().Err(err).Msg("failed to record gap repair error")
+			}
 		}
-		saved++
-		log.Debug().
-			Int64("activity_id", activity.ID).
-			Str("activity_name", activity.Name).
-			Str("activity_type", activity.Type).
-			Msg("saved activity")
-	}
 
-	rl = client.GetRateLimit()
-	log.Info().
-		Int("fetched", len(activities)).
-		Int("saved", saved).
-		Str("15min_usage", fmt.Sprintf("%d/%d", rl.Usage15Min, rl.Limit15Min)).
-		Str("daily_usage", fmt.Sprintf("%d/%d", rl.UsageDaily, rl.LimitDaily)).
-		Msg("activity sync completed")
+		if errors.Is(jobErr, syncsvc.ErrPremiumRequired) {
+			s.premiumRequired = true
+			if _, err := s.queries.DeadLetterPendingSyncJobs(ctx, db.DeadLetterPendingSyncJobsParams{
+				LastError: lastError, UpdatedAt: now, FinishedAt: finished, Kind: syncsvc.JobZones,
+			}); err != nil {
+				log.Error().Err(err).Msg("failed to dead-letter zones jobs")
+			}
+			log.Warn().
+				Str("info", "https://www.strava.com/summit").
+				Msg("zone sync disabled - Activity Zones API requires Strava Summit (premium) subscription")
+		}
+		return true
+
+	default:
+		stats.retried++
+		wait := syncsvc.JobBackoff(job.Attempts + 1)
+		if err := s.queries.RetrySyncJob(ctx, db.RetrySyncJobParams{LastError: lastError, RunAfter: now.Add(wait), UpdatedAt: now, ID: job.ID}); err != nil {
+			log.Error().Err(err).Int64("job_id", job.ID).Msg("failed to retry sync job")
+			return false
+		}
+		log.Warn().Err(jobErr).Str("kind", job.Kind).Int64("target", job.Target).Dur("retry_in", wait).Msg("sync job failed")
+		return true
+	}
 }
 
-func (a *ActivitySyncer) getLatestActivityDate(ctx context.Context) (time.Time, error) {
-	activities, err := a.queries.GetRecentActivities(ctx, 1)
+func (s *SyncScheduler) getLatestActivityDate(ctx context.Context) (time.Time, error) {
+	activities, err := s.queries.GetRecentActivities(ctx, 1)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -261,6 +525,90 @@ func (a *ActivitySyncer) getLatestActivityDate(ctx context.Context) (time.Time,
 	return time.Time{}, nil
 }
 
+// Keys recording in sync_state that a full sync has run to fill in route
+// data, and kudos and comment counts
+const (
+	routeBackfillKey  = "route_backfill"
+	socialBackfillKey = "social_backfill"
+)
+
+// backfillKeys are the backfills a full sync settles. It refreshes every
+// activity Strava still lists, so any left without the data afterwards are
+// ones Strava no longer returns, such as deleted activities, and another full
+// sync would not fill them in.
+var backfillKeys = []string{routeBackfillKey, socialBackfillKey}
+
+// fullSyncResumesKey counts in sync_state how many times the current full
+// sync has been resumed from a dead-lettered page
+const fullSyncResumesKey = "full_sync_resumes"
+
+// fullSyncMaxResumes is how many times a full sync is resumed from a
+// dead-lettered page before it is left dead, so a page that always fails is
+// not retried without end
+const fullSyncMaxResumes = 3
+
+// fullSyncResumes is how many times the current full sync has been resumed
+func fullSyncResumes(ctx context.Context, queries *db.Queries) int {
+	value, err := queries.GetSyncState(ctx, fullSyncResumesKey)
+	if err != nil {
+		return 0
+	}
+	resumes, _ := strconv.Atoi(value)
+	return resumes
+}
+
+// setFullSyncResumes records how many times the current full sync has been
+// resumed
+func setFullSyncResumes(ctx context.Context, queries *db.Queries, resumes int, now time.Time) {
+	err := queries.SetSyncState(ctx, db.SetSyncStateParams{Key: fullSyncResumesKey, Value: strconv.Itoa(resumes), UpdatedAt: now})
+	if err != nil {
+		logging.Logger.Warn().Err(err).Msg("failed to record full sync resumes")
+	}
+}
+
+// backfillAttempted reports whether a full sync has already run for the
+// backfill
+func backfillAttempted(ctx context.Context, queries *db.Queries, key string) bool {
+	_, err := queries.GetSyncState(ctx, key)
+	return err == nil
+}
+
+// recordFullSync marks each backfill as attempted once a full sync has run
+// to the last page
+func recordFullSync(ctx context.Context, queries *db.Queries, now time.Time) {
+	for _, key := range backfillKeys {
+		err := queries.SetSyncState(ctx, db.SetSyncStateParams{Key: key, Value: now.Format(time.RFC3339), UpdatedAt: now})
+		if err != nil {
+			logging.Logger.Warn().Err(err).Str("key", key).Msg("failed to record full sync")
+		}
+	}
+}
+
+// needsRouteBackfill reports whether stored activities predate route support
+// (summary_polyline is NULL rather than empty). Those rows are only refreshed
+// by a full sync, since delta sync never revisits older activities, and only
+// one full sync is run for them.
+func needsRouteBackfill(ctx context.Context, queries *db.Queries) bool {
+	missing, err := queries.CountActivitiesWithoutRouteData(ctx)
+	if err != nil || missing == 0 || backfillAttempted(ctx, queries, routeBackfillKey) {
+		return false
+	}
+	logging.Logger.Info().Int64("activities", missing).Msg("activities missing route data, performing full sync to backfill")
+	return true
+}
+
+// needsSocialBackfill reports whether stored activities predate kudos and
+// comment counts. Like route data, those are only filled in by a full sync,
+// and only one is run for them.
+func needsSocialBackfill(ctx context.Context, queries *db.Queries) bool {
+	missing, err := queries.CountActivitiesWithoutSocialCounts(ctx)
+	if err != nil || missing == 0 || backfillAttempted(ctx, queries, socialBackfillKey) {
+		return false
+	}
+	logging.Logger.Info().Int64("activities", missing).Msg("activities missing kudos and comment counts, performing full sync to backfill")
+	return true
+}
+
 // SyncOnce performs a single sync (used for initial sync on startup)
 func SyncOnce(ctx context.Context, queries *db.Queries, accessToken string, retryConfig strava.RetryConfig) error {
 	log := logging.Logger
@@ -271,7 +619,7 @@ func SyncOnce(ctx context.Context, queries *db.Queries, accessToken string, retr
 	// Get the latest activity date for delta sync
 	var latestDate time.Time
 	activities, err := queries.GetRecentActivities(ctx, 1)
-	if err == nil && len(activities) > 0 && activities[0].StartDate.Valid {
+	if err == nil && len(activities) > 0 && activities[0].StartDate.Valid && !needsRouteBackfill(ctx, queries) && !needsSocialBackfill(ctx, queries) {
 		latestDate = activities[0].StartDate.Time
 	}
 
@@ -313,197 +661,519 @@ func SyncOnce(ctx context.Context, queries *db.Queries, accessToken string, retr
 		default:
 		}
 
-		params := syncsvc.ConvertActivityToParams(activity)
-		if err := queries.CreateActivity(ctx, params); err != nil {
+		// Failures are recorded for the integrity checker to repair
+		if err := syncsvc.SaveActivity(ctx, queries, activity); err != nil {
 			log.Error().Int64("activity_id", activity.ID).Err(err).Msg("failed to save activity")
 			continue
 		}
 		saved++
 	}
 
+	if latestDate.IsZero() {
+		recordFullSync(ctx, queries, time.Now().UTC())
+	}
+
 	log.Info().Int("fetched", len(fetchedActivities)).Int("saved", saved).Msg("initial sync completed")
 	return nil
 }
 
-// ZoneSyncer periodically syncs activity zones from Strava
-type ZoneSyncer struct {
-	queries         *db.Queries
-	storage         *auth.Storage
-	interval        time.Duration
-	batchSize       int
-	premiumRequired bool // Set to true if we detect premium is required
-	retryConfig     strava.RetryConfig
+// socialRefreshDays is how far back kudos and comment counts are refreshed.
+// Most arrive within days of an activity, which delta sync never revisits.
+const socialRefreshDays = 7
+
+// SocialSyncer keeps kudos and comment counts of recent activities current.
+// Who gave the kudos and the comments are fetched by the sync scheduler's
+// queue when the counts change.
+type SocialSyncer struct {
+	queries     *db.Queries
+	storage     *auth.Storage
+	interval    time.Duration
+	retryConfig strava.RetryConfig
 }
 
-// NewZoneSyncer creates a new zone sync worker
-func NewZoneSyncer(queries *db.Queries, storage *auth.Storage, interval time.Duration, retryConfig strava.RetryConfig) *ZoneSyncer {
-	return &ZoneSyncer{
+// NewSocialSyncer creates a new social sync worker
+func NewSocialSyncer(queries *db.Queries, storage *auth.Storage, interval time.Duration, retryConfig strava.RetryConfig) *SocialSyncer {
+	return &SocialSyncer{
 		queries:     queries,
 		storage:     storage,
 		interval:    interval,
-		batchSize:   25, // Sync 25 activities per batch, but run multiple batches based on rate limit headroom
 		retryConfig: retryConfig,
 	}
 }
 
-// Run starts the zone sync worker
-func (z *ZoneSyncer) Run(ctx context.Context) {
+// Run starts the social sync worker
+func (s *SocialSyncer) Run(ctx context.Context) {
 	log := logging.Logger
-	log.Info().Dur("interval", z.interval).Int("batch_size", z.batchSize).Msg("zone syncer started")
+	log.Info().Dur("interval", s.interval).Msg("social syncer started")
 
-	// Initial delay to let activity sync complete first
+	// Initial delay so the other syncs get the first share of the rate limit
 	select {
 	case <-ctx.Done():
 		return
-	case <-time.After(30 * time.Second):
+	case <-time.After(90 * time.Second):
 	}
 
-	// Do an initial sync (continuous until rate limited or done)
-	z.syncZonesContinuously(ctx)
+	s.syncSocial(ctx)
 
-	ticker := time.NewTicker(z.interval)
+	ticker := time.NewTicker(s.interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Info().Msg("zone syncer stopped")
+			log.Info().Msg("social syncer stopped")
 			return
 		case <-ticker.C:
-			z.syncZonesContinuously(ctx)
+			s.syncSocial(ctx)
 		}
 	}
 }
 
-// syncZonesContinuously syncs zones in batches, continuing as long as we have API rate limit headroom
-func (z *ZoneSyncer) syncZonesContinuously(ctx context.Context) {
+// syncSocial refreshes the counts of recent activities
+func (s *SocialSyncer) syncSocial(ctx context.Context) {
 	log := logging.Logger
 
-	// If we've already detected premium is required, skip
-	if z.premiumRequired {
-		log.Debug().Msg("zone sync skipped - Strava Summit subscription required")
+	accessToken, err := s.storage.GetValidAccessToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get access token for social sync")
 		return
 	}
 
-	// Check how many activities need zones synced
-	withoutZones, err := z.queries.CountActivitiesWithoutZones(ctx)
-	if err != nil {
-		log.Error().Err(err).Msg("failed to count activities without zones")
+	client := strava.NewClientWithRetryConfig(accessToken, s.retryConfig)
+	if err := client.WaitForRateLimit(ctx); err != nil {
+		log.Info().Err(err).Msg("social sync cancelled while waiting for rate limit")
 		return
 	}
 
-	if withoutZones == 0 {
-		log.Debug().Msg("all activities have zones synced")
+	recent, err := client.FetchActivitiesSince(ctx, time.Now().AddDate(0, 0, -socialRefreshDays), nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch recent activities for social counts")
 		return
 	}
+	refreshed := 0
+	for _, activity := range recent {
+		if err := s.queries.UpsertActivitySocialCounts(ctx, syncsvc.ConvertSocialCountsToParams(activity)); err != nil {
+			// Activities not yet saved by the activity sync are picked up next time
+			log.Debug().Int64("activity_id", activity.ID).Err(err).Msg("failed to refresh social counts")
+			continue
+		}
+		refreshed++
+	}
+	log.Debug().Int("activities", refreshed).Msg("social counts refreshed")
+}
 
-	log.Info().Int64("activities_remaining", withoutZones).Msg("starting zone sync")
+// ClubSyncer stores the athlete's clubs and the new activities in their
+// feeds. Club feeds only hold recent activities, so they are read every
+// interval to catch each activity while it is there.
+type ClubSyncer struct {
+	queries     *db.Queries
+	storage     *auth.Storage
+	interval    time.Duration
+	retryConfig strava.RetryConfig
+}
 
-	// Get valid access token
-	accessToken, err := z.storage.GetValidAccessToken()
-	if err != nil {
-		log.Error().Err(err).Msg("failed to get access token for zone sync")
+// NewClubSyncer creates a new club sync worker
+func NewClubSyncer(queries *db.Queries, storage *auth.Storage, interval time.Duration, retryConfig strava.RetryConfig) *ClubSyncer {
+	return &ClubSyncer{
+		queries:     queries,
+		storage:     storage,
+		interval:    interval,
+		retryConfig: retryConfig,
+	}
+}
+
+// Run starts the club sync worker
+func (c *ClubSyncer) Run(ctx context.Context) {
+	log := logging.Logger
+	log.Info().Dur("interval", c.interval).Msg("club syncer started")
+
+	// Initial delay so the activity sync gets the first share of the rate limit
+	select {
+	case <-ctx.Done():
 		return
+	case <-time.After(60 * time.Second):
 	}
 
-	// Create Strava client with retry config and sync service
-	client := strava.NewClientWithRetryConfig(accessToken, z.retryConfig)
-	syncService := syncsvc.NewService(z.queries, client)
+	c.syncClubs(ctx)
 
-	totalSynced := 0
-	batchNum := 0
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
 
 	for {
-		// Check if we need to wait for rate limits before proceeding
-		rateLimit := client.GetRateLimit()
-
-		// If approaching daily limit, stop for this session (can't wait that long)
-		if rateLimit.IsApproachingDailyLimit() {
-			log.Info().
-				Int("usage", rateLimit.UsageDaily).
-				Int("limit", rateLimit.LimitDaily).
-				Dur("reset_in", rateLimit.TimeUntilDailyReset.Round(time.Minute)).
-				Int("total_synced", totalSynced).
-				Msg("zone sync stopping - approaching daily rate limit")
-			break
-		}
-
-		// If approaching 15-minute limit, wait for window reset then continue
-		if rateLimit.IsApproaching15MinLimit() {
-			log.Info().
-				Int("usage", rateLimit.Usage15Min).
-				Int("limit", rateLimit.Limit15Min).
-				Dur("wait", rateLimit.TimeUntil15MinReset.Round(time.Second)).
-				Int("total_synced", totalSynced).
-				Msg("zone sync waiting for 15-minute rate limit window to reset")
-
-			if err := client.WaitForRateLimit(ctx); err != nil {
-				log.Info().Err(err).Msg("zone sync cancelled while waiting for rate limit")
-				return
-			}
-			// After waiting, continue to next iteration to re-check limits
-			continue
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("club syncer stopped")
+			return
+		case <-ticker.C:
+			c.syncClubs(ctx)
 		}
+	}
+}
 
-		batchNum++
-		synced, err := syncService.SyncZones(ctx, z.batchSize, nil)
-		totalSynced += synced
+// syncClubs runs one club sync
+func (c *ClubSyncer) syncClubs(ctx context.Context) {
+	log := logging.Logger
 
-		if err != nil {
-			if err == syncsvc.ErrPremiumRequired {
-				z.premiumRequired = true
-				log.Warn().
-					Str("info", "https://www.strava.com/summit").
-					Msg("zone sync disabled - Activity Zones API requires Strava Summit (premium) subscription")
-				return
-			}
-			if err == syncsvc.ErrRateLimited {
-				// Rate limited - wait for window reset and continue
-				log.Info().
-					Int("total_synced", totalSynced).
-					Int("batches", batchNum).
-					Msg("zone sync hit rate limit, waiting for window reset")
-				if err := client.WaitForRateLimit(ctx); err != nil {
-					log.Info().Err(err).Msg("zone sync cancelled while waiting for rate limit")
-					return
-				}
-				continue
-			}
-			log.Error().Err(err).Int("batch", batchNum).Msg("zone sync batch failed")
+	accessToken, err := c.storage.GetValidAccessToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get access token for club sync")
+		return
+	}
+
+	client := strava.NewClientWithRetryConfig(accessToken, c.retryConfig)
+	if err := client.WaitForRateLimit(ctx); err != nil {
+		log.Info().Err(err).Msg("club sync cancelled while waiting for rate limit")
+		return
+	}
+
+	result, err := syncsvc.NewService(c.queries, client).SyncClubs(ctx)
+	if err != nil {
+		if err == syncsvc.ErrRateLimited {
+			log.Info().Int("clubs", result.Clubs).Msg("club sync hit rate limit, continuing next interval")
 			return
 		}
+		log.Error().Err(err).Msg("club sync failed")
+		return
+	}
+
+	log.Info().
+		Int("clubs", result.Clubs).
+		Int("new_activities", result.NewActivities).
+		Int("removed_clubs", result.RemovedClubs).
+		Msg("club sync completed")
+}
 
-		// Update remaining count
-		withoutZones -= int64(synced)
+// LibrarySyncInterval is how often saved routes and starred segments are
+// synced. They change rarely, so this is much slower than the activity sync.
+const LibrarySyncInterval = 6 * time.Hour
 
-		// Log progress every batch
-		rateLimit = client.GetRateLimit()
-		log.Info().
-			Int("batch", batchNum).
-			Int("synced", synced).
-			Int("total_synced", totalSynced).
-			Int64("remaining", withoutZones).
-			Str("15min_usage", fmt.Sprintf("%d/%d", rateLimit.Usage15Min, rateLimit.Limit15Min)).
-			Str("daily_usage", fmt.Sprintf("%d/%d", rateLimit.UsageDaily, rateLimit.LimitDaily)).
-			Msg("zone sync batch completed")
+// LibrarySyncer stores the athlete's saved routes, with their GPX, and
+// starred segments
+type LibrarySyncer struct {
+	queries     *db.Queries
+	storage     *auth.Storage
+	interval    time.Duration
+	retryConfig strava.RetryConfig
+}
 
-		// If we synced less than batch size, we're probably done or hit errors
-		if synced < z.batchSize {
-			break
+// NewLibrarySyncer creates a new route library sync worker
+func NewLibrarySyncer(queries *db.Queries, storage *auth.Storage, interval time.Duration, retryConfig strava.RetryConfig) *LibrarySyncer {
+	return &LibrarySyncer{
+		queries:     queries,
+		storage:     storage,
+		interval:    interval,
+		retryConfig: retryConfig,
+	}
+}
+
+// Run starts the route library sync worker
+func (l *LibrarySyncer) Run(ctx context.Context) {
+	log := logging.Logger
+	log.Info().Dur("interval", l.interval).Msg("library syncer started")
+
+	// Initial delay so the activity and club syncs go first
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(90 * time.Second):
+	}
+
+	l.syncLibrary(ctx)
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("library syncer stopped")
+			return
+		case <-ticker.C:
+			l.syncLibrary(ctx)
 		}
+	}
+}
 
-		// If no more activities need syncing, we're done
-		if withoutZones <= 0 {
-			log.Info().Int("total_synced", totalSynced).Msg("zone sync complete - all activities synced")
-			break
+// syncLibrary runs one route library sync
+func (l *LibrarySyncer) syncLibrary(ctx context.Context) {
+	log := logging.Logger
+
+	accessToken, err := l.storage.GetValidAccessToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get access token for library sync")
+		return
+	}
+
+	// Routes and starred segments rarely change, so they yield to everything else
+	client := strava.NewClientWithRetryConfig(accessToken, l.retryConfig).WithPriority(strava.PriorityBackfill)
+	if err := client.WaitForRateLimit(ctx); err != nil {
+		log.Info().Err(err).Msg("library sync cancelled while waiting for rate limit")
+		return
+	}
+
+	result, err := syncsvc.NewService(l.queries, client).SyncRouteLibrary(ctx)
+	if err != nil {
+		if err == syncsvc.ErrRateLimited || errors.Is(err, strava.ErrBudgetExhausted) {
+			log.Info().Int("gpx_exported", result.GPXExported).Msg("library sync hit rate limit, continuing next interval")
+			return
 		}
+		log.Error().Err(err).Msg("library sync failed")
+		return
+	}
+
+	log.Info().
+		Int("routes", result.Routes).
+		Int("gpx_exported", result.GPXExported).
+		Int("removed_routes", result.RemovedRoutes).
+		Int("segments", result.Segments).
+		Int("removed_segments", result.RemovedSegments).
+		Msg("library sync completed")
+}
+
+// IntegrityCheckInterval is how often Strava's athlete stats are compared
+// with the stored totals
+const IntegrityCheckInterval = 24 * time.Hour
 
-		// Small delay between batches to be nice to the API
+// IntegrityChecker compares Strava's athlete stats with the stored totals
+// and queues activities that failed to save and date windows with gaps to be
+// synced again
+type IntegrityChecker struct {
+	queries     *db.Queries
+	storage     *auth.Storage
+	interval    time.Duration
+	retryConfig strava.RetryConfig
+}
+
+// NewIntegrityChecker creates a new sync integrity worker
+func NewIntegrityChecker(queries *db.Queries, storage *auth.Storage, interval time.Duration, retryConfig strava.RetryConfig) *IntegrityChecker {
+	return &IntegrityChecker{
+		queries:     queries,
+		storage:     storage,
+		interval:    interval,
+		retryConfig: retryConfig,
+	}
+}
+
+// Run starts the sync integrity worker
+func (i *IntegrityChecker) Run(ctx context.Context) {
+	log := logging.Logger
+	log.Info().Dur("interval", i.interval).Msg("integrity checker started")
+
+	// Initial delay so the activity sync finishes first; comparing during
+	// a full sync would find gaps that are only still being filled
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(5 * time.Minute):
+	}
+
+	i.checkIntegrity(ctx)
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
 		select {
 		case <-ctx.Done():
+			log.Info().Msg("integrity checker stopped")
+			return
+		case <-ticker.C:
+			i.checkIntegrity(ctx)
+		}
+	}
+}
+
+// checkIntegrity runs one integrity check
+func (i *IntegrityChecker) checkIntegrity(ctx context.Context) {
+	log := logging.Logger
+
+	accessToken, err := i.storage.GetValidAccessToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get access token for integrity check")
+		return
+	}
+
+	client := strava.NewClientWithRetryConfig(accessToken, i.retryConfig).WithPriority(strava.PriorityBackfill)
+	if err := client.WaitForRateLimit(ctx); err != nil {
+		log.Info().Err(err).Msg("integrity check cancelled while waiting for rate limit")
+		return
+	}
+
+	result, err := syncsvc.NewService(i.queries, client).CheckIntegrity(ctx)
+	if err != nil {
+		if err == syncsvc.ErrRateLimited || errors.Is(err, strava.ErrBudgetExhausted) {
+			log.Info().Msg("integrity check hit rate limit, continuing next interval")
 			return
-		case <-time.After(500 * time.Millisecond):
 		}
+		log.Error().Err(err).Msg("integrity check failed")
+		return
+	}
+
+	var missing int64
+	for _, c := range result.Checks {
+		if c.Period == syncsvc.PeriodAll {
+			missing += c.Missing()
+		}
+	}
+	log.Info().
+		Int64("missing", missing).
+		Int("repairs_queued", len(result.Repairs)).
+		Int("failed_queued", result.Queued).
+		Msg("integrity check completed")
+}
+
+// DetectRoutes assigns newly synced activities to recurring routes. Existing
+// assignments are kept, so route IDs stay stable across runs.
+func DetectRoutes(ctx context.Context, queries *db.Queries) {
+	log := logging.Logger
+
+	result, err := routes.Detect(ctx, queries)
+	if err != nil {
+		log.Warn().Err(err).Msg("route detection failed")
+		return
+	}
+	if result.Assigned > 0 || result.Created > 0 {
+		log.Info().
+			Int("assigned", result.Assigned).
+			Int("new_routes", result.Created).
+			Msg("route detection completed")
+	}
+}
+
+// LoadZoneSettings applies the zone boundaries in a --zones-file. Without a
+// file, boundaries from a previous file are cleared so the athlete's Strava
+// profile zones apply again.
+func LoadZoneSettings(ctx context.Context, queries *db.Queries, path string) error {
+	log := logging.Logger
+
+	if path == "" {
+		return zones.ClearConfigSettings(ctx, queries)
+	}
+
+	settings, err := zones.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, zoneType := range zones.Types(settings) {
+		changed, err := zones.ApplySettings(ctx, queries, zoneType, settings[zoneType], zones.SourceConfig)
+		if err != nil {
+			return fmt.Errorf("applying %s zones: %w", zoneType, err)
+		}
+		if changed {
+			log.Info().Str("zone_type", zoneType).Ints("boundaries", settings[zoneType]).Msg("zone boundaries updated from zones file")
+		}
+	}
+	return nil
+}
+
+// SyncAthleteZones stores the heart rate and power zone boundaries from the
+// athlete's Strava profile. Boundaries from a zones file take precedence.
+func SyncAthleteZones(ctx context.Context, queries *db.Queries, accessToken string, retryConfig strava.RetryConfig) {
+	log := logging.Logger
+
+	client := strava.NewClientWithRetryConfig(accessToken, retryConfig)
+	athleteZones, err := client.FetchAthleteZones(ctx)
+	if err != nil {
+		if err == strava.ErrMissingScope {
+			log.Warn().Msg("cannot read athlete zones without the profile:read_all scope; run with --force-reauth to grant it, or use --zones-file")
+			return
+		}
+		log.Warn().Err(err).Msg("failed to fetch athlete zones")
+		return
+	}
+
+	for zoneType, set := range map[string]*strava.AthleteZoneSet{
+		zones.TypeHeartrate: athleteZones.HeartRate,
+		zones.TypePower:     athleteZones.Power,
+	} {
+		if set == nil || len(set.Zones) < 2 {
+			continue
+		}
+		// Each zone after the first starts where the previous one ended
+		boundaries := make([]int, 0, len(set.Zones)-1)
+		for _, z := range set.Zones[1:] {
+			boundaries = append(boundaries, z.Min)
+		}
+		if err := zones.Validate(boundaries); err != nil {
+			log.Debug().Str("zone_type", zoneType).Err(err).Msg("ignoring athlete zones")
+			continue
+		}
+		changed, err := zones.ApplySettings(ctx, queries, zoneType, boundaries, zones.SourceAthlete)
+		if err != nil {
+			log.Warn().Str("zone_type", zoneType).Err(err).Msg("failed to save athlete zones")
+			continue
+		}
+		if changed {
+			log.Info().Str("zone_type", zoneType).Ints("boundaries", boundaries).Msg("zone boundaries updated from Strava profile")
+		}
+	}
+}
+
+// ComputeLocalZones fills in time-in-zone for activities with streams but no
+// zones from Strava, using the stored zone boundaries
+func ComputeLocalZones(ctx context.Context, queries *db.Queries) {
+	log := logging.Logger
+
+	computed, err := zones.Compute(ctx, queries)
+	if err != nil {
+		log.Warn().Err(err).Int("computed", computed).Msg("local zone computation failed")
+		return
+	}
+	if computed > 0 {
+		log.Info().Int("activities", computed).Msg("local zone computation completed")
+	}
+}
+
+// AnalyzeThresholds estimates thresholds from activities with new streams and
+// records any that rose
+func AnalyzeThresholds(ctx context.Context, queries *db.Queries) {
+	log := logging.Logger
+
+	result, err := thresholds.Run(ctx, queries)
+	if err != nil {
+		log.Warn().Err(err).Int("analyzed", result.Analyzed).Msg("threshold analysis failed")
+		return
+	}
+	for _, d := range result.Detected {
+		log.Info().
+			Str("metric", d.Metric).
+			Str("sport", d.Sport).
+			Float64("value", d.Value).
+			Int64("activity_id", d.ActivityID).
+			Time("detected_on", d.DetectedOn).
+			Msg("new threshold detected")
+	}
+	if result.Analyzed > 0 {
+		log.Info().Int("activities", result.Analyzed).Int("new_thresholds", len(result.Detected)).Msg("threshold analysis completed")
+	}
+}
+
+// ClassifyWorkouts labels new and changed activities with a workout category
+func ClassifyWorkouts(ctx context.Context, queries *db.Queries) {
+	log := logging.Logger
+
+	classified, err := workouts.Run(ctx, queries)
+	if err != nil {
+		log.Warn().Err(err).Int("classified", classified).Msg("workout classification failed")
+		return
+	}
+	if classified > 0 {
+		log.Info().Int("activities", classified).Msg("workout classification completed")
+	}
+}
+
+// CheckDataQuality flags problems in new and changed activities, leaving
+// activities with errors out of records and progress
+func CheckDataQuality(ctx context.Context, queries *db.Queries) {
+	log := logging.Logger
+
+	result, err := quality.Run(ctx, queries)
+	if err != nil {
+		log.Warn().Err(err).Int("checked", result.Checked).Msg("data quality check failed")
+		return
+	}
+	if result.Checked > 0 {
+		log.Info().Int("activities", result.Checked).Int("flagged", result.Flagged).Msg("data quality check completed")
 	}
 }
 
@@ -526,6 +1196,7 @@ func LogDatabaseStats(ctx context.Context, queries *db.Queries) {
 	oldestRaw, _ := queries.GetOldestActivityDate(ctx)
 	withZones, _ := queries.CountActivitiesWithZones(ctx)
 	withoutZones, _ := queries.CountActivitiesWithoutZones(ctx)
+	withoutStreams, _ := queries.CountActivitiesWithoutStreams(ctx)
 
 	newest := formatDate(newestRaw)
 	oldest := formatDate(oldestRaw)
@@ -536,6 +1207,7 @@ func LogDatabaseStats(ctx context.Context, queries *db.Queries) {
 		Str("oldest_activity", oldest).
 		Int64("with_zones", withZones).
 		Int64("without_zones", withoutZones).
+		Int64("without_streams", withoutStreams).
 		Msg("database statistics")
 }
 
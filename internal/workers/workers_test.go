@@ -3,13 +3,21 @@ package workers
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/geo"
 	"github.com/joshdurbin/strava-mcp/internal/strava"
+	syncsvc "github.com/joshdurbin/strava-mcp/internal/sync"
 	_ "modernc.org/sqlite"
 )
 
@@ -83,33 +91,22 @@ func TestNewTokenRefresher(t *testing.T) {
 	}
 }
 
-func TestNewActivitySyncer(t *testing.T) {
+func TestNewSyncScheduler(t *testing.T) {
 	t.Parallel()
 
 	retryConfig := strava.DefaultRetryConfig()
-	syncer := NewActivitySyncer(nil, nil, 15*time.Minute, retryConfig)
+	scheduler := NewSyncScheduler(nil, nil, 15*time.Minute, retryConfig, false)
 
-	if syncer.interval != 15*time.Minute {
-		t.Errorf("expected interval 15m, got %v", syncer.interval)
+	if scheduler.interval != 15*time.Minute {
+		t.Errorf("expected interval 15m, got %v", scheduler.interval)
 	}
 
-	if syncer.retryConfig.MaxRetries != retryConfig.MaxRetries {
-		t.Errorf("expected retry max %d, got %d", retryConfig.MaxRetries, syncer.retryConfig.MaxRetries)
-	}
-}
-
-func TestNewZoneSyncer(t *testing.T) {
-	t.Parallel()
-
-	retryConfig := strava.DefaultRetryConfig()
-	syncer := NewZoneSyncer(nil, nil, 1*time.Hour, retryConfig)
-
-	if syncer.interval != 1*time.Hour {
-		t.Errorf("expected interval 1h, got %v", syncer.interval)
+	if scheduler.pollInterval != SyncJobPollInterval {
+		t.Errorf("expected poll interval %v, got %v", SyncJobPollInterval, scheduler.pollInterval)
 	}
 
-	if syncer.batchSize != 25 {
-		t.Errorf("expected batch size 25, got %d", syncer.batchSize)
+	if scheduler.retryConfig.MaxRetries != retryConfig.MaxRetries {
+		t.Errorf("expected retry max %d, got %d", retryConfig.MaxRetries, scheduler.retryConfig.MaxRetries)
 	}
 }
 
@@ -149,15 +146,223 @@ func setupTestDB(t *testing.T) (*db.Queries, *sql.DB, func()) {
 		max_heartrate REAL,
 		calories REAL,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		summary_polyline TEXT,
+		start_lat REAL,
+		start_lng REAL,
+		end_lat REAL,
+		end_lng REAL,
+		workout_type INTEGER,
+		workout_category TEXT,
+		workout_confidence REAL,
+		workout_classified_at DATETIME
 	);
 	CREATE TABLE IF NOT EXISTS activity_zones (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		activity_id INTEGER NOT NULL,
 		zone_type TEXT NOT NULL,
-		sensor_based BOOLEAN,
+		sensor_based INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		source TEXT NOT NULL DEFAULT 'strava',
 		UNIQUE(activity_id, zone_type)
 	);
+	CREATE TABLE IF NOT EXISTS zone_buckets (
+		id INTEGER PRIMARY KEY,
+		activity_zone_id INTEGER NOT NULL,
+		zone_number INTEGER NOT NULL,
+		min_value INTEGER NOT NULL,
+		max_value INTEGER NOT NULL,
+		time_seconds INTEGER NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS zone_settings (
+		zone_type TEXT PRIMARY KEY,
+		boundaries TEXT NOT NULL,
+		source TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS activity_streams (
+		activity_id INTEGER PRIMARY KEY,
+		point_count INTEGER NOT NULL DEFAULT 0,
+		time_data TEXT,
+		distance_data TEXT,
+		latlng_data TEXT,
+		altitude_data TEXT,
+		velocity_data TEXT,
+		heartrate_data TEXT,
+		cadence_data TEXT,
+		watts_data TEXT,
+		grade_data TEXT,
+		moving_data TEXT,
+		fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS routes_detected (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		activity_type TEXT,
+		representative_activity_id INTEGER NOT NULL,
+		polyline TEXT NOT NULL,
+		distance REAL NOT NULL,
+		start_lat REAL NOT NULL,
+		start_lng REAL NOT NULL,
+		end_lat REAL NOT NULL,
+		end_lng REAL NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS route_efforts (
+		activity_id INTEGER PRIMARY KEY,
+		route_id INTEGER NOT NULL,
+		shape_distance REAL NOT NULL DEFAULT 0
+	);
+	CREATE TABLE IF NOT EXISTS threshold_efforts (
+		activity_id INTEGER PRIMARY KEY,
+		sport TEXT NOT NULL,
+		start_date DATETIME,
+		lthr REAL,
+		ftp REAL,
+		threshold_speed REAL,
+		analyzed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS threshold_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		metric TEXT NOT NULL,
+		sport TEXT NOT NULL,
+		value REAL NOT NULL,
+		previous_value REAL,
+		activity_id INTEGER NOT NULL,
+		detected_on DATETIME NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS activity_quality_checks (
+		activity_id INTEGER PRIMARY KEY,
+		checked_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS activity_flags (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		activity_id INTEGER NOT NULL,
+		code TEXT NOT NULL,
+		severity TEXT NOT NULL,
+		detail TEXT NOT NULL,
+		related_activity_id INTEGER,
+		detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (activity_id, code)
+	);
+	CREATE TABLE IF NOT EXISTS activity_overrides (
+		activity_id INTEGER PRIMARY KEY,
+		name TEXT,
+		type TEXT,
+		distance REAL,
+		counted INTEGER,
+		reason TEXT,
+		original_name TEXT,
+		original_type TEXT,
+		original_sport_type TEXT,
+		original_distance REAL,
+		original_average_speed REAL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS activity_social (
+		activity_id INTEGER PRIMARY KEY,
+		kudos_count INTEGER NOT NULL DEFAULT 0,
+		comment_count INTEGER NOT NULL DEFAULT 0,
+		athlete_count INTEGER NOT NULL DEFAULT 1,
+		lists_kudos_count INTEGER,
+		lists_comment_count INTEGER,
+		lists_synced_at DATETIME,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS activity_kudos (
+		activity_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		athlete_name TEXT NOT NULL,
+		PRIMARY KEY (activity_id, position)
+	);
+	CREATE TABLE IF NOT EXISTS activity_comments (
+		id INTEGER PRIMARY KEY,
+		activity_id INTEGER NOT NULL,
+		athlete_name TEXT NOT NULL,
+		text TEXT NOT NULL,
+		created_at DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS sync_failures (
+		activity_id INTEGER PRIMARY KEY,
+		activity_name TEXT,
+		start_date DATETIME,
+		error TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 1,
+		first_failed_at DATETIME NOT NULL,
+		last_failed_at DATETIME NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS sync_integrity (
+		sport TEXT NOT NULL,
+		period TEXT NOT NULL,
+		window_start DATETIME,
+		strava_count INTEGER NOT NULL,
+		local_count INTEGER NOT NULL,
+		strava_distance REAL NOT NULL,
+		local_distance REAL NOT NULL,
+		strava_moving_time INTEGER NOT NULL,
+		local_moving_time INTEGER NOT NULL,
+		repaired_missing INTEGER,
+		checked_at DATETIME NOT NULL,
+		PRIMARY KEY (sport, period)
+	);
+	CREATE TABLE IF NOT EXISTS sync_repairs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reason TEXT NOT NULL,
+		window_start DATETIME,
+		window_end DATETIME,
+		fetched INTEGER NOT NULL DEFAULT 0,
+		added INTEGER NOT NULL DEFAULT 0,
+		failed INTEGER NOT NULL DEFAULT 0,
+		error TEXT,
+		repaired_at DATETIME NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS sync_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		target INTEGER NOT NULL,
+		after_time DATETIME,
+		priority INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		max_attempts INTEGER NOT NULL,
+		last_error TEXT,
+		run_after DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		finished_at DATETIME,
+		before_time DATETIME
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_jobs_target ON sync_jobs(kind, target, IFNULL(after_time, ''), IFNULL(before_time, ''));
+	CREATE TABLE IF NOT EXISTS activity_laps (
+		activity_id INTEGER NOT NULL,
+		lap_index INTEGER NOT NULL,
+		name TEXT,
+		distance REAL,
+		moving_time INTEGER,
+		elapsed_time INTEGER,
+		start_date DATETIME,
+		total_elevation_gain REAL,
+		average_speed REAL,
+		max_speed REAL,
+		average_heartrate REAL,
+		max_heartrate REAL,
+		average_cadence REAL,
+		average_watts REAL,
+		PRIMARY KEY (activity_id, lap_index)
+	);
+	CREATE TABLE IF NOT EXISTS sync_state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+	CREATE VIEW IF NOT EXISTS excluded_activities AS
+	SELECT activity_id FROM activity_flags
+	WHERE severity = 'error'
+	  AND activity_id NOT IN (SELECT activity_id FROM activity_overrides WHERE counted = 1)
+	UNION
+	SELECT activity_id FROM activity_overrides WHERE counted = 0;
 	`
 	if _, err := sqlDB.Exec(schema); err != nil {
 		sqlDB.Close()
@@ -397,72 +602,171 @@ func TestCountActivitiesWithoutZones(t *testing.T) {
 	}
 }
 
-func TestCreateActivity(t *testing.T) {
+func TestActivitiesWithoutStreams(t *testing.T) {
 	t.Parallel()
 
-	queries, _, cleanup := setupTestDB(t)
+	queries, sqlDB, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	ctx := context.Background()
 
-	// Create
-	err := queries.CreateActivity(ctx, db.CreateActivityParams{
-		ID:       12345,
-		Name:     "Test Run",
-		Distance: sql.NullFloat64{Float64: 5000, Valid: true},
-		Type:     sql.NullString{String: "Run", Valid: true},
-	})
+	for i := 1; i <= 4; i++ {
+		_, err := sqlDB.Exec("INSERT INTO activities (id, name, start_date) VALUES (?, ?, ?)",
+			i, "Activity", time.Date(2024, 1, i, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to insert activity: %v", err)
+		}
+	}
+
+	// An empty marker row counts as synced
+	if err := queries.UpsertActivityStreams(ctx, db.UpsertActivityStreamsParams{ActivityID: 2}); err != nil {
+		t.Fatalf("failed to upsert streams: %v", err)
+	}
+
+	count, err := queries.CountActivitiesWithoutStreams(ctx)
 	if err != nil {
-		t.Fatalf("failed to create: %v", err)
+		t.Fatalf("failed to count: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 without streams, got %d", count)
 	}
 
-	// Verify
-	activity, err := queries.GetActivity(ctx, 12345)
+	ids, err := queries.GetActivitiesWithoutStreams(ctx, 2)
 	if err != nil {
-		t.Fatalf("failed to get: %v", err)
+		t.Fatalf("failed to list: %v", err)
 	}
-	if activity.Name != "Test Run" {
-		t.Errorf("expected 'Test Run', got %q", activity.Name)
+	if len(ids) != 2 || ids[0] != 4 || ids[1] != 3 {
+		t.Errorf("expected newest first [4 3], got %v", ids)
 	}
 }
 
-func TestCreateActivityUpsert(t *testing.T) {
+func TestDetectRoutes(t *testing.T) {
 	t.Parallel()
 
-	queries, _, cleanup := setupTestDB(t)
+	queries, sqlDB, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	ctx := context.Background()
 
-	// Create initial
-	err := queries.CreateActivity(ctx, db.CreateActivityParams{
-		ID:   99999,
-		Name: "Original",
-	})
+	loop := []geo.Point{
+		{Lat: 37.77, Lng: -122.45},
+		{Lat: 37.779, Lng: -122.45},
+		{Lat: 37.779, Lng: -122.439},
+		{Lat: 37.77, Lng: -122.439},
+		{Lat: 37.7702, Lng: -122.4498},
+	}
+	polyline := geo.EncodePolyline(loop)
+	for i := 1; i <= 3; i++ {
+		_, err := sqlDB.Exec("INSERT INTO activities (id, name, type, start_date, summary_polyline) VALUES (?, ?, ?, ?, ?)",
+			i, "Loop", "Run", time.Date(2024, 1, i, 0, 0, 0, 0, time.UTC), polyline)
+		if err != nil {
+			t.Fatalf("failed to insert activity: %v", err)
+		}
+	}
+
+	DetectRoutes(ctx, queries)
+	DetectRoutes(ctx, queries) // a rerun finds nothing new
+
+	recurring, err := queries.ListRecurringRoutes(ctx, db.ListRecurringRoutesParams{Limit: 10})
 	if err != nil {
-		t.Fatalf("failed to create: %v", err)
+		t.Fatalf("failed to list routes: %v", err)
+	}
+	if len(recurring) != 1 || recurring[0].EffortCount != 3 {
+		t.Fatalf("expected one route with 3 efforts, got %+v", recurring)
 	}
 
-	// Upsert with same ID
-	err = queries.CreateActivity(ctx, db.CreateActivityParams{
-		ID:   99999,
-		Name: "Updated",
-	})
+	effort, err := queries.GetRouteEffort(ctx, 3)
 	if err != nil {
-		t.Fatalf("failed to upsert: %v", err)
+		t.Fatalf("failed to get effort: %v", err)
+	}
+	if effort.RouteID != recurring[0].ID {
+		t.Errorf("expected activity 3 on route %d, got %d", recurring[0].ID, effort.RouteID)
 	}
+}
 
-	// Verify update
-	activity, err := queries.GetActivity(ctx, 99999)
+func TestLocalZones(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for i, activityType := range []string{"Run", "Run", "Ride"} {
+		id := i + 1
+		if _, err := sqlDB.Exec("INSERT INTO activities (id, name, type) VALUES (?, ?, ?)", id, "Activity", activityType); err != nil {
+			t.Fatalf("failed to insert activity: %v", err)
+		}
+		err := queries.UpsertActivityStreams(ctx, db.UpsertActivityStreamsParams{
+			ActivityID:    int64(id),
+			PointCount:    4,
+			TimeData:      sql.NullString{String: "[0,10,20,30]", Valid: true},
+			HeartrateData: sql.NullString{String: "[110,110,140,160]", Valid: true},
+			VelocityData:  sql.NullString{String: "[3,3,3.5,4.2]", Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("failed to upsert streams: %v", err)
+		}
+	}
+	// Activity 2 already has zones from Strava
+	if _, err := sqlDB.Exec("INSERT INTO activity_zones (activity_id, zone_type) VALUES (?, ?)", 2, "heartrate"); err != nil {
+		t.Fatalf("failed to insert zone: %v", err)
+	}
+
+	zonesFile := filepath.Join(t.TempDir(), "zones.json")
+	if err := os.WriteFile(zonesFile, []byte(`{"heartrate": [120, 150], "threshold_pace": "4:10"}`), 0o600); err != nil {
+		t.Fatalf("failed to write zones file: %v", err)
+	}
+	if err := LoadZoneSettings(ctx, queries, zonesFile); err != nil {
+		t.Fatalf("failed to load zone settings: %v", err)
+	}
+	ComputeLocalZones(ctx, queries)
+
+	zone, err := queries.GetActivityZoneByActivityAndType(ctx, db.GetActivityZoneByActivityAndTypeParams{ActivityID: 1, ZoneType: "heartrate"})
 	if err != nil {
-		t.Fatalf("failed to get: %v", err)
+		t.Fatalf("expected local zones for activity 1: %v", err)
 	}
-	if activity.Name != "Updated" {
-		t.Errorf("expected 'Updated', got %q", activity.Name)
+	if zone.Source != "local" {
+		t.Errorf("expected source local, got %q", zone.Source)
+	}
+	buckets, err := queries.GetZoneBuckets(ctx, zone.ID)
+	if err != nil {
+		t.Fatalf("failed to get buckets: %v", err)
+	}
+	if len(buckets) != 3 || buckets[0].TimeSeconds != 10 || buckets[2].MaxValue != -1 {
+		t.Errorf("unexpected buckets: %+v", buckets)
+	}
+
+	// Pace zones are only computed for runs
+	if _, err := queries.GetActivityZoneByActivityAndType(ctx, db.GetActivityZoneByActivityAndTypeParams{ActivityID: 1, ZoneType: "pace"}); err != nil {
+		t.Errorf("expected pace zones for the run: %v", err)
+	}
+	if _, err := queries.GetActivityZoneByActivityAndType(ctx, db.GetActivityZoneByActivityAndTypeParams{ActivityID: 3, ZoneType: "pace"}); err != sql.ErrNoRows {
+		t.Errorf("expected no pace zones for the ride, got %v", err)
+	}
+
+	// Local zones don't stop the Strava zone syncer from trying activities 1 and 3
+	missing, err := queries.CountActivitiesWithoutZones(ctx)
+	if err != nil {
+		t.Fatalf("failed to count: %v", err)
+	}
+	if missing != 2 {
+		t.Errorf("expected 2 activities without Strava zones, got %d", missing)
+	}
+
+	// Dropping the zones file clears its boundaries and the zones computed from them
+	if err := LoadZoneSettings(ctx, queries, ""); err != nil {
+		t.Fatalf("failed to clear zone settings: %v", err)
+	}
+	if _, err := queries.GetActivityZoneByActivityAndType(ctx, db.GetActivityZoneByActivityAndTypeParams{ActivityID: 1, ZoneType: "heartrate"}); err != sql.ErrNoRows {
+		t.Errorf("expected local zones to be cleared, got %v", err)
+	}
+	if _, err := queries.GetActivityZoneByActivityAndType(ctx, db.GetActivityZoneByActivityAndTypeParams{ActivityID: 2, ZoneType: "heartrate"}); err != nil {
+		t.Errorf("expected Strava zones to be kept: %v", err)
 	}
 }
 
-func TestLogDatabaseStatsWithData(t *testing.T) {
+func TestAnalyzeThresholds(t *testing.T) {
 	t.Parallel()
 
 	queries, sqlDB, cleanup := setupTestDB(t)
@@ -470,41 +774,1050 @@ func TestLogDatabaseStatsWithData(t *testing.T) {
 
 	ctx := context.Background()
 
-	// Add data
-	now := time.Now()
-	for i := 1; i <= 5; i++ {
+	// Half an hour of one-second samples at a steady effort
+	steady := func(value float64) sql.NullString {
+		values := make([]string, 1800)
+		for i := range values {
+			values[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		return sql.NullString{String: "[" + strings.Join(values, ",") + "]", Valid: true}
+	}
+	times := make([]string, 1800)
+	for i := range times {
+		times[i] = strconv.Itoa(i)
+	}
+	timeData := sql.NullString{String: "[" + strings.Join(times, ",") + "]", Valid: true}
+
+	start := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+	addRide := func(id int, day time.Time, watts float64) {
+		t.Helper()
+		if _, err := sqlDB.Exec("INSERT INTO activities (id, name, type, start_date) VALUES (?, ?, ?, ?)", id, "Ride", "Ride", day); err != nil {
+			t.Fatalf("failed to insert activity: %v", err)
+		}
+		err := queries.UpsertActivityStreams(ctx, db.UpsertActivityStreamsParams{
+			ActivityID: int64(id),
+			PointCount: 1800,
+			TimeData:   timeData,
+			WattsData:  steady(watts),
+		})
+		if err != nil {
+			t.Fatalf("failed to upsert streams: %v", err)
+		}
+	}
+
+	addRide(1, start, 250)
+	AnalyzeThresholds(ctx, queries)
+	addRide(2, start.AddDate(0, 4, 0), 280)
+	AnalyzeThresholds(ctx, queries)
+
+	history, err := queries.ListThresholdHistory(ctx)
+	if err != nil {
+		t.Fatalf("failed to list threshold history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected baseline and a rise, got %+v", history)
+	}
+	if history[0].Metric != "ftp" || history[0].Value != 238 || !history[0].DetectedOn.Equal(start) {
+		t.Errorf("unexpected baseline: %+v", history[0])
+	}
+	if history[1].Value != 266 || history[1].PreviousValue.Float64 != 238 || history[1].ActivityID != 2 {
+		t.Errorf("unexpected rise: %+v", history[1])
+	}
+}
+
+func TestClassifyWorkouts(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+	for i := range 10 {
 		_, err := sqlDB.Exec(
-			"INSERT INTO activities (id, name, start_date) VALUES (?, ?, ?)",
-			i, "Activity", now.AddDate(0, 0, -i),
+			"INSERT INTO activities (id, name, type, start_date, moving_time, average_speed, average_heartrate, workout_type) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			i+1, "Run", "Run", start.AddDate(0, 0, i), 2700, 3.0, 135, nil,
 		)
 		if err != nil {
-			t.Fatalf("failed to insert: %v", err)
+			t.Fatalf("failed to insert activity: %v", err)
 		}
 	}
+	if _, err := sqlDB.Exec("UPDATE activities SET workout_type = 1, moving_time = 2400 WHERE id = 10"); err != nil {
+		t.Fatalf("failed to tag race: %v", err)
+	}
 
-	// Add zones
-	for i := 1; i <= 2; i++ {
+	ClassifyWorkouts(ctx, queries)
+
+	count, err := queries.CountActivitiesFiltered(ctx, db.CountActivitiesFilteredParams{
+		Column7:         "easy",
+		WorkoutCategory: sql.NullString{String: "easy", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to count activities: %v", err)
+	}
+	if count != 9 {
+		t.Errorf("expected 9 easy runs, got %d", count)
+	}
+
+	race, err := queries.GetActivity(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to get activity: %v", err)
+	}
+	if race.WorkoutCategory.String != "race" || race.WorkoutConfidence.Float64 < 0.9 {
+		t.Errorf("expected a confident race, got %v (%v)", race.WorkoutCategory, race.WorkoutConfidence)
+	}
+
+	// Nothing is left to classify until an activity changes
+	remaining, err := queries.ListActivitiesNeedingClassification(ctx, db.ListActivitiesNeedingClassificationParams{Limit: 100})
+	if err != nil {
+		t.Fatalf("failed to list activities: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("expected all activities classified, got %d remaining", len(remaining))
+	}
+}
+
+func TestCheckDataQuality(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+	activities := []struct {
+		id       int
+		start    time.Time
+		distance float64
+		speed    float64
+	}{
+		{1, start, 10000, 3.3},
+		{2, start.Add(time.Minute), 10020, 3.3}, // the same run uploaded twice
+		{3, start.AddDate(0, 0, 1), 10000, 16.7},
+		{4, start.AddDate(0, 0, 2), 5000, 3.6},
+	}
+	for _, a := range activities {
 		_, err := sqlDB.Exec(
-			"INSERT INTO activity_zones (activity_id, zone_type) VALUES (?, ?)",
-			i, "heartrate",
+			"INSERT INTO activities (id, name, type, start_date, distance, moving_time, average_speed, max_speed) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			a.id, "Run", "Run", a.start, a.distance, int(a.distance/a.speed), a.speed, a.speed+1,
 		)
 		if err != nil {
-			t.Fatalf("failed to insert zone: %v", err)
+			t.Fatalf("failed to insert activity: %v", err)
 		}
 	}
 
-	// Should not panic
-	LogDatabaseStats(ctx, queries)
+	CheckDataQuality(ctx, queries)
+
+	flags, err := queries.ListActivityFlags(ctx, db.ListActivityFlagsParams{Column1: nil, Column3: nil, Column5: nil, Limit: 100})
+	if err != nil {
+		t.Fatalf("failed to list flags: %v", err)
+	}
+	found := make(map[int64]string)
+	for _, f := range flags {
+		if f.Severity == "error" {
+			found[f.ActivityID] = f.Code
+		}
+	}
+	if len(found) != 2 || found[2] != "duplicate" || found[3] != "implausible_speed" {
+		t.Errorf("expected activity 2 as a duplicate and 3 as too fast, got %v", found)
+	}
+
+	// Flagged activities don't count as records
+	fastest, err := queries.GetFastestActivity(ctx)
+	if err != nil {
+		t.Fatalf("failed to get fastest activity: %v", err)
+	}
+	if fastest.ID != 4 {
+		t.Errorf("expected activity 4 to be the fastest, got %d", fastest.ID)
+	}
+
+	remaining, err := queries.ListActivitiesNeedingQualityCheck(ctx, db.ListActivitiesNeedingQualityCheckParams{Limit: 100})
+	if err != nil {
+		t.Fatalf("failed to list activities: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("expected all activities checked, got %d remaining", len(remaining))
+	}
 }
 
-func TestLogDatabaseStatsEmpty(t *testing.T) {
+func TestNeedsRouteBackfill(t *testing.T) {
 	t.Parallel()
 
-	queries, _, cleanup := setupTestDB(t)
+	queries, sqlDB, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	ctx := context.Background()
 
-	// Should not panic with empty database
-	LogDatabaseStats(ctx, queries)
+	if needsRouteBackfill(ctx, queries) {
+		t.Error("expected no backfill for empty db")
+	}
+
+	// Indoor activity synced with route support stores an empty polyline
+	if _, err := sqlDB.Exec("INSERT INTO activities (id, name, summary_polyline) VALUES (1, 'Treadmill', '')"); err != nil {
+		t.Fatalf("failed to insert activity: %v", err)
+	}
+	if needsRouteBackfill(ctx, queries) {
+		t.Error("expected no backfill when all activities have route data")
+	}
+
+	// Activity synced before route support has a NULL polyline
+	if _, err := sqlDB.Exec("INSERT INTO activities (id, name) VALUES (2, 'Old Run')"); err != nil {
+		t.Fatalf("failed to insert activity: %v", err)
+	}
+	if !needsRouteBackfill(ctx, queries) {
+		t.Error("expected backfill when an activity has no route data")
+	}
+
+	// One Strava no longer lists keeps its NULL polyline after the full sync
+	recordFullSync(ctx, queries, time.Now().UTC())
+	if needsRouteBackfill(ctx, queries) {
+		t.Error("expected no second backfill once a full sync has run")
+	}
+}
+
+func TestScheduleBackfillOnce(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// An activity deleted on Strava before route data and social counts were
+	// synced
+	if _, err := sqlDB.Exec("INSERT INTO activities (id, name, start_date) VALUES (1, 'Old Run', ?)", now.AddDate(0, 0, -30)); err != nil {
+		t.Fatalf("failed to insert activity: %v", err)
+	}
+
+	scheduler := NewSyncScheduler(queries, nil, time.Hour, strava.DefaultRetryConfig(), false)
+	listPage := func() db.SyncJob {
+		t.Helper()
+		scheduler.schedule(ctx)
+		job, err := queries.ClaimSyncJob(ctx, db.ClaimSyncJobParams{UpdatedAt: now, RunAfter: time.Now().UTC()})
+		if err != nil || job.Kind != syncsvc.JobListPage {
+			t.Fatalf("expected a list page queued, got %+v (%v)", job, err)
+		}
+		finished := sql.NullTime{Time: now, Valid: true}
+		if err := queries.CompleteSyncJob(ctx, db.CompleteSyncJobParams{UpdatedAt: now, FinishedAt: finished, ID: job.ID}); err != nil {
+			t.Fatalf("failed to complete job: %v", err)
+		}
+		// The page came back empty, ending the sync
+		scheduler.finishFullSync(ctx)
+		return job
+	}
+
+	if job := listPage(); job.AfterTime.Valid {
+		t.Errorf("expected a full sync to backfill, got one after %v", job.AfterTime.Time)
+	}
+	// The full sync could not fill it in, so the next sync is a delta sync
+	if job := listPage(); !job.AfterTime.Valid {
+		t.Error("expected a delta sync once the full sync has run")
+	}
+}
+
+func TestScheduleResumesFullSync(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// The first page of a full sync was saved, the second dead-lettered
+	if _, err := sqlDB.Exec("INSERT INTO activities (id, name, start_date, summary_polyline) VALUES (1, 'Morning Run', ?, '')", now.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("failed to insert activity: %v", err)
+	}
+	if err := syncsvc.EnqueueJob(ctx, queries, syncsvc.JobListPage, 2, syncsvc.PriorityListPage, time.Time{}, now); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	job, err := queries.ClaimSyncJob(ctx, db.ClaimSyncJobParams{UpdatedAt: now, RunAfter: now})
+	if err != nil {
+		t.Fatalf("failed to claim job: %v", err)
+	}
+	err = queries.DeadLetterSyncJob(ctx, db.DeadLetterSyncJobParams{
+		LastError:  sql.NullString{String: "unexpected status code: 500", Valid: true},
+		UpdatedAt:  now,
+		FinishedAt: sql.NullTime{Time: now, Valid: true},
+		ID:         job.ID,
+	})
+	if err != nil {
+		t.Fatalf("failed to dead-letter job: %v", err)
+	}
+
+	scheduler := NewSyncScheduler(queries, nil, time.Hour, strava.DefaultRetryConfig(), false)
+
+	// The dead page is queued again, and new activities are synced alongside
+	scheduler.schedule(ctx)
+	job, err = queries.ClaimSyncJob(ctx, db.ClaimSyncJobParams{UpdatedAt: now, RunAfter: time.Now().UTC()})
+	if err != nil || job.Kind != syncsvc.JobListPage || job.Target != 2 || job.AfterTime.Valid || job.Attempts != 0 {
+		t.Fatalf("expected full sync page 2 queued again, got %+v (%v)", job, err)
+	}
+	job, err = queries.ClaimSyncJob(ctx, db.ClaimSyncJobParams{UpdatedAt: now, RunAfter: time.Now().UTC()})
+	if err != nil || job.Kind != syncsvc.JobListPage || job.Target != 1 || !job.AfterTime.Valid {
+		t.Fatalf("expected a delta sync queued too, got %+v (%v)", job, err)
+	}
+}
+
+func TestScheduleFullSyncResumesLimited(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	if _, err := sqlDB.Exec("INSERT INTO activities (id, name, start_date) VALUES (1, 'Morning Run', ?)", now.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("failed to insert activity: %v", err)
+	}
+
+	// A backfill full sync whose second page always fails
+	scheduler := NewSyncScheduler(queries, nil, time.Hour, strava.DefaultRetryConfig(), false)
+	pageErr := errors.New("unexpected status code: 500")
+	complete := func(job db.SyncJob) {
+		t.Helper()
+		finished := sql.NullTime{Time: now, Valid: true}
+		if err := queries.CompleteSyncJob(ctx, db.CompleteSyncJobParams{UpdatedAt: now, FinishedAt: finished, ID: job.ID}); err != nil {
+			t.Fatalf("failed to complete job: %v", err)
+		}
+	}
+	for pass := 0; pass <= fullSyncMaxResumes+1; pass++ {
+		scheduler.schedule(ctx)
+		deltas := 0
+		for {
+			job, err := queries.ClaimSyncJob(ctx, db.ClaimSyncJobParams{UpdatedAt: now, RunAfter: time.Now().UTC()})
+			if err == sql.ErrNoRows {
+				break
+			}
+			if err != nil {
+				t.Fatalf("failed to claim job: %v", err)
+			}
+			switch {
+			case job.Kind != syncsvc.JobListPage:
+				complete(job)
+			case job.AfterTime.Valid:
+				deltas++
+				complete(job)
+			case job.Target == 1:
+				if err := syncsvc.EnqueueJob(ctx, queries, syncsvc.JobListPage, 2, syncsvc.PriorityListPage, time.Time{}, now); err != nil {
+					t.Fatalf("failed to enqueue: %v", err)
+				}
+				complete(job)
+			default:
+				// Its last attempt fails
+				job.Attempts = job.MaxAttempts - 1
+				scheduler.failJob(ctx, job, pageErr, &syncJobStats{})
+			}
+		}
+		if deltas != 1 {
+			t.Errorf("pass %d: expected a delta sync queued, got %d", pass, deltas)
+		}
+	}
+
+	var status string
+	if err := sqlDB.QueryRow("SELECT status FROM sync_jobs WHERE kind = 'list_page' AND target = 2 AND after_time IS NULL").Scan(&status); err != nil {
+		t.Fatalf("failed to read job: %v", err)
+	}
+	if status != "dead" {
+		t.Errorf("expected the failing page left dead, got %s", status)
+	}
+	if resumes := fullSyncResumes(ctx, queries); resumes != fullSyncMaxResumes {
+		t.Errorf("expected %d resumes, got %d", fullSyncMaxResumes, resumes)
+	}
+}
+
+func TestCreateActivity(t *testing.T) {
+	t.Parallel()
+
+	queries, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Create
+	err := queries.CreateActivity(ctx, db.CreateActivityParams{
+		ID:       12345,
+		Name:     "Test Run",
+		Distance: sql.NullFloat64{Float64: 5000, Valid: true},
+		Type:     sql.NullString{String: "Run", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	// Verify
+	activity, err := queries.GetActivity(ctx, 12345)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if activity.Name != "Test Run" {
+		t.Errorf("expected 'Test Run', got %q", activity.Name)
+	}
+}
+
+func TestCreateActivityUpsert(t *testing.T) {
+	t.Parallel()
+
+	queries, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Create initial
+	err := queries.CreateActivity(ctx, db.CreateActivityParams{
+		ID:   99999,
+		Name: "Original",
+	})
+	if err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	// Upsert with same ID
+	err = queries.CreateActivity(ctx, db.CreateActivityParams{
+		ID:   99999,
+		Name: "Updated",
+	})
+	if err != nil {
+		t.Fatalf("failed to upsert: %v", err)
+	}
+
+	// Verify update
+	activity, err := queries.GetActivity(ctx, 99999)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if activity.Name != "Updated" {
+		t.Errorf("expected 'Updated', got %q", activity.Name)
+	}
+}
+
+func TestCreateActivityKeepsOverrides(t *testing.T) {
+	t.Parallel()
+
+	queries, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	synced := db.CreateActivityParams{
+		ID:           99999,
+		Name:         "Morning Run",
+		Type:         sql.NullString{String: "Run", Valid: true},
+		SportType:    sql.NullString{String: "TrailRun", Valid: true},
+		Distance:     sql.NullFloat64{Float64: 30000, Valid: true},
+		MovingTime:   sql.NullInt64{Int64: 3000, Valid: true},
+		AverageSpeed: sql.NullFloat64{Float64: 10, Valid: true},
+	}
+	if err := queries.CreateActivity(ctx, synced); err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	// The watch was left running on the drive home
+	err := queries.UpsertActivityCorrection(ctx, db.UpsertActivityCorrectionParams{
+		Distance: sql.NullFloat64{Float64: 10000, Valid: true},
+		ID:       99999,
+	})
+	if err != nil {
+		t.Fatalf("failed to correct: %v", err)
+	}
+	err = queries.UpsertActivityCorrection(ctx, db.UpsertActivityCorrectionParams{
+		Name: sql.NullString{String: "Trail 10k", Valid: true},
+		ID:   99999,
+	})
+	if err != nil {
+		t.Fatalf("failed to correct: %v", err)
+	}
+	if err := queries.ApplyActivityOverride(ctx, 99999); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+
+	// A re-sync brings Strava's values back, which the corrections replace
+	synced.Name = "Morning Run (edited)"
+	if err := queries.CreateActivity(ctx, synced); err != nil {
+		t.Fatalf("failed to upsert: %v", err)
+	}
+	activity, err := queries.GetActivity(ctx, 99999)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if activity.Name != "Trail 10k" || activity.Distance.Float64 != 10000 || activity.AverageSpeed.Float64 != 10000.0/3000 {
+		t.Errorf("expected the corrections to survive the sync, got %q, %.0f m at %.2f m/s",
+			activity.Name, activity.Distance.Float64, activity.AverageSpeed.Float64)
+	}
+
+	// Resetting restores Strava's values from before the first correction
+	if err := queries.ResetActivityCorrections(ctx, 99999); err != nil {
+		t.Fatalf("failed to reset: %v", err)
+	}
+	activity, err = queries.GetActivity(ctx, 99999)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if activity.Name != "Morning Run" || activity.Distance.Float64 != 30000 || activity.AverageSpeed.Float64 != 10 || activity.SportType.String != "TrailRun" {
+		t.Errorf("expected Strava's values back, got %q, %.0f m at %.2f m/s", activity.Name, activity.Distance.Float64, activity.AverageSpeed.Float64)
+	}
+}
+
+func TestExcludedActivities(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		_, err := sqlDB.Exec(
+			"INSERT INTO activities (id, name, type, start_date, distance, moving_time, average_speed) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			i+1, "Run", "Run", start.AddDate(0, 0, i), 10000, 3000, 3.33,
+		)
+		if err != nil {
+			t.Fatalf("failed to insert activity: %v", err)
+		}
+	}
+	err := queries.CreateActivityFlag(ctx, db.CreateActivityFlagParams{ActivityID: 1, Code: "duplicate", Severity: "error", Detail: "Duplicate"})
+	if err != nil {
+		t.Fatalf("failed to flag: %v", err)
+	}
+
+	excluded := func() []int64 {
+		var ids []int64
+		for id := int64(1); id <= 3; id++ {
+			n, err := queries.IsActivityExcluded(ctx, id)
+			if err != nil {
+				t.Fatalf("failed to check exclusion: %v", err)
+			}
+			if n == 1 {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	if ids := excluded(); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("expected only the flagged activity excluded, got %v", ids)
+	}
+
+	// Counting the flagged activity anyway, and leaving out another by hand
+	for _, o := range []db.SetActivityCountedParams{
+		{ActivityID: 1, Counted: sql.NullInt64{Int64: 1, Valid: true}},
+		{ActivityID: 3, Counted: sql.NullInt64{Int64: 0, Valid: true}},
+	} {
+		if err := queries.SetActivityCounted(ctx, o); err != nil {
+			t.Fatalf("failed to set counted: %v", err)
+		}
+	}
+	if ids := excluded(); len(ids) != 1 || ids[0] != 3 {
+		t.Fatalf("expected only activity 3 excluded, got %v", ids)
+	}
+	summary, err := queries.GetTrainingSummary(ctx)
+	if err != nil {
+		t.Fatalf("failed to get summary: %v", err)
+	}
+	if summary.ActivityCount != 2 {
+		t.Errorf("expected 2 activities in the summary, got %d", summary.ActivityCount)
+	}
+
+	// Clearing the decision goes back to the flags
+	if err := queries.SetActivityCounted(ctx, db.SetActivityCountedParams{ActivityID: 1}); err != nil {
+		t.Fatalf("failed to clear counted: %v", err)
+	}
+	if err := queries.DeleteEmptyActivityOverride(ctx, 1); err != nil {
+		t.Fatalf("failed to delete override: %v", err)
+	}
+	if _, err := queries.GetActivityOverride(ctx, 1); err != sql.ErrNoRows {
+		t.Errorf("expected the empty override deleted, got %v", err)
+	}
+	if ids := excluded(); len(ids) != 2 {
+		t.Errorf("expected activities 1 and 3 excluded, got %v", ids)
+	}
+}
+
+func TestLogDatabaseStatsWithData(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Add data
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		_, err := sqlDB.Exec(
+			"INSERT INTO activities (id, name, start_date) VALUES (?, ?, ?)",
+			i, "Activity", now.AddDate(0, 0, -i),
+		)
+		if err != nil {
+			t.Fatalf("failed to insert: %v", err)
+		}
+	}
+
+	// Add zones
+	for i := 1; i <= 2; i++ {
+		_, err := sqlDB.Exec(
+			"INSERT INTO activity_zones (activity_id, zone_type) VALUES (?, ?)",
+			i, "heartrate",
+		)
+		if err != nil {
+			t.Fatalf("failed to insert zone: %v", err)
+		}
+	}
+
+	// Should not panic
+	LogDatabaseStats(ctx, queries)
+}
+
+func TestLogDatabaseStatsEmpty(t *testing.T) {
+	t.Parallel()
+
+	queries, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Should not panic with empty database
+	LogDatabaseStats(ctx, queries)
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Strava has two recent runs and an older ride; only the first run is stored
+	stravaActivities := []strava.Activity{
+		{ID: 1, Name: "Morning Run", Type: "Run", Distance: 5000, StartDate: now.AddDate(0, 0, -2)},
+		{ID: 2, Name: "Evening Run", Type: "Run", Distance: 8000, StartDate: now.AddDate(0, 0, -1)},
+	}
+	if _, err := sqlDB.Exec("INSERT INTO activities (id, name, type, distance, start_date) VALUES (1, 'Morning Run', 'Run', 5000, ?)", now.AddDate(0, 0, -2)); err != nil {
+		t.Fatalf("failed to insert activity: %v", err)
+	}
+	// An activity that failed to save and was deleted on Strava since
+	err := queries.RecordSyncFailure(ctx, db.RecordSyncFailureParams{ActivityID: 3, Error: "database is locked", FirstFailedAt: now, LastFailedAt: now})
+	if err != nil {
+		t.Fatalf("failed to record failure: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/athlete":
+			w.Write([]byte(`{"id": 7}`))
+		case "/athletes/7/stats":
+			w.Write([]byte(`{"recent_run_totals": {"count": 2, "distance": 13000}, "ytd_run_totals": {"count": 2, "distance": 13000},
+				"all_run_totals": {"count": 2, "distance": 13000}, "all_ride_totals": {"count": 1, "distance": 40000}}`))
+		case "/athlete/activities":
+			page := []strava.Activity{}
+			after, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+			before, _ := strconv.ParseInt(r.URL.Query().Get("before"), 10, 64)
+			if r.URL.Query().Get("page") == "1" {
+				for _, a := range stravaActivities {
+					if a.StartDate.Unix() > after && (before == 0 || a.StartDate.Unix() < before) {
+						page = append(page, a)
+					}
+				}
+			}
+			json.NewEncoder(w).Encode(page)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := syncsvc.NewService(queries, strava.NewClientWithBaseURL("test-token", server.URL))
+	result, err := service.CheckIntegrity(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The recent window for the run and older years for the ride
+	if len(result.Repairs) != 2 || result.Queued != 1 {
+		t.Fatalf("expected 2 repairs and 1 failure queued, got %+v", result)
+	}
+	if active, err := queries.CountActiveSyncJobs(ctx, syncsvc.JobListPage); err != nil || active != 2 {
+		t.Errorf("expected a list page queued for each repair, got %d (%v)", active, err)
+	}
+
+	// The sync queue lists the windows and fetches the failed activity
+	client := strava.NewClientWithBaseURL("test-token", server.URL).WithRetryConfig(0, time.Millisecond, time.Millisecond)
+	NewSyncScheduler(queries, nil, time.Hour, strava.DefaultRetryConfig(), false).runQueue(ctx, client)
+	if _, err := queries.GetActivity(ctx, 2); err != nil {
+		t.Errorf("expected the missing run to be stored: %v", err)
+	}
+	failures, err := queries.ListSyncFailures(ctx)
+	if err != nil || len(failures) != 0 {
+		t.Errorf("expected the deleted activity's failure cleared, got %+v (%v)", failures, err)
+	}
+	repairs, err := queries.ListSyncRepairs(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to list repairs: %v", err)
+	}
+	var fetched, added int64
+	for _, r := range repairs {
+		fetched += r.Fetched
+		added += r.Added
+	}
+	// Both recent runs are listed again, one of them new
+	if len(repairs) != 2 || fetched != 2 || added != 1 {
+		t.Errorf("expected the repairs to log 2 activities fetched and 1 added, got %+v", repairs)
+	}
+
+	// The ride gap could not be closed, so it is not resynced again
+	result, err = service.CheckIntegrity(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Repairs) != 0 {
+		t.Errorf("expected no repairs, got %+v", result.Repairs)
+	}
+	checks, err := queries.ListSyncIntegrity(ctx)
+	if err != nil {
+		t.Fatalf("failed to list checks: %v", err)
+	}
+	for _, c := range checks {
+		switch {
+		case c.Sport == "run" && c.LocalCount != c.StravaCount:
+			t.Errorf("expected runs in sync after repair, got %+v", c)
+		case c.Sport == "ride" && c.Period == syncsvc.PeriodAll && (c.LocalCount != 0 || c.RepairedMissing.Int64 != 1):
+			t.Errorf("expected the ride gap recorded as repaired, got %+v", c)
+		}
+	}
+}
+
+func TestSyncJobQueue(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	jobs := []struct {
+		kind     string
+		target   int64
+		priority int64
+	}{
+		{syncsvc.JobZones, 11, syncsvc.PriorityZones},
+		{syncsvc.JobStreams, 10, syncsvc.PriorityStreams},
+		{syncsvc.JobListPage, 1, syncsvc.PriorityListPage},
+	}
+	for _, j := range jobs {
+		if err := syncsvc.EnqueueJob(ctx, queries, j.kind, j.target, j.priority, time.Time{}, now); err != nil {
+			t.Fatalf("failed to enqueue %s: %v", j.kind, err)
+		}
+	}
+
+	claim := func() (db.SyncJob, error) {
+		t.Helper()
+		return queries.ClaimSyncJob(ctx, db.ClaimSyncJobParams{UpdatedAt: now, RunAfter: time.Now().UTC()})
+	}
+
+	// Highest priority first, and nothing is claimed twice
+	for _, want := range []string{syncsvc.JobListPage, syncsvc.JobStreams, syncsvc.JobZones} {
+		job, err := claim()
+		if err != nil || job.Kind != want || job.Status != "running" {
+			t.Fatalf("expected a running %s job, got %+v (%v)", want, job, err)
+		}
+	}
+	if _, err := claim(); err != sql.ErrNoRows {
+		t.Fatalf("expected no jobs left to claim, got %v", err)
+	}
+
+	// Jobs left running by a crash are resumed
+	resumed, err := queries.ResetRunningSyncJobs(ctx, now)
+	if err != nil || resumed != 3 {
+		t.Fatalf("expected 3 jobs resumed, got %d (%v)", resumed, err)
+	}
+
+	// A failed job waits out its backoff, and queueing it again leaves it alone
+	page, err := claim()
+	if err != nil || page.Kind != syncsvc.JobListPage {
+		t.Fatalf("expected the list page, got %+v (%v)", page, err)
+	}
+	err = queries.RetrySyncJob(ctx, db.RetrySyncJobParams{
+		LastError: sql.NullString{String: "unexpected status code: 500", Valid: true},
+		RunAfter:  now.Add(syncsvc.JobBackoff(1)),
+		UpdatedAt: now,
+		ID:        page.ID,
+	})
+	if err != nil {
+		t.Fatalf("failed to retry job: %v", err)
+	}
+	if err := syncsvc.EnqueueJob(ctx, queries, syncsvc.JobListPage, 1, syncsvc.PriorityListPage, time.Time{}, now); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	var attempts int64
+	var status string
+	if err := sqlDB.QueryRow("SELECT attempts, status FROM sync_jobs WHERE id = ?", page.ID).Scan(&attempts, &status); err != nil {
+		t.Fatalf("failed to read job: %v", err)
+	}
+	if attempts != 1 || status != "pending" {
+		t.Errorf("expected the retry kept with 1 attempt, got %d %s", attempts, status)
+	}
+	ready, err := queries.CountReadySyncJobs(ctx, time.Now().UTC())
+	if err != nil || ready != 2 {
+		t.Errorf("expected 2 jobs due while the page backs off, got %d (%v)", ready, err)
+	}
+
+	// A dead-lettered job is only queued again explicitly, with its attempts reset
+	streams, err := claim()
+	if err != nil || streams.Kind != syncsvc.JobStreams {
+		t.Fatalf("expected the streams job, got %+v (%v)", streams, err)
+	}
+	err = queries.DeadLetterSyncJob(ctx, db.DeadLetterSyncJobParams{
+		LastError:  sql.NullString{String: "giving up", Valid: true},
+		UpdatedAt:  now,
+		FinishedAt: sql.NullTime{Time: now, Valid: true},
+		ID:         streams.ID,
+	})
+	if err != nil {
+		t.Fatalf("failed to dead-letter job: %v", err)
+	}
+	if queued, err := syncsvc.EnqueueActivityDataJobs(ctx, queries, true, now); err != nil || queued != 0 {
+		t.Errorf("expected no data jobs without activities, got %d (%v)", queued, err)
+	}
+	if err := syncsvc.EnqueueJob(ctx, queries, syncsvc.JobStreams, 10, syncsvc.PriorityStreams, time.Time{}, now); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	if err := sqlDB.QueryRow("SELECT attempts, status FROM sync_jobs WHERE id = ?", streams.ID).Scan(&attempts, &status); err != nil {
+		t.Fatalf("failed to read job: %v", err)
+	}
+	if attempts != 0 || status != "pending" {
+		t.Errorf("expected the dead job queued again, got %d attempts %s", attempts, status)
+	}
+
+	// Finished list pages are pruned once old enough
+	err = queries.CompleteSyncJob(ctx, db.CompleteSyncJobParams{
+		UpdatedAt:  now,
+		FinishedAt: sql.NullTime{Time: now.Add(-2 * finishedJobRetention), Valid: true},
+		ID:         page.ID,
+	})
+	if err != nil {
+		t.Fatalf("failed to complete job: %v", err)
+	}
+	pruned, err := queries.DeleteFinishedSyncJobs(ctx, sql.NullTime{Time: now.Add(-finishedJobRetention), Valid: true})
+	if err != nil || pruned != 1 {
+		t.Errorf("expected 1 finished job pruned, got %d (%v)", pruned, err)
+	}
+}
+
+func TestRunSyncJobs(t *testing.T) {
+	t.Parallel()
+
+	queries, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/athlete/activities":
+			page := []strava.Activity{}
+			if r.URL.Query().Get("page") == "1" {
+				page = append(page,
+					strava.Activity{ID: 1, Name: "Morning Run", Type: "Run", Distance: 5000, StartDate: now.AddDate(0, 0, -2)},
+					strava.Activity{ID: 2, Name: "Evening Ride", Type: "Ride", Distance: 20000, StartDate: now.AddDate(0, 0, -1)},
+				)
+			}
+			json.NewEncoder(w).Encode(page)
+		case "/activities/1/streams":
+			w.Write([]byte(`{"time": {"data": [0, 1, 2]}, "distance": {"data": [0, 3, 6]}}`))
+		case "/activities/1/laps":
+			w.Write([]byte(`[{"lap_index": 1, "distance": 2500, "moving_time": 600}, {"lap_index": 2, "distance": 2500, "moving_time": 590}]`))
+		case "/activities/2/laps":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/activities/1/zones", "/activities/2/zones":
+			w.WriteHeader(http.StatusPaymentRequired)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := strava.NewClientWithBaseURL("test-token", server.URL).WithRetryConfig(0, time.Millisecond, time.Millisecond)
+	scheduler := NewSyncScheduler(queries, nil, time.Hour, strava.DefaultRetryConfig(), false)
+
+	// The first page queues the next, which is empty and ends the sync
+	if err := syncsvc.EnqueueJob(ctx, queries, syncsvc.JobListPage, 1, syncsvc.PriorityListPage, time.Time{}, now); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	scheduler.runQueue(ctx, client)
+	if count, err := queries.CountActivities(ctx); err != nil || count != 2 {
+		t.Fatalf("expected 2 activities saved, got %d (%v)", count, err)
+	}
+	if !backfillAttempted(ctx, queries, routeBackfillKey) {
+		t.Error("expected the full sync recorded once its last page came back empty")
+	}
+
+	queued, err := syncsvc.EnqueueActivityDataJobs(ctx, queries, true, now)
+	if err != nil || queued != 6 {
+		t.Fatalf("expected streams, laps and zones jobs for both activities, got %d (%v)", queued, err)
+	}
+	scheduler.runQueue(ctx, client)
+
+	laps, err := queries.ListActivityLaps(ctx, 1)
+	if err != nil || len(laps) != 2 || laps[1].MovingTime.Int64 != 590 {
+		t.Errorf("expected both laps stored, got %+v (%v)", laps, err)
+	}
+	if !scheduler.premiumRequired {
+		t.Error("expected zone sync disabled after Summit was required")
+	}
+
+	counts, err := queries.CountSyncJobsByStatus(ctx)
+	if err != nil {
+		t.Fatalf("failed to count jobs: %v", err)
+	}
+	got := map[string]int64{}
+	for _, c := range counts {
+		got[c.Kind+"/"+c.Status] = c.Jobs
+	}
+	want := map[string]int64{
+		"list_page/done": 2,
+		"streams/done":   2,
+		"laps/done":      1,
+		"laps/pending":   1, // the server error is retried after a backoff
+		"zones/dead":     2,
+	}
+	for key, n := range want {
+		if got[key] != n {
+			t.Errorf("expected %d %s jobs, got %d (all: %v)", n, key, got[key], got)
+		}
+	}
+
+	// Activities with a job of each kind are not queued again
+	if queued, err := syncsvc.EnqueueActivityDataJobs(ctx, queries, true, now); err != nil || queued != 0 {
+		t.Errorf("expected nothing queued again, got %d (%v)", queued, err)
+	}
+}
+
+func TestRunSocialSyncJobs(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Activity 1 has new kudos, 2 was deleted on Strava and 3 is up to date
+	stmts := []string{
+		"INSERT INTO activities (id, name, start_date) VALUES (1, 'Morning Run', ?)",
+		"INSERT INTO activities (id, name, start_date) VALUES (2, 'Evening Ride', ?)",
+		"INSERT INTO activities (id, name, start_date) VALUES (3, 'Easy Run', ?)",
+		"INSERT INTO activity_social (activity_id, kudos_count, comment_count) VALUES (1, 2, 1)",
+		"INSERT INTO activity_social (activity_id, kudos_count, comment_count) VALUES (2, 1, 0)",
+		"INSERT INTO activity_social (activity_id, kudos_count, lists_kudos_count, lists_comment_count) VALUES (3, 4, 4, 0)",
+	}
+	for _, stmt := range stmts {
+		if _, err := sqlDB.Exec(stmt, now.AddDate(0, 0, -1)); err != nil {
+			t.Fatalf("failed to insert: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/activities/1/kudos":
+			w.Write([]byte(`[{"firstname": "Ann", "lastname": "B."}, {"firstname": "Cal", "lastname": "D."}]`))
+		case "/activities/1/comments":
+			w.Write([]byte(`[{"id": 9, "text": "Nice one", "athlete": {"firstname": "Ann", "lastname": "B."}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := strava.NewClientWithBaseURL("test-token", server.URL).WithRetryConfig(0, time.Millisecond, time.Millisecond)
+	scheduler := NewSyncScheduler(queries, nil, time.Hour, strava.DefaultRetryConfig(), true)
+
+	queued, err := syncsvc.EnqueueSocialJobs(ctx, queries, now)
+	if err != nil || queued != 2 {
+		t.Fatalf("expected kudos and comments jobs for 2 activities, got %d (%v)", queued, err)
+	}
+	scheduler.runQueue(ctx, client)
+
+	var kudos, comments int
+	if err := sqlDB.QueryRow("SELECT COUNT(*) FROM activity_kudos WHERE activity_id = 1").Scan(&kudos); err != nil {
+		t.Fatalf("failed to count kudos: %v", err)
+	}
+	if err := sqlDB.QueryRow("SELECT COUNT(*) FROM activity_comments WHERE activity_id = 1").Scan(&comments); err != nil {
+		t.Fatalf("failed to count comments: %v", err)
+	}
+	if kudos != 2 || comments != 1 {
+		t.Errorf("expected 2 kudos and 1 comment stored, got %d and %d", kudos, comments)
+	}
+	if synced, err := queries.CountSocialListsSynced(ctx); err != nil || synced != 2 {
+		t.Errorf("expected both queued activities marked synced, got %d (%v)", synced, err)
+	}
+
+	// Finished jobs are queued again only once the counts change
+	if queued, err := syncsvc.EnqueueSocialJobs(ctx, queries, now); err != nil || queued != 0 {
+		t.Errorf("expected nothing queued again, got %d (%v)", queued, err)
+	}
+	if _, err := sqlDB.Exec("UPDATE activity_social SET kudos_count = 3 WHERE activity_id = 1"); err != nil {
+		t.Fatalf("failed to update counts: %v", err)
+	}
+	if queued, err := syncsvc.EnqueueSocialJobs(ctx, queries, now); err != nil || queued != 1 {
+		t.Errorf("expected the changed activity queued again, got %d (%v)", queued, err)
+	}
+}
+
+func TestCheckIntegrityLocalChanges(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Both of Strava's runs are stored, one excluded and one retyped locally
+	stmts := []string{
+		"INSERT INTO activities (id, name, type, distance, start_date) VALUES (1, 'Morning Run', 'Run', 5000, ?)",
+		"INSERT INTO activities (id, name, type, distance, start_date) VALUES (2, 'Evening Walk', 'Walk', 3000, ?)",
+		"INSERT INTO activity_overrides (activity_id, counted) VALUES (1, 0)",
+		`INSERT INTO activity_overrides (activity_id, type, distance, original_name, original_type, original_distance)
+			VALUES (2, 'Walk', 3000, 'Evening Run', 'Run', 8000)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := sqlDB.Exec(stmt, now.AddDate(0, 0, -1)); err != nil {
+			t.Fatalf("failed to insert: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/athlete":
+			w.Write([]byte(`{"id": 7}`))
+		case "/athletes/7/stats":
+			w.Write([]byte(`{"recent_run_totals": {"count": 2, "distance": 13000}, "ytd_run_totals": {"count": 2, "distance": 13000},
+				"all_run_totals": {"count": 2, "distance": 13000}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := syncsvc.NewService(queries, strava.NewClientWithBaseURL("test-token", server.URL))
+	result, err := service.CheckIntegrity(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Repairs) != 0 {
+		t.Errorf("expected no repairs, got %+v", result.Repairs)
+	}
+	for _, c := range result.Checks {
+		if c.LocalCount != c.StravaCount || c.LocalDistance != c.StravaDistance {
+			t.Errorf("expected stored totals to match Strava's, got %+v", c)
+		}
+	}
 }
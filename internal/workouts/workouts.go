@@ -0,0 +1,415 @@
+// Package workouts classifies runs and rides as easy, tempo, intervals, long
+// or race from their intensity, how much their pace or power varied, their
+// length against the athlete's usual, and Strava's workout_type tag. Laps
+// aren't synced, so a session's structure is read from its streams instead.
+package workouts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+	"github.com/joshdurbin/strava-mcp/internal/thresholds"
+)
+
+// Workout categories
+const (
+	CategoryEasy      = "easy"
+	CategoryTempo     = "tempo"
+	CategoryIntervals = "intervals"
+	CategoryLong      = "long"
+	CategoryRace      = "race"
+)
+
+// Categories lists every workout category
+var Categories = []string{CategoryEasy, CategoryTempo, CategoryIntervals, CategoryLong, CategoryRace}
+
+// Strava workout_type values
+const (
+	runRace     = 1
+	runLong     = 2
+	runWorkout  = 3
+	rideRace    = 11
+	rideWorkout = 12
+)
+
+// Intensity is average heart rate as a fraction of LTHR (or speed as a
+// fraction of threshold speed). Below easyCeiling is Friel's zones 1 and 2;
+// holding raceFloor or more for a whole activity is a race effort.
+const (
+	easyCeiling = 0.90
+	raceFloor   = 1.00
+)
+
+// intervalIntensity is the least intensity a variable session needs to count
+// as intervals rather than an easy run with stops or hills
+const intervalIntensity = 0.85
+
+// longFactor is how much longer than the athlete's median an activity must be
+// to count as long
+const longFactor = 1.5
+
+// minLong is the least moving time, in seconds, for a long session by sport
+var minLong = map[string]int64{
+	thresholds.SportRun:  75 * 60,
+	thresholds.SportRide: 150 * 60,
+}
+
+// intervalVariability is the coefficient of variation of minute-by-minute
+// speed (runs) or power (rides) above which a session counts as intervals.
+// Power varies far more than pace even on a steady ride.
+var intervalVariability = map[string]float64{
+	thresholds.SportRun:  0.15,
+	thresholds.SportRide: 0.40,
+}
+
+// terrainTypes vary with the ground rather than the effort, so variability
+// says nothing about intervals
+var terrainTypes = map[string]bool{
+	"TrailRun":         true,
+	"MountainBikeRide": true,
+}
+
+// medianIntensity is the intensity assumed for the athlete's median speed
+// when there's no threshold to compare against; most sessions are easy
+const medianIntensity = 0.85
+
+// lthrFromMaxHR estimates LTHR from maximum heart rate when no threshold has
+// been detected
+const lthrFromMaxHR = 0.90
+
+// minNormSamples is the number of activities needed before a median or
+// maximum heart rate is trusted
+const minNormSamples = 5
+
+// Confidence bounds
+const (
+	minConfidence = 0.30
+	maxConfidence = 0.95
+)
+
+// blockSeconds is the length of the blocks variability is measured over;
+// long enough to smooth GPS noise, short enough to see a 400 m repeat
+const blockSeconds = 60
+
+// maxSampleGap caps the seconds credited to a single sample
+const maxSampleGap = 30
+
+// minBlocks is the number of blocks needed to measure variability
+const minBlocks = 10
+
+// pageSize is the number of activities classified per query
+const pageSize = 200
+
+// Features are what an activity is classified from
+type Features struct {
+	Type             string
+	WorkoutType      sql.NullInt64 // Strava's tag
+	MovingTime       int64         // seconds
+	AverageSpeed     float64       // m/s
+	AverageHeartrate float64       // bpm
+	// Variability is the coefficient of variation of minute-by-minute speed
+	// or power; only meaningful when HasVariability is set
+	Variability    float64
+	HasVariability bool
+}
+
+// Norms are the athlete's usual durations and speeds by activity type, and
+// thresholds by sport
+type Norms struct {
+	MedianMovingTime map[string]float64 // seconds, by activity type
+	MedianSpeed      map[string]float64 // m/s, by activity type
+	LTHR             map[string]float64 // bpm, by sport
+	ThresholdSpeed   float64            // m/s, runs
+}
+
+// Result is a classification; Category is empty for activity types that
+// aren't classified
+type Result struct {
+	Category   string
+	Confidence float64
+}
+
+// Classify assigns a workout category. Strava's race and long run tags are
+// taken at their word; a workout tag is split into tempo or intervals by
+// variability. Untagged activities are classified from variability, length
+// and intensity, with confidence lower the closer they sit to a boundary and
+// when intensity has to be guessed from speed alone.
+func Classify(f Features, n Norms) Result {
+	sport := thresholds.Sport(f.Type)
+	if sport == "" {
+		return Result{}
+	}
+
+	if f.WorkoutType.Valid {
+		switch f.WorkoutType.Int64 {
+		case runRace, rideRace:
+			return result(CategoryRace, maxConfidence)
+		case runLong:
+			return result(CategoryLong, 0.90)
+		}
+	}
+
+	intensity, penalty := n.intensity(f, sport)
+	cut := intervalVariability[sport]
+	measured := f.HasVariability && !terrainTypes[f.Type]
+	variable := measured && f.Variability >= cut
+
+	if f.WorkoutType.Valid && (f.WorkoutType.Int64 == runWorkout || f.WorkoutType.Int64 == rideWorkout) {
+		switch {
+		case variable:
+			return result(CategoryIntervals, 0.90)
+		case measured:
+			return result(CategoryTempo, 0.80)
+		case intensity >= easyCeiling:
+			return result(CategoryTempo, 0.60)
+		default:
+			return result(CategoryIntervals, 0.50)
+		}
+	}
+
+	var ratio float64
+	if median := n.MedianMovingTime[f.Type]; median > 0 {
+		ratio = float64(f.MovingTime) / median
+	}
+	long := ratio >= longFactor && f.MovingTime >= minLong[sport]
+
+	switch {
+	case variable && (intensity == 0 || intensity >= intervalIntensity):
+		return result(CategoryIntervals, 0.60+margin(f.Variability/cut-1)-penalty)
+	case long:
+		return result(CategoryLong, 0.60+margin(ratio-longFactor)-penalty)
+	case intensity >= raceFloor:
+		return result(CategoryRace, 0.50+margin(intensity-raceFloor)-penalty)
+	case intensity >= easyCeiling:
+		// Confidence falls toward either edge of the tempo band
+		edge := math.Min(intensity-easyCeiling, raceFloor-intensity)
+		return result(CategoryTempo, 0.55+margin(edge)-penalty)
+	case intensity > 0:
+		return result(CategoryEasy, 0.60+margin(easyCeiling-intensity)-penalty)
+	}
+	return result(CategoryEasy, minConfidence)
+}
+
+// intensity returns how hard an activity was against threshold, and how much
+// confidence to take off for how it was estimated. It's 0 when unknown.
+func (n Norms) intensity(f Features, sport string) (float64, float64) {
+	if lthr := n.LTHR[sport]; lthr > 0 && f.AverageHeartrate > 0 {
+		return f.AverageHeartrate / lthr, 0
+	}
+	if sport == thresholds.SportRun && n.ThresholdSpeed > 0 && f.AverageSpeed > 0 && !terrainTypes[f.Type] {
+		return f.AverageSpeed / n.ThresholdSpeed, 0.05
+	}
+	if median := n.MedianSpeed[f.Type]; median > 0 && f.AverageSpeed > 0 {
+		return medianIntensity * f.AverageSpeed / median, 0.15
+	}
+	return 0, 0
+}
+
+// margin turns how far past a boundary a measure sits into extra confidence
+func margin(distance float64) float64 {
+	return math.Min(0.30, math.Max(0, distance*3))
+}
+
+func result(category string, confidence float64) Result {
+	confidence = math.Max(minConfidence, math.Min(maxConfidence, confidence))
+	return Result{Category: category, Confidence: math.Round(confidence*100) / 100}
+}
+
+// Variability returns the coefficient of variation of an activity's
+// minute-by-minute speed, or power for rides with a power meter, over moving
+// time. ok is false with under minBlocks minutes of data.
+func Variability(st *streams.Streams, activityType string) (float64, bool) {
+	values := st.Velocity
+	if thresholds.Sport(activityType) == thresholds.SportRide && len(st.Watts) > 0 {
+		values = st.Watts
+	}
+	n := min(len(st.Time), len(values))
+	if n < 2 {
+		return 0, false
+	}
+
+	var means []float64
+	var secs, sum float64
+	for i := 1; i < n; i++ {
+		dt := float64(min(st.Time[i]-st.Time[i-1], maxSampleGap))
+		if dt <= 0 || (i < len(st.Moving) && !st.Moving[i]) {
+			continue
+		}
+		secs += dt
+		sum += values[i] * dt
+		if secs >= blockSeconds {
+			means = append(means, sum/secs)
+			secs, sum = 0, 0
+		}
+	}
+	if len(means) < minBlocks {
+		return 0, false
+	}
+
+	var mean float64
+	for _, m := range means {
+		mean += m
+	}
+	mean /= float64(len(means))
+	if mean <= 0 {
+		return 0, false
+	}
+	var variance float64
+	for _, m := range means {
+		variance += (m - mean) * (m - mean)
+	}
+	return math.Sqrt(variance/float64(len(means))) / mean, true
+}
+
+// Store is the subset of queries needed to classify workouts
+type Store interface {
+	ListWorkoutNorms(ctx context.Context) ([]db.ListWorkoutNormsRow, error)
+	GetLatestThreshold(ctx context.Context, arg db.GetLatestThresholdParams) (db.ThresholdHistory, error)
+	ListActivitiesNeedingClassification(ctx context.Context, arg db.ListActivitiesNeedingClassificationParams) ([]db.ListActivitiesNeedingClassificationRow, error)
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+	UpdateWorkoutClassification(ctx context.Context, arg db.UpdateWorkoutClassificationParams) error
+}
+
+// LoadNorms builds the athlete's norms from their activities and detected
+// thresholds. Without a detected LTHR, one is estimated from the highest
+// maximum heart rates recorded.
+func LoadNorms(ctx context.Context, store Store) (Norms, error) {
+	rows, err := store.ListWorkoutNorms(ctx)
+	if err != nil {
+		return Norms{}, fmt.Errorf("listing workout norms: %w", err)
+	}
+
+	times := make(map[string][]float64)
+	speeds := make(map[string][]float64)
+	maxHRs := make(map[string][]float64)
+	for _, r := range rows {
+		t := r.Type.String
+		times[t] = append(times[t], float64(r.MovingTime.Int64))
+		if r.AverageSpeed.Valid && r.AverageSpeed.Float64 > 0 {
+			speeds[t] = append(speeds[t], r.AverageSpeed.Float64)
+		}
+		if sport := thresholds.Sport(t); sport != "" && r.MaxHeartrate.Valid && r.MaxHeartrate.Float64 > 0 {
+			maxHRs[sport] = append(maxHRs[sport], r.MaxHeartrate.Float64)
+		}
+	}
+
+	n := Norms{
+		MedianMovingTime: make(map[string]float64),
+		MedianSpeed:      make(map[string]float64),
+		LTHR:             make(map[string]float64),
+	}
+	for t, v := range times {
+		if len(v) >= minNormSamples {
+			n.MedianMovingTime[t] = percentile(v, 0.5)
+		}
+	}
+	for t, v := range speeds {
+		if len(v) >= minNormSamples {
+			n.MedianSpeed[t] = percentile(v, 0.5)
+		}
+	}
+	for sport, v := range maxHRs {
+		if len(v) >= minNormSamples {
+			// The 95th percentile skips the odd strap spike
+			n.LTHR[sport] = lthrFromMaxHR * percentile(v, 0.95)
+		}
+	}
+
+	for _, sport := range []string{thresholds.SportRun, thresholds.SportRide} {
+		latest, err := store.GetLatestThreshold(ctx, db.GetLatestThresholdParams{Metric: thresholds.MetricLTHR, Sport: sport})
+		if err != nil && err != sql.ErrNoRows {
+			return Norms{}, fmt.Errorf("loading %s LTHR: %w", sport, err)
+		}
+		if err == nil {
+			n.LTHR[sport] = latest.Value
+		}
+	}
+	latest, err := store.GetLatestThreshold(ctx, db.GetLatestThresholdParams{Metric: thresholds.MetricThresholdPace, Sport: thresholds.SportRun})
+	if err != nil && err != sql.ErrNoRows {
+		return Norms{}, fmt.Errorf("loading threshold pace: %w", err)
+	}
+	if err == nil {
+		n.ThresholdSpeed = latest.Value
+	}
+	return n, nil
+}
+
+func percentile(values []float64, p float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// Run classifies activities that are new, updated or have new streams since
+// they were last classified, returning how many were classified
+func Run(ctx context.Context, store Store) (int, error) {
+	norms, err := LoadNorms(ctx, store)
+	if err != nil {
+		return 0, err
+	}
+
+	classified := 0
+	var lastID int64
+	for {
+		rows, err := store.ListActivitiesNeedingClassification(ctx, db.ListActivitiesNeedingClassificationParams{ID: lastID, Limit: pageSize})
+		if err != nil {
+			return classified, fmt.Errorf("listing activities needing classification: %w", err)
+		}
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return classified, err
+			}
+			if err := classifyActivity(ctx, store, norms, row); err != nil {
+				return classified, err
+			}
+			classified++
+			lastID = row.ID
+		}
+		if len(rows) < pageSize {
+			return classified, nil
+		}
+	}
+}
+
+func classifyActivity(ctx context.Context, store Store, norms Norms, row db.ListActivitiesNeedingClassificationRow) error {
+	f := Features{
+		Type:             row.Type.String,
+		WorkoutType:      row.WorkoutType,
+		MovingTime:       row.MovingTime.Int64,
+		AverageSpeed:     row.AverageSpeed.Float64,
+		AverageHeartrate: row.AverageHeartrate.Float64,
+	}
+
+	// Streams only matter for the types that get classified
+	if thresholds.Sport(f.Type) != "" {
+		streamRow, err := store.GetActivityStreams(ctx, row.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("loading streams for activity %d: %w", row.ID, err)
+		}
+		if err == nil {
+			st, err := streams.FromRow(streamRow)
+			if err != nil {
+				logging.Warn("Skipping unreadable activity streams", "activity_id", row.ID, "error", err)
+			} else {
+				f.Variability, f.HasVariability = Variability(st, f.Type)
+			}
+		}
+	}
+
+	r := Classify(f, norms)
+	err := store.UpdateWorkoutClassification(ctx, db.UpdateWorkoutClassificationParams{
+		WorkoutCategory:   sql.NullString{String: r.Category, Valid: r.Category != ""},
+		WorkoutConfidence: sql.NullFloat64{Float64: r.Confidence, Valid: r.Category != ""},
+		ID:                row.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("saving classification for activity %d: %w", row.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package workouts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+// memStore is an in-memory Store
+type memStore struct {
+	activities map[int64]db.ListActivitiesNeedingClassificationRow
+	norms      []db.ListWorkoutNormsRow
+	thresholds []db.ThresholdHistory
+	streams    map[int64]db.ActivityStream
+	classified map[int64]db.UpdateWorkoutClassificationParams
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		activities: map[int64]db.ListActivitiesNeedingClassificationRow{},
+		streams:    map[int64]db.ActivityStream{},
+		classified: map[int64]db.UpdateWorkoutClassificationParams{},
+	}
+}
+
+func (m *memStore) ListWorkoutNorms(ctx context.Context) ([]db.ListWorkoutNormsRow, error) {
+	return m.norms, nil
+}
+
+func (m *memStore) GetLatestThreshold(ctx context.Context, arg db.GetLatestThresholdParams) (db.ThresholdHistory, error) {
+	for _, t := range m.thresholds {
+		if t.Metric == arg.Metric && t.Sport == arg.Sport {
+			return t, nil
+		}
+	}
+	return db.ThresholdHistory{}, sql.ErrNoRows
+}
+
+func (m *memStore) ListActivitiesNeedingClassification(ctx context.Context, arg db.ListActivitiesNeedingClassificationParams) ([]db.ListActivitiesNeedingClassificationRow, error) {
+	var out []db.ListActivitiesNeedingClassificationRow
+	for id := arg.ID + 1; int64(len(out)) < arg.Limit && id <= 1000; id++ {
+		if a, ok := m.activities[id]; ok {
+			if _, done := m.classified[id]; !done {
+				out = append(out, a)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error) {
+	row, ok := m.streams[activityID]
+	if !ok {
+		return db.ActivityStream{}, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+func (m *memStore) UpdateWorkoutClassification(ctx context.Context, arg db.UpdateWorkoutClassificationParams) error {
+	m.classified[arg.ID] = arg
+	return nil
+}
+
+func jsonColumn(t *testing.T, v any) sql.NullString {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// repeats returns 40 minutes of one-second speed samples alternating between
+// fast and slow every few minutes
+func repeats(fast, slow float64) *streams.Streams {
+	st := &streams.Streams{}
+	for i := range 2400 {
+		st.Time = append(st.Time, i)
+		v := slow
+		if (i/180)%2 == 0 {
+			v = fast
+		}
+		st.Velocity = append(st.Velocity, v)
+	}
+	return st
+}
+
+func runNorms() Norms {
+	return Norms{
+		MedianMovingTime: map[string]float64{"Run": 2700, "Ride": 5400},
+		MedianSpeed:      map[string]float64{"Run": 3.2, "Ride": 7},
+		LTHR:             map[string]float64{"run": 170, "ride": 165},
+	}
+}
+
+func TestClassify(t *testing.T) {
+	n := runNorms()
+	tagged := func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} }
+
+	tests := []struct {
+		name string
+		f    Features
+		want string
+	}{
+		{"race tag", Features{Type: "Run", WorkoutType: tagged(1), MovingTime: 2400, AverageHeartrate: 140}, CategoryRace},
+		{"long run tag", Features{Type: "Run", WorkoutType: tagged(2), MovingTime: 3000}, CategoryLong},
+		{"workout tag, steady", Features{Type: "Run", WorkoutType: tagged(3), MovingTime: 2700, Variability: 0.05, HasVariability: true}, CategoryTempo},
+		{"workout tag, variable", Features{Type: "Run", WorkoutType: tagged(3), MovingTime: 2700, Variability: 0.25, HasVariability: true}, CategoryIntervals},
+		{"easy", Features{Type: "Run", MovingTime: 2700, AverageHeartrate: 140, Variability: 0.05, HasVariability: true}, CategoryEasy},
+		{"tempo", Features{Type: "Run", MovingTime: 2700, AverageHeartrate: 160, Variability: 0.05, HasVariability: true}, CategoryTempo},
+		{"race effort", Features{Type: "Run", MovingTime: 2400, AverageHeartrate: 175}, CategoryRace},
+		{"intervals", Features{Type: "Run", MovingTime: 3000, AverageHeartrate: 155, Variability: 0.3, HasVariability: true}, CategoryIntervals},
+		{"easy with stops", Features{Type: "Run", MovingTime: 3000, AverageHeartrate: 130, Variability: 0.3, HasVariability: true}, CategoryEasy},
+		{"long", Features{Type: "Run", MovingTime: 6000, AverageHeartrate: 145}, CategoryLong},
+		{"short but long for the athlete", Features{Type: "Run", MovingTime: 4200, AverageHeartrate: 145}, CategoryEasy},
+		{"hilly trail run isn't intervals", Features{Type: "TrailRun", MovingTime: 3000, AverageHeartrate: 150, Variability: 0.4, HasVariability: true}, CategoryEasy},
+		{"steady ride", Features{Type: "Ride", MovingTime: 5400, AverageHeartrate: 135, Variability: 0.3, HasVariability: true}, CategoryEasy},
+		{"no heart rate, fast", Features{Type: "Run", MovingTime: 2400, AverageSpeed: 3.8}, CategoryRace},
+		{"no heart rate, typical pace", Features{Type: "Run", MovingTime: 2400, AverageSpeed: 3.2}, CategoryEasy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.f, n)
+			if got.Category != tt.want {
+				t.Errorf("expected %s, got %s (%.2f)", tt.want, got.Category, got.Confidence)
+			}
+			if got.Confidence < minConfidence || got.Confidence > maxConfidence {
+				t.Errorf("confidence %.2f out of range", got.Confidence)
+			}
+		})
+	}
+
+	if got := Classify(Features{Type: "Yoga", MovingTime: 3600}, n); got != (Result{}) {
+		t.Errorf("expected yoga to go unclassified, got %+v", got)
+	}
+}
+
+func TestClassifyConfidence(t *testing.T) {
+	n := runNorms()
+
+	clear := Classify(Features{Type: "Run", MovingTime: 2700, AverageHeartrate: 120}, n)
+	borderline := Classify(Features{Type: "Run", MovingTime: 2700, AverageHeartrate: 151}, n)
+	if clear.Category != CategoryEasy || borderline.Category != CategoryEasy {
+		t.Fatalf("expected both easy, got %+v and %+v", clear, borderline)
+	}
+	if clear.Confidence <= borderline.Confidence {
+		t.Errorf("expected a clearly easy run to be more confident than a borderline one, got %.2f and %.2f", clear.Confidence, borderline.Confidence)
+	}
+
+	// Guessing intensity from pace costs confidence
+	fromPace := Classify(Features{Type: "Run", MovingTime: 2700, AverageSpeed: 2.6}, n)
+	fromHR := Classify(Features{Type: "Run", MovingTime: 2700, AverageHeartrate: 130}, n)
+	if fromPace.Confidence >= fromHR.Confidence {
+		t.Errorf("expected pace-only classification to be less confident, got %.2f vs %.2f", fromPace.Confidence, fromHR.Confidence)
+	}
+}
+
+func TestVariability(t *testing.T) {
+	steady := repeats(3.3, 3.3)
+	cv, ok := Variability(steady, "Run")
+	if !ok || cv > 0.01 {
+		t.Errorf("expected no variability for a steady run, got %.3f (%v)", cv, ok)
+	}
+
+	cv, ok = Variability(repeats(4.5, 2.5), "Run")
+	if !ok || cv < intervalVariability["run"] {
+		t.Errorf("expected interval-level variability, got %.3f (%v)", cv, ok)
+	}
+
+	short := &streams.Streams{Time: steady.Time[:300], Velocity: steady.Velocity[:300]}
+	if _, ok := Variability(short, "Run"); ok {
+		t.Error("expected too little data to measure variability")
+	}
+}
+
+func TestLoadNorms(t *testing.T) {
+	m := newMemStore()
+	for i := range 10 {
+		m.norms = append(m.norms, db.ListWorkoutNormsRow{
+			Type:         sql.NullString{String: "Run", Valid: true},
+			MovingTime:   sql.NullInt64{Int64: int64(1800 + i*60), Valid: true},
+			AverageSpeed: sql.NullFloat64{Float64: 3, Valid: true},
+			MaxHeartrate: sql.NullFloat64{Float64: 190, Valid: true},
+		})
+	}
+	// Too few rides to trust
+	m.norms = append(m.norms, db.ListWorkoutNormsRow{
+		Type:       sql.NullString{String: "Ride", Valid: true},
+		MovingTime: sql.NullInt64{Int64: 3600, Valid: true},
+	})
+
+	n, err := LoadNorms(context.Background(), m)
+	if err != nil {
+		t.Fatalf("LoadNorms: %v", err)
+	}
+	if n.MedianMovingTime["Run"] != 2040 || n.MedianSpeed["Run"] != 3 {
+		t.Errorf("unexpected run norms: %+v", n)
+	}
+	if _, ok := n.MedianMovingTime["Ride"]; ok {
+		t.Error("expected no ride norms from one ride")
+	}
+	if math.Abs(n.LTHR["run"]-171) > 0.01 {
+		t.Errorf("expected LTHR estimated from max heart rate, got %.1f", n.LTHR["run"])
+	}
+
+	// A detected threshold wins over the estimate
+	m.thresholds = []db.ThresholdHistory{{Metric: "lthr", Sport: "run", Value: 165}}
+	n, err = LoadNorms(context.Background(), m)
+	if err != nil {
+		t.Fatalf("LoadNorms: %v", err)
+	}
+	if n.LTHR["run"] != 165 {
+		t.Errorf("expected detected LTHR 165, got %.1f", n.LTHR["run"])
+	}
+}
+
+func TestRun(t *testing.T) {
+	m := newMemStore()
+	m.thresholds = []db.ThresholdHistory{{Metric: "lthr", Sport: "run", Value: 170}}
+	m.activities[1] = db.ListActivitiesNeedingClassificationRow{
+		ID:               1,
+		Type:             sql.NullString{String: "Run", Valid: true},
+		MovingTime:       sql.NullInt64{Int64: 2400, Valid: true},
+		AverageHeartrate: sql.NullFloat64{Float64: 155, Valid: true},
+	}
+	st := repeats(4.5, 2.5)
+	m.streams[1] = db.ActivityStream{ActivityID: 1, TimeData: jsonColumn(t, st.Time), VelocityData: jsonColumn(t, st.Velocity)}
+	m.activities[2] = db.ListActivitiesNeedingClassificationRow{
+		ID:   2,
+		Type: sql.NullString{String: "Yoga", Valid: true},
+	}
+
+	n, err := Run(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 activities classified, got %d", n)
+	}
+	if got := m.classified[1]; got.WorkoutCategory.String != CategoryIntervals || !got.WorkoutConfidence.Valid {
+		t.Errorf("expected intervals from the streams, got %+v", got)
+	}
+	if got, ok := m.classified[2]; !ok || got.WorkoutCategory.Valid {
+		t.Errorf("expected yoga marked classified with no category, got %+v", got)
+	}
+}
@@ -0,0 +1,450 @@
+// Package zones computes time-in-zone distributions locally from activity
+// streams, so zone analysis works without Strava's Summit-only activity
+// zones endpoint.
+package zones
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+	"github.com/joshdurbin/strava-mcp/internal/logging"
+	"github.com/joshdurbin/strava-mcp/internal/streams"
+)
+
+// Zone types
+const (
+	TypeHeartrate = "heartrate"
+	TypePower     = "power"
+	TypePace      = "pace"
+)
+
+// PaceScale converts m/s to the integer units pace zones are stored in.
+// Pace zone bounds are speeds in mm/s, so zone 1 is the slowest like the
+// other zone types.
+const PaceScale = 1000
+
+// paceZoneFactors place pace zone boundaries as a fraction of threshold
+// pace, slowest first: zone 1 is slower than 129% of threshold pace, zone 5
+// faster than 99% (Friel's run pace zones, collapsed to five)
+var paceZoneFactors = []float64{1.29, 1.14, 1.06, 0.99}
+
+// lthrZoneFactors place heart rate zone boundaries as a fraction of lactate
+// threshold heart rate (Friel's zones, collapsed to five)
+var lthrZoneFactors = []float64{0.85, 0.90, 0.95, 1.00}
+
+// ftpZoneFactors place power zone boundaries as a fraction of FTP (Coggan's
+// seven levels)
+var ftpZoneFactors = []float64{0.56, 0.76, 0.91, 1.06, 1.21, 1.51}
+
+// thresholdIntensity is the fraction of VO2max held at threshold pace in
+// Daniels' tables
+const thresholdIntensity = 0.88
+
+// Setting sources, in increasing precedence
+const (
+	SourceAthlete = "athlete" // the athlete's Strava profile
+	SourceConfig  = "config"  // --zones-file
+)
+
+// SourceLocal marks activity zones computed here rather than fetched from Strava
+const SourceLocal = "local"
+
+// maxSampleGap caps the seconds credited to a single sample, so a recording
+// pause doesn't count as minutes spent in whatever zone came next
+const maxSampleGap = 30
+
+// pageSize is the number of activities computed per query
+const pageSize = 100
+
+// Range bounds a single zone. Max is -1 for the top zone.
+type Range struct {
+	Min int
+	Max int
+}
+
+// Ranges turns boundaries, the lower bounds of zones 2..n, into zone ranges
+// starting from zero
+func Ranges(boundaries []int) []Range {
+	ranges := make([]Range, 0, len(boundaries)+1)
+	lo := 0
+	for _, b := range boundaries {
+		ranges = append(ranges, Range{Min: lo, Max: b})
+		lo = b
+	}
+	return append(ranges, Range{Min: lo, Max: -1})
+}
+
+// Validate checks that boundaries are positive and strictly ascending
+func Validate(boundaries []int) error {
+	if len(boundaries) == 0 {
+		return fmt.Errorf("no zone boundaries")
+	}
+	for i, b := range boundaries {
+		if b <= 0 {
+			return fmt.Errorf("zone boundary %d must be positive", b)
+		}
+		if i > 0 && b <= boundaries[i-1] {
+			return fmt.Errorf("zone boundaries must be ascending (%d after %d)", b, boundaries[i-1])
+		}
+	}
+	return nil
+}
+
+// Distribution returns the seconds spent in each range. Each sample is
+// credited the time since the previous one, capped at maxSampleGap. Samples
+// flagged as not moving are skipped, as are zero values unless includeZero
+// is set (zero watts is coasting, zero BPM is a sensor dropout).
+func Distribution(ranges []Range, time []int, values []float64, moving []bool, includeZero bool) []int64 {
+	seconds := make([]int64, len(ranges))
+	n := min(len(time), len(values))
+	for i := 1; i < n; i++ {
+		if i < len(moving) && !moving[i] {
+			continue
+		}
+		v := values[i]
+		if v < 0 || (v == 0 && !includeZero) {
+			continue
+		}
+		dt := min(time[i]-time[i-1], maxSampleGap)
+		if dt <= 0 {
+			continue
+		}
+		for z, r := range ranges {
+			if v >= float64(r.Min) && (r.Max < 0 || v < float64(r.Max)) {
+				seconds[z] += int64(dt)
+				break
+			}
+		}
+	}
+	return seconds
+}
+
+// ParsePace parses a "m:ss" per-kilometer pace into a speed in m/s
+func ParsePace(pace string) (float64, error) {
+	mins, secs, ok := strings.Cut(strings.TrimSuffix(strings.TrimSpace(pace), "/km"), ":")
+	m, errM := strconv.Atoi(mins)
+	sec, errS := strconv.Atoi(secs)
+	if !ok || errM != nil || errS != nil || m < 0 || sec < 0 || sec >= 60 || m*60+sec == 0 {
+		return 0, fmt.Errorf("invalid pace %q (expected m:ss per km)", pace)
+	}
+	return 1000 / float64(m*60+sec), nil
+}
+
+// VDOTThresholdSpeed returns the threshold pace speed in m/s for a Daniels
+// VDOT, solving his oxygen cost of running equation at threshold intensity
+func VDOTThresholdSpeed(vdot float64) float64 {
+	// VO2 = -4.60 + 0.182258v + 0.000104v², v in m/min
+	a, b, c := 0.000104, 0.182258, -4.60-thresholdIntensity*vdot
+	v := (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+	return v / 60
+}
+
+// PaceBoundaries returns pace zone boundaries, in PaceScale units, for a
+// threshold pace speed in m/s
+func PaceBoundaries(thresholdSpeed float64) []int {
+	boundaries := make([]int, len(paceZoneFactors))
+	for i, f := range paceZoneFactors {
+		boundaries[i] = int(math.Round(thresholdSpeed / f * PaceScale))
+	}
+	return boundaries
+}
+
+// LTHRBoundaries returns heart rate zone boundaries for a lactate threshold
+// heart rate in bpm
+func LTHRBoundaries(lthr float64) []int {
+	return scaleBoundaries(lthr, lthrZoneFactors)
+}
+
+// FTPBoundaries returns power zone boundaries for an FTP in watts
+func FTPBoundaries(ftp float64) []int {
+	return scaleBoundaries(ftp, ftpZoneFactors)
+}
+
+func scaleBoundaries(threshold float64, factors []float64) []int {
+	boundaries := make([]int, len(factors))
+	for i, f := range factors {
+		boundaries[i] = int(math.Round(threshold * f))
+	}
+	return boundaries
+}
+
+// LoadFile reads a zones file: a JSON object mapping heartrate and power to
+// their boundaries, plus either threshold_pace ("m:ss" per km) or vdot for
+// pace zones, e.g.
+// {"heartrate": [125, 150, 165, 180], "power": [150, 200, 240, 280, 330, 400], "threshold_pace": "4:30"}
+func LoadFile(path string) (map[string][]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading zones file: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing zones file: %w", err)
+	}
+	if _, hasPace := raw["threshold_pace"]; hasPace {
+		if _, hasVDOT := raw["vdot"]; hasVDOT {
+			return nil, fmt.Errorf("zones file: set threshold_pace or vdot, not both")
+		}
+	}
+
+	settings := make(map[string][]int)
+	for key, value := range raw {
+		switch key {
+		case TypeHeartrate, TypePower:
+			var boundaries []int
+			if err := json.Unmarshal(value, &boundaries); err != nil {
+				return nil, fmt.Errorf("zones file: %s: %w", key, err)
+			}
+			if err := Validate(boundaries); err != nil {
+				return nil, fmt.Errorf("zones file: %s: %w", key, err)
+			}
+			settings[key] = boundaries
+		case "threshold_pace":
+			var pace string
+			if err := json.Unmarshal(value, &pace); err != nil {
+				return nil, fmt.Errorf("zones file: threshold_pace: %w", err)
+			}
+			speed, err := ParsePace(pace)
+			if err != nil {
+				return nil, fmt.Errorf("zones file: threshold_pace: %w", err)
+			}
+			settings[TypePace] = PaceBoundaries(speed)
+		case "vdot":
+			var vdot float64
+			if err := json.Unmarshal(value, &vdot); err != nil {
+				return nil, fmt.Errorf("zones file: vdot: %w", err)
+			}
+			if vdot < 20 || vdot > 90 {
+				return nil, fmt.Errorf("zones file: vdot %.1f out of range (20-90)", vdot)
+			}
+			settings[TypePace] = PaceBoundaries(VDOTThresholdSpeed(vdot))
+		default:
+			return nil, fmt.Errorf("zones file: unknown key %q (expected heartrate, power, threshold_pace or vdot)", key)
+		}
+	}
+	return settings, nil
+}
+
+// Store is the subset of queries needed to manage local zones
+type Store interface {
+	ListZoneSettings(ctx context.Context) ([]db.ZoneSetting, error)
+	UpsertZoneSetting(ctx context.Context, arg db.UpsertZoneSettingParams) error
+	DeleteZoneSettingsBySource(ctx context.Context, source string) error
+	DeleteLocalZoneBuckets(ctx context.Context, zoneType string) error
+	DeleteLocalActivityZones(ctx context.Context, zoneType string) error
+	ListActivitiesNeedingLocalZones(ctx context.Context, arg db.ListActivitiesNeedingLocalZonesParams) ([]int64, error)
+	GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error)
+	CreateActivityZone(ctx context.Context, arg db.CreateActivityZoneParams) (int64, error)
+	DeleteZoneBucketsForActivityZone(ctx context.Context, activityZoneID int64) error
+	CreateZoneBucket(ctx context.Context, arg db.CreateZoneBucketParams) error
+}
+
+// ApplySettings stores the boundaries for a zone type. Athlete settings
+// never replace config settings. When the boundaries change, the local zones
+// computed from the old ones are dropped so they get recomputed. Reports
+// whether anything changed.
+func ApplySettings(ctx context.Context, store Store, zoneType string, boundaries []int, source string) (bool, error) {
+	if err := Validate(boundaries); err != nil {
+		return false, err
+	}
+	encoded, err := json.Marshal(boundaries)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := settings(ctx, store)
+	if err != nil {
+		return false, err
+	}
+	if existing, ok := current[zoneType]; ok {
+		if existing.Source == SourceConfig && source == SourceAthlete {
+			return false, nil
+		}
+		if existing.Boundaries == string(encoded) && existing.Source == source {
+			return false, nil
+		}
+	}
+
+	if err := store.UpsertZoneSetting(ctx, db.UpsertZoneSettingParams{
+		ZoneType:   zoneType,
+		Boundaries: string(encoded),
+		Source:     source,
+	}); err != nil {
+		return false, fmt.Errorf("saving %s zone settings: %w", zoneType, err)
+	}
+	if err := dropLocalZones(ctx, store, zoneType); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ClearConfigSettings removes settings that came from a zones file, along
+// with the local zones computed from them
+func ClearConfigSettings(ctx context.Context, store Store) error {
+	current, err := settings(ctx, store)
+	if err != nil {
+		return err
+	}
+	for zoneType, s := range current {
+		if s.Source != SourceConfig {
+			continue
+		}
+		if err := dropLocalZones(ctx, store, zoneType); err != nil {
+			return err
+		}
+	}
+	if err := store.DeleteZoneSettingsBySource(ctx, SourceConfig); err != nil {
+		return fmt.Errorf("clearing config zone settings: %w", err)
+	}
+	return nil
+}
+
+// Compute stores local zone distributions for every activity that has
+// streams but no zones of a configured type. Activities whose streams yield
+// no time in any zone get an empty zone row so they aren't retried.
+// Returns the number of distributions written.
+func Compute(ctx context.Context, store Store) (int, error) {
+	current, err := settings(ctx, store)
+	if err != nil {
+		return 0, err
+	}
+
+	computed := 0
+	for _, zoneType := range []string{TypeHeartrate, TypePower, TypePace} {
+		s, ok := current[zoneType]
+		if !ok {
+			continue
+		}
+		var boundaries []int
+		if err := json.Unmarshal([]byte(s.Boundaries), &boundaries); err != nil {
+			logging.Warn("Ignoring unreadable zone settings", "zone_type", zoneType, "error", err)
+			continue
+		}
+		ranges := Ranges(boundaries)
+
+		for {
+			ids, err := store.ListActivitiesNeedingLocalZones(ctx, db.ListActivitiesNeedingLocalZonesParams{
+				ZoneType: zoneType,
+				Column2:  zoneType,
+				Limit:    pageSize,
+			})
+			if err != nil {
+				return computed, fmt.Errorf("listing activities needing %s zones: %w", zoneType, err)
+			}
+			for _, id := range ids {
+				if err := ctx.Err(); err != nil {
+					return computed, err
+				}
+				if err := computeActivity(ctx, store, id, zoneType, ranges); err != nil {
+					return computed, err
+				}
+				computed++
+			}
+			if len(ids) < pageSize {
+				break
+			}
+		}
+	}
+	return computed, nil
+}
+
+func computeActivity(ctx context.Context, store Store, activityID int64, zoneType string, ranges []Range) error {
+	var seconds []int64
+	row, err := store.GetActivityStreams(ctx, activityID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("loading streams for activity %d: %w", activityID, err)
+	}
+	if err == nil {
+		st, err := streams.FromRow(row)
+		if err != nil {
+			logging.Warn("Skipping unreadable activity streams", "activity_id", activityID, "error", err)
+		} else {
+			switch zoneType {
+			case TypeHeartrate:
+				seconds = Distribution(ranges, st.Time, st.Heartrate, st.Moving, false)
+			case TypePower:
+				seconds = Distribution(ranges, st.Time, st.Watts, st.Moving, true)
+			case TypePace:
+				speeds := make([]float64, len(st.Velocity))
+				for i, v := range st.Velocity {
+					speeds[i] = v * PaceScale
+				}
+				seconds = Distribution(ranges, st.Time, speeds, st.Moving, false)
+			}
+		}
+	}
+
+	zoneID, err := store.CreateActivityZone(ctx, db.CreateActivityZoneParams{
+		ActivityID:  activityID,
+		ZoneType:    zoneType,
+		SensorBased: 1,
+		Source:      SourceLocal,
+	})
+	if err != nil {
+		return fmt.Errorf("creating activity zone: %w", err)
+	}
+	if err := store.DeleteZoneBucketsForActivityZone(ctx, zoneID); err != nil {
+		return fmt.Errorf("deleting existing zone buckets: %w", err)
+	}
+
+	var total int64
+	for _, s := range seconds {
+		total += s
+	}
+	if total == 0 {
+		return nil
+	}
+	for i, r := range ranges {
+		err := store.CreateZoneBucket(ctx, db.CreateZoneBucketParams{
+			ActivityZoneID: zoneID,
+			ZoneNumber:     int64(i + 1),
+			MinValue:       int64(r.Min),
+			MaxValue:       int64(r.Max),
+			TimeSeconds:    seconds[i],
+		})
+		if err != nil {
+			return fmt.Errorf("creating zone bucket: %w", err)
+		}
+	}
+	return nil
+}
+
+func settings(ctx context.Context, store Store) (map[string]db.ZoneSetting, error) {
+	rows, err := store.ListZoneSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing zone settings: %w", err)
+	}
+	out := make(map[string]db.ZoneSetting, len(rows))
+	for _, r := range rows {
+		out[r.ZoneType] = r
+	}
+	return out, nil
+}
+
+func dropLocalZones(ctx context.Context, store Store, zoneType string) error {
+	if err := store.DeleteLocalZoneBuckets(ctx, zoneType); err != nil {
+		return fmt.Errorf("deleting local %s zone buckets: %w", zoneType, err)
+	}
+	if err := store.DeleteLocalActivityZones(ctx, zoneType); err != nil {
+		return fmt.Errorf("deleting local %s zones: %w", zoneType, err)
+	}
+	return nil
+}
+
+// Types returns the zone types in a settings map, sorted
+func Types(settings map[string][]int) []string {
+	types := make([]string, 0, len(settings))
+	for t := range settings {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
@@ -0,0 +1,426 @@
+package zones
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/joshdurbin/strava-mcp/internal/db"
+)
+
+// memStore is an in-memory Store. Zones are keyed by activity and type, the
+// way the activity_zones unique constraint keys them.
+type memStore struct {
+	settings map[string]db.ZoneSetting
+	streams  map[int64]db.ActivityStream
+	zones    map[int64]map[string]string // activity -> zone type -> source
+	buckets  map[int64]map[string][]db.CreateZoneBucketParams
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		settings: map[string]db.ZoneSetting{},
+		streams:  map[int64]db.ActivityStream{},
+		zones:    map[int64]map[string]string{},
+		buckets:  map[int64]map[string][]db.CreateZoneBucketParams{},
+	}
+}
+
+var zoneTypes = []string{TypeHeartrate, TypePower, TypePace}
+
+// zoneID packs an activity and zone type into a fake row ID
+func zoneID(activityID int64, zoneType string) int64 {
+	return activityID*10 + int64(slices.Index(zoneTypes, zoneType))
+}
+
+// unpackZoneID reverses zoneID
+func unpackZoneID(id int64) (int64, string) {
+	return id / 10, zoneTypes[id%10]
+}
+
+func (m *memStore) ListZoneSettings(ctx context.Context) ([]db.ZoneSetting, error) {
+	var out []db.ZoneSetting
+	for _, s := range m.settings {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (m *memStore) UpsertZoneSetting(ctx context.Context, arg db.UpsertZoneSettingParams) error {
+	m.settings[arg.ZoneType] = db.ZoneSetting{ZoneType: arg.ZoneType, Boundaries: arg.Boundaries, Source: arg.Source}
+	return nil
+}
+
+func (m *memStore) DeleteZoneSettingsBySource(ctx context.Context, source string) error {
+	for t, s := range m.settings {
+		if s.Source == source {
+			delete(m.settings, t)
+		}
+	}
+	return nil
+}
+
+func (m *memStore) DeleteLocalZoneBuckets(ctx context.Context, zoneType string) error {
+	for id, byType := range m.zones {
+		if byType[zoneType] == SourceLocal {
+			delete(m.buckets[id], zoneType)
+		}
+	}
+	return nil
+}
+
+func (m *memStore) DeleteLocalActivityZones(ctx context.Context, zoneType string) error {
+	for _, byType := range m.zones {
+		if byType[zoneType] == SourceLocal {
+			delete(byType, zoneType)
+		}
+	}
+	return nil
+}
+
+func (m *memStore) ListActivitiesNeedingLocalZones(ctx context.Context, arg db.ListActivitiesNeedingLocalZonesParams) ([]int64, error) {
+	var ids []int64
+	for id, row := range m.streams {
+		col := row.HeartrateData
+		switch arg.ZoneType {
+		case TypePower:
+			col = row.WattsData
+		case TypePace:
+			col = row.VelocityData
+		}
+		if !row.TimeData.Valid || !col.Valid {
+			continue
+		}
+		if _, ok := m.zones[id][arg.ZoneType]; ok {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids[:min(len(ids), int(arg.Limit))], nil
+}
+
+func (m *memStore) GetActivityStreams(ctx context.Context, activityID int64) (db.ActivityStream, error) {
+	row, ok := m.streams[activityID]
+	if !ok {
+		return db.ActivityStream{}, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+func (m *memStore) CreateActivityZone(ctx context.Context, arg db.CreateActivityZoneParams) (int64, error) {
+	if m.zones[arg.ActivityID] == nil {
+		m.zones[arg.ActivityID] = map[string]string{}
+	}
+	m.zones[arg.ActivityID][arg.ZoneType] = arg.Source
+	return zoneID(arg.ActivityID, arg.ZoneType), nil
+}
+
+func (m *memStore) DeleteZoneBucketsForActivityZone(ctx context.Context, activityZoneID int64) error {
+	activityID, zoneType := unpackZoneID(activityZoneID)
+	delete(m.buckets[activityID], zoneType)
+	return nil
+}
+
+func (m *memStore) CreateZoneBucket(ctx context.Context, arg db.CreateZoneBucketParams) error {
+	activityID, zoneType := unpackZoneID(arg.ActivityZoneID)
+	if m.buckets[activityID] == nil {
+		m.buckets[activityID] = map[string][]db.CreateZoneBucketParams{}
+	}
+	m.buckets[activityID][zoneType] = append(m.buckets[activityID][zoneType], arg)
+	return nil
+}
+
+func jsonColumn(t *testing.T, v any) sql.NullString {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+func TestRanges(t *testing.T) {
+	got := Ranges([]int{120, 150})
+	want := []Range{{0, 120}, {120, 150}, {150, -1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		boundaries []int
+		ok         bool
+	}{
+		{[]int{120, 150, 170}, true},
+		{nil, false},
+		{[]int{0, 150}, false},
+		{[]int{150, 150}, false},
+		{[]int{170, 150}, false},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.boundaries); (err == nil) != tt.ok {
+			t.Errorf("Validate(%v) = %v, expected ok=%v", tt.boundaries, err, tt.ok)
+		}
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	ranges := Ranges([]int{120, 150})
+	time := []int{0, 10, 20, 30, 130, 140, 150}
+	values := []float64{100, 100, 130, 160, 160, 0, 140}
+	moving := []bool{true, true, true, true, true, true, false}
+
+	got := Distribution(ranges, time, values, moving, false)
+	// 10s in Z1, 10s in Z2, 10s + a 100s gap capped at 30s in Z3, the zero
+	// and stopped samples skipped
+	want := []int64{10, 10, 40}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	got = Distribution(ranges, time, values, nil, true)
+	want = []int64{20, 20, 40}
+	if !slices.Equal(got, want) {
+		t.Errorf("with zeros and no moving stream: expected %v, got %v", want, got)
+	}
+}
+
+func TestParsePace(t *testing.T) {
+	speed, err := ParsePace("4:10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if speed != 4 {
+		t.Errorf("expected 4 m/s, got %f", speed)
+	}
+	if speed, _ := ParsePace(" 5:00/km"); speed != 1000.0/300 {
+		t.Errorf("expected a /km suffix to be accepted, got %f", speed)
+	}
+	for _, bad := range []string{"", "4", "4:60", "0:00", "x:10", "-1:30"} {
+		if _, err := ParsePace(bad); err == nil {
+			t.Errorf("ParsePace(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestVDOTThresholdSpeed(t *testing.T) {
+	// Daniels' tables put threshold pace for VDOT 50 at 4:15/km
+	secPerKm := 1000 / VDOTThresholdSpeed(50)
+	if math.Abs(secPerKm-255) > 2 {
+		t.Errorf("expected ~255 s/km at VDOT 50, got %.1f", secPerKm)
+	}
+	if VDOTThresholdSpeed(60) <= VDOTThresholdSpeed(50) {
+		t.Error("expected a higher VDOT to mean a faster threshold pace")
+	}
+}
+
+func TestPaceBoundaries(t *testing.T) {
+	got := PaceBoundaries(4) // 4:10/km threshold
+	want := []int{3101, 3509, 3774, 4040}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if err := Validate(got); err != nil {
+		t.Errorf("expected valid boundaries: %v", err)
+	}
+}
+
+func TestThresholdBoundaries(t *testing.T) {
+	if got, want := LTHRBoundaries(170), []int{145, 153, 162, 170}; !slices.Equal(got, want) {
+		t.Errorf("expected heart rate boundaries %v, got %v", want, got)
+	}
+	if got, want := FTPBoundaries(250), []int{140, 190, 228, 265, 303, 378}; !slices.Equal(got, want) {
+		t.Errorf("expected power boundaries %v, got %v", want, got)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	settings, err := LoadFile(write("ok.json", `{"heartrate": [125, 150, 165, 180], "power": [150, 200]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(settings[TypeHeartrate], []int{125, 150, 165, 180}) || !slices.Equal(settings[TypePower], []int{150, 200}) {
+		t.Errorf("unexpected settings: %v", settings)
+	}
+	if got := Types(settings); !slices.Equal(got, []string{TypeHeartrate, TypePower}) {
+		t.Errorf("expected sorted types, got %v", got)
+	}
+
+	settings, err = LoadFile(write("pace.json", `{"threshold_pace": "4:10"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(settings[TypePace], PaceBoundaries(4)) {
+		t.Errorf("unexpected pace boundaries: %v", settings[TypePace])
+	}
+	settings, err = LoadFile(write("vdot.json", `{"vdot": 50}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(settings[TypePace], PaceBoundaries(VDOTThresholdSpeed(50))) {
+		t.Errorf("unexpected VDOT pace boundaries: %v", settings[TypePace])
+	}
+
+	for name, content := range map[string]string{
+		"type.json":       `{"pace": [300]}`,
+		"both.json":       `{"threshold_pace": "4:10", "vdot": 50}`,
+		"badpace.json":    `{"threshold_pace": "fast"}`,
+		"badvdot.json":    `{"vdot": 5}`,
+		"descending.json": `{"heartrate": [150, 125]}`,
+		"broken.json":     `{"heartrate": `,
+	} {
+		if _, err := LoadFile(write(name, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := LoadFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestApplySettingsPrecedence(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+
+	changed, err := ApplySettings(ctx, store, TypeHeartrate, []int{120, 150}, SourceAthlete)
+	if err != nil || !changed {
+		t.Fatalf("expected athlete settings to be stored, got changed=%v err=%v", changed, err)
+	}
+	if changed, _ := ApplySettings(ctx, store, TypeHeartrate, []int{120, 150}, SourceAthlete); changed {
+		t.Error("expected identical settings to be a no-op")
+	}
+	if changed, _ := ApplySettings(ctx, store, TypeHeartrate, []int{130, 160}, SourceConfig); !changed {
+		t.Error("expected config settings to replace athlete settings")
+	}
+	if changed, _ := ApplySettings(ctx, store, TypeHeartrate, []int{120, 150}, SourceAthlete); changed {
+		t.Error("expected athlete settings not to replace config settings")
+	}
+	if store.settings[TypeHeartrate].Boundaries != "[130,160]" {
+		t.Errorf("expected config boundaries to stick, got %s", store.settings[TypeHeartrate].Boundaries)
+	}
+	if _, err := ApplySettings(ctx, store, TypePower, []int{200, 100}, SourceConfig); err == nil {
+		t.Error("expected invalid boundaries to be rejected")
+	}
+
+	if err := ClearConfigSettings(ctx, store); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.settings) != 0 {
+		t.Errorf("expected config settings to be cleared, got %v", store.settings)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	store.streams[1] = db.ActivityStream{
+		ActivityID:    1,
+		TimeData:      jsonColumn(t, []int{0, 10, 20, 30}),
+		HeartrateData: jsonColumn(t, []int{110, 110, 140, 160}),
+		WattsData:     jsonColumn(t, []int{0, 0, 250, 250}),
+	}
+	store.streams[2] = db.ActivityStream{
+		ActivityID:    2,
+		TimeData:      jsonColumn(t, []int{0, 10}),
+		HeartrateData: jsonColumn(t, []int{0, 0}), // strap never connected
+	}
+	// Activity 3 already has Strava zones, which take precedence
+	store.streams[3] = store.streams[1]
+	store.zones[3] = map[string]string{TypeHeartrate: "strava"}
+
+	if n, err := Compute(ctx, store); err != nil || n != 0 {
+		t.Fatalf("expected nothing computed without settings, got %d (%v)", n, err)
+	}
+
+	if _, err := ApplySettings(ctx, store, TypeHeartrate, []int{120, 150}, SourceAthlete); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ApplySettings(ctx, store, TypePower, []int{200}, SourceConfig); err != nil {
+		t.Fatal(err)
+	}
+	n, err := Compute(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// HR for 1 and 2, power for 1 and 3
+	if n != 4 {
+		t.Errorf("expected 4 distributions, got %d", n)
+	}
+	if store.zones[3][TypeHeartrate] != "strava" {
+		t.Error("expected Strava zones to be left alone")
+	}
+
+	hr := store.buckets[1][TypeHeartrate]
+	if len(hr) != 3 || hr[0].TimeSeconds != 10 || hr[1].TimeSeconds != 10 || hr[2].TimeSeconds != 10 {
+		t.Errorf("unexpected heart rate buckets: %+v", hr)
+	}
+	if hr[2].MinValue != 150 || hr[2].MaxValue != -1 {
+		t.Errorf("expected an open-ended top zone, got %+v", hr[2])
+	}
+	power := store.buckets[1][TypePower]
+	if len(power) != 2 || power[0].TimeSeconds != 10 || power[1].TimeSeconds != 20 {
+		t.Errorf("unexpected power buckets: %+v", power)
+	}
+	if store.zones[2][TypeHeartrate] != SourceLocal || len(store.buckets[2][TypeHeartrate]) != 0 {
+		t.Error("expected an empty local zone row for an activity without heart rate")
+	}
+
+	if n, _ := Compute(ctx, store); n != 0 {
+		t.Errorf("expected a second pass to compute nothing, got %d", n)
+	}
+
+	// New boundaries drop and recompute the local zones of that type only
+	if _, err := ApplySettings(ctx, store, TypeHeartrate, []int{100, 150}, SourceConfig); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := Compute(ctx, store); n != 2 {
+		t.Errorf("expected heart rate to be recomputed for 2 activities, got %d", n)
+	}
+	if hr := store.buckets[1][TypeHeartrate]; hr[1].TimeSeconds != 20 {
+		t.Errorf("expected recomputed buckets, got %+v", hr)
+	}
+}
+
+func TestComputePace(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	store.streams[1] = db.ActivityStream{
+		ActivityID:   1,
+		TimeData:     jsonColumn(t, []int{0, 10, 20, 30, 40}),
+		VelocityData: jsonColumn(t, []float64{0, 2.5, 3.6, 4.2, 0}),
+		MovingData:   jsonColumn(t, []bool{false, true, true, true, false}),
+	}
+
+	if _, err := ApplySettings(ctx, store, TypePace, PaceBoundaries(4), SourceConfig); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := Compute(ctx, store); err != nil || n != 1 {
+		t.Fatalf("expected 1 distribution, got %d (%v)", n, err)
+	}
+
+	// 2.5 m/s is an easy jog, 3.6 m/s zone 3 and 4.2 m/s faster than threshold
+	pace := store.buckets[1][TypePace]
+	got := make([]int64, len(pace))
+	for i, b := range pace {
+		got[i] = b.TimeSeconds
+	}
+	if want := []int64{10, 0, 10, 0, 10}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Route geometry from the Strava activity list (map.summary_polyline, start/end latlng)
+ALTER TABLE activities ADD COLUMN summary_polyline TEXT;
+ALTER TABLE activities ADD COLUMN start_lat REAL;
+ALTER TABLE activities ADD COLUMN start_lng REAL;
+ALTER TABLE activities ADD COLUMN end_lat REAL;
+ALTER TABLE activities ADD COLUMN end_lng REAL;
+
+-- +goose Down
+ALTER TABLE activities DROP COLUMN end_lng;
+ALTER TABLE activities DROP COLUMN end_lat;
+ALTER TABLE activities DROP COLUMN start_lng;
+ALTER TABLE activities DROP COLUMN start_lat;
+ALTER TABLE activities DROP COLUMN summary_polyline;
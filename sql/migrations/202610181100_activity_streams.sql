@@ -0,0 +1,24 @@
+-- +goose Up
+-- Per-activity time series from the Strava streams API. Each *_data column
+-- holds a JSON array aligned by index; NULL when the stream is unavailable.
+-- A row with point_count = 0 records that Strava had no streams (e.g. manual
+-- activities) so the activity is not fetched again.
+CREATE TABLE IF NOT EXISTS activity_streams (
+    activity_id INTEGER PRIMARY KEY,
+    point_count INTEGER NOT NULL DEFAULT 0,
+    time_data TEXT,          -- seconds since start
+    distance_data TEXT,      -- meters
+    latlng_data TEXT,        -- [[lat, lng], ...]
+    altitude_data TEXT,      -- meters
+    velocity_data TEXT,      -- velocity_smooth, m/s
+    heartrate_data TEXT,     -- bpm
+    cadence_data TEXT,       -- rpm (running cadence is per leg)
+    watts_data TEXT,         -- watts
+    grade_data TEXT,         -- grade_smooth, percent
+    moving_data TEXT,        -- booleans
+    fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS activity_streams;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Recurring routes found by clustering activity polylines. Route IDs are
+-- stable: an activity is assigned once, and later activities join an
+-- existing route when they match its representative polyline.
+CREATE TABLE IF NOT EXISTS routes_detected (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    activity_type TEXT,
+    representative_activity_id INTEGER NOT NULL,
+    polyline TEXT NOT NULL,            -- summary polyline of the representative activity
+    distance REAL NOT NULL,            -- meters
+    start_lat REAL NOT NULL,
+    start_lng REAL NOT NULL,
+    end_lat REAL NOT NULL,
+    end_lng REAL NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per activity that has been through route detection
+CREATE TABLE IF NOT EXISTS route_efforts (
+    activity_id INTEGER PRIMARY KEY,
+    route_id INTEGER NOT NULL,
+    shape_distance REAL NOT NULL DEFAULT 0, -- mean deviation from the route's polyline in meters
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE,
+    FOREIGN KEY (route_id) REFERENCES routes_detected(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_route_efforts_route_id ON route_efforts(route_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_route_efforts_route_id;
+DROP TABLE IF EXISTS route_efforts;
+DROP TABLE IF EXISTS routes_detected;
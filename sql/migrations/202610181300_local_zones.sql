@@ -0,0 +1,19 @@
+-- +goose Up
+-- Where an activity's zone distribution came from: 'strava' for the Summit
+-- activity zones API, 'local' when computed from the activity's streams
+ALTER TABLE activity_zones ADD COLUMN source TEXT NOT NULL DEFAULT 'strava';
+
+-- Zone boundaries used to compute local zones. boundaries is a JSON array of
+-- the lower bound of zones 2..n (BPM or watts); zone 1 starts at 0 and the
+-- last zone is unbounded. source is 'athlete' (Strava profile) or 'config'
+-- (--zones-file), which takes precedence.
+CREATE TABLE IF NOT EXISTS zone_settings (
+    zone_type TEXT PRIMARY KEY,  -- 'heartrate' or 'power'
+    boundaries TEXT NOT NULL,
+    source TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS zone_settings;
+ALTER TABLE activity_zones DROP COLUMN source;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Per-activity threshold estimates from the best 20 minutes of its streams.
+-- One row per analyzed activity; a NULL estimate means the activity had no
+-- usable 20 minutes of that stream. sport is 'run', 'ride' or '' (neither).
+CREATE TABLE IF NOT EXISTS threshold_efforts (
+    activity_id INTEGER PRIMARY KEY,
+    sport TEXT NOT NULL,
+    start_date DATETIME,
+    lthr REAL,             -- bpm
+    ftp REAL,              -- watts
+    threshold_speed REAL,  -- m/s
+    analyzed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_threshold_efforts_start_date ON threshold_efforts(start_date);
+
+-- Each time an estimate rose past the previous threshold. notified is set
+-- once get_threshold_history has reported the change.
+CREATE TABLE IF NOT EXISTS threshold_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    metric TEXT NOT NULL,           -- 'lthr', 'ftp' or 'threshold_pace'
+    sport TEXT NOT NULL,            -- 'run' or 'ride'
+    value REAL NOT NULL,            -- bpm, watts, or m/s for threshold_pace
+    previous_value REAL,
+    activity_id INTEGER NOT NULL,   -- the effort the estimate came from
+    detected_on DATETIME NOT NULL,  -- start date of that activity
+    notified INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_threshold_history_metric ON threshold_history(metric, sport, detected_on);
+
+-- +goose Down
+DROP TABLE IF EXISTS threshold_history;
+DROP TABLE IF EXISTS threshold_efforts;
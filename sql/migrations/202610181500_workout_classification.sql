@@ -0,0 +1,20 @@
+-- +goose Up
+-- Strava's workout_type (0 default, 1 race, 2 long run, 3 workout for runs;
+-- 10 default, 11 race, 12 workout for rides) and the locally classified
+-- workout category: easy, tempo, intervals, long or race. Category and
+-- confidence stay NULL for activity types that aren't classified.
+-- workout_classified_at marks when the activity was last classified, so
+-- activities updated or given streams since are classified again.
+ALTER TABLE activities ADD COLUMN workout_type INTEGER;
+ALTER TABLE activities ADD COLUMN workout_category TEXT;
+ALTER TABLE activities ADD COLUMN workout_confidence REAL;
+ALTER TABLE activities ADD COLUMN workout_classified_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_activities_workout_category ON activities(workout_category, start_date);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_activities_workout_category;
+ALTER TABLE activities DROP COLUMN workout_classified_at;
+ALTER TABLE activities DROP COLUMN workout_confidence;
+ALTER TABLE activities DROP COLUMN workout_category;
+ALTER TABLE activities DROP COLUMN workout_type;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Races the athlete is training for. priority is 'A' (the goal race), 'B'
+-- or 'C' (tune-ups); race readiness defaults to the next A race.
+CREATE TABLE IF NOT EXISTS races (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    race_date DATETIME NOT NULL,
+    distance REAL NOT NULL,         -- meters
+    activity_type TEXT NOT NULL,    -- 'Run', 'Ride', ...
+    priority TEXT NOT NULL DEFAULT 'A',
+    goal_time INTEGER,              -- seconds
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_races_race_date ON races(race_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS races;
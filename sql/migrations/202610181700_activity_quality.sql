@@ -0,0 +1,36 @@
+-- +goose Up
+-- One row per activity checked for data problems. Activities updated or
+-- given streams since checked_at are checked again.
+CREATE TABLE IF NOT EXISTS activity_quality_checks (
+    activity_id INTEGER PRIMARY KEY,
+    checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Problems found in an activity. code is 'implausible_speed', 'gps_spike',
+-- 'zero_distance', 'hr_dropout', 'implausible_heartrate' or 'duplicate';
+-- related_activity_id is the original of a duplicate. Activities with an
+-- 'error' flag are left out of records and progress; 'warning' flags are
+-- reported only.
+CREATE TABLE IF NOT EXISTS activity_flags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    activity_id INTEGER NOT NULL,
+    code TEXT NOT NULL,
+    severity TEXT NOT NULL,         -- 'error' or 'warning'
+    detail TEXT NOT NULL,
+    related_activity_id INTEGER,
+    detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE,
+    UNIQUE (activity_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_flags_severity ON activity_flags(severity, activity_id);
+
+-- Activities left out of records and progress
+CREATE VIEW IF NOT EXISTS excluded_activities AS
+SELECT DISTINCT activity_id FROM activity_flags WHERE severity = 'error';
+
+-- +goose Down
+DROP VIEW IF EXISTS excluded_activities;
+DROP TABLE IF EXISTS activity_flags;
+DROP TABLE IF EXISTS activity_quality_checks;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- Local corrections to an activity. name, type and distance replace Strava's
+-- values in activities (type also replaces sport_type, and distance the
+-- average speed) and are applied again whenever the activity syncs.
+-- original_* hold Strava's values from when the first correction was made,
+-- restored when the corrections are reset. counted is NULL to leave stats to
+-- the data quality checks, 0 to leave the activity out of records, progress
+-- and totals, or 1 to count it even when flagged.
+CREATE TABLE IF NOT EXISTS activity_overrides (
+    activity_id INTEGER PRIMARY KEY,
+    name TEXT,
+    type TEXT,
+    distance REAL,
+    counted INTEGER,
+    reason TEXT,
+    original_name TEXT,
+    original_type TEXT,
+    original_sport_type TEXT,
+    original_distance REAL,
+    original_average_speed REAL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Activities left out of records and progress: those with a data quality
+-- error unless counted anyway, and those excluded by hand
+DROP VIEW IF EXISTS excluded_activities;
+CREATE VIEW IF NOT EXISTS excluded_activities AS
+SELECT activity_id FROM activity_flags
+WHERE severity = 'error'
+  AND activity_id NOT IN (SELECT activity_id FROM activity_overrides WHERE counted = 1)
+UNION
+SELECT activity_id FROM activity_overrides WHERE counted = 0;
+
+-- +goose Down
+DROP VIEW IF EXISTS excluded_activities;
+CREATE VIEW IF NOT EXISTS excluded_activities AS
+SELECT DISTINCT activity_id FROM activity_flags WHERE severity = 'error';
+DROP TABLE IF EXISTS activity_overrides;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Kudos, comment and athlete counts for each activity, from the activities
+-- Strava returns. lists_kudos_count and lists_comment_count are the counts
+-- when the kudos and comments were last fetched, NULL when never, so lists
+-- are fetched again only when the counts change.
+CREATE TABLE IF NOT EXISTS activity_social (
+    activity_id INTEGER PRIMARY KEY,
+    kudos_count INTEGER NOT NULL DEFAULT 0,
+    comment_count INTEGER NOT NULL DEFAULT 0,
+    athlete_count INTEGER NOT NULL DEFAULT 1,
+    lists_kudos_count INTEGER,
+    lists_comment_count INTEGER,
+    lists_synced_at DATETIME,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Athletes who gave an activity kudos, in Strava's order. Strava only gives
+-- names (first name and last initial), not athlete IDs.
+CREATE TABLE IF NOT EXISTS activity_kudos (
+    activity_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    athlete_name TEXT NOT NULL,
+    PRIMARY KEY (activity_id, position),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS activity_comments (
+    id INTEGER PRIMARY KEY,
+    activity_id INTEGER NOT NULL,
+    athlete_name TEXT NOT NULL,
+    text TEXT NOT NULL,
+    created_at DATETIME,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_comments_activity ON activity_comments(activity_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_activity_comments_activity;
+DROP TABLE IF EXISTS activity_comments;
+DROP TABLE IF EXISTS activity_kudos;
+DROP TABLE IF EXISTS activity_social;
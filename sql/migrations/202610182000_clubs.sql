@@ -0,0 +1,43 @@
+-- +goose Up
+-- Clubs the athlete belongs to, refreshed on each club sync
+CREATE TABLE IF NOT EXISTS clubs (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    sport_type TEXT,
+    city TEXT,
+    state TEXT,
+    country TEXT,
+    private INTEGER NOT NULL DEFAULT 0,
+    member_count INTEGER NOT NULL DEFAULT 0,
+    url TEXT,
+    synced_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Activities from a club's feed. Strava gives club activities without an ID
+-- or start date, so each is identified by a fingerprint of its athlete, name,
+-- type, distance and times, and dated by when a sync first saw it. Activities
+-- found on a club's first sync are marked backfill: their dates are unknown.
+CREATE TABLE IF NOT EXISTS club_activities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    club_id INTEGER NOT NULL,
+    fingerprint TEXT NOT NULL,
+    athlete_name TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT,
+    sport_type TEXT,
+    distance REAL NOT NULL DEFAULT 0,
+    moving_time INTEGER NOT NULL DEFAULT 0,
+    elapsed_time INTEGER NOT NULL DEFAULT 0,
+    total_elevation_gain REAL NOT NULL DEFAULT 0,
+    first_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    backfill INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (club_id, fingerprint),
+    FOREIGN KEY (club_id) REFERENCES clubs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_club_activities_seen ON club_activities(club_id, first_seen_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_club_activities_seen;
+DROP TABLE IF EXISTS club_activities;
+DROP TABLE IF EXISTS clubs;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Routes the athlete created or saved on Strava. gpx is the route's GPX
+-- export and gpx_updated_at the updated_at it was exported for, so the GPX
+-- is fetched again only when the route changes.
+CREATE TABLE IF NOT EXISTS saved_routes (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT,
+    type TEXT,
+    sub_type TEXT,
+    distance REAL NOT NULL DEFAULT 0,
+    elevation_gain REAL NOT NULL DEFAULT 0,
+    estimated_moving_time INTEGER NOT NULL DEFAULT 0,
+    polyline TEXT,
+    private INTEGER NOT NULL DEFAULT 0,
+    starred INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME,
+    updated_at DATETIME,
+    gpx TEXT,
+    gpx_updated_at DATETIME,
+    synced_at DATETIME NOT NULL
+);
+
+-- Segments the athlete starred, with their PR and effort count from Strava
+CREATE TABLE IF NOT EXISTS starred_segments (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    activity_type TEXT,
+    distance REAL NOT NULL DEFAULT 0,
+    average_grade REAL NOT NULL DEFAULT 0,
+    maximum_grade REAL NOT NULL DEFAULT 0,
+    elevation_high REAL NOT NULL DEFAULT 0,
+    elevation_low REAL NOT NULL DEFAULT 0,
+    climb_category INTEGER NOT NULL DEFAULT 0,
+    city TEXT,
+    state TEXT,
+    country TEXT,
+    private INTEGER NOT NULL DEFAULT 0,
+    start_lat REAL,
+    start_lng REAL,
+    end_lat REAL,
+    end_lng REAL,
+    pr_elapsed_time INTEGER,
+    pr_date TEXT,
+    pr_activity_id INTEGER,
+    effort_count INTEGER NOT NULL DEFAULT 0,
+    synced_at DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS starred_segments;
+DROP TABLE IF EXISTS saved_routes;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Activities that failed to save during a sync. A row is cleared when the
+-- activity saves, and gap repair fetches each one again by ID.
+CREATE TABLE IF NOT EXISTS sync_failures (
+    activity_id INTEGER PRIMARY KEY,
+    activity_name TEXT,
+    start_date DATETIME,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    first_failed_at DATETIME NOT NULL,
+    last_failed_at DATETIME NOT NULL
+);
+
+-- The latest comparison of Strava's athlete stats with the stored totals,
+-- one row per sport and period. repaired_missing is how many activities the
+-- period was missing when it was last repaired, so a gap repair could not
+-- close is only resynced again once it grows.
+CREATE TABLE IF NOT EXISTS sync_integrity (
+    sport TEXT NOT NULL,
+    period TEXT NOT NULL,
+    window_start DATETIME,
+    strava_count INTEGER NOT NULL,
+    local_count INTEGER NOT NULL,
+    strava_distance REAL NOT NULL,
+    local_distance REAL NOT NULL,
+    strava_moving_time INTEGER NOT NULL,
+    local_moving_time INTEGER NOT NULL,
+    repaired_missing INTEGER,
+    checked_at DATETIME NOT NULL,
+    PRIMARY KEY (sport, period)
+);
+
+-- Gap repair resyncs, one row per date window or failed activity
+CREATE TABLE IF NOT EXISTS sync_repairs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    reason TEXT NOT NULL,
+    window_start DATETIME,
+    window_end DATETIME,
+    fetched INTEGER NOT NULL DEFAULT 0,
+    added INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    repaired_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_repairs_repaired_at ON sync_repairs(repaired_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_sync_repairs_repaired_at;
+DROP TABLE IF EXISTS sync_repairs;
+DROP TABLE IF EXISTS sync_integrity;
+DROP TABLE IF EXISTS sync_failures;
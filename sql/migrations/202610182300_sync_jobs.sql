@@ -0,0 +1,49 @@
+-- +goose Up
+-- Durable queue of Strava fetches. target is the page number for list_page
+-- jobs and the activity ID for the per-activity kinds (activity, streams,
+-- zones, laps). Jobs run highest priority first once run_after has passed;
+-- a failed job is retried with exponential backoff until max_attempts, then
+-- kept as dead. Jobs left running by a crash are reset to pending on start.
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    target INTEGER NOT NULL,
+    after_time DATETIME,
+    priority INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    last_error TEXT,
+    run_after DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    finished_at DATETIME,
+    UNIQUE (kind, target)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_ready ON sync_jobs(status, priority DESC, run_after);
+
+-- Laps recorded with an activity (device laps or Strava's auto-laps)
+CREATE TABLE IF NOT EXISTS activity_laps (
+    activity_id INTEGER NOT NULL,
+    lap_index INTEGER NOT NULL,
+    name TEXT,
+    distance REAL,
+    moving_time INTEGER,
+    elapsed_time INTEGER,
+    start_date DATETIME,
+    total_elevation_gain REAL,
+    average_speed REAL,
+    max_speed REAL,
+    average_heartrate REAL,
+    max_heartrate REAL,
+    average_cadence REAL,
+    average_watts REAL,
+    PRIMARY KEY (activity_id, lap_index),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS activity_laps;
+DROP INDEX IF EXISTS idx_sync_jobs_ready;
+DROP TABLE IF EXISTS sync_jobs;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- The Strava rate limit budget shared by every client in the process, kept
+-- so a restart remembers what the current 15-minute window and day used.
+-- There is only ever one row.
+CREATE TABLE IF NOT EXISTS rate_limit_budget (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    window_start DATETIME NOT NULL,
+    window_usage INTEGER NOT NULL,
+    window_limit INTEGER NOT NULL,
+    day_start DATETIME NOT NULL,
+    daily_usage INTEGER NOT NULL,
+    daily_limit INTEGER NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS rate_limit_budget;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Small pieces of sync bookkeeping by key, such as when a backfill was last
+-- attempted, so they survive a restart
+CREATE TABLE IF NOT EXISTS sync_state (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS sync_state;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- get_threshold_history reports thresholds detected since a date rather than
+-- marking each one reported
+ALTER TABLE threshold_history DROP COLUMN notified;
+
+-- +goose Down
+ALTER TABLE threshold_history ADD COLUMN notified INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,51 @@
+-- +goose Up
+-- A list page is one page of a listing from its after_time, so a delta sync
+-- can be queued while a full sync, which has no after_time, is still paging.
+-- SQLite cannot drop a table constraint, so the table is rebuilt.
+CREATE TABLE sync_jobs_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    target INTEGER NOT NULL,
+    after_time DATETIME,
+    priority INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    last_error TEXT,
+    run_after DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    finished_at DATETIME
+);
+INSERT INTO sync_jobs_new SELECT * FROM sync_jobs;
+DROP TABLE sync_jobs;
+ALTER TABLE sync_jobs_new RENAME TO sync_jobs;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_jobs_target ON sync_jobs(kind, target, IFNULL(after_time, ''));
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_ready ON sync_jobs(status, priority DESC, run_after);
+
+-- +goose Down
+CREATE TABLE sync_jobs_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    target INTEGER NOT NULL,
+    after_time DATETIME,
+    priority INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    last_error TEXT,
+    run_after DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    finished_at DATETIME,
+    UNIQUE (kind, target)
+);
+-- Only the latest job for each page is kept
+INSERT INTO sync_jobs_old
+SELECT * FROM sync_jobs
+WHERE id IN (SELECT MAX(id) FROM sync_jobs GROUP BY kind, target);
+DROP TABLE sync_jobs;
+ALTER TABLE sync_jobs_old RENAME TO sync_jobs;
+
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_ready ON sync_jobs(status, priority DESC, run_after);
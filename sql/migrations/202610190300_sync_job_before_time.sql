@@ -0,0 +1,12 @@
+-- +goose Up
+-- Gap repair lists the activities in a date window, so a list page can also
+-- end at before_time, and pages of different windows are separate jobs
+ALTER TABLE sync_jobs ADD COLUMN before_time DATETIME;
+DROP INDEX IF EXISTS idx_sync_jobs_target;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_jobs_target ON sync_jobs(kind, target, IFNULL(after_time, ''), IFNULL(before_time, ''));
+
+-- +goose Down
+DELETE FROM sync_jobs WHERE before_time IS NOT NULL;
+DROP INDEX IF EXISTS idx_sync_jobs_target;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_jobs_target ON sync_jobs(kind, target, IFNULL(after_time, ''));
+ALTER TABLE sync_jobs DROP COLUMN before_time;
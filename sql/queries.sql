@@ -3,30 +3,39 @@ INSERT INTO activities (
     id, name, distance, moving_time, elapsed_time, total_elevation_gain,
     type, sport_type, start_date, start_date_local, timezone,
     average_speed, max_speed, average_cadence, average_heartrate,
-    max_heartrate, calories, created_at, updated_at
+    max_heartrate, calories, summary_polyline,
+    start_lat, start_lng, end_lat, end_lng, workout_type, created_at, updated_at
 ) VALUES (
     ?, ?, ?, ?, ?, ?,
     ?, ?, ?, ?, ?,
     ?, ?, ?, ?,
-    ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+    ?, ?, ?,
+    ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
 ON CONFLICT(id) DO UPDATE SET
-    name = excluded.name,
-    distance = excluded.distance,
+    name = COALESCE((SELECT o.name FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.name),
+    distance = COALESCE((SELECT o.distance FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.distance),
     moving_time = excluded.moving_time,
     elapsed_time = excluded.elapsed_time,
     total_elevation_gain = excluded.total_elevation_gain,
-    type = excluded.type,
-    sport_type = excluded.sport_type,
+    type = COALESCE((SELECT o.type FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.type),
+    sport_type = COALESCE((SELECT o.type FROM activity_overrides o WHERE o.activity_id = excluded.id), excluded.sport_type),
     start_date = excluded.start_date,
     start_date_local = excluded.start_date_local,
     timezone = excluded.timezone,
-    average_speed = excluded.average_speed,
+    average_speed = COALESCE((SELECT o.distance / excluded.moving_time FROM activity_overrides o
+                              WHERE o.activity_id = excluded.id AND excluded.moving_time > 0), excluded.average_speed),
     max_speed = excluded.max_speed,
     average_cadence = excluded.average_cadence,
     average_heartrate = excluded.average_heartrate,
     max_heartrate = excluded.max_heartrate,
     calories = excluded.calories,
+    summary_polyline = excluded.summary_polyline,
+    start_lat = excluded.start_lat,
+    start_lng = excluded.start_lng,
+    end_lat = excluded.end_lat,
+    end_lng = excluded.end_lng,
+    workout_type = excluded.workout_type,
     updated_at = CURRENT_TIMESTAMP;
 
 -- name: GetActivity :one
@@ -44,14 +53,22 @@ WHERE start_date >= ? AND start_date <= ?
 ORDER BY start_date DESC;
 
 -- name: CountActivities :one
-SELECT COUNT(*) FROM activities;
+SELECT COUNT(*) FROM activities
+WHERE id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: CountActivitiesByType :one
-SELECT COUNT(*) FROM activities WHERE type = ?;
+SELECT COUNT(*) FROM activities WHERE type = ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetLatestActivityDate :one
 SELECT MAX(start_date) as latest_date FROM activities;
 
+-- name: GetLatestActivityTimezone :one
+SELECT timezone FROM activities
+WHERE timezone IS NOT NULL AND timezone != ''
+ORDER BY start_date DESC
+LIMIT 1;
+
 -- name: GetOldestActivityDate :one
 SELECT MIN(start_date) as oldest_date FROM activities;
 
@@ -68,32 +85,40 @@ SELECT * FROM activities ORDER BY start_date ASC LIMIT 1;
 SELECT * FROM activities ORDER BY start_date DESC LIMIT 1;
 
 -- name: GetActivityTypeSummary :many
-SELECT type, COUNT(*) as count FROM activities WHERE type IS NOT NULL GROUP BY type ORDER BY count DESC;
+SELECT type, COUNT(*) as count FROM activities
+WHERE type IS NOT NULL
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY type ORDER BY count DESC;
 
 -- name: CountActivitiesByTypeInRange :one
-SELECT COUNT(*) FROM activities WHERE type = ? AND start_date >= ? AND start_date <= ?;
+SELECT COUNT(*) FROM activities WHERE type = ? AND start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetActivitiesByTypeAndDateRange :many
 SELECT * FROM activities WHERE type = ? AND start_date >= ? AND start_date <= ? ORDER BY start_date DESC;
 
 -- name: GetActivityCountsByMonth :many
 SELECT 
-    strftime('%Y-%m', start_date) as month,
+    strftime('%Y-%m', substr(start_date, 1, 19)) as month,
     type,
     COUNT(*) as count
 FROM activities 
 WHERE start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-%m', start_date), type
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-%m', substr(start_date, 1, 19)), type
 ORDER BY month DESC, count DESC;
 
 -- name: GetActivityCountsByWeek :many
 SELECT 
-    strftime('%Y-W%W', start_date) as week,
+    strftime('%Y-W%W', substr(start_date, 1, 19)) as week,
     type,
     COUNT(*) as count
 FROM activities 
 WHERE start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-W%W', start_date), type
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-W%W', substr(start_date, 1, 19)), type
 ORDER BY week DESC, count DESC;
 
 -- Metrics aggregation queries
@@ -106,7 +131,8 @@ SELECT
     COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE calories IS NOT NULL AND calories > 0;
+WHERE calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetCaloriesSummaryByType :one
 SELECT 
@@ -116,7 +142,8 @@ SELECT
     COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND calories IS NOT NULL AND calories > 0;
+WHERE type = ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetCaloriesSummaryInRange :one
 SELECT 
@@ -126,7 +153,8 @@ SELECT
     COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0;
+WHERE start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetCaloriesSummaryByTypeInRange :one
 SELECT 
@@ -136,7 +164,8 @@ SELECT
     COALESCE(MAX(calories), 0) as max_calories,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0;
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetHeartrateSummary :one
 SELECT 
@@ -146,7 +175,8 @@ SELECT
     COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE average_heartrate IS NOT NULL AND average_heartrate > 0;
+WHERE average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetHeartrateSummaryByType :one
 SELECT 
@@ -156,7 +186,8 @@ SELECT
     COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND average_heartrate IS NOT NULL AND average_heartrate > 0;
+WHERE type = ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetHeartrateSummaryInRange :one
 SELECT 
@@ -166,7 +197,8 @@ SELECT
     COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0;
+WHERE start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetHeartrateSummaryByTypeInRange :one
 SELECT 
@@ -176,7 +208,8 @@ SELECT
     COALESCE(MAX(max_heartrate), 0) as overall_max_heartrate,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0;
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_heartrate IS NOT NULL AND average_heartrate > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetSpeedSummary :one
 SELECT 
@@ -186,7 +219,8 @@ SELECT
     COALESCE(MAX(max_speed), 0) as overall_max_speed,
     COUNT(*) as activity_count
 FROM activities 
-WHERE average_speed IS NOT NULL AND average_speed > 0;
+WHERE average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetSpeedSummaryByType :one
 SELECT 
@@ -196,7 +230,8 @@ SELECT
     COALESCE(MAX(max_speed), 0) as overall_max_speed,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND average_speed IS NOT NULL AND average_speed > 0;
+WHERE type = ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetSpeedSummaryInRange :one
 SELECT 
@@ -206,7 +241,8 @@ SELECT
     COALESCE(MAX(max_speed), 0) as overall_max_speed,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0;
+WHERE start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetSpeedSummaryByTypeInRange :one
 SELECT 
@@ -216,7 +252,8 @@ SELECT
     COALESCE(MAX(max_speed), 0) as overall_max_speed,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0;
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetCadenceSummary :one
 SELECT 
@@ -225,7 +262,8 @@ SELECT
     COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE average_cadence IS NOT NULL AND average_cadence > 0;
+WHERE average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetCadenceSummaryByType :one
 SELECT 
@@ -234,7 +272,8 @@ SELECT
     COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND average_cadence IS NOT NULL AND average_cadence > 0;
+WHERE type = ? AND average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetCadenceSummaryInRange :one
 SELECT 
@@ -243,7 +282,8 @@ SELECT
     COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0;
+WHERE start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetCadenceSummaryByTypeInRange :one
 SELECT 
@@ -252,7 +292,8 @@ SELECT
     COALESCE(MAX(average_cadence), 0) as max_cadence,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0;
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND average_cadence IS NOT NULL AND average_cadence > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDistanceSummary :one
 SELECT 
@@ -262,7 +303,8 @@ SELECT
     COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE distance IS NOT NULL AND distance > 0;
+WHERE distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDistanceSummaryByType :one
 SELECT 
@@ -272,7 +314,8 @@ SELECT
     COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND distance IS NOT NULL AND distance > 0;
+WHERE type = ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDistanceSummaryInRange :one
 SELECT 
@@ -282,7 +325,8 @@ SELECT
     COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0;
+WHERE start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDistanceSummaryByTypeInRange :one
 SELECT 
@@ -292,7 +336,8 @@ SELECT
     COALESCE(MAX(distance), 0) as max_distance,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0;
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetElevationSummary :one
 SELECT 
@@ -302,7 +347,8 @@ SELECT
     COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
     COUNT(*) as activity_count
 FROM activities 
-WHERE total_elevation_gain IS NOT NULL AND total_elevation_gain > 0;
+WHERE total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetElevationSummaryByType :one
 SELECT 
@@ -312,7 +358,8 @@ SELECT
     COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0;
+WHERE type = ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetElevationSummaryInRange :one
 SELECT 
@@ -322,7 +369,8 @@ SELECT
     COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0;
+WHERE start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetElevationSummaryByTypeInRange :one
 SELECT 
@@ -332,7 +380,8 @@ SELECT
     COALESCE(MAX(total_elevation_gain), 0) as max_elevation,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0;
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDurationSummary :one
 SELECT 
@@ -342,7 +391,8 @@ SELECT
     COALESCE(MAX(moving_time), 0) as max_moving_time,
     COUNT(*) as activity_count
 FROM activities 
-WHERE moving_time IS NOT NULL AND moving_time > 0;
+WHERE moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDurationSummaryByType :one
 SELECT 
@@ -352,7 +402,8 @@ SELECT
     COALESCE(MAX(moving_time), 0) as max_moving_time,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND moving_time IS NOT NULL AND moving_time > 0;
+WHERE type = ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDurationSummaryInRange :one
 SELECT 
@@ -362,7 +413,8 @@ SELECT
     COALESCE(MAX(moving_time), 0) as max_moving_time,
     COUNT(*) as activity_count
 FROM activities 
-WHERE start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0;
+WHERE start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetDurationSummaryByTypeInRange :one
 SELECT 
@@ -372,7 +424,8 @@ SELECT
     COALESCE(MAX(moving_time), 0) as max_moving_time,
     COUNT(*) as activity_count
 FROM activities 
-WHERE type = ? AND start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0;
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- Auth config queries
 
@@ -404,10 +457,11 @@ DELETE FROM auth_config WHERE id = 1;
 -- Activity zone queries
 
 -- name: CreateActivityZone :one
-INSERT INTO activity_zones (activity_id, zone_type, sensor_based)
-VALUES (?, ?, ?)
+INSERT INTO activity_zones (activity_id, zone_type, sensor_based, source)
+VALUES (?, ?, ?, ?)
 ON CONFLICT(activity_id, zone_type) DO UPDATE SET
-    sensor_based = excluded.sensor_based
+    sensor_based = excluded.sensor_based,
+    source = excluded.source
 RETURNING id;
 
 -- name: CreateZoneBucket :exec
@@ -435,7 +489,7 @@ LIMIT ?;
 
 -- name: GetActivitiesWithoutZones :many
 SELECT a.id FROM activities a
-LEFT JOIN activity_zones az ON a.id = az.activity_id
+LEFT JOIN activity_zones az ON a.id = az.activity_id AND az.source = 'strava'
 WHERE az.id IS NULL
 ORDER BY a.start_date DESC
 LIMIT ?;
@@ -445,7 +499,7 @@ SELECT COUNT(DISTINCT activity_id) FROM activity_zones;
 
 -- name: CountActivitiesWithoutZones :one
 SELECT COUNT(*) FROM activities a
-LEFT JOIN activity_zones az ON a.id = az.activity_id
+LEFT JOIN activity_zones az ON a.id = az.activity_id AND az.source = 'strava'
 WHERE az.id IS NULL;
 
 -- name: GetHeartRateZoneSummary :many
@@ -457,6 +511,7 @@ SELECT
 FROM zone_buckets zb
 JOIN activity_zones az ON zb.activity_zone_id = az.id
 WHERE az.zone_type = 'heartrate'
+  AND az.activity_id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number;
 
@@ -470,6 +525,7 @@ FROM zone_buckets zb
 JOIN activity_zones az ON zb.activity_zone_id = az.id
 JOIN activities a ON az.activity_id = a.id
 WHERE az.zone_type = 'heartrate' AND a.type = ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number;
 
@@ -484,6 +540,7 @@ JOIN activity_zones az ON zb.activity_zone_id = az.id
 JOIN activities a ON az.activity_id = a.id
 WHERE az.zone_type = 'heartrate' 
   AND a.start_date >= ? AND a.start_date <= ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number;
 
@@ -498,6 +555,7 @@ JOIN activity_zones az ON zb.activity_zone_id = az.id
 JOIN activities a ON az.activity_id = a.id
 WHERE az.zone_type = 'heartrate' AND a.type = ?
   AND a.start_date >= ? AND a.start_date <= ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number;
 
@@ -510,6 +568,7 @@ SELECT
 FROM zone_buckets zb
 JOIN activity_zones az ON zb.activity_zone_id = az.id
 WHERE az.zone_type = 'power'
+  AND az.activity_id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number;
 
@@ -523,6 +582,7 @@ FROM zone_buckets zb
 JOIN activity_zones az ON zb.activity_zone_id = az.id
 JOIN activities a ON az.activity_id = a.id
 WHERE az.zone_type = 'power' AND a.type = ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number;
 
@@ -537,6 +597,7 @@ JOIN activity_zones az ON zb.activity_zone_id = az.id
 JOIN activities a ON az.activity_id = a.id
 WHERE az.zone_type = 'power'
   AND a.start_date >= ? AND a.start_date <= ?
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY zb.zone_number
 ORDER BY zb.zone_number;
 
@@ -551,7 +612,8 @@ SELECT
     COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
     COALESCE(SUM(calories), 0) as total_calories,
     COALESCE(SUM(total_elevation_gain), 0) as total_elevation
-FROM activities;
+FROM activities
+WHERE id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetTrainingSummaryByType :one
 SELECT
@@ -563,7 +625,8 @@ SELECT
     COALESCE(SUM(calories), 0) as total_calories,
     COALESCE(SUM(total_elevation_gain), 0) as total_elevation
 FROM activities
-WHERE type = ?;
+WHERE type = ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetTrainingSummaryInRange :one
 SELECT
@@ -575,7 +638,8 @@ SELECT
     COALESCE(SUM(calories), 0) as total_calories,
     COALESCE(SUM(total_elevation_gain), 0) as total_elevation
 FROM activities
-WHERE start_date >= ? AND start_date <= ?;
+WHERE start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetTrainingSummaryByTypeInRange :one
 SELECT
@@ -587,7 +651,8 @@ SELECT
     COALESCE(SUM(calories), 0) as total_calories,
     COALESCE(SUM(total_elevation_gain), 0) as total_elevation
 FROM activities
-WHERE type = ? AND start_date >= ? AND start_date <= ?;
+WHERE type = ? AND start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- Period comparison queries with detailed metrics
 
@@ -599,6 +664,7 @@ SELECT
     COALESCE(AVG(average_speed), 0) as avg_speed
 FROM activities
 WHERE start_date >= ? AND start_date <= ?;
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 
 -- name: GetPeriodStatsByType :one
 SELECT
@@ -608,15 +674,19 @@ SELECT
     COALESCE(AVG(average_speed), 0) as avg_speed
 FROM activities
 WHERE type = ? AND start_date >= ? AND start_date <= ?;
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 
 -- Consolidated counting queries
 
 -- name: CountActivitiesInRange :one
-SELECT COUNT(*) FROM activities WHERE start_date >= ? AND start_date <= ?;
+SELECT COUNT(*) FROM activities
+WHERE start_date >= ? AND start_date <= ?
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
 
 -- name: GetActivityTypeSummaryInRange :many
 SELECT type, COUNT(*) as count FROM activities
 WHERE start_date >= ? AND start_date <= ? AND type IS NOT NULL
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 GROUP BY type ORDER BY count DESC;
 
 -- Personal records queries
@@ -624,60 +694,70 @@ GROUP BY type ORDER BY count DESC;
 -- name: GetFastestActivity :one
 SELECT * FROM activities
 WHERE average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY average_speed DESC
 LIMIT 1;
 
 -- name: GetFastestActivityByType :one
 SELECT * FROM activities
 WHERE type = ? AND average_speed IS NOT NULL AND average_speed > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY average_speed DESC
 LIMIT 1;
 
 -- name: GetLongestDistanceActivity :one
 SELECT * FROM activities
 WHERE distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY distance DESC
 LIMIT 1;
 
 -- name: GetLongestDistanceActivityByType :one
 SELECT * FROM activities
 WHERE type = ? AND distance IS NOT NULL AND distance > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY distance DESC
 LIMIT 1;
 
 -- name: GetLongestDurationActivity :one
 SELECT * FROM activities
 WHERE moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY moving_time DESC
 LIMIT 1;
 
 -- name: GetLongestDurationActivityByType :one
 SELECT * FROM activities
 WHERE type = ? AND moving_time IS NOT NULL AND moving_time > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY moving_time DESC
 LIMIT 1;
 
 -- name: GetHighestElevationActivity :one
 SELECT * FROM activities
 WHERE total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY total_elevation_gain DESC
 LIMIT 1;
 
 -- name: GetHighestElevationActivityByType :one
 SELECT * FROM activities
 WHERE type = ? AND total_elevation_gain IS NOT NULL AND total_elevation_gain > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY total_elevation_gain DESC
 LIMIT 1;
 
 -- name: GetMostCaloriesActivity :one
 SELECT * FROM activities
 WHERE calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY calories DESC
 LIMIT 1;
 
 -- name: GetMostCaloriesActivityByType :one
 SELECT * FROM activities
 WHERE type = ? AND calories IS NOT NULL AND calories > 0
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
 ORDER BY calories DESC
 LIMIT 1;
 
@@ -685,7 +765,7 @@ LIMIT 1;
 
 -- name: GetWeeklyVolume :many
 SELECT
-    strftime('%Y-W%W', start_date) as week,
+    strftime('%Y-W%W', substr(start_date, 1, 19)) as week,
     COUNT(*) as activity_count,
     COALESCE(SUM(distance), 0) as total_distance,
     COALESCE(SUM(moving_time), 0) as total_duration,
@@ -693,12 +773,13 @@ SELECT
     COALESCE(SUM(total_elevation_gain), 0) as total_elevation
 FROM activities
 WHERE start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-W%W', start_date)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-W%W', substr(start_date, 1, 19))
 ORDER BY week DESC;
 
 -- name: GetWeeklyVolumeByType :many
 SELECT
-    strftime('%Y-W%W', start_date) as week,
+    strftime('%Y-W%W', substr(start_date, 1, 19)) as week,
     COUNT(*) as activity_count,
     COALESCE(SUM(distance), 0) as total_distance,
     COALESCE(SUM(moving_time), 0) as total_duration,
@@ -706,7 +787,8 @@ SELECT
     COALESCE(SUM(total_elevation_gain), 0) as total_elevation
 FROM activities
 WHERE type = ? AND start_date >= ? AND start_date <= ?
-GROUP BY strftime('%Y-W%W', start_date)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY strftime('%Y-W%W', substr(start_date, 1, 19))
 ORDER BY week DESC;
 
 -- Flexible activity search with sorting
@@ -716,6 +798,7 @@ SELECT * FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
 ORDER BY start_date DESC
 LIMIT ?;
 
@@ -724,6 +807,7 @@ SELECT * FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND distance IS NOT NULL
 ORDER BY distance DESC
 LIMIT ?;
@@ -733,6 +817,7 @@ SELECT * FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND moving_time IS NOT NULL
 ORDER BY moving_time DESC
 LIMIT ?;
@@ -742,6 +827,7 @@ SELECT * FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND average_speed IS NOT NULL
 ORDER BY average_speed DESC
 LIMIT ?;
@@ -751,6 +837,1018 @@ SELECT * FROM activities
 WHERE (? IS NULL OR type = ?)
   AND (? IS NULL OR start_date >= ?)
   AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
   AND total_elevation_gain IS NOT NULL
 ORDER BY total_elevation_gain DESC
 LIMIT ?;
+
+-- Route queries (activities with a stored summary polyline)
+
+-- name: SearchActivitiesWithRoute :many
+SELECT * FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND summary_polyline IS NOT NULL AND summary_polyline != ''
+ORDER BY start_date DESC
+LIMIT ?;
+
+-- name: CountActivitiesWithoutRouteData :one
+SELECT COUNT(*) FROM activities WHERE summary_polyline IS NULL;
+
+-- Activity stream queries
+
+-- name: UpsertActivityStreams :exec
+INSERT INTO activity_streams (
+    activity_id, point_count, time_data, distance_data, latlng_data, altitude_data,
+    velocity_data, heartrate_data, cadence_data, watts_data, grade_data, moving_data
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    point_count = excluded.point_count,
+    time_data = excluded.time_data,
+    distance_data = excluded.distance_data,
+    latlng_data = excluded.latlng_data,
+    altitude_data = excluded.altitude_data,
+    velocity_data = excluded.velocity_data,
+    heartrate_data = excluded.heartrate_data,
+    cadence_data = excluded.cadence_data,
+    watts_data = excluded.watts_data,
+    grade_data = excluded.grade_data,
+    moving_data = excluded.moving_data,
+    fetched_at = CURRENT_TIMESTAMP;
+
+-- name: GetActivityStreams :one
+SELECT * FROM activity_streams WHERE activity_id = ?;
+
+-- name: GetActivitiesWithoutStreams :many
+SELECT a.id FROM activities a
+LEFT JOIN activity_streams s ON a.id = s.activity_id
+WHERE s.activity_id IS NULL
+ORDER BY a.start_date DESC
+LIMIT ?;
+
+-- name: CountActivitiesWithoutStreams :one
+SELECT COUNT(*) FROM activities a
+LEFT JOIN activity_streams s ON a.id = s.activity_id
+WHERE s.activity_id IS NULL;
+
+-- Exploration queries (GPS coverage across all activities)
+-- ListLatlngStreams pages through stored GPS streams by activity ID
+
+-- name: ListLatlngStreams :many
+SELECT s.activity_id, a.type, a.start_date, s.latlng_data
+FROM activity_streams s
+JOIN activities a ON a.id = s.activity_id
+WHERE s.latlng_data IS NOT NULL
+  AND s.activity_id > ?
+  AND (? IS NULL OR a.type = ?)
+  AND (? IS NULL OR a.start_date >= ?)
+  AND (? IS NULL OR a.start_date <= ?)
+ORDER BY s.activity_id
+LIMIT ?;
+
+-- name: ListPolylinesWithoutStreams :many
+SELECT a.id, a.type, a.start_date, a.summary_polyline
+FROM activities a
+LEFT JOIN activity_streams s ON s.activity_id = a.id
+WHERE s.latlng_data IS NULL
+  AND a.summary_polyline IS NOT NULL AND a.summary_polyline != ''
+  AND (? IS NULL OR a.type = ?)
+  AND (? IS NULL OR a.start_date >= ?)
+  AND (? IS NULL OR a.start_date <= ?)
+ORDER BY a.id;
+
+-- Detected route queries
+
+-- name: CreateRoutesDetected :one
+INSERT INTO routes_detected (
+    name, activity_type, representative_activity_id, polyline, distance,
+    start_lat, start_lng, end_lat, end_lng
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: CreateRouteEffort :exec
+INSERT INTO route_efforts (activity_id, route_id, shape_distance)
+VALUES (?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    route_id = excluded.route_id,
+    shape_distance = excluded.shape_distance;
+
+-- name: ListRoutesDetected :many
+SELECT * FROM routes_detected ORDER BY id;
+
+-- name: GetRoutesDetected :one
+SELECT * FROM routes_detected WHERE id = ?;
+
+-- name: GetRouteEffort :one
+SELECT * FROM route_efforts WHERE activity_id = ?;
+
+-- name: ListActivitiesWithoutRouteEffort :many
+SELECT a.* FROM activities a
+LEFT JOIN route_efforts re ON re.activity_id = a.id
+WHERE re.activity_id IS NULL
+  AND a.summary_polyline IS NOT NULL AND a.summary_polyline != ''
+ORDER BY a.start_date;
+
+-- name: GetRouteEffortActivities :many
+SELECT a.* FROM activities a
+JOIN route_efforts re ON re.activity_id = a.id
+WHERE re.route_id = ?
+ORDER BY a.start_date;
+
+-- name: ListRecurringRoutes :many
+SELECT r.id, r.name, r.activity_type, r.distance, COUNT(re.activity_id) AS effort_count
+FROM routes_detected r
+JOIN route_efforts re ON re.route_id = r.id
+WHERE (? IS NULL OR r.activity_type = ?)
+GROUP BY r.id
+HAVING COUNT(re.activity_id) >= 2
+ORDER BY effort_count DESC, r.id
+LIMIT ?;
+
+-- Local zone queries
+
+-- name: UpsertZoneSetting :exec
+INSERT INTO zone_settings (zone_type, boundaries, source, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(zone_type) DO UPDATE SET
+    boundaries = excluded.boundaries,
+    source = excluded.source,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListZoneSettings :many
+SELECT * FROM zone_settings ORDER BY zone_type;
+
+-- name: DeleteZoneSettingsBySource :exec
+DELETE FROM zone_settings WHERE source = ?;
+
+-- name: ListActivitiesNeedingLocalZones :many
+SELECT s.activity_id FROM activity_streams s
+JOIN activities a ON a.id = s.activity_id
+LEFT JOIN activity_zones az ON az.activity_id = s.activity_id AND az.zone_type = ?
+WHERE az.id IS NULL
+  AND s.time_data IS NOT NULL
+  AND CASE ?
+      WHEN 'heartrate' THEN s.heartrate_data
+      WHEN 'power' THEN s.watts_data
+      WHEN 'pace' THEN CASE WHEN a.type IN ('Run', 'TrailRun', 'VirtualRun') THEN s.velocity_data END
+  END IS NOT NULL
+ORDER BY s.activity_id DESC
+LIMIT ?;
+
+-- name: DeleteLocalZoneBuckets :exec
+DELETE FROM zone_buckets WHERE activity_zone_id IN (
+    SELECT id FROM activity_zones WHERE source = 'local' AND zone_type = ?
+);
+
+-- name: DeleteLocalActivityZones :exec
+DELETE FROM activity_zones WHERE source = 'local' AND zone_type = ?;
+
+-- name: GetZoneSourceCounts :many
+SELECT source, COUNT(*) AS activity_count
+FROM activity_zones
+WHERE zone_type = ?
+  AND EXISTS (SELECT 1 FROM zone_buckets zb WHERE zb.activity_zone_id = activity_zones.id)
+GROUP BY source
+ORDER BY source;
+
+-- Pace zone queries
+
+-- name: GetPaceZoneSummary :many
+SELECT
+    zb.zone_number,
+    SUM(zb.time_seconds) as total_time,
+    AVG(zb.time_seconds) as avg_time,
+    COUNT(*) as activity_count
+FROM zone_buckets zb
+JOIN activity_zones az ON zb.activity_zone_id = az.id
+JOIN activities a ON az.activity_id = a.id
+WHERE az.zone_type = 'pace'
+  AND (? IS NULL OR a.type = ?)
+  AND (? IS NULL OR a.start_date >= ?)
+  AND (? IS NULL OR a.start_date <= ?)
+  AND a.id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY zb.zone_number
+ORDER BY zb.zone_number;
+
+-- Threshold detection queries
+
+-- name: ListActivitiesNeedingThresholdAnalysis :many
+SELECT a.id, a.type, a.start_date FROM activities a
+JOIN activity_streams s ON s.activity_id = a.id
+LEFT JOIN threshold_efforts te ON te.activity_id = a.id
+WHERE te.activity_id IS NULL
+  AND s.time_data IS NOT NULL
+ORDER BY a.start_date DESC
+LIMIT ?;
+
+-- name: UpsertThresholdEffort :exec
+INSERT INTO threshold_efforts (activity_id, sport, start_date, lthr, ftp, threshold_speed, analyzed_at)
+VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET
+    sport = excluded.sport,
+    start_date = excluded.start_date,
+    lthr = excluded.lthr,
+    ftp = excluded.ftp,
+    threshold_speed = excluded.threshold_speed,
+    analyzed_at = CURRENT_TIMESTAMP;
+
+-- name: ListThresholdEfforts :many
+SELECT * FROM threshold_efforts
+WHERE sport = ? AND start_date IS NOT NULL
+ORDER BY start_date, activity_id;
+
+-- name: GetLatestThreshold :one
+SELECT * FROM threshold_history
+WHERE metric = ? AND sport = ?
+ORDER BY detected_on DESC, id DESC
+LIMIT 1;
+
+-- name: CreateThresholdHistory :exec
+INSERT INTO threshold_history (metric, sport, value, previous_value, activity_id, detected_on)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListThresholdHistory :many
+SELECT * FROM threshold_history ORDER BY detected_on, id;
+
+-- Workout classification queries
+
+-- ListActivitiesNeedingClassification pages by ID through activities never
+-- classified, or updated or given streams since they were
+
+-- name: ListActivitiesNeedingClassification :many
+SELECT a.id, a.type, a.workout_type, a.moving_time, a.average_speed, a.average_heartrate
+FROM activities a
+LEFT JOIN activity_streams s ON s.activity_id = a.id
+WHERE a.id > ?
+  AND (a.workout_classified_at IS NULL
+       OR a.workout_classified_at < a.updated_at
+       OR a.workout_classified_at < s.fetched_at)
+ORDER BY a.id
+LIMIT ?;
+
+-- name: ListWorkoutNorms :many
+SELECT type, moving_time, average_speed, max_heartrate FROM activities
+WHERE type IS NOT NULL AND moving_time > 0;
+
+-- name: UpdateWorkoutClassification :exec
+UPDATE activities
+SET workout_category = ?, workout_confidence = ?, workout_classified_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: CountActivitiesFiltered :one
+SELECT COUNT(*) FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
+
+-- name: GetTrainingSummaryFiltered :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(AVG(average_speed), 0) as avg_speed,
+    COALESCE(AVG(average_heartrate), 0) as avg_heartrate,
+    COALESCE(SUM(calories), 0) as total_calories,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
+  AND id NOT IN (SELECT activity_id FROM excluded_activities);
+
+-- name: GetWorkoutCategoryCounts :many
+SELECT type, workout_category, COUNT(*) as count FROM activities
+WHERE (? IS NULL OR type = ?)
+  AND (? IS NULL OR start_date >= ?)
+  AND (? IS NULL OR start_date <= ?)
+  AND (? IS NULL OR workout_category = ?)
+  AND workout_category IS NOT NULL
+  AND id NOT IN (SELECT activity_id FROM excluded_activities)
+GROUP BY type, workout_category
+ORDER BY count DESC;
+
+-- Race calendar queries
+
+-- name: CreateRace :one
+INSERT INTO races (name, race_date, distance, activity_type, priority, goal_time, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetRace :one
+SELECT * FROM races WHERE id = ?;
+
+-- name: ListRaces :many
+SELECT * FROM races ORDER BY race_date, id;
+
+-- name: ListUpcomingRaces :many
+SELECT * FROM races
+WHERE race_date >= ?
+ORDER BY race_date, priority, id;
+
+-- name: DeleteRace :execrows
+DELETE FROM races WHERE id = ?;
+
+-- Data quality queries
+
+-- ListActivitiesNeedingQualityCheck pages by ID through activities never
+-- checked, or updated or given streams since they were
+
+-- name: ListActivitiesNeedingQualityCheck :many
+SELECT a.id, a.type, a.distance, a.moving_time, a.start_date, a.average_speed, a.max_speed, a.max_heartrate
+FROM activities a
+LEFT JOIN activity_quality_checks c ON c.activity_id = a.id
+LEFT JOIN activity_streams s ON s.activity_id = a.id
+WHERE a.id > ?
+  AND (c.checked_at IS NULL
+       OR c.checked_at < a.updated_at
+       OR c.checked_at < s.fetched_at)
+ORDER BY a.id
+LIMIT ?;
+
+-- name: ListActivitiesStartedBetween :many
+SELECT id, distance, moving_time, start_date FROM activities
+WHERE type = ? AND start_date >= ? AND start_date <= ? AND id <> ?
+ORDER BY id;
+
+-- name: DeleteActivityFlags :exec
+DELETE FROM activity_flags WHERE activity_id = ?;
+
+-- name: CreateActivityFlag :exec
+INSERT INTO activity_flags (activity_id, code, severity, detail, related_activity_id)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: MarkActivityQualityChecked :exec
+INSERT INTO activity_quality_checks (activity_id, checked_at)
+VALUES (?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET checked_at = CURRENT_TIMESTAMP;
+
+-- name: ListActivityFlags :many
+SELECT f.id, f.activity_id, f.code, f.severity, f.detail, f.related_activity_id, f.detected_at,
+       a.name, a.type, a.start_date, a.distance, a.average_speed
+FROM activity_flags f
+JOIN activities a ON a.id = f.activity_id
+WHERE (? IS NULL OR f.severity = ?)
+  AND (? IS NULL OR f.code = ?)
+  AND (? IS NULL OR a.type = ?)
+ORDER BY a.start_date DESC, f.id
+LIMIT ?;
+
+-- name: CountActivityFlagsByCode :many
+SELECT code, severity, COUNT(*) AS count FROM activity_flags
+GROUP BY code, severity
+ORDER BY severity, count DESC, code;
+
+-- name: CountActivitiesQualityChecked :one
+SELECT COUNT(*) FROM activity_quality_checks;
+
+-- name: CountExcludedActivities :one
+SELECT COUNT(*) FROM excluded_activities;
+
+-- Activity override queries
+
+-- name: GetActivityOverride :one
+SELECT * FROM activity_overrides WHERE activity_id = ?;
+
+-- name: UpsertActivityCorrection :exec
+INSERT INTO activity_overrides (
+    activity_id, name, type, distance,
+    original_name, original_type, original_sport_type, original_distance, original_average_speed
+)
+SELECT id, ?, ?, ?, name, type, sport_type, distance, average_speed
+FROM activities WHERE id = ?
+ON CONFLICT(activity_id) DO UPDATE SET
+    original_name = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                         THEN excluded.original_name ELSE activity_overrides.original_name END,
+    original_type = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                         THEN excluded.original_type ELSE activity_overrides.original_type END,
+    original_sport_type = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                               THEN excluded.original_sport_type ELSE activity_overrides.original_sport_type END,
+    original_distance = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                             THEN excluded.original_distance ELSE activity_overrides.original_distance END,
+    original_average_speed = CASE WHEN activity_overrides.name IS NULL AND activity_overrides.type IS NULL AND activity_overrides.distance IS NULL
+                                  THEN excluded.original_average_speed ELSE activity_overrides.original_average_speed END,
+    name = COALESCE(excluded.name, activity_overrides.name),
+    type = COALESCE(excluded.type, activity_overrides.type),
+    distance = COALESCE(excluded.distance, activity_overrides.distance),
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: ApplyActivityOverride :exec
+UPDATE activities SET
+    name = COALESCE(o.name, activities.name),
+    type = COALESCE(o.type, activities.type),
+    sport_type = COALESCE(o.type, activities.sport_type),
+    distance = COALESCE(o.distance, activities.distance),
+    average_speed = CASE WHEN o.distance IS NOT NULL AND activities.moving_time > 0
+                         THEN o.distance / activities.moving_time ELSE activities.average_speed END,
+    updated_at = CURRENT_TIMESTAMP
+FROM activity_overrides o
+WHERE o.activity_id = activities.id AND activities.id = ?;
+
+-- name: ResetActivityCorrections :exec
+UPDATE activities SET
+    name = CASE WHEN o.name IS NOT NULL THEN o.original_name ELSE activities.name END,
+    type = CASE WHEN o.type IS NOT NULL THEN o.original_type ELSE activities.type END,
+    sport_type = CASE WHEN o.type IS NOT NULL THEN o.original_sport_type ELSE activities.sport_type END,
+    distance = CASE WHEN o.distance IS NOT NULL THEN o.original_distance ELSE activities.distance END,
+    average_speed = CASE WHEN o.distance IS NOT NULL THEN o.original_average_speed ELSE activities.average_speed END,
+    updated_at = CURRENT_TIMESTAMP
+FROM activity_overrides o
+WHERE o.activity_id = activities.id AND activities.id = ?;
+
+-- name: ClearActivityCorrections :exec
+UPDATE activity_overrides SET
+    name = NULL, type = NULL, distance = NULL,
+    original_name = NULL, original_type = NULL, original_sport_type = NULL,
+    original_distance = NULL, original_average_speed = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE activity_id = ?;
+
+-- name: SetActivityCounted :exec
+INSERT INTO activity_overrides (activity_id, counted, reason)
+VALUES (?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    counted = excluded.counted,
+    reason = excluded.reason,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteEmptyActivityOverride :exec
+DELETE FROM activity_overrides
+WHERE activity_id = ? AND name IS NULL AND type IS NULL AND distance IS NULL AND counted IS NULL;
+
+-- name: IsActivityExcluded :one
+SELECT EXISTS(SELECT 1 FROM excluded_activities WHERE activity_id = ?) AS excluded;
+
+-- Social queries
+
+-- name: UpsertActivitySocialCounts :exec
+INSERT INTO activity_social (activity_id, kudos_count, comment_count, athlete_count)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    kudos_count = excluded.kudos_count,
+    comment_count = excluded.comment_count,
+    athlete_count = excluded.athlete_count,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetActivitySocialCounts :one
+SELECT kudos_count, comment_count FROM activity_social WHERE activity_id = ?;
+
+-- name: DeleteActivityKudos :exec
+DELETE FROM activity_kudos WHERE activity_id = ?;
+
+-- name: CreateActivityKudo :exec
+INSERT INTO activity_kudos (activity_id, position, athlete_name) VALUES (?, ?, ?);
+
+-- name: DeleteActivityComments :exec
+DELETE FROM activity_comments WHERE activity_id = ?;
+
+-- name: CreateActivityComment :exec
+INSERT OR REPLACE INTO activity_comments (id, activity_id, athlete_name, text, created_at) VALUES (?, ?, ?, ?, ?);
+
+-- name: MarkSocialListsSynced :exec
+UPDATE activity_social
+SET lists_kudos_count = ?, lists_comment_count = ?, lists_synced_at = CURRENT_TIMESTAMP
+WHERE activity_id = ?;
+
+-- name: CountSocialListsSynced :one
+SELECT COUNT(*) FROM activity_social WHERE lists_synced_at IS NOT NULL;
+
+-- name: GetSocialSummary :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments,
+    COALESCE(SUM(CASE WHEN s.kudos_count > 0 THEN 1 ELSE 0 END), 0) as with_kudos,
+    COALESCE(SUM(CASE WHEN s.athlete_count > 1 THEN 1 ELSE 0 END), 0) as group_activities
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?;
+
+-- Times are stored as Go formats them, which SQLite date functions only read
+-- without the zone suffix
+-- name: GetSocialByMonth :many
+SELECT
+    strftime('%Y-%m', substr(a.start_date_local, 1, 19)) as month,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY strftime('%Y-%m', substr(a.start_date_local, 1, 19))
+ORDER BY month;
+
+-- name: GetSocialByType :many
+SELECT
+    a.type,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY a.type
+ORDER BY total_kudos DESC;
+
+-- name: GetSocialByHour :many
+SELECT
+    strftime('%H', substr(a.start_date_local, 1, 19)) as hour,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY strftime('%H', substr(a.start_date_local, 1, 19))
+ORDER BY hour;
+
+-- name: GetSocialByWeekday :many
+SELECT
+    strftime('%w', substr(a.start_date_local, 1, 19)) as weekday,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(s.kudos_count), 0) as total_kudos,
+    COALESCE(SUM(s.comment_count), 0) as total_comments
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ?
+GROUP BY strftime('%w', substr(a.start_date_local, 1, 19))
+ORDER BY weekday;
+
+-- name: GetMostKudoedActivities :many
+SELECT a.id, a.name, a.type, a.start_date_local, a.distance, s.kudos_count, s.comment_count
+FROM activities a
+JOIN activity_social s ON s.activity_id = a.id
+WHERE a.start_date >= ? AND a.start_date <= ? AND (s.kudos_count > 0 OR s.comment_count > 0)
+ORDER BY s.kudos_count DESC, s.comment_count DESC, a.start_date DESC
+LIMIT ?;
+
+-- name: GetTopSupporters :many
+SELECT athlete_name, SUM(kudos) as kudos, SUM(comments) as comments
+FROM (
+    SELECT k.athlete_name, 1 as kudos, 0 as comments
+    FROM activity_kudos k
+    JOIN activities a ON a.id = k.activity_id
+    WHERE a.start_date >= ? AND a.start_date <= ?
+    UNION ALL
+    SELECT c.athlete_name, 0 as kudos, 1 as comments
+    FROM activity_comments c
+    JOIN activities a ON a.id = c.activity_id
+    WHERE a.start_date >= ? AND a.start_date <= ?
+)
+GROUP BY athlete_name
+ORDER BY SUM(kudos) + SUM(comments) DESC, athlete_name
+LIMIT ?;
+
+-- name: CountActivitiesWithoutSocialCounts :one
+SELECT COUNT(*) FROM activities
+WHERE id NOT IN (SELECT activity_id FROM activity_social);
+
+-- Club queries
+
+-- name: UpsertClub :exec
+INSERT INTO clubs (id, name, sport_type, city, state, country, private, member_count, url, synced_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    name = excluded.name,
+    sport_type = excluded.sport_type,
+    city = excluded.city,
+    state = excluded.state,
+    country = excluded.country,
+    private = excluded.private,
+    member_count = excluded.member_count,
+    url = excluded.url,
+    synced_at = excluded.synced_at;
+
+-- name: DeleteStaleClubActivities :exec
+DELETE FROM club_activities
+WHERE club_id IN (SELECT id FROM clubs WHERE synced_at < ?);
+
+-- name: DeleteStaleClubs :execrows
+DELETE FROM clubs WHERE synced_at < ?;
+
+-- name: ListClubs :many
+SELECT * FROM clubs ORDER BY name;
+
+-- name: GetClub :one
+SELECT * FROM clubs WHERE id = ? LIMIT 1;
+
+-- name: CountClubActivities :one
+SELECT COUNT(*) FROM club_activities WHERE club_id = ?;
+
+-- name: CreateClubActivity :execrows
+INSERT OR IGNORE INTO club_activities (
+    club_id, fingerprint, athlete_name, name, type, sport_type, distance,
+    moving_time, elapsed_time, total_elevation_gain, first_seen_at, backfill
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListClubActivityCounts :many
+SELECT
+    club_id,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(CASE WHEN backfill = 0 AND first_seen_at >= ? THEN 1 ELSE 0 END), 0) as recent_count
+FROM club_activities
+GROUP BY club_id;
+
+-- Times are stored as Go formats them, which SQLite date functions only read
+-- without the zone suffix
+-- name: GetClubWeeklyTotals :many
+SELECT
+    date(substr(first_seen_at, 1, 19), 'weekday 0', '-6 days') as week_start,
+    COUNT(*) as activity_count,
+    COUNT(DISTINCT athlete_name) as athletes,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM club_activities
+WHERE club_id = ? AND backfill = 0 AND first_seen_at >= ?
+GROUP BY date(substr(first_seen_at, 1, 19), 'weekday 0', '-6 days')
+ORDER BY week_start;
+
+-- name: GetClubLeaderboard :many
+SELECT
+    athlete_name,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time,
+    COALESCE(SUM(total_elevation_gain), 0) as total_elevation
+FROM club_activities
+WHERE club_id = ? AND first_seen_at >= ?
+GROUP BY athlete_name
+ORDER BY SUM(distance) DESC, athlete_name
+LIMIT ?;
+
+-- name: GetClubTypeTotals :many
+SELECT
+    type,
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time
+FROM club_activities
+WHERE club_id = ? AND first_seen_at >= ?
+GROUP BY type
+ORDER BY activity_count DESC;
+
+-- name: CountClubBackfillActivities :one
+SELECT COUNT(*) FROM club_activities
+WHERE club_id = ? AND backfill = 1 AND first_seen_at >= ?;
+
+-- Route library queries
+
+-- name: UpsertSavedRoute :exec
+INSERT INTO saved_routes (
+    id, name, description, type, sub_type, distance, elevation_gain,
+    estimated_moving_time, polyline, private, starred, created_at, updated_at, synced_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    name = excluded.name,
+    description = excluded.description,
+    type = excluded.type,
+    sub_type = excluded.sub_type,
+    distance = excluded.distance,
+    elevation_gain = excluded.elevation_gain,
+    estimated_moving_time = excluded.estimated_moving_time,
+    polyline = excluded.polyline,
+    private = excluded.private,
+    starred = excluded.starred,
+    created_at = excluded.created_at,
+    updated_at = excluded.updated_at,
+    synced_at = excluded.synced_at;
+
+-- name: ListSavedRoutesNeedingGPX :many
+SELECT id, updated_at FROM saved_routes
+WHERE gpx IS NULL OR gpx_updated_at IS NULL OR gpx_updated_at != updated_at
+ORDER BY id;
+
+-- name: SetSavedRouteGPX :exec
+UPDATE saved_routes SET gpx = ?, gpx_updated_at = ? WHERE id = ?;
+
+-- name: DeleteStaleSavedRoutes :execrows
+DELETE FROM saved_routes WHERE synced_at < ?;
+
+-- name: ListSavedRoutes :many
+SELECT id, name, description, type, sub_type, distance, elevation_gain,
+    estimated_moving_time, polyline, private, starred, created_at, updated_at,
+    gpx IS NOT NULL as has_gpx
+FROM saved_routes
+ORDER BY name;
+
+-- name: GetSavedRouteGPX :one
+SELECT id, name, gpx FROM saved_routes WHERE id = ? LIMIT 1;
+
+-- name: UpsertStarredSegment :exec
+INSERT INTO starred_segments (
+    id, name, activity_type, distance, average_grade, maximum_grade, elevation_high,
+    elevation_low, climb_category, city, state, country, private, start_lat, start_lng,
+    end_lat, end_lng, pr_elapsed_time, pr_date, pr_activity_id, effort_count, synced_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    name = excluded.name,
+    activity_type = excluded.activity_type,
+    distance = excluded.distance,
+    average_grade = excluded.average_grade,
+    maximum_grade = excluded.maximum_grade,
+    elevation_high = excluded.elevation_high,
+    elevation_low = excluded.elevation_low,
+    climb_category = excluded.climb_category,
+    city = excluded.city,
+    state = excluded.state,
+    country = excluded.country,
+    private = excluded.private,
+    start_lat = excluded.start_lat,
+    start_lng = excluded.start_lng,
+    end_lat = excluded.end_lat,
+    end_lng = excluded.end_lng,
+    pr_elapsed_time = excluded.pr_elapsed_time,
+    pr_date = excluded.pr_date,
+    pr_activity_id = excluded.pr_activity_id,
+    effort_count = excluded.effort_count,
+    synced_at = excluded.synced_at;
+
+-- name: DeleteStaleStarredSegments :execrows
+DELETE FROM starred_segments WHERE synced_at < ?;
+
+-- name: ListStarredSegments :many
+SELECT * FROM starred_segments ORDER BY name;
+
+-- name: ListActivitiesForRouteMatch :many
+SELECT * FROM activities
+WHERE type = ? AND distance >= ? AND distance <= ?
+  AND summary_polyline IS NOT NULL AND summary_polyline != ''
+ORDER BY start_date;
+
+-- Sync integrity queries
+
+-- name: RecordSyncFailure :exec
+INSERT INTO sync_failures (
+    activity_id, activity_name, start_date, error, first_failed_at, last_failed_at
+) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    activity_name = excluded.activity_name,
+    start_date = excluded.start_date,
+    error = excluded.error,
+    attempts = sync_failures.attempts + 1,
+    last_failed_at = excluded.last_failed_at;
+
+-- name: ClearSyncFailure :exec
+DELETE FROM sync_failures WHERE activity_id = ?;
+
+-- name: ListSyncFailures :many
+SELECT * FROM sync_failures ORDER BY last_failed_at DESC;
+
+-- name: UpsertSyncIntegrity :exec
+INSERT INTO sync_integrity (
+    sport, period, window_start, strava_count, local_count, strava_distance, local_distance,
+    strava_moving_time, local_moving_time, checked_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(sport, period) DO UPDATE SET
+    window_start = excluded.window_start,
+    strava_count = excluded.strava_count,
+    local_count = excluded.local_count,
+    strava_distance = excluded.strava_distance,
+    local_distance = excluded.local_distance,
+    strava_moving_time = excluded.strava_moving_time,
+    local_moving_time = excluded.local_moving_time,
+    checked_at = excluded.checked_at;
+
+-- name: SetSyncIntegrityRepaired :exec
+UPDATE sync_integrity SET repaired_missing = ? WHERE sport = ? AND period = ?;
+
+-- name: ListSyncIntegrity :many
+SELECT * FROM sync_integrity ORDER BY sport, period;
+
+-- name: CreateSyncRepair :exec
+INSERT INTO sync_repairs (
+    reason, window_start, window_end, fetched, added, failed, error, repaired_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: AddSyncRepairProgress :exec
+UPDATE sync_repairs
+SET fetched = fetched + ?, added = added + ?, failed = failed + ?
+WHERE id = (
+    SELECT id FROM sync_repairs
+    WHERE window_start IS ? AND window_end = ?
+    ORDER BY id DESC
+    LIMIT 1
+);
+
+-- name: SetSyncRepairError :exec
+UPDATE sync_repairs SET error = ?
+WHERE id = (
+    SELECT id FROM sync_repairs
+    WHERE window_start IS ? AND window_end = ?
+    ORDER BY id DESC
+    LIMIT 1
+);
+
+-- name: ListSyncRepairs :many
+SELECT * FROM sync_repairs ORDER BY repaired_at DESC, id DESC LIMIT ?;
+
+-- Totals of every stored activity by the type Strava gave it, excluded
+-- activities included, to compare with the athlete's stats on Strava
+
+-- name: GetStoredTotalsByType :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time
+FROM (
+    SELECT
+        COALESCE(o.original_type, a.type) as type,
+        COALESCE(o.original_distance, a.distance) as distance,
+        a.moving_time,
+        a.start_date
+    FROM activities a
+    LEFT JOIN activity_overrides o ON o.activity_id = a.id
+) AS stored
+WHERE type = ?;
+
+-- name: GetStoredTotalsByTypeInRange :one
+SELECT
+    COUNT(*) as activity_count,
+    COALESCE(SUM(distance), 0) as total_distance,
+    COALESCE(SUM(moving_time), 0) as total_moving_time
+FROM (
+    SELECT
+        COALESCE(o.original_type, a.type) as type,
+        COALESCE(o.original_distance, a.distance) as distance,
+        a.moving_time,
+        a.start_date
+    FROM activities a
+    LEFT JOIN activity_overrides o ON o.activity_id = a.id
+) AS stored
+WHERE type = ? AND start_date >= ? AND start_date <= ?;
+
+-- Sync job queries
+
+-- name: EnqueueSyncJob :exec
+INSERT INTO sync_jobs (
+    kind, target, after_time, before_time, priority, max_attempts, run_after, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(kind, target, IFNULL(after_time, ''), IFNULL(before_time, '')) DO UPDATE SET
+    priority = excluded.priority,
+    status = 'pending',
+    attempts = 0,
+    max_attempts = excluded.max_attempts,
+    last_error = NULL,
+    run_after = excluded.run_after,
+    updated_at = excluded.updated_at,
+    finished_at = NULL
+WHERE sync_jobs.status IN ('done', 'dead');
+
+-- name: EnqueueStreamSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'streams', a.id, ?, ?, ?, ?, ?
+FROM activities a
+WHERE NOT EXISTS (SELECT 1 FROM activity_streams s WHERE s.activity_id = a.id)
+  AND NOT EXISTS (SELECT 1 FROM sync_jobs j WHERE j.kind = 'streams' AND j.target = a.id)
+ORDER BY a.start_date DESC;
+
+-- name: EnqueueZoneSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'zones', a.id, ?, ?, ?, ?, ?
+FROM activities a
+WHERE NOT EXISTS (SELECT 1 FROM activity_zones az WHERE az.activity_id = a.id AND az.source = 'strava')
+  AND NOT EXISTS (SELECT 1 FROM sync_jobs j WHERE j.kind = 'zones' AND j.target = a.id)
+ORDER BY a.start_date DESC;
+
+-- name: EnqueueLapSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'laps', a.id, ?, ?, ?, ?, ?
+FROM activities a
+WHERE NOT EXISTS (SELECT 1 FROM activity_laps l WHERE l.activity_id = a.id)
+  AND NOT EXISTS (SELECT 1 FROM sync_jobs j WHERE j.kind = 'laps' AND j.target = a.id)
+ORDER BY a.start_date DESC;
+
+-- name: EnqueueSocialSyncJobs :execrows
+INSERT INTO sync_jobs (kind, target, priority, max_attempts, run_after, created_at, updated_at)
+SELECT 'social', s.activity_id, ?, ?, ?, ?, ?
+FROM activity_social s
+WHERE ((s.lists_kudos_count IS NULL AND (s.kudos_count > 0 OR s.comment_count > 0))
+       OR s.lists_kudos_count != s.kudos_count
+       OR s.lists_comment_count != s.comment_count)
+  AND NOT EXISTS (
+      SELECT 1 FROM sync_jobs j
+      WHERE j.kind = 'social' AND j.target = s.activity_id AND j.status IN ('pending', 'running', 'dead')
+  )
+ORDER BY s.activity_id DESC
+ON CONFLICT(kind, target, IFNULL(after_time, ''), IFNULL(before_time, '')) DO UPDATE SET
+    priority = excluded.priority,
+    status = 'pending',
+    attempts = 0,
+    max_attempts = excluded.max_attempts,
+    last_error = NULL,
+    run_after = excluded.run_after,
+    updated_at = excluded.updated_at,
+    finished_at = NULL;
+
+-- name: ClaimSyncJob :one
+UPDATE sync_jobs SET status = 'running', updated_at = ?
+WHERE id = (
+    SELECT id FROM sync_jobs
+    WHERE status = 'pending' AND run_after <= ?
+    ORDER BY priority DESC, run_after, id
+    LIMIT 1
+)
+RETURNING id, kind, target, after_time, priority, status, attempts, max_attempts, last_error, run_after, created_at, updated_at, finished_at, before_time;
+
+-- name: CompleteSyncJob :exec
+UPDATE sync_jobs
+SET status = 'done', last_error = NULL, updated_at = ?, finished_at = ?
+WHERE id = ?;
+
+-- name: RetrySyncJob :exec
+UPDATE sync_jobs
+SET status = 'pending', attempts = attempts + 1, last_error = ?, run_after = ?, updated_at = ?
+WHERE id = ?;
+
+-- name: DeadLetterSyncJob :exec
+UPDATE sync_jobs
+SET status = 'dead', attempts = attempts + 1, last_error = ?, updated_at = ?, finished_at = ?
+WHERE id = ?;
+
+-- name: DeadLetterPendingSyncJobs :execrows
+UPDATE sync_jobs
+SET status = 'dead', last_error = ?, updated_at = ?, finished_at = ?
+WHERE kind = ? AND status = 'pending';
+
+-- name: ReleaseSyncJob :exec
+UPDATE sync_jobs SET status = 'pending', run_after = ?, updated_at = ? WHERE id = ?;
+
+-- name: ResetRunningSyncJobs :execrows
+UPDATE sync_jobs SET status = 'pending', updated_at = ? WHERE status = 'running';
+
+-- name: CountActiveSyncJobs :one
+SELECT COUNT(*) FROM sync_jobs WHERE kind = ? AND status IN ('pending', 'running');
+
+-- name: CountActiveDeltaPages :one
+SELECT COUNT(*) FROM sync_jobs
+WHERE kind = 'list_page' AND after_time IS NOT NULL AND before_time IS NULL AND status IN ('pending', 'running');
+
+-- name: CountActiveFullSyncPages :one
+SELECT COUNT(*) FROM sync_jobs
+WHERE kind = 'list_page' AND after_time IS NULL AND before_time IS NULL AND status IN ('pending', 'running');
+
+-- name: GetDeadFullSyncPage :one
+SELECT id, kind, target, after_time, priority, status, attempts, max_attempts, last_error, run_after, created_at, updated_at, finished_at, before_time FROM sync_jobs
+WHERE kind = 'list_page' AND status = 'dead' AND after_time IS NULL AND before_time IS NULL
+ORDER BY target
+LIMIT 1;
+
+-- name: CountReadySyncJobs :one
+SELECT COUNT(*) FROM sync_jobs WHERE status = 'pending' AND run_after <= ?;
+
+-- name: CountDeadSyncJobsWithError :one
+SELECT COUNT(*) FROM sync_jobs WHERE kind = ? AND status = 'dead' AND last_error = ?;
+
+-- name: CountSyncJobsByStatus :many
+SELECT kind, status, COUNT(*) AS jobs FROM sync_jobs GROUP BY kind, status ORDER BY kind, status;
+
+-- name: DeleteFinishedSyncJobs :execrows
+DELETE FROM sync_jobs
+WHERE status = 'done' AND kind IN ('list_page', 'activity') AND finished_at < ?;
+
+-- Lap queries
+
+-- name: DeleteActivityLaps :exec
+DELETE FROM activity_laps WHERE activity_id = ?;
+
+-- name: CreateActivityLap :exec
+INSERT INTO activity_laps (
+    activity_id, lap_index, name, distance, moving_time, elapsed_time, start_date, total_elevation_gain,
+    average_speed, max_speed, average_heartrate, max_heartrate, average_cadence, average_watts
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListActivityLaps :many
+SELECT * FROM activity_laps WHERE activity_id = ? ORDER BY lap_index;
+
+-- Rate limit budget queries
+
+-- name: GetRateLimitBudget :one
+SELECT * FROM rate_limit_budget WHERE id = 1;
+
+-- name: UpsertRateLimitBudget :exec
+INSERT INTO rate_limit_budget (
+    id, window_start, window_usage, window_limit, day_start, daily_usage, daily_limit, updated_at
+) VALUES (1, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    window_start = excluded.window_start,
+    window_usage = excluded.window_usage,
+    window_limit = excluded.window_limit,
+    day_start = excluded.day_start,
+    daily_usage = excluded.daily_usage,
+    daily_limit = excluded.daily_limit,
+    updated_at = excluded.updated_at;
+
+-- Sync state queries
+
+-- name: GetSyncState :one
+SELECT value FROM sync_state WHERE key = ?;
+
+-- name: SetSyncState :exec
+INSERT INTO sync_state (key, value, updated_at) VALUES (?, ?, ?)
+ON CONFLICT(key) DO UPDATE SET
+    value = excluded.value,
+    updated_at = excluded.updated_at;
@@ -17,7 +17,16 @@ CREATE TABLE IF NOT EXISTS activities (
     max_heartrate REAL,
     calories REAL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    summary_polyline TEXT,
+    start_lat REAL,
+    start_lng REAL,
+    end_lat REAL,
+    end_lng REAL,
+    workout_type INTEGER,
+    workout_category TEXT,
+    workout_confidence REAL,
+    workout_classified_at DATETIME
 );
 
 CREATE INDEX IF NOT EXISTS idx_activities_start_date ON activities(start_date);
@@ -30,6 +39,9 @@ CREATE INDEX IF NOT EXISTS idx_activities_type_start_date ON activities(type, st
 -- Covering index for GROUP BY queries on type
 CREATE INDEX IF NOT EXISTS idx_activities_type_count ON activities(type) WHERE type IS NOT NULL;
 
+-- Workout category filters (find_activities, count_activities, get_training_summary)
+CREATE INDEX IF NOT EXISTS idx_activities_workout_category ON activities(workout_category, start_date);
+
 -- Auth configuration table (stores client credentials and tokens)
 CREATE TABLE IF NOT EXISTS auth_config (
     id INTEGER PRIMARY KEY CHECK (id = 1),  -- Singleton row
@@ -49,6 +61,7 @@ CREATE TABLE IF NOT EXISTS activity_zones (
     zone_type TEXT NOT NULL,  -- 'heartrate' or 'power'
     sensor_based INTEGER NOT NULL DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    source TEXT NOT NULL DEFAULT 'strava', -- 'strava' or 'local' (computed from streams)
     FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE,
     UNIQUE(activity_id, zone_type)
 );
@@ -67,3 +80,407 @@ CREATE TABLE IF NOT EXISTS zone_buckets (
 CREATE INDEX IF NOT EXISTS idx_activity_zones_activity_id ON activity_zones(activity_id);
 CREATE INDEX IF NOT EXISTS idx_activity_zones_type ON activity_zones(zone_type);
 CREATE INDEX IF NOT EXISTS idx_zone_buckets_zone_id ON zone_buckets(activity_zone_id);
+
+-- Per-activity time series from the Strava streams API. Each *_data column
+-- holds a JSON array aligned by index; NULL when the stream is unavailable.
+-- A row with point_count = 0 records that Strava had no streams (e.g. manual
+-- activities) so the activity is not fetched again.
+CREATE TABLE IF NOT EXISTS activity_streams (
+    activity_id INTEGER PRIMARY KEY,
+    point_count INTEGER NOT NULL DEFAULT 0,
+    time_data TEXT,          -- seconds since start
+    distance_data TEXT,      -- meters
+    latlng_data TEXT,        -- [[lat, lng], ...]
+    altitude_data TEXT,      -- meters
+    velocity_data TEXT,      -- velocity_smooth, m/s
+    heartrate_data TEXT,     -- bpm
+    cadence_data TEXT,       -- rpm (running cadence is per leg)
+    watts_data TEXT,         -- watts
+    grade_data TEXT,         -- grade_smooth, percent
+    moving_data TEXT,        -- booleans
+    fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Recurring routes found by clustering activity polylines. Route IDs are
+-- stable: an activity is assigned once, and later activities join an
+-- existing route when they match its representative polyline.
+CREATE TABLE IF NOT EXISTS routes_detected (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    activity_type TEXT,
+    representative_activity_id INTEGER NOT NULL,
+    polyline TEXT NOT NULL,            -- summary polyline of the representative activity
+    distance REAL NOT NULL,            -- meters
+    start_lat REAL NOT NULL,
+    start_lng REAL NOT NULL,
+    end_lat REAL NOT NULL,
+    end_lng REAL NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per activity that has been through route detection
+CREATE TABLE IF NOT EXISTS route_efforts (
+    activity_id INTEGER PRIMARY KEY,
+    route_id INTEGER NOT NULL,
+    shape_distance REAL NOT NULL DEFAULT 0, -- mean deviation from the route's polyline in meters
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE,
+    FOREIGN KEY (route_id) REFERENCES routes_detected(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_route_efforts_route_id ON route_efforts(route_id);
+
+-- Zone boundaries used to compute local zones. boundaries is a JSON array of
+-- the lower bound of zones 2..n (BPM or watts); zone 1 starts at 0 and the
+-- last zone is unbounded. source is 'athlete' (Strava profile) or 'config'
+-- (--zones-file), which takes precedence.
+CREATE TABLE IF NOT EXISTS zone_settings (
+    zone_type TEXT PRIMARY KEY,  -- 'heartrate', 'power' or 'pace' (bounds in mm/s)
+    boundaries TEXT NOT NULL,
+    source TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Per-activity threshold estimates from the best 20 minutes of its streams.
+-- One row per analyzed activity; a NULL estimate means the activity had no
+-- usable 20 minutes of that stream. sport is 'run', 'ride' or '' (neither).
+CREATE TABLE IF NOT EXISTS threshold_efforts (
+    activity_id INTEGER PRIMARY KEY,
+    sport TEXT NOT NULL,
+    start_date DATETIME,
+    lthr REAL,             -- bpm
+    ftp REAL,              -- watts
+    threshold_speed REAL,  -- m/s
+    analyzed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_threshold_efforts_start_date ON threshold_efforts(start_date);
+
+-- Each time an estimate rose past the previous threshold
+CREATE TABLE IF NOT EXISTS threshold_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    metric TEXT NOT NULL,           -- 'lthr', 'ftp' or 'threshold_pace'
+    sport TEXT NOT NULL,            -- 'run' or 'ride'
+    value REAL NOT NULL,            -- bpm, watts, or m/s for threshold_pace
+    previous_value REAL,
+    activity_id INTEGER NOT NULL,   -- the effort the estimate came from
+    detected_on DATETIME NOT NULL,  -- start date of that activity
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_threshold_history_metric ON threshold_history(metric, sport, detected_on);
+
+-- Races the athlete is training for. priority is 'A' (the goal race), 'B'
+-- or 'C' (tune-ups); race readiness defaults to the next A race.
+CREATE TABLE IF NOT EXISTS races (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    race_date DATETIME NOT NULL,
+    distance REAL NOT NULL,         -- meters
+    activity_type TEXT NOT NULL,    -- 'Run', 'Ride', ...
+    priority TEXT NOT NULL DEFAULT 'A',
+    goal_time INTEGER,              -- seconds
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_races_race_date ON races(race_date);
+
+-- One row per activity checked for data problems. Activities updated or
+-- given streams since checked_at are checked again.
+CREATE TABLE IF NOT EXISTS activity_quality_checks (
+    activity_id INTEGER PRIMARY KEY,
+    checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Problems found in an activity. code is 'implausible_speed', 'gps_spike',
+-- 'zero_distance', 'hr_dropout', 'implausible_heartrate' or 'duplicate';
+-- related_activity_id is the original of a duplicate. Activities with an
+-- 'error' flag are left out of records and progress; 'warning' flags are
+-- reported only.
+CREATE TABLE IF NOT EXISTS activity_flags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    activity_id INTEGER NOT NULL,
+    code TEXT NOT NULL,
+    severity TEXT NOT NULL,         -- 'error' or 'warning'
+    detail TEXT NOT NULL,
+    related_activity_id INTEGER,
+    detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE,
+    UNIQUE (activity_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_flags_severity ON activity_flags(severity, activity_id);
+
+-- Local corrections to an activity. name, type and distance replace Strava's
+-- values in activities (type also replaces sport_type, and distance the
+-- average speed) and are applied again whenever the activity syncs.
+-- original_* hold Strava's values from when the first correction was made,
+-- restored when the corrections are reset. counted is NULL to leave stats to
+-- the data quality checks, 0 to leave the activity out of records, progress
+-- and totals, or 1 to count it even when flagged.
+CREATE TABLE IF NOT EXISTS activity_overrides (
+    activity_id INTEGER PRIMARY KEY,
+    name TEXT,
+    type TEXT,
+    distance REAL,
+    counted INTEGER,
+    reason TEXT,
+    original_name TEXT,
+    original_type TEXT,
+    original_sport_type TEXT,
+    original_distance REAL,
+    original_average_speed REAL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Activities left out of records and progress: those with a data quality
+-- error unless counted anyway, and those excluded by hand
+CREATE VIEW IF NOT EXISTS excluded_activities AS
+SELECT activity_id FROM activity_flags
+WHERE severity = 'error'
+  AND activity_id NOT IN (SELECT activity_id FROM activity_overrides WHERE counted = 1)
+UNION
+SELECT activity_id FROM activity_overrides WHERE counted = 0;
+
+-- Kudos, comment and athlete counts for each activity, from the activities
+-- Strava returns. lists_kudos_count and lists_comment_count are the counts
+-- when the kudos and comments were last fetched, NULL when never, so lists
+-- are fetched again only when the counts change.
+CREATE TABLE IF NOT EXISTS activity_social (
+    activity_id INTEGER PRIMARY KEY,
+    kudos_count INTEGER NOT NULL DEFAULT 0,
+    comment_count INTEGER NOT NULL DEFAULT 0,
+    athlete_count INTEGER NOT NULL DEFAULT 1,
+    lists_kudos_count INTEGER,
+    lists_comment_count INTEGER,
+    lists_synced_at DATETIME,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Athletes who gave an activity kudos, in Strava's order. Strava only gives
+-- names (first name and last initial), not athlete IDs.
+CREATE TABLE IF NOT EXISTS activity_kudos (
+    activity_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    athlete_name TEXT NOT NULL,
+    PRIMARY KEY (activity_id, position),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS activity_comments (
+    id INTEGER PRIMARY KEY,
+    activity_id INTEGER NOT NULL,
+    athlete_name TEXT NOT NULL,
+    text TEXT NOT NULL,
+    created_at DATETIME,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_comments_activity ON activity_comments(activity_id);
+
+-- Clubs the athlete belongs to, refreshed on each club sync
+CREATE TABLE IF NOT EXISTS clubs (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    sport_type TEXT,
+    city TEXT,
+    state TEXT,
+    country TEXT,
+    private INTEGER NOT NULL DEFAULT 0,
+    member_count INTEGER NOT NULL DEFAULT 0,
+    url TEXT,
+    synced_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Activities from a club's feed. Strava gives club activities without an ID
+-- or start date, so each is identified by a fingerprint of its athlete, name,
+-- type, distance and times, and dated by when a sync first saw it. Activities
+-- found on a club's first sync are marked backfill: their dates are unknown.
+CREATE TABLE IF NOT EXISTS club_activities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    club_id INTEGER NOT NULL,
+    fingerprint TEXT NOT NULL,
+    athlete_name TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT,
+    sport_type TEXT,
+    distance REAL NOT NULL DEFAULT 0,
+    moving_time INTEGER NOT NULL DEFAULT 0,
+    elapsed_time INTEGER NOT NULL DEFAULT 0,
+    total_elevation_gain REAL NOT NULL DEFAULT 0,
+    first_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    backfill INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (club_id, fingerprint),
+    FOREIGN KEY (club_id) REFERENCES clubs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_club_activities_seen ON club_activities(club_id, first_seen_at);
+
+-- Routes the athlete created or saved on Strava. gpx is the route's GPX
+-- export and gpx_updated_at the updated_at it was exported for, so the GPX
+-- is fetched again only when the route changes.
+CREATE TABLE IF NOT EXISTS saved_routes (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT,
+    type TEXT,
+    sub_type TEXT,
+    distance REAL NOT NULL DEFAULT 0,
+    elevation_gain REAL NOT NULL DEFAULT 0,
+    estimated_moving_time INTEGER NOT NULL DEFAULT 0,
+    polyline TEXT,
+    private INTEGER NOT NULL DEFAULT 0,
+    starred INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME,
+    updated_at DATETIME,
+    gpx TEXT,
+    gpx_updated_at DATETIME,
+    synced_at DATETIME NOT NULL
+);
+
+-- Segments the athlete starred, with their PR and effort count from Strava
+CREATE TABLE IF NOT EXISTS starred_segments (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    activity_type TEXT,
+    distance REAL NOT NULL DEFAULT 0,
+    average_grade REAL NOT NULL DEFAULT 0,
+    maximum_grade REAL NOT NULL DEFAULT 0,
+    elevation_high REAL NOT NULL DEFAULT 0,
+    elevation_low REAL NOT NULL DEFAULT 0,
+    climb_category INTEGER NOT NULL DEFAULT 0,
+    city TEXT,
+    state TEXT,
+    country TEXT,
+    private INTEGER NOT NULL DEFAULT 0,
+    start_lat REAL,
+    start_lng REAL,
+    end_lat REAL,
+    end_lng REAL,
+    pr_elapsed_time INTEGER,
+    pr_date TEXT,
+    pr_activity_id INTEGER,
+    effort_count INTEGER NOT NULL DEFAULT 0,
+    synced_at DATETIME NOT NULL
+);
+
+-- Activities that failed to save during a sync. A row is cleared when the
+-- activity saves, and gap repair fetches each one again by ID.
+CREATE TABLE IF NOT EXISTS sync_failures (
+    activity_id INTEGER PRIMARY KEY,
+    activity_name TEXT,
+    start_date DATETIME,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    first_failed_at DATETIME NOT NULL,
+    last_failed_at DATETIME NOT NULL
+);
+
+-- The latest comparison of Strava's athlete stats with the stored totals,
+-- one row per sport and period. repaired_missing is how many activities the
+-- period was missing when it was last repaired, so a gap repair could not
+-- close is only resynced again once it grows.
+CREATE TABLE IF NOT EXISTS sync_integrity (
+    sport TEXT NOT NULL,
+    period TEXT NOT NULL,
+    window_start DATETIME,
+    strava_count INTEGER NOT NULL,
+    local_count INTEGER NOT NULL,
+    strava_distance REAL NOT NULL,
+    local_distance REAL NOT NULL,
+    strava_moving_time INTEGER NOT NULL,
+    local_moving_time INTEGER NOT NULL,
+    repaired_missing INTEGER,
+    checked_at DATETIME NOT NULL,
+    PRIMARY KEY (sport, period)
+);
+
+-- Gap repair resyncs, one row per date window or failed activity
+CREATE TABLE IF NOT EXISTS sync_repairs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    reason TEXT NOT NULL,
+    window_start DATETIME,
+    window_end DATETIME,
+    fetched INTEGER NOT NULL DEFAULT 0,
+    added INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    repaired_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_repairs_repaired_at ON sync_repairs(repaired_at);
+
+-- Durable queue of Strava fetches. target is the page number for list_page
+-- jobs and the activity ID for the per-activity kinds (activity, streams,
+-- zones, laps, social). Jobs run highest priority first once run_after has passed;
+-- a failed job is retried with exponential backoff until max_attempts, then
+-- kept as dead. Jobs left running by a crash are reset to pending on start.
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    target INTEGER NOT NULL,
+    after_time DATETIME,
+    priority INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    last_error TEXT,
+    run_after DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    finished_at DATETIME,
+    before_time DATETIME
+);
+
+-- A list page is one page of a listing between its after_time and
+-- before_time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_jobs_target ON sync_jobs(kind, target, IFNULL(after_time, ''), IFNULL(before_time, ''));
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_ready ON sync_jobs(status, priority DESC, run_after);
+
+-- Laps recorded with an activity (device laps or Strava's auto-laps)
+CREATE TABLE IF NOT EXISTS activity_laps (
+    activity_id INTEGER NOT NULL,
+    lap_index INTEGER NOT NULL,
+    name TEXT,
+    distance REAL,
+    moving_time INTEGER,
+    elapsed_time INTEGER,
+    start_date DATETIME,
+    total_elevation_gain REAL,
+    average_speed REAL,
+    max_speed REAL,
+    average_heartrate REAL,
+    max_heartrate REAL,
+    average_cadence REAL,
+    average_watts REAL,
+    PRIMARY KEY (activity_id, lap_index),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- The Strava rate limit budget shared by every client in the process, kept
+-- so a restart remembers what the current 15-minute window and day used.
+-- There is only ever one row.
+CREATE TABLE IF NOT EXISTS rate_limit_budget (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    window_start DATETIME NOT NULL,
+    window_usage INTEGER NOT NULL,
+    window_limit INTEGER NOT NULL,
+    day_start DATETIME NOT NULL,
+    daily_usage INTEGER NOT NULL,
+    daily_limit INTEGER NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- Small pieces of sync bookkeeping by key, such as when a backfill was last
+-- attempted, so they survive a restart
+CREATE TABLE IF NOT EXISTS sync_state (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME NOT NULL
+);